@@ -4,24 +4,75 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"strings"
 
+	"github.com/openai/openai-go/option"
 	"textadventure/cmd/game/ui"
+	"textadventure/internal/artifacts"
+	"textadventure/internal/campaign"
+	"textadventure/internal/chaos"
 	"textadventure/internal/debug"
+	"textadventure/internal/experiment"
+	"textadventure/internal/feed"
+	"textadventure/internal/game"
+	"textadventure/internal/game/actors"
+	"textadventure/internal/game/dialogue"
+	"textadventure/internal/game/director"
+	"textadventure/internal/game/engine"
+	"textadventure/internal/game/events"
+	"textadventure/internal/game/narration"
+	"textadventure/internal/game/perception"
+	"textadventure/internal/game/rng"
+	"textadventure/internal/game/translate"
 	"textadventure/internal/llm"
 	"textadventure/internal/logging"
 	"textadventure/internal/mcp"
 	"textadventure/internal/observability"
+	"textadventure/internal/redact"
+	"textadventure/internal/save"
 )
 
-func createApp() (ui.Model, func(), error) {
+// debugConfigFile holds the /debug category toggles, under the data directory so they
+// carry over between sessions.
+const debugConfigFile = "debug.json"
+
+// turnTagFallbackBudget caps LLM calls for tagging turns the rules can't, per session.
+const turnTagFallbackBudget = 20
+
+func createApp(loadPath, campaignName string) (ui.Model, func(), error) {
 	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
+	baseURL := strings.TrimSpace(os.Getenv("OPENAI_BASE_URL"))
+	if apiKey == "" && baseURL == "" {
 		return ui.Model{}, nil, fmt.Errorf("please set OPENAI_API_KEY environment variable")
 	}
 	
-	debugMode := os.Getenv("DEBUG") == "1" || os.Getenv("DEBUG") == "true"
+	debugMode, debugModeErr := debug.ParseMode(os.Getenv("DEBUG"))
 	
-	debugLogger := debug.NewLogger(debugMode)
+	artifactConfig := artifacts.LoadConfigFromEnv()
+	session, err := artifacts.NewSession(artifactConfig, time.Now())
+	if session == nil {
+		return ui.Model{}, nil, fmt.Errorf("failed to set up session artifacts: %w", err)
+	}
+	
+	redactor, redactErrs := redact.LoadFromEnv()
+	redact.SetDefault(redactor)
+	debugLogger := debug.NewLogger(debugMode, session.Path("debug.log"))
+	debugLogger.Printf("Session artifacts in %s", session.Dir)
+	if debugModeErr != nil {
+		debugLogger.Printf("Ignoring DEBUG: %v", debugModeErr)
+	}
+	if err := debugLogger.LoadConfig(filepath.Join(artifactConfig.Root, debugConfigFile)); err != nil {
+		debugLogger.Printf("Ignoring debug config: %v", err)
+	}
+	for _, redactErr := range redactErrs {
+		debugLogger.Printf("Ignoring redaction pattern: %v", redactErr)
+	}
+	if err != nil {
+		debugLogger.Printf("Session directory housekeeping failed: %v", err)
+	}
 	
 	ctx := context.Background()
 	tracingConfig := observability.LoadConfigFromEnv()
@@ -34,24 +85,88 @@ func createApp() (ui.Model, func(), error) {
 		debugLogger.Println("OpenTelemetry tracing disabled (set OTEL_TRACES_ENABLED=true to enable)")
 	}
 	
-	llmService := llm.NewService(apiKey, debugLogger)
+	debugLogger.Printf("Random seed: %d (replay with --seed %d)", rng.MasterSeed(), rng.MasterSeed())
+	
+	var injector *chaos.Injector
+	if chaosConfig := chaos.LoadConfigFromEnv(); debugLogger.IsEnabled() && chaosConfig.Enabled() {
+		injector = chaos.NewInjector(chaosConfig)
+		debugLogger.Printf("Chaos injection enabled: %+v", chaosConfig)
+	}
+	
+	var llmOptions []option.RequestOption
+	if injector != nil {
+		llmOptions = append(llmOptions, option.WithMiddleware(injector.LLMMiddleware()))
+	}
+	llmService := newLLMService(apiKey, baseURL, debugLogger, llmOptions...)
 	debugLogger.Println("Starting text adventure with debug logging")
 	
-	logger, err := logging.NewCompletionLogger()
+	logger, err := logging.NewCompletionLogger(artifactConfig.CompletionsDB)
 	if err != nil {
 		return ui.Model{}, nil, fmt.Errorf("failed to initialize completion logger: %w", err)
 	}
+	if full := strings.ToLower(os.Getenv("COMPLETIONS_FULL_WORLD")); full == "1" || full == "true" {
+		logger.SetFullWorldState(true)
+	}
 	
 	debugLogger.Println("Initializing MCP client...")
-	mcpClient, err := mcp.NewWorldStateClient(debugMode)
+	mcpClient, err := newWorldStateClient(debugLogger.IsEnabled(), debugLogger)
 	if err != nil {
 		return ui.Model{}, nil, fmt.Errorf("failed to initialize MCP client: %w", err)
 	}
+	if injector != nil {
+		mcpClient.WrapToolCaller(injector.WrapToolCaller)
+	}
 	
 	debugLogger.Println("Connecting to MCP server...")
 	if err := mcpClient.Connect(ctx); err != nil {
 		return ui.Model{}, nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
+	if err := director.LoadToolCatalog(ctx, mcpClient, debugLogger); err != nil {
+		debugLogger.Printf("Failed to load director tool catalog: %v", err)
+	}
+	if missing, err := mcpClient.ProbeTools(ctx, mcp.FactTools...); err != nil {
+		debugLogger.Printf("Failed to probe world-state server tools: %v", err)
+	} else if len(missing) > 0 {
+		debugLogger.Printf("WARNING: world-state server lacks %s; narrated facts will only be kept locally", strings.Join(missing, ", "))
+	}
+	
+	var playCampaign *campaign.Campaign
+	started := false
+	if campaignName != "" {
+		playCampaign, err = campaign.Open(campaignName)
+		if err != nil {
+			return ui.Model{}, nil, err
+		}
+		// Release the lock if the session never starts; once it does, Cleanup releases it
+		defer func() {
+			if !started {
+				playCampaign.Close()
+			}
+		}()
+		switch {
+		case playCampaign.HasWorld() && loadPath != "":
+			return ui.Model{}, nil, fmt.Errorf("campaign %q already has a world; --load only seeds a new campaign", campaignName)
+		case playCampaign.HasWorld():
+			loadPath = playCampaign.WorldPath()
+		}
+		debugLogger.Printf("Playing campaign %s", campaignName)
+	}
+	
+	var snapshot save.Snapshot
+	if loadPath != "" {
+		snapshot, err = save.Read(loadPath)
+		if err != nil {
+			return ui.Model{}, nil, fmt.Errorf("failed to load %s: %w", loadPath, err)
+		}
+		save.Migrate(&snapshot)
+		if err := save.Validate(snapshot.World); err != nil {
+			return ui.Model{}, nil, fmt.Errorf("cannot load %s: %w", loadPath, err)
+		}
+		if _, err := mcpClient.RestoreWorldState(ctx, snapshot.World); err != nil {
+			return ui.Model{}, nil, fmt.Errorf("failed to restore world state: %w", err)
+		}
+		debugLogger.Printf("Restored world state from %s", loadPath)
+	}
 	
 	debugLogger.Println("Fetching initial world state from MCP server...")
 	mcpWorld, err := mcpClient.GetWorldState(ctx)
@@ -61,7 +176,18 @@ func createApp() (ui.Model, func(), error) {
 	
 	debugLogger.Printf("MCP world: player at %s, inventory: %v", mcpWorld.Player.Location, mcpWorld.Player.Inventory)
 	
+	registered, problems := director.RegisterPassthroughTools(mcpWorld.PassthroughTools)
+	if len(registered) > 0 {
+		debugLogger.Printf("Scenario tools offered to the director: %s", strings.Join(registered, ", "))
+	}
+	for _, problem := range problems {
+		debugLogger.Printf("WARNING: %v", problem)
+	}
+	
 	world := mcp.MCPToGameWorldState(mcpWorld)
+	for _, problem := range dialogue.Check(world) {
+		debugLogger.Printf("WARNING: %v", problem)
+	}
 	
 	debugLogger.Printf("Game world converted: player at %s, inventory: %v", world.Location, world.Inventory)
 	
@@ -70,6 +196,116 @@ func createApp() (ui.Model, func(), error) {
 		Completion: logger,
 	}
 	model := ui.NewModel(llmService, mcpClient, loggers, world)
+	if injector != nil {
+		model.SetChaosInjector(injector)
+	}
+	if translation := strings.ToLower(os.Getenv("INPUT_TRANSLATION")); translation == "1" || translation == "true" {
+		model.SetInputNormalizer(translate.NewNormalizer(translate.NewLLMTranslator(llmService)))
+		debugLogger.Println("Input translation enabled")
+	}
+	model.SetNarrationLanguage(os.Getenv("NARRATION_LANGUAGE"))
+	if pov, err := game.ParsePOV(os.Getenv("NARRATION_POV")); err != nil {
+		debugLogger.Printf("Ignoring NARRATION_POV: %v", err)
+	} else {
+		model.SetNarrationPOV(pov)
+	}
+	if style, err := narration.ParseStyle(os.Getenv("NARRATION_STYLE")); err != nil {
+		debugLogger.Printf("Ignoring NARRATION_STYLE: %v", err)
+	} else {
+		model.SetNarrationStyle(style)
+	}
+	if config, err := experiment.LoadFromEnv(); err != nil {
+		debugLogger.Printf("Ignoring NARRATOR_EXPERIMENT: %v", err)
+	} else if config != nil {
+		var styleErr error
+		for _, variant := range []experiment.Variant{config.A, config.B} {
+			if _, err := narration.ParseStylePreset(variant.Style); err != nil {
+				styleErr = err
+			}
+		}
+		if styleErr != nil {
+			debugLogger.Printf("Ignoring NARRATOR_EXPERIMENT: %v", styleErr)
+		} else {
+			model.SetNarratorExperiment(config)
+			debugLogger.Printf("Narrator experiment %s running with seed %d", config.Name, config.Seed)
+		}
+	}
+	if perceptionMode, err := perception.ParseMode(os.Getenv("PERCEPTION_MODE")); err != nil {
+		debugLogger.Printf("Ignoring PERCEPTION_MODE: %v", err)
+	} else {
+		model.SetPerceptionMode(perceptionMode)
+	}
+	if strict := strings.ToLower(os.Getenv("STRICT")); strict == "1" || strict == "true" {
+		model.SetStrict(true)
+		debugLogger.Println("Strict mode enabled")
+	}
+	model.SetTurnClassifier(engine.NewClassifier(engine.NewLLMTagger(llmService), turnTagFallbackBudget))
+	if limit := os.Getenv("NPC_MEMORY_PROMPT_LIMIT"); limit != "" {
+		if n, err := strconv.Atoi(limit); err != nil {
+			debugLogger.Printf("Ignoring NPC_MEMORY_PROMPT_LIMIT=%q: %v", limit, err)
+		} else {
+			actors.RecentMemoryLimit = n
+		}
+	}
+	if budget := os.Getenv("NPC_TURN_BUDGET"); budget != "" {
+		n, err := strconv.Atoi(budget)
+		if err != nil {
+			debugLogger.Printf("Ignoring NPC_TURN_BUDGET=%q: %v", budget, err)
+		}
+		model.SetNPCTurnBudget(n)
+	}
+	if parallel := strings.ToLower(os.Getenv("NPC_PARALLEL_TURNS")); parallel == "1" || parallel == "true" {
+		model.SetParallelNPCTurns(true)
+		debugLogger.Println("Parallel NPC turns enabled")
+	}
+	if distance := os.Getenv("NPC_NARRATION_DISTANCE"); distance != "" {
+		n, err := strconv.Atoi(distance)
+		if err != nil {
+			debugLogger.Printf("Ignoring NPC_NARRATION_DISTANCE=%q: %v", distance, err)
+			n = -1
+		}
+		model.SetNPCNarrationDistance(n)
+	}
+	if timeout := os.Getenv("TURN_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			debugLogger.Printf("Ignoring TURN_TIMEOUT=%q: %v", timeout, err)
+		} else {
+			model.SetTurnTimeout(d)
+		}
+	}
+	if limit, err := game.ParseSessionLimit(os.Getenv("SESSION_MAX_TURNS"), os.Getenv("SESSION_MAX_DURATION"), os.Getenv("SESSION_WRAP_UP_TURNS")); err != nil {
+		debugLogger.Printf("Ignoring session limit: %v", err)
+	} else if limit.Enabled() {
+		debugLogger.Printf("Session limit: %d turns, %s, wrap-up over %d turns", limit.MaxTurns, limit.MaxDuration, limit.WrapUpTurns)
+		model.SetSessionLimit(limit)
+	}
+	model.SetBriefing(mcpWorld.Briefing)
+	model.SetArtifactDir(session.Dir)
+	if loadPath != "" {
+		model.RestoreSnapshot(snapshot.History, save.Origin(loadPath, snapshot))
+	}
+	if playCampaign != nil {
+		model.SetCampaign(playCampaign, snapshot.Metadata.TurnIndex)
+	}
+	if eventStore, err := events.OpenStore(artifactConfig.CompletionsDB, model.SessionID()); err != nil {
+		debugLogger.Printf("Failed to open world event log: %v", err)
+	} else {
+		model.SetEventStore(eventStore)
+	}
+	
+	if feedDir := os.Getenv("SESSION_FEED_DIR"); feedDir != "" {
+		feedWriter, err := feed.NewWriter(feedDir)
+		if err != nil {
+			debugLogger.Printf("Failed to initialize session feed: %v", err)
+		} else {
+			if mcpWorld.Briefing != nil {
+				feedWriter.SetBriefing(mcpWorld.Briefing.Text())
+			}
+			model.AddTurnSubscriber(feedWriter)
+			debugLogger.Printf("Session feed enabled in %s", feedDir)
+		}
+	}
 	
 	cleanup := func() {
 		model.Cleanup()
@@ -78,5 +314,44 @@ func createApp() (ui.Model, func(), error) {
 		}
 	}
 	
+	started = true
 	return model, cleanup, nil
-}
\ No newline at end of file
+}
+
+// newLLMService creates the LLM service for the configured endpoint, with the model and
+// price configs from the environment. Configs that don't load are logged and ignored.
+func newLLMService(apiKey, baseURL string, debugLogger *debug.Logger, llmOptions ...option.RequestOption) *llm.Service {
+	if baseURL != "" {
+		// OpenAI-compatible servers (Ollama, llama.cpp) for offline development
+		llmOptions = append(llmOptions, option.WithBaseURL(baseURL))
+	}
+	llmService := llm.NewService(apiKey, debugLogger, llmOptions...)
+	llmService.SetCompatibility(llm.CompatibilityFor(baseURL))
+	if modelConfig, err := llm.LoadModelConfigFromEnv(); err != nil {
+		debugLogger.Printf("Ignoring model config: %v", err)
+	} else if modelConfig != nil {
+		llmService.SetModelConfig(modelConfig)
+		debugLogger.Printf("Model config loaded for %d operation types", len(modelConfig))
+	}
+	if prices, err := llm.LoadPricesFromEnv(); err != nil {
+		debugLogger.Printf("Ignoring price config: %v", err)
+	} else {
+		llmService.Usage().SetPrices(prices)
+	}
+	return llmService
+}
+
+// newWorldStateClient creates the world-state client, with the call timeout from
+// WORLD_STATE_CALL_TIMEOUT when it parses. It doesn't connect.
+func newWorldStateClient(debugMode bool, debugLogger *debug.Logger) (*mcp.WorldStateClient, error) {
+	var mcpOptions []mcp.ClientOption
+	if timeout := os.Getenv("WORLD_STATE_CALL_TIMEOUT"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			debugLogger.Printf("Ignoring WORLD_STATE_CALL_TIMEOUT=%q: %v", timeout, err)
+		} else {
+			mcpOptions = append(mcpOptions, mcp.WithCallTimeout(d))
+		}
+	}
+	return mcp.NewWorldStateClient(debugMode, mcpOptions...)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"textadventure/internal/artifacts"
+	"textadventure/internal/debug"
+	"textadventure/internal/doctor"
+	"textadventure/internal/logging"
+	"textadventure/internal/observability"
+)
+
+// runDoctor probes every subsystem the game needs without starting it: textadventure
+// doctor. Services that can't even be set up are reported as failures of their own.
+func runDoctor(out io.Writer) error {
+	debugLogger := debug.NewLogger(debug.Off, os.DevNull)
+	ctx := context.Background()
+	var checks []doctor.Check
+	var setupFailures []string
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	baseURL := strings.TrimSpace(os.Getenv("OPENAI_BASE_URL"))
+	var models doctor.Models
+	if apiKey == "" && baseURL == "" {
+		setupFailures = append(setupFailures, "FAIL  llm: set OPENAI_API_KEY (or OPENAI_BASE_URL for a local endpoint)")
+	} else {
+		models = newLLMService(apiKey, baseURL, debugLogger)
+	}
+
+	var world doctor.WorldStore
+	if client, err := newWorldStateClient(false, debugLogger); err != nil {
+		setupFailures = append(setupFailures, fmt.Sprintf("FAIL  world store: %v", err))
+	} else if err := client.Connect(ctx); err != nil {
+		setupFailures = append(setupFailures, fmt.Sprintf("FAIL  world store: %v; is uv installed? (or set WORLD_STATE_SERVER=go)", err))
+	} else {
+		defer client.Close()
+		world = client
+	}
+
+	var store doctor.Store
+	if logger, err := logging.NewCompletionLogger(artifacts.LoadConfigFromEnv().CompletionsDB); err != nil {
+		setupFailures = append(setupFailures, fmt.Sprintf("FAIL  completions db: %v; COMPLETIONS_DB moves it", err))
+	} else {
+		defer logger.Close()
+		store = logger
+	}
+
+	checks = doctor.Checks(world, models, store, observability.LoadConfigFromEnv())
+	results := doctor.Run(ctx, checks, doctor.DefaultTimeout)
+	for _, line := range append(setupFailures, doctor.Format(results)...) {
+		fmt.Fprintln(out, line)
+	}
+	if failed := doctor.Failures(results) + len(setupFailures); failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	fmt.Fprintln(out, "All checks passed")
+	return nil
+}
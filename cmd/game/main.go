@@ -1,14 +1,68 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/cmd/game/ui"
+	"textadventure/internal/experiment"
+	"textadventure/internal/game/rng"
+	"textadventure/internal/save"
+	"textadventure/internal/timeline"
+	"textadventure/internal/worldgraph"
 )
 
 func main() {
-	model, cleanup, err := createApp()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "branch":
+			runBranch(os.Args[2:])
+			return
+		case "save":
+			if err := save.RunCLI(os.Args[2:], os.Stdout); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "timeline":
+			if err := timeline.RunCLI(os.Args[2:], os.Stdout); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "doctor":
+			if err := runDoctor(os.Stdout); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "experiments":
+			if err := experiment.RunCLI(os.Args[2:], os.Stdout); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "graph":
+			if err := worldgraph.RunCLI(os.Args[2:], os.Stdout); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	loadPath := flag.String("load", "", "start from a save or bookmark file (e.g. saves/bookmarks/cellar.json)")
+	campaignName := flag.String("campaign", "", "play the named campaign, continuing its world and saving it back on exit")
+	seed := flag.Int64("seed", 0, "seed for game randomness, to reproduce a run (0 picks one)")
+	flag.Parse()
+	if *seed != 0 {
+		rng.Seed(*seed)
+	}
+
+	model, cleanup, err := createApp(*loadPath, *campaignName)
 	if err != nil {
 		fmt.Printf("Error initializing app: %v\n", err)
 		os.Exit(1)
@@ -18,9 +72,29 @@ func main() {
 		defer cleanup()
 	}
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error running app: %v\n", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+	if finalModel, ok := final.(ui.Model); ok {
+		if err := finalModel.EndCampaignSession(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+}
+
+// runBranch copies a bookmark into a new save: textadventure branch <bookmark> <new-save>
+func runBranch(args []string) {
+	if len(args) != 2 {
+		fmt.Println("Usage: textadventure branch <bookmark> <new-save>")
+		os.Exit(2)
+	}
+	path, err := save.Branch(args[0], args[1])
+	if err != nil {
+		fmt.Printf("Error branching bookmark: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Branched %s into %s (load with --load %s)\n", args[0], path, path)
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"maps"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recordAmbienceHeard notes that an NPC has perceived the ambience of location, the room
+// its turn was generated in, so it isn't perceived again there. With no location the
+// NPC's current room is used. The returned command persists it on the server.
+func (m *Model) recordAmbienceHeard(npcID, location, ambience string) tea.Cmd {
+	npc, ok := m.world.NPCs[npcID]
+	if ambience == "" || !ok {
+		return nil
+	}
+	if location == "" {
+		location = npc.Location
+	}
+	world := m.world.Clone()
+	npc.AmbienceHeard = maps.Clone(npc.AmbienceHeard)
+	if npc.AmbienceHeard == nil {
+		npc.AmbienceHeard = map[string]string{}
+	}
+	npc.AmbienceHeard[location] = ambience
+	world.NPCs[npcID] = npc
+	m.setWorld(world)
+
+	if m.mcpClient == nil {
+		return nil
+	}
+	ctx := m.createGameContext(m.turnContext, "npc.ambience")
+	client := m.mcpClient
+	debugLogger := m.loggers.Debug
+	return func() tea.Msg {
+		if _, err := client.MarkAmbienceHeard(ctx, npcID, location, ambience); err != nil {
+			debugLogger.Printf("Failed to record that %s heard the ambience of %s: %v", npcID, location, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"textadventure/internal/mcp"
+)
+
+// SetBriefing shows the scenario's briefing before the intro narration. The briefing
+// is meta text for the player and is kept on the model only, never in the world
+// state or history that prompts are built from.
+func (m *Model) SetBriefing(briefing *mcp.Briefing) {
+	if briefing == nil || strings.TrimSpace(briefing.Text()) == "" {
+		return
+	}
+	m.briefing = briefing
+}
+
+// dismissBriefing hides the briefing and starts the intro.
+func (m Model) dismissBriefing() (tea.Model, tea.Cmd) {
+	m.briefing = nil
+	return m, initialLookAroundCmd()
+}
+
+// renderBriefing draws the briefing as a centered panel.
+func (m Model) renderBriefing() string {
+	b := m.briefing
+	panelWidth := m.width - 4
+	if panelWidth > 72 {
+		panelWidth = 72
+	}
+	if panelWidth < 20 {
+		panelWidth = 20
+	}
+	contentWidth := panelWidth - 4
+
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Width(contentWidth)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	var sections []string
+	if b.Title != "" {
+		sections = append(sections, titleStyle.Render(b.Title))
+	}
+	if b.Premise != "" {
+		sections = append(sections, textStyle.Render(b.Premise))
+	}
+	if len(b.ContentWarnings) > 0 {
+		sections = append(sections, labelStyle.Render("Content warnings")+"\n"+textStyle.Render(strings.Join(b.ContentWarnings, ", ")))
+	}
+	if len(b.SuggestedVerbs) > 0 {
+		sections = append(sections, labelStyle.Render("Try")+"\n"+textStyle.Render(strings.Join(b.SuggestedVerbs, " · ")))
+	}
+	sections = append(sections, hintStyle.Render("Press any key to begin"))
+
+	panel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("8")).
+		Padding(1, 2).
+		Render(strings.Join(sections, "\n\n"))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/campaign"
+	"textadventure/internal/game"
+)
+
+// campaignAutosaveEvery is how many turns pass between campaign autosaves, so a crash
+// loses at most this many turns of the campaign.
+const campaignAutosaveEvery = 5
+
+// SetCampaign plays this session as part of a campaign: turns are appended to its turn
+// log, the world is autosaved into it and EndCampaignSession writes it back on exit.
+// turnIndex is the turn the campaign's world was saved on, so numbering carries on.
+func (m *Model) SetCampaign(c *campaign.Campaign, turnIndex int) {
+	m.campaign = c
+	m.turnIndex = turnIndex
+	m.campaignFirstTurn = turnIndex
+	m.AddTurnSubscriber(c)
+	if m.sessionSpan != nil {
+		m.sessionSpan.SetAttributes(attribute.String("game.campaign", c.Name()))
+	}
+}
+
+// autosaveCampaign returns a command writing the world back to the campaign every
+// campaignAutosaveEvery turns. It is taken once a turn has finished and runs behind the
+// turn's writes, so the snapshot never holds a half-applied turn.
+func (m *Model) autosaveCampaign() tea.Cmd {
+	if m.campaign == nil || m.turnIndex == m.campaignFirstTurn || m.turnIndex%campaignAutosaveEvery != 0 {
+		return nil
+	}
+	return m.snapshotCmd(m.campaign.WorldPath(), m.campaign.Name(), "campaign.autosave")
+}
+
+// EndCampaignSession writes the world back to the campaign and adds this session to its
+// chronicle. Call it on the model the program finished with, before Cleanup.
+func (m Model) EndCampaignSession() error {
+	if m.campaign == nil {
+		return nil
+	}
+	if err := m.writeSnapshot(m.campaign.WorldPath(), m.campaign.Name(), "campaign.save"); err != nil {
+		return fmt.Errorf("failed to save campaign %s: %w", m.campaign.Name(), err)
+	}
+	return m.campaign.AppendChronicle(campaign.Session{
+		SessionID: m.sessionID,
+		StartedAt: m.sessionStartTime,
+		EndedAt:   time.Now(),
+		FirstTurn: m.campaignFirstTurn,
+		LastTurn:  m.turnIndex,
+		Location:  m.world.Location,
+		LastScene: m.lastNarration(),
+	})
+}
+
+// lastNarration returns the most recent narration in the history.
+func (m Model) lastNarration() string {
+	entries := m.gameHistory.Entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Kind == game.HistoryNarrator {
+			return entries[i].Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commandArg describes one positional argument of a slash command.
+type commandArg struct {
+	Name     string
+	Optional bool
+	Rest     bool // swallow all remaining words, e.g. a free-text note
+}
+
+// slashCommand is a command typed into the input line with a leading "/". Run returns
+// the lines to show (without any [DEBUG] prefix) and an optional follow-up command.
+type slashCommand struct {
+	Name      string
+	Aliases   []string
+	Args      []commandArg
+	DebugOnly bool
+	Summary   string
+	Run       func(m *Model, args []string) ([]string, tea.Cmd)
+}
+
+// Usage renders the command's call syntax, e.g. "/bookmark <name>".
+func (c slashCommand) Usage() string {
+	parts := []string{"/" + c.Name}
+	for _, arg := range c.Args {
+		name := arg.Name
+		if arg.Rest {
+			name += "..."
+		}
+		if arg.Optional {
+			parts = append(parts, "["+name+"]")
+		} else {
+			parts = append(parts, "<"+name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseArgs checks the words after the command name against the arg spec.
+func (c slashCommand) parseArgs(words []string) ([]string, error) {
+	required, max := 0, len(c.Args)
+	for _, arg := range c.Args {
+		if !arg.Optional {
+			required++
+		}
+		if arg.Rest {
+			max = -1
+		}
+	}
+	if len(words) < required {
+		return nil, fmt.Errorf("missing %s", c.Args[len(words)].Name)
+	}
+	if max >= 0 && len(words) > max {
+		return nil, fmt.Errorf("too many arguments")
+	}
+	if max < 0 && len(words) > len(c.Args) {
+		last := len(c.Args) - 1
+		return append(words[:last:last], strings.Join(words[last:], " ")), nil
+	}
+	return words, nil
+}
+
+type commandRegistry struct {
+	commands []*slashCommand
+	byName   map[string]*slashCommand
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{byName: make(map[string]*slashCommand)}
+}
+
+// Register adds a command. Duplicate names or aliases are programming errors.
+func (r *commandRegistry) Register(cmd slashCommand) {
+	entry := &cmd
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, exists := r.byName[name]; exists {
+			panic(fmt.Sprintf("slash command %q registered twice", name))
+		}
+		r.byName[name] = entry
+	}
+	r.commands = append(r.commands, entry)
+}
+
+// Lookup finds a command by name or alias, with or without the leading slash.
+func (r *commandRegistry) Lookup(name string) (*slashCommand, bool) {
+	cmd, ok := r.byName[strings.TrimPrefix(strings.ToLower(name), "/")]
+	return cmd, ok
+}
+
+// Handles reports whether input should be dispatched as a command rather than sent to
+// the director. Outside debug mode only registered non-debug commands are intercepted.
+func (r *commandRegistry) Handles(input string, debug bool) bool {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return false
+	}
+	if debug {
+		return true
+	}
+	cmd, ok := r.Lookup(fields[0])
+	return ok && !cmd.DebugOnly
+}
+
+// Dispatch runs the command in input and returns the lines to show, already prefixed
+// for display.
+func (r *commandRegistry) Dispatch(m *Model, input string, debug bool) ([]string, tea.Cmd) {
+	fields := strings.Fields(input)
+	cmd, ok := r.Lookup(fields[0])
+	if !ok || (cmd.DebugOnly && !debug) {
+		return prefixLines(debug, fmt.Sprintf("Unknown command %s. Try /help", fields[0])), nil
+	}
+	args, err := cmd.parseArgs(fields[1:])
+	if err != nil {
+		return prefixLines(debug, fmt.Sprintf("%s: %v. Usage: %s", cmd.Name, err, cmd.Usage())), nil
+	}
+	lines, teaCmd := cmd.Run(m, args)
+	return prefixLines(debug || cmd.DebugOnly, lines...), teaCmd
+}
+
+// HelpLines lists the commands available in the current mode, sorted by name.
+func (r *commandRegistry) HelpLines(debug bool) []string {
+	var visible []*slashCommand
+	for _, cmd := range r.commands {
+		if debug || !cmd.DebugOnly {
+			visible = append(visible, cmd)
+		}
+	}
+	sort.Slice(visible, func(i, j int) bool { return visible[i].Name < visible[j].Name })
+
+	lines := []string{"Available commands:"}
+	for _, cmd := range visible {
+		line := fmt.Sprintf("%s - %s", cmd.Usage(), cmd.Summary)
+		if len(cmd.Aliases) > 0 {
+			line += fmt.Sprintf(" (aliases: /%s)", strings.Join(cmd.Aliases, ", /"))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func prefixLines(debug bool, lines ...string) []string {
+	if !debug {
+		return lines
+	}
+	prefixed := make([]string, len(lines))
+	for i, line := range lines {
+		prefixed[i] = "[DEBUG] " + line
+	}
+	return prefixed
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testRegistry returns a registry with a public command taking a required and an
+// optional argument, a debug-only command and a command whose last argument swallows
+// the rest of the line. Each records the args it ran with.
+func testRegistry(ran *[]string) *commandRegistry {
+	record := func(name string) func(m *Model, args []string) ([]string, tea.Cmd) {
+		return func(m *Model, args []string) ([]string, tea.Cmd) {
+			*ran = append(*ran, name+"("+strings.Join(args, "|")+")")
+			return []string{name + " ran"}, nil
+		}
+	}
+	r := newCommandRegistry()
+	r.Register(slashCommand{
+		Name:    "bookmark",
+		Aliases: []string{"bm"},
+		Args:    []commandArg{{Name: "name"}, {Name: "turn", Optional: true}},
+		Summary: "Bookmark a turn",
+		Run:     record("bookmark"),
+	})
+	r.Register(slashCommand{
+		Name:      "worldstate",
+		DebugOnly: true,
+		Summary:   "Dump the world",
+		Run:       record("worldstate"),
+	})
+	r.Register(slashCommand{
+		Name:    "note",
+		Args:    []commandArg{{Name: "text", Rest: true}},
+		Summary: "Add a note",
+		Run:     record("note"),
+	})
+	return r
+}
+
+func TestCommandRegistryDispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		debug   bool
+		wantRan []string
+		want    []string
+	}{
+		{"by name", "/bookmark start", false, []string{"bookmark(start)"}, []string{"bookmark ran"}},
+		{"optional arg", "/bookmark start 3", false, []string{"bookmark(start|3)"}, []string{"bookmark ran"}},
+		{"alias", "/bm start", false, []string{"bookmark(start)"}, []string{"bookmark ran"}},
+		{"any case", "/BookMark start", false, []string{"bookmark(start)"}, []string{"bookmark ran"}},
+		{"rest joins words", "/note the  door was open", false, []string{"note(the door was open)"}, []string{"note ran"}},
+		{"debug prefix", "/bookmark start", true, []string{"bookmark(start)"}, []string{"[DEBUG] bookmark ran"}},
+		{"debug-only in debug", "/worldstate", true, []string{"worldstate()"}, []string{"[DEBUG] worldstate ran"}},
+		{"unknown", "/teleport moon", false, nil, []string{"Unknown command /teleport. Try /help"}},
+		{"unknown in debug", "/teleport", true, nil, []string{"[DEBUG] Unknown command /teleport. Try /help"}},
+		{"debug-only outside debug", "/worldstate", false, nil, []string{"Unknown command /worldstate. Try /help"}},
+		{"missing arg", "/bookmark", false, nil, []string{"bookmark: missing name. Usage: /bookmark <name> [turn]"}},
+		{"too many args", "/bookmark a 1 2", false, nil, []string{"bookmark: too many arguments. Usage: /bookmark <name> [turn]"}},
+		{"missing rest", "/note", false, nil, []string{"note: missing text. Usage: /note <text...>"}},
+		{"args to a command without any", "/worldstate now", true, nil, []string{"[DEBUG] worldstate: too many arguments. Usage: /worldstate"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			lines, _ := testRegistry(&ran).Dispatch(&Model{}, tt.input, tt.debug)
+			if !reflect.DeepEqual(ran, tt.wantRan) {
+				t.Errorf("ran %v, want %v", ran, tt.wantRan)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("lines = %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandRegistryHandles(t *testing.T) {
+	var ran []string
+	r := testRegistry(&ran)
+	tests := []struct {
+		input string
+		debug bool
+		want  bool
+	}{
+		{"/bookmark start", false, true},
+		{"/bm", false, true},
+		{"/worldstate", false, false},
+		{"/worldstate", true, true},
+		{"/teleport", false, false},
+		// Debug mode reports unknown commands rather than sending them to the director
+		{"/teleport", true, true},
+		{"open the door", true, false},
+		{"", true, false},
+		{"   ", false, false},
+		{"look at /bookmark", false, false},
+	}
+	for _, tt := range tests {
+		if got := r.Handles(tt.input, tt.debug); got != tt.want {
+			t.Errorf("Handles(%q, debug=%v) = %v, want %v", tt.input, tt.debug, got, tt.want)
+		}
+	}
+}
+
+func TestCommandRegistryHelp(t *testing.T) {
+	var ran []string
+	r := testRegistry(&ran)
+	want := []string{
+		"Available commands:",
+		"/bookmark <name> [turn] - Bookmark a turn (aliases: /bm)",
+		"/note <text...> - Add a note",
+	}
+	if got := r.HelpLines(false); !reflect.DeepEqual(got, want) {
+		t.Errorf("help = %q, want %q", got, want)
+	}
+	debugHelp := r.HelpLines(true)
+	if len(debugHelp) != 4 || debugHelp[3] != "/worldstate - Dump the world" {
+		t.Errorf("debug help = %q", debugHelp)
+	}
+}
+
+func TestCommandRegistryRejectsDuplicates(t *testing.T) {
+	var ran []string
+	r := testRegistry(&ran)
+	defer func() {
+		if recover() == nil {
+			t.Error("registering an alias twice did not panic")
+		}
+	}()
+	r.Register(slashCommand{Name: "bm", Summary: "clash"})
+}
+
+func TestSlashCommandsDocumented(t *testing.T) {
+	if _, ok := slashCommands.Lookup("help"); !ok {
+		t.Fatal("/help is not registered")
+	}
+	for _, cmd := range slashCommands.commands {
+		if cmd.Summary == "" {
+			t.Errorf("/%s has no summary for /help", cmd.Name)
+		}
+		if cmd.Run == nil {
+			t.Errorf("/%s has no handler", cmd.Name)
+		}
+		for i, arg := range cmd.Args {
+			if arg.Rest && i != len(cmd.Args)-1 {
+				t.Errorf("/%s: only the last argument can take the rest of the line", cmd.Name)
+			}
+		}
+	}
+}
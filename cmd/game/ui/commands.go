@@ -6,8 +6,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func animationTimer() tea.Cmd {
+func animationTimer(id int) tea.Cmd {
 	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return animationTickMsg{}
+		return animationTickMsg{id: id}
 	})
 }
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/llm"
+)
+
+// slashCommands holds every command the input line understands. /help is generated
+// from it, so registering a command here is all that's needed to document it.
+var slashCommands = newCommandRegistry()
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "help",
+		DebugOnly: true,
+		Summary:   "Show this help",
+		Run: func(m *Model, args []string) ([]string, tea.Cmd) {
+			return slashCommands.HelpLines(m.loggers.Debug.IsEnabled()), nil
+		},
+	})
+	slashCommands.Register(slashCommand{
+		Name:      "worldstate",
+		Aliases:   []string{"world"},
+		DebugOnly: true,
+		Summary:   "Show current world state",
+		Run:       runWorldStateCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:      "inventory",
+		Aliases:   []string{"inv"},
+		DebugOnly: true,
+		Summary:   "List carried items",
+		Run:       runInventoryCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:      "errors",
+		DebugOnly: true,
+		Summary:   "Show errors recorded this session",
+		Run:       runErrorsCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:      "chaos",
+		DebugOnly: true,
+		Summary:   "Show failure injection settings and counts",
+		Run:       runChaosCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:      "usage",
+		DebugOnly: true,
+		Summary:   "Show LLM token usage and estimated cost this session",
+		Run:       runUsageCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:      "bookmark",
+		Args:      []commandArg{{Name: "name"}},
+		DebugOnly: true,
+		Summary:   "Snapshot the world to saves/bookmarks/<name>.json",
+		Run: func(m *Model, args []string) ([]string, tea.Cmd) {
+			line, cmd := m.requestBookmark(args[0])
+			return []string{line}, cmd
+		},
+	})
+}
+
+func runWorldStateCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	lines := []string{
+		"Current World State:",
+		fmt.Sprintf("Player Location: %s", m.world.Location),
+		fmt.Sprintf("Player Inventory: %v", m.world.Inventory),
+		fmt.Sprintf("Available Locations: %v", getLocationList(m.world)),
+	}
+	for locID, loc := range m.world.Locations {
+		lines = append(lines, fmt.Sprintf("%s: %s (Facts: %v, Exits: %v)", locID, loc.Name, loc.Facts, loc.Exits))
+	}
+	lines = append(lines, scheduledEventLines(m.world.ScheduledEvents)...)
+	return lines, nil
+}
+
+func runInventoryCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if len(m.world.Inventory) == 0 {
+		return []string{"Inventory is empty"}, nil
+	}
+	var lines []string
+	for _, itemID := range m.world.Inventory {
+		name := itemID
+		if item, ok := m.world.Items[itemID]; ok && item.Name != "" {
+			name = item.Name
+		}
+		lines = append(lines, "- "+name)
+	}
+	lines = append(lines, "Tip: items you carry can be examined, e.g. \"look at my "+strings.ReplaceAll(m.world.Inventory[0], "_", " ")+"\"")
+	return lines, nil
+}
+
+func runErrorsCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if len(m.sessionErrors) == 0 {
+		return []string{"No errors this session"}, nil
+	}
+	var lines []string
+	for _, sessionErr := range m.sessionErrors {
+		lines = append(lines, fmt.Sprintf("turn %d %s [%s] %s", sessionErr.TurnIndex, sessionErr.At.Format("15:04:05"), sessionErr.Phase, sessionErr.Message))
+	}
+	return lines, nil
+}
+
+func runChaosCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if m.chaos == nil {
+		return []string{"Chaos injection disabled (set CHAOS_* env vars with DEBUG=1)"}, nil
+	}
+	return append([]string{"Chaos injection:"}, m.chaos.Summary()...), nil
+}
+
+func runUsageCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	rows := m.llmService.Usage().Rows()
+	if len(rows) == 0 {
+		return []string{"No LLM calls this session"}, nil
+	}
+	lines := []string{fmt.Sprintf("%-24s %-20s %6s %10s %10s %9s", "operation", "model", "calls", "input", "output", "cost")}
+	unpriced := false
+	for _, row := range rows {
+		cost := "?"
+		if row.PriceKnown {
+			cost = fmt.Sprintf("$%.4f", row.CostUSD)
+		} else {
+			unpriced = true
+		}
+		lines = append(lines, fmt.Sprintf("%-24s %-20s %6d %10d %10d %9s", row.Operation, row.Model, row.Calls, row.InputTokens, row.OutputTokens, cost))
+	}
+	totals, cost := llm.Total(rows)
+	lines = append(lines, fmt.Sprintf("%-24s %-20s %6d %10d %10d %9s", "total", "", totals.Calls, totals.InputTokens, totals.OutputTokens, fmt.Sprintf("$%.4f", cost)))
+	if unpriced {
+		lines = append(lines, "? = no price for the model (set LLM_PRICE_CONFIG); left out of the total")
+	}
+	return lines, nil
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+)
+
+// debugLog writes lines to the debug log and mirrors them to the chat pane when their
+// category is shown there.
+func (m *Model) debugLog(category debug.Category, lines ...string) {
+	m.messages = append(m.messages, m.loggers.Debug.Log(category, lines...)...)
+}
+
+// debugError logs a failed step with its error and, when the category is shown, adds a
+// red line naming the step to the chat pane.
+func (m *Model) debugError(category debug.Category, step string, err error) {
+	if !m.loggers.Debug.IsEnabled() {
+		return
+	}
+	m.loggers.Debug.Errorf("%s: %v", step, err)
+	if m.loggers.Debug.Shows(category) {
+		m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("[ERROR] %s", step)))
+	}
+}
+
+// outcomeLines lists mutation results under an event: successes in magenta, failures
+// in red.
+func outcomeLines(successes, failures []string) []string {
+	lines := make([]string, 0, len(successes)+len(failures))
+	for _, success := range successes {
+		lines = append(lines, colorize(colorMagenta, "  "+success))
+	}
+	for _, failure := range failures {
+		lines = append(lines, colorize(colorRed, fmt.Sprintf("  [ERROR] %s", failure)))
+	}
+	return lines
+}
+
+func runDebugCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	logger := m.loggers.Debug
+	if len(args) == 0 {
+		lines := []string{fmt.Sprintf("Debug mode: %s", logger.Mode())}
+		if logger.Mode() != debug.UI {
+			lines = append(lines, "Nothing is mirrored to the chat pane; run with DEBUG=ui to show the categories below")
+		}
+		for _, category := range debug.Categories {
+			state := "on"
+			if logger.Hidden(category) {
+				state = "off"
+			}
+			lines = append(lines, fmt.Sprintf("  %-10s %s", category, state))
+		}
+		return lines, nil
+	}
+	if args[0] != "show" || len(args) != 3 {
+		return []string{"Usage: /debug show <category|all> on|off"}, nil
+	}
+	var shown bool
+	switch strings.ToLower(args[2]) {
+	case "on":
+		shown = true
+	case "off":
+	default:
+		return []string{fmt.Sprintf("debug: %q is not on or off", args[2])}, nil
+	}
+	categories := debug.Categories
+	if !strings.EqualFold(args[1], "all") {
+		category, err := debug.ParseCategory(args[1])
+		if err != nil {
+			return []string{fmt.Sprintf("debug: %v", err)}, nil
+		}
+		categories = []debug.Category{category}
+	}
+	for _, category := range categories {
+		if err := logger.SetShown(category, shown); err != nil {
+			return []string{fmt.Sprintf("Failed to save debug settings: %v", err)}, nil
+		}
+	}
+	return []string{fmt.Sprintf("Debug %s: %s in the chat pane", args[1], args[2])}, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "debug",
+		Args:      []commandArg{{Name: "show", Optional: true}, {Name: "category", Optional: true}, {Name: "on|off", Optional: true}},
+		DebugOnly: true,
+		Summary:   "List the debug categories, or show or hide one (or all) in the chat pane",
+		Run:       runDebugCommand,
+	})
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+)
+
+// dialogueOptionsStyle sets the follow-ups an authored line offers apart from narration.
+const dialogueOptionsStyle = "3"
+
+// dialogueAppliedMsg carries the outcome of an authored dialogue node's effects.
+type dialogueAppliedMsg struct {
+	npcID     string
+	nodeID    string
+	successes []string
+	failures  []string
+}
+
+// applyDialogueCmd runs the effects of an authored line an NPC just said and records it
+// as said, before the director interprets the line itself.
+func (m Model) applyDialogueCmd(npcID string, node game.DialogueNode) tea.Cmd {
+	if m.mcpClient == nil {
+		return nil
+	}
+	ctx := m.createGameContext(m.turnContext, "dialogue.apply")
+	client := m.mcpClient
+	debugLogger := m.loggers.Debug
+	world := m.world
+	return func() tea.Msg {
+		successes, failures := director.ApplyDialogue(ctx, npcID, node, client, debugLogger, world)
+		return dialogueAppliedMsg{npcID: npcID, nodeID: node.ID, successes: successes, failures: failures}
+	}
+}
+
+// handleDialogueApplied adds an authored line's effects to the turn's results. The
+// director's pass over the line refreshes the world afterwards.
+func (m Model) handleDialogueApplied(msg dialogueAppliedMsg) (tea.Model, tea.Cmd) {
+	m.currentMutationResults = append(m.currentMutationResults, msg.successes...)
+	m.currentFailures = append(m.currentFailures, msg.failures...)
+	lines := []string{colorize(colorCyan, fmt.Sprintf("[DIALOGUE] %s said %s", msg.npcID, msg.nodeID))}
+	lines = append(lines, outcomeLines(msg.successes, msg.failures)...)
+	(&m).debugLog(debug.Events, append(lines, "")...)
+	return m, nil
+}
+
+// showDialogueOptions lists the follow-ups offered by the turn's authored lines once the
+// narration is done.
+func (m *Model) showDialogueOptions() {
+	if len(m.dialogueOptions) == 0 {
+		return
+	}
+	m.messages = append(m.messages, colorize(dialogueOptionsStyle, "You could: "+strings.Join(m.dialogueOptions, " · ")), "")
+	m.dialogueOptions = nil
+}
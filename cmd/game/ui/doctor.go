@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/doctor"
+	"textadventure/internal/observability"
+)
+
+// doctorResultsMsg carries the results of /doctor's probes.
+type doctorResultsMsg struct {
+	results []doctor.Result
+}
+
+// doctorChecks are the probes /doctor runs against this session's services.
+func (m Model) doctorChecks() []doctor.Check {
+	var world doctor.WorldStore
+	if m.mcpClient != nil {
+		world = m.mcpClient
+	}
+	var models doctor.Models
+	if service, ok := m.llmService.(doctor.Models); ok {
+		models = service
+	}
+	var store doctor.Store
+	if m.loggers.Completion != nil {
+		store = m.loggers.Completion
+	}
+	return doctor.Checks(world, models, store, observability.LoadConfigFromEnv())
+}
+
+func runDoctorCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	ctx := m.createGameContext(m.sessionContext, "doctor")
+	checks := m.doctorChecks()
+	return []string{fmt.Sprintf("Running %d checks...", len(checks))}, func() tea.Msg {
+		return doctorResultsMsg{results: doctor.Run(ctx, checks, doctor.DefaultTimeout)}
+	}
+}
+
+// handleDoctorResults shows one line per check, failures in red.
+func (m Model) handleDoctorResults(msg doctorResultsMsg) (tea.Model, tea.Cmd) {
+	for i, line := range doctor.Format(msg.results) {
+		if !msg.results[i].OK() {
+			line = colorize(colorRed, line)
+		}
+		m.messages = append(m.messages, line)
+	}
+	if failed := doctor.Failures(msg.results); failed > 0 {
+		m.messages = append(m.messages, fmt.Sprintf("%d of %d checks failed", failed, len(msg.results)))
+	} else {
+		m.messages = append(m.messages, "All checks passed")
+	}
+	m.messages = append(m.messages, "")
+	return m, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:    "doctor",
+		Summary: "Probe the world server, models, database and tracing, and report what's broken",
+		Run:     runDoctorCommand,
+	})
+}
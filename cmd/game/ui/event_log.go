@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultEventLogLines is how many events /events shows without an argument.
+const defaultEventLogLines = 20
+
+func runEventsCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if m.eventStore == nil {
+		return []string{"World event log unavailable"}, nil
+	}
+	n := defaultEventLogLines
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed <= 0 {
+			return []string{fmt.Sprintf("events: %q is not a positive number", args[0])}, nil
+		}
+		n = parsed
+	}
+	stored, err := m.eventStore.Last(n)
+	if err != nil {
+		return []string{fmt.Sprintf("Failed to read world events: %v", err)}, nil
+	}
+	if len(stored) == 0 {
+		return []string{"No world events this session"}, nil
+	}
+	lines := []string{fmt.Sprintf("Last %d world events:", len(stored))}
+	for _, event := range stored {
+		lines = append(lines, fmt.Sprintf("turn %d %s [%s] %s", event.TurnIndex, event.Timestamp.Format("15:04:05"), event.Type, event.Line()))
+	}
+	return lines, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "events",
+		Args:      []commandArg{{Name: "n", Optional: true}},
+		DebugOnly: true,
+		Summary:   "Show the session's last n world events (default 20) with their turn",
+		Run:       runEventsCommand,
+	})
+}
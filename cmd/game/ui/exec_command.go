@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+	"textadventure/internal/mcp"
+)
+
+// execResultMsg carries the outcome of /exec back to the UI.
+type execResultMsg struct {
+	tool       string
+	successes  []string
+	failures   []string
+	world      game.WorldState
+	invariants []game.InvariantViolation
+	refreshErr error
+}
+
+// parseExecArgs reads the JSON object given to /exec. An empty string means no args.
+func parseExecArgs(raw string) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	if raw == "" {
+		return args, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return nil, fmt.Errorf("invalid JSON args: %w", err)
+	}
+	return args, nil
+}
+
+// runExecCommand runs one director tool directly, skipping the LLM. It goes through
+// ExecuteMutations like a real turn, so validation, NPC/item resolution and tracing
+// all apply, then refreshes the world. No turn starts and history is untouched.
+func runExecCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	toolName := args[0]
+	raw := ""
+	if len(args) > 1 {
+		raw = args[1]
+	}
+	toolArgs, err := parseExecArgs(raw)
+	if err != nil {
+		return []string{"exec: " + err.Error()}, nil
+	}
+	if m.mcpClient == nil {
+		return []string{"exec: no world-state server connected"}, nil
+	}
+
+	ctx := m.createGameContext(m.sessionContext, "debug.exec")
+	client := m.mcpClient
+	logger := m.loggers.Debug
+	world := m.world
+	mutations := []director.MutationRequest{{Tool: toolName, Args: toolArgs}}
+	cmd := func() tea.Msg {
+		successes, failures := director.ExecuteMutations(ctx, mutations, client, logger, world, "")
+		result := execResultMsg{tool: toolName, successes: successes, failures: failures, world: world}
+		mcpWorld, err := client.GetWorldState(ctx)
+		if err != nil {
+			result.refreshErr = err
+		} else {
+			result.world, result.invariants = director.RepairRefreshedWorld(nil, logger, world, mcp.MCPToGameWorldState(mcpWorld))
+		}
+		return result
+	}
+	return []string{fmt.Sprintf("Executing %s...", toolName)}, cmd
+}
+
+func (m Model) handleExecResult(msg execResultMsg) (tea.Model, tea.Cmd) {
+	if msg.refreshErr == nil {
+		(&m).setWorld(msg.world)
+		(&m).recordInvariantViolations(msg.invariants)
+	}
+	m.messages = append(m.messages, colorize(colorMagenta, "[PLAYER MUTATIONS]"))
+	for _, success := range msg.successes {
+		m.messages = append(m.messages, colorize(colorMagenta, "  "+success))
+	}
+	for _, failure := range msg.failures {
+		m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("  [ERROR] %s", failure)))
+	}
+	if msg.refreshErr != nil {
+		m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("  [ERROR] World refresh failed: %v", msg.refreshErr)))
+	}
+	m.messages = append(m.messages, "")
+	return m, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "exec",
+		Args:      []commandArg{{Name: "tool"}, {Name: "json-args", Optional: true, Rest: true}},
+		DebugOnly: true,
+		Summary:   "Run a director tool directly, e.g. /exec move_player {\"location\": \"study\"}. No turn is used",
+		Run:       runExecCommand,
+	})
+}
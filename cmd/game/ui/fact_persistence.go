@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/facts"
+	"textadventure/internal/mcp"
+)
+
+// queueFactWrites holds attributed facts for the end-of-turn flush, with the local
+// updates that mirror them. Items the world doesn't have yet are created at the
+// observer's location; known items, or every item when the server lacks create_item,
+// get add_item_facts.
+func (m *Model) queueFactWrites(attribution *facts.FactAttribution, observerLocationID string) {
+	for _, locationID := range slices.Sorted(maps.Keys(attribution.LocationFacts)) {
+		locationFacts := attribution.LocationFacts[locationID]
+		if len(locationFacts) == 0 {
+			continue
+		}
+		m.queueWrite(mcp.LocationFactsCall(locationID, locationFacts), locationID, func(world *game.WorldState) {
+			if loc, exists := world.Locations[locationID]; exists {
+				loc.Facts = append(loc.Facts, locationFacts...)
+				world.Locations[locationID] = loc
+			}
+		})
+	}
+
+	for _, itemID := range slices.Sorted(maps.Keys(attribution.ItemFacts)) {
+		itemFacts := attribution.ItemFacts[itemID]
+		if len(itemFacts) == 0 {
+			continue
+		}
+		call := mcp.ItemFactsCall(itemID, itemFacts)
+		if _, exists := m.world.Items[itemID]; !exists && m.mcpClient != nil && m.mcpClient.HasTool("create_item") {
+			// Use item_id as name for now
+			call = mcp.CreateItemCall(itemID, itemID, observerLocationID, itemFacts)
+		}
+		m.queueWrite(call, itemID, nil)
+	}
+
+	for _, npcID := range slices.Sorted(maps.Keys(attribution.NPCFacts)) {
+		npcFacts := attribution.NPCFacts[npcID]
+		if len(npcFacts) == 0 {
+			continue
+		}
+		m.queueNPCWrite(mcp.NPCFactsCall(npcID, npcFacts), npcID, func(world *game.WorldState) {
+			if npc, exists := world.NPCs[npcID]; exists {
+				npc.Facts = append(npc.Facts, npcFacts...)
+				world.NPCs[npcID] = npc
+			}
+		})
+	}
+}
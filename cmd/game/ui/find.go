@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+)
+
+// runFindCommand answers "where did I leave it?" in the game's voice, from what the
+// player can know: what they carry and where they last saw the item. An item they have
+// never seen, or one the world doesn't have, gets the same answer, so /find can't be
+// used to learn what exists.
+func runFindCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	ref := strings.Join(args, " ")
+	itemID, err := game.ResolveItemID(m.world, ref)
+	if _, exists := m.world.Items[itemID]; err != nil || !exists {
+		return []string{"You don't remember seeing that."}, nil
+	}
+	var moves []events.ItemMove
+	if m.eventStore != nil {
+		if moves, err = m.eventStore.ItemMoves(itemID); err != nil {
+			m.loggers.Debug.Errorf("find: failed to read item moves: %v", err)
+		}
+	}
+	name := strings.ToLower(game.DisplayName(m.world, itemID))
+	sighting, ok := events.LastSeen(m.world, itemID, moves)
+	switch {
+	case !ok:
+		return []string{"You don't remember seeing that."}, nil
+	case sighting.Carried:
+		return []string{fmt.Sprintf("You're carrying the %s.", name)}, nil
+	}
+	return []string{fmt.Sprintf("You last saw the %s %s.", name, m.sightingPlace(sighting))}, nil
+}
+
+// sightingPlace describes where an item was seen: "in the study", "in the oak chest in
+// the study" or "with Elena in the study", masking NPCs the player hasn't met.
+func (m Model) sightingPlace(sighting events.Sighting) string {
+	room := "in the " + strings.ToLower(game.DisplayName(m.world, sighting.Room))
+	switch {
+	case sighting.Holder == sighting.Room:
+		return room
+	case sighting.Holder == "player":
+		return "on you, " + room
+	case m.world.Items[sighting.Holder].IsContainer:
+		return fmt.Sprintf("in the %s %s", strings.ToLower(game.DisplayName(m.world, sighting.Holder)), room)
+	}
+	if _, isNPC := m.world.NPCs[sighting.Holder]; isNPC {
+		return fmt.Sprintf("with %s %s", game.MaskUnmetNPCNames(m.world, game.NPCName(sighting.Holder)), room)
+	}
+	return room
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:    "find",
+		Args:    []commandArg{{Name: "item", Rest: true}},
+		Summary: "Recall where you last saw an item",
+		Run:     runFindCommand,
+	})
+}
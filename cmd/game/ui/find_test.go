@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+)
+
+// findModel has the player in the foyer, having visited the study, with a lamp. The
+// study holds an opened oak chest with a coin, a closed box with a ring, and elena with
+// a letter; the cellar, with the wine, has never been visited.
+func findModel(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel(t)
+	m.world = game.WorldState{
+		Location:         "foyer",
+		Inventory:        []string{"lamp"},
+		VisitedLocations: []string{"foyer", "study"},
+		Locations: map[string]game.LocationInfo{
+			"foyer":  {Name: "Foyer"},
+			"study":  {Name: "Study"},
+			"cellar": {Name: "Cellar"},
+		},
+		NPCs: map[string]game.NPCInfo{
+			"elena": {Location: "study", Description: "a pale woman", Inventory: []string{"letter"}},
+		},
+		Items: map[string]game.ItemInfo{
+			"lamp":   {Name: "brass lamp", Location: "player"},
+			"letter": {Name: "letter", Location: "elena"},
+			"chest":  {Name: "Oak Chest", Location: "study", IsContainer: true, Contains: []string{"coin"}, Facts: []string{game.ContainerOpenedFact}},
+			"coin":   {Name: "coin", Location: "chest"},
+			"box":    {Name: "box", Location: "study", IsContainer: true, Contains: []string{"ring"}},
+			"ring":   {Name: "ring", Location: "box"},
+			"wine":   {Name: "wine", Location: "cellar"},
+			"stool":  {Name: "stool", Location: "study"},
+		},
+	}
+	return m
+}
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		met  bool
+		want string
+	}{
+		{"carried, by name", []string{"brass", "lamp"}, false, "You're carrying the brass lamp."},
+		{"in a visited room", []string{"stool"}, false, "You last saw the stool in the study."},
+		{"in an opened container", []string{"coin"}, false, "You last saw the coin in the oak chest in the study."},
+		{"with an unmet NPC", []string{"letter"}, false, "You last saw the letter with a pale woman in the study."},
+		{"with a met NPC", []string{"letter"}, true, "You last saw the letter with Elena in the study."},
+		// Never seen and nonexistent get the same answer
+		{"in an unopened container", []string{"ring"}, false, "You don't remember seeing that."},
+		{"in an unvisited room", []string{"wine"}, false, "You don't remember seeing that."},
+		{"no such item", []string{"sword"}, false, "You don't remember seeing that."},
+		{"no item given", nil, false, "You don't remember seeing that."},
+	}
+	for _, tt := range tests {
+		m := findModel(t)
+		if tt.met {
+			m.world.MetNPCs = []string{"elena"}
+		}
+		lines, cmd := runFindCommand(&m, tt.args)
+		if cmd != nil || len(lines) != 1 || lines[0] != tt.want {
+			t.Errorf("%s: /find = %q, want %q", tt.name, lines, tt.want)
+		}
+	}
+}
+
+// With an event log, /find answers from the item's logged moves rather than where the
+// world has it now.
+func TestFindCommandUsesItemMoves(t *testing.T) {
+	store, err := events.OpenStore(filepath.Join(t.TempDir(), "events.db"), "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	m := findModel(t)
+	m.eventStore = store
+
+	// elena carried the letter to the foyer on turn 3 and on into the cellar on turn 6
+	letter := m.world.Items["letter"]
+	letter.Location = "cellar"
+	m.world.Items["letter"] = letter
+	moves := []events.ItemMove{
+		{Item: "letter", Actor: "elena", From: "elena", FromRoom: "study", To: "foyer", ToRoom: "foyer"},
+	}
+	if err := store.AppendItemMoves(3, moves); err != nil {
+		t.Fatal(err)
+	}
+	moves = []events.ItemMove{
+		{Item: "letter", Actor: "elena", From: "foyer", FromRoom: "foyer", To: "cellar", ToRoom: "cellar"},
+	}
+	if err := store.AppendItemMoves(6, moves); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, _ := runFindCommand(&m, []string{"letter"})
+	if want := "You last saw the letter in the foyer."; len(lines) != 1 || lines[0] != want {
+		t.Errorf("/find = %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/worldgraph"
+)
+
+// exportGraph writes the world graph to the session directory as DOT or JSON and
+// returns the file it wrote.
+func (m *Model) exportGraph(format string) (string, worldgraph.Graph, error) {
+	graph := worldgraph.Build(m.world)
+	var data []byte
+	switch format {
+	case "dot":
+		data = []byte(graph.DOT())
+	case "json":
+		encoded, err := graph.JSON()
+		if err != nil {
+			return "", graph, err
+		}
+		data = encoded
+	default:
+		return "", graph, fmt.Errorf("unknown format %q (expected dot or json)", format)
+	}
+	path := filepath.Join(m.artifactDir, fmt.Sprintf("graph-turn%d.%s", m.turnIndex, format))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", graph, fmt.Errorf("write graph: %w", err)
+	}
+	return path, graph, nil
+}
+
+func runExportGraphCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	format := "dot"
+	if len(args) > 0 {
+		format = args[0]
+	}
+	path, graph, err := m.exportGraph(format)
+	if err != nil {
+		return []string{fmt.Sprintf("Graph export failed: %v", err)}, nil
+	}
+	return []string{fmt.Sprintf("Graph written to %s (%d nodes, %d edges)", path, len(graph.Nodes), len(graph.Edges))}, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "export-graph",
+		Args:      []commandArg{{Name: "dot|json", Optional: true}},
+		DebugOnly: true,
+		Summary:   "Write who is where, holds what and knows whom to the session directory",
+		Run:       runExportGraphCommand,
+	})
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/guide"
+)
+
+// guideStyle sets guide answers apart from narration (italic cyan), so players learn
+// which replies are the game speaking out of character.
+const guideStyle = "3;36"
+
+// guideClassifiedMsg carries the model's call on an input the keyword rules were unsure of.
+type guideClassifiedMsg struct {
+	input string
+	meta  bool
+	err   error
+}
+
+// guideAnswerMsg carries the guide's answer to a meta question.
+type guideAnswerMsg struct {
+	answer string
+	err    error
+}
+
+// routeInput sends a meta question to the guide and anything else into a turn. Inputs
+// the keyword rules are unsure of are classified by the model first; while that or the
+// answer is in flight no turn runs, and further input is queued.
+func (m *Model) routeInput(userInput string) tea.Cmd {
+	switch guide.Classify(userInput) {
+	case guide.Meta:
+		return m.askGuide(userInput)
+	case guide.Unsure:
+		m.guidePending = true
+		ctx := m.createGameContext(m.sessionContext, "guide.classify")
+		llmService := m.llmService
+		return func() tea.Msg {
+			meta, err := guide.IsMeta(ctx, llmService, userInput)
+			return guideClassifiedMsg{input: userInput, meta: meta, err: err}
+		}
+	default:
+		return m.startPlayerTurn(userInput)
+	}
+}
+
+func (m Model) handleGuideClassified(msg guideClassifiedMsg) (tea.Model, tea.Cmd) {
+	m.guidePending = false
+	if msg.err != nil {
+		// When in doubt the input is played; a lost question costs less than a lost action
+		(&m).recordSessionError("guide.classify", msg.err.Error())
+	}
+	if msg.meta {
+		return m, (&m).askGuide(msg.input)
+	}
+	return m, (&m).startPlayerTurn(msg.input)
+}
+
+// askGuide answers a meta question without starting a turn: the turn index, history,
+// NPCs and facts are left alone and the world isn't touched.
+func (m *Model) askGuide(question string) tea.Cmd {
+	m.messages = append(m.messages, "", "> "+question, "")
+	m.guidePending = true
+	ctx := m.createGameContext(m.sessionContext, "guide.answer")
+	llmService := m.llmService
+	world := m.world
+	history := m.gameHistory.For(game.HistoryForNarration, "")
+	return func() tea.Msg {
+		answer, err := guide.Answer(ctx, llmService, question, world, history)
+		return guideAnswerMsg{answer: answer, err: err}
+	}
+}
+
+func (m Model) handleGuideAnswer(msg guideAnswerMsg) (tea.Model, tea.Cmd) {
+	m.guidePending = false
+	answer := msg.answer
+	if msg.err != nil {
+		(&m).recordSessionError("guide.answer", msg.err.Error())
+		answer = "The guide can't answer right now. Try looking around."
+	}
+	m.messages = append(m.messages, colorize(guideStyle, "[guide] "+answer), "")
+	return m, nil
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+	"textadventure/internal/llm"
+	"textadventure/internal/logging"
+)
+
+// play runs cmds as the program would and keeps going with every command Update returns,
+// until the model settles. Animation and watchdog ticks are dropped so nothing repeats.
+func play(t *testing.T, m Model, cmds ...tea.Cmd) Model {
+	t.Helper()
+	for steps := 0; len(cmds) > 0; steps++ {
+		if steps > 500 {
+			t.Fatal("the session did not settle")
+		}
+		cmd := cmds[0]
+		cmds = cmds[1:]
+		if cmd == nil {
+			continue
+		}
+		msg := cmd()
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			cmds = append(cmds, msg...)
+			continue
+		case animationTickMsg, turnWatchdogMsg, worldHealthTickMsg, tea.QuitMsg:
+			continue
+		}
+		if value := reflect.ValueOf(msg); value.Kind() == reflect.Slice && value.Type().Elem() == reflect.TypeOf(tea.Cmd(nil)) {
+			for i := range value.Len() {
+				cmds = append(cmds, value.Index(i).Interface().(tea.Cmd))
+			}
+			continue
+		}
+		updated, next := m.Update(msg)
+		m = updated.(Model)
+		cmds = append(cmds, next)
+	}
+	return m
+}
+
+// Engine machinery (the intro's synthetic input, the old NPC narration trigger and
+// error text) never reaches a prompt an NPC sees, however a session goes.
+func TestNPCPromptsCarryNoEngineText(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m, fake := withFakeWorld(t, newTestModel(t))
+	m.SetTurnTimeout(0)
+	completions, err := logging.NewCompletionLogger("completions.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { completions.Close() })
+	m.loggers.Completion = completions
+	elena := m.world.NPCs["elena"]
+	elena.Location = "foyer"
+	m.world.NPCs["elena"] = elena
+	fake.MoveNPC(t.Context(), "elena", "foyer")
+
+	// Every turn the director also moves the player, which the world fails with an
+	// "Error: ..." answer.
+	fake.FailTool("move_player", errors.New("Error: the world store is locked"))
+	service := llm.NewMockService().
+		OnOperation("director", `{"mutations": [{"tool": "move_player", "args": {"location": "study"}}]}`).
+		OnOperation("events.summarize", `{"events": [{"type": "action", "content": "player looks around the foyer"}]}`).
+		OnOperation("narration", "Dust hangs in the foyer's grey light.").
+		OnOperation("npc.perceive", `{"events": ["player looks around the foyer"]}`).
+		OnOperation("npc.goal_review", `{"completed": [], "added": []}`).
+		OnOperation("npc", "Elena watches the stranger carefully.")
+	m.llmService = service
+	m.director = director.NewDirector(service, fake.WorldStateClient, m.loggers.Debug)
+
+	updated, cmd := m.Update(initialLookAroundMsg{})
+	m = play(t, updated.(Model), cmd)
+	for _, input := range []string{"look around", "wait"} {
+		m.input = input
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = play(t, updated.(Model), cmd)
+	}
+	if m.turnPhase != AwaitingInput {
+		t.Fatalf("session stopped in %v", m.turnPhase)
+	}
+
+	leaks := []string{"narrate recent events", game.IntroInput, "Error:"}
+	npcCalls := map[string]int{}
+	for _, call := range service.Calls() {
+		if !strings.HasPrefix(call.Operation, "npc.") {
+			continue
+		}
+		npcCalls[call.Operation]++
+		for _, leak := range leaks {
+			if strings.Contains(call.SystemPrompt, leak) || strings.Contains(call.UserPrompt, leak) {
+				t.Errorf("%s prompt contains %q:\n%s\n%s", call.Operation, leak, call.SystemPrompt, call.UserPrompt)
+			}
+		}
+	}
+	if npcCalls["npc.think"] == 0 || npcCalls["npc.act"] == 0 {
+		t.Fatalf("no NPC took a turn: %v", npcCalls)
+	}
+	for _, entry := range m.gameHistory.GetEntries() {
+		for _, leak := range leaks {
+			if strings.Contains(entry, leak) {
+				t.Errorf("history entry %q contains %q", entry, leak)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inputCursorGlyph marks the cursor position in the rendered input line.
+const inputCursorGlyph = "│"
+
+// handleEditKey applies readline-style editing keys to the input line. cursor is a rune
+// index into input. It reports whether the key was an editing key.
+func (m *Model) handleEditKey(msg tea.KeyMsg) bool {
+	runes := []rune(m.input)
+	m.cursor = max(0, min(m.cursor, len(runes)))
+
+	switch msg.Type {
+	case tea.KeyLeft:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyRight:
+		if m.cursor < len(runes) {
+			m.cursor++
+		}
+	case tea.KeyCtrlA:
+		m.cursor = 0
+	case tea.KeyCtrlE:
+		m.cursor = len(runes)
+	case tea.KeyBackspace:
+		if m.cursor > 0 {
+			m.setInput(append(runes[:m.cursor-1:m.cursor-1], runes[m.cursor:]...), m.cursor-1)
+		}
+	case tea.KeyDelete:
+		if m.cursor < len(runes) {
+			m.setInput(append(runes[:m.cursor:m.cursor], runes[m.cursor+1:]...), m.cursor)
+		}
+	case tea.KeyCtrlW:
+		start := previousWordStart(runes, m.cursor)
+		m.setInput(append(runes[:start:start], runes[m.cursor:]...), start)
+	case tea.KeySpace:
+		m.insertInput(" ")
+	case tea.KeyRunes:
+		// Pastes arrive as one message with many runes
+		m.insertInput(string(msg.Runes))
+	default:
+		return false
+	}
+	return true
+}
+
+// insertInput inserts text at the cursor, flattening pasted line breaks and tabs into
+// spaces so the input stays on one line.
+func (m *Model) insertInput(text string) {
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	if text == "" {
+		return
+	}
+	runes := []rune(m.input)
+	inserted := []rune(text)
+	edited := make([]rune, 0, len(runes)+len(inserted))
+	edited = append(edited, runes[:m.cursor]...)
+	edited = append(edited, inserted...)
+	edited = append(edited, runes[m.cursor:]...)
+	m.setInput(edited, m.cursor+len(inserted))
+}
+
+// clearInput empties the input line, e.g. after it was submitted.
+func (m *Model) clearInput() {
+	m.input = ""
+	m.cursor = 0
+}
+
+func (m *Model) setInput(runes []rune, cursor int) {
+	m.input = string(runes)
+	m.cursor = cursor
+}
+
+// previousWordStart is where ctrl+w deletes back to: over any spaces before the
+// cursor, then over the word before them.
+func previousWordStart(runes []rune, cursor int) int {
+	i := cursor
+	for i > 0 && unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	return i
+}
+
+// renderInputLine shows the input with the cursor glyph at the cursor position.
+func (m Model) renderInputLine() string {
+	runes := []rune(m.input)
+	cursor := max(0, min(m.cursor, len(runes)))
+	return string(runes[:cursor]) + inputCursorGlyph + string(runes[cursor:])
+}
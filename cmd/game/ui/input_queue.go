@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// duplicateSubmitWindow is how soon after an enter the same text counts as a repeat of
+// it rather than a new action: a double-tap, or enter held down.
+const duplicateSubmitWindow = time.Second
+
+// isDuplicateSubmit reports whether input repeats the last thing entered within
+// duplicateSubmitWindow while that action is still queued or being played, and records
+// the press. A repeat is dropped rather than queued behind the action it duplicates.
+func (m *Model) isDuplicateSubmit(input string, now time.Time) bool {
+	input = strings.TrimSpace(input)
+	repeat := input == m.lastSubmitted && now.Sub(m.lastSubmittedAt) < duplicateSubmitWindow
+	m.lastSubmitted, m.lastSubmittedAt = input, now
+	if !repeat {
+		return false
+	}
+	queued := strings.TrimSpace(m.queuedInput) == input
+	playing := m.turnPhase != AwaitingInput && strings.TrimSpace(m.currentUserInput) == input
+	if !queued && !playing && !m.guidePending && !m.loadPending && !m.writesPending {
+		return false
+	}
+	m.loggers.Debug.Printf("ignored repeated enter: %q is already queued or in flight", input)
+	return true
+}
+
+// submitQueuedInput starts the action the player queued with enter while the last
+// turn was in flight, once the model is back to AwaitingInput. It runs after every
+// message, like syncWorldAvailability, so the finished turn's span has already ended
+// and the queued action gets a fresh one. Escape clears the queue before it fires.
+func (m *Model) submitQueuedInput() tea.Cmd {
+	if m.queuedInput == "" || m.turnPhase != AwaitingInput || m.isStreaming() || m.guidePending || m.loadPending || m.writesPending {
+		return nil
+	}
+	userInput := m.queuedInput
+	m.queuedInput = ""
+	return m.submitInput(userInput)
+}
+
+// queuedIndicator is shown after the input while an action is queued.
+func (m Model) queuedIndicator() string {
+	if m.queuedInput == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Render("  [queued: " + truncate(m.queuedInput, 24) + " · esc clears]")
+}
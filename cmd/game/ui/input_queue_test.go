@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestIsDuplicateSubmit(t *testing.T) {
+	start := time.Unix(1000, 0)
+	tests := []struct {
+		name    string
+		setup   func(m *Model)
+		input   string
+		after   time.Duration
+		dropped bool
+	}{
+		{"repeat of the queued input", func(m *Model) { m.turnPhase = Narration; m.queuedInput = "go north" }, "go north", 200 * time.Millisecond, true},
+		{"repeat of the input being played", func(m *Model) { m.turnPhase = PlayerTurn; m.currentUserInput = "go north" }, " go north ", 200 * time.Millisecond, true},
+		{"repeat while the guide answers", func(m *Model) { m.guidePending = true }, "go north", 200 * time.Millisecond, true},
+		{"repeat after the window", func(m *Model) { m.turnPhase = Narration; m.queuedInput = "go north" }, "go north", duplicateSubmitWindow, false},
+		{"different input", func(m *Model) { m.turnPhase = Narration; m.queuedInput = "go north" }, "go south", 200 * time.Millisecond, false},
+		{"repeat of a finished turn", func(m *Model) { m.currentUserInput = "go north" }, "go north", 200 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		m := newTestModel(t)
+		if m.isDuplicateSubmit("go north", start) {
+			t.Fatalf("%s: the first press was dropped", tt.name)
+		}
+		tt.setup(&m)
+		if got := m.isDuplicateSubmit(tt.input, start.Add(tt.after)); got != tt.dropped {
+			t.Errorf("%s: dropped = %v, want %v", tt.name, got, tt.dropped)
+		}
+	}
+}
+
+// Enter pressed twice on an action waiting behind a turn queues it once; the chat and
+// the turn in flight are left alone.
+func TestRepeatedEnterDuringTurn(t *testing.T) {
+	m := newTestModel(t)
+	if _, ok := m.beginTurn(turnEventPlayerInput); !ok {
+		t.Fatal("turn did not begin")
+	}
+	m.currentUserInput = "look"
+	index, messages := m.turnIndex, slices.Clone(m.messages)
+
+	m = enter(t, m, "go north")
+	m = enter(t, m, "go north")
+	if m.queuedInput != "go north" || m.input != "" {
+		t.Errorf("queued %q, input %q", m.queuedInput, m.input)
+	}
+	m = enter(t, m, "look")
+	if m.queuedInput != "look" {
+		t.Errorf("a new action should replace the queue, got %q", m.queuedInput)
+	}
+	m = enter(t, m, "look")
+	if m.queuedInput != "look" {
+		t.Errorf("queued %q after a repeat", m.queuedInput)
+	}
+	if m.turnPhase != PlayerTurn || m.turnIndex != index || !slices.Equal(m.messages, messages) {
+		t.Errorf("repeated enter touched the turn in flight: phase %s, turn %d", m.turnPhase, m.turnIndex)
+	}
+}
+
+// Input that races a turn already in flight is dropped before the chat or history see it.
+func TestStartPlayerTurnRefusedDuringTurn(t *testing.T) {
+	m := newTestModel(t)
+	m.beginTurn(turnEventIntro)
+	messages := slices.Clone(m.messages)
+	if cmd := m.startPlayerTurn("go north"); cmd != nil {
+		t.Error("a second turn started")
+	}
+	if m.turnPhase != Narration || m.currentUserInput == "go north" || !slices.Equal(m.messages, messages) {
+		t.Errorf("dropped input changed the model: phase %s, messages %q", m.turnPhase, m.messages)
+	}
+}
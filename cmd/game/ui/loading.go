@@ -0,0 +1,57 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// loadingRowSentinel marks where View draws the loading animation. It only ever appears
+// in the slice View renders, never in m.messages, so handlers can't remove it by position.
+const loadingRowSentinel = "\x00LOADING_ANIMATION"
+
+// isLoading reports whether the loading animation should show: a turn is in progress
+// and narration hasn't started streaming into the pane yet, the guide is answering, or a
+// save is loading.
+func (m Model) isLoading() bool {
+	return (m.turnPhase != AwaitingInput && !m.isStreaming()) || m.guidePending || m.loadPending
+}
+
+// syncAnimation starts a ticker when loading begins and stops it when loading ends.
+// Stopping bumps animationID so a tick already in flight is dropped on arrival.
+func (m *Model) syncAnimation() tea.Cmd {
+	loading := m.isLoading()
+	switch {
+	case loading && !m.animating:
+		m.animating = true
+		m.animationID++
+		m.animationFrame = 0
+		return animationTimer(m.animationID)
+	case !loading && m.animating:
+		m.animating = false
+		m.animationID++
+	}
+	return nil
+}
+
+// beginStreamMessage appends the line narration streams into and remembers where it is,
+// so messages appended while streaming (e.g. NPC thoughts) are never overwritten.
+func (m *Model) beginStreamMessage() {
+	m.messages = append(m.messages, "")
+	m.streamIndex = len(m.messages) - 1
+}
+
+// setStreamMessage replaces the streaming line with text.
+func (m *Model) setStreamMessage(text string) {
+	if m.streamIndex < 0 || m.streamIndex >= len(m.messages) {
+		m.loggers.Debug.Errorf("stream message index %d out of range (%d messages)", m.streamIndex, len(m.messages))
+		m.beginStreamMessage()
+	}
+	m.messages[m.streamIndex] = text
+}
+
+// renderedMessages returns the messages View draws, with the loading row last while loading.
+func (m Model) renderedMessages() []string {
+	if !m.isLoading() {
+		return m.messages
+	}
+	rendered := make([]string, len(m.messages), len(m.messages)+1)
+	copy(rendered, m.messages)
+	return append(rendered, loadingRowSentinel)
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+
+	"textadventure/internal/game/narration"
+)
+
+func TestStreamLineSurvivesInterleavedMessages(t *testing.T) {
+	// Each step is a chunk of narration, or, prefixed with "+", a message another
+	// handler appends while the narration streams.
+	tests := []struct {
+		name  string
+		steps []string
+		want  []string
+	}{
+		{"narration only", []string{"The door", " creaks open."}, []string{"The door creaks open."}},
+		{"message before the first chunk", []string{"+Elena looks up.", "The door", " creaks open."}, []string{"The door creaks open.", "Elena looks up."}},
+		{"message between chunks", []string{"The door", "+Elena looks up.", " creaks open."}, []string{"The door creaks open.", "Elena looks up."}},
+		{"messages around every chunk", []string{"+one", "The door", "+two", " creaks", "+three", " open."}, []string{"The door creaks open.", "one", "two", "three"}},
+		{"message after the last chunk", []string{"The door", " creaks open.", "+Elena looks up."}, []string{"The door creaks open.", "Elena looks up."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.messages = []string{"> open the door"}
+			m.beginTurn(turnEventPlayerInput)
+			m.turnPhase = Narration
+			stream := &ssestream.Stream[openai.ChatCompletionChunk]{}
+			if !m.attachStream(stream, nil) {
+				t.Fatal("stream refused")
+			}
+			m.beginStreamMessage()
+
+			for _, step := range tt.steps {
+				if step[0] == '+' {
+					m.messages = append(m.messages, step[1:])
+					continue
+				}
+				updated, _ := m.Update(narration.StreamChunkMsg{Chunk: step, Stream: stream})
+				m = updated.(Model)
+			}
+
+			want := append([]string{"> open the door"}, tt.want...)
+			if !slices.Equal(m.messages, want) {
+				t.Errorf("messages = %q, want %q", m.messages, want)
+			}
+		})
+	}
+}
+
+func TestSetStreamMessageOutOfRange(t *testing.T) {
+	m := newTestModel(t)
+	m.messages = []string{"earlier"}
+	m.streamIndex = 5
+	m.setStreamMessage("The door creaks open.")
+	if !slices.Equal(m.messages, []string{"earlier", "The door creaks open."}) || m.streamIndex != 1 {
+		t.Errorf("messages = %q, stream index %d", m.messages, m.streamIndex)
+	}
+}
+
+func TestLoadingRow(t *testing.T) {
+	m := newTestModel(t)
+	m.messages = []string{"welcome"}
+	if rendered := m.renderedMessages(); slices.Contains(rendered, loadingRowSentinel) {
+		t.Error("loading row shown while awaiting input")
+	}
+
+	m.beginTurn(turnEventPlayerInput)
+	m.messages = append(m.messages, "> look", "Elena looks up.")
+	rendered := m.renderedMessages()
+	if rendered[len(rendered)-1] != loadingRowSentinel || slices.Contains(m.messages, loadingRowSentinel) {
+		t.Errorf("rendered %q, messages %q", rendered, m.messages)
+	}
+
+	// Narration taking over the pane hides the loading row; messages are untouched.
+	m.turnPhase = Narration
+	m.attachStream(&ssestream.Stream[openai.ChatCompletionChunk]{}, nil)
+	m.beginStreamMessage()
+	if rendered := m.renderedMessages(); slices.Contains(rendered, loadingRowSentinel) || len(rendered) != 4 {
+		t.Errorf("rendered while streaming = %q", rendered)
+	}
+}
+
+func TestSyncAnimation(t *testing.T) {
+	m := newTestModel(t)
+	if cmd := m.syncAnimation(); cmd != nil || m.animating {
+		t.Error("animation started while awaiting input")
+	}
+
+	m.beginTurn(turnEventPlayerInput)
+	if cmd := m.syncAnimation(); cmd == nil || !m.animating {
+		t.Fatal("animation did not start with the turn")
+	}
+	id := m.animationID
+	if cmd := m.syncAnimation(); cmd != nil || m.animationID != id {
+		t.Error("a second ticker started")
+	}
+
+	m.turnPhase = Narration
+	m.attachStream(&ssestream.Stream[openai.ChatCompletionChunk]{}, nil)
+	if m.syncAnimation(); m.animating || m.animationID == id {
+		t.Errorf("animating %v, id %d after streaming began", m.animating, m.animationID)
+	}
+
+	// A tick from the stopped ticker is dropped.
+	updated, cmd := m.Update(animationTickMsg{id: id})
+	if cmd != nil || updated.(Model).animationFrame != 0 {
+		t.Error("a stale tick advanced the animation")
+	}
+}
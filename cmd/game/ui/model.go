@@ -2,23 +2,37 @@ package ui
 
 import (
     "context"
+    "errors"
     "fmt"
     "strings"
     "time"
     
     tea "github.com/charmbracelet/bubbletea"
     "github.com/google/uuid"
+    "github.com/openai/openai-go"
+    "github.com/openai/openai-go/packages/ssestream"
     "go.opentelemetry.io/otel"
     "go.opentelemetry.io/otel/attribute"
     "go.opentelemetry.io/otel/trace"
     
+    "textadventure/internal/campaign"
+    "textadventure/internal/chaos"
     "textadventure/internal/debug"
+    "textadventure/internal/experiment"
     "textadventure/internal/game"
+    "textadventure/internal/game/actors"
     "textadventure/internal/game/director"
+    "textadventure/internal/game/echoes"
+    "textadventure/internal/game/engine"
+    "textadventure/internal/game/events"
     "textadventure/internal/game/facts"
+    "textadventure/internal/game/narration"
+    "textadventure/internal/game/perception"
+    "textadventure/internal/game/translate"
     "textadventure/internal/llm"
     "textadventure/internal/logging"
     "textadventure/internal/mcp"
+    "textadventure/internal/save"
 )
 
 type GameLoggers struct {
@@ -26,12 +40,24 @@ type GameLoggers struct {
 	Completion *logging.CompletionLogger
 }
 
+// sessionError is a failure kept for debugging. Errors are tracked here instead of in
+// gameHistory so they never end up in prompts.
+type sessionError struct {
+	At        time.Time
+	TurnIndex int
+	Phase     string
+	Message   string
+}
+
 type TurnPhase int
 
 const (
 	PlayerTurn TurnPhase = iota
 	NPCTurns
 	Narration
+	// AwaitingInput is the idle phase between turns; every other phase means a turn is
+	// in progress and the loading animation is running.
+	AwaitingInput
 )
 
 func (tp TurnPhase) String() string {
@@ -42,6 +68,8 @@ func (tp TurnPhase) String() string {
 		return "npc_turns"
 	case Narration:
 		return "narration"
+	case AwaitingInput:
+		return "awaiting_input"
 	default:
 		return "unknown"
 	}
@@ -53,23 +81,69 @@ type Model struct {
 	cursor                  int
 	width                   int
 	height                  int
-	llmService              *llm.Service
+	llmService              llm.Client
 	mcpClient               *mcp.WorldStateClient
 	loggers                 GameLoggers
 	director                *director.Director
-	loading                 bool
-	streaming               bool
+	streamIndex             int  // index in messages that narration streams into
+	// activeStream is the one narration stream feeding the pane, and streamSpan its span.
+	activeStream            *ssestream.Stream[openai.ChatCompletionChunk]
+	streamSpan              trace.Span
 	currentResponse         string
 	animationFrame          int
+	animating               bool
+	animationID             int // identifies the live ticker; ticks from older ones are dropped
 	world                   game.WorldState
 	gameHistory             *game.History
 	logger                  *logging.CompletionLogger
 	turnPhase               TurnPhase
-	npcTurnComplete         bool
-    accumulatedWorldEvents  []string
+	npcTurnInFlight         bool
+	worldUnavailable        bool // the world-state server is gone; only recovery commands run
+	worldReconnects         int  // server restarts already reported; see syncWorldReconnects
+	artifactDir             string // session directory for exports such as /export-graph
+	turnReconnectBase       int  // worldReconnects when the current turn started
+	worldStale              bool // a turn changed the server but the refresh failed; resync before planning
+	queuedInput             string // entered while a turn was in flight; submitted when it finishes
+	lastSubmitted           string    // the last input entered, to drop an accidental repeat of it
+	lastSubmittedAt         time.Time // when lastSubmitted was entered; see isDuplicateSubmit
+	sessionLimit            game.SessionLimit
+	limitStart              time.Time // when the first player turn counted toward sessionLimit
+	limitTurns              int       // player turns counted toward sessionLimit
+	sessionEnded            bool      // the session limit was reached; any key quits
+	briefing                *mcp.Briefing // shown before the intro until any key is pressed
+	scrollOffset            int  // messages the chat view is scrolled up from the bottom
+	unseenBelow             bool // new messages arrived below while scrolled up
+	classifier              *engine.Classifier
+	turnTags                engine.TagCounts
+	turnCount               int
+	npcQueue                []string       // NPCs still to act this turn, in fairness order
+	npcIdleTurns            map[string]int // turns since each NPC last acted
+	npcTurnBudget           int
+	parallelNPCTurns        bool
+	npcBatchID              int                   // identifies the parallel batch in flight; results from older ones are dropped
+	npcBatchResults         []actors.NPCActionMsg // the batch's actions so far, in queue order
+	npcBatchPending         int                   // batch NPCs still thinking
+	npcPendingActions       []actors.NPCActionMsg // finished batch actions still to apply, in queue order
+	npcPhaseStart           time.Time
+	npcParallelTurns        int // NPC turns this turn that ran in a parallel batch
+	guidePending            bool // a guide classification or answer is in flight; no turn runs
+	loadPending             bool // a /load is replacing the world; no turn runs until it lands
+	writesPending           bool // a finished turn's world writes or a snapshot are in flight; no turn runs until they land
+    accumulatedWorldEvents  []events.WorldEvent
     currentUserInput        string
+    currentInput            translate.Result
+    normalizer              *translate.Normalizer
+    narrationLanguage       string // fixed narration language; empty follows the player's
+    narrationPOV            game.POV // the person narration, hints and fact extraction assume
+    narrationVoice          narration.Voice // recent narration and the style preset, for tone continuity
+    strict                  bool   // fallbacks report errors instead of degrading (see game.WithStrict)
+    perceptionMode          perception.Mode
+    playerLanguage          string // language of the player's latest translated input
     currentActionContext    string
     currentMutationResults  []string
+    currentFailures         []string
+    currentNPCActions       []string
+    dialogueOptions         []string // follow-ups offered by this turn's authored NPC lines
     sessionID               string
     sessionStartTime        time.Time
     sessionContext          context.Context
@@ -77,16 +151,41 @@ type Model struct {
     turnID                  string
     turnIndex               int
     turnContext             context.Context
+    cancelTurn              context.CancelFunc
+    cancelSession           context.CancelFunc
     turnSpan                trace.Span
+    turnStartTime           time.Time
+    turnTimeout             time.Duration // how long a turn may run before the watchdog abandons it
+    turnSubscribers         []game.TurnSubscriber
+    worldVersion            uint64
+    contextCache            *game.ContextCache
+    situations              *actors.SituationCache // NPC situation summaries shared within a turn
+    chaos                   *chaos.Injector
+    branchedFrom            string
+    sessionErrors           []sessionError
+    npcColors               map[string]string
+    echoStore               *echoes.Store
+    factUsage               *facts.UsageStore
+    distances               *game.DistanceCache
+    visitedLocations        map[string]bool
+    npcNarrationDistance    int
+    pendingBookmark         string
+    pendingWrites           []turnWrite // memory and fact writes held for the end-of-turn flush
+    experiment              *experiment.Config // the narrator A/B experiment; nil when none runs
+    lastNarratedTurn        int                // the turn /rate rates; 0 before any narration
+    eventStore              *events.Store
+    campaign                *campaign.Campaign
+    campaignFirstTurn       int // turn index the session's campaign world was saved on
 }
 
 func NewModel(
-	llmService *llm.Service,
+	llmService llm.Client,
 	mcpClient *mcp.WorldStateClient,
 	loggers GameLoggers,
 	world game.WorldState,
 ) Model {
 	messages := []string{}
+	npcColors := assignNPCColors(world.NPCs)
 	sessionID := uuid.New().String()
 	sessionStartTime := time.Now()
 	
@@ -102,14 +201,19 @@ func NewModel(
 			attribute.String("langfuse.trace.tags", "game,session"),
 		),
 	)
+	// Cancelled by Cleanup so in-flight completions stop when the program exits
+	sessionCtx, cancelSession := context.WithCancel(sessionCtx)
 	
-	if loggers.Debug.IsEnabled() {
-		messages = append(messages, "[DEBUG] MCP integration active - world state loaded from server")
-		messages = append(messages, fmt.Sprintf("[DEBUG] Player location: %s, Inventory: %v", world.Location, world.Inventory))
-		messages = append(messages, "[DEBUG] Debug commands: /worldstate, /help")
-		messages = append(messages, fmt.Sprintf("[DEBUG] Session ID: %s", sessionID[:8]))
-		messages = append(messages, "")
+	startup := []string{
+		"[DEBUG] MCP integration active - world state loaded from server",
+		fmt.Sprintf("[DEBUG] Player location: %s, Inventory: %v", world.Location, world.Inventory),
+		"[DEBUG] Debug commands: /worldstate, /debug, /help",
+	}
+	if legend := npcLegend(npcColors); legend != "" {
+		startup = append(startup, legend)
 	}
+	startup = append(startup, fmt.Sprintf("[DEBUG] Session ID: %s", sessionID[:8]), "")
+	messages = append(messages, loggers.Debug.Log(debug.Session, startup...)...)
 	
     return Model{
 		messages:                messages,
@@ -120,10 +224,14 @@ func NewModel(
 		loggers:                 loggers,
 		director:                director.NewDirector(llmService, mcpClient, loggers.Debug),
 		world:                   world,
-		gameHistory:             game.NewHistory(6),
-		turnPhase:               PlayerTurn,
-		npcTurnComplete:         false,
-        accumulatedWorldEvents:  []string{},
+		gameHistory:             game.NewHistory(12),
+		turnPhase:               AwaitingInput,
+		npcIdleTurns:            map[string]int{},
+		turnTags:                engine.TagCounts{},
+		npcTurnBudget:           defaultNPCTurnBudget,
+		turnTimeout:             defaultTurnTimeout,
+		streamIndex:             -1,
+        accumulatedWorldEvents:  []events.WorldEvent{},
         currentUserInput:        "",
         currentActionContext:    "",
         currentMutationResults:  []string{},
@@ -134,21 +242,42 @@ func NewModel(
         turnID:                  "",
         turnIndex:               0,
         turnContext:             nil,
+        cancelSession:           cancelSession,
         turnSpan:                nil,
+        worldVersion:            1,
+        contextCache:            game.NewContextCache(),
+        situations:              actors.NewSituationCache(),
+        npcColors:               npcColors,
+        echoStore:               echoes.NewStore(),
+        factUsage:               facts.NewUsageStore(),
+        distances:               game.NewDistanceCache(),
+        visitedLocations:        map[string]bool{world.Location: true},
+        npcNarrationDistance:    defaultNPCNarrationDistance,
     }
 }
 
 
 func (m Model) Init() tea.Cmd {
-	return initialLookAroundCmd()
+	if m.briefing != nil {
+		// The intro starts once the player dismisses the briefing
+		return worldHealthTick()
+	}
+	return tea.Batch(initialLookAroundCmd(), worldHealthTick())
 }
 
-type animationTickMsg struct{}
+type animationTickMsg struct {
+	id int
+}
 
 type initialLookAroundMsg struct{}
 
+type inputNormalizedMsg struct {
+    result translate.Result
+    err    error
+}
+
 type npcTurnMsg struct{
-    worldEventLines []string
+    worldEvents     []events.WorldEvent
 }
 
 type narrationTurnMsg struct {
@@ -158,7 +287,7 @@ type narrationTurnMsg struct {
 	userInput        string
 	actionContext    string
 	mutationResults  []string
-	worldEventLines  []string
+	worldEvents      []events.WorldEvent
 }
 
 
@@ -169,9 +298,9 @@ func initialLookAroundCmd() tea.Cmd {
 	}
 }
 
-func npcTurnCmd(worldEventLines []string) tea.Cmd {
+func npcTurnCmd(worldEvents []events.WorldEvent) tea.Cmd {
     return func() tea.Msg {
-        return npcTurnMsg{worldEventLines: worldEventLines}
+        return npcTurnMsg{worldEvents: worldEvents}
     }
 }
 
@@ -206,30 +335,377 @@ func (m Model) createGameContext(ctx context.Context, operationType string) cont
 	enrichedCtx := llm.WithSessionID(ctx, m.sessionID)
 	enrichedCtx = llm.WithOperationType(enrichedCtx, operationType)
 	enrichedCtx = llm.WithGameContext(enrichedCtx, gameCtx)
+	enrichedCtx = game.WithContextCache(enrichedCtx, m.contextCache, m.worldVersion)
+	enrichedCtx = actors.WithSituationCache(enrichedCtx, m.situations)
+	enrichedCtx = echoes.WithStore(enrichedCtx, m.echoStore, m.turnIndex)
+	enrichedCtx = game.WithTurnIndex(enrichedCtx, m.turnIndex)
+	enrichedCtx = translate.WithResult(enrichedCtx, m.currentInput)
+	enrichedCtx = translate.WithLanguage(enrichedCtx, m.outputLanguage())
+	enrichedCtx = game.WithPOV(enrichedCtx, m.narrationVoice.Style.POV(m.narrationPOV))
+	enrichedCtx = game.WithStrict(enrichedCtx, m.strict)
+	enrichedCtx = perception.WithMode(enrichedCtx, m.perceptionMode)
 	
 	return enrichedCtx
 }
 
+// setWorld replaces the local world copy and bumps the world version so cached
+// world context built from the previous copy is no longer reused.
+func (m *Model) setWorld(world game.WorldState) {
+    m.world = world
+    m.npcColors = assignNPCColors(world.NPCs)
+    m.markVisited()
+    m.bumpWorldVersion()
+}
+
+// bumpWorldVersion marks the local world as changed after in-place edits (e.g. new facts).
+func (m *Model) bumpWorldVersion() {
+    m.worldVersion++
+    if m.contextCache != nil {
+        m.contextCache.Invalidate(m.worldVersion)
+    }
+}
+
+func (m *Model) recordSessionError(phase, message string) {
+    m.sessionErrors = append(m.sessionErrors, sessionError{
+        At:        time.Now(),
+        TurnIndex: m.turnIndex,
+        Phase:     phase,
+        Message:   message,
+    })
+    m.loggers.Debug.Errorf("[%s] %s", phase, message)
+}
+
+// AddTurnSubscriber registers a subscriber notified after each completed narration phase.
+func (m *Model) AddTurnSubscriber(subscriber game.TurnSubscriber) {
+    m.turnSubscribers = append(m.turnSubscribers, subscriber)
+}
+
+// SetChaosInjector exposes the active failure injector to the /chaos debug command.
+func (m *Model) SetChaosInjector(injector *chaos.Injector) {
+    m.chaos = injector
+}
+
+// SetTurnClassifier enables the LLM fallback for tagging turns the rules can't. Without
+// one, turns are tagged by rules alone.
+func (m *Model) SetTurnClassifier(classifier *engine.Classifier) {
+    m.classifier = classifier
+}
+
+// SetInputNormalizer enables translating non-English input to English before it reaches
+// the director. Without one, input is used as typed.
+func (m *Model) SetInputNormalizer(normalizer *translate.Normalizer) {
+    m.normalizer = normalizer
+}
+
+// SetArtifactDir sets the session directory files exported during play are written to.
+// Without one they go to the working directory.
+func (m *Model) SetArtifactDir(dir string) {
+    m.artifactDir = dir
+}
+
+// SetStrict turns on strict mode: fallback branches that would quietly degrade report an
+// error in red instead. Meant for development, to catch broken prompts and parsers.
+func (m *Model) SetStrict(strict bool) {
+    m.strict = strict
+}
+
+// SessionID is the ID the session's traces and logs are recorded under.
+func (m Model) SessionID() string {
+    return m.sessionID
+}
+
+// SetEventStore logs the session's world events to store, for /events and later queries.
+// The model closes it in Cleanup.
+func (m *Model) SetEventStore(store *events.Store) {
+    m.eventStore = store
+    m.director.SetEventStore(store)
+}
+
+// SetPerceptionMode forces NPC perception down the rules or the LLM path, for comparing
+// the two. The default decides obvious events by rule and asks the LLM about the rest.
+func (m *Model) SetPerceptionMode(mode perception.Mode) {
+    m.perceptionMode = mode
+}
+
+// SetNarrationLanguage fixes the language narration is written in. When unset, narration
+// follows the language the player last typed in.
+func (m *Model) SetNarrationLanguage(language string) {
+    m.narrationLanguage = language
+}
+
+// SetNarrationPOV sets the point of view narration is told from: second person by
+// default, first person, or third person following a named protagonist.
+func (m *Model) SetNarrationPOV(pov game.POV) {
+    m.narrationPOV = pov
+}
+
+// outputLanguage is the language narration should use, or "" for English.
+func (m Model) outputLanguage() string {
+    switch {
+    case strings.EqualFold(m.narrationLanguage, "english"):
+        return ""
+    case m.narrationLanguage != "":
+        return m.narrationLanguage
+    }
+    return m.playerLanguage
+}
+
+// RestoreSnapshot seeds the session from a loaded save. The session keeps its own new ID;
+// branchedFrom records where it came from on the session span and in later bookmarks.
+func (m *Model) RestoreSnapshot(history []string, branchedFrom string) {
+    m.gameHistory.Restore(history)
+    m.branchedFrom = branchedFrom
+    if branchedFrom != "" && m.sessionSpan != nil {
+        m.sessionSpan.SetAttributes(attribute.String("branched_from", branchedFrom))
+    }
+}
+
+// requestBookmark writes the bookmark now if the world is quiescent, or defers it until
+// the current turn finishes so the snapshot never captures a half-applied turn.
+func (m *Model) requestBookmark(name string) (string, tea.Cmd) {
+    if err := save.ValidateName(name); err != nil {
+        return fmt.Sprintf("Cannot bookmark: %v", err), nil
+    }
+    if m.turnPhase != AwaitingInput {
+        m.pendingBookmark = name
+        return fmt.Sprintf("Bookmark %q will be written when this turn finishes", name), nil
+    }
+    return fmt.Sprintf("Writing bookmark %q...", name), m.holdTurnsFor(m.snapshotCmd(save.BookmarkPath(name), name, "save.bookmark"))
+}
+
+// flushPendingBookmark returns a command writing a bookmark deferred by requestBookmark.
+func (m *Model) flushPendingBookmark() tea.Cmd {
+    if m.pendingBookmark == "" {
+        return nil
+    }
+    name := m.pendingBookmark
+    m.pendingBookmark = ""
+    return m.snapshotCmd(save.BookmarkPath(name), name, "save.bookmark")
+}
+
+// snapshotCmd returns a command writing the server's world and the recent history to
+// path, so the UI doesn't wait on the server or the disk. The metadata and history are
+// taken now; callers run it behind holdTurnsFor so the world it reads is still this
+// turn's. handleSnapshotWritten reports the result.
+func (m *Model) snapshotCmd(path, name, operation string) tea.Cmd {
+    write := m.snapshotWriter(path, name, operation)
+    return func() tea.Msg {
+        return snapshotWrittenMsg{operation: operation, name: name, path: path, err: write()}
+    }
+}
+
+// writeSnapshot writes a snapshot to path before returning, for when the program is
+// exiting and nothing else will run.
+func (m Model) writeSnapshot(path, name, operation string) error {
+    return m.snapshotWriter(path, name, operation)()
+}
+
+func (m Model) snapshotWriter(path, name, operation string) func() error {
+    ctx := m.createGameContext(m.sessionContext, operation)
+    client := m.mcpClient
+    metadata := save.Metadata{
+        Name:         name,
+        SessionID:    m.sessionID,
+        TurnIndex:    m.turnIndex,
+        CreatedAt:    time.Now(),
+        BranchedFrom: m.branchedFrom,
+    }
+    history := m.gameHistory.GetEntries()
+    return func() error {
+        mcpWorld, err := client.GetWorldState(ctx)
+        if err != nil {
+            return fmt.Errorf("failed to read world state: %w", err)
+        }
+        return save.Write(path, save.Snapshot{Metadata: metadata, World: mcpWorld, History: history})
+    }
+}
+
+// snapshotWrittenMsg reports a snapshot written by snapshotCmd.
+type snapshotWrittenMsg struct {
+    operation string
+    name      string
+    path      string
+    err       error
+}
+
+// handleSnapshotWritten tells whoever asked for the snapshot how it went.
+func (m Model) handleSnapshotWritten(msg snapshotWrittenMsg) (tea.Model, tea.Cmd) {
+    switch msg.operation {
+    case "save.bookmark":
+        if msg.err != nil {
+            m.messages = append(m.messages, fmt.Sprintf("[DEBUG] Bookmark failed: %v", msg.err), "")
+        } else {
+            m.messages = append(m.messages, fmt.Sprintf("[DEBUG] Bookmark saved to %s", msg.path), "")
+        }
+    case "save.game":
+        if msg.err != nil {
+            m.messages = append(m.messages, fmt.Sprintf("Save failed: %v", msg.err), "")
+        } else {
+            m.messages = append(m.messages, fmt.Sprintf("Game saved to %s (load with /load %s)", msg.path, msg.name), "")
+        }
+    case "campaign.autosave":
+        if msg.err != nil {
+            m.loggers.Debug.Errorf("Campaign autosave failed: %v", msg.err)
+        } else {
+            m.loggers.Debug.Printf("Campaign %s autosaved to %s", msg.name, msg.path)
+        }
+    case "session.end":
+        if msg.err != nil {
+            m.debugError(debug.Session, "Saving the ended session failed", msg.err)
+        } else {
+            m.messages = append(m.messages, fmt.Sprintf("The story was saved to %s.", msg.path))
+        }
+    }
+    return m, nil
+}
+
+// notifyTurnComplete tags the current turn and hands its player-facing record to all
+// subscribers. Turns the rules can't tag go to the classifier's LLM fallback first; then
+// the returned command finishes the job and subscribers hear about the turn slightly later.
+func (m *Model) notifyTurnComplete(narrationText string) tea.Cmd {
+    record := game.TurnRecord{
+        SessionID:   m.sessionID,
+        TurnID:      m.turnID,
+        TurnIndex:   m.turnIndex,
+        Location:    m.world.Location,
+        PlayerInput: m.currentUserInput,
+        Narration:   narrationText,
+        WorldEvents: events.Lines(m.accumulatedWorldEvents),
+        Mutations:   append([]string{}, m.currentMutationResults...),
+        Failures:    append([]string{}, m.currentFailures...),
+        NPCActions:  append([]string{}, m.currentNPCActions...),
+        StartedAt:   m.turnStartTime,
+        CompletedAt: time.Now(),
+    }
+    tags, useFallback := m.classifier.Rules(record)
+    if useFallback {
+        return m.classifyTurnCmd(record)
+    }
+    record.Tags = tags
+    if m.turnSpan != nil && len(tags) > 0 {
+        m.turnSpan.SetAttributes(attribute.StringSlice("turn.tags", tags))
+    }
+    m.publishTurn(record)
+    return nil
+}
+
+// publishTurn counts the turn's tags for /stats and notifies subscribers.
+func (m *Model) publishTurn(record game.TurnRecord) {
+    m.turnCount++
+    m.turnTags.Add(record.Tags)
+    for _, subscriber := range m.turnSubscribers {
+        if err := subscriber.OnTurnComplete(record); err != nil {
+            m.loggers.Debug.Errorf("Turn subscriber failed: %v", err)
+        }
+    }
+}
+
+// advancePlayerConditions applies per-turn condition decay and returns a command that
+// persists the result. The local conditions change once the server has them, in
+// handlePlayerConditionsAdvanced; without a server they change at once.
+func (m *Model) advancePlayerConditions() tea.Cmd {
+    if len(m.world.Conditions) == 0 {
+        return nil
+    }
+    remaining, expired := game.AdvanceConditions(m.world)
+    if m.mcpClient == nil {
+        m.applyPlayerConditions(remaining, expired)
+        return nil
+    }
+    // The turn's context ends with the turn, before the command runs
+    ctx := m.createGameContext(m.sessionContext, "conditions.advance")
+    client := m.mcpClient
+    return func() tea.Msg {
+        _, err := client.SyncPlayerConditions(ctx, mcp.GameToMCPConditions(remaining))
+        return playerConditionsAdvancedMsg{remaining: remaining, expired: expired, err: err}
+    }
+}
+
+// playerConditionsAdvancedMsg reports the decayed conditions advancePlayerConditions sent.
+type playerConditionsAdvancedMsg struct {
+    remaining []game.PlayerCondition
+    expired   []string
+    err       error
+}
+
+func (m Model) handlePlayerConditionsAdvanced(msg playerConditionsAdvancedMsg) (tea.Model, tea.Cmd) {
+    if msg.err != nil {
+        m.loggers.Debug.Errorf("Failed to persist player conditions: %v", msg.err)
+        return m, nil
+    }
+    (&m).applyPlayerConditions(msg.remaining, msg.expired)
+    return m, nil
+}
+
+func (m *Model) applyPlayerConditions(remaining []game.PlayerCondition, expired []string) {
+    m.world.Conditions = remaining
+    if len(expired) > 0 {
+        m.loggers.Debug.Printf("Player conditions expired: %v", expired)
+        m.bumpWorldVersion()
+    }
+}
+
+// sessionEndReason says why the session is closing, for the session span.
+func (m Model) sessionEndReason() string {
+	if m.sessionEnded {
+		return "session_limit"
+	}
+	return "normal_exit"
+}
+
 func (m Model) Cleanup() {
+	if m.cancelSession != nil {
+		m.cancelSession()
+	}
+	if m.eventStore != nil {
+		defer m.eventStore.Close()
+	}
+	if m.campaign != nil {
+		defer m.campaign.Close()
+	}
+	rows := m.llmService.Usage().Rows()
+	totals, cost := llm.Total(rows)
 	if m.sessionSpan != nil {
 		sessionDuration := time.Since(m.sessionStartTime)
 		m.sessionSpan.SetAttributes(
 			attribute.Int64("game.session_duration_seconds", int64(sessionDuration.Seconds())),
-			attribute.String("game.session_end_reason", "normal_exit"),
+			attribute.String("game.session_end_reason", m.sessionEndReason()),
+			attribute.Int("game.llm_calls", totals.Calls),
+			attribute.Int64("game.llm_input_tokens", totals.InputTokens),
+			attribute.Int64("game.llm_output_tokens", totals.OutputTokens),
+			attribute.Float64("game.llm_estimated_cost_usd", cost),
 		)
 		m.sessionSpan.End()
 	}
+	if m.logger != nil && len(rows) > 0 {
+		summary := logging.SessionSummary{
+			SessionID:        m.sessionID,
+			EndedAt:          time.Now(),
+			Calls:            totals.Calls,
+			InputTokens:      totals.InputTokens,
+			OutputTokens:     totals.OutputTokens,
+			EstimatedCostUSD: cost,
+			Breakdown:        rows,
+		}
+		if err := m.logger.LogSessionSummary(summary); err != nil {
+			m.loggers.Debug.Errorf("failed to log session usage: %v", err)
+		}
+	}
 }
 
 // startTurn initializes a new turn span and context under the session.
 func (m *Model) startTurn() {
-    // End any dangling turn span first
+    // End any dangling turn span first; the lifecycle should already have closed it
     if m.turnSpan != nil {
+        m.loggers.Debug.Errorf("turn %s still open when turn %d started", m.turnID, m.turnIndex+1)
         m.turnSpan.End()
         m.turnSpan = nil
     }
     m.turnIndex++
     m.turnID = uuid.New().String()
+    m.contextCache.Invalidate(m.worldVersion)
+    m.turnStartTime = time.Now()
+    m.turnReconnectBase = m.worldReconnects
     tracer := otel.Tracer("text-adventure-ui")
     ctx, span := tracer.Start(m.sessionContext, "game.turn",
         trace.WithAttributes(
@@ -240,6 +716,9 @@ func (m *Model) startTurn() {
             attribute.Int("inventory_count", len(m.world.Inventory)),
         ),
     )
+    // Cancelled by esc. A turn that ends normally leaves its context to the session's
+    // cancellation, since follow-up work such as turn classification still runs under it.
+    ctx, m.cancelTurn = context.WithCancel(ctx)
     m.turnContext = ctx
     m.turnSpan = span
 }
@@ -255,6 +734,44 @@ func (m *Model) endTurn(endReason string) {
         m.turnContext = nil
         m.turnID = ""
     }
+    if m.situations != nil {
+        m.situations.Clear()
+    }
+}
+
+// recordFactUsage counts which of the current location's established facts the
+// narration echoed, before this turn's new facts are accumulated, and reports the
+// counts and any never-used facts in debug mode.
+func (m *Model) recordFactUsage(narrationText string) {
+    if m.factUsage == nil || strings.TrimSpace(narrationText) == "" {
+        return
+    }
+    locationFacts := m.world.Locations[m.world.Location].Facts
+    if len(locationFacts) == 0 {
+        return
+    }
+    echoed := m.factUsage.Record(m.world.Location, locationFacts, narrationText, m.turnIndex)
+    if m.turnSpan != nil {
+        m.turnSpan.SetAttributes(
+            attribute.Int("facts.exposed", len(locationFacts)),
+            attribute.Int("facts.echoed", len(echoed)),
+        )
+    }
+    if !m.loggers.Debug.IsEnabled() {
+        return
+    }
+    lines := []string{fmt.Sprintf("[DEBUG] Facts echoed: %d/%d", len(echoed), len(locationFacts))}
+    for _, f := range echoed {
+        usage, _ := m.factUsage.Usage(m.world.Location, f)
+        lines = append(lines, fmt.Sprintf("  - %s (used %d/%d)", f, usage.Uses, usage.Exposures))
+    }
+    for _, candidate := range m.factUsage.ConsolidationCandidates(facts.UnusedFactExposures) {
+        if candidate.LocationID != m.world.Location {
+            continue
+        }
+        lines = append(lines, fmt.Sprintf("[DEBUG] Unused after %d exposures (since turn %d): %s", candidate.Exposures, candidate.FirstSeenTurn, candidate.Fact))
+    }
+    m.debugLog(debug.Facts, lines...)
 }
 
 func (m *Model) extractAndAccumulateFacts(narrationText string) {
@@ -265,72 +782,45 @@ func (m *Model) extractAndAccumulateFacts(narrationText string) {
     currentLocation := m.world.Locations[m.world.Location]
     ctx := m.createGameContext(m.sessionContext, "facts.extract")
     
-    extractedFacts, err := facts.ExtractLocationFacts(ctx, m.llmService, narrationText, m.world.Location, currentLocation.Facts)
+    extractedFacts, err := facts.ExtractLocationFacts(ctx, m.llmService, narrationText, m.world.Location, game.DisplayName(m.world, m.world.Location), currentLocation.Facts, "")
     if err != nil {
-        if m.loggers.Debug.IsEnabled() {
-            m.loggers.Debug.Errorf("Fact extraction failed: %v", err)
-            m.messages = append(m.messages, "\033[31m[ERROR] Fact extraction failed\033[0m")
+        if errors.Is(err, game.ErrStrictFallback) {
+            m.reportStrict("facts.extract", err)
+        } else {
+            m.debugError(debug.Facts, "Fact extraction failed", err)
         }
         return
     }
     
     if len(extractedFacts) > 0 {
-        if m.loggers.Debug.IsEnabled() {
-            header := "[DEBUG] Facts extracted:"
-            m.loggers.Debug.Printf(header)
-            m.messages = append(m.messages, header)
-            for _, f := range extractedFacts {
-                line := "  - " + strings.TrimSpace(f)
-                m.loggers.Debug.Printf(line)
-                m.messages = append(m.messages, line)
-            }
-        }
+        m.debugLog(debug.Facts, extractedFactLines("[DEBUG] Facts extracted:", extractedFacts)...)
         
         attribution, err := facts.AttributeFacts(ctx, m.llmService, extractedFacts, &m.world)
         if err != nil {
-            if m.loggers.Debug.IsEnabled() {
-                m.loggers.Debug.Errorf("Fact attribution failed: %v", err)
-                m.messages = append(m.messages, "\033[31m[ERROR] Fact attribution failed\033[0m")
+            m.debugError(debug.Facts, "Fact attribution failed", err)
+            // Fallback: unattributed facts all go to the current location
+            if serr := game.StrictFallback(ctx, "facts attribute", err); serr != nil {
+                m.reportStrict("facts.attribute", serr)
+                return
             }
+            m.world = m.world.Clone()
             m.world.AccumulateLocationFacts(m.world.Location, extractedFacts)
+            m.bumpWorldVersion()
             return
         }
         
         m.persistAttributedFacts(attribution)
         
-        if m.loggers.Debug.IsEnabled() {
-            // Show attribution results
-            for locationID, facts := range attribution.LocationFacts {
-                debugMsg := fmt.Sprintf("[DEBUG] Location %s: %v", locationID, facts)
-                m.loggers.Debug.Printf(debugMsg)
-                m.messages = append(m.messages, debugMsg)
-            }
-            for itemID, facts := range attribution.ItemFacts {
-                debugMsg := fmt.Sprintf("[DEBUG] Item %s: %v", itemID, facts)
-                m.loggers.Debug.Printf(debugMsg)
-                m.messages = append(m.messages, debugMsg)
-            }
-            for npcID, facts := range attribution.NPCFacts {
-                debugMsg := fmt.Sprintf("[DEBUG] NPC %s: %v", npcID, facts)
-                m.loggers.Debug.Printf(debugMsg)
-                m.messages = append(m.messages, debugMsg)
-            }
-            if len(attribution.Skipped) > 0 {
-                debugMsg := fmt.Sprintf("[DEBUG] Skipped: %v", attribution.Skipped)
-                m.loggers.Debug.Printf(debugMsg)
-                m.messages = append(m.messages, debugMsg)
-            }
-        }
-    } else if m.loggers.Debug.IsEnabled() {
-        debugMsg := "[DEBUG] Facts extracted: []"
-        m.loggers.Debug.Printf(debugMsg)
-        m.messages = append(m.messages, debugMsg)
+        m.debugLog(debug.Facts, attributionLines(attribution)...)
+    } else {
+        m.debugLog(debug.Facts, "[DEBUG] Facts extracted: []")
     }
 }
 
 // extractAndAccumulateFactsForLocation runs fact extraction/attribution for a specific location
-// (used to attribute NPC-perspective narration to the NPC's current room).
-func (m *Model) extractAndAccumulateFactsForLocation(locationID string, narrationText string) {
+// (used to attribute NPC-perspective narration to the NPC's current room). observerNPCID keeps
+// the observing NPC's own feelings and actions out of the extracted location facts.
+func (m *Model) extractAndAccumulateFactsForLocation(observerNPCID string, locationID string, narrationText string) {
     if strings.TrimSpace(narrationText) == "" {
         return
     }
@@ -339,147 +829,92 @@ func (m *Model) extractAndAccumulateFactsForLocation(locationID string, narratio
         return
     }
     ctx := m.createGameContext(m.sessionContext, "facts.extract")
-    extractedFacts, err := facts.ExtractLocationFacts(ctx, m.llmService, narrationText, locationID, loc.Facts)
+    extractedFacts, err := facts.ExtractLocationFacts(ctx, m.llmService, narrationText, locationID, game.DisplayName(m.world, locationID), loc.Facts, observerNPCID)
     if err != nil {
-        if m.loggers.Debug.IsEnabled() {
-            m.loggers.Debug.Errorf("Fact extraction failed (%s): %v", locationID, err)
-            m.messages = append(m.messages, fmt.Sprintf("\033[31m[ERROR] Fact extraction failed for %s\033[0m", locationID))
+        if errors.Is(err, game.ErrStrictFallback) {
+            m.reportStrict("facts.extract", err)
+        } else {
+            m.debugError(debug.Facts, "Fact extraction failed for "+locationID, err)
         }
         return
     }
     if len(extractedFacts) == 0 {
-        if m.loggers.Debug.IsEnabled() {
-            header := fmt.Sprintf("[DEBUG] Facts extracted for %s:", locationID)
-            m.loggers.Debug.Printf(header)
-            m.messages = append(m.messages, header)
-            m.loggers.Debug.Printf("  - (none)")
-            m.messages = append(m.messages, "  - (none)")
-        }
+        m.debugLog(debug.Facts, fmt.Sprintf("[DEBUG] Facts extracted for %s:", locationID), "  - (none)")
         return
     }
-    if m.loggers.Debug.IsEnabled() {
-        header := fmt.Sprintf("[DEBUG] Facts extracted for %s:", locationID)
-        m.loggers.Debug.Printf(header)
-        m.messages = append(m.messages, header)
-        for _, f := range extractedFacts {
-            line := "  - " + strings.TrimSpace(f)
-            m.loggers.Debug.Printf(line)
-            m.messages = append(m.messages, line)
-        }
-    }
+    m.debugLog(debug.Facts, extractedFactLines(fmt.Sprintf("[DEBUG] Facts extracted for %s:", locationID), extractedFacts)...)
     attribution, err := facts.AttributeFacts(ctx, m.llmService, extractedFacts, &m.world)
     if err != nil {
-        if m.loggers.Debug.IsEnabled() {
-            m.loggers.Debug.Errorf("Fact attribution failed (%s): %v", locationID, err)
-            m.messages = append(m.messages, fmt.Sprintf("\033[31m[ERROR] Fact attribution failed for %s\033[0m", locationID))
+        m.debugError(debug.Facts, "Fact attribution failed for "+locationID, err)
+        // Fallback: unattributed facts all go to the NPC's room
+        if serr := game.StrictFallback(ctx, "facts attribute", err); serr != nil {
+            m.reportStrict("facts.attribute", serr)
+            return
         }
+        m.world = m.world.Clone()
         m.world.AccumulateLocationFacts(locationID, extractedFacts)
+        m.bumpWorldVersion()
         return
     }
     m.persistAttributedFactsForLocation(attribution, locationID)
-    if m.loggers.Debug.IsEnabled() {
-        for lID, f := range attribution.LocationFacts {
-            debugMsg := fmt.Sprintf("[DEBUG] Location %s: %v", lID, f)
-            m.loggers.Debug.Printf(debugMsg)
-            m.messages = append(m.messages, debugMsg)
-        }
-        for itemID, f := range attribution.ItemFacts {
-            debugMsg := fmt.Sprintf("[DEBUG] Item %s: %v", itemID, f)
-            m.loggers.Debug.Printf(debugMsg)
-            m.messages = append(m.messages, debugMsg)
-        }
-        for npcID, f := range attribution.NPCFacts {
-            debugMsg := fmt.Sprintf("[DEBUG] NPC %s: %v", npcID, f)
-            m.loggers.Debug.Printf(debugMsg)
-            m.messages = append(m.messages, debugMsg)
-        }
-        if len(attribution.Skipped) > 0 {
-            debugMsg := fmt.Sprintf("[DEBUG] Skipped: %v", attribution.Skipped)
-            m.loggers.Debug.Printf(debugMsg)
-            m.messages = append(m.messages, debugMsg)
-        }
+    m.debugLog(debug.Facts, attributionLines(attribution)...)
+}
+
+// extractedFactLines lists extracted facts under a header for the debug output.
+func extractedFactLines(header string, extracted []string) []string {
+    lines := []string{header}
+    for _, f := range extracted {
+        lines = append(lines, "  - "+strings.TrimSpace(f))
     }
+    return lines
 }
 
-func (m *Model) persistAttributedFacts(attribution *facts.FactAttribution) {
-    m.persistAttributedFactsForLocation(attribution, m.world.Location)
+// attributionLines shows where attributed facts went for the debug output.
+func attributionLines(attribution *facts.FactAttribution) []string {
+    var lines []string
+    for locationID, f := range attribution.LocationFacts {
+        lines = append(lines, fmt.Sprintf("[DEBUG] Location %s: %v", locationID, f))
+    }
+    for itemID, f := range attribution.ItemFacts {
+        lines = append(lines, fmt.Sprintf("[DEBUG] Item %s: %v", itemID, f))
+    }
+    for npcID, f := range attribution.NPCFacts {
+        lines = append(lines, fmt.Sprintf("[DEBUG] NPC %s: %v", npcID, f))
+    }
+    if len(attribution.Skipped) > 0 {
+        lines = append(lines, fmt.Sprintf("[DEBUG] Skipped: %v", attribution.Skipped))
+    }
+    return lines
 }
 
-// persistAttributedFactsForLocation persists attributed facts, scoping item creation to the observer's location.
-func (m *Model) persistAttributedFactsForLocation(attribution *facts.FactAttribution, observerLocationID string) {
-    ctx := m.createGameContext(m.sessionContext, "facts.persist")
-    
-    // Persist location facts
-    for locationID, locationFacts := range attribution.LocationFacts {
-        if len(locationFacts) > 0 {
-            result, err := m.mcpClient.CallTool(ctx, "add_location_facts", map[string]interface{}{
-                "location_id": locationID,
-                "new_facts":   locationFacts,
-            })
-            if err != nil && m.loggers.Debug.IsEnabled() {
-                m.loggers.Debug.Errorf("Failed to persist location facts for %s: %v", locationID, err)
-                m.messages = append(m.messages, fmt.Sprintf("\033[31m[ERROR] Persist location facts failed for %s\033[0m", locationID))
-            } else if m.loggers.Debug.IsEnabled() {
-                m.loggers.Debug.Printf("Persisted location facts for %s: %s", locationID, result)
-            }
-            
-            // Update local world state
-            if loc, exists := m.world.Locations[locationID]; exists {
-                m.world.Locations[locationID] = game.LocationInfo{
-                    Name:  loc.Name,
-                    Facts: append(loc.Facts, locationFacts...),
-                    Exits: loc.Exits,
-                }
-            }
+// resolveNPCLocation decides where results computed for an NPC should be applied.
+// Commands capture the world by value when dispatched; if the world has been replaced
+// since (capturedVersion is stale) the NPC may have moved, so the location is looked up
+// again in the current world. It returns false when the NPC no longer exists.
+func (m *Model) resolveNPCLocation(npcID, capturedLocation string, capturedVersion uint64) (string, bool) {
+    npc, exists := m.world.NPCs[npcID]
+    if capturedVersion == m.worldVersion {
+        if capturedLocation == "" && exists {
+            return npc.Location, true
         }
+        return capturedLocation, capturedLocation != ""
     }
-    
-    // Create items and persist item facts (assigning to observer's current location)
-    for itemID, itemFacts := range attribution.ItemFacts {
-        if len(itemFacts) > 0 {
-            result, err := m.mcpClient.CallTool(ctx, "create_item", map[string]interface{}{
-                "item_id":       itemID,
-                "name":          itemID, // Use item_id as name for now
-                "location":      observerLocationID,
-                "initial_facts": itemFacts,
-            })
-            if err != nil && m.loggers.Debug.IsEnabled() {
-                // Item might already exist, try adding facts instead
-                result, err = m.mcpClient.CallTool(ctx, "add_item_facts", map[string]interface{}{
-                    "item_id":   itemID,
-                    "new_facts": itemFacts,
-                })
-                if err != nil && m.loggers.Debug.IsEnabled() {
-                    m.loggers.Debug.Errorf("Failed to persist item facts for %s: %v", itemID, err)
-                    m.messages = append(m.messages, fmt.Sprintf("\033[31m[ERROR] Persist item facts failed for %s\033[0m", itemID))
-                } else if m.loggers.Debug.IsEnabled() {
-                    m.loggers.Debug.Printf("Added facts to existing item %s: %s", itemID, result)
-                }
-            } else if m.loggers.Debug.IsEnabled() {
-                m.loggers.Debug.Printf("Created item %s: %s", itemID, result)
-            }
-        }
+    if !exists {
+        m.loggers.Debug.Printf("Dropping stale result for %s: NPC no longer in world (captured v%d, now v%d)", npcID, capturedVersion, m.worldVersion)
+        return "", false
     }
-    
-    // Persist NPC facts
-    for npcID, npcFacts := range attribution.NPCFacts {
-        if len(npcFacts) > 0 {
-            result, err := m.mcpClient.CallTool(ctx, "add_npc_facts", map[string]interface{}{
-                "npc_id":    npcID,
-                "new_facts": npcFacts,
-            })
-            if err != nil && m.loggers.Debug.IsEnabled() {
-                m.loggers.Debug.Errorf("Failed to persist NPC facts for %s: %v", npcID, err)
-                m.messages = append(m.messages, fmt.Sprintf("\033[31m[ERROR] Persist NPC facts failed for %s\033[0m", npcID))
-            } else if m.loggers.Debug.IsEnabled() {
-                m.loggers.Debug.Printf("Persisted NPC facts for %s: %s", npcID, result)
-            }
-            
-            // Update local world state
-            if npc, exists := m.world.NPCs[npcID]; exists {
-                npc.Facts = append(npc.Facts, npcFacts...)
-                m.world.NPCs[npcID] = npc
-            }
-        }
+    if npc.Location != capturedLocation {
+        m.loggers.Debug.Printf("Stale result for %s: re-resolved location %s -> %s (captured v%d, now v%d)", npcID, capturedLocation, npc.Location, capturedVersion, m.worldVersion)
     }
+    return npc.Location, true
+}
+
+func (m *Model) persistAttributedFacts(attribution *facts.FactAttribution) {
+    m.persistAttributedFactsForLocation(attribution, m.world.Location)
+}
+
+// persistAttributedFactsForLocation queues attributed facts for the end-of-turn flush, scoping item creation to the observer's location.
+// Local state is updated at the flush whether or not the server can store the facts.
+func (m *Model) persistAttributedFactsForLocation(attribution *facts.FactAttribution, observerLocationID string) {
+    m.queueFactWrites(attribution, observerLocationID)
 }
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+)
+
+// newTestModel returns a model awaiting input with no LLM or world server behind it.
+// Commands it returns that would reach either must not be run.
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	loggers := GameLoggers{Debug: debug.NewLogger(debug.Off, filepath.Join(t.TempDir(), "debug.log"))}
+	m := NewModel(nil, nil, loggers, game.NewDefaultWorldState())
+	t.Cleanup(m.cancelSession)
+	return m
+}
+
+func enter(t *testing.T, m Model, input string) Model {
+	t.Helper()
+	m.input = input
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model)
+}
+
+// run runs cmd as the program would, feeding every message it produces, including those
+// of batched and sequenced commands, back through Update in order. Commands Update
+// returns are not run.
+func run(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	msg := cmd()
+	if cmds, ok := msg.(tea.BatchMsg); ok {
+		for _, cmd := range cmds {
+			m = run(t, m, cmd)
+		}
+		return m
+	}
+	// tea.Sequence's message is an unexported []tea.Cmd
+	if value := reflect.ValueOf(msg); value.Kind() == reflect.Slice && value.Type().Elem() == reflect.TypeOf(tea.Cmd(nil)) {
+		for i := range value.Len() {
+			m = run(t, m, value.Index(i).Interface().(tea.Cmd))
+		}
+		return m
+	}
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game/narration"
+)
+
+// SetNarrationStyle sets the style the narrator tells turns in: a preset from
+// narration.StylePresets or one loaded from a file. A style with a perspective
+// overrides the narration POV while it is set.
+func (m *Model) SetNarrationStyle(style narration.Style) {
+	m.narrationVoice.Style = style
+}
+
+// runStyleCommand switches the narration style mid-session, so the same world can be
+// narrated in different styles side by side. The voice's recent paragraphs are kept, so
+// the first turn after a switch still sees the old style's examples.
+func runStyleCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if len(args) == 0 {
+		return append(m.styleLines(m.narrationVoice.Style), "Presets: "+strings.Join(narration.StylePresetNames(), ", ")+" (or default, or a .json style file)"), nil
+	}
+	var style narration.Style
+	if !strings.EqualFold(args[0], "default") {
+		var err error
+		if style, err = narration.ParseStyle(args[0]); err != nil {
+			return []string{err.Error()}, nil
+		}
+	}
+	m.SetNarrationStyle(style)
+	m.loggers.Debug.Printf("Narration style switched to %s", style.Label())
+	return m.styleLines(style), nil
+}
+
+// styleLines describes a style for /style.
+func (m Model) styleLines(style narration.Style) []string {
+	lines := []string{
+		fmt.Sprintf("Narration style: %s", style.Label()),
+		fmt.Sprintf("  %s; point of view: %s", style.LengthRule(), style.POV(m.narrationPOV)),
+	}
+	if style.Tone != "" {
+		lines = append(lines, "  Tone: "+style.Tone)
+	}
+	return lines
+}
+
+// failedTurn reports whether the turn so far is only failures, so its narration is the
+// failure variant: a short beat about why nothing happened.
+func (m Model) failedTurn() bool {
+	return len(m.currentFailures) > 0 && len(m.currentMutationResults) == 0
+}
+
+// withNarrationVoice attaches the established voice to a narration context, except on
+// failed turns, whose short beats shouldn't be told like a scene.
+func (m Model) withNarrationVoice(ctx context.Context) context.Context {
+	if m.failedTurn() {
+		return ctx
+	}
+	return narration.WithVoice(ctx, m.narrationVoice)
+}
+
+// recordNarrationVoice keeps a completed narration as an example of the voice. Failure
+// beats are left out for the same reason they don't get the voice.
+func (m *Model) recordNarrationVoice(text string) {
+	if m.failedTurn() {
+		return
+	}
+	m.narrationVoice.Record(text)
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "style",
+		Args:      []commandArg{{Name: "preset", Optional: true}},
+		DebugOnly: true,
+		Summary:   "Show or switch the narration style",
+		Run:       runStyleCommand,
+	})
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/experiment"
+	"textadventure/internal/logging"
+)
+
+// SetNarratorExperiment narrates every turn under one of the experiment's two
+// variants; nil narrates as configured.
+func (m *Model) SetNarratorExperiment(config *experiment.Config) {
+	m.experiment = config
+}
+
+// withExperiment attaches the turn's variant to a narration context. The variant
+// follows from the turn index, so narration started again within a turn keeps it.
+func (m *Model) withExperiment(ctx context.Context) context.Context {
+	if m.experiment == nil {
+		return ctx
+	}
+	assignment := m.experiment.Assign(m.turnIndex)
+	if m.turnSpan != nil {
+		m.turnSpan.SetAttributes(
+			attribute.String("experiment.name", assignment.Experiment),
+			attribute.String("experiment.variant", assignment.Variant),
+		)
+	}
+	m.debugLog(debug.Narration, fmt.Sprintf("[DEBUG] Experiment %s: turn %d narrated by variant %s", assignment.Experiment, m.turnIndex, assignment.Variant))
+	return experiment.WithAssignment(ctx, assignment)
+}
+
+func runRateCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	var rating int
+	switch strings.ToLower(args[0]) {
+	case "up", "+":
+		rating = 1
+	case "down", "-":
+		rating = -1
+	default:
+		return []string{"Usage: /rate up|down"}, nil
+	}
+	if m.lastNarratedTurn == 0 {
+		return []string{"Nothing has been narrated yet"}, nil
+	}
+	if m.loggers.Completion == nil {
+		return []string{"Ratings aren't recorded in this session"}, nil
+	}
+	record := logging.NarrationRating{
+		SessionID: m.sessionID,
+		TurnIndex: m.lastNarratedTurn,
+		Rating:    rating,
+		RatedAt:   time.Now(),
+	}
+	if m.experiment != nil {
+		assignment := m.experiment.Assign(m.lastNarratedTurn)
+		record.Experiment, record.Variant = assignment.Experiment, assignment.Variant
+	}
+	if err := m.loggers.Completion.LogRating(record); err != nil {
+		return []string{fmt.Sprintf("Failed to save rating: %v", err)}, nil
+	}
+	verdict := "up"
+	if rating < 0 {
+		verdict = "down"
+	}
+	return []string{fmt.Sprintf("Rated the narration of turn %d thumbs %s", m.lastNarratedTurn, verdict)}, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:    "rate",
+		Aliases: []string{"thumbs"},
+		Args:    []commandArg{{Name: "up|down"}},
+		Summary: "Rate the last narration; rating a turn again replaces the earlier rating",
+		Run:     runRateCommand,
+	})
+}
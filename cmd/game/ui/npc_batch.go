@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/actors"
+	"textadventure/internal/game/events"
+)
+
+// npcBatchResultMsg is one NPC's finished turn from a parallel batch.
+type npcBatchResultMsg struct {
+	batch  int
+	index  int
+	action actors.NPCActionMsg
+}
+
+// npcTurnBatchCompleteMsg carries the actions of every NPC in a parallel batch, in
+// queue order.
+type npcTurnBatchCompleteMsg struct {
+	actions []actors.NPCActionMsg
+}
+
+// SetParallelNPCTurns lets NPCs far enough apart that they can't affect each other
+// perceive, think and act at the same time. Their actions are still applied one by one.
+func (m *Model) SetParallelNPCTurns(parallel bool) {
+	m.parallelNPCTurns = parallel
+}
+
+// startNPCBatch runs the first n queued NPCs' turns concurrently. Each reports back with
+// an npcBatchResultMsg; the last one in completes the batch.
+func (m *Model) startNPCBatch(n int, worldEvents []events.WorldEvent) tea.Cmd {
+	batch := append([]string(nil), m.npcQueue[:n]...)
+	m.npcQueue = m.npcQueue[n:]
+	m.npcTurnInFlight = true
+	m.npcBatchID++
+	m.npcBatchResults = make([]actors.NPCActionMsg, n)
+	m.npcBatchPending = n
+	m.npcParallelTurns += n
+
+	cmds := make([]tea.Cmd, n)
+	for i, npcID := range batch {
+		npcCtx := m.createGameContext(m.turnContext, "npc.turn")
+		turn := m.stampNPCTurn(npcID, actors.GenerateNPCTurn(npcCtx, m.llmService, npcID, m.world, m.gameHistory.For(game.HistoryForNPC, npcID), m.loggers.Debug.IsEnabled(), worldEvents))
+		batchID, index := m.npcBatchID, i
+		cmds[i] = func() tea.Msg {
+			action, ok := turn().(actors.NPCActionMsg)
+			if !ok {
+				action = actors.NPCActionMsg{NPCID: npcID}
+			}
+			return npcBatchResultMsg{batch: batchID, index: index, action: action}
+		}
+	}
+	m.debugLog(debug.Turns, fmt.Sprintf("[DEBUG] NPC turns in parallel: %s", strings.Join(batch, ", ")), "")
+	return tea.Batch(cmds...)
+}
+
+func (m Model) handleNPCBatchResult(msg npcBatchResultMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase != NPCTurns || msg.batch != m.npcBatchID || m.npcBatchPending == 0 {
+		// From a batch the turn already let go of, e.g. after a cancel
+		return m, nil
+	}
+	m.npcBatchResults[msg.index] = msg.action
+	m.npcBatchPending--
+	if m.npcBatchPending > 0 {
+		return m, nil
+	}
+	actions := m.npcBatchResults
+	m.npcBatchResults = nil
+	return m, func() tea.Msg {
+		return npcTurnBatchCompleteMsg{actions: actions}
+	}
+}
+
+// handleNPCTurnBatchComplete queues a batch's actions to be applied in queue order. An
+// NPC whose action mentions an item an earlier NPC in the batch also went for goes back
+// to the front of the queue instead, to act again once it can perceive what happened.
+func (m Model) handleNPCTurnBatchComplete(msg npcTurnBatchCompleteMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase != NPCTurns {
+		return m, nil
+	}
+	claimed := map[string]string{}
+	var retry []string
+	for _, action := range msg.actions {
+		items := actors.ActionItems(m.world, action.Action)
+		contended := ""
+		for _, item := range items {
+			if other, ok := claimed[item]; ok {
+				contended = fmt.Sprintf("%s (also wanted by %s)", item, other)
+				break
+			}
+		}
+		if contended != "" {
+			retry = append(retry, action.NPCID)
+			m.loggers.Debug.Printf("NPC %s contends for %s; taking its turn again after the batch", action.NPCID, contended)
+			continue
+		}
+		for _, item := range items {
+			claimed[item] = action.NPCID
+		}
+		m.npcPendingActions = append(m.npcPendingActions, action)
+	}
+	m.npcParallelTurns -= len(retry)
+	m.npcQueue = append(retry, m.npcQueue...)
+	return m, (&m).nextNPCTurnCmd()
+}
+
+// endNPCPhase records how long the NPC phase took on the turn span and hands over to
+// narration.
+func (m *Model) endNPCPhase() tea.Cmd {
+	if m.turnSpan != nil && !m.npcPhaseStart.IsZero() {
+		m.turnSpan.SetAttributes(
+			attribute.Int64("npc.phase_wall_ms", time.Since(m.npcPhaseStart).Milliseconds()),
+			attribute.Int("npc.parallel_turns", m.npcParallelTurns),
+		)
+	}
+	m.npcPhaseStart = time.Time{}
+	return m.narrationTurnCmd()
+}
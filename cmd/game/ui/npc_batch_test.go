@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/actors"
+)
+
+// npcBatchModel is in the NPC phase with Ada, Elena and Marcus queued ahead of Bram,
+// and a brass lamp in the foyer.
+func npcBatchModel(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel(t)
+	m.world.NPCs["ada"] = game.NPCInfo{Location: "study"}
+	m.world.NPCs["marcus"] = game.NPCInfo{Location: "kitchen"}
+	m.world.NPCs["bram"] = game.NPCInfo{Location: "foyer"}
+	m.world.Items = map[string]game.ItemInfo{"lamp": {Name: "brass lamp", Location: "foyer"}}
+	m.beginTurn(turnEventPlayerInput)
+	m.advanceTurn(turnEventPlayerResolved)
+	m.npcQueue = []string{"ada", "elena", "marcus", "bram"}
+	return m
+}
+
+func npcIDs(actions []actors.NPCActionMsg) []string {
+	var ids []string
+	for _, action := range actions {
+		ids = append(ids, action.NPCID)
+	}
+	return ids
+}
+
+func TestNPCBatchAppliesActionsInQueueOrder(t *testing.T) {
+	m := npcBatchModel(t)
+	m.startNPCBatch(3, nil)
+
+	// The NPCs finish in a different order than they were queued.
+	batch := []string{"ada", "elena", "marcus"}
+	var next tea.Cmd
+	for _, index := range []int{2, 0, 1} {
+		updated, cmd := m.handleNPCBatchResult(npcBatchResultMsg{batch: m.npcBatchID, index: index, action: actors.NPCActionMsg{NPCID: batch[index]}})
+		m, next = updated.(Model), cmd
+	}
+	if next == nil {
+		t.Fatal("the batch did not complete")
+	}
+	complete, ok := next().(npcTurnBatchCompleteMsg)
+	if !ok || !slices.Equal(npcIDs(complete.actions), batch) {
+		t.Fatalf("batch completed with %+v", complete.actions)
+	}
+
+	updated, next := m.handleNPCTurnBatchComplete(complete)
+	m = updated.(Model)
+	first, ok := next().(actors.NPCActionMsg)
+	if !ok || first.NPCID != "ada" {
+		t.Fatalf("first applied action = %+v", first)
+	}
+	if got := npcIDs(m.npcPendingActions); !slices.Equal(got, []string{"elena", "marcus"}) {
+		t.Errorf("pending actions = %q", got)
+	}
+	if !slices.Equal(m.npcQueue, []string{"bram"}) {
+		t.Errorf("queue = %q", m.npcQueue)
+	}
+}
+
+func TestNPCBatchRetriesContendedItems(t *testing.T) {
+	m := npcBatchModel(t)
+	m.startNPCBatch(3, nil)
+	updated, next := m.handleNPCTurnBatchComplete(npcTurnBatchCompleteMsg{actions: []actors.NPCActionMsg{
+		{NPCID: "ada", Action: "picks up the brass lamp"},
+		{NPCID: "elena", Action: "reads quietly"},
+		{NPCID: "marcus", Action: "reaches for the lamp"},
+	}})
+	m = updated.(Model)
+
+	if first := next().(actors.NPCActionMsg); first.NPCID != "ada" {
+		t.Errorf("first applied action is %s's", first.NPCID)
+	}
+	if got := npcIDs(m.npcPendingActions); !slices.Equal(got, []string{"elena"}) {
+		t.Errorf("pending actions = %q", got)
+	}
+	// Marcus acts again before Bram, once he can see what Ada did.
+	if !slices.Equal(m.npcQueue, []string{"marcus", "bram"}) {
+		t.Errorf("queue = %q", m.npcQueue)
+	}
+	if m.npcParallelTurns != 2 {
+		t.Errorf("parallel turns = %d", m.npcParallelTurns)
+	}
+}
+
+func TestNPCBatchDropsResultsAfterCancel(t *testing.T) {
+	m := npcBatchModel(t)
+	m.startNPCBatch(2, nil)
+	stale := m.npcBatchID
+	m.abandonTurn(turnEventCancelled, "cancelled")
+
+	// Arriving while the player has the input back.
+	updated, cmd := m.handleNPCBatchResult(npcBatchResultMsg{batch: stale, index: 0, action: actors.NPCActionMsg{NPCID: "ada"}})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("a result from the cancelled batch was acted on")
+	}
+
+	// Arriving during the next turn's batch.
+	m.beginTurn(turnEventPlayerInput)
+	m.advanceTurn(turnEventPlayerResolved)
+	m.npcQueue = []string{"ada", "marcus"}
+	m.startNPCBatch(2, nil)
+	for index := range 2 {
+		updated, cmd = m.handleNPCBatchResult(npcBatchResultMsg{batch: stale, index: index, action: actors.NPCActionMsg{NPCID: "stale"}})
+		m = updated.(Model)
+	}
+	if cmd != nil || m.npcBatchPending != 2 || slices.Contains(npcIDs(m.npcBatchResults), "stale") {
+		t.Errorf("stale results landed in the new batch: pending %d, results %q", m.npcBatchPending, npcIDs(m.npcBatchResults))
+	}
+}
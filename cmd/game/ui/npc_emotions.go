@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/actors"
+)
+
+// applyEmotionShifts applies how what an NPC perceived moved its emotions to the local
+// world, showing each change and the perception behind it in debug mode. The returned
+// command persists the shifts on the server.
+func (m *Model) applyEmotionShifts(npcID string, shifts []actors.EmotionShift) tea.Cmd {
+	npc, ok := m.world.NPCs[npcID]
+	if len(shifts) == 0 || !ok {
+		return nil
+	}
+	before := npc.Emotions
+	world := m.world.Clone()
+	npc.Emotions = actors.ApplyEmotionShifts(before, shifts)
+	world.NPCs[npcID] = npc
+	m.setWorld(world)
+
+	lines := make([]string, 0, len(shifts)+1)
+	for _, shift := range shifts {
+		lines = append(lines, colorize(colorYellow, fmt.Sprintf("[%s EMOTION] %s %.2f → %.2f (perceived: %q)",
+			strings.ToUpper(npcID), shift.Emotion, before[shift.Emotion], npc.Emotions[shift.Emotion], shift.Cause)))
+	}
+	m.debugLog(debug.NPCState, append(lines, "")...)
+
+	if m.mcpClient == nil {
+		return nil
+	}
+	ctx := m.createGameContext(m.turnContext, "npc.emotions")
+	client := m.mcpClient
+	debugLogger := m.loggers.Debug
+	return func() tea.Msg {
+		for _, shift := range shifts {
+			if _, err := client.AdjustNPCEmotion(ctx, npcID, shift.Emotion, shift.Delta, shift.Cause); err != nil {
+				debugLogger.Printf("Failed to persist %s's %s: %v", npcID, shift.Emotion, err)
+			}
+		}
+		return nil
+	}
+}
+
+// decayNPCEmotions lets every NPC's emotions fade by a turn before the NPCs act, so a
+// fright wears off unless something renews it.
+func (m *Model) decayNPCEmotions() {
+	if m.mcpClient == nil {
+		return
+	}
+	ctx := m.createGameContext(m.turnContext, "npc.emotions.decay")
+	var world game.WorldState
+	for _, npcID := range sortedNPCIDs(m.world) {
+		npc := m.world.NPCs[npcID]
+		decayed, changed := game.DecayEmotions(npc.Emotions)
+		if !changed {
+			continue
+		}
+		if _, err := m.mcpClient.SyncNPCEmotions(ctx, npcID, decayed); err != nil {
+			m.loggers.Debug.Errorf("Failed to persist %s's emotions: %v", npcID, err)
+			continue
+		}
+		if world.NPCs == nil {
+			world = m.world.Clone()
+		}
+		npc.Emotions = maps.Clone(decayed)
+		world.NPCs[npcID] = npc
+	}
+	if world.NPCs != nil {
+		m.setWorld(world)
+	}
+}
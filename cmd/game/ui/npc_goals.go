@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/game/actors"
+)
+
+// npcGoalReviewEvery is how many turns pass between reviews of what each NPC is working
+// toward.
+const npcGoalReviewEvery = 5
+
+// npcGoalsReviewedMsg carries each NPC's goals after a review that changed them.
+type npcGoalsReviewedMsg struct {
+	goals map[string][]string
+}
+
+// npcGoalReviewCmd reviews every NPC's goals against its recent memory every
+// npcGoalReviewEvery turns, applying the changes on the server. NPCs with nothing in
+// recent memory are skipped, as there is nothing to review against.
+func (m Model) npcGoalReviewCmd() tea.Cmd {
+	if m.llmService == nil || m.mcpClient == nil || m.turnIndex == 0 || m.turnIndex%npcGoalReviewEvery != 0 {
+		return nil
+	}
+	ctx := m.createGameContext(m.sessionContext, "npc.goal_review")
+	llmService := m.llmService
+	client := m.mcpClient
+	debugLogger := m.loggers.Debug
+	world := m.world
+	return func() tea.Msg {
+		msg := npcGoalsReviewedMsg{goals: map[string][]string{}}
+		for _, npcID := range sortedNPCIDs(world) {
+			npc := world.NPCs[npcID]
+			if len(npc.RecentThoughts) == 0 && len(npc.RecentActions) == 0 {
+				continue
+			}
+			review, err := actors.ReviewGoals(ctx, llmService, npcID, world)
+			if err != nil {
+				debugLogger.Printf("Goal review failed for %s: %v", npcID, err)
+				continue
+			}
+			if review.Empty() {
+				continue
+			}
+			goals := slices.Clone(npc.Goals)
+			for _, goal := range review.Completed {
+				if _, err := client.SetNPCGoal(ctx, npcID, "complete", goal); err != nil {
+					debugLogger.Printf("Failed to complete goal for %s: %v", npcID, err)
+					continue
+				}
+				goals = slices.DeleteFunc(goals, func(g string) bool { return g == goal })
+			}
+			for _, goal := range review.Added {
+				if _, err := client.SetNPCGoal(ctx, npcID, "add", goal); err != nil {
+					debugLogger.Printf("Failed to add goal for %s: %v", npcID, err)
+					continue
+				}
+				goals = append(goals, goal)
+			}
+			msg.goals[npcID] = goals
+		}
+		return msg
+	}
+}
+
+// handleNPCGoalsReviewed adopts the reviewed goals into the local world. The server
+// already has them, so the next refresh agrees.
+func (m Model) handleNPCGoalsReviewed(msg npcGoalsReviewedMsg) (tea.Model, tea.Cmd) {
+	if len(msg.goals) == 0 {
+		return m, nil
+	}
+	before := m.world
+	world := m.world.Clone()
+	for npcID, goals := range msg.goals {
+		npc, ok := world.NPCs[npcID]
+		if !ok {
+			continue
+		}
+		npc.Goals = goals
+		world.NPCs[npcID] = npc
+	}
+	(&m).setWorld(world)
+	if lines := goalChangeLines(before, m.world); len(lines) > 0 {
+		(&m).debugLog(debug.NPCState, append(lines, "")...)
+	}
+	return m, nil
+}
+
+// goalChangeLines has a debug line for each NPC whose goals differ between before and
+// after.
+func goalChangeLines(before, after game.WorldState) []string {
+	var lines []string
+	for _, npcID := range sortedNPCIDs(after) {
+		old, goals := before.NPCs[npcID].Goals, after.NPCs[npcID].Goals
+		if slices.Equal(old, goals) {
+			continue
+		}
+		var changes []string
+		for _, goal := range old {
+			if !slices.Contains(goals, goal) {
+				changes = append(changes, "done: "+goal)
+			}
+		}
+		for _, goal := range goals {
+			if !slices.Contains(old, goal) {
+				changes = append(changes, "new: "+goal)
+			}
+		}
+		lines = append(lines, colorize(colorYellow, fmt.Sprintf("[%s GOALS] %s", strings.ToUpper(npcID), strings.Join(changes, "; "))))
+	}
+	return lines
+}
+
+func sortedNPCIDs(world game.WorldState) []string {
+	ids := make([]string, 0, len(world.NPCs))
+	for id := range world.NPCs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+	"textadventure/internal/game/facts"
+)
+
+// defaultNPCNarrationDistance is how many exits away from the player an NPC can act and
+// still get NPC-perspective narration, unless configured otherwise.
+const defaultNPCNarrationDistance = 1
+
+// SetNPCNarrationDistance sets how far from the player an NPC's turn is narrated from
+// its perspective. 0 means only NPCs in the player's room; negative values keep the default.
+func (m *Model) SetNPCNarrationDistance(distance int) {
+	if distance < 0 {
+		distance = defaultNPCNarrationDistance
+	}
+	m.npcNarrationDistance = distance
+}
+
+// markVisited records the player's current location as visited, along with the rooms
+// on the world's known map.
+func (m *Model) markVisited() {
+	if m.visitedLocations == nil {
+		m.visitedLocations = map[string]bool{}
+	}
+	for _, location := range m.world.VisitedLocations {
+		m.visitedLocations[location] = true
+	}
+	if m.world.Location != "" {
+		m.visitedLocations[m.world.Location] = true
+	}
+}
+
+// npcNarrationGate is whether an NPC's turn is worth NPC-perspective narration.
+type npcNarrationGate struct {
+	Narrate  bool
+	Distance int // -1 when the NPC's room can't be reached from the player's
+	Visited  bool
+}
+
+// gateNPCNarration narrates an NPC's turn only when the NPC is within the configured
+// distance of the player or in a room the player has visited. Elsewhere the narration
+// would only feed facts for a room the player may never see.
+func (m *Model) gateNPCNarration(npcID string) npcNarrationGate {
+	location := m.world.NPCs[npcID].Location
+	var distance int
+	if m.distances != nil {
+		distance = m.distances.Distance(m.worldVersion, m.world, m.world.Location, location)
+	} else {
+		distance = game.RoomDistance(m.world.Locations, m.world.Location, location)
+	}
+	gate := npcNarrationGate{Distance: distance, Visited: m.visitedLocations[location]}
+	gate.Narrate = (distance >= 0 && distance <= m.npcNarrationDistance) || gate.Visited
+	return gate
+}
+
+// narrateNPCTurn generates NPC-perspective narration for an NPC's turn when the gate
+// allows it. Otherwise it records a synthetic observation for the NPC's room without an
+// LLM call and returns nil.
+func (m *Model) narrateNPCTurn(msg director.MutationsGeneratedMsg) tea.Cmd {
+	npcID := msg.ActingNPCID
+	npc, ok := m.world.NPCs[npcID]
+	if !ok {
+		return nil
+	}
+	gate := m.gateNPCNarration(npcID)
+	if m.turnSpan != nil {
+		m.turnSpan.AddEvent("npc.narration_gate", trace.WithAttributes(
+			attribute.String("npc.id", npcID),
+			attribute.Bool("npc.narration_generated", gate.Narrate),
+			attribute.Int("npc.distance", gate.Distance),
+			attribute.Bool("npc.location_visited", gate.Visited),
+		))
+	}
+	if m.loggers.Debug.IsEnabled() {
+		decision := "synthetic observation"
+		if gate.Narrate {
+			decision = "narrate"
+		}
+		m.loggers.Debug.Printf("NPC narration gate for %s at %s: %s (distance %d, visited %t)", npcID, npc.Location, decision, gate.Distance, gate.Visited)
+	}
+	if gate.Narrate {
+		return m.generateNPCNarration(npcID, msg.WorldEvents, msg.ActionContext, msg.Successes)
+	}
+	m.recordSyntheticObservation(npcID, npc.Location)
+	return nil
+}
+
+// syntheticObservation is the fact recorded for an un-narrated NPC turn. Unmet NPCs are
+// described rather than named, since location facts reach the narrator.
+func syntheticObservation(world game.WorldState, npcID, locationID string) string {
+	who := npcID
+	if !game.HasMetNPC(world, npcID) {
+		who = game.NPCDescription(world, npcID)
+	}
+	where := locationID
+	if name := world.Locations[locationID].Name; name != "" {
+		where = name
+	}
+	return fmt.Sprintf("%s has been in the %s", who, where)
+}
+
+// recordSyntheticObservation adds the synthetic observation to the NPC's room once;
+// repeat visits by the same NPC don't add more facts.
+func (m *Model) recordSyntheticObservation(npcID, locationID string) {
+	loc, ok := m.world.Locations[locationID]
+	if !ok {
+		return
+	}
+	line := syntheticObservation(m.world, npcID, locationID)
+	for _, fact := range loc.Facts {
+		if fact == line {
+			return
+		}
+	}
+	m.persistAttributedFactsForLocation(&facts.FactAttribution{
+		LocationFacts: map[string][]string{locationID: {line}},
+	}, locationID)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/actors"
+	"textadventure/internal/game/director"
+	"textadventure/internal/game/events"
+)
+
+// npcReply is what an NPC the player spoke to said back.
+type npcReply struct {
+	npcID string
+	words string // what the player said to the NPC
+	reply string
+	err   error
+}
+
+// memory is what the NPC keeps of the exchange: hearing the player, and answering.
+func (r npcReply) memory() (thought, action string) {
+	return fmt.Sprintf("The player said to me: %q", r.words), "say " + r.reply
+}
+
+// npcRepliesMsg carries the replies of the NPCs the player spoke to this turn, and the
+// speech world events they make.
+type npcRepliesMsg struct {
+	replies     []npcReply
+	worldEvents []events.WorldEvent
+}
+
+// npcRepliesCmd has every NPC the player spoke to reply, in the order they were spoken
+// to, and records each reply in the event log.
+func (m Model) npcRepliesCmd(addressed []director.Address) tea.Cmd {
+	ctx := m.createGameContext(m.turnContext, "npc.reply")
+	llmService := m.llmService
+	debugLogger := m.loggers.Debug
+	world := m.world
+	eventStore, turnIndex := m.eventStore, m.turnIndex
+	return func() tea.Msg {
+		var msg npcRepliesMsg
+		for _, address := range addressed {
+			reply, err := actors.GenerateNPCReply(ctx, llmService, address.NPCID, address.Words, world)
+			msg.replies = append(msg.replies, npcReply{npcID: address.NPCID, words: address.Words, reply: reply, err: err})
+			if err != nil {
+				continue
+			}
+			location := world.NPCs[address.NPCID].Location
+			msg.worldEvents = append(msg.worldEvents, events.New(events.EventSpeech, address.NPCID, location, fmt.Sprintf("says to the player: %q", reply)))
+		}
+		if eventStore != nil && len(msg.worldEvents) > 0 {
+			if err := eventStore.Append(turnIndex, msg.worldEvents); err != nil {
+				debugLogger.Errorf("failed to log npc reply events: %v", err)
+			}
+		}
+		return msg
+	}
+}
+
+// handleNPCReplies shows the replies as dialogue and adds them to the history and the
+// turn's world events, so the NPCs about to act hear them, and queues them for the
+// NPCs' memories, then starts the NPC turns.
+// An NPC whose reply failed says nothing; it can still answer on its own turn.
+func (m Model) handleNPCReplies(msg npcRepliesMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase != PlayerTurn {
+		return m, nil
+	}
+	for _, reply := range msg.replies {
+		if reply.err != nil {
+			m.loggers.Debug.Errorf("%s could not reply: %v", reply.npcID, reply.err)
+			continue
+		}
+		m.gameHistory.AddNPCSpeech(reply.npcID, reply.reply, m.world)
+		thought, action := reply.memory()
+		(&m).queueNPCMemory(reply.npcID, thought, action)
+		m.currentNPCActions = append(m.currentNPCActions, fmt.Sprintf("%s: %q", reply.npcID, reply.reply))
+		m.messages = append(m.messages, colorize(m.npcColor(reply.npcID), fmt.Sprintf("%s: %q", m.speakerName(reply.npcID), reply.reply)), "")
+	}
+	m.accumulatedWorldEvents = append(m.accumulatedWorldEvents, msg.worldEvents...)
+	return m, (&m).startNPCTurns()
+}
+
+// speakerName is how a line of dialogue names an NPC: by name once the player has met
+// them, and by description until then.
+func (m Model) speakerName(npcID string) string {
+	if game.HasMetNPC(m.world, npcID) {
+		return game.NPCName(npcID)
+	}
+	description := game.NPCDescription(m.world, npcID)
+	return strings.ToUpper(description[:1]) + description[1:]
+}
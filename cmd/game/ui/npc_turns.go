@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game/actors"
+	"textadventure/internal/game/events"
+)
+
+// defaultNPCTurnBudget is how many NPCs act per turn unless configured otherwise.
+const defaultNPCTurnBudget = 1
+
+// SetNPCTurnBudget caps how many NPCs act each turn. Values below 1 keep the default.
+func (m *Model) SetNPCTurnBudget(budget int) {
+	if budget < 1 {
+		budget = defaultNPCTurnBudget
+	}
+	m.npcTurnBudget = budget
+}
+
+// startNPCTurns ends the player's part of the turn and queues the NPCs to act on
+// everything that happened in it, scheduled events included. NPC emotions fade by a
+// turn first.
+func (m *Model) startNPCTurns() tea.Cmd {
+	m.advanceTurn(turnEventPlayerResolved)
+	m.npcTurnInFlight = false
+	m.decayNPCEmotions()
+	m.planNPCTurns(m.accumulatedWorldEvents)
+	return npcTurnCmd(m.accumulatedWorldEvents)
+}
+
+// planNPCTurns fills the NPC phase queue with the best-scoring NPCs within the budget
+// and updates every NPC's turns-since-acted count. The full order is shown in debug mode.
+func (m *Model) planNPCTurns(worldEvents []events.WorldEvent) {
+	order := actors.OrderNPCTurns(m.world, m.npcIdleTurns, worldEvents)
+	budget := m.npcTurnBudget
+	if budget < 1 {
+		budget = defaultNPCTurnBudget
+	}
+
+	if m.npcIdleTurns == nil {
+		m.npcIdleTurns = make(map[string]int)
+	}
+	m.npcQueue = m.npcQueue[:0]
+	m.npcPendingActions = nil
+	m.npcPhaseStart = time.Now()
+	m.npcParallelTurns = 0
+	parts := make([]string, 0, len(order))
+	for i, entry := range order {
+		if i < budget {
+			m.npcQueue = append(m.npcQueue, entry.NPCID)
+			m.npcIdleTurns[entry.NPCID] = 0
+			parts = append(parts, fmt.Sprintf("%s(%d)*", entry.NPCID, entry.Score))
+		} else {
+			m.npcIdleTurns[entry.NPCID]++
+			parts = append(parts, fmt.Sprintf("%s(%d)", entry.NPCID, entry.Score))
+		}
+	}
+
+	m.debugLog(debug.Turns, fmt.Sprintf("[DEBUG] NPC turn order (budget %d, * acts): %s", budget, strings.Join(parts, " ")), "")
+}
+
+// stampNPCTurn marks the action turn reports with where the NPC is and the world version
+// now, as the turn is dispatched, so handleNPCAction can tell a result computed against
+// a world that has since moved on.
+func (m Model) stampNPCTurn(npcID string, turn tea.Cmd) tea.Cmd {
+	location, worldVersion := m.world.NPCs[npcID].Location, m.worldVersion
+	return func() tea.Msg {
+		msg := turn()
+		if action, ok := msg.(actors.NPCActionMsg); ok {
+			action.Location, action.WorldVersion = location, worldVersion
+			return action
+		}
+		return msg
+	}
+}
+
+// nextNPCTurnCmd applies the next action left by a parallel batch, or starts the next
+// queued NPC's turn, or narration once both are empty. Later NPCs perceive everything
+// that happened earlier in the turn.
+func (m *Model) nextNPCTurnCmd() tea.Cmd {
+	m.npcTurnInFlight = false
+	if len(m.npcPendingActions) > 0 {
+		action := m.npcPendingActions[0]
+		m.npcPendingActions = m.npcPendingActions[1:]
+		m.npcTurnInFlight = true
+		return func() tea.Msg { return action }
+	}
+	if len(m.npcQueue) == 0 {
+		return m.endNPCPhase()
+	}
+	return npcTurnCmd(m.accumulatedWorldEvents)
+}
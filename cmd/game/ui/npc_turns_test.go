@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+func TestPlanNPCTurns(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget int
+		rounds [][]string // the queue planned each round
+		idle   map[string]int
+	}{
+		{"budget of one lets every NPC act in turn", 1, [][]string{{"marcus"}, {"ada"}, {"elena"}, {"marcus"}}, map[string]int{"marcus": 0, "ada": 2, "elena": 1}},
+		{"budget of two", 2, [][]string{{"marcus", "ada"}, {"elena", "marcus"}, {"ada", "marcus"}}, map[string]int{"marcus": 0, "ada": 0, "elena": 1}},
+		{"budget covering every NPC", 5, [][]string{{"marcus", "ada", "elena"}, {"marcus", "ada", "elena"}}, map[string]int{"marcus": 0, "ada": 0, "elena": 0}},
+		{"budget below one keeps the default", 0, [][]string{{"marcus"}, {"ada"}}, map[string]int{"marcus": 1, "ada": 0, "elena": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			// Marcus shares the foyer with the player; Ada and Elena are a room away.
+			m.world.NPCs["marcus"] = game.NPCInfo{Location: "foyer"}
+			m.world.NPCs["ada"] = game.NPCInfo{Location: "study"}
+			m.SetNPCTurnBudget(tt.budget)
+
+			for round, want := range tt.rounds {
+				m.planNPCTurns(nil)
+				if !slices.Equal(m.npcQueue, want) {
+					t.Fatalf("round %d queue = %q, want %q", round+1, m.npcQueue, want)
+				}
+			}
+			if fmt.Sprint(m.npcIdleTurns) != fmt.Sprint(tt.idle) {
+				t.Errorf("idle turns = %v, want %v", m.npcIdleTurns, tt.idle)
+			}
+		})
+	}
+}
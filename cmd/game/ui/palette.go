@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"textadventure/internal/game"
+)
+
+const (
+	ansiReset       = "\033[0m"
+	defaultNPCColor = "36"
+)
+
+// SGR codes for the game's own messages: errors red, mutations magenta, world events
+// cyan and NPC debug lines yellow.
+const (
+	colorRed     = "31"
+	colorGreen   = "32"
+	colorYellow  = "33"
+	colorMagenta = "35"
+	colorCyan    = "36"
+)
+
+// npcPalette is the order colors are handed out to NPCs without a valid scenario color.
+var npcPalette = []string{"33", "35", "32", "34", "91", "92", "93", "94", "95", "96", "31", "36"}
+
+// namedColors lets scenarios use readable names instead of raw SGR codes.
+var namedColors = map[string]string{
+	"red": "31", "green": "32", "yellow": "33", "blue": "34", "magenta": "35", "cyan": "36",
+	"bright_red": "91", "bright_green": "92", "bright_yellow": "93", "bright_blue": "94",
+	"bright_magenta": "95", "bright_cyan": "96",
+}
+
+// normalizeColor turns a scenario color into an SGR code, or returns false if it
+// isn't a foreground color we can render.
+func normalizeColor(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if code, ok := namedColors[value]; ok {
+		return code, true
+	}
+	if rest, ok := strings.CutPrefix(value, "38;5;"); ok {
+		n, err := strconv.Atoi(rest)
+		return value, err == nil && n >= 0 && n <= 255
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return "", false
+	}
+	return value, (n >= 30 && n <= 37) || (n >= 90 && n <= 97)
+}
+
+// assignNPCColors gives every NPC a distinct color. NPCs are processed in ID order so
+// assignments are stable across runs; a valid scenario color is kept unless an earlier
+// NPC already claimed it, otherwise the next unused palette color is used.
+func assignNPCColors(npcs map[string]game.NPCInfo) map[string]string {
+	ids := make([]string, 0, len(npcs))
+	for id := range npcs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	colors := make(map[string]string, len(ids))
+	used := make(map[string]bool)
+	var unassigned []string
+	for _, id := range ids {
+		if code, ok := normalizeColor(npcs[id].DebugColor); ok && !used[code] {
+			colors[id] = code
+			used[code] = true
+			continue
+		}
+		unassigned = append(unassigned, id)
+	}
+
+	next := 0
+	for _, id := range unassigned {
+		for next < len(npcPalette) && used[npcPalette[next]] {
+			next++
+		}
+		if next >= len(npcPalette) {
+			// More NPCs than palette entries: reuse colors rather than fail
+			colors[id] = npcPalette[len(colors)%len(npcPalette)]
+			continue
+		}
+		colors[id] = npcPalette[next]
+		used[npcPalette[next]] = true
+	}
+	return colors
+}
+
+// colorize wraps text in the given SGR color code.
+func colorize(code, text string) string {
+	return fmt.Sprintf("\033[%sm%s%s", code, text, ansiReset)
+}
+
+// npcColor returns the color assigned to an NPC, falling back to cyan.
+func (m Model) npcColor(npcID string) string {
+	if code, ok := m.npcColors[npcID]; ok {
+		return code
+	}
+	return defaultNPCColor
+}
+
+// npcLegend renders the one-line NPC → color legend shown in debug mode.
+func npcLegend(colors map[string]string) string {
+	if len(colors) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(colors))
+	for id := range colors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, colorize(colors[id], "■ "+id))
+	}
+	return "[DEBUG] NPCs: " + strings.Join(parts, "  ")
+}
+
+// npcThoughtLines formats an NPC's multi-line thoughts: the first line is tagged with
+// the NPC's ID and continuation lines are indented under it.
+func (m Model) npcThoughtLines(npcID, thoughts string) []string {
+	colorCode := m.npcColor(npcID)
+	var lines []string
+	for i, line := range strings.Split(thoughts, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if i == 0 {
+			lines = append(lines, colorize(colorCode, fmt.Sprintf("[%s] %s", strings.ToUpper(npcID), line)))
+		} else {
+			lines = append(lines, colorize(colorCode, "      "+line))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"maps"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+func TestAssignNPCColors(t *testing.T) {
+	tests := []struct {
+		name string
+		npcs map[string]string // NPC ID -> scenario color
+		want map[string]string
+	}{
+		{"scenario colors kept", map[string]string{"elena": "yellow", "marcus": "bright_blue"}, map[string]string{"elena": "33", "marcus": "94"}},
+		{"raw codes kept", map[string]string{"elena": "31", "marcus": "38;5;208"}, map[string]string{"elena": "31", "marcus": "38;5;208"}},
+		{"invalid colors get the palette in ID order", map[string]string{"marcus": "plaid", "elena": "", "ada": "7"}, map[string]string{"ada": "33", "elena": "35", "marcus": "32"}},
+		{"a claimed color goes to the first NPC by ID", map[string]string{"marcus": "yellow", "elena": "33"}, map[string]string{"elena": "33", "marcus": "35"}},
+		{"palette skips claimed colors", map[string]string{"ada": "", "elena": "yellow"}, map[string]string{"ada": "35", "elena": "33"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			npcs := make(map[string]game.NPCInfo)
+			for id, color := range tt.npcs {
+				npcs[id] = game.NPCInfo{DebugColor: color}
+			}
+			got := assignNPCColors(npcs)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("colors = %v, want %v", got, tt.want)
+			}
+			// Map iteration order must not leak into the assignment
+			for range 20 {
+				if again := assignNPCColors(maps.Clone(npcs)); !maps.Equal(again, got) {
+					t.Fatalf("assignment changed between runs: %v, then %v", got, again)
+				}
+			}
+		})
+	}
+}
+
+// An NPC's color doesn't change as others come and go, so long as those joining sort
+// after it and carry their own colors.
+func TestNPCColorsStableAcrossWorldUpdates(t *testing.T) {
+	m := newTestModel(t)
+	before := m.npcColor("elena")
+
+	world := m.world.Clone()
+	world.NPCs["zara"] = game.NPCInfo{Location: "study", DebugColor: "magenta"}
+	m.setWorld(world)
+	if got := m.npcColor("elena"); got != before {
+		t.Errorf("elena's color changed from %s to %s", before, got)
+	}
+	if got := m.npcColor("zara"); got != "35" {
+		t.Errorf("zara = %s, want her scenario color", got)
+	}
+	if got := m.npcColor("nobody"); got != defaultNPCColor {
+		t.Errorf("unknown NPC = %s, want the default", got)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	if got := colorize(colorRed, "[ERROR] lost"); got != "\033[31m[ERROR] lost\033[0m" {
+		t.Errorf("colorize = %q", got)
+	}
+	if got := npcLegend(map[string]string{"marcus": "35", "elena": "33"}); got != "[DEBUG] NPCs: \033[33m■ elena\033[0m  \033[35m■ marcus\033[0m" {
+		t.Errorf("legend = %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+)
+
+// planPreviewMsg carries the outcome of /plan back to the UI.
+type planPreviewMsg struct {
+	action  string
+	preview *director.PlanPreview
+	err     error
+}
+
+// runPlanCommand shows the mutations the director would generate for an action without
+// executing them. No turn starts, so nothing is narrated, no NPC acts and history is
+// untouched.
+func runPlanCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if m.turnPhase != AwaitingInput {
+		return []string{"Can't plan during a turn; try again when it finishes"}, nil
+	}
+	action := args[0]
+	ctx := m.createGameContext(m.sessionContext, "debug.plan")
+	d := m.director
+	world := m.world
+	history := m.gameHistory.For(game.HistoryForDirector, "")
+	cmd := func() tea.Msg {
+		preview, err := d.PreviewIntent(ctx, action, world, history, "")
+		return planPreviewMsg{action: action, preview: preview, err: err}
+	}
+	return []string{fmt.Sprintf("Planning %q...", action)}, cmd
+}
+
+func (m Model) handlePlanPreview(msg planPreviewMsg) (tea.Model, tea.Cmd) {
+	m.messages = append(m.messages, colorize(colorMagenta, fmt.Sprintf("[PLAN] %s", msg.action)))
+	switch {
+	case msg.err != nil:
+		m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("  [ERROR] %v", msg.err)))
+	case len(msg.preview.Plan.Mutations) == 0:
+		m.messages = append(m.messages, colorize(colorMagenta, "  No mutations"))
+	default:
+		for _, mutation := range msg.preview.Plan.Mutations {
+			args, _ := json.Marshal(mutation.Args)
+			m.messages = append(m.messages, colorize(colorMagenta, fmt.Sprintf("  %s %s", mutation.Tool, args)))
+		}
+		for _, violation := range msg.preview.Violations {
+			m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("  [ERROR] %s", violation)))
+		}
+	}
+	m.messages = append(m.messages, "")
+	return m, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "plan",
+		Args:      []commandArg{{Name: "action", Rest: true}},
+		DebugOnly: true,
+		Summary:   "Show the mutations the director would plan for an action, without running them",
+		Run:       runPlanCommand,
+	})
+}
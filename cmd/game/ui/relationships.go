@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+)
+
+func runRelationshipsCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	lines := []string{"Relationships with the player:"}
+	for _, npcID := range sortedNPCIDs(m.world) {
+		relationship := m.world.NPCs[npcID].Relationship
+		line := fmt.Sprintf("%s: %+d, %s", npcID, relationship.Affinity, game.Attitude(relationship.Affinity))
+		if len(relationship.Interactions) > 0 {
+			line += " - " + strings.Join(relationship.Interactions, "; ")
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 1 {
+		return []string{"No NPCs"}, nil
+	}
+	return lines, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:      "relationships",
+		DebugOnly: true,
+		Summary:   "Show each NPC's affinity for the player and the interactions behind it",
+		Run:       runRelationshipsCommand,
+	})
+}
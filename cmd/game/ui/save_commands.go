@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+	"textadventure/internal/save"
+)
+
+// gameLoadedMsg carries the outcome of /load.
+type gameLoadedMsg struct {
+	name     string
+	path     string
+	snapshot save.Snapshot
+	world    game.WorldState
+	err      error
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:    "save",
+		Args:    []commandArg{{Name: "name", Optional: true}},
+		Summary: "Save the game to saves/<name>.json",
+		Run:     runSaveCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:    "load",
+		Args:    []commandArg{{Name: "name", Optional: true}},
+		Summary: "Load a save (the most recent one without a name)",
+		Run:     runLoadCommand,
+	})
+}
+
+func runSaveCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if m.worldUnavailable {
+		return []string{"The world is unavailable; use /save-local instead"}, nil
+	}
+	if m.turnPhase != AwaitingInput {
+		return []string{"Can't save during a turn; try again when it finishes"}, nil
+	}
+	name := "save-" + time.Now().Format("20060102-150405")
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if err := save.ValidateName(name); err != nil {
+		return []string{fmt.Sprintf("Cannot save: %v", err)}, nil
+	}
+	return []string{fmt.Sprintf("Saving %s...", name)}, m.holdTurnsFor(m.snapshotCmd(save.SavePath(name), name, "save.game"))
+}
+
+func runLoadCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if m.worldUnavailable {
+		return []string{"The world is unavailable; use /retry-connection first"}, nil
+	}
+	if m.turnPhase != AwaitingInput {
+		return []string{"Can't load during a turn; try again when it finishes"}, nil
+	}
+	if m.loadPending {
+		return []string{"Already loading a save; wait for it to finish"}, nil
+	}
+	if m.writesPending {
+		return []string{"The world is still being saved; try again in a moment"}, nil
+	}
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		latest, err := save.Latest()
+		if err != nil {
+			return []string{fmt.Sprintf("Nothing to load: %v", err)}, nil
+		}
+		name = latest
+	}
+	// The server's world is replaced before the result arrives, so no turn may start
+	// against the old one in the meantime
+	m.loadPending = true
+	return []string{fmt.Sprintf("Loading %s...", name)}, m.loadGameCmd(name)
+}
+
+// loadGameCmd checks the save against world validation before touching the server, so
+// a save whose locations, NPCs or items don't line up leaves the current game as it is.
+func (m *Model) loadGameCmd(name string) tea.Cmd {
+	ctx := m.createGameContext(m.sessionContext, "save.load")
+	client := m.mcpClient
+	return func() tea.Msg {
+		path, err := save.Resolve(name)
+		if err != nil {
+			return gameLoadedMsg{name: name, err: err}
+		}
+		snapshot, err := save.Read(path)
+		if err != nil {
+			return gameLoadedMsg{name: name, err: err}
+		}
+		save.Migrate(&snapshot)
+		if err := save.Validate(snapshot.World); err != nil {
+			return gameLoadedMsg{name: name, err: err}
+		}
+		if _, err := client.RestoreWorldState(ctx, snapshot.World); err != nil {
+			return gameLoadedMsg{name: name, err: fmt.Errorf("failed to restore world state: %w", err)}
+		}
+		mcpWorld, err := client.GetWorldState(ctx)
+		if err != nil {
+			return gameLoadedMsg{name: name, err: fmt.Errorf("world restored but could not be read back: %w", err)}
+		}
+		return gameLoadedMsg{name: name, path: path, snapshot: snapshot, world: mcp.MCPToGameWorldState(mcpWorld)}
+	}
+}
+
+// handleGameLoaded adopts the restored world, history and turn count, then replays the
+// opening look-around against it so the player sees where they are. Input entered while
+// the load was pending was queued, and runs after the look-around.
+func (m Model) handleGameLoaded(msg gameLoadedMsg) (tea.Model, tea.Cmd) {
+	m.loadPending = false
+	if msg.err != nil {
+		m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("Could not load %s: %v", msg.name, msg.err)), "")
+		return m, nil
+	}
+	(&m).setWorld(msg.world)
+	m.gameHistory = game.NewHistory(12)
+	(&m).RestoreSnapshot(msg.snapshot.History, save.Origin(msg.path, msg.snapshot))
+	m.turnIndex = msg.snapshot.Metadata.TurnIndex
+	m.npcIdleTurns = map[string]int{}
+	m.visitedLocations = nil
+	(&m).markVisited()
+	m.worldStale = false
+	m.messages = append(m.messages, colorize(colorGreen, fmt.Sprintf("— Restored %s (turn %d, %s) —", msg.name, m.turnIndex, m.world.Location)), "")
+	if m.turnPhase != AwaitingInput {
+		return m, nil
+	}
+	return m, initialLookAroundCmd()
+}
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+	"textadventure/internal/save"
+)
+
+func loadedMsg(turnIndex int) gameLoadedMsg {
+	world := game.NewDefaultWorldState()
+	world.Location = "study"
+	return gameLoadedMsg{
+		name:     "before-the-storm",
+		path:     "saves/before-the-storm.json",
+		snapshot: save.Snapshot{Metadata: save.Metadata{Name: "before-the-storm", TurnIndex: turnIndex}, History: []string{"> look"}},
+		world:    world,
+	}
+}
+
+func TestLoadBlocksTurnsUntilItLands(t *testing.T) {
+	m := newTestModel(t)
+	lines, cmd := runLoadCommand(&m, []string{"before-the-storm"})
+	if cmd == nil || len(lines) != 1 || lines[0] != "Loading before-the-storm..." {
+		t.Fatalf("load = %q, %v", lines, cmd != nil)
+	}
+	if !m.loadPending || !m.isLoading() {
+		t.Fatal("load is not pending")
+	}
+
+	// Input while the load is in flight waits instead of starting a turn against the
+	// world the load is replacing.
+	m = enter(t, m, "go north")
+	if m.turnPhase != AwaitingInput || m.turnSpan != nil {
+		t.Fatalf("a turn started during the load: phase %s", m.turnPhase)
+	}
+	if m.queuedInput != "go north" {
+		t.Errorf("queued = %q, want the input held back", m.queuedInput)
+	}
+	if m.canBeginTurn(turnEventIntro) {
+		t.Error("a turn may begin while the load is pending")
+	}
+	if again, cmd := runLoadCommand(&m, []string{"other"}); cmd != nil || again[0] != "Already loading a save; wait for it to finish" {
+		t.Errorf("second load = %q", again)
+	}
+
+	updated, cmd := m.handleGameLoaded(loadedMsg(7))
+	m = updated.(Model)
+	if m.loadPending {
+		t.Error("load still pending after its result landed")
+	}
+	if m.world.Location != "study" || m.turnIndex != 7 {
+		t.Errorf("world at %s, turn %d; want study, 7", m.world.Location, m.turnIndex)
+	}
+	if cmd == nil {
+		t.Fatal("no look-around after the load")
+	}
+	if _, ok := cmd().(initialLookAroundMsg); !ok {
+		t.Error("the load's follow-up is not the look-around")
+	}
+	if m.queuedInput != "go north" {
+		t.Errorf("queued input lost: %q", m.queuedInput)
+	}
+	if !m.canBeginTurn(turnEventIntro) {
+		t.Error("turns are still blocked after the load")
+	}
+}
+
+func TestFailedLoadUnblocksTurns(t *testing.T) {
+	m := newTestModel(t)
+	runLoadCommand(&m, []string{"missing"})
+	updated, cmd := m.handleGameLoaded(gameLoadedMsg{name: "missing", err: errors.New("no such save")})
+	m = updated.(Model)
+	if m.loadPending || cmd != nil {
+		t.Errorf("pending = %v, cmd = %v after a failed load", m.loadPending, cmd != nil)
+	}
+	if m.world.Location != "foyer" {
+		t.Errorf("failed load moved the world to %s", m.world.Location)
+	}
+}
+
+func TestLoadRefusedDuringTurn(t *testing.T) {
+	m := newTestModel(t)
+	m.turnPhase = Narration
+	lines, cmd := runLoadCommand(&m, []string{"before-the-storm"})
+	if cmd != nil || m.loadPending {
+		t.Error("a load started during a turn")
+	}
+	if lines[0] != "Can't load during a turn; try again when it finishes" {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestBookmarkKeepsLineage(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m, _ := withFakeWorld(t, newTestModel(t))
+	m.RestoreSnapshot([]string{"> look", "The foyer is cold."}, "before-cellar@1a2b3c4d#12")
+	m.turnIndex = 9
+
+	line, cmd := m.requestBookmark("mid-cellar")
+	if line != `Writing bookmark "mid-cellar"...` || cmd == nil {
+		t.Fatalf("bookmark = %q, %v", line, cmd != nil)
+	}
+	if !m.writesPending {
+		t.Error("turns may begin while the bookmark is written")
+	}
+	m = run(t, m, cmd)
+	if m.writesPending {
+		t.Error("turns still held after the bookmark landed")
+	}
+	if last := m.messages[len(m.messages)-2]; last != "[DEBUG] Bookmark saved to saves/bookmarks/mid-cellar.json" {
+		t.Errorf("report = %q", last)
+	}
+
+	snapshot, err := save.Read(save.BookmarkPath("mid-cellar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := save.Metadata{Name: "mid-cellar", SessionID: m.sessionID, TurnIndex: 9, BranchedFrom: "before-cellar@1a2b3c4d#12"}
+	got := snapshot.Metadata
+	got.CreatedAt = time.Time{}
+	if got != want {
+		t.Errorf("metadata = %+v, want %+v", got, want)
+	}
+	if len(snapshot.History) != 2 || snapshot.World.Player.Location != "foyer" {
+		t.Errorf("snapshot history %q, player at %s", snapshot.History, snapshot.World.Player.Location)
+	}
+
+	if _, err := save.Branch("mid-cellar", "retry"); err != nil {
+		t.Fatal(err)
+	}
+	branched, err := save.Read(save.SavePath("retry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := save.Lineage("mid-cellar", snapshot.Metadata); branched.Metadata.BranchedFrom != want {
+		t.Errorf("branch lineage = %q, want %q", branched.Metadata.BranchedFrom, want)
+	}
+}
+
+// A bookmark asked for during a turn is taken after the turn's writes have landed, and
+// no turn starts until it has been written.
+func TestBookmarkDuringTurnWaitsForTheTurn(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m, fake := withFakeWorld(t, newTestModel(t))
+	m.turnPhase = Narration
+	if line, cmd := m.requestBookmark("mid-turn"); cmd != nil || line != `Bookmark "mid-turn" will be written when this turn finishes` {
+		t.Fatalf("bookmark during a turn = %q, %v", line, cmd != nil)
+	}
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", nil)
+
+	m.turnPhase = AwaitingInput
+	cmd := (&m).holdTurnsFor((&m).flushTurnWrites(), (&m).flushPendingBookmark())
+	if m.pendingBookmark != "" {
+		t.Error("bookmark still pending after the turn")
+	}
+	if m.canBeginTurn(turnEventPlayerInput) {
+		t.Error("a turn may begin before the bookmark is written")
+	}
+	m = run(t, m, cmd)
+	if !m.canBeginTurn(turnEventPlayerInput) {
+		t.Error("turns still held after the bookmark landed")
+	}
+
+	snapshot, err := save.Read(save.BookmarkPath("mid-turn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(snapshot.World.Locations["foyer"].Facts, "The floor is cold marble") {
+		t.Error("the bookmark was taken before the turn's writes landed")
+	}
+	if tools := fakeTools(fake); !slices.Equal(tools, []string{"add_location_facts", "get_world_state"}) {
+		t.Errorf("calls = %v", tools)
+	}
+}
+
+func TestSaveRunsInTheBackground(t *testing.T) {
+	t.Chdir(t.TempDir())
+	m, _ := withFakeWorld(t, newTestModel(t))
+	lines, cmd := runSaveCommand(&m, []string{"slot-1"})
+	if cmd == nil || lines[0] != "Saving slot-1..." {
+		t.Fatalf("save = %q, %v", lines, cmd != nil)
+	}
+	if again, cmd := runLoadCommand(&m, []string{"slot-1"}); cmd != nil || again[0] != "The world is still being saved; try again in a moment" {
+		t.Errorf("load during the save = %q", again)
+	}
+	m = run(t, m, cmd)
+	if last := m.messages[len(m.messages)-2]; last != "Game saved to saves/slot-1.json (load with /load slot-1)" {
+		t.Errorf("report = %q", last)
+	}
+	if _, err := save.Read(save.SavePath("slot-1")); err != nil {
+		t.Error(err)
+	}
+
+	fake := mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(m.world)).FailTool("get_world_state", errors.New("server gone"))
+	m.mcpClient = fake.WorldStateClient
+	_, cmd = runSaveCommand(&m, []string{"slot-2"})
+	m = run(t, m, cmd)
+	if last := m.messages[len(m.messages)-2]; !strings.HasPrefix(last, "Save failed: ") || m.writesPending {
+		t.Errorf("failed save reported %q, held %v", last, m.writesPending)
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+	"textadventure/internal/game/events"
+	"textadventure/internal/mcp"
+)
+
+// scheduledEventsMsg carries the countdown of scheduled events that starts a turn and
+// the outcome of any that fired.
+type scheduledEventsMsg struct {
+	world         game.WorldState
+	fired         director.FiredEvents
+	invariants    []game.InvariantViolation
+	refreshFailed bool
+	err           error
+}
+
+// fireScheduledEventsOrPlan counts pending scheduled events down before the director
+// plans the turn, so anything firing this turn has already happened when the player acts.
+func (m Model) fireScheduledEventsOrPlan() tea.Cmd {
+	if len(m.world.ScheduledEvents) == 0 || m.mcpClient == nil {
+		return m.planPlayerInput()
+	}
+	ctx := m.createGameContext(m.turnContext, "scheduled_events.fire")
+	client := m.mcpClient
+	debugLogger := m.loggers.Debug
+	world := m.world
+	eventStore, turnIndex := m.eventStore, m.turnIndex
+	return func() tea.Msg {
+		pending, due := game.AdvanceScheduledEvents(world.ScheduledEvents)
+		// Persist the countdown before firing, so a failure can't fire an event twice
+		if _, err := client.SyncScheduledEvents(ctx, mcp.GameToMCPScheduledEvents(pending)); err != nil {
+			return scheduledEventsMsg{err: err}
+		}
+		msg := scheduledEventsMsg{fired: director.FireScheduledEvents(ctx, due, client, debugLogger, world)}
+		if eventStore != nil {
+			if err := eventStore.Append(turnIndex, msg.fired.WorldEvents); err != nil {
+				debugLogger.Errorf("failed to log scheduled world events: %v", err)
+			}
+		}
+		mcpWorld, err := client.GetWorldState(ctx)
+		if err != nil {
+			world = world.Clone()
+			world.ScheduledEvents = pending
+			msg.world = world
+			msg.refreshFailed = true
+			return msg
+		}
+		msg.world, msg.invariants = director.RepairRefreshedWorld(nil, debugLogger, world, mcp.MCPToGameWorldState(mcpWorld))
+		if eventStore != nil {
+			if err := eventStore.AppendItemMoves(turnIndex, events.ItemMoves("event", world, msg.world)); err != nil {
+				debugLogger.Errorf("failed to log scheduled item moves: %v", err)
+			}
+		}
+		return msg
+	}
+}
+
+// handleScheduledEvents adopts the world after the countdown and queues fired events as
+// this turn's first world event lines, then plans the turn either way.
+func (m Model) handleScheduledEvents(msg scheduledEventsMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase != PlayerTurn {
+		return m, nil
+	}
+	if msg.err != nil {
+		(&m).recordSessionError("scheduled_events", msg.err.Error())
+		return m, m.planPlayerInput()
+	}
+	if msg.refreshFailed && len(msg.fired.Successes) > 0 {
+		m.worldStale = true
+	}
+	(&m).setWorld(msg.world)
+	(&m).recordInvariantViolations(msg.invariants)
+
+	fired := msg.fired
+	m.accumulatedWorldEvents = append(m.accumulatedWorldEvents, fired.WorldEvents...)
+	m.currentMutationResults = append(m.currentMutationResults, fired.Successes...)
+	m.currentFailures = append(m.currentFailures, fired.Failures...)
+
+	if len(fired.WorldEvents) > 0 {
+		lines := []string{colorize(colorCyan, "[SCHEDULED EVENTS]")}
+		for _, event := range fired.WorldEvents {
+			lines = append(lines, colorize(colorCyan, "  "+event.Line()))
+		}
+		lines = append(lines, outcomeLines(fired.Successes, fired.Failures)...)
+		(&m).debugLog(debug.Events, append(lines, "")...)
+	}
+	return m, m.planPlayerInput()
+}
+
+// scheduledEventLines lists pending scheduled events for /worldstate.
+func scheduledEventLines(events []game.ScheduledEvent) []string {
+	if len(events) == 0 {
+		return []string{"Scheduled Events: none"}
+	}
+	lines := []string{"Scheduled Events:"}
+	for _, event := range events {
+		lines = append(lines, fmt.Sprintf("  #%d in %d turns at %s: %s (%d mutations)", event.ID, event.TurnsLeft, event.Location, event.Description, len(event.Mutations)))
+	}
+	return lines
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+func moveElena(location string) []game.ScheduledMutation {
+	return []game.ScheduledMutation{{Tool: "move_npc", Args: map[string]interface{}{"npc_id": "elena", "location": location}}}
+}
+
+// scheduledModel is a player turn with three events pending: #3 and #1 due this turn,
+// scheduled in that order, and #2 two turns later. Elena can only reach the kitchen
+// if #1 has brought her to the foyer first.
+func scheduledModel(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel(t)
+	m.world.ScheduledEvents = []game.ScheduledEvent{
+		{ID: 3, TurnsLeft: 1, Description: "Elena heads for the kitchen", Location: "foyer", Mutations: moveElena("kitchen")},
+		{ID: 1, TurnsLeft: 1, Description: "Elena comes looking for you", Location: "library", Mutations: moveElena("foyer")},
+		{ID: 2, TurnsLeft: 3, Description: "the clock strikes", Location: "study"},
+	}
+	m.beginTurn(turnEventPlayerInput)
+	return m
+}
+
+func TestScheduledEventsFireInOrder(t *testing.T) {
+	m, fake := withFakeWorld(t, scheduledModel(t))
+	updated, _ := m.Update(m.fireScheduledEventsOrPlan()())
+	m = updated.(Model)
+
+	if want := []string{"sync_scheduled_events", "move_npc", "get_world_state", "move_npc", "get_world_state", "get_world_state"}; !slices.Equal(fakeTools(fake), want) {
+		t.Errorf("tools = %q, want %q", fakeTools(fake), want)
+	}
+	var fired []string
+	for _, event := range m.accumulatedWorldEvents {
+		fired = append(fired, event.Content)
+	}
+	if want := []string{"Elena comes looking for you", "Elena heads for the kitchen"}; !slices.Equal(fired, want) {
+		t.Errorf("fired %q, want %q", fired, want)
+	}
+	if len(m.currentMutationResults) != 2 || len(m.currentFailures) != 0 {
+		t.Errorf("successes %q, failures %q", m.currentMutationResults, m.currentFailures)
+	}
+	if m.world.NPCs["elena"].Location != "kitchen" {
+		t.Errorf("elena in %s", m.world.NPCs["elena"].Location)
+	}
+
+	// The countdown is persisted: the server and the game agree on what is left.
+	if events := fake.World().ScheduledEvents; len(events) != 1 || events[0].ID != 2 || events[0].TurnsLeft != 2 {
+		t.Errorf("server events = %+v", events)
+	}
+	if events := m.world.ScheduledEvents; len(events) != 1 || events[0].ID != 2 || events[0].TurnsLeft != 2 {
+		t.Errorf("game events = %+v", events)
+	}
+}
+
+func TestScheduledEventsCountDownAcrossTurns(t *testing.T) {
+	m, fake := withFakeWorld(t, scheduledModel(t))
+	m.world.ScheduledEvents = m.world.ScheduledEvents[2:]
+	for turn := 1; turn <= 2; turn++ {
+		updated, _ := m.Update(m.fireScheduledEventsOrPlan()())
+		m = updated.(Model)
+		if len(m.accumulatedWorldEvents) != 0 || len(fake.World().ScheduledEvents) != 1 || fake.World().ScheduledEvents[0].TurnsLeft != 3-turn {
+			t.Fatalf("turn %d: fired %d, server events %+v", turn, len(m.accumulatedWorldEvents), fake.World().ScheduledEvents)
+		}
+	}
+	updated, _ := m.Update(m.fireScheduledEventsOrPlan()())
+	m = updated.(Model)
+	if len(m.accumulatedWorldEvents) != 1 || m.accumulatedWorldEvents[0].Content != "the clock strikes" {
+		t.Errorf("fired %+v on the third turn", m.accumulatedWorldEvents)
+	}
+	if len(m.world.ScheduledEvents) != 0 || len(fake.World().ScheduledEvents) != 0 {
+		t.Errorf("events left: game %+v, server %+v", m.world.ScheduledEvents, fake.World().ScheduledEvents)
+	}
+}
+
+func TestScheduledEventsHeldWhenCountdownFails(t *testing.T) {
+	m, fake := withFakeWorld(t, scheduledModel(t))
+	fake.FailTool("sync_scheduled_events", errors.New("store locked"))
+	updated, cmd := m.Update(m.fireScheduledEventsOrPlan()())
+	m = updated.(Model)
+
+	if slices.Contains(fakeTools(fake), "move_npc") || len(m.accumulatedWorldEvents) != 0 {
+		t.Errorf("events fired without their countdown persisted: %q", fakeTools(fake))
+	}
+	if len(fake.World().ScheduledEvents) != 3 || len(m.world.ScheduledEvents) != 3 {
+		t.Errorf("events changed: server %d, game %d", len(fake.World().ScheduledEvents), len(m.world.ScheduledEvents))
+	}
+	if len(m.sessionErrors) != 1 || m.sessionErrors[0].Phase != "scheduled_events" {
+		t.Errorf("session errors = %+v", m.sessionErrors)
+	}
+	if cmd == nil {
+		t.Error("the turn was not planned after the failure")
+	}
+}
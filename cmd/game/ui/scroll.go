@@ -0,0 +1,109 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wheelScrollStep is how many messages one mouse wheel notch scrolls.
+const wheelScrollStep = 3
+
+// newMessagesIndicator replaces the bottom line of the chat while the player is
+// scrolled up and something new has arrived below.
+const newMessagesIndicator = "↓ new messages below (end to jump)"
+
+// chatCapacity is how many message rows fit in the chat panel.
+func (m Model) chatCapacity() int {
+	inputHeight := 3
+	chatHeight := m.height - inputHeight
+	if m.worldUnavailable {
+		chatHeight--
+	}
+	capacity := chatHeight - 2
+	if capacity < 1 {
+		capacity = 1
+	}
+	return capacity
+}
+
+// scrollBy moves the chat view delta messages up (negative scrolls down), staying
+// within the backlog. Reaching the bottom clears the new-messages indicator.
+func (m *Model) scrollBy(delta int) {
+	m.scrollOffset += delta
+	if limit := len(m.renderedMessages()) - m.chatCapacity(); m.scrollOffset > limit {
+		m.scrollOffset = limit
+	}
+	if m.scrollOffset <= 0 {
+		m.scrollToBottom()
+	}
+}
+
+// scrollToBottom anchors the view to the newest message again.
+func (m *Model) scrollToBottom() {
+	m.scrollOffset = 0
+	m.unseenBelow = false
+}
+
+// followNewMessages keeps a scrolled-up view where it is when messages are appended
+// below it, and flags them instead. At the bottom the view simply follows. It runs
+// after every message, like syncWorldAvailability.
+func (m *Model) followNewMessages(before int) {
+	added := len(m.messages) - before
+	if m.scrollOffset == 0 || added <= 0 {
+		return
+	}
+	m.scrollOffset += added
+	m.unseenBelow = true
+}
+
+// handleScrollKey handles the scrolling keys; it reports false for any other key.
+func (m *Model) handleScrollKey(key string) bool {
+	page := m.chatCapacity() - 1
+	if page < 1 {
+		page = 1
+	}
+	switch key {
+	case "pgup":
+		m.scrollBy(page)
+	case "pgdown":
+		m.scrollBy(-page)
+	case "home":
+		m.scrollBy(len(m.renderedMessages()))
+	case "end":
+		m.scrollToBottom()
+	default:
+		return false
+	}
+	return true
+}
+
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		(&m).scrollBy(wheelScrollStep)
+	case tea.MouseButtonWheelDown:
+		(&m).scrollBy(-wheelScrollStep)
+	}
+	return m, nil
+}
+
+// visibleWindow returns the messages that fit in the chat panel at the current scroll
+// position, with the new-messages indicator on the last row when it applies.
+func (m Model) visibleWindow(messages []string, capacity int) []string {
+	offset := m.scrollOffset
+	if limit := len(messages) - capacity; offset > limit {
+		offset = limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	end := len(messages) - offset
+	start := end - capacity
+	if start < 0 {
+		start = 0
+	}
+	window := messages[start:end]
+	if m.unseenBelow && offset > 0 && len(window) > 0 {
+		window = append(append([]string(nil), window[:len(window)-1]...), newMessagesIndicator)
+	}
+	return window
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/game/narration"
+	"textadventure/internal/save"
+)
+
+// SetSessionLimit ends the session by itself after a number of turns or a stretch of
+// play, for kiosks. The clock starts with the first player turn, so a session waiting
+// on its briefing isn't running down.
+func (m *Model) SetSessionLimit(limit game.SessionLimit) {
+	m.sessionLimit = limit
+}
+
+// sessionElapsed is how long the session has been played, by now.
+func (m Model) sessionElapsed(now time.Time) time.Duration {
+	if m.limitStart.IsZero() {
+		return 0
+	}
+	return now.Sub(m.limitStart)
+}
+
+// countLimitedTurn counts a player turn reaching narration toward the session limit.
+func (m *Model) countLimitedTurn(now time.Time) {
+	if !m.sessionLimit.Enabled() {
+		return
+	}
+	if m.limitStart.IsZero() {
+		m.limitStart = now
+	}
+	m.limitTurns++
+}
+
+// withWrapUp marks a narration context as part of the wrap-up once the session is
+// nearly over, so the narrator steers toward closure and tells the final turn as an
+// epilogue.
+func (m *Model) withWrapUp(ctx context.Context) context.Context {
+	elapsed := m.sessionElapsed(time.Now())
+	if !m.sessionLimit.WrappingUp(m.limitTurns, elapsed) {
+		return ctx
+	}
+	turnsLeft, _ := m.sessionLimit.TurnsLeft(m.limitTurns, elapsed)
+	if m.turnSpan != nil {
+		m.turnSpan.SetAttributes(attribute.Int("session.turns_left", turnsLeft))
+	}
+	m.debugLog(debug.Narration, fmt.Sprintf("[WRAP-UP] %d turns left", turnsLeft))
+	return narration.WithWrapUp(ctx, turnsLeft)
+}
+
+// endSessionIfLimited runs the ending once a finished turn has used up the session:
+// input stops and a short summary is shown. The returned command saves the game; any
+// key quits once it has.
+func (m *Model) endSessionIfLimited() tea.Cmd {
+	if m.sessionEnded || !m.sessionLimit.Reached(m.limitTurns, m.sessionElapsed(time.Now())) {
+		return nil
+	}
+	m.sessionEnded = true
+	m.queuedInput = ""
+	elapsed := m.sessionElapsed(time.Now()).Round(time.Minute)
+	m.messages = append(m.messages,
+		"— The End —",
+		"",
+		fmt.Sprintf("You played %d turns over %s and explored %d places.", m.limitTurns, elapsed, len(m.world.VisitedLocations)),
+	)
+	m.messages = append(m.messages, "", "Press any key to leave.")
+	m.loggers.Debug.Printf("Session limit reached after %d turns (%s)", m.limitTurns, elapsed)
+	if m.mcpClient == nil {
+		return nil
+	}
+	name := "session-end-" + time.Now().Format("20060102-150405")
+	return m.snapshotCmd(save.SavePath(name), name, "session.end")
+}
+
+// sessionLimitIndicator counts down the turns left, shown after the input during the
+// wrap-up.
+func (m Model) sessionLimitIndicator() string {
+	if m.sessionEnded {
+		return ""
+	}
+	elapsed := m.sessionElapsed(time.Now())
+	turnsLeft, ok := m.sessionLimit.TurnsLeft(m.limitTurns, elapsed)
+	if !ok || turnsLeft > m.sessionLimit.WrapUpTurns {
+		return ""
+	}
+	label := fmt.Sprintf("%d turns left", turnsLeft)
+	if turnsLeft <= 1 {
+		label = "last turn"
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Render("  [" + label + "]")
+}
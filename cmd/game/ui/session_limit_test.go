@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+)
+
+func TestSessionLimitWrapUpAndEnd(t *testing.T) {
+	m := newTestModel(t)
+	m.SetSessionLimit(game.SessionLimit{MaxTurns: 4, WrapUpTurns: 2})
+	if m.sessionElapsed(time.Now()) != 0 {
+		t.Error("the clock runs before the first turn")
+	}
+
+	start := time.Now()
+	wantIndicator := []string{"", "2 turns left", "last turn", "last turn"}
+	for turn := 1; turn <= 4; turn++ {
+		m.countLimitedTurn(start)
+		if !m.limitStart.Equal(start) || m.limitTurns != turn {
+			t.Fatalf("turn %d: counted %d from %v", turn, m.limitTurns, m.limitStart)
+		}
+		if turn < 4 {
+			m.endSessionIfLimited()
+			if m.sessionEnded {
+				t.Fatalf("session ended after turn %d of 4", turn)
+			}
+		}
+		if got := m.sessionLimitIndicator(); !strings.Contains(got, wantIndicator[turn-1]) || (wantIndicator[turn-1] == "") != (got == "") {
+			t.Errorf("after turn %d: indicator %q, want %q", turn, got, wantIndicator[turn-1])
+		}
+	}
+
+	m.queuedInput = "look"
+	m.endSessionIfLimited()
+	if !m.sessionEnded || m.queuedInput != "" {
+		t.Fatalf("ended %v, queued %q after the last turn", m.sessionEnded, m.queuedInput)
+	}
+	transcript := strings.Join(m.messages, "\n")
+	for _, want := range []string{"— The End —", "You played 4 turns", "explored 1 places", "Press any key to leave."} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("ending lacks %q:\n%s", want, transcript)
+		}
+	}
+	if m.sessionLimitIndicator() != "" {
+		t.Error("indicator still shown after the end")
+	}
+	shown := len(m.messages)
+	m.endSessionIfLimited()
+	if len(m.messages) != shown {
+		t.Error("the ending ran twice")
+	}
+
+	m.input = "go north"
+	_, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("a key after the end did nothing")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("a key after the end does not quit")
+	}
+}
+
+func TestWithWrapUp(t *testing.T) {
+	m := newTestModel(t)
+	m.SetSessionLimit(game.SessionLimit{MaxTurns: 10, WrapUpTurns: 3})
+	m.limitStart, m.limitTurns = time.Now(), 7
+	ctx := context.Background()
+	if got := m.withWrapUp(ctx); got != ctx {
+		t.Error("3 turns left is not yet the wrap-up")
+	}
+	m.limitTurns = 8
+	if got := m.withWrapUp(ctx); got == ctx {
+		t.Error("2 turns left should be the wrap-up")
+	}
+
+	unlimited := newTestModel(t)
+	unlimited.countLimitedTurn(time.Now())
+	if unlimited.limitTurns != 0 || !unlimited.limitStart.IsZero() {
+		t.Error("turns counted without a session limit")
+	}
+	if got := unlimited.withWrapUp(ctx); got != ctx {
+		t.Error("a session without a limit wraps up")
+	}
+}
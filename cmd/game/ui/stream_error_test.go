@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+
+	"textadventure/internal/game/narration"
+)
+
+// failingStream streams a single chunk of narration and then fails with err.
+func failingStream(err error) *ssestream.Stream[openai.ChatCompletionChunk] {
+	chunk := `data: {"id":"mock","object":"chat.completion.chunk","model":"mock","choices":[{"index":0,"delta":{"content":"The lamp flickers"}}]}` + "\n\n"
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       io.NopCloser(io.MultiReader(strings.NewReader(chunk), &errReader{err: err})),
+	}
+	return ssestream.NewStream[openai.ChatCompletionChunk](ssestream.NewDecoder(res), nil)
+}
+
+type errReader struct{ err error }
+
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestStreamErrorsStayOutOfHistory(t *testing.T) {
+	tests := []struct {
+		name  string
+		phase string
+		start func(t *testing.T, m Model, err error) Model
+	}{
+		{"stream fails to start", "narration", func(t *testing.T, m Model, err error) Model {
+			updated, _ := m.Update(narration.StreamErrorMsg{Err: err})
+			return updated.(Model)
+		}},
+		{"stream fails mid-narration", "narration.stream", func(t *testing.T, m Model, err error) Model {
+			updated, cmd := m.Update(narration.StreamStartedMsg{Stream: failingStream(err)})
+			// Keep reading, as the program would, until the stream is done with
+			for cmd != nil {
+				updated, cmd = updated.Update(cmd())
+			}
+			return updated.(Model)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.beginTurn(turnEventIntro)
+			before := len(m.gameHistory.GetEntries())
+
+			m = tt.start(t, m, errors.New("connection reset by peer"))
+
+			if m.turnPhase != AwaitingInput || m.isStreaming() {
+				t.Errorf("phase %v, streaming %v after the error", m.turnPhase, m.isStreaming())
+			}
+			entries := m.gameHistory.GetEntries()
+			if len(entries) != before {
+				t.Errorf("history grew from %d to %d entries: %q", before, len(entries), entries)
+			}
+			for _, entry := range entries {
+				if strings.Contains(entry, "[ERROR]") || strings.Contains(entry, "connection reset") {
+					t.Errorf("history entry carries the error: %q", entry)
+				}
+			}
+			if len(m.sessionErrors) != 1 || m.sessionErrors[0].Phase != tt.phase || !strings.Contains(m.sessionErrors[0].Message, "connection reset by peer") {
+				t.Errorf("session errors = %+v", m.sessionErrors)
+			}
+			if !strings.Contains(strings.Join(m.messages, "\n"), "[ERROR] ") {
+				t.Error("the error was not shown to the player")
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package ui
+
+import "fmt"
+
+// reportStrict shows a fallback strict mode refused, in red whether or not debug output
+// is on, and records it with the session's errors.
+func (m *Model) reportStrict(phase string, err error) {
+	m.messages = append(m.messages, colorize(colorRed, fmt.Sprintf("[STRICT] %v", err)))
+	m.recordSessionError(phase, err.Error())
+}
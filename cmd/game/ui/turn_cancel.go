@@ -0,0 +1,27 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// cancelCurrentTurn abandons the turn in flight: its context is cancelled so pending
+// director, NPC and narration calls stop, and the player gets the input back. Mutations
+// that already landed stay; the next turn resyncs the world if any did. The returned
+// command writes a bookmark requested during the turn.
+func (m *Model) cancelCurrentTurn() tea.Cmd {
+	m.abandonTurn(turnEventCancelled, "cancelled")
+	m.messages = append(m.messages, "(cancelled)", "")
+	return m.holdTurnsFor(m.flushPendingBookmark())
+}
+
+// abandonTurn stops the turn in flight without letting it finish: its context is
+// cancelled, the NPCs still to act are dropped and the turn ends with reason.
+func (m *Model) abandonTurn(event turnEvent, reason string) {
+	if m.cancelTurn != nil {
+		m.cancelTurn()
+		m.cancelTurn = nil
+	}
+	m.currentResponse = ""
+	m.npcQueue = nil
+	m.npcPendingActions = nil
+	m.npcBatchPending = 0
+	m.finishTurn(event, reason)
+}
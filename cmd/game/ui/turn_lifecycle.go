@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// turnEvent is something that happens to the turn in flight. Handlers report events
+// rather than setting the phase, so every phase change goes through turnTransitions.
+type turnEvent int
+
+const (
+	turnEventPlayerInput    turnEvent = iota // the player submitted an action
+	turnEventIntro                           // the awakening intro, narrated without a player action
+	turnEventPlayerResolved                  // the director applied the player's action
+	turnEventNPCsDone                        // every queued NPC has acted
+	turnEventNarrationDone                   // the narration stream completed
+	turnEventFailed                          // the turn hit an error it can't continue past
+	turnEventCancelled                       // the player cancelled the turn
+)
+
+func (e turnEvent) String() string {
+	switch e {
+	case turnEventPlayerInput:
+		return "player_input"
+	case turnEventIntro:
+		return "intro"
+	case turnEventPlayerResolved:
+		return "player_resolved"
+	case turnEventNPCsDone:
+		return "npcs_done"
+	case turnEventNarrationDone:
+		return "narration_done"
+	case turnEventFailed:
+		return "failed"
+	case turnEventCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+type turnTransition struct {
+	from  TurnPhase
+	event turnEvent
+}
+
+// turnTransitions is the turn lifecycle. Failed and cancelled are also accepted from
+// any phase but AwaitingInput; see advanceTurn.
+var turnTransitions = map[turnTransition]TurnPhase{
+	{AwaitingInput, turnEventPlayerInput}: PlayerTurn,
+	{AwaitingInput, turnEventIntro}:       Narration,
+	{PlayerTurn, turnEventPlayerResolved}: NPCTurns,
+	{NPCTurns, turnEventNPCsDone}:         Narration,
+	{Narration, turnEventNarrationDone}:   AwaitingInput,
+}
+
+// nextPhase returns the phase an event leads to from the given phase, if the event is
+// legal there.
+func nextPhase(from TurnPhase, event turnEvent) (TurnPhase, bool) {
+	if next, ok := turnTransitions[turnTransition{from, event}]; ok {
+		return next, true
+	}
+	if (event == turnEventFailed || event == turnEventCancelled) && from != AwaitingInput {
+		return AwaitingInput, true
+	}
+	return from, false
+}
+
+// advanceTurn applies an event to the turn phase. An illegal event is logged and leaves
+// the phase unchanged.
+func (m *Model) advanceTurn(event turnEvent) bool {
+	next, ok := nextPhase(m.turnPhase, event)
+	if !ok {
+		m.loggers.Debug.Errorf("illegal turn transition: %s in %s", event, m.turnPhase)
+		return false
+	}
+	m.turnPhase = next
+	return true
+}
+
+// canBeginTurn reports whether event may start a turn now: it must be legal from the
+// current phase, no turn span may still be open, no save may be loading and the last
+// turn's world writes must have landed. A violation is logged so the caller can drop
+// whatever was about to start the turn before touching any state.
+func (m *Model) canBeginTurn(event turnEvent) bool {
+	if m.loadPending {
+		m.loggers.Debug.Errorf("refusing to begin turn: %s while a save is loading", event)
+		return false
+	}
+	if m.writesPending {
+		m.loggers.Debug.Errorf("refusing to begin turn: %s while the last turn's writes are in flight", event)
+		return false
+	}
+	if _, ok := nextPhase(m.turnPhase, event); !ok {
+		m.loggers.Debug.Errorf("refusing to begin turn: %s in %s", event, m.turnPhase)
+		return false
+	}
+	if m.turnSpan != nil {
+		m.loggers.Debug.Errorf("refusing to begin turn: %s while turn %d is still open", event, m.turnIndex)
+		return false
+	}
+	return true
+}
+
+// beginTurn starts a turn: the phase leaves AwaitingInput and a turn span opens. The
+// returned command is the turn's watchdog, to run alongside the turn's first step.
+func (m *Model) beginTurn(event turnEvent) (tea.Cmd, bool) {
+	if !m.canBeginTurn(event) || !m.advanceTurn(event) {
+		return nil, false
+	}
+	m.startTurn()
+	return m.armWatchdog(), true
+}
+
+// finishTurn ends the turn in flight: the phase returns to AwaitingInput, the narration
+// stream (if any) is let go and its span and the turn span are closed with reason.
+func (m *Model) finishTurn(event turnEvent, reason string) bool {
+	if !m.advanceTurn(event) {
+		return false
+	}
+	m.releaseStream(reason)
+	m.npcTurnInFlight = false
+	m.endTurn(reason)
+	return true
+}
+
+// isStreaming reports whether narration is streaming into the pane.
+func (m Model) isStreaming() bool {
+	return m.activeStream != nil
+}
+
+// attachStream makes a narration stream the one feeding the pane. There is only ever
+// one: a stream that arrives outside narration, or while another is active, is refused.
+func (m *Model) attachStream(stream *ssestream.Stream[openai.ChatCompletionChunk], span trace.Span) bool {
+	if m.turnPhase != Narration || m.activeStream != nil {
+		m.loggers.Debug.Errorf("refusing narration stream in %s (stream active: %t)", m.turnPhase, m.activeStream != nil)
+		return false
+	}
+	m.activeStream = stream
+	m.streamSpan = span
+	return true
+}
+
+// isActiveStream reports whether a stream message belongs to the stream feeding the pane.
+func (m Model) isActiveStream(stream *ssestream.Stream[openai.ChatCompletionChunk]) bool {
+	return stream != nil && stream == m.activeStream
+}
+
+// detachStream forgets the active stream once it has completed. Its reader has already
+// closed it, and the caller finishes its span.
+func (m *Model) detachStream() {
+	m.activeStream = nil
+	m.streamSpan = nil
+}
+
+// releaseStream drops a stream that didn't complete and closes its span with reason.
+// The stream itself is closed by its reader, which stops once the turn context is
+// cancelled.
+func (m *Model) releaseStream(reason string) {
+	if m.activeStream == nil {
+		return
+	}
+	if m.streamSpan != nil {
+		m.streamSpan.SetStatus(codes.Error, reason)
+		m.streamSpan.End()
+	}
+	m.detachStream()
+}
+
+// checkTurnInvariants runs after every message. It logs any state the turn lifecycle
+// should never reach and repairs what it can, so a missed transition can't leave the
+// game stuck or a span open.
+func (m *Model) checkTurnInvariants() {
+	var problems []string
+	if m.activeStream != nil && m.turnPhase != Narration {
+		problems = append(problems, "narration stream active in "+m.turnPhase.String())
+		m.releaseStream("invariant")
+	}
+	if m.npcTurnInFlight && m.turnPhase != NPCTurns {
+		problems = append(problems, "NPC turn in flight in "+m.turnPhase.String())
+		m.npcTurnInFlight = false
+	}
+	if m.turnPhase == AwaitingInput && m.turnSpan != nil {
+		problems = append(problems, "turn span open while awaiting input")
+		m.endTurn("abandoned")
+	}
+	if m.turnPhase != AwaitingInput && m.turnSpan == nil {
+		problems = append(problems, "no turn span in "+m.turnPhase.String())
+	}
+	if len(problems) > 0 {
+		m.loggers.Debug.Errorf("turn invariant violated: %s", strings.Join(problems, "; "))
+	}
+}
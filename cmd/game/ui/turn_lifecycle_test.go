@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var allPhases = []TurnPhase{AwaitingInput, PlayerTurn, NPCTurns, Narration}
+
+var allTurnEvents = []turnEvent{
+	turnEventPlayerInput, turnEventIntro, turnEventPlayerResolved, turnEventNPCsDone,
+	turnEventNarrationDone, turnEventFailed, turnEventCancelled,
+}
+
+func TestNextPhaseTable(t *testing.T) {
+	// Every legal transition; any pair not listed must be refused.
+	legal := map[turnTransition]TurnPhase{
+		{AwaitingInput, turnEventPlayerInput}: PlayerTurn,
+		{AwaitingInput, turnEventIntro}:       Narration,
+		{PlayerTurn, turnEventPlayerResolved}: NPCTurns,
+		{NPCTurns, turnEventNPCsDone}:         Narration,
+		{Narration, turnEventNarrationDone}:   AwaitingInput,
+		{PlayerTurn, turnEventFailed}:         AwaitingInput,
+		{NPCTurns, turnEventFailed}:           AwaitingInput,
+		{Narration, turnEventFailed}:          AwaitingInput,
+		{PlayerTurn, turnEventCancelled}:      AwaitingInput,
+		{NPCTurns, turnEventCancelled}:        AwaitingInput,
+		{Narration, turnEventCancelled}:       AwaitingInput,
+	}
+	for _, from := range allPhases {
+		for _, event := range allTurnEvents {
+			want, wantOK := legal[turnTransition{from, event}]
+			if !wantOK {
+				want = from
+			}
+			got, ok := nextPhase(from, event)
+			if got != want || ok != wantOK {
+				t.Errorf("%s in %s = %s, %v; want %s, %v", event, from, got, ok, want, wantOK)
+			}
+		}
+	}
+}
+
+// recordSpans routes the UI's spans to an in-memory recorder for the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func endedTurnSpans(recorder *tracetest.SpanRecorder) []sdktrace.ReadOnlySpan {
+	var turns []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "game.turn" {
+			turns = append(turns, span)
+		}
+	}
+	return turns
+}
+
+func turnEndReason(span sdktrace.ReadOnlySpan) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == "game.turn_end_reason" {
+			return attr.Value.AsString()
+		}
+	}
+	return ""
+}
+
+// Each path out of a turn returns to AwaitingInput with the turn span closed, its
+// stream let go and nothing left loading.
+func TestTurnExitPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  turnEvent
+		steps  []turnEvent
+		finish turnEvent
+		reason string
+	}{
+		{"player turn completes", turnEventPlayerInput, []turnEvent{turnEventPlayerResolved, turnEventNPCsDone}, turnEventNarrationDone, "completed"},
+		{"intro completes", turnEventIntro, nil, turnEventNarrationDone, "completed"},
+		{"director fails", turnEventPlayerInput, nil, turnEventFailed, "error"},
+		{"NPC turn fails", turnEventPlayerInput, []turnEvent{turnEventPlayerResolved}, turnEventFailed, "error"},
+		{"narration stream fails", turnEventPlayerInput, []turnEvent{turnEventPlayerResolved, turnEventNPCsDone}, turnEventFailed, "error"},
+		{"intro stream fails", turnEventIntro, nil, turnEventFailed, "error"},
+		{"cancelled during director", turnEventPlayerInput, nil, turnEventCancelled, "cancelled"},
+		{"cancelled during NPCs", turnEventPlayerInput, []turnEvent{turnEventPlayerResolved}, turnEventCancelled, "cancelled"},
+		{"cancelled during narration", turnEventPlayerInput, []turnEvent{turnEventPlayerResolved, turnEventNPCsDone}, turnEventCancelled, "cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			m := newTestModel(t)
+			if _, ok := m.beginTurn(tt.start); !ok {
+				t.Fatal("turn did not begin")
+			}
+			if m.turnSpan == nil || !m.isLoading() {
+				t.Fatal("turn began without a span or loading")
+			}
+			for _, step := range tt.steps {
+				if !m.advanceTurn(step) {
+					t.Fatalf("%s refused in %s", step, m.turnPhase)
+				}
+			}
+			if m.turnPhase == Narration && !m.attachStream(&ssestream.Stream[openai.ChatCompletionChunk]{}, nil) {
+				t.Fatal("stream refused in narration")
+			}
+			if !m.finishTurn(tt.finish, tt.reason) {
+				t.Fatalf("%s refused in %s", tt.finish, m.turnPhase)
+			}
+
+			if m.turnPhase != AwaitingInput || m.turnSpan != nil || m.isStreaming() || m.isLoading() || m.npcTurnInFlight {
+				t.Errorf("after exit: phase %s, span open %v, streaming %v, loading %v", m.turnPhase, m.turnSpan != nil, m.isStreaming(), m.isLoading())
+			}
+			turns := endedTurnSpans(recorder)
+			if len(turns) != 1 {
+				t.Fatalf("ended turn spans = %d, want 1", len(turns))
+			}
+			if reason := turnEndReason(turns[0]); reason != tt.reason {
+				t.Errorf("turn end reason = %q, want %q", reason, tt.reason)
+			}
+			if _, ok := m.beginTurn(turnEventPlayerInput); !ok {
+				t.Error("the next turn could not begin")
+			}
+		})
+	}
+}
+
+func TestCancelCurrentTurn(t *testing.T) {
+	m := newTestModel(t)
+	m.beginTurn(turnEventPlayerInput)
+	m.advanceTurn(turnEventPlayerResolved)
+	m.npcQueue = []string{"elena"}
+	m.npcTurnInFlight = true
+	m.cancelCurrentTurn()
+	if m.turnPhase != AwaitingInput || m.turnSpan != nil || m.npcQueue != nil || m.npcTurnInFlight {
+		t.Errorf("after cancel: phase %s, span open %v, queue %v", m.turnPhase, m.turnSpan != nil, m.npcQueue)
+	}
+}
+
+func TestIllegalTransitionsLeaveThePhase(t *testing.T) {
+	m := newTestModel(t)
+	if m.advanceTurn(turnEventNarrationDone) || m.advanceTurn(turnEventFailed) || m.finishTurn(turnEventCancelled, "cancelled") {
+		t.Error("an exit was accepted with no turn in flight")
+	}
+	m.beginTurn(turnEventPlayerInput)
+	if m.advanceTurn(turnEventNarrationDone) || m.advanceTurn(turnEventNPCsDone) {
+		t.Error("a later phase's event was accepted in PlayerTurn")
+	}
+	if m.turnPhase != PlayerTurn {
+		t.Errorf("phase = %s, want PlayerTurn", m.turnPhase)
+	}
+}
+
+func TestCannotBeginTurnWhileOneIsOpen(t *testing.T) {
+	m := newTestModel(t)
+	if _, ok := m.beginTurn(turnEventPlayerInput); !ok {
+		t.Fatal("first turn did not begin")
+	}
+	index := m.turnIndex
+	if _, ok := m.beginTurn(turnEventPlayerInput); ok {
+		t.Error("a second turn began during the first")
+	}
+	// A phase wrongly reset to AwaitingInput must not start a turn over the open span.
+	m.turnPhase = AwaitingInput
+	if m.canBeginTurn(turnEventPlayerInput) {
+		t.Error("a turn may begin while the last span is still open")
+	}
+	if m.turnIndex != index {
+		t.Errorf("turn index moved to %d", m.turnIndex)
+	}
+}
+
+func TestOnlyOneStream(t *testing.T) {
+	m := newTestModel(t)
+	stream := &ssestream.Stream[openai.ChatCompletionChunk]{}
+	if m.attachStream(stream, nil) {
+		t.Error("stream attached while awaiting input")
+	}
+	m.beginTurn(turnEventIntro)
+	if !m.attachStream(stream, nil) {
+		t.Fatal("stream refused in narration")
+	}
+	if m.attachStream(&ssestream.Stream[openai.ChatCompletionChunk]{}, nil) {
+		t.Error("a second stream attached")
+	}
+	if !m.isActiveStream(stream) || m.isLoading() {
+		t.Errorf("active %v, loading %v while streaming", m.isActiveStream(stream), m.isLoading())
+	}
+	m.detachStream()
+	if m.isStreaming() || !m.isLoading() {
+		t.Errorf("streaming %v, loading %v after the stream completed", m.isStreaming(), m.isLoading())
+	}
+}
+
+func TestCheckTurnInvariantsRepairs(t *testing.T) {
+	recorder := recordSpans(t)
+	m := newTestModel(t)
+	m.beginTurn(turnEventIntro)
+	m.attachStream(&ssestream.Stream[openai.ChatCompletionChunk]{}, nil)
+	m.npcTurnInFlight = true
+	// The stuck state from a stream error that reset the phase but nothing else.
+	m.turnPhase = AwaitingInput
+	m.checkTurnInvariants()
+
+	if m.isStreaming() || m.npcTurnInFlight || m.turnSpan != nil {
+		t.Errorf("streaming %v, NPC in flight %v, span open %v after repair", m.isStreaming(), m.npcTurnInFlight, m.turnSpan != nil)
+	}
+	turns := endedTurnSpans(recorder)
+	if len(turns) != 1 || turnEndReason(turns[0]) != "abandoned" {
+		t.Errorf("ended turn spans = %d", len(turns))
+	}
+	if _, ok := m.beginTurn(turnEventPlayerInput); !ok {
+		t.Error("the next turn could not begin after the repair")
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"textadventure/internal/game"
+)
+
+// turnClassifiedMsg carries a turn record tagged by the LLM fallback.
+type turnClassifiedMsg struct {
+	record game.TurnRecord
+	err    error
+}
+
+// classifyTurnCmd tags a turn the rules found ambiguous. The turn span has ended by the
+// time it runs, so the tags go on a turn.classify span under it.
+func (m Model) classifyTurnCmd(record game.TurnRecord) tea.Cmd {
+	ctx := m.createGameContext(m.turnContext, "turn.classify")
+	classifier := m.classifier
+	return func() tea.Msg {
+		ctx, span := otel.Tracer("text-adventure-ui").Start(ctx, "turn.classify",
+			trace.WithAttributes(attribute.Int("turn.index", record.TurnIndex)),
+		)
+		defer span.End()
+		tags, err := classifier.Fallback(ctx, record)
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.SetAttributes(attribute.StringSlice("turn.tags", tags))
+		record.Tags = tags
+		return turnClassifiedMsg{record: record, err: err}
+	}
+}
+
+func (m Model) handleTurnClassified(msg turnClassifiedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.loggers.Debug.Printf("Turn %d left untagged: %v", msg.record.TurnIndex, msg.err)
+	}
+	(&m).publishTurn(msg.record)
+	return m, nil
+}
+
+func runStatsCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if m.turnCount == 0 {
+		return []string{"No completed turns yet"}, nil
+	}
+	lines := []string{fmt.Sprintf("Turn tags over %d turns:", m.turnCount)}
+	for _, tag := range m.turnTags.Sorted() {
+		count := m.turnTags[tag]
+		lines = append(lines, fmt.Sprintf("  %-12s %3d  (%d%%)", tag, count, count*100/m.turnCount))
+	}
+	return lines, nil
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:    "stats",
+		Summary: "Show what this session's turns were about",
+		Run:     runStatsCommand,
+	})
+}
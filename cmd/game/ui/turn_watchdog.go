@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// defaultTurnTimeout is how long a turn may run before the watchdog abandons it.
+const defaultTurnTimeout = 120 * time.Second
+
+// SetTurnTimeout sets how long a turn may run before the watchdog abandons it. Zero or
+// less turns the watchdog off.
+func (m *Model) SetTurnTimeout(timeout time.Duration) {
+	m.turnTimeout = timeout
+}
+
+// turnWatchdogMsg fires once a turn has run for the full timeout. It names the turn it
+// was armed for, so a tick that outlives its turn is dropped.
+type turnWatchdogMsg struct {
+	turnID string
+}
+
+// watchdogWorldMsg carries the world re-read after the watchdog abandoned a turn.
+type watchdogWorldMsg struct {
+	world game.WorldState
+	err   error
+}
+
+// armWatchdog starts the tick that abandons the current turn if it is still running
+// when the timeout runs out. Ending the turn disarms it: endTurn clears the turn ID the
+// tick is checked against.
+func (m Model) armWatchdog() tea.Cmd {
+	if m.turnTimeout <= 0 || m.turnID == "" {
+		return nil
+	}
+	turnID := m.turnID
+	return tea.Tick(m.turnTimeout, func(time.Time) tea.Msg {
+		return turnWatchdogMsg{turnID: turnID}
+	})
+}
+
+// handleTurnWatchdog abandons a turn that is stuck: a stream that never ends, a call
+// with no deadline, a message that never arrived. Its context is cancelled and any
+// narration stream closed, so whatever is still running stops, and the player gets the
+// input back once the world has been read again.
+func (m Model) handleTurnWatchdog(msg turnWatchdogMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase == AwaitingInput || msg.turnID != m.turnID {
+		return m, nil
+	}
+	phase := m.turnPhase
+	m.loggers.Debug.Errorf("turn %s stuck in %s for %s; abandoning it", m.turnID, phase, m.turnTimeout)
+	(&m).recordSessionError("turn.watchdog", fmt.Sprintf("turn stuck in %s for %s", phase, m.turnTimeout))
+	if m.activeStream != nil {
+		m.activeStream.Close()
+	}
+	(&m).abandonTurn(turnEventFailed, "watchdog_timeout")
+	m.messages = append(m.messages,
+		colorize(colorRed, fmt.Sprintf("[ERROR] The turn stopped responding after %s and was abandoned. Reloading the world; try again.", m.turnTimeout)),
+		"")
+	bookmark := (&m).holdTurnsFor((&m).flushPendingBookmark())
+	if m.mcpClient == nil {
+		return m, bookmark
+	}
+	return m, tea.Batch(m.refetchWorldCmd(), bookmark)
+}
+
+// refetchWorldCmd reads the world again after an abandoned turn, whose mutations may
+// have landed on the server without reaching the local copy.
+func (m Model) refetchWorldCmd() tea.Cmd {
+	ctx := m.createGameContext(m.sessionContext, "world.refetch")
+	client := m.mcpClient
+	return func() tea.Msg {
+		mcpWorld, err := client.GetWorldState(ctx)
+		if err != nil {
+			return watchdogWorldMsg{err: err}
+		}
+		return watchdogWorldMsg{world: mcp.MCPToGameWorldState(mcpWorld)}
+	}
+}
+
+// handleWatchdogWorld adopts the re-read world, or leaves the next turn to resync it if
+// the read failed. A turn that started in the meantime resyncs on its own.
+func (m Model) handleWatchdogWorld(msg watchdogWorldMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		(&m).recordSessionError("world.refetch", msg.err.Error())
+		m.worldStale = true
+		return m, nil
+	}
+	if m.turnPhase != AwaitingInput {
+		m.worldStale = true
+		return m, nil
+	}
+	m.worldStale = false
+	(&m).setWorld(msg.world)
+	return m, nil
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+
+	"textadventure/internal/game/narration"
+)
+
+// hangingStream is a narration stream that never sends anything and never ends until
+// it is closed.
+func hangingStream() *ssestream.Stream[openai.ChatCompletionChunk] {
+	body, _ := io.Pipe()
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       body,
+	}
+	return ssestream.NewStream[openai.ChatCompletionChunk](ssestream.NewDecoder(res), nil)
+}
+
+func TestWatchdogRecoversFromAHungStream(t *testing.T) {
+	const ceiling = 50 * time.Millisecond
+	m, _ := withFakeWorld(t, newTestModel(t))
+	m.SetTurnTimeout(ceiling)
+	start := time.Now()
+	m.beginTurn(turnEventIntro)
+	watchdog := m.armWatchdog()
+
+	updated, read := m.Update(narration.StreamStartedMsg{Stream: hangingStream()})
+	m = updated.(Model)
+	reads := make(chan tea.Msg, 1)
+	go func() { reads <- read() }()
+
+	updated, recover := m.Update(watchdog())
+	m = updated.(Model)
+	if m.turnPhase != AwaitingInput || m.isStreaming() {
+		t.Fatalf("phase %v, streaming %v after the watchdog fired", m.turnPhase, m.isStreaming())
+	}
+	if len(m.sessionErrors) != 1 || m.sessionErrors[0].Phase != "turn.watchdog" {
+		t.Errorf("session errors = %+v", m.sessionErrors)
+	}
+
+	// Closing the stream unblocks its reader; what it reports is stale and dropped.
+	select {
+	case msg := <-reads:
+		messages := len(m.messages)
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+		if len(m.messages) != messages || len(m.sessionErrors) != 1 {
+			t.Errorf("the abandoned stream's %T reached the player", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the hung stream was not closed")
+	}
+
+	m = run(t, m, recover)
+	if elapsed := time.Since(start); elapsed < ceiling || elapsed > ceiling+time.Second {
+		t.Errorf("recovered after %s with a %s ceiling", elapsed, ceiling)
+	}
+	if m.worldStale || !strings.Contains(strings.Join(m.messages, "\n"), "stopped responding after 50ms") {
+		t.Errorf("world stale %v after recovering; messages %q", m.worldStale, m.messages)
+	}
+	if _, ok := m.beginTurn(turnEventPlayerInput); !ok {
+		t.Error("the next turn could not begin")
+	}
+}
+
+func TestWatchdogIgnoresFinishedTurns(t *testing.T) {
+	m := newTestModel(t)
+	m.SetTurnTimeout(time.Minute)
+	m.beginTurn(turnEventIntro)
+	tick := turnWatchdogMsg{turnID: m.turnID}
+	m.finishTurn(turnEventNarrationDone, "narration_complete")
+	m.beginTurn(turnEventPlayerInput)
+
+	updated, cmd := m.handleTurnWatchdog(tick)
+	if m = updated.(Model); cmd != nil || m.turnPhase == AwaitingInput || len(m.sessionErrors) != 0 {
+		t.Error("a tick from an earlier turn abandoned the current one")
+	}
+	if m.SetTurnTimeout(0); m.armWatchdog() != nil {
+		t.Error("the watchdog was armed while turned off")
+	}
+}
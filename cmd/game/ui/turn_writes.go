@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// turnWrite is a world-state write held until the end of the turn, with the change
+// that mirrors it in the local world.
+type turnWrite struct {
+	call    mcp.ToolCall
+	subject string                       // the location, item or NPC written to, for reporting
+	apply   func(world *game.WorldState) // nil when nothing is kept locally
+	// npcID is the NPC a memory or fact write is about. Such writes are stamped with the
+	// NPC's location and the world version when queued, and dropped at the flush if the
+	// world has since lost the NPC; see resolveNPCLocation.
+	npcID        string
+	location     string
+	worldVersion uint64
+}
+
+// queueWrite holds a write for the turn's flush. Memory and fact writes made during a
+// turn all wait for narration to finish, so nothing the turn's later steps read from
+// the server changes under them.
+func (m *Model) queueWrite(call mcp.ToolCall, subject string, apply func(world *game.WorldState)) {
+	m.pendingWrites = append(m.pendingWrites, turnWrite{call: call, subject: subject, apply: apply, worldVersion: m.worldVersion})
+}
+
+// queueNPCWrite holds a write about an NPC for the turn's flush; see turnWrite.npcID.
+func (m *Model) queueNPCWrite(call mcp.ToolCall, npcID string, apply func(world *game.WorldState)) {
+	m.pendingWrites = append(m.pendingWrites, turnWrite{
+		call: call, subject: npcID, apply: apply,
+		npcID: npcID, location: m.world.NPCs[npcID].Location, worldVersion: m.worldVersion,
+	})
+}
+
+// queueNPCMemory holds an NPC's thought and action for the turn's flush.
+func (m *Model) queueNPCMemory(npcID, thought, action string) {
+	if m.mcpClient == nil {
+		return
+	}
+	turn := m.turnIndex
+	m.queueNPCWrite(m.mcpClient.NPCMemoryCall(npcID, thought, action, turn), npcID, func(world *game.WorldState) {
+		npc, exists := world.NPCs[npcID]
+		if !exists {
+			return
+		}
+		if thought != "" {
+			npc.RecentThoughts = append(npc.RecentThoughts, game.NPCMemoryEntry{Text: thought, Turn: turn})
+		}
+		if action != "" {
+			npc.RecentActions = append(npc.RecentActions, game.NPCMemoryEntry{Text: action, Turn: turn})
+		}
+		world.NPCs[npcID] = npc
+	})
+}
+
+// flushTurnWrites sends the held writes to the server in one batch, in the order they
+// were queued, from a command so narration's end doesn't wait on the server. Each write's
+// local update applies when its result arrives, in handleTurnWritesFlushed. Tools the
+// server doesn't provide are skipped and their local updates apply at once, as do all of
+// them without a server. Writes queued after a turn ends, e.g. by NPC narration that
+// arrives late, wait for the next turn's flush.
+func (m *Model) flushTurnWrites() tea.Cmd {
+	writes := m.pendingWrites
+	m.pendingWrites = nil
+	var sent, local []turnWrite
+	for _, write := range writes {
+		if write.npcID != "" {
+			if _, ok := m.resolveNPCLocation(write.npcID, write.location, write.worldVersion); !ok {
+				m.loggers.Debug.Printf("Dropped %s for %s: the NPC is no longer in the world", write.call.Tool, write.npcID)
+				continue
+			}
+		}
+		if m.mcpClient != nil && m.mcpClient.HasTool(write.call.Tool) {
+			sent = append(sent, write)
+			continue
+		}
+		if m.mcpClient != nil {
+			m.loggers.Debug.Printf("Skipped %s for %s: not provided by the server", write.call.Tool, write.subject)
+		}
+		local = append(local, write)
+	}
+	m.applyWrites(local)
+	if len(sent) == 0 {
+		return nil
+	}
+
+	if m.turnSpan != nil {
+		m.turnSpan.SetAttributes(attribute.Int("world.writes", len(sent)))
+	}
+	client := m.mcpClient
+	ctx := m.createGameContext(m.sessionContext, "world.flush_writes")
+	return func() tea.Msg {
+		calls := make([]mcp.ToolCall, len(sent))
+		for i, write := range sent {
+			calls[i] = write.call
+		}
+		return turnWritesFlushedMsg{writes: sent, results: client.CallTools(ctx, calls)}
+	}
+}
+
+// turnWritesFlushedMsg carries the server's result for each write a flush sent, in order.
+type turnWritesFlushedMsg struct {
+	writes  []turnWrite
+	results []mcp.ToolResult
+}
+
+// handleTurnWritesFlushed reports each write's result and applies the local updates of
+// the ones the server took. A failed write is reported on its own, doesn't stop the rest
+// and isn't applied locally, so the local world keeps agreeing with the server.
+func (m Model) handleTurnWritesFlushed(msg turnWritesFlushedMsg) (tea.Model, tea.Cmd) {
+	var applied []turnWrite
+	var failed int
+	for i, result := range msg.results {
+		write := msg.writes[i]
+		if result.Err != nil {
+			failed++
+			m.debugLog(debug.Facts, colorize(colorRed, fmt.Sprintf("[ERROR] %s failed for %s: %v", result.Call.Tool, write.subject, result.Err)))
+			continue
+		}
+		m.loggers.Debug.Printf("%s for %s: %s", result.Call.Tool, write.subject, result.Response)
+		applied = append(applied, write)
+	}
+	(&m).applyWrites(applied)
+	if failed > 0 {
+		m.loggers.Debug.Errorf("%d of %d world writes failed", failed, len(msg.results))
+	}
+	return m, nil
+}
+
+// applyWrites makes the writes' local updates, in order.
+func (m *Model) applyWrites(writes []turnWrite) {
+	if !slices.ContainsFunc(writes, func(write turnWrite) bool { return write.apply != nil }) {
+		return
+	}
+	// Copy before editing so in-flight commands keep the snapshot they were dispatched with
+	m.world = m.world.Clone()
+	for _, write := range writes {
+		if write.apply != nil {
+			write.apply(&m.world)
+		}
+	}
+	m.bumpWorldVersion()
+}
+
+// holdTurnsFor runs cmds, the world writes and snapshots a finished turn leaves behind,
+// one after another, and holds the next turn until the last of them has landed, so no
+// turn plans against a half-written world and no snapshot captures a half-applied turn.
+// Input entered meanwhile is queued.
+func (m *Model) holdTurnsFor(cmds ...tea.Cmd) tea.Cmd {
+	cmds = slices.DeleteFunc(cmds, func(cmd tea.Cmd) bool { return cmd == nil })
+	if len(cmds) == 0 {
+		return nil
+	}
+	m.writesPending = true
+	return tea.Sequence(append(cmds, func() tea.Msg { return turnWritesLandedMsg{} })...)
+}
+
+// turnWritesLandedMsg follows the last of the commands holdTurnsFor ran.
+type turnWritesLandedMsg struct{}
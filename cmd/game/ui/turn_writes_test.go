@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// withFakeWorld puts a fake world server, started from the model's world, behind m.
+func withFakeWorld(t *testing.T, m Model) (Model, *mcp.FakeWorldClient) {
+	t.Helper()
+	fake := mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(m.world))
+	m.mcpClient = fake.WorldStateClient
+	return m, fake
+}
+
+// flush runs the model's turn write flush and delivers its result, as the program would.
+func flush(t *testing.T, m Model) Model {
+	t.Helper()
+	cmd := (&m).flushTurnWrites()
+	if cmd == nil {
+		t.Fatal("flush returned no command")
+	}
+	updated, _ := m.Update(cmd())
+	return updated.(Model)
+}
+
+func fakeTools(fake *mcp.FakeWorldClient) []string {
+	var tools []string
+	for _, call := range fake.Calls() {
+		tools = append(tools, call.Tool)
+	}
+	return tools
+}
+
+func TestFlushTurnWritesSendsInQueueOrder(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	(&m).queueNPCMemory("elena", "Someone else is awake.", "listens at the door")
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", nil)
+	(&m).queueWrite(mcp.NPCFactsCall("elena", []string{"Elena hums when nervous"}), "elena", nil)
+
+	cmd := (&m).flushTurnWrites()
+	if cmd == nil {
+		t.Fatal("flush returned no command")
+	}
+	if len(fake.Calls()) != 0 {
+		t.Fatalf("flush called the server before its command ran: %v", fakeTools(fake))
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+
+	want := []string{"update_npc_memory", "add_location_facts", "add_npc_facts"}
+	if got := fakeTools(fake); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+	world := fake.World()
+	if got := world.Locations["foyer"].Facts; !slices.Contains(got, "The floor is cold marble") {
+		t.Errorf("server foyer facts = %q", got)
+	}
+	if got := m.world.NPCs["elena"].RecentThoughts; len(got) != 1 || got[0].Text != "Someone else is awake." {
+		t.Errorf("local thoughts = %v", got)
+	}
+}
+
+func TestFlushTurnWritesOnce(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", nil)
+	m = flush(t, m)
+	if cmd := (&m).flushTurnWrites(); cmd != nil {
+		t.Error("second flush with nothing queued returned a command")
+	}
+	if got := len(fake.Calls()); got != 1 {
+		t.Errorf("server got %d calls, want 1", got)
+	}
+
+	// Writes queued after the flush wait for the next one
+	(&m).queueWrite(mcp.NPCFactsCall("elena", []string{"Elena hums when nervous"}), "elena", nil)
+	if got := len(fake.Calls()); got != 1 {
+		t.Fatalf("a late write reached the server before the next flush")
+	}
+	flush(t, m)
+	if got := fakeTools(fake); !slices.Equal(got, []string{"add_location_facts", "add_npc_facts"}) {
+		t.Errorf("calls = %v", got)
+	}
+}
+
+func TestFlushTurnWritesReportsEachFailure(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	fake.FailTool("add_location_facts", errors.New("disk full"))
+	var applied []string
+	apply := func(subject string) func(*game.WorldState) {
+		return func(*game.WorldState) { applied = append(applied, subject) }
+	}
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", apply("foyer"))
+	(&m).queueWrite(mcp.ItemFactsCall("lamp", []string{"dented"}), "lamp", apply("lamp"))
+	(&m).queueNPCMemory("elena", "Someone else is awake.", "")
+
+	cmd := (&m).flushTurnWrites()
+	msg, ok := cmd().(turnWritesFlushedMsg)
+	if !ok {
+		t.Fatalf("flush reported %T", msg)
+	}
+	var failed []string
+	for i, result := range msg.results {
+		if result.Err != nil {
+			failed = append(failed, msg.writes[i].subject)
+		}
+	}
+	if !slices.Equal(failed, []string{"foyer", "lamp"}) {
+		t.Errorf("failed writes = %v, want foyer and lamp", failed)
+	}
+
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if len(applied) != 0 {
+		t.Errorf("failed writes applied locally: %v", applied)
+	}
+	if got := m.world.NPCs["elena"].RecentThoughts; len(got) != 1 {
+		t.Errorf("write after the failures not applied: %v", got)
+	}
+}
+
+func TestFlushTurnWritesWithoutServerApplyLocally(t *testing.T) {
+	m := newTestModel(t)
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", func(world *game.WorldState) {
+		loc := world.Locations["foyer"]
+		loc.Facts = append(loc.Facts, "The floor is cold marble")
+		world.Locations["foyer"] = loc
+	})
+	if cmd := (&m).flushTurnWrites(); cmd != nil {
+		t.Error("flush without a server returned a command")
+	}
+	if got := m.world.Locations["foyer"].Facts; !slices.Contains(got, "The floor is cold marble") {
+		t.Errorf("local foyer facts = %q", got)
+	}
+}
+
+func TestTurnWaitsForWritesToLand(t *testing.T) {
+	m, _ := withFakeWorld(t, newTestModel(t))
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", nil)
+	cmd := (&m).holdTurnsFor((&m).flushTurnWrites())
+	if cmd == nil || !m.writesPending {
+		t.Fatal("holdTurnsFor did not hold the next turn")
+	}
+	if m.canBeginTurn(turnEventPlayerInput) {
+		t.Error("a turn may begin while writes are in flight")
+	}
+	m = enter(t, m, "look")
+	if m.queuedInput != "look" {
+		t.Fatalf("input during writes not queued: %q", m.queuedInput)
+	}
+
+	updated, _ := m.Update(turnWritesLandedMsg{})
+	m = updated.(Model)
+	if m.writesPending || m.queuedInput != "" || m.turnPhase == AwaitingInput {
+		t.Errorf("queued input did not start once writes landed: pending=%v queued=%q phase=%v", m.writesPending, m.queuedInput, m.turnPhase)
+	}
+}
+
+func TestPlayerConditionsAdvanceFromACommand(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	m.world.Conditions = []game.PlayerCondition{{Name: "soaked", Turns: 4}, {Name: "injured"}}
+	cmd := (&m).advancePlayerConditions()
+	if cmd == nil {
+		t.Fatal("no command for the decay")
+	}
+	if len(m.world.Conditions) != 2 || m.world.Conditions[0].Turns != 4 {
+		t.Errorf("conditions changed before the server took them: %+v", m.world.Conditions)
+	}
+	m = run(t, m, cmd)
+	want := []game.PlayerCondition{{Name: "injured"}}
+	if !slices.Equal(m.world.Conditions, want) {
+		t.Errorf("local conditions = %+v, want %+v", m.world.Conditions, want)
+	}
+	if got := fake.World().Player.Conditions; len(got) != 1 || got[0].Name != "injured" {
+		t.Errorf("server conditions = %+v", got)
+	}
+
+	// A failed sync leaves the conditions as they were
+	fake.FailTool("sync_player_conditions", errors.New("server gone"))
+	m.world.Conditions = []game.PlayerCondition{{Name: "soaked", Turns: 4}}
+	m = run(t, m, (&m).advancePlayerConditions())
+	if len(m.world.Conditions) != 1 || m.world.Conditions[0].Turns != 4 {
+		t.Errorf("conditions after a failed sync = %+v", m.world.Conditions)
+	}
+}
+
+// Locations created outdoors on the server keep the player soaked; see
+// game.AdvanceConditions.
+func TestSoakedStaysInOutdoorLocations(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	ctx := t.Context()
+	if _, err := fake.CallToolValidated(ctx, "create_location", map[string]interface{}{
+		"location_id": "garden", "name": "Overgrown Garden", "exits": map[string]interface{}{"in": "foyer"}, "outdoors": true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	mcpWorld, err := fake.GetWorldState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.world = mcp.MCPToGameWorldState(mcpWorld)
+	if !m.world.Locations["garden"].Outdoors || m.world.Locations["foyer"].Outdoors {
+		t.Fatalf("garden outdoors %v, foyer outdoors %v", m.world.Locations["garden"].Outdoors, m.world.Locations["foyer"].Outdoors)
+	}
+
+	m.world.Location = "garden"
+	m.world.Conditions = []game.PlayerCondition{{Name: "soaked", Turns: 4}}
+	m = run(t, m, (&m).advancePlayerConditions())
+	if want := []game.PlayerCondition{{Name: "soaked", Turns: 4}}; !slices.Equal(m.world.Conditions, want) {
+		t.Errorf("conditions outdoors = %+v, want %+v", m.world.Conditions, want)
+	}
+}
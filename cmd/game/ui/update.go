@@ -9,18 +9,47 @@ import (
 
     tea "github.com/charmbracelet/bubbletea"
     
+    "textadventure/internal/debug"
     "textadventure/internal/game"
     "textadventure/internal/game/actors"
     "textadventure/internal/game/director"
+    "textadventure/internal/game/events"
     "textadventure/internal/game/narration"
+    "textadventure/internal/game/translate"
     "textadventure/internal/llm"
     "go.opentelemetry.io/otel/attribute"
 )
 
+// Update dispatches msg to its handler, then starts or stops the loading animation to
+// match the phase the handler left the model in, keeps a scrolled-up chat in place,
+// picks up world-server loss, restarts or recovery and submits an action queued during
+// the turn that just finished.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	before := len(m.messages)
+	next, cmd := m.dispatch(msg)
+	model, ok := next.(Model)
+	if !ok {
+		return next, cmd
+	}
+	(&model).checkTurnInvariants()
+	(&model).followNewMessages(before)
+	(&model).syncWorldAvailability()
+	(&model).syncWorldReconnects()
+	if queuedCmd := (&model).submitQueuedInput(); queuedCmd != nil {
+		cmd = tea.Batch(cmd, queuedCmd)
+	}
+	if animationCmd := (&model).syncAnimation(); animationCmd != nil {
+		cmd = tea.Batch(cmd, animationCmd)
+	}
+	return model, cmd
+}
+
+func (m Model) dispatch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case initialLookAroundMsg:
 		return m.handleInitialLook(msg)
+	case inputNormalizedMsg:
+		return m.handleInputNormalized(msg)
 	case npcTurnMsg:
 		return m.handleNPCTurn(msg)
 	case narrationTurnMsg:
@@ -45,128 +74,246 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case npcNarrationReadyMsg:
 		return m.handleNPCNarrationReady(msg)
+	case worldRetryMsg:
+		return m.handleWorldRetry(msg)
+	case worldResyncMsg:
+		return m.handleWorldResync(msg)
+	case worldHealthTickMsg:
+		return m.handleWorldHealthTick(msg)
+	case worldHealthMsg:
+		return m.handleWorldHealth(msg)
+	case gameLoadedMsg:
+		return m.handleGameLoaded(msg)
+	case scheduledEventsMsg:
+		return m.handleScheduledEvents(msg)
+	case npcBatchResultMsg:
+		return m.handleNPCBatchResult(msg)
+	case npcTurnBatchCompleteMsg:
+		return m.handleNPCTurnBatchComplete(msg)
+	case dialogueAppliedMsg:
+		return m.handleDialogueApplied(msg)
+	case npcRepliesMsg:
+		return m.handleNPCReplies(msg)
+	case doctorResultsMsg:
+		return m.handleDoctorResults(msg)
+	case npcGoalsReviewedMsg:
+		return m.handleNPCGoalsReviewed(msg)
+	case turnClassifiedMsg:
+		return m.handleTurnClassified(msg)
+	case execResultMsg:
+		return m.handleExecResult(msg)
+	case planPreviewMsg:
+		return m.handlePlanPreview(msg)
+	case guideClassifiedMsg:
+		return m.handleGuideClassified(msg)
+	case guideAnswerMsg:
+		return m.handleGuideAnswer(msg)
+	case turnWritesFlushedMsg:
+		return m.handleTurnWritesFlushed(msg)
+	case playerConditionsAdvancedMsg:
+		return m.handlePlayerConditionsAdvanced(msg)
+	case snapshotWrittenMsg:
+		return m.handleSnapshotWritten(msg)
+	case turnWritesLandedMsg:
+		m.writesPending = false
+		return m, nil
 
 	case tea.WindowSizeMsg:
 		return m.handleWindowResize(msg)
 	case animationTickMsg:
 		return m.handleAnimation(msg)
+	case turnWatchdogMsg:
+		return m.handleTurnWatchdog(msg)
+	case watchdogWorldMsg:
+		return m.handleWatchdogWorld(msg)
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	}
 	return m, nil
 }
 
 func (m Model) handleInitialLook(msg initialLookAroundMsg) (tea.Model, tea.Cmd) {
-	if !m.loading && m.mcpClient != nil {
-		userInput := "awakening"
-		m.gameHistory.AddPlayerAction(userInput)
-		m.loading = true
-		m.animationFrame = 0
-		m.messages = append(m.messages, "LOADING_ANIMATION")
-		m.turnPhase = Narration
-		
-        (&m).startTurn()
+	if m.turnPhase == AwaitingInput && m.mcpClient != nil {
+		userInput := game.IntroInput
+		watchdog, ok := (&m).beginTurn(turnEventIntro)
+		if !ok {
+			return m, nil
+		}
         ctx := m.createGameContext(m.turnContext, "director.awakening_intro")
-        return m, tea.Batch(m.director.ProcessPlayerActionWithContext(ctx, userInput, m.world, m.gameHistory.GetEntries(), m.loggers.Completion), animationTimer())
+        return m, tea.Batch(watchdog, m.directIntent(ctx, userInput, ""))
     }
     return m, nil
 }
 
 func (m Model) handleNPCTurn(msg npcTurnMsg) (tea.Model, tea.Cmd) {
-    if m.turnPhase == NPCTurns && !m.npcTurnComplete {
-        m.npcTurnComplete = true
+    if m.turnPhase == NPCTurns && !m.npcTurnInFlight {
+        if len(m.npcQueue) == 0 {
+            return m, (&m).endNPCPhase()
+        }
+        if m.parallelNPCTurns {
+            if n := actors.IndependentPrefix(m.world, m.npcQueue); n > 1 {
+                return m, (&m).startNPCBatch(n, msg.worldEvents)
+            }
+        }
+        npcID := m.npcQueue[0]
+        m.npcQueue = m.npcQueue[1:]
+        m.npcTurnInFlight = true
         npcCtx := m.createGameContext(m.turnContext, "npc.turn")
-        return m, actors.GenerateNPCTurn(npcCtx, m.llmService, "elena", m.world, m.gameHistory.GetEntries(), m.loggers.Debug.IsEnabled(), msg.worldEventLines)
+        return m, m.stampNPCTurn(npcID, actors.GenerateNPCTurn(npcCtx, m.llmService, npcID, m.world, m.gameHistory.For(game.HistoryForNPC, npcID), m.loggers.Debug.IsEnabled(), msg.worldEvents))
     }
     return m, nil
 }
 
 func (m Model) handleNarrationTurn(msg narrationTurnMsg) (tea.Model, tea.Cmd) {
-	if !m.loading {
-        m.turnPhase = Narration
-        m.loading = true
-        m.animationFrame = 0
-        m.messages = append(m.messages, "LOADING_ANIMATION")
+	if m.turnPhase == NPCTurns {
+        (&m).advanceTurn(turnEventNPCsDone)
+        (&m).countLimitedTurn(time.Now())
         
-        ctx := m.createGameContext(m.turnContext, "narration.generate")
-        return m, narration.StartLLMStream(ctx, m.llmService, msg.userInput, msg.world, msg.gameHistory, m.loggers.Completion, msg.debug, msg.actionContext, msg.mutationResults, msg.worldEventLines)
+        ctx := (&m).withWrapUp((&m).withExperiment(m.withNarrationVoice(m.createGameContext(m.turnContext, "narration.generate"))))
+        return m, narration.StartLLMStream(ctx, m.llmService, msg.userInput, msg.world, msg.gameHistory, m.loggers.Completion, msg.debug, msg.actionContext, msg.mutationResults, msg.worldEvents)
     }
     return m, nil
 }
 
 func (m Model) handleNPCThoughts(msg actors.NPCThoughtsMsg) (tea.Model, tea.Cmd) {
 	if msg.Debug && msg.Thoughts != "" {
-		var colorCode string
-		if npc, exists := m.world.NPCs[msg.NPCID]; exists && npc.DebugColor != "" {
-			colorCode = fmt.Sprintf("\033[%sm", npc.DebugColor)
-		} else {
-			colorCode = "\033[36m"
-		}
-		
-		lines := strings.Split(msg.Thoughts, "\n")
-		for i, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				if i == 0 {
-					coloredThoughts := fmt.Sprintf("%s[%s] %s\033[0m", colorCode, strings.ToUpper(msg.NPCID), line)
-					m.messages = append(m.messages, coloredThoughts)
-				} else {
-					coloredThoughts := fmt.Sprintf("%s      %s\033[0m", colorCode, line)
-					m.messages = append(m.messages, coloredThoughts)
-				}
-			}
-		}
-		m.messages = append(m.messages, "")
+		(&m).debugLog(debug.Thoughts, append(m.npcThoughtLines(msg.NPCID, msg.Thoughts), "")...)
 	}
 	return m, nil
 }
 
 func (m Model) handleNPCAction(msg actors.NPCActionMsg) (tea.Model, tea.Cmd) {
 	if msg.Debug && msg.Thoughts != "" {
-		var colorCode string
-		if npc, exists := m.world.NPCs[msg.NPCID]; exists && npc.DebugColor != "" {
-			colorCode = fmt.Sprintf("\033[%sm", npc.DebugColor)
-		} else {
-			colorCode = "\033[36m"
-		}
-		
-		lines := strings.Split(msg.Thoughts, "\n")
-		for i, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				if i == 0 {
-					coloredThoughts := fmt.Sprintf("%s[%s] %s\033[0m", colorCode, strings.ToUpper(msg.NPCID), line)
-					m.messages = append(m.messages, coloredThoughts)
-				} else {
-					coloredThoughts := fmt.Sprintf("%s      %s\033[0m", colorCode, line)
-					m.messages = append(m.messages, coloredThoughts)
-				}
-			}
-		}
-		m.messages = append(m.messages, "")
+		(&m).debugLog(debug.Thoughts, append(m.npcThoughtLines(msg.NPCID, msg.Thoughts), "")...)
 	}
 	
-	if msg.Action != "" && !m.loading {
-		if msg.Debug {
-			actionMsg := fmt.Sprintf("\033[33m[%s ACTION] %s\033[0m", strings.ToUpper(msg.NPCID), msg.Action)
-			m.messages = append(m.messages, actionMsg)
-			m.messages = append(m.messages, "")
+	if m.turnPhase != NPCTurns {
+		return m, nil
+	}
+	if _, ok := (&m).resolveNPCLocation(msg.NPCID, msg.Location, msg.WorldVersion); !ok {
+		// The NPC left the world while it was thinking; nothing of its turn applies
+		return m, (&m).nextNPCTurnCmd()
+	}
+	if msg.StrictErr != nil {
+		(&m).reportStrict("npc.perception", msg.StrictErr)
+	}
+	if msg.Skipped {
+		(&m).debugLog(debug.Turns, colorize(colorYellow, fmt.Sprintf("[%s] far away and undisturbed; skipped", strings.ToUpper(msg.NPCID))), "")
+	}
+	persistPerception := tea.Batch(
+		(&m).applyEmotionShifts(msg.NPCID, msg.EmotionShifts),
+		(&m).recordAmbienceHeard(msg.NPCID, msg.Location, msg.AmbienceHeard),
+	)
+	if msg.Action == "" {
+		// The NPC chose to do nothing, or sat the turn out; move on to the next NPC or narration
+		return m, tea.Batch(persistPerception, (&m).nextNPCTurnCmd())
+	}
+	(&m).debugLog(debug.Thoughts, colorize(colorYellow, fmt.Sprintf("[%s ACTION] %s", strings.ToUpper(msg.NPCID), msg.Action)), "")
+	
+	(&m).queueNPCMemory(msg.NPCID, msg.Thoughts, msg.Action)
+	
+	if quote, ok := actors.ParseNPCSpeech(msg.Action); ok {
+		m.gameHistory.AddNPCSpeech(msg.NPCID, quote, m.world)
+		m.currentNPCActions = append(m.currentNPCActions, fmt.Sprintf("%s: %q", msg.NPCID, quote))
+	} else {
+		m.gameHistory.AddNPCAction(msg.NPCID, msg.Action, m.world)
+		m.currentNPCActions = append(m.currentNPCActions, fmt.Sprintf("%s: %s", msg.NPCID, msg.Action))
+	}
+	
+	// Continue current turn context
+	ctx := m.createGameContext(m.turnContext, "director.npc_action")
+	directCmd := m.directIntent(ctx, msg.Action, msg.NPCID)
+	if msg.Dialogue != nil {
+		m.dialogueOptions = append(m.dialogueOptions, msg.Dialogue.Options...)
+		directCmd = tea.Sequence(m.applyDialogueCmd(msg.NPCID, *msg.Dialogue), directCmd)
+	}
+	if persistPerception != nil {
+		// Persist first, so the director's world refresh doesn't undo what was perceived
+		directCmd = tea.Sequence(persistPerception, directCmd)
+	}
+	return m, directCmd
+}
+
+// normalizeInputCmd translates the player's input to English off the UI goroutine.
+func (m Model) normalizeInputCmd(userInput string) tea.Cmd {
+	ctx := m.createGameContext(m.turnContext, "input.translate")
+	normalizer := m.normalizer
+	return func() tea.Msg {
+		result, err := normalizer.Normalize(ctx, userInput)
+		return inputNormalizedMsg{result: result, err: err}
+	}
+}
+
+func (m Model) handleInputNormalized(msg inputNormalizedMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase != PlayerTurn {
+		return m, nil
+	}
+	if msg.err != nil {
+		// Fall back to the input as typed rather than losing the turn
+		(&m).recordSessionError("input.translate", msg.err.Error())
+	}
+	m.currentInput = msg.result
+	// Short commands like "norte" skip translation; they shouldn't reset the language
+	if !msg.result.Skipped && msg.err == nil {
+		m.playerLanguage = msg.result.Language
+	}
+	if msg.result.Language != "" {
+		(&m).debugLog(debug.Narration, fmt.Sprintf("[DEBUG] Translated from %s: %s", msg.result.Language, msg.result.Normalized), "")
+	}
+	return m, m.processPlayerInput()
+}
+
+// processPlayerInput sends the current turn's normalized input to the director,
+// resyncing the world first if the last turn left it stale and firing any scheduled
+// events that are due.
+func (m Model) processPlayerInput() tea.Cmd {
+	if m.worldStale && m.mcpClient != nil {
+		return m.resyncWorldCmd()
+	}
+	return m.fireScheduledEventsOrPlan()
+}
+
+// planPlayerInput asks the director to interpret and execute the current input.
+func (m Model) planPlayerInput() tea.Cmd {
+	ctx := m.createGameContext(m.turnContext, "director.player_input")
+	return m.directIntent(ctx, m.currentInput.Normalized, "")
+}
+
+// directIntent runs an action through the director for the player or, with npcID, an
+// NPC. A request the director refuses comes back as a failed action so the turn still
+// moves on.
+func (m Model) directIntent(ctx context.Context, action, npcID string) tea.Cmd {
+	cmd, err := m.director.ProcessIntent(action).
+		WithContext(ctx).
+		WithWorld(m.world).
+		WithHistory(m.gameHistory.For(game.HistoryForDirector, npcID)).
+		WithActor(npcID).
+		WithLogger(m.loggers.Completion).
+		Execute()
+	if err != nil {
+		world := m.world
+		return func() tea.Msg {
+			return director.MutationsGeneratedMsg{Failures: []string{err.Error()}, NewWorld: world, UserInput: action, ActingNPCID: npcID}
+		}
+	}
+	return cmd
+}
+
+// narrationTurnCmd hands the accumulated results of this turn to the narration phase.
+func (m Model) narrationTurnCmd() tea.Cmd {
+	return func() tea.Msg {
+		return narrationTurnMsg{
+			world:           m.world,
+			gameHistory:     m.gameHistory.For(game.HistoryForNarration, ""),
+			debug:           m.loggers.Debug.IsEnabled(),
+			userInput:       m.currentInput.Normalized,
+			actionContext:   m.currentActionContext,
+			mutationResults: m.currentMutationResults,
+			worldEvents:     m.accumulatedWorldEvents,
 		}
-		
-		updateMemoryCmd := m.updateNPCMemory(msg.NPCID, msg.Thoughts, msg.Action)
-		
-		m.gameHistory.AddNPCAction(msg.NPCID, msg.Action)
-		m.loading = true
-		m.animationFrame = 0
-		m.messages = append(m.messages, "LOADING_ANIMATION")
-		
-        // Continue current turn context
-        ctx := m.createGameContext(m.turnContext, "director.npc_action")
-        return m, tea.Batch(
-            updateMemoryCmd,
-            m.director.ProcessPlayerActionWithContext(ctx, msg.Action, m.world, m.gameHistory.GetEntries(), m.loggers.Completion, msg.NPCID), 
-            animationTimer(),
-        )
 	}
-	return m, nil
 }
 
 func (m Model) handleWindowResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
@@ -175,180 +322,184 @@ func (m Model) handleWindowResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleAnimation advances the loading animation. Ticks from a stopped or replaced
+// ticker are dropped without re-arming, so at most one ticker is ever live.
 func (m Model) handleAnimation(msg animationTickMsg) (tea.Model, tea.Cmd) {
-	if m.loading {
-		m.animationFrame++
-		return m, animationTimer()
+	if !m.animating || msg.id != m.animationID {
+		return m, nil
 	}
-	return m, nil
+	m.animationFrame++
+	return m, animationTimer(m.animationID)
 }
 
 func (m Model) handleStreamStarted(msg narration.StreamStartedMsg) (tea.Model, tea.Cmd) {
-	if m.loading {
-		m.messages = m.messages[:len(m.messages)-1]
-		m.streaming = true
-		m.currentResponse = ""
-		m.messages = append(m.messages, "")
+	if !(&m).attachStream(msg.Stream, msg.Span) {
+		// Not this turn's stream: stop reading it and close its span
+		msg.Stream.Close()
+		if msg.Span != nil {
+			msg.Span.End()
+		}
+		return m, nil
 	}
-	return m, narration.ReadNextChunk(msg.Stream, msg.Debug, &msg, "")
+	m.currentResponse = ""
+	if msg.Debug && len(msg.Echoes) > 0 {
+		lines := []string{"[DEBUG] Echoes of earlier events:"}
+		for _, match := range msg.Echoes {
+			lines = append(lines, fmt.Sprintf("[DEBUG]   turn %d (%.2f): %s", match.TurnIndex, match.Score, truncate(match.Text, 100)))
+		}
+		(&m).debugLog(debug.Narration, lines...)
+	}
+	(&m).beginStreamMessage()
+	return m, narration.ReadNextChunk(msg.Stream, msg.Debug, &msg)
 }
 
 func (m Model) handleStreamChunk(msg narration.StreamChunkMsg) (tea.Model, tea.Cmd) {
-	if m.streaming {
-		if msg.Debug {
-			log.Printf("DEBUG: Received chunk: %q", msg.Chunk)
-		}
-		m.currentResponse += msg.Chunk
-		if len(m.messages) > 0 {
-			m.messages[len(m.messages)-1] = m.currentResponse
-		}
+	if !m.isActiveStream(msg.Stream) {
+		// A stream from a cancelled or finished turn; stop reading it
+		msg.Stream.Close()
+		return m, nil
+	}
+	if msg.Debug {
+		log.Printf("DEBUG: Received chunk: %q", msg.Chunk)
+	}
+	m.currentResponse += msg.Chunk
+	(&m).setStreamMessage(m.currentResponse)
+	if m.scrollOffset > 0 {
+		m.unseenBelow = true
 	}
-	return m, narration.ReadNextChunk(msg.Stream, msg.Debug, msg.CompletionCtx, m.currentResponse)
+	return m, narration.ReadNextChunk(msg.Stream, msg.Debug, msg.CompletionCtx)
 }
 
 func (m Model) handleStreamComplete(msg narration.StreamCompleteMsg) (tea.Model, tea.Cmd) {
-    if m.streaming {
-        if msg.Debug {
-            log.Printf("DEBUG: Stream complete - currentResponse: %q", m.currentResponse)
-        }
-        m.streaming = false
-        m.loading = false
-        
-        if len(m.messages) > 0 && m.currentResponse != "" {
-            m.gameHistory.AddNarratorResponse(m.currentResponse)
-        }
-        
-        m.messages = append(m.messages, "")
-
-        // Finalize narration span if present
+    if !m.isActiveStream(msg.Stream) {
+        // A stream the turn already let go of, e.g. after a cancel
         if msg.Span != nil {
-            duration := time.Since(msg.StartTime)
-            msg.Span.SetAttributes(
-                attribute.String("langfuse.observation.output", m.currentResponse),
-                attribute.Int64("response_time_ms", duration.Milliseconds()),
-            )
             msg.Span.End()
         }
+        return m, nil
+    }
+    if msg.Debug {
+        log.Printf("DEBUG: Stream complete - currentResponse: %q", m.currentResponse)
+    }
+    (&m).detachStream()
+    // Chunks may have been held back while the UI was behind; the message has it all
+    if msg.Response != m.currentResponse {
+        m.currentResponse = msg.Response
+        (&m).setStreamMessage(m.currentResponse)
+    }
+    
+    if len(m.messages) > 0 && m.currentResponse != "" {
+        m.gameHistory.AddNarratorResponse(m.currentResponse, m.world)
+    }
+    
+    m.messages = append(m.messages, "")
+    (&m).showDialogueOptions()
 
-        if m.turnPhase == Narration {
-            m.extractAndAccumulateFacts(m.currentResponse)
-            
-            m.turnPhase = PlayerTurn
-            (&m).endTurn("narration_complete")
+    leaked := narration.LeakedNotes(msg.NarratorNotes, m.currentResponse)
+    if len(leaked) > 0 {
+        m.loggers.Debug.Printf("WARNING: narration repeated narrator notes verbatim: %q", leaked)
+    }
+
+    // Finalize narration span if present
+    if msg.Span != nil {
+        if len(leaked) > 0 {
+            msg.Span.SetAttributes(attribute.StringSlice("narration.leaked_notes", leaked))
         }
-        return m, nil
+        duration := time.Since(msg.StartTime)
+        msg.Span.SetAttributes(
+            attribute.String("langfuse.observation.output", m.currentResponse),
+            attribute.Int64("response_time_ms", duration.Milliseconds()),
+        )
+        msg.Span.End()
     }
-    return m, nil
+
+    recordEcho := narration.RecordEcho(m.createGameContext(m.sessionContext, "narration.echoes.record"), m.llmService, m.currentResponse)
+    (&m).recordFactUsage(m.currentResponse)
+    (&m).recordNarrationVoice(m.currentResponse)
+    m.extractAndAccumulateFacts(m.currentResponse)
+    flushWrites := (&m).flushTurnWrites()
+    m.lastNarratedTurn = m.turnIndex
+    classifyTurn := (&m).notifyTurnComplete(m.currentResponse)
+    conditions := (&m).advancePlayerConditions()
+    
+    (&m).finishTurn(turnEventNarrationDone, "narration_complete")
+    bookmark := (&m).flushPendingBookmark()
+    autosave := (&m).autosaveCampaign()
+    sessionEnd := (&m).endSessionIfLimited()
+    return m, tea.Batch(recordEcho, classifyTurn, m.npcGoalReviewCmd(), (&m).holdTurnsFor(flushWrites, conditions, bookmark, autosave, sessionEnd))
 }
 
 func (m Model) handleStreamError(msg narration.StreamErrorMsg) (tea.Model, tea.Cmd) {
-    if m.loading && !m.streaming {
-        m.messages = m.messages[:len(m.messages)-1]
+    if msg.Stream != nil && !m.isActiveStream(msg.Stream) {
+        // A stream the turn already let go of; its error is expected after a cancel
+        if msg.Span != nil {
+            msg.Span.End()
+        }
+        return m, nil
+    }
+    if m.turnPhase != Narration {
+        return m, nil
+    }
+    if !m.isStreaming() {
+        // Errors stay out of gameHistory so they never leak into later prompts
         if msg.Err != nil {
-            errorMsg := "\033[31m[ERROR] " + msg.Err.Error() + "\033[0m"
+            errorMsg := colorize(colorRed, "[ERROR] "+msg.Err.Error())
             m.messages = append(m.messages, errorMsg)
-            m.gameHistory.AddError(msg.Err)
+            (&m).recordSessionError("narration", msg.Err.Error())
         } else {
-            m.messages = append(m.messages, "\033[31m[ERROR]\033[0m "+msg.Response)
-            m.gameHistory.AddNarratorResponse(msg.Response)
+            m.messages = append(m.messages, colorize(colorRed, "[ERROR]")+" "+msg.Response)
+            (&m).recordSessionError("narration", msg.Response)
         }
         m.messages = append(m.messages, "")
-        m.loading = false
-    } else if m.streaming {
-        m.streaming = false
-        m.loading = false
-        if msg.Err != nil {
-            if len(m.messages) > 0 {
-                m.messages[len(m.messages)-1] = "\033[31m[ERROR] " + msg.Err.Error() + "\033[0m"
-            }
-            m.messages = append(m.messages, "")
-        }
+    } else if msg.Err != nil {
+        (&m).recordSessionError("narration.stream", msg.Err.Error())
+        (&m).setStreamMessage(colorize(colorRed, "[ERROR] "+msg.Err.Error()))
+        m.messages = append(m.messages, "")
     }
+    (&m).finishTurn(turnEventFailed, "narration_error")
     return m, nil
 }
 
 func (m Model) handleMutationsGenerated(msg director.MutationsGeneratedMsg) (tea.Model, tea.Cmd) {
-	if m.loading {
-		m.messages = m.messages[:len(m.messages)-1]
-		m.world = msg.NewWorld
-		
-		if msg.Debug && len(msg.Mutations) > 0 {
-			actorLabel := "PLAYER"
-			if msg.ActingNPCID != "" {
-				actorLabel = strings.ToUpper(msg.ActingNPCID)
-			}
-			
-			mutationHeader := fmt.Sprintf("\033[35m[%s MUTATIONS]\033[0m", actorLabel)
-			m.messages = append(m.messages, mutationHeader)
-			
-			for _, mutation := range msg.Mutations {
-				if !strings.HasPrefix(mutation, "[MUTATIONS]") {
-					coloredMutation := fmt.Sprintf("\033[35m  %s\033[0m", mutation)
-					m.messages = append(m.messages, coloredMutation)
-				}
-			}
+	if msg.Cancelled {
+		if len(msg.Successes) > 0 {
+			m.worldStale = true
 		}
-		
-		if len(msg.Failures) > 0 && msg.Debug {
-			for _, failure := range msg.Failures {
-				coloredError := fmt.Sprintf("\033[31m  [ERROR] %s\033[0m", failure)
-				m.messages = append(m.messages, coloredError)
-			}
+		return m, nil
+	}
+	if m.turnPhase != AwaitingInput {
+		before := m.world
+		(&m).setWorld(msg.NewWorld)
+		(&m).trackWorldConsistency(msg)
+		(&m).recordInvariantViolations(msg.Invariants)
+		for _, err := range msg.StrictErrors {
+			(&m).reportStrict("director", err)
 		}
 		
-        if msg.Debug && len(msg.WorldEventLines) > 0 {
-            actorLabel := "PLAYER"
-            if msg.ActingNPCID != "" {
-                actorLabel = strings.ToUpper(msg.ActingNPCID)
-            }
-            
-            header := fmt.Sprintf("\033[36m[%s WORLD EVENTS]\033[0m", actorLabel)
-            m.messages = append(m.messages, header)
-            for _, line := range msg.WorldEventLines {
-                eventMsg := fmt.Sprintf("\033[36m  %s\033[0m", line)
-                m.messages = append(m.messages, eventMsg)
-            }
-        }
-		
-        if msg.Debug && (len(msg.Mutations) > 0 || len(msg.WorldEventLines) > 0) {
-            m.messages = append(m.messages, "")
-        }
+		if msg.Debug {
+			(&m).showDirectorResults(msg, before)
+		}
         
-        m.accumulatedWorldEvents = append(m.accumulatedWorldEvents, msg.WorldEventLines...)
+        m.accumulatedWorldEvents = append(m.accumulatedWorldEvents, msg.WorldEvents...)
         m.currentMutationResults = append(m.currentMutationResults, msg.Successes...)
+        m.currentFailures = append(m.currentFailures, msg.Failures...)
         m.currentActionContext = msg.ActionContext
 		
 		if m.turnPhase == Narration {
-			m.messages = append(m.messages, "LOADING_ANIMATION")
-			
             // Narration uses world events (omniscient view) for this turn
-            narrCtx := m.createGameContext(m.turnContext, "narration.generate")
-            return m, narration.StartLLMStream(narrCtx, m.llmService, msg.UserInput, m.world, m.gameHistory.GetEntries(), m.loggers.Completion, m.loggers.Debug.IsEnabled(), msg.ActionContext, msg.Successes, msg.WorldEventLines, msg.ActingNPCID)
+            narrCtx := (&m).withExperiment(m.withNarrationVoice(m.createGameContext(m.turnContext, "narration.generate")))
+            return m, narration.StartLLMStream(narrCtx, m.llmService, msg.UserInput, m.world, m.gameHistory.For(game.HistoryForNarration, ""), m.loggers.Completion, m.loggers.Debug.IsEnabled(), msg.ActionContext, msg.Successes, msg.WorldEvents, msg.ActingNPCID)
         } else {
-            m.loading = false
-            
             switch m.turnPhase {
             case PlayerTurn:
-                m.turnPhase = NPCTurns
-                m.npcTurnComplete = false
-                // Compute perceptions for NPC in next step
-                return m, npcTurnCmd(msg.WorldEventLines)
+                if len(msg.Addressed) > 0 && m.llmService != nil {
+                    // NPCs the player spoke to answer before anyone takes a turn
+                    return m, m.npcRepliesCmd(msg.Addressed)
+                }
+                return m, (&m).startNPCTurns()
             case NPCTurns:
-                m.loading = false
-                
-                return m, func() tea.Cmd {
-                    return func() tea.Msg {
-                        return narrationTurnMsg{
-                            world:            m.world,
-                            gameHistory:      m.gameHistory.GetEntries(),
-                            debug:            m.loggers.Debug.IsEnabled(),
-                            userInput:        m.currentUserInput,
-                            actionContext:    m.currentActionContext,
-                            mutationResults:  m.currentMutationResults,
-                            worldEventLines:  m.accumulatedWorldEvents,
-                        }
-                    }
-                }()
+                narrate := (&m).narrateNPCTurn(msg)
+                return m, tea.Batch(narrate, (&m).nextNPCTurnCmd())
             default:
 				return m, nil
 			}
@@ -357,113 +508,166 @@ func (m Model) handleMutationsGenerated(msg director.MutationsGeneratedMsg) (tea
 	return m, nil
 }
 
+// showDirectorResults logs an action's mutations, failures, world events and the goal
+// changes it caused, mirroring each to the chat pane by category.
+func (m *Model) showDirectorResults(msg director.MutationsGeneratedMsg, before game.WorldState) {
+	actorLabel := "PLAYER"
+	if msg.ActingNPCID != "" {
+		actorLabel = strings.ToUpper(msg.ActingNPCID)
+	}
+	shown := len(m.messages)
+	if len(msg.Mutations) > 0 {
+		lines := []string{colorize(colorMagenta, fmt.Sprintf("[%s MUTATIONS]", actorLabel))}
+		for _, mutation := range msg.Mutations {
+			if !strings.HasPrefix(mutation, "[MUTATIONS]") {
+				lines = append(lines, colorize(colorMagenta, "  "+mutation))
+			}
+		}
+		m.debugLog(debug.Mutations, lines...)
+	}
+	for _, failure := range msg.Failures {
+		m.debugLog(debug.Mutations, colorize(colorRed, fmt.Sprintf("  [ERROR] %s", failure)))
+	}
+	if len(msg.WorldEvents) > 0 {
+		lines := []string{colorize(colorCyan, fmt.Sprintf("[%s WORLD EVENTS]", actorLabel))}
+		for _, event := range msg.WorldEvents {
+			lines = append(lines, colorize(colorCyan, "  "+event.Line()))
+		}
+		m.debugLog(debug.Events, lines...)
+	}
+	m.debugLog(debug.NPCState, goalChangeLines(before, m.world)...)
+	if len(m.messages) > shown {
+		m.messages = append(m.messages, "")
+	}
+}
+
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.briefing != nil && msg.String() != "ctrl+c" {
+		return m.dismissBriefing()
+	}
+	if m.sessionEnded {
+		if m.writesPending {
+			// The ended session is still being saved
+			return m, nil
+		}
+		return m, tea.Quit
+	}
+	if (&m).handleScrollKey(msg.String()) {
+		return m, nil
+	}
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return m, tea.Quit
 
 	case "enter":
-		if strings.TrimSpace(m.input) != "" && !m.loading {
-			userInput := m.input
-			m.input = ""
-			
-			if m.loggers.Debug.IsEnabled() && strings.HasPrefix(userInput, "/") {
-            // Ensure spacing before the player's submitted prompt for readability
-            m.messages = append(m.messages, "")
-            m.messages = append(m.messages, "> "+userInput)
-				switch strings.ToLower(userInput) {
-				case "/worldstate", "/world", "/debug":
-					worldInfo := fmt.Sprintf("[DEBUG] Current World State:")
-					m.messages = append(m.messages, worldInfo)
-					m.messages = append(m.messages, fmt.Sprintf("[DEBUG] Player Location: %s", m.world.Location))
-					m.messages = append(m.messages, fmt.Sprintf("[DEBUG] Player Inventory: %v", m.world.Inventory))
-					m.messages = append(m.messages, fmt.Sprintf("[DEBUG] Available Locations: %v", getLocationList(m.world)))
-					for locID, loc := range m.world.Locations {
-						m.messages = append(m.messages, fmt.Sprintf("[DEBUG] %s: %s (Facts: %v, Exits: %v)", locID, loc.Name, loc.Facts, loc.Exits))
-					}
-				case "/help":
-					m.messages = append(m.messages, "[DEBUG] Available commands:")
-					m.messages = append(m.messages, "[DEBUG] /worldstate - Show current world state")
-					m.messages = append(m.messages, "[DEBUG] /help - Show this help")
-				default:
-					m.messages = append(m.messages, "[DEBUG] Unknown command. Try /help")
-				}
-				m.messages = append(m.messages, "")
-				return m, nil
-			}
-			
-			m.messages = append(m.messages, "")
-			m.messages = append(m.messages, "> "+userInput)
-			m.messages = append(m.messages, "")
-			m.gameHistory.AddPlayerAction(userInput)
-			m.currentUserInput = userInput
-			m.accumulatedWorldEvents = []string{}
-			m.currentMutationResults = []string{}
-			m.loading = true
-			m.animationFrame = 0
-			m.messages = append(m.messages, "LOADING_ANIMATION")
-			m.turnPhase = PlayerTurn
-			
-            // Start a new turn span and context
-            (&m).startTurn()
-            ctx := m.createGameContext(m.turnContext, "director.player_input")
-            return m, tea.Batch(m.director.ProcessPlayerActionWithContext(ctx, userInput, m.world, m.gameHistory.GetEntries(), m.loggers.Completion), animationTimer())
-        }
-        return m, nil
+		if strings.TrimSpace(m.input) == "" {
+			return m, nil
+		}
+		userInput := m.input
+		(&m).clearInput()
+		if (&m).isDuplicateSubmit(userInput, time.Now()) {
+			return m, nil
+		}
+		if m.turnPhase != AwaitingInput || m.guidePending || m.loadPending || m.writesPending {
+			m.queuedInput = userInput
+			return m, nil
+		}
+		return m, (&m).submitInput(userInput)
 
-	case "backspace":
-		if len(m.input) > 0 && !m.loading {
-			m.input = m.input[:len(m.input)-1]
+	case "esc":
+		if m.queuedInput != "" {
+			m.queuedInput = ""
+			return m, nil
+		}
+		if m.turnPhase != AwaitingInput {
+			return m, (&m).cancelCurrentTurn()
 		}
 		return m, nil
 
 	default:
-		if len(msg.String()) == 1 && !m.loading {
-			m.input += msg.String()
-		}
+		(&m).handleEditKey(msg)
 		return m, nil
 	}
 }
 
-func (m Model) updateNPCMemory(npcID, thoughts, action string) tea.Cmd {
-	return func() tea.Msg {
-		if m.mcpClient == nil {
-			return nil
-		}
-		
-		ctx := context.Background()
-		_, err := m.mcpClient.UpdateNPCMemory(ctx, npcID, thoughts, action)
-		if err != nil && m.loggers.Debug.IsEnabled() {
-			m.loggers.Debug.Printf("Failed to update NPC memory for %s: %v", npcID, err)
-		}
-		
+// submitInput starts a turn for the player's input, or runs it as a slash command or,
+// for a question about the game, asks the guide.
+func (m *Model) submitInput(userInput string) tea.Cmd {
+	m.scrollToBottom()
+	if slashCommands.Handles(userInput, m.loggers.Debug.IsEnabled()) {
+		// Ensure spacing before the player's submitted prompt for readability
+		m.messages = append(m.messages, "")
+		m.messages = append(m.messages, "> "+userInput)
+		lines, cmd := slashCommands.Dispatch(m, userInput, m.loggers.Debug.IsEnabled())
+		m.messages = append(m.messages, lines...)
+		m.messages = append(m.messages, "")
+		return cmd
+	}
+	if m.worldUnavailable {
+		m.messages = append(m.messages, "", "> "+userInput, "The world is unavailable. Use /save-local, /retry-connection or /quit.", "")
 		return nil
 	}
+	return m.routeInput(userInput)
+}
+
+// startPlayerTurn starts a turn for the player's input.
+// A turn already in flight means the input raced it; it is dropped before the
+// history or the chat pane see it, so the running turn's state is left alone.
+func (m *Model) startPlayerTurn(userInput string) tea.Cmd {
+	if !m.canBeginTurn(turnEventPlayerInput) {
+		m.loggers.Debug.Printf("dropped player input %q: a turn is already in flight", userInput)
+		return nil
+	}
+	m.messages = append(m.messages, "")
+	m.messages = append(m.messages, "> "+userInput)
+	m.messages = append(m.messages, "")
+	m.gameHistory.AddPlayerAction(userInput, m.world)
+	m.currentUserInput = userInput
+	m.accumulatedWorldEvents = []events.WorldEvent{}
+	m.currentMutationResults = []string{}
+	m.currentFailures = []string{}
+	m.currentNPCActions = []string{}
+	m.dialogueOptions = nil
+	m.currentInput = translate.Result{Original: userInput, Normalized: userInput, Skipped: true}
+	// Start a new turn span and context
+	watchdog, ok := m.beginTurn(turnEventPlayerInput)
+	if !ok {
+		return nil
+	}
+	if m.normalizer != nil {
+		return tea.Batch(watchdog, m.normalizeInputCmd(userInput))
+	}
+	return tea.Batch(watchdog, m.processPlayerInput())
 }
 
 // npcNarrationReadyMsg carries NPC-perspective narration back to the UI for optional display and fact extraction.
 type npcNarrationReadyMsg struct {
     NPCID     string
     Narration string
+    // Location and WorldVersion describe the world the narration was generated against.
+    Location     string
+    WorldVersion uint64
 }
 
 // generateNPCNarration creates a tea.Cmd that generates a short NPC-perspective narration
 // and returns it as a message. It does not affect loading/spinner states.
-func (m Model) generateNPCNarration(npcID string, worldEventLines []string, actionContext string, mutationResults []string) tea.Cmd {
+func (m Model) generateNPCNarration(npcID string, worldEvents []events.WorldEvent, actionContext string, mutationResults []string) tea.Cmd {
+    worldVersion := m.worldVersion
+    location := m.world.NPCs[npcID].Location
     return func() tea.Msg {
-        worldCtx := game.BuildWorldContext(m.world, []string{}, npcID)
-        systemPrompt := narration.BuildNPCNarrationPrompt(npcID, actionContext, mutationResults, worldEventLines)
+        ctx := m.createGameContext(m.sessionContext, "npc.narration")
+        worldCtx := game.CachedWorldContext(ctx, m.world, []string{}, npcID)
+        systemPrompt := narration.BuildNPCNarrationPrompt(npcID, actionContext, mutationResults, worldEvents)
         req := llm.TextCompletionRequest{
             SystemPrompt: systemPrompt,
-            UserPrompt:   worldCtx + "NPC ACTION: " + strings.ToUpper(npcID),
+            UserPrompt:   worldCtx + "NPC ACTION: " + game.RefID(npcID),
             MaxTokens:    2000,
         }
-        ctx := m.createGameContext(m.sessionContext, "npc.narration")
         text, err := m.llmService.CompleteText(ctx, req)
         if err != nil {
-            return npcNarrationReadyMsg{NPCID: npcID, Narration: ""}
+            return npcNarrationReadyMsg{NPCID: npcID, Narration: "", Location: location, WorldVersion: worldVersion}
         }
-        return npcNarrationReadyMsg{NPCID: npcID, Narration: strings.TrimSpace(text)}
+        return npcNarrationReadyMsg{NPCID: npcID, Narration: strings.TrimSpace(text), Location: location, WorldVersion: worldVersion}
     }
 }
 
@@ -472,27 +676,29 @@ func (m Model) handleNPCNarrationReady(msg npcNarrationReadyMsg) (tea.Model, tea
         return m, nil
     }
     if m.loggers.Debug.IsEnabled() {
-        var colorCode string
-        if npc, ok := m.world.NPCs[msg.NPCID]; ok && npc.DebugColor != "" {
-            colorCode = fmt.Sprintf("\033[%sm", npc.DebugColor)
-        } else {
-            colorCode = "\033[36m"
-        }
-        header := fmt.Sprintf("%s[%s NARRATION]\033[0m", colorCode, strings.ToUpper(msg.NPCID))
-        m.messages = append(m.messages, header)
+        colorCode := m.npcColor(msg.NPCID)
+        lines := []string{colorize(colorCode, fmt.Sprintf("[%s NARRATION]", strings.ToUpper(msg.NPCID)))}
         for _, line := range strings.Split(msg.Narration, "\n") {
             if s := strings.TrimSpace(line); s != "" {
-                m.messages = append(m.messages, colorCode+"  "+s+"\033[0m")
+                lines = append(lines, colorize(colorCode, "  "+s))
             }
         }
-        m.messages = append(m.messages, "")
+        (&m).debugLog(debug.Narration, append(lines, "")...)
     }
-    if npc, ok := m.world.NPCs[msg.NPCID]; ok {
-        m.extractAndAccumulateFactsForLocation(npc.Location, msg.Narration)
+    if locationID, ok := (&m).resolveNPCLocation(msg.NPCID, msg.Location, msg.WorldVersion); ok {
+        m.extractAndAccumulateFactsForLocation(msg.NPCID, locationID, msg.Narration)
     }
     return m, nil
 }
 
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
+
 func getLocationList(world game.WorldState) []string {
 	var locations []string
 	for locID := range world.Locations {
@@ -7,8 +7,11 @@ import (
 )
 
 func (m Model) View() string {
-	inputHeight := 3
-	chatHeight := m.height - inputHeight
+	if m.briefing != nil {
+		return m.renderBriefing()
+	}
+	maxMessages := m.chatCapacity()
+	chatHeight := maxMessages + 2
 	rightWidth := m.width
 
 	messageStyle := lipgloss.NewStyle().
@@ -33,15 +36,7 @@ func (m Model) View() string {
 
 	var chatContent strings.Builder
 	
-	visibleMessages := m.messages
-	maxMessages := chatHeight - 2
-	if maxMessages < 1 {
-		maxMessages = 1
-	}
-	
-	if len(visibleMessages) > maxMessages {
-		visibleMessages = visibleMessages[len(visibleMessages)-maxMessages:]
-	}
+	visibleMessages := m.visibleWindow(m.renderedMessages(), maxMessages)
 
 	paddingLines := maxMessages - len(visibleMessages)
 	if paddingLines > 0 {
@@ -67,7 +62,9 @@ func (m Model) View() string {
 		} else if strings.HasPrefix(message, "[DEBUG] ") {
 			wrappedText := wrapAndIndent(message, contentWidth, " ")
 			chatContent.WriteString(debugStyle.Render(wrappedText) + "\n")
-		} else if message == "LOADING_ANIMATION" {
+		} else if message == newMessagesIndicator {
+			chatContent.WriteString(loadingStyle.Render(wrapAndIndent(message, contentWidth, " ")) + "\n")
+		} else if message == loadingRowSentinel {
 			animationText := getLoadingAnimation(m.animationFrame)
 			wrappedText := wrapAndIndent(animationText, contentWidth, " ")
 			chatContent.WriteString(loadingStyle.Render(wrappedText) + "\n")
@@ -78,8 +75,21 @@ func (m Model) View() string {
 	}
 
 	chat := chatPanel.Render(chatContent.String())
-	input := inputStyle.Render(m.input + "│")
+	input := inputStyle.Render(m.renderInputLine() + m.queuedIndicator() + m.sessionLimitIndicator())
 
+	if m.worldUnavailable {
+		text := worldUnavailableBanner
+		if m.width > 3 {
+			text = truncate(text, m.width-3)
+		}
+		banner := lipgloss.NewStyle().
+			Width(m.width).
+			Background(lipgloss.Color("1")).
+			Foreground(lipgloss.Color("15")).
+			Bold(true).
+			Render(text)
+		return chat + "\n" + banner + "\n" + input
+	}
 	return chat + "\n" + input
 }
 
@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+	"textadventure/internal/save"
+)
+
+// worldUnavailableBanner is shown across the screen while the world-state server is
+// unreachable.
+const worldUnavailableBanner = "WORLD UNAVAILABLE — the world-state server stopped responding. /save-local · /retry-connection · /quit"
+
+// worldHealthInterval is how often an idle game pings the world-state server, so a
+// crashed server is restarted before the player's next action needs it.
+const worldHealthInterval = 30 * time.Second
+
+// worldHealthTickMsg is due when the next health check should run.
+type worldHealthTickMsg struct{}
+
+// worldHealthMsg carries the outcome of a health check.
+type worldHealthMsg struct {
+	err error
+}
+
+// worldRetryMsg carries the outcome of /retry-connection.
+type worldRetryMsg struct {
+	world game.WorldState
+	err   error
+}
+
+// syncWorldAvailability enters or leaves the world-unavailable state to match the
+// client. It runs after every message, like syncAnimation.
+func (m *Model) syncWorldAvailability() {
+	if m.mcpClient == nil {
+		return
+	}
+	unavailable := m.mcpClient.Unavailable()
+	if unavailable == m.worldUnavailable {
+		return
+	}
+	m.worldUnavailable = unavailable
+	event := "world.available"
+	if unavailable {
+		event = "world.unavailable"
+		m.recordSessionError("world", mcp.ErrWorldUnavailable.Error())
+		m.messages = append(m.messages,
+			colorize(colorRed, "The world-state server is unavailable and reconnecting failed. Turns are paused."),
+			"Use /save-local to snapshot the local world, /retry-connection to try again, or /quit.",
+			"")
+	} else {
+		m.messages = append(m.messages, "Connection to the world restored. Play on.", "")
+	}
+	if m.sessionSpan != nil {
+		m.sessionSpan.AddEvent(event, trace.WithAttributes(attribute.Int("turn_index", m.turnIndex)))
+		m.sessionSpan.SetAttributes(attribute.Bool("world.unavailable", unavailable))
+	}
+}
+
+// syncWorldReconnects reports server restarts the client made since the last message:
+// a debug line each time, and the count so far this turn on the turn span.
+func (m *Model) syncWorldReconnects() {
+	if m.mcpClient == nil {
+		return
+	}
+	attempts := m.mcpClient.ReconnectAttempts()
+	if attempts == m.worldReconnects {
+		return
+	}
+	m.debugLog(debug.World, fmt.Sprintf("[DEBUG] Lost the world-state server; restarted it (%d restarts this session)", attempts))
+	m.worldReconnects = attempts
+	if m.turnSpan != nil {
+		m.turnSpan.SetAttributes(attribute.Int("world.reconnect_attempts", attempts-m.turnReconnectBase))
+	}
+}
+
+func worldHealthTick() tea.Cmd {
+	return tea.Tick(worldHealthInterval, func(time.Time) tea.Msg {
+		return worldHealthTickMsg{}
+	})
+}
+
+// handleWorldHealthTick pings the server between turns. During a turn the turn's own
+// tool calls notice a lost connection, and once the world is unavailable recovery is
+// left to /retry-connection.
+func (m Model) handleWorldHealthTick(msg worldHealthTickMsg) (tea.Model, tea.Cmd) {
+	if m.mcpClient == nil {
+		return m, nil
+	}
+	if m.turnPhase != AwaitingInput || m.worldUnavailable {
+		return m, worldHealthTick()
+	}
+	ctx := m.createGameContext(m.sessionContext, "world.health_check")
+	client := m.mcpClient
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		return worldHealthMsg{err: client.HealthCheck(ctx)}
+	}
+}
+
+func (m Model) handleWorldHealth(msg worldHealthMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil && !errors.Is(msg.err, mcp.ErrWorldUnavailable) {
+		(&m).debugLog(debug.World, fmt.Sprintf("[DEBUG] World-state health check failed: %v", msg.err))
+	}
+	return m, worldHealthTick()
+}
+
+// retryConnectionCmd restarts the world-state server and reads the world back.
+func (m Model) retryConnectionCmd() tea.Cmd {
+	ctx := m.createGameContext(m.sessionContext, "world.reconnect")
+	client := m.mcpClient
+	return func() tea.Msg {
+		if err := client.Reconnect(ctx); err != nil {
+			return worldRetryMsg{err: err}
+		}
+		mcpWorld, err := client.GetWorldState(ctx)
+		if err != nil {
+			return worldRetryMsg{err: err}
+		}
+		return worldRetryMsg{world: mcp.MCPToGameWorldState(mcpWorld)}
+	}
+}
+
+func (m Model) handleWorldRetry(msg worldRetryMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.messages = append(m.messages, fmt.Sprintf("Still unavailable: %v", msg.err), "")
+		return m, nil
+	}
+	(&m).setWorld(msg.world)
+	return m, nil
+}
+
+// saveLocalWorld snapshots the local world copy without asking the server, for when
+// the server is gone. Door states and other server-only details are not included.
+func (m *Model) saveLocalWorld(name string) (string, error) {
+	if name == "" {
+		name = "local-" + time.Now().Format("20060102-150405")
+	}
+	if err := save.ValidateName(name); err != nil {
+		return "", err
+	}
+	snapshot := save.Snapshot{
+		Metadata: save.Metadata{
+			Name:         name,
+			SessionID:    m.sessionID,
+			TurnIndex:    m.turnIndex,
+			CreatedAt:    time.Now(),
+			BranchedFrom: m.branchedFrom,
+		},
+		World:   mcp.GameToMCPWorldState(m.world),
+		History: m.gameHistory.GetEntries(),
+	}
+	path := save.SavePath(name)
+	if err := save.Write(path, snapshot); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func runSaveLocalCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	path, err := m.saveLocalWorld(name)
+	if err != nil {
+		return []string{fmt.Sprintf("Save failed: %v", err)}, nil
+	}
+	return []string{fmt.Sprintf("Local world saved to %s (load with --load %s)", path, path)}, nil
+}
+
+func runRetryConnectionCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	if !m.worldUnavailable {
+		return []string{"The world-state server is connected"}, nil
+	}
+	return []string{"Reconnecting to the world-state server..."}, m.retryConnectionCmd()
+}
+
+func runQuitCommand(m *Model, args []string) ([]string, tea.Cmd) {
+	return []string{"Goodbye."}, tea.Quit
+}
+
+func init() {
+	slashCommands.Register(slashCommand{
+		Name:    "save-local",
+		Args:    []commandArg{{Name: "name", Optional: true}},
+		Summary: "Save the local world copy to saves/<name>.json without the server",
+		Run:     runSaveLocalCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:    "retry-connection",
+		Summary: "Restart the world-state server after it became unavailable",
+		Run:     runRetryConnectionCommand,
+	})
+	slashCommands.Register(slashCommand{
+		Name:    "quit",
+		Aliases: []string{"exit"},
+		Summary: "Quit the game",
+		Run:     runQuitCommand,
+	})
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// submit enters input and runs whatever command it starts, as the program would.
+func submit(t *testing.T, m Model, input string) Model {
+	t.Helper()
+	m.input = input
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return run(t, updated.(Model), cmd)
+}
+
+func TestWorldUnavailableUntilRetrySucceeds(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	m.width, m.height = 200, 30
+
+	// The store is gone for good: the health check loses the connection and every
+	// reconnect fails.
+	fake.Crash(errors.New("world store unreachable"))
+	updated, cmd := m.Update(worldHealthTickMsg{})
+	m = run(t, updated.(Model), cmd)
+
+	if !m.worldUnavailable {
+		t.Fatal("the world is still available after reconnecting failed")
+	}
+	if len(m.sessionErrors) != 1 || m.sessionErrors[0].Phase != "world" {
+		t.Errorf("session errors = %+v", m.sessionErrors)
+	}
+	if !strings.Contains(m.View(), "WORLD UNAVAILABLE") {
+		t.Error("no banner while the world is unavailable")
+	}
+
+	m = submit(t, m, "look around")
+	if m.turnPhase != AwaitingInput || !strings.Contains(strings.Join(m.messages, "\n"), "The world is unavailable.") {
+		t.Fatalf("a turn began while the world was unavailable (phase %v)", m.turnPhase)
+	}
+
+	m = submit(t, m, "/retry-connection")
+	if !m.worldUnavailable || !strings.Contains(strings.Join(m.messages, "\n"), "Still unavailable: ") {
+		t.Fatal("retrying reported success while the store was down")
+	}
+
+	fake.Restart()
+	m = submit(t, m, "/retry-connection")
+	if m.worldUnavailable || !strings.Contains(strings.Join(m.messages, "\n"), "Connection to the world restored") {
+		t.Fatal("retrying did not restore the world")
+	}
+	if strings.Contains(m.View(), "WORLD UNAVAILABLE") {
+		t.Error("banner still shown after the world came back")
+	}
+
+	if m = enter(t, m, "look around"); m.turnPhase == AwaitingInput {
+		t.Error("play did not resume after reconnecting")
+	}
+}
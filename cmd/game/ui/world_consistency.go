@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"textadventure/internal/debug"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+	"textadventure/internal/mcp"
+)
+
+// worldResyncMsg carries the forced refresh that starts a turn after a turn whose
+// mutations landed but whose world refresh failed.
+type worldResyncMsg struct {
+	world game.WorldState
+	err   error
+}
+
+// trackWorldConsistency marks the local world stale when a director pass changed the
+// server but couldn't read it back, and fresh again after any successful refresh.
+func (m *Model) trackWorldConsistency(msg director.MutationsGeneratedMsg) {
+	if !msg.RefreshFailed {
+		m.worldStale = false
+		return
+	}
+	if len(msg.Successes) > 0 {
+		m.worldStale = true
+	}
+}
+
+// resyncWorldCmd reads the world from the server before the director plans the turn.
+func (m Model) resyncWorldCmd() tea.Cmd {
+	ctx := m.createGameContext(m.turnContext, "world.resync")
+	client := m.mcpClient
+	return func() tea.Msg {
+		mcpWorld, err := client.GetWorldState(ctx)
+		if err != nil {
+			return worldResyncMsg{err: err}
+		}
+		return worldResyncMsg{world: mcp.MCPToGameWorldState(mcpWorld)}
+	}
+}
+
+// handleWorldResync adopts the server's world, warning in debug mode if the player
+// was somewhere other than we thought, then plans the turn either way.
+func (m Model) handleWorldResync(msg worldResyncMsg) (tea.Model, tea.Cmd) {
+	if m.turnPhase != PlayerTurn {
+		return m, nil
+	}
+	if msg.err != nil {
+		(&m).recordSessionError("world.resync", msg.err.Error())
+		return m, m.fireScheduledEventsOrPlan()
+	}
+	if msg.world.Location != m.world.Location {
+		(&m).debugLog(debug.World, colorize(colorYellow, fmt.Sprintf("[WARNING] Location drift: local %q, server %q", m.world.Location, msg.world.Location)), "")
+		if m.turnSpan != nil {
+			m.turnSpan.AddEvent("world.location_drift", trace.WithAttributes(
+				attribute.String("local", m.world.Location),
+				attribute.String("server", msg.world.Location),
+			))
+		}
+	}
+	m.worldStale = false
+	(&m).setWorld(msg.world)
+	return m, m.fireScheduledEventsOrPlan()
+}
+
+// recordInvariantViolations reports what RepairRefreshedWorld found wrong with a world
+// read back after mutations: on the turn span, in red in the debug pane, and in /errors
+// for what it couldn't repair.
+func (m *Model) recordInvariantViolations(violations []game.InvariantViolation) {
+	if len(violations) == 0 {
+		return
+	}
+	lines := []string{colorize(colorRed, "[WORLD] Invariants violated after mutations:")}
+	for _, violation := range violations {
+		lines = append(lines, colorize(colorRed, fmt.Sprintf("  %s", violation)))
+		if m.turnSpan != nil {
+			m.turnSpan.AddEvent("world.invariant_violation", trace.WithAttributes(
+				attribute.String("invariant.rule", violation.Rule),
+				attribute.String("invariant.detail", violation.Detail),
+				attribute.Bool("invariant.repaired", violation.Repaired),
+			))
+		}
+		if !violation.Repaired {
+			m.recordSessionError("world.invariants", violation.String())
+		}
+	}
+	m.debugLog(debug.World, append(lines, "")...)
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"textadventure/internal/game/actors"
+	"textadventure/internal/llm"
+	"textadventure/internal/mcp"
+)
+
+// moveNPC moves an NPC in the local world the way a director result would, leaving
+// results dispatched before it stale.
+func moveNPC(m *Model, npcID, location string) {
+	world := m.world.Clone()
+	npc := world.NPCs[npcID]
+	npc.Location = location
+	world.NPCs[npcID] = npc
+	m.setWorld(world)
+}
+
+func removeNPC(m *Model, npcID string) {
+	world := m.world.Clone()
+	delete(world.NPCs, npcID)
+	m.setWorld(world)
+}
+
+// Narration generated while Elena was in the library arrives after she walked into the
+// foyer: its facts are extracted for, and land on, the foyer.
+func TestNPCNarrationFactsFollowAMovedNPC(t *testing.T) {
+	narrationFrom := func(m Model) npcNarrationReadyMsg {
+		return npcNarrationReadyMsg{
+			NPCID:        "elena",
+			Narration:    "Elena steps into the foyer. A brass lamp sits on the sideboard.",
+			Location:     m.world.NPCs["elena"].Location,
+			WorldVersion: m.worldVersion,
+		}
+	}
+
+	t.Run("attributed", func(t *testing.T) {
+		m, fake := withFakeWorld(t, newTestModel(t))
+		mock := llm.NewMockService().
+			OnOperation("facts.extract", `["A brass lamp sits on the sideboard"]`).
+			OnOperation("facts.attribute", `{"item_facts": {"brass_lamp": ["sits on the sideboard"]}}`)
+		m.llmService = mock
+		msg := narrationFrom(m)
+		if msg.Location != "library" {
+			t.Fatalf("elena starts in %s", msg.Location)
+		}
+		moveNPC(&m, "elena", "foyer")
+		if _, err := fake.MoveNPC(t.Context(), "elena", "foyer"); err != nil {
+			t.Fatal(err)
+		}
+
+		updated, _ := m.handleNPCNarrationReady(msg)
+		m = updated.(Model)
+		if calls := mock.Calls(); len(calls) == 0 || !strings.HasPrefix(calls[0].UserPrompt, "Location: Foyer\n") {
+			t.Errorf("facts not extracted for the foyer: %+v", calls)
+		}
+		m = flush(t, m)
+		world := fake.World()
+		if lamp, ok := world.Items["brass_lamp"]; !ok || lamp.Location != "foyer" {
+			t.Errorf("lamp created at %q (%v), want the foyer", lamp.Location, ok)
+		}
+	})
+
+	t.Run("unattributed", func(t *testing.T) {
+		m := newTestModel(t)
+		m.llmService = llm.NewMockService().
+			OnOperation("facts.extract", `["A draft comes from the north window"]`).
+			FailOperation("facts.attribute", errors.New("model unavailable"))
+		msg := narrationFrom(m)
+		moveNPC(&m, "elena", "foyer")
+
+		updated, _ := m.handleNPCNarrationReady(msg)
+		m = updated.(Model)
+		if !slices.Contains(m.world.Locations["foyer"].Facts, "A draft comes from the north window") {
+			t.Errorf("foyer facts = %q", m.world.Locations["foyer"].Facts)
+		}
+		if len(m.world.Locations["library"].Facts) != 0 {
+			t.Errorf("facts landed on the room elena left: %q", m.world.Locations["library"].Facts)
+		}
+	})
+
+	t.Run("NPC gone", func(t *testing.T) {
+		m := newTestModel(t)
+		mock := llm.NewMockService()
+		m.llmService = mock
+		msg := narrationFrom(m)
+		removeNPC(&m, "elena")
+
+		updated, _ := m.handleNPCNarrationReady(msg)
+		m = updated.(Model)
+		if calls := mock.Calls(); len(calls) != 0 {
+			t.Errorf("facts extracted for an NPC no longer in the world: %+v", calls)
+		}
+	})
+}
+
+func TestNPCActionIsResolvedAgainstTheCurrentWorld(t *testing.T) {
+	m := newTestModel(t)
+	m.turnPhase = NPCTurns
+	turn := m.stampNPCTurn("elena", func() tea.Msg {
+		return actors.NPCActionMsg{NPCID: "elena", AmbienceHeard: "rain drumming on the skylight"}
+	})
+	msg := turn().(actors.NPCActionMsg)
+	if msg.Location != "library" || msg.WorldVersion != m.worldVersion {
+		t.Fatalf("stamped %s v%d, want library v%d", msg.Location, msg.WorldVersion, m.worldVersion)
+	}
+
+	// The ambience is the library's, where elena perceived it, though she has moved on
+	moveNPC(&m, "elena", "foyer")
+	updated, _ := m.handleNPCAction(msg)
+	m = updated.(Model)
+	heard := m.world.NPCs["elena"].AmbienceHeard
+	if heard["library"] != "rain drumming on the skylight" || heard["foyer"] != "" {
+		t.Errorf("ambience heard = %v", heard)
+	}
+
+	// An NPC the world lost while it was thinking leaves nothing behind
+	m.turnPhase = NPCTurns
+	msg = actors.NPCActionMsg{NPCID: "elena", Thoughts: "I should hide.", Action: "hides behind the curtain", Location: "foyer", WorldVersion: m.worldVersion}
+	removeNPC(&m, "elena")
+	history := len(m.gameHistory.Entries())
+	updated, _ = m.handleNPCAction(msg)
+	m = updated.(Model)
+	if len(m.pendingWrites) != 0 || len(m.gameHistory.Entries()) != history || len(m.currentNPCActions) != 0 {
+		t.Errorf("a lost NPC's action applied: writes %d, history %d -> %d, actions %q", len(m.pendingWrites), history, len(m.gameHistory.Entries()), m.currentNPCActions)
+	}
+}
+
+func TestNPCWritesForALostNPCAreDropped(t *testing.T) {
+	m, fake := withFakeWorld(t, newTestModel(t))
+	(&m).queueNPCMemory("elena", "Someone else is awake.", "")
+	(&m).queueWrite(mcp.LocationFactsCall("foyer", []string{"The floor is cold marble"}), "foyer", nil)
+	removeNPC(&m, "elena")
+
+	m = flush(t, m)
+	if got := fakeTools(fake); !slices.Equal(got, []string{"add_location_facts"}) {
+		t.Errorf("calls = %v, want only the location's facts", got)
+	}
+	if _, ok := m.world.NPCs["elena"]; ok {
+		t.Error("dropped write brought the NPC back")
+	}
+}
@@ -0,0 +1,154 @@
+// Package artifacts gives each session its own directory for the files it writes
+// (debug log, exports and the like), keeps a "latest" pointer to the newest one, and
+// prunes old sessions.
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultRetention is how many session directories are kept when unconfigured.
+	DefaultRetention = 20
+	// LatestLink is the symlink in the sessions directory pointing at the newest session.
+	LatestLink = "latest"
+
+	sessionTimeFormat = "20060102-150405"
+)
+
+// Config locates artifacts on disk.
+type Config struct {
+	Root          string // holds sessions/ and, by default, the completions database
+	Retention     int    // session directories to keep; 0 or less keeps everything
+	CompletionsDB string // path of the global completions database
+}
+
+// LoadConfigFromEnv reads TEXTADVENTURE_DATA_DIR, SESSION_RETENTION and COMPLETIONS_DB,
+// defaulting to ~/.local/share/textadventure (or $XDG_DATA_HOME/textadventure).
+func LoadConfigFromEnv() Config {
+	cfg := Config{Root: os.Getenv("TEXTADVENTURE_DATA_DIR"), Retention: DefaultRetention}
+	if cfg.Root == "" {
+		cfg.Root = DefaultRoot()
+	}
+	if v, err := strconv.Atoi(os.Getenv("SESSION_RETENTION")); err == nil {
+		cfg.Retention = v
+	}
+	cfg.CompletionsDB = os.Getenv("COMPLETIONS_DB")
+	if cfg.CompletionsDB == "" {
+		cfg.CompletionsDB = filepath.Join(cfg.Root, "completions.db")
+	}
+	return cfg
+}
+
+// DefaultRoot returns the platform data directory for the game, falling back to a
+// directory under the working directory when there's no home directory.
+func DefaultRoot() string {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "textadventure")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".textadventure"
+	}
+	return filepath.Join(home, ".local", "share", "textadventure")
+}
+
+// SessionsDir is where per-session directories live.
+func (c Config) SessionsDir() string {
+	return filepath.Join(c.Root, "sessions")
+}
+
+// Session is one run's artifact directory.
+type Session struct {
+	Name string
+	Dir  string
+}
+
+// NewSession creates a directory named <timestamp>-<shortid> for a session started at
+// now, points the latest link at it, and prunes sessions beyond the retention limit.
+// Pointer and pruning failures don't stop the session; they're returned alongside it.
+func NewSession(cfg Config, now time.Time) (*Session, error) {
+	sessionsDir := cfg.SessionsDir()
+	name := fmt.Sprintf("%s-%s", now.Format(sessionTimeFormat), uuid.New().String()[:8])
+	session := &Session{Name: name, Dir: filepath.Join(sessionsDir, name)}
+	if err := os.MkdirAll(session.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if err := UpdateLatest(sessionsDir, name); err != nil {
+		return session, err
+	}
+	if _, err := Prune(sessionsDir, cfg.Retention); err != nil {
+		return session, err
+	}
+	return session, nil
+}
+
+// Path returns the path of a file in the session directory.
+func (s *Session) Path(name string) string {
+	return filepath.Join(s.Dir, name)
+}
+
+// UpdateLatest points sessionsDir/latest at the named session. The link is replaced
+// atomically so readers never see it missing.
+func UpdateLatest(sessionsDir, name string) error {
+	tmp := filepath.Join(sessionsDir, LatestLink+".tmp")
+	os.Remove(tmp)
+	if err := os.Symlink(name, tmp); err != nil {
+		return fmt.Errorf("failed to create latest link: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(sessionsDir, LatestLink)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to update latest link: %w", err)
+	}
+	return nil
+}
+
+// Prune removes the oldest session directories so at most keep remain, never removing
+// the one latest points at. It returns the names it removed.
+func Prune(sessionsDir string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(sessionsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	latest, _ := os.Readlink(filepath.Join(sessionsDir, LatestLink))
+
+	var sessions []string
+	for _, entry := range entries {
+		if entry.IsDir() && isSessionName(entry.Name()) {
+			sessions = append(sessions, entry.Name())
+		}
+	}
+	// Names start with a sortable timestamp, so lexical order is age order
+	sort.Strings(sessions)
+
+	var removed []string
+	for i := 0; i < len(sessions)-keep; i++ {
+		if sessions[i] == latest {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(sessionsDir, sessions[i])); err != nil {
+			return removed, fmt.Errorf("failed to remove session %s: %w", sessions[i], err)
+		}
+		removed = append(removed, sessions[i])
+	}
+	return removed, nil
+}
+
+// isSessionName guards pruning against deleting directories we didn't create.
+func isSessionName(name string) bool {
+	if len(name) <= len(sessionTimeFormat)+1 || name[len(sessionTimeFormat)] != '-' {
+		return false
+	}
+	_, err := time.Parse(sessionTimeFormat, name[:len(sessionTimeFormat)])
+	return err == nil
+}
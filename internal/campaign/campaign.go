@@ -0,0 +1,221 @@
+// Package campaign keeps one world going across many play sessions. A campaign is a
+// directory holding the world snapshot (NPC memories and the visited map included),
+// a log of every turn played in it and a chronicle with one entry per session. Only
+// one process can have a campaign open at a time.
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"textadventure/internal/game"
+	"textadventure/internal/save"
+)
+
+const (
+	// Dir is where campaigns live, relative to the project root.
+	Dir = "campaigns"
+
+	worldFile     = "world.json"
+	turnLogFile   = "turns.jsonl"
+	chronicleFile = "chronicle.md"
+	lockFile      = "campaign.lock"
+)
+
+// ErrLocked is returned by Open when another running process has the campaign open.
+var ErrLocked = errors.New("campaign is open in another process")
+
+// Campaign is an open campaign directory. It implements game.TurnSubscriber, appending
+// every turn to the campaign's turn log, and is safe for concurrent use.
+type Campaign struct {
+	name string
+	dir  string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Open opens the named campaign, creating its directory if needed, and locks it
+// against other processes until Close. A lock left behind by a process that is no
+// longer running is taken over.
+func Open(name string) (*Campaign, error) {
+	if err := save.ValidateName(name); err != nil {
+		return nil, fmt.Errorf("campaign: %w", err)
+	}
+	dir := filepath.Join(Dir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create campaign directory: %w", err)
+	}
+	c := &Campaign{name: name, dir: dir}
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name returns the campaign's name.
+func (c *Campaign) Name() string {
+	return c.name
+}
+
+// WorldPath returns the file holding the campaign's world snapshot.
+func (c *Campaign) WorldPath() string {
+	return filepath.Join(c.dir, worldFile)
+}
+
+// HasWorld reports whether the campaign has a world yet; a new campaign gets one when
+// its first session writes back.
+func (c *Campaign) HasWorld() bool {
+	_, err := os.Stat(c.WorldPath())
+	return err == nil
+}
+
+// Snapshot reads the campaign's world snapshot.
+func (c *Campaign) Snapshot() (save.Snapshot, error) {
+	return save.Read(c.WorldPath())
+}
+
+// WriteSnapshot replaces the campaign's world snapshot.
+func (c *Campaign) WriteSnapshot(snapshot save.Snapshot) error {
+	return save.Write(c.WorldPath(), snapshot)
+}
+
+// OnTurnComplete appends the turn to the campaign's turn log.
+func (c *Campaign) OnTurnComplete(record game.TurnRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal turn: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return appendFile(filepath.Join(c.dir, turnLogFile), append(data, '\n'))
+}
+
+// Session is what the chronicle records about one play session.
+type Session struct {
+	SessionID string
+	StartedAt time.Time
+	EndedAt   time.Time
+	FirstTurn int // turn index the session started from
+	LastTurn  int // turn index the session ended on
+	Location  string
+	LastScene string // the session's last narration
+}
+
+// AppendChronicle adds an entry for a finished session to the campaign chronicle.
+func (c *Campaign) AppendChronicle(session Session) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Session %s\n\n", shortID(session.SessionID))
+	fmt.Fprintf(&b, "- Played: %s to %s\n", session.StartedAt.Format("2006-01-02 15:04"), session.EndedAt.Format("15:04"))
+	fmt.Fprintf(&b, "- Turns: %d to %d\n", session.FirstTurn, session.LastTurn)
+	if session.Location != "" {
+		fmt.Fprintf(&b, "- Ended at: %s\n", session.Location)
+	}
+	if scene := strings.TrimSpace(session.LastScene); scene != "" {
+		fmt.Fprintf(&b, "\n%s\n", quote(scene))
+	}
+	b.WriteString("\n")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return appendFile(filepath.Join(c.dir, chronicleFile), []byte(b.String()))
+}
+
+// Close releases the campaign's lock. It is safe to call more than once.
+func (c *Campaign) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if err := os.Remove(filepath.Join(c.dir, lockFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release campaign lock: %w", err)
+	}
+	return nil
+}
+
+// lock creates the lockfile holding this process's PID, taking over a stale one.
+func (c *Campaign) lock() error {
+	path := filepath.Join(c.dir, lockFile)
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, writeErr := fmt.Fprintf(file, "%d\n", os.Getpid())
+			closeErr := file.Close()
+			if writeErr != nil || closeErr != nil {
+				os.Remove(path)
+				return fmt.Errorf("failed to write campaign lock: %v", errors.Join(writeErr, closeErr))
+			}
+			return nil
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to lock campaign: %w", err)
+		}
+		pid, running := lockHolder(path)
+		if running {
+			return fmt.Errorf("%w (%q, pid %d)", ErrLocked, c.name, pid)
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale campaign lock: %w", err)
+		}
+	}
+	return fmt.Errorf("%w (%q)", ErrLocked, c.name)
+}
+
+// lockHolder returns the PID in a lockfile and whether that process is still running.
+// An unreadable lock counts as stale.
+func lockHolder(path string) (int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	if pid == os.Getpid() {
+		return pid, true
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return pid, false
+	}
+	return pid, process.Signal(syscall.Signal(0)) == nil
+}
+
+func appendFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return file.Close()
+}
+
+func shortID(sessionID string) string {
+	if len(sessionID) > 8 {
+		return sessionID[:8]
+	}
+	return sessionID
+}
+
+// quote renders text as a Markdown blockquote.
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
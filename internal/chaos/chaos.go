@@ -0,0 +1,194 @@
+// Package chaos injects failures into the LLM provider and the world-state MCP
+// session so retry and recovery paths can be exercised in a real run. It is only
+// wired up when DEBUG is on.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/openai/openai-go/option"
+
+	"textadventure/internal/game/rng"
+	"textadventure/internal/mcp"
+)
+
+// ErrInjected is returned (wrapped) by every injected failure.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config holds the injection settings, normally read from CHAOS_* env vars.
+type Config struct {
+	LLMFailRate float64       // probability an LLM HTTP request fails outright
+	MCPFailRate float64       // probability an MCP tool call fails outright
+	MCPLatency  time.Duration // delay added before every MCP tool call
+	StreamDrop  bool          // cut streaming LLM responses off part-way through
+	Seed        int64         // RNG seed; 0 uses the game's "chaos" random stream
+}
+
+// LoadConfigFromEnv reads CHAOS_LLM_FAIL_RATE, CHAOS_MCP_FAIL_RATE, CHAOS_MCP_LATENCY_MS,
+// CHAOS_STREAM_DROP and CHAOS_SEED. Unparseable values are ignored.
+func LoadConfigFromEnv() Config {
+	cfg := Config{}
+	if v, err := strconv.ParseFloat(os.Getenv("CHAOS_LLM_FAIL_RATE"), 64); err == nil {
+		cfg.LLMFailRate = clampRate(v)
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("CHAOS_MCP_FAIL_RATE"), 64); err == nil {
+		cfg.MCPFailRate = clampRate(v)
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHAOS_MCP_LATENCY_MS")); err == nil && v > 0 {
+		cfg.MCPLatency = time.Duration(v) * time.Millisecond
+	}
+	drop := strings.ToLower(os.Getenv("CHAOS_STREAM_DROP"))
+	cfg.StreamDrop = drop == "1" || drop == "true"
+	if v, err := strconv.ParseInt(os.Getenv("CHAOS_SEED"), 10, 64); err == nil {
+		cfg.Seed = v
+	}
+	return cfg
+}
+
+// Enabled reports whether any injection is configured.
+func (c Config) Enabled() bool {
+	return c.LLMFailRate > 0 || c.MCPFailRate > 0 || c.MCPLatency > 0 || c.StreamDrop
+}
+
+func clampRate(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// Counts tracks how many failures have been injected so far.
+type Counts struct {
+	LLMFailures    int
+	MCPFailures    int
+	MCPDelays      int
+	StreamsDropped int
+}
+
+// Injector decides when to inject failures and counts what it injected.
+type Injector struct {
+	cfg    Config
+	mu     sync.Mutex
+	rng    *rng.Stream
+	counts Counts
+}
+
+// NewInjector creates an injector for cfg. Without its own seed it draws from the
+// game's "chaos" stream, so failures repeat for a given --seed.
+func NewInjector(cfg Config) *Injector {
+	stream := rng.For("chaos")
+	if cfg.Seed != 0 {
+		stream = rng.New(cfg.Seed, "chaos")
+	}
+	return &Injector{cfg: cfg, rng: stream}
+}
+
+// Config returns the settings the injector was created with.
+func (i *Injector) Config() Config {
+	return i.cfg
+}
+
+// Counts returns a snapshot of the injected failure counts.
+func (i *Injector) Counts() Counts {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.counts
+}
+
+// roll returns true with the given probability.
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64() < rate
+}
+
+func (i *Injector) record(update func(c *Counts)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	update(&i.counts)
+}
+
+// LLMMiddleware returns an OpenAI client middleware that fails requests at the configured
+// rate and, when StreamDrop is set, truncates event-stream responses.
+func (i *Injector) LLMMiddleware() option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		if i.roll(i.cfg.LLMFailRate) {
+			i.record(func(c *Counts) { c.LLMFailures++ })
+			return nil, fmt.Errorf("%w: llm request to %s", ErrInjected, req.URL.Path)
+		}
+		resp, err := next(req)
+		if err != nil || !i.cfg.StreamDrop || resp == nil {
+			return resp, err
+		}
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+			i.record(func(c *Counts) { c.StreamsDropped++ })
+			resp.Body = &droppingBody{ReadCloser: resp.Body, remaining: 2048}
+		}
+		return resp, nil
+	}
+}
+
+// droppingBody returns an unexpected EOF once the byte budget is spent.
+type droppingBody struct {
+	io.ReadCloser
+	remaining int
+}
+
+func (b *droppingBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, fmt.Errorf("%w: stream dropped: %w", ErrInjected, io.ErrUnexpectedEOF)
+	}
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= n
+	return n, err
+}
+
+// WrapToolCaller decorates an MCP tool caller with the configured latency and failure rate.
+// Pass it to mcp.WorldStateClient.WrapToolCaller.
+func (i *Injector) WrapToolCaller(next mcp.ToolCaller) mcp.ToolCaller {
+	return mcp.ToolCallerFunc(func(ctx context.Context, params *sdkmcp.CallToolParams) (*sdkmcp.CallToolResult, error) {
+		if i.cfg.MCPLatency > 0 {
+			i.record(func(c *Counts) { c.MCPDelays++ })
+			select {
+			case <-time.After(i.cfg.MCPLatency):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if i.roll(i.cfg.MCPFailRate) {
+			i.record(func(c *Counts) { c.MCPFailures++ })
+			return nil, fmt.Errorf("%w: mcp tool %s", ErrInjected, params.Name)
+		}
+		return next.CallTool(ctx, params)
+	})
+}
+
+// Summary renders the settings and counts for the /chaos command.
+func (i *Injector) Summary() []string {
+	counts := i.Counts()
+	return []string{
+		fmt.Sprintf("LLM fail rate: %.2f (injected: %d)", i.cfg.LLMFailRate, counts.LLMFailures),
+		fmt.Sprintf("MCP fail rate: %.2f (injected: %d)", i.cfg.MCPFailRate, counts.MCPFailures),
+		fmt.Sprintf("MCP latency: %v (delayed calls: %d)", i.cfg.MCPLatency, counts.MCPDelays),
+		fmt.Sprintf("Stream drop: %t (dropped: %d)", i.cfg.StreamDrop, counts.StreamsDropped),
+	}
+}
@@ -0,0 +1,187 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/openai/openai-go/option"
+
+	"textadventure/internal/mcp"
+)
+
+const calls = 2000
+
+func okResponse(contentType, body string) option.MiddlewareNext {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+// llmFailures sends calls requests through the injector's LLM middleware and returns
+// which of them failed.
+func llmFailures(t *testing.T, injector *Injector) []int {
+	t.Helper()
+	middleware := injector.LLMMiddleware()
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example/chat/completions", nil)
+	var failed []int
+	for n := range calls {
+		_, err := middleware(req, okResponse("application/json", "{}"))
+		if err != nil {
+			if !errors.Is(err, ErrInjected) {
+				t.Fatalf("call %d: %v is not an injected failure", n, err)
+			}
+			failed = append(failed, n)
+		}
+	}
+	return failed
+}
+
+func toolFailures(t *testing.T, injector *Injector) []int {
+	t.Helper()
+	caller := injector.WrapToolCaller(mcp.ToolCallerFunc(func(ctx context.Context, params *sdkmcp.CallToolParams) (*sdkmcp.CallToolResult, error) {
+		return &sdkmcp.CallToolResult{}, nil
+	}))
+	var failed []int
+	for n := range calls {
+		if _, err := caller.CallTool(context.Background(), &sdkmcp.CallToolParams{Name: "move_player"}); err != nil {
+			if !errors.Is(err, ErrInjected) {
+				t.Fatalf("call %d: %v is not an injected failure", n, err)
+			}
+			failed = append(failed, n)
+		}
+	}
+	return failed
+}
+
+// withinRate fails unless failed is about rate of calls: within four standard
+// deviations, far outside what a seeded run could drift by.
+func withinRate(t *testing.T, failed int, rate float64) {
+	t.Helper()
+	want := rate * calls
+	slack := 4 * math.Sqrt(calls*rate*(1-rate))
+	if math.Abs(float64(failed)-want) > slack {
+		t.Errorf("%d of %d calls failed, want %.0f ± %.0f", failed, calls, want, slack)
+	}
+}
+
+func TestDecoratorsFailAtTheConfiguredRate(t *testing.T) {
+	decorators := map[string]struct {
+		config   func(rate float64) Config
+		failures func(*testing.T, *Injector) []int
+		counted  func(Counts) int
+	}{
+		"llm": {func(rate float64) Config { return Config{LLMFailRate: rate, Seed: 7} }, llmFailures, func(c Counts) int { return c.LLMFailures }},
+		"mcp": {func(rate float64) Config { return Config{MCPFailRate: rate, Seed: 7} }, toolFailures, func(c Counts) int { return c.MCPFailures }},
+	}
+	for name, decorator := range decorators {
+		for _, rate := range []float64{0, 0.1, 0.5, 1} {
+			t.Run(fmt.Sprintf("%s at %.1f", name, rate), func(t *testing.T) {
+				injector := NewInjector(decorator.config(rate))
+				failed := decorator.failures(t, injector)
+				withinRate(t, len(failed), rate)
+				if counted := decorator.counted(injector.Counts()); counted != len(failed) {
+					t.Errorf("counted %d failures, injected %d", counted, len(failed))
+				}
+
+				// The same seed fails the same calls
+				again := decorator.failures(t, NewInjector(decorator.config(rate)))
+				if !slices.Equal(again, failed) {
+					t.Error("a second run with the same seed failed different calls")
+				}
+			})
+		}
+	}
+}
+
+func TestDifferentSeedsFailDifferentCalls(t *testing.T) {
+	first := llmFailures(t, NewInjector(Config{LLMFailRate: 0.5, Seed: 1}))
+	second := llmFailures(t, NewInjector(Config{LLMFailRate: 0.5, Seed: 2}))
+	if slices.Equal(first, second) {
+		t.Error("seeds 1 and 2 failed the same calls")
+	}
+}
+
+func TestStreamDropCutsOnlyEventStreams(t *testing.T) {
+	injector := NewInjector(Config{StreamDrop: true, Seed: 7})
+	middleware := injector.LLMMiddleware()
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example/chat/completions", nil)
+	body := strings.Repeat("data: {}\n\n", 1000)
+
+	resp, err := middleware(req, okResponse("text/event-stream", body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrInjected) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want an injected unexpected EOF", err)
+	}
+	if len(read) != 2048 {
+		t.Errorf("read %d bytes before the drop, want 2048", len(read))
+	}
+
+	resp, err = middleware(req, okResponse("application/json", body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read, err := io.ReadAll(resp.Body); err != nil || len(read) != len(body) {
+		t.Errorf("plain response read %d bytes, %v", len(read), err)
+	}
+	if counts := injector.Counts(); counts.StreamsDropped != 1 || counts.LLMFailures != 0 {
+		t.Errorf("counts = %+v", counts)
+	}
+}
+
+func TestToolLatency(t *testing.T) {
+	injector := NewInjector(Config{MCPLatency: 20 * time.Millisecond, Seed: 7})
+	caller := injector.WrapToolCaller(mcp.ToolCallerFunc(func(ctx context.Context, params *sdkmcp.CallToolParams) (*sdkmcp.CallToolResult, error) {
+		return &sdkmcp.CallToolResult{}, nil
+	}))
+	start := time.Now()
+	if _, err := caller.CallTool(context.Background(), &sdkmcp.CallToolParams{Name: "get_world_state"}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("call returned after %v, before the latency", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := caller.CallTool(ctx, &sdkmcp.CallToolParams{Name: "get_world_state"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled call = %v", err)
+	}
+	if counts := injector.Counts(); counts.MCPDelays != 2 || counts.MCPFailures != 0 {
+		t.Errorf("counts = %+v", counts)
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv("CHAOS_LLM_FAIL_RATE", "1.5")
+	t.Setenv("CHAOS_MCP_FAIL_RATE", "0.25")
+	t.Setenv("CHAOS_MCP_LATENCY_MS", "150")
+	t.Setenv("CHAOS_STREAM_DROP", "true")
+	t.Setenv("CHAOS_SEED", "42")
+	want := Config{LLMFailRate: 1, MCPFailRate: 0.25, MCPLatency: 150 * time.Millisecond, StreamDrop: true, Seed: 42}
+	if got := LoadConfigFromEnv(); got != want || !got.Enabled() {
+		t.Errorf("config = %+v, want %+v", got, want)
+	}
+
+	for _, key := range []string{"CHAOS_LLM_FAIL_RATE", "CHAOS_MCP_FAIL_RATE", "CHAOS_MCP_LATENCY_MS", "CHAOS_STREAM_DROP", "CHAOS_SEED"} {
+		t.Setenv(key, "nonsense")
+	}
+	if got := LoadConfigFromEnv(); got != (Config{}) || got.Enabled() {
+		t.Errorf("unparseable config = %+v", got)
+	}
+}
@@ -0,0 +1,186 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Mode is how much debug output a session produces.
+type Mode int
+
+const (
+	// Off writes only the usual log lines.
+	Off Mode = iota
+	// LogOnly writes full debug detail to the debug log and keeps the chat pane clean.
+	LogOnly
+	// UI also mirrors the shown categories to the chat pane.
+	UI
+)
+
+func (m Mode) String() string {
+	switch m {
+	case LogOnly:
+		return "log"
+	case UI:
+		return "ui"
+	}
+	return "off"
+}
+
+// ParseMode reads the DEBUG setting: "log" for the log only, "ui" (or the older "1" and
+// "true") for the log and the chat pane, and empty, "0", "false" or "off" for neither.
+func ParseMode(value string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "off":
+		return Off, nil
+	case "log":
+		return LogOnly, nil
+	case "ui", "1", "true":
+		return UI, nil
+	}
+	return Off, fmt.Errorf("unknown debug mode %q (want log or ui)", value)
+}
+
+// Category groups debug output by what it's about, so each can be shown in the chat
+// pane or kept to the log on its own.
+type Category string
+
+const (
+	Session   Category = "session"   // startup summary and the NPC colour legend
+	Turns     Category = "turns"     // NPC turn order, parallel batches and skipped NPCs
+	Thoughts  Category = "thoughts"  // NPC thoughts and chosen actions
+	Mutations Category = "mutations" // director mutations and the ones that failed
+	Events    Category = "events"    // world events, scheduled events and authored dialogue
+	NPCState  Category = "npcs"      // NPC emotions and goals
+	Narration Category = "narration" // NPC-perspective narration, echoes and translated input
+	Facts     Category = "facts"     // fact extraction, attribution and usage
+	World     Category = "world"     // world-state server restarts, health checks and drift
+)
+
+// Categories lists every category in the order /debug shows them.
+var Categories = []Category{Session, Turns, Thoughts, Mutations, Events, NPCState, Narration, Facts, World}
+
+// ParseCategory finds a category by name, ignoring case.
+func ParseCategory(name string) (Category, error) {
+	category := Category(strings.ToLower(strings.TrimSpace(name)))
+	if !slices.Contains(Categories, category) {
+		return "", fmt.Errorf("unknown debug category %q", name)
+	}
+	return category, nil
+}
+
+// config is the file the category toggles persist to. Categories are shown unless
+// listed, so ones added later start out visible.
+type config struct {
+	HiddenCategories []Category `json:"hidden_categories"`
+}
+
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// Shows reports whether lines in the category are mirrored to the chat pane.
+func (d *Logger) Shows(category Category) bool {
+	if d.mode != UI {
+		return false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return !d.hidden[category]
+}
+
+// Hidden reports whether the category has been toggled off, whatever the mode.
+func (d *Logger) Hidden(category Category) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.hidden[category]
+}
+
+// SetShown toggles whether the category is mirrored to the chat pane and saves the
+// toggles to the config file, if there is one.
+func (d *Logger) SetShown(category Category, shown bool) error {
+	d.mu.Lock()
+	if shown {
+		delete(d.hidden, category)
+	} else {
+		d.hidden[category] = true
+	}
+	d.mu.Unlock()
+	return d.saveConfig()
+}
+
+// LoadConfig reads the category toggles saved at path and saves later changes there.
+// A missing file leaves every category shown.
+func (d *Logger) LoadConfig(path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.configPath = path
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read debug config: %w", err)
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse debug config %s: %w", path, err)
+	}
+	for _, category := range cfg.HiddenCategories {
+		d.hidden[category] = true
+	}
+	return nil
+}
+
+func (d *Logger) saveConfig() error {
+	d.mu.RLock()
+	path := d.configPath
+	var cfg config
+	for _, category := range Categories {
+		if d.hidden[category] {
+			cfg.HiddenCategories = append(cfg.HiddenCategories, category)
+		}
+	}
+	d.mu.RUnlock()
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal debug config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create debug config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write debug config: %w", err)
+	}
+	return nil
+}
+
+// Log writes lines in a category to the debug log, without their colour codes, and
+// returns the ones to mirror to the chat pane: all of them when the category is shown,
+// none otherwise. Outside debug mode it does nothing. Blank lines only separate
+// entries in the chat pane and aren't logged.
+func (d *Logger) Log(category Category, lines ...string) []string {
+	if d.mode == Off {
+		return nil
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			d.Printf("[%s] %s", category, ansiEscape.ReplaceAllString(line, ""))
+		}
+	}
+	if !d.Shows(category) {
+		return nil
+	}
+	return lines
+}
+
+// Logf is Log for a single formatted line.
+func (d *Logger) Logf(category Category, format string, args ...interface{}) []string {
+	return d.Log(category, fmt.Sprintf(format, args...))
+}
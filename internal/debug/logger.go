@@ -3,25 +3,37 @@ package debug
 import (
 	"log"
 	"os"
+	"sync"
+
+	"textadventure/internal/redact"
 )
 
 type Logger struct {
-    enabled bool
+    mode Mode
+
+    mu         sync.RWMutex
+    hidden     map[Category]bool // categories kept out of the chat pane
+    configPath string
 }
 
-func NewLogger(enabled bool) *Logger {
-	logFile, err := os.OpenFile("debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+// NewLogger sends the standard logger's output to logPath, appending if it exists.
+// Everything written goes through the redactor, including other packages' log calls.
+func NewLogger(mode Mode, logPath string) *Logger {
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err == nil {
-		log.SetOutput(logFile)
+		log.SetOutput(redact.NewWriter(logFile))
 	}
 	
-	if enabled {
+	switch mode {
+	case UI:
 		log.Printf("=== DEBUG MODE ENABLED ===")
-	} else {
+	case LogOnly:
+		log.Printf("=== DEBUG MODE ENABLED (LOG ONLY) ===")
+	default:
 		log.Printf("=== LOGGING ENABLED (UI DEBUG OFF) ===")
 	}
 	
-	return &Logger{enabled: enabled}
+	return &Logger{mode: mode, hidden: make(map[Category]bool)}
 }
 
 func (d *Logger) Printf(format string, args ...interface{}) {
@@ -48,6 +60,12 @@ func (d *Logger) Errorln(args ...interface{}) {
     log.Println(args...)
 }
 
+// IsEnabled reports whether debug detail is being collected, in the log or the UI.
 func (d *Logger) IsEnabled() bool {
-	return d.enabled
+	return d.mode != Off
+}
+
+// Mode returns where debug output goes.
+func (d *Logger) Mode() Mode {
+	return d.mode
 }
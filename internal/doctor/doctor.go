@@ -0,0 +1,102 @@
+// Package doctor probes each subsystem the game depends on and reports which are
+// healthy. Problems like a world-state server missing tools or an endpoint rejecting
+// JSON schemas otherwise only show up as odd gameplay. Probes are cheap, time-boxed and
+// never change the game's world.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds each probe.
+const DefaultTimeout = 10 * time.Second
+
+// Check is one probe. Probe returns a short detail line on success and an actionable
+// error on failure.
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name    string
+	Detail  string
+	Err     error
+	Elapsed time.Duration
+}
+
+// OK reports whether the check passed.
+func (r Result) OK() bool {
+	return r.Err == nil
+}
+
+// Run runs every check at once, each bounded by timeout, and returns the results in
+// the order of checks.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check, timeout)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	type outcome struct {
+		detail string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		detail, err := check.Probe(ctx)
+		done <- outcome{detail, err}
+	}()
+	select {
+	case o := <-done:
+		return Result{Name: check.Name, Detail: o.detail, Err: o.err, Elapsed: time.Since(start)}
+	case <-ctx.Done():
+		// A probe that ignores its context is abandoned rather than waited for
+		return Result{Name: check.Name, Err: fmt.Errorf("no answer within %s", timeout), Elapsed: time.Since(start)}
+	}
+}
+
+// Format renders results one line each, e.g. "PASS  world store (42ms): 14 tools".
+func Format(results []Result) []string {
+	lines := make([]string, 0, len(results))
+	for _, r := range results {
+		elapsed := r.Elapsed.Round(time.Millisecond)
+		if r.OK() {
+			line := fmt.Sprintf("PASS  %s (%s)", r.Name, elapsed)
+			if r.Detail != "" {
+				line += ": " + r.Detail
+			}
+			lines = append(lines, line)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("FAIL  %s (%s): %v", r.Name, elapsed, r.Err))
+	}
+	return lines
+}
+
+// Failures counts the failed results.
+func Failures(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if !r.OK() {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game/director"
+	"textadventure/internal/mcp"
+	"textadventure/internal/observability"
+)
+
+// WorldStore is the part of the world-state client the world store probe uses; both
+// calls only read.
+type WorldStore interface {
+	ListToolSpecs(ctx context.Context) ([]mcp.ToolSpec, error)
+	GetWorldState(ctx context.Context) (*mcp.WorldState, error)
+}
+
+// Models is the part of the LLM service the model probes use.
+type Models interface {
+	Models() []string
+	ProbeModel(ctx context.Context, model string) error
+	ProbeJSONSchema(ctx context.Context) (string, error)
+}
+
+// Store is a database that can check it is writable without keeping the write.
+type Store interface {
+	Probe(ctx context.Context) error
+}
+
+// WorldStoreCheck lists the server's tools, checking the director has every one it
+// needs, and reads the world back.
+func WorldStoreCheck(world WorldStore) Check {
+	return Check{Name: "world store", Probe: func(ctx context.Context) (string, error) {
+		specs, err := world.ListToolSpecs(ctx)
+		if err != nil {
+			return "", fmt.Errorf("%w; is the world-state server running? (WORLD_STATE_SERVER picks python or go)", err)
+		}
+		if missing := director.MissingServerTools(specs); len(missing) > 0 {
+			return "", fmt.Errorf("server lacks %s; update the world-state server to match this build", strings.Join(missing, ", "))
+		}
+		state, err := world.GetWorldState(ctx)
+		if err != nil {
+			return "", fmt.Errorf("get_world_state failed: %w; check the world file is valid JSON", err)
+		}
+		return fmt.Sprintf("%d tools, player at %s", len(specs), state.Player.Location), nil
+	}}
+}
+
+// ModelChecks sends a tiny completion to every model the game may call.
+func ModelChecks(models Models) []Check {
+	var checks []Check
+	for _, model := range models.Models() {
+		checks = append(checks, Check{Name: "llm " + model, Probe: func(ctx context.Context) (string, error) {
+			if err := models.ProbeModel(ctx, model); err != nil {
+				return "", fmt.Errorf("%w; check OPENAI_API_KEY, OPENAI_BASE_URL and the model name in LLM_MODEL_CONFIG", err)
+			}
+			return "", nil
+		}})
+	}
+	return checks
+}
+
+// JSONSchemaCheck checks schema completions come back in the shape asked for.
+func JSONSchemaCheck(models Models) Check {
+	return Check{Name: "json schema", Probe: func(ctx context.Context) (string, error) {
+		format, err := models.ProbeJSONSchema(ctx)
+		if err != nil {
+			if format == "json_schema" {
+				return "", fmt.Errorf("%w; if the endpoint doesn't support strict JSON schemas, set LLM_NO_JSON_SCHEMA=1", err)
+			}
+			return "", err
+		}
+		return "using " + format, nil
+	}}
+}
+
+// CompletionsDBCheck writes to the completions database and reads it back.
+func CompletionsDBCheck(store Store) Check {
+	return Check{Name: "completions db", Probe: func(ctx context.Context) (string, error) {
+		if err := store.Probe(ctx); err != nil {
+			return "", fmt.Errorf("%w; is another game holding it locked? COMPLETIONS_DB moves it", err)
+		}
+		return "", nil
+	}}
+}
+
+// TracingCheck checks the trace exporter is reachable and accepts the keys.
+func TracingCheck(config observability.Config) Check {
+	return Check{Name: "tracing", Probe: func(ctx context.Context) (string, error) {
+		if !config.Enabled {
+			return "disabled (set OTEL_TRACES_ENABLED=true to enable)", nil
+		}
+		if err := observability.ProbeExporter(ctx, config); err != nil {
+			return "", err
+		}
+		return "exporting to " + config.LangfuseHost, nil
+	}}
+}
+
+// Checks returns every check in report order. A nil world or store leaves its check out,
+// as when the game runs without one.
+func Checks(world WorldStore, models Models, store Store, tracing observability.Config) []Check {
+	var checks []Check
+	if world != nil {
+		checks = append(checks, WorldStoreCheck(world))
+	}
+	if models != nil {
+		checks = append(checks, ModelChecks(models)...)
+		checks = append(checks, JSONSchemaCheck(models))
+	}
+	if store != nil {
+		checks = append(checks, CompletionsDBCheck(store))
+	}
+	return append(checks, TracingCheck(tracing))
+}
@@ -0,0 +1,48 @@
+package experiment
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"textadventure/internal/artifacts"
+	"textadventure/internal/logging"
+)
+
+// RunCLI implements `textadventure experiments report [--experiment name]`, which
+// compares the variants of narrator experiments by narration latency and ratings.
+func RunCLI(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "report" {
+		return fmt.Errorf("usage: textadventure experiments report [--experiment name]")
+	}
+	fs := flag.NewFlagSet("experiments report", flag.ContinueOnError)
+	fs.SetOutput(out)
+	name := fs.String("experiment", "", "only report this experiment")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	logger, err := logging.NewCompletionLogger(artifacts.LoadConfigFromEnv().CompletionsDB)
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+
+	narrations, err := logger.ExperimentNarrations(*name)
+	if err != nil {
+		return err
+	}
+	ratings, err := logger.Ratings(*name)
+	if err != nil {
+		return err
+	}
+	reports := Aggregate(narrations, ratings)
+	if len(reports) == 0 {
+		if *name != "" {
+			return fmt.Errorf("nothing recorded for experiment %q", *name)
+		}
+		return fmt.Errorf("no narrator experiments recorded")
+	}
+	_, err = io.WriteString(out, Format(reports))
+	return err
+}
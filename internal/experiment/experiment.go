@@ -0,0 +1,107 @@
+// Package experiment compares two narrator configurations live. Each turn's narration
+// is assigned to variant A or B; the variant is recorded with the completion, on the
+// narration span and with the player's rating, so ratings and latency can be compared
+// per variant afterwards with `textadventure experiments report`.
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"textadventure/internal/game/rng"
+	"textadventure/internal/llm"
+)
+
+// Variant is one narrator configuration. Zero fields keep the narrator's usual settings.
+type Variant struct {
+	Model     string `json:"model,omitempty"`
+	Style     string `json:"style,omitempty"` // a NARRATION_STYLE preset name
+	MaxTokens int    `json:"max_tokens,omitempty"`
+}
+
+// Apply returns the model settings with the variant's model and token budget in place.
+func (v Variant) Apply(settings llm.ModelSettings) llm.ModelSettings {
+	if strings.TrimSpace(v.Model) != "" {
+		settings.Model = v.Model
+	}
+	if v.MaxTokens > 0 {
+		settings.MaxTokens = v.MaxTokens
+	}
+	return settings
+}
+
+// Config is a narrator experiment, e.g.
+//
+//	{"name": "terse-vs-noir", "seed": 7, "a": {"style": "terse"}, "b": {"style": "noir", "model": "gpt-5-mini"}}
+//
+// Seed 0 uses the run's --seed (or the seed picked at startup).
+type Config struct {
+	Name string  `json:"name"`
+	Seed int64   `json:"seed,omitempty"`
+	A    Variant `json:"a"`
+	B    Variant `json:"b"`
+}
+
+// Load reads an experiment config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read experiment config: %w", err)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse experiment config %s: %w", path, err)
+	}
+	config.Name = strings.TrimSpace(config.Name)
+	if config.Name == "" {
+		return nil, fmt.Errorf("experiment config %s has no name", path)
+	}
+	if config.Seed == 0 {
+		config.Seed = rng.MasterSeed()
+	}
+	return &config, nil
+}
+
+// LoadFromEnv reads the experiment config named by NARRATOR_EXPERIMENT. It returns nil,
+// and no error, when the variable is unset.
+func LoadFromEnv() (*Config, error) {
+	path := strings.TrimSpace(os.Getenv("NARRATOR_EXPERIMENT"))
+	if path == "" {
+		return nil, nil
+	}
+	return Load(path)
+}
+
+// Assignment is the variant a turn's narration runs under.
+type Assignment struct {
+	Experiment string
+	Variant    string // "A" or "B"
+	Settings   Variant
+}
+
+// Assign returns the variant for the turn. It depends only on the seed, the experiment
+// and the turn, so narration started again for the same turn gets the same variant and
+// a run replayed with the same seed gets the same assignments.
+func (c *Config) Assign(turnIndex int) Assignment {
+	stream := rng.New(c.Seed, fmt.Sprintf("experiment/%s/turn/%d", c.Name, turnIndex))
+	if stream.Intn(2) == 0 {
+		return Assignment{Experiment: c.Name, Variant: "A", Settings: c.A}
+	}
+	return Assignment{Experiment: c.Name, Variant: "B", Settings: c.B}
+}
+
+type assignmentKey struct{}
+
+// WithAssignment attaches the turn's variant for the narration started with ctx.
+func WithAssignment(ctx context.Context, assignment Assignment) context.Context {
+	return context.WithValue(ctx, assignmentKey{}, assignment)
+}
+
+// AssignmentFromContext returns the variant attached by WithAssignment.
+func AssignmentFromContext(ctx context.Context) (Assignment, bool) {
+	assignment, ok := ctx.Value(assignmentKey{}).(Assignment)
+	return assignment, ok
+}
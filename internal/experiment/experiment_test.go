@@ -0,0 +1,168 @@
+package experiment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game/rng"
+	"textadventure/internal/llm"
+)
+
+func testConfig() *Config {
+	return &Config{
+		Name: "terse-vs-noir",
+		Seed: 7,
+		A:    Variant{Style: "terse"},
+		B:    Variant{Style: "noir", Model: "gpt-5-mini"},
+	}
+}
+
+func TestAssignIsStablePerTurn(t *testing.T) {
+	config := testConfig()
+	counts := map[string]int{}
+	for turn := 0; turn < 200; turn++ {
+		first := config.Assign(turn)
+		if again := config.Assign(turn); again != first {
+			t.Fatalf("turn %d assigned %s, then %s", turn, first.Variant, again.Variant)
+		}
+		if first.Experiment != config.Name {
+			t.Errorf("turn %d: experiment = %q", turn, first.Experiment)
+		}
+		switch first.Variant {
+		case "A":
+			if first.Settings != config.A {
+				t.Errorf("turn %d: variant A runs %+v", turn, first.Settings)
+			}
+		case "B":
+			if first.Settings != config.B {
+				t.Errorf("turn %d: variant B runs %+v", turn, first.Settings)
+			}
+		default:
+			t.Fatalf("turn %d: variant %q", turn, first.Variant)
+		}
+		counts[first.Variant]++
+	}
+	// Both variants get a fair share of turns
+	if counts["A"] < 70 || counts["B"] < 70 {
+		t.Errorf("200 turns split %v", counts)
+	}
+}
+
+// Assignments depend on the seed and the experiment, not on anything the session has
+// drawn from the random streams, so a replayed run gets the same ones.
+func TestAssignDependsOnSeedAndName(t *testing.T) {
+	sequence := func(config *Config) string {
+		var b strings.Builder
+		for turn := 0; turn < 64; turn++ {
+			b.WriteString(config.Assign(turn).Variant)
+		}
+		return b.String()
+	}
+	config := testConfig()
+	want := sequence(config)
+
+	rng.Seed(99)
+	rng.New(99, "npc").Intn(10)
+	if got := sequence(testConfig()); got != want {
+		t.Errorf("assignments changed after other draws:\n%s\n%s", got, want)
+	}
+
+	reseeded := testConfig()
+	reseeded.Seed = 8
+	renamed := testConfig()
+	renamed.Name = "terse-vs-plain"
+	for name, other := range map[string]*Config{"seed": reseeded, "name": renamed} {
+		if sequence(other) == want {
+			t.Errorf("a different %s assigned the same 64 turns", name)
+		}
+	}
+}
+
+func TestVariantApply(t *testing.T) {
+	settings := llm.ModelSettings{Model: "gpt-5", MaxTokens: 800}
+	tests := []struct {
+		variant Variant
+		want    llm.ModelSettings
+	}{
+		{Variant{}, settings},
+		{Variant{Style: "noir"}, settings},
+		{Variant{Model: "  "}, settings},
+		{Variant{Model: "gpt-5-mini"}, llm.ModelSettings{Model: "gpt-5-mini", MaxTokens: 800}},
+		{Variant{MaxTokens: 300}, llm.ModelSettings{Model: "gpt-5", MaxTokens: 300}},
+		{Variant{Model: "gpt-5-nano", MaxTokens: -1}, llm.ModelSettings{Model: "gpt-5-nano", MaxTokens: 800}},
+	}
+	for _, tt := range tests {
+		if got := tt.variant.Apply(settings); got.Model != tt.want.Model || got.MaxTokens != tt.want.MaxTokens {
+			t.Errorf("%+v.Apply = %s/%d, want %s/%d", tt.variant, got.Model, got.MaxTokens, tt.want.Model, tt.want.MaxTokens)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	config, err := Load(write("full.json", `{"name": " terse-vs-noir ", "seed": 7, "a": {"style": "terse"}, "b": {"style": "noir", "model": "gpt-5-mini", "max_tokens": 400}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Config{Name: "terse-vs-noir", Seed: 7, A: Variant{Style: "terse"}, B: Variant{Style: "noir", Model: "gpt-5-mini", MaxTokens: 400}}
+	if *config != want {
+		t.Errorf("Load = %+v, want %+v", *config, want)
+	}
+
+	rng.Seed(1234)
+	unseeded, err := Load(write("unseeded.json", `{"name": "plain"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unseeded.Seed != 1234 {
+		t.Errorf("seed = %d, want the run's seed 1234", unseeded.Seed)
+	}
+
+	for name, tt := range map[string]struct{ path, wantErr string }{
+		"missing":  {filepath.Join(dir, "missing.json"), "read experiment config"},
+		"not JSON": {write("bad.json", `{"name": `), "parse experiment config"},
+		"no name":  {write("anonymous.json", `{"name": "  ", "a": {"style": "terse"}}`), "has no name"},
+	} {
+		if _, err := Load(tt.path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want one mentioning %q", name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("NARRATOR_EXPERIMENT", " ")
+	if config, err := LoadFromEnv(); config != nil || err != nil {
+		t.Errorf("unset: %v, %v", config, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "experiment.json")
+	if err := os.WriteFile(path, []byte(`{"name": "from-env", "seed": 3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NARRATOR_EXPERIMENT", path)
+	if config, err := LoadFromEnv(); err != nil || config.Name != "from-env" {
+		t.Errorf("LoadFromEnv = %v, %v", config, err)
+	}
+}
+
+func TestAssignmentContext(t *testing.T) {
+	if _, ok := AssignmentFromContext(context.Background()); ok {
+		t.Error("a bare context carries an assignment")
+	}
+	assignment := testConfig().Assign(3)
+	got, ok := AssignmentFromContext(WithAssignment(context.Background(), assignment))
+	if !ok || got != assignment {
+		t.Errorf("AssignmentFromContext = %+v, %v; want %+v", got, ok, assignment)
+	}
+}
@@ -0,0 +1,91 @@
+package experiment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"textadventure/internal/logging"
+)
+
+// VariantReport sums up one variant of an experiment: how many narrations it produced,
+// how long they took, and how the player rated them.
+type VariantReport struct {
+	Experiment  string
+	Variant     string
+	Narrations  int
+	MeanLatency time.Duration
+	MaxLatency  time.Duration
+	ThumbsUp    int
+	ThumbsDown  int
+}
+
+// Approval is the share of ratings that were thumbs up, or -1 when there are none.
+func (r VariantReport) Approval() float64 {
+	rated := r.ThumbsUp + r.ThumbsDown
+	if rated == 0 {
+		return -1
+	}
+	return float64(r.ThumbsUp) / float64(rated)
+}
+
+// Aggregate groups narrations and ratings by experiment and variant, sorted by both.
+func Aggregate(narrations []logging.ExperimentNarration, ratings []logging.NarrationRating) []VariantReport {
+	byVariant := map[[2]string]*VariantReport{}
+	report := func(experiment, variant string) *VariantReport {
+		key := [2]string{experiment, variant}
+		if byVariant[key] == nil {
+			byVariant[key] = &VariantReport{Experiment: experiment, Variant: variant}
+		}
+		return byVariant[key]
+	}
+	totals := map[[2]string]time.Duration{}
+	for _, narration := range narrations {
+		r := report(narration.Experiment, narration.Variant)
+		r.Narrations++
+		r.MaxLatency = max(r.MaxLatency, narration.ResponseTime)
+		totals[[2]string{narration.Experiment, narration.Variant}] += narration.ResponseTime
+	}
+	for _, rating := range ratings {
+		r := report(rating.Experiment, rating.Variant)
+		switch {
+		case rating.Rating > 0:
+			r.ThumbsUp++
+		case rating.Rating < 0:
+			r.ThumbsDown++
+		}
+	}
+
+	reports := make([]VariantReport, 0, len(byVariant))
+	for key, r := range byVariant {
+		if r.Narrations > 0 {
+			r.MeanLatency = totals[key] / time.Duration(r.Narrations)
+		}
+		reports = append(reports, *r)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Experiment != reports[j].Experiment {
+			return reports[i].Experiment < reports[j].Experiment
+		}
+		return reports[i].Variant < reports[j].Variant
+	})
+	return reports
+}
+
+// Format renders reports as an aligned table, one row per variant.
+func Format(reports []VariantReport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-20s %-7s %10s %12s %12s %4s %4s %8s\n", "EXPERIMENT", "VARIANT", "NARRATIONS", "MEAN LATENCY", "MAX LATENCY", "UP", "DOWN", "APPROVAL")
+	for _, r := range reports {
+		approval := "-"
+		if a := r.Approval(); a >= 0 {
+			approval = fmt.Sprintf("%.0f%%", a*100)
+		}
+		fmt.Fprintf(&sb, "%-20s %-7s %10d %12s %12s %4d %4d %8s\n",
+			r.Experiment, r.Variant, r.Narrations,
+			r.MeanLatency.Round(time.Millisecond), r.MaxLatency.Round(time.Millisecond),
+			r.ThumbsUp, r.ThumbsDown, approval)
+	}
+	return sb.String()
+}
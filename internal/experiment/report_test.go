@@ -0,0 +1,151 @@
+package experiment
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"textadventure/internal/logging"
+)
+
+func TestAggregate(t *testing.T) {
+	narrations := []logging.ExperimentNarration{
+		{Experiment: "terse-vs-noir", Variant: "B", ResponseTime: 3 * time.Second},
+		{Experiment: "terse-vs-noir", Variant: "A", ResponseTime: 1 * time.Second},
+		{Experiment: "terse-vs-noir", Variant: "A", ResponseTime: 2 * time.Second},
+		{Experiment: "alpha", Variant: "A", ResponseTime: 500 * time.Millisecond},
+	}
+	ratings := []logging.NarrationRating{
+		{Experiment: "terse-vs-noir", Variant: "A", Rating: 1},
+		{Experiment: "terse-vs-noir", Variant: "A", Rating: 1},
+		{Experiment: "terse-vs-noir", Variant: "A", Rating: -1},
+		{Experiment: "terse-vs-noir", Variant: "B", Rating: -1},
+		{Experiment: "terse-vs-noir", Variant: "B", Rating: 0},
+		// A rating whose narration wasn't logged still gets a row
+		{Experiment: "beta", Variant: "B", Rating: 1},
+	}
+	want := []VariantReport{
+		{Experiment: "alpha", Variant: "A", Narrations: 1, MeanLatency: 500 * time.Millisecond, MaxLatency: 500 * time.Millisecond},
+		{Experiment: "beta", Variant: "B", ThumbsUp: 1},
+		{Experiment: "terse-vs-noir", Variant: "A", Narrations: 2, MeanLatency: 1500 * time.Millisecond, MaxLatency: 2 * time.Second, ThumbsUp: 2, ThumbsDown: 1},
+		{Experiment: "terse-vs-noir", Variant: "B", Narrations: 1, MeanLatency: 3 * time.Second, MaxLatency: 3 * time.Second, ThumbsDown: 1},
+	}
+	got := Aggregate(narrations, ratings)
+	if len(got) != len(want) {
+		t.Fatalf("Aggregate = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if reports := Aggregate(nil, nil); len(reports) != 0 {
+		t.Errorf("Aggregate of nothing = %+v", reports)
+	}
+}
+
+func TestApproval(t *testing.T) {
+	tests := []struct {
+		up, down int
+		want     float64
+	}{
+		{0, 0, -1},
+		{3, 0, 1},
+		{0, 2, 0},
+		{3, 1, 0.75},
+	}
+	for _, tt := range tests {
+		if got := (VariantReport{ThumbsUp: tt.up, ThumbsDown: tt.down}).Approval(); got != tt.want {
+			t.Errorf("%d up, %d down: Approval = %v, want %v", tt.up, tt.down, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	out := Format([]VariantReport{
+		{Experiment: "terse-vs-noir", Variant: "A", Narrations: 2, MeanLatency: 1500400 * time.Microsecond, MaxLatency: 2 * time.Second, ThumbsUp: 2, ThumbsDown: 1},
+		{Experiment: "terse-vs-noir", Variant: "B", Narrations: 1, MeanLatency: 3 * time.Second, MaxLatency: 3 * time.Second},
+	})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Format = %q, want a header and two rows", out)
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "EXPERIMENT VARIANT NARRATIONS MEAN LATENCY MAX LATENCY UP DOWN APPROVAL" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if fields := strings.Join(strings.Fields(lines[1]), " "); fields != "terse-vs-noir A 2 1.5s 2s 2 1 67%" {
+		t.Errorf("row A = %q", fields)
+	}
+	if fields := strings.Join(strings.Fields(lines[2]), " "); fields != "terse-vs-noir B 1 3s 3s 0 0 -" {
+		t.Errorf("row B = %q", fields)
+	}
+	// Columns line up
+	if len(lines[1]) != len(lines[0]) || len(lines[2]) != len(lines[0]) {
+		t.Errorf("rows are not aligned:\n%s", out)
+	}
+}
+
+// The report reads what the game logged: narration completions tagged with a variant
+// and the player's ratings.
+func TestRunCLIReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "completions.db")
+	t.Setenv("COMPLETIONS_DB", path)
+	logger, err := logging.NewCompletionLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completions := []logging.CompletionMetadata{
+		{Model: "gpt-5", ResponseTime: time.Second, Experiment: "terse-vs-noir", Variant: "A"},
+		{Model: "gpt-5-mini", ResponseTime: 3 * time.Second, Experiment: "terse-vs-noir", Variant: "B"},
+		{Model: "gpt-5", ResponseTime: 2 * time.Second, Experiment: "other", Variant: "A"},
+		{Model: "gpt-5", ResponseTime: 9 * time.Second}, // outside any experiment
+	}
+	for _, metadata := range completions {
+		if err := logger.LogCompletion(map[string]string{}, "look", "system", "You look around.", metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ratings := []logging.NarrationRating{
+		{SessionID: "s1", TurnIndex: 1, Experiment: "terse-vs-noir", Variant: "A", Rating: -1},
+		{SessionID: "s1", TurnIndex: 1, Experiment: "terse-vs-noir", Variant: "A", Rating: 1}, // re-rated
+		{SessionID: "s1", TurnIndex: 2, Experiment: "terse-vs-noir", Variant: "B", Rating: -1},
+		{SessionID: "s1", TurnIndex: 3, Rating: 1}, // outside any experiment
+	}
+	for i, rating := range ratings {
+		rating.RatedAt = time.Unix(int64(i), 0)
+		if err := logger.LogRating(rating); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logger.Close()
+
+	var out bytes.Buffer
+	if err := RunCLI([]string{"report", "--experiment", "terse-vs-noir"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var rows []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+		rows = append(rows, strings.Join(strings.Fields(line), " "))
+	}
+	want := []string{
+		"terse-vs-noir A 1 1s 1s 1 0 100%",
+		"terse-vs-noir B 1 3s 3s 0 1 0%",
+	}
+	if strings.Join(rows, "\n") != strings.Join(want, "\n") {
+		t.Errorf("report rows:\n%s\nwant\n%s", strings.Join(rows, "\n"), strings.Join(want, "\n"))
+	}
+
+	out.Reset()
+	if err := RunCLI([]string{"report"}, &out); err != nil || !strings.Contains(out.String(), "other ") {
+		t.Errorf("report of every experiment = %q, %v", out.String(), err)
+	}
+	if err := RunCLI([]string{"report", "--experiment", "missing"}, &out); err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Errorf("unknown experiment: error = %v", err)
+	}
+	if err := RunCLI(nil, &out); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("no subcommand: error = %v", err)
+	}
+}
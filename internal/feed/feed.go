@@ -0,0 +1,156 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"textadventure/internal/game"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// Feed is a JSON Feed (https://jsonfeed.org) document with one item per turn.
+type Feed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"` // the scenario briefing, if any
+	Items       []Item `json:"items"`
+}
+
+// Item is a single turn entry. Game-specific fields live under the "_text_adventure"
+// extension key as required by the JSON Feed spec.
+type Item struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	ContentText   string    `json:"content_text"`
+	DatePublished time.Time `json:"date_published"`
+	Extension     TurnEntry `json:"_text_adventure"`
+}
+
+// TurnEntry holds the structured turn contents for tools consuming the feed.
+type TurnEntry struct {
+	SessionID   string   `json:"session_id"`
+	TurnIndex   int      `json:"turn_index"`
+	Location    string   `json:"location"`
+	PlayerInput string   `json:"player_input,omitempty"`
+	Narration   string   `json:"narration"`
+	Events      []string `json:"events,omitempty"`
+	DurationMs  int64    `json:"duration_ms"`
+}
+
+// Writer keeps a session feed file up to date. It implements game.TurnSubscriber
+// and starts a new file whenever the session ID changes.
+type Writer struct {
+	dir       string
+	mu        sync.Mutex
+	sessionID string
+	path      string
+	feed      Feed
+	briefing  string
+}
+
+// NewWriter creates a feed writer that stores one file per session in dir.
+func NewWriter(dir string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create feed directory: %w", err)
+	}
+	return &Writer{dir: dir}, nil
+}
+
+// SetBriefing sets the scenario briefing written at the top of each session feed.
+func (w *Writer) SetBriefing(text string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.briefing = text
+}
+
+// Path returns the file backing the current session's feed.
+func (w *Writer) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path
+}
+
+// OnTurnComplete appends the turn to the session feed and rewrites the file.
+func (w *Writer) OnTurnComplete(record game.TurnRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if record.SessionID != w.sessionID || w.path == "" {
+		w.rotate(record)
+	}
+
+	w.feed.Items = append(w.feed.Items, newItem(record))
+	return w.flush()
+}
+
+func (w *Writer) rotate(record game.TurnRecord) {
+	shortID := record.SessionID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
+	}
+	started := record.StartedAt
+	if started.IsZero() {
+		started = time.Now()
+	}
+	w.sessionID = record.SessionID
+	w.path = filepath.Join(w.dir, fmt.Sprintf("session-%s-%s.json", started.Format("20060102-150405"), shortID))
+	w.feed = Feed{
+		Version:     jsonFeedVersion,
+		Title:       fmt.Sprintf("Text Adventure session %s", shortID),
+		Description: w.briefing,
+		Items:       []Item{},
+	}
+}
+
+// flush writes the feed to a temp file and renames it so readers never see a partial document.
+func (w *Writer) flush() error {
+	data, err := json.MarshalIndent(w.feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed: %w", err)
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write feed: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return fmt.Errorf("failed to replace feed: %w", err)
+	}
+	return nil
+}
+
+func newItem(record game.TurnRecord) Item {
+	title := fmt.Sprintf("Turn %d", record.TurnIndex)
+	var content strings.Builder
+	if strings.TrimSpace(record.PlayerInput) != "" {
+		title = fmt.Sprintf("Turn %d: %s", record.TurnIndex, record.PlayerInput)
+		content.WriteString("> " + record.PlayerInput + "\n\n")
+	}
+	content.WriteString(strings.TrimSpace(record.Narration))
+
+	id := record.TurnID
+	if id == "" {
+		id = fmt.Sprintf("%s-%d", record.SessionID, record.TurnIndex)
+	}
+
+	return Item{
+		ID:            id,
+		Title:         title,
+		ContentText:   content.String(),
+		DatePublished: record.CompletedAt,
+		Extension: TurnEntry{
+			SessionID:   record.SessionID,
+			TurnIndex:   record.TurnIndex,
+			Location:    record.Location,
+			PlayerInput: record.PlayerInput,
+			Narration:   record.Narration,
+			Events:      record.WorldEvents,
+			DurationMs:  record.CompletedAt.Sub(record.StartedAt).Milliseconds(),
+		},
+	}
+}
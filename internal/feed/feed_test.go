@@ -0,0 +1,175 @@
+package feed
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"textadventure/internal/game"
+)
+
+func readFeed(t *testing.T, path string) Feed {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read feed: %v", err)
+	}
+	var feed Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("parse feed: %v", err)
+	}
+	return feed
+}
+
+func turnRecord(sessionID string, index int, input, narration string) game.TurnRecord {
+	started := time.Date(2025, 3, 1, 12, 0, index, 0, time.UTC)
+	return game.TurnRecord{
+		SessionID:   sessionID,
+		TurnID:      sessionID + "-turn",
+		TurnIndex:   index,
+		Location:    "foyer",
+		PlayerInput: input,
+		Narration:   narration,
+		WorldEvents: []string{"player: opened the door"},
+		Mutations:   []string{"Player moved to study"},
+		Failures:    []string{"Error: door is locked"},
+		StartedAt:   started,
+		CompletedAt: started.Add(1500 * time.Millisecond),
+	}
+}
+
+func TestWriterFeedStructure(t *testing.T) {
+	writer, err := NewWriter(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.SetBriefing("A manor at midnight.")
+	if err := writer.OnTurnComplete(turnRecord("abcdef123456", 1, "open the door", "  The door creaks open.  ")); err != nil {
+		t.Fatal(err)
+	}
+
+	feed := readFeed(t, writer.Path())
+	if feed.Version != jsonFeedVersion {
+		t.Errorf("version = %q, want %q", feed.Version, jsonFeedVersion)
+	}
+	if feed.Title != "Text Adventure session abcdef12" {
+		t.Errorf("title = %q", feed.Title)
+	}
+	if feed.Description != "A manor at midnight." {
+		t.Errorf("description = %q", feed.Description)
+	}
+	if len(feed.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(feed.Items))
+	}
+	if _, err := os.Stat(writer.Path() + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+}
+
+func TestWriterTurnEntry(t *testing.T) {
+	writer, err := NewWriter(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := turnRecord("11111111-one", 3, "open the door", "The door creaks open.")
+	if err := writer.OnTurnComplete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	item := readFeed(t, writer.Path()).Items[0]
+	if item.ID != "11111111-one-turn" {
+		t.Errorf("id = %q", item.ID)
+	}
+	if item.Title != "Turn 3: open the door" {
+		t.Errorf("title = %q", item.Title)
+	}
+	if item.ContentText != "> open the door\n\nThe door creaks open." {
+		t.Errorf("content = %q", item.ContentText)
+	}
+	if !item.DatePublished.Equal(record.CompletedAt) {
+		t.Errorf("published = %v, want %v", item.DatePublished, record.CompletedAt)
+	}
+	entry := item.Extension
+	if entry.SessionID != "11111111-one" || entry.TurnIndex != 3 || entry.Location != "foyer" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.PlayerInput != "open the door" || entry.Narration != "The door creaks open." {
+		t.Errorf("entry = %+v", entry)
+	}
+	if len(entry.Events) != 1 || entry.Events[0] != "player: opened the door" {
+		t.Errorf("events = %v", entry.Events)
+	}
+	if entry.DurationMs != 1500 {
+		t.Errorf("duration = %d, want 1500", entry.DurationMs)
+	}
+}
+
+func TestWriterOmitsDebugContent(t *testing.T) {
+	writer, err := NewWriter(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.OnTurnComplete(turnRecord("11111111-one", 1, "look", "Dust hangs in the air.")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(writer.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, debugText := range []string{"Player moved to study", "door is locked", "[DEBUG]"} {
+		if strings.Contains(string(data), debugText) {
+			t.Errorf("feed contains %q", debugText)
+		}
+	}
+}
+
+func TestWriterTurnWithoutInput(t *testing.T) {
+	writer, err := NewWriter(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := turnRecord("11111111-one", 0, "", "You wake in a cold foyer.")
+	record.TurnID = ""
+	if err := writer.OnTurnComplete(record); err != nil {
+		t.Fatal(err)
+	}
+	item := readFeed(t, writer.Path()).Items[0]
+	if item.Title != "Turn 0" || item.ContentText != "You wake in a cold foyer." {
+		t.Errorf("item = %+v", item)
+	}
+	if item.ID != "11111111-one-0" {
+		t.Errorf("id = %q, want 11111111-one-0", item.ID)
+	}
+}
+
+func TestWriterAppendsAndRotatesPerSession(t *testing.T) {
+	writer, err := NewWriter(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 2; i++ {
+		if err := writer.OnTurnComplete(turnRecord("11111111-one", i, "wait", "Nothing happens.")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first := writer.Path()
+	if got := len(readFeed(t, first).Items); got != 2 {
+		t.Fatalf("first session has %d items, want 2", got)
+	}
+
+	if err := writer.OnTurnComplete(turnRecord("22222222-two", 1, "wait", "Still nothing.")); err != nil {
+		t.Fatal(err)
+	}
+	second := writer.Path()
+	if second == first {
+		t.Fatal("a new session reused the previous feed file")
+	}
+	if got := len(readFeed(t, second).Items); got != 1 {
+		t.Errorf("second session has %d items, want 1", got)
+	}
+	if got := len(readFeed(t, first).Items); got != 2 {
+		t.Errorf("first session changed to %d items", got)
+	}
+}
@@ -0,0 +1,28 @@
+package actors
+
+import (
+	"textadventure/internal/game"
+	"textadventure/internal/game/dialogue"
+	"textadventure/internal/game/events"
+)
+
+// authoredLine returns the scenario's dialogue node for an NPC the player is engaging:
+// the player is in the NPC's room and did something the NPC perceived this turn. Other
+// turns are the NPC's own, and go to the LLM as usual.
+func authoredLine(world game.WorldState, npcID string, perceived []events.WorldEvent) (game.DialogueNode, bool) {
+	npc, ok := world.NPCs[npcID]
+	if !ok || len(npc.Dialogue) == 0 || npc.Location != world.Location {
+		return game.DialogueNode{}, false
+	}
+	engaged := false
+	for _, event := range perceived {
+		if game.RefID(event.Actor) == "player" {
+			engaged = true
+			break
+		}
+	}
+	if !engaged {
+		return game.DialogueNode{}, false
+	}
+	return dialogue.Match(world, npcID)
+}
@@ -0,0 +1,78 @@
+package actors
+
+import (
+	"maps"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+)
+
+// EmotionShift is a change to one of an NPC's emotions, with the perceived event line
+// that caused it.
+type EmotionShift struct {
+	Emotion string
+	Delta   float64
+	Cause   string
+}
+
+// startleWords mark an event as sudden and loud enough to frighten whoever perceives it.
+var startleWords = []string{"crash", "bang", "scream", "shriek", "shatter", "smash", "explo", "slam", "gunshot", "roar", "thud", "loud"}
+
+const (
+	startleFear     = 0.3
+	contestFear     = 0.2
+	noticeCuriosity = 0.1
+)
+
+// AppraiseEmotions decides how what an NPC perceived this turn moves its emotions: a
+// startling noise frightens it, a struggle nearby unsettles it and anything else done
+// by someone else makes it curious. Each emotion moves at most once a turn, citing the
+// first event that moved it; the NPC's own doings and its room's ambience move nothing.
+func AppraiseEmotions(npcID string, perceived []events.WorldEvent) []EmotionShift {
+	var shifts []EmotionShift
+	moved := map[string]bool{}
+	shift := func(emotion string, delta float64, event events.WorldEvent) {
+		if moved[emotion] {
+			return
+		}
+		moved[emotion] = true
+		shifts = append(shifts, EmotionShift{Emotion: emotion, Delta: delta, Cause: event.Line()})
+	}
+	for _, event := range perceived {
+		if event.Actor == npcID || event.Type == events.EventAmbience {
+			continue
+		}
+		switch {
+		case isStartling(event):
+			shift("fear", startleFear, event)
+		case event.Type == events.EventContest:
+			shift("fear", contestFear, event)
+		case event.Type != events.EventSpeech:
+			shift("curiosity", noticeCuriosity, event)
+		}
+	}
+	return shifts
+}
+
+func isStartling(event events.WorldEvent) bool {
+	text := strings.ToLower(event.Content)
+	for _, word := range startleWords {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyEmotionShifts returns the NPC's emotions with the shifts applied.
+func ApplyEmotionShifts(emotions map[string]float64, shifts []EmotionShift) map[string]float64 {
+	updated := maps.Clone(emotions)
+	if updated == nil {
+		updated = map[string]float64{}
+	}
+	for _, s := range shifts {
+		updated[s.Emotion] = game.AdjustEmotion(updated, s.Emotion, s.Delta)
+	}
+	return updated
+}
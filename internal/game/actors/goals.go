@@ -0,0 +1,120 @@
+package actors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/game"
+	"textadventure/internal/llm"
+)
+
+const (
+	// MaxGoals caps how many goals an NPC pursues at once; a review adds no more.
+	MaxGoals = 3
+	// goalReviewMemoryLimit is how many recent thoughts and actions a review looks back
+	// over. It spans more than a turn's prompt does, since reviews run every few turns.
+	goalReviewMemoryLimit = 8
+)
+
+// GoalReview is what a goal review decided: goals the NPC has achieved or given up on,
+// and new ones it has taken on.
+type GoalReview struct {
+	Completed []string
+	Added     []string
+}
+
+// Empty reports whether the review changes nothing.
+func (r GoalReview) Empty() bool {
+	return len(r.Completed) == 0 && len(r.Added) == 0
+}
+
+// ReviewGoals asks whether an NPC's recent thoughts and actions have achieved any of its
+// goals, and whether they point to new ones. Completed goals are always from the NPC's
+// current list, and new goals never take it past MaxGoals.
+func ReviewGoals(ctx context.Context, llmService llm.Completer, npcID string, world game.WorldState) (GoalReview, error) {
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return GoalReview{}, fmt.Errorf("no such NPC %q", npcID)
+	}
+	ctx, span := otel.Tracer("actors").Start(ctx, "npc.goal_review")
+	defer span.End()
+	span.SetAttributes(attribute.String("npc.id", npcID), attribute.Int("npc.goals", len(npc.Goals)))
+
+	turn := game.TurnIndexFromContext(ctx)
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"completed": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "Current goals that are achieved or abandoned, copied exactly",
+			},
+			"added": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "New goals, each a short phrase",
+			},
+		},
+		"required":             []string{"completed", "added"},
+		"additionalProperties": false,
+	}
+	req := llm.JSONSchemaCompletionRequest{
+		SystemPrompt: buildGoalReviewPromptXML(npcID, npc.Personality, npc.Backstory),
+		UserPrompt: buildGoalReviewUserXML(npc.Goals,
+			game.RecentMemoryLines(npc.RecentThoughts, turn, goalReviewMemoryLimit),
+			game.RecentMemoryLines(npc.RecentActions, turn, goalReviewMemoryLimit)),
+		MaxTokens:       1000,
+		Model:           "gpt-5-mini",
+		ReasoningEffort: "minimal",
+		SchemaName:      "goal_review",
+		Schema:          schema,
+	}
+	ctx = llm.WithOperationType(ctx, "npc.goal_review")
+	ctx = llm.WithGameContext(ctx, map[string]interface{}{
+		"npc_id":   npcID,
+		"location": npc.Location,
+	})
+	content, err := llmService.CompleteJSONSchema(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		return GoalReview{}, err
+	}
+	var response struct {
+		Completed []string `json:"completed"`
+		Added     []string `json:"added"`
+	}
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		span.RecordError(err)
+		return GoalReview{}, fmt.Errorf("failed to parse goal review: %w", err)
+	}
+	return cleanGoalReview(npc.Goals, response.Completed, response.Added), nil
+}
+
+// cleanGoalReview keeps only completions of goals the NPC has and additions it doesn't,
+// up to MaxGoals once the completions are taken off.
+func cleanGoalReview(goals, completed, added []string) GoalReview {
+	var review GoalReview
+	remaining := len(goals)
+	for _, goal := range completed {
+		goal = strings.TrimSpace(goal)
+		if slices.Contains(goals, goal) && !slices.Contains(review.Completed, goal) {
+			review.Completed = append(review.Completed, goal)
+			remaining--
+		}
+	}
+	for _, goal := range added {
+		goal = strings.TrimSpace(goal)
+		if goal == "" || remaining >= MaxGoals || slices.Contains(goals, goal) || slices.Contains(review.Added, goal) {
+			continue
+		}
+		review.Added = append(review.Added, goal)
+		remaining++
+	}
+	return review
+}
@@ -9,12 +9,18 @@ import (
     tea "github.com/charmbracelet/bubbletea"
 
     "textadventure/internal/game"
+    "textadventure/internal/game/dialogue"
+    "textadventure/internal/game/events"
     "textadventure/internal/game/perception"
     "textadventure/internal/llm"
     "go.opentelemetry.io/otel"
     "go.opentelemetry.io/otel/attribute"
 )
 
+// RecentMemoryLimit caps how many recent thoughts and actions each NPC prompt
+// includes, however many the server keeps. 0 or less includes all of them.
+var RecentMemoryLimit = 4
+
 func BuildNPCWorldContext(npcID string, world game.WorldState, gameHistory []string) string {
 	if _, exists := world.NPCs[npcID]; !exists {
 		return fmt.Sprintf("ERROR: NPC %s not found", npcID)
@@ -22,12 +28,12 @@ func BuildNPCWorldContext(npcID string, world game.WorldState, gameHistory []str
 	return game.BuildWorldContext(world, gameHistory, npcID)
 }
 
-func BuildNPCWorldContextWithPerceptions(npcID string, world game.WorldState, perceivedLines []string) string {
+func BuildNPCWorldContextWithPerceptions(ctx context.Context, npcID string, world game.WorldState, perceivedLines []string) string {
     if _, exists := world.NPCs[npcID]; !exists {
         return "ERROR: NPC not found"
     }
 
-    baseContext := game.BuildWorldContext(world, []string{}, npcID)
+    baseContext := game.CachedWorldContext(ctx, world, []string{}, npcID)
     if len(perceivedLines) == 0 {
         return baseContext
     }
@@ -57,26 +63,53 @@ type NPCActionMsg struct {
     Thoughts      string
     Action        string
     Debug         bool
+    StrictErr     error // a fallback strict mode refused; the NPC sat the turn out
+    Skipped       bool  // the NPC was far from the player and perceived nothing, so it didn't think or act
+    Dialogue      *game.DialogueNode // the authored node Action says verbatim, if one matched
+    EmotionShifts []EmotionShift      // how what the NPC perceived moved its emotions
+    AmbienceHeard string              // its room's ambience, if the NPC perceived it for the first time
+    // Location and WorldVersion describe the world the turn was generated against. The
+    // game stamps them when it dispatches the turn, so a stale result can be re-resolved.
+    Location      string
+    WorldVersion  uint64
+}
+
+// quietDistance is how many rooms from the player an NPC has to be for a turn in which it
+// perceived nothing to be skipped without asking the LLM what it thinks or does.
+const quietDistance = 2
+
+// isQuietTurn reports whether an NPC that perceived nothing is far enough from the player
+// to sit the turn out. NPCs with no route to the player count as far.
+func isQuietTurn(world game.WorldState, npcID string, perceived []events.WorldEvent) bool {
+    if len(perceived) > 0 {
+        return false
+    }
+    distance := game.RoomDistance(world.Locations, world.NPCs[npcID].Location, world.Location)
+    return distance < 0 || distance >= quietDistance
 }
 
 // GenerateNPCThoughts creates a tea.Cmd that generates thoughts for an NPC
-func GenerateNPCThoughts(ctx context.Context, llmService *llm.Service, npcID string, world game.WorldState, gameHistory []string, debug bool, perceivedLines []string, situation string) tea.Cmd {
+func GenerateNPCThoughts(ctx context.Context, llmService llm.Completer, npcID string, world game.WorldState, gameHistory []string, debug bool, perceivedLines []string, situation string) tea.Cmd {
     return func() tea.Msg {
-        worldContext := game.BuildWorldContext(world, []string{}, npcID)
+        worldContext := game.CachedWorldContext(ctx, world, []string{}, npcID)
 		
 		var recentThoughts, recentActions []string
 		var personality, backstory string
-		var coreMemories []string
+		var coreMemories, goals []string
+		var emotions string
 		if npc, exists := world.NPCs[npcID]; exists {
-			recentThoughts = npc.RecentThoughts
-			recentActions = npc.RecentActions
+			turn := game.TurnIndexFromContext(ctx)
+			recentThoughts = game.RecentMemoryLines(npc.RecentThoughts, turn, RecentMemoryLimit)
+			recentActions = game.RecentMemoryLines(npc.RecentActions, turn, RecentMemoryLimit)
 			personality = npc.Personality
 			backstory = npc.Backstory
 			coreMemories = npc.Memories
+			goals = npc.Goals
+			emotions = game.FormatEmotions(npc.Emotions)
 		}
 		
         req := llm.TextCompletionRequest{
-            SystemPrompt:    buildThoughtsPromptXML(npcID, recentThoughts, recentActions, personality, backstory, coreMemories),
+            SystemPrompt:    buildThoughtsPromptXML(npcID, recentThoughts, recentActions, personality, backstory, coreMemories, goals, emotions),
             UserPrompt:      buildNPCThoughtsUserXML(worldContext, perceivedLines, situation),
             MaxTokens:       2000,
             Model:           "gpt-5-mini",
@@ -108,23 +141,25 @@ func GenerateNPCThoughts(ctx context.Context, llmService *llm.Service, npcID str
 }
 
 // GenerateNPCAction generates an action for an NPC based on their thoughts and world state
-func GenerateNPCAction(ctx context.Context, llmService *llm.Service, npcID string, npcThoughts string, world game.WorldState, perceivedLines []string, debug bool) (string, error) {
+func GenerateNPCAction(ctx context.Context, llmService llm.Completer, npcID string, npcThoughts string, world game.WorldState, perceivedLines []string, debug bool) (string, error) {
     if npcThoughts == "" {
         return "", nil
     }
 
-    worldContext := BuildNPCWorldContextWithPerceptions(npcID, world, perceivedLines)
+    worldContext := BuildNPCWorldContextWithPerceptions(ctx, npcID, world, perceivedLines)
 	
-	var recentActions []string
-	var personality, backstory string
+	var recentActions, goals []string
+	var personality, backstory, emotions string
 	if npc, exists := world.NPCs[npcID]; exists {
-		recentActions = npc.RecentActions
+		recentActions = game.RecentMemoryLines(npc.RecentActions, game.TurnIndexFromContext(ctx), RecentMemoryLimit)
 		personality = npc.Personality
 		backstory = npc.Backstory
+		goals = npc.Goals
+		emotions = game.FormatEmotions(npc.Emotions)
 	}
 	
 	req := llm.TextCompletionRequest{
-		SystemPrompt:    buildActionPrompt(npcID, npcThoughts, recentActions, personality, backstory),
+		SystemPrompt:    buildActionPrompt(npcID, npcThoughts, recentActions, personality, backstory, dialogue.Examples(world, npcID), goals, emotions),
 		UserPrompt:      worldContext,
 		MaxTokens:       2000,
 		Model:           "gpt-5-mini",
@@ -148,29 +183,37 @@ func GenerateNPCAction(ctx context.Context, llmService *llm.Service, npcID strin
 }
 
 // GenerateNPCTurn creates a tea.Cmd that handles a complete NPC turn (thoughts + action)
-func GenerateNPCTurn(ctx context.Context, llmService *llm.Service, npcID string, world game.WorldState, gameHistory []string, debug bool, worldEventLines []string) tea.Cmd {
+func GenerateNPCTurn(ctx context.Context, llmService llm.Completer, npcID string, world game.WorldState, gameHistory []string, debug bool, worldEvents []events.WorldEvent) tea.Cmd {
     return func() tea.Msg {
         thoughts := ""
         situation := ""
         if debug {
-            worldContext := game.BuildWorldContext(world, []string{}, npcID)
+            worldContext := game.CachedWorldContext(ctx, world, []string{}, npcID)
             log.Printf("=== NPC TURN START ===")
             log.Printf("NPC: %s", npcID)
             log.Printf("World context length: %d chars", len(worldContext))
         }
 
-        // LLM-driven perception per NPC
+        // Perception per NPC, by rule where obvious and by LLM otherwise
         tracer := otel.Tracer("perception")
-        pctx, pspan := tracer.Start(ctx, "perception.llm")
-        perceivedLines, perr := perception.GeneratePerceivedEventsForNPC(pctx, llmService, npcID, world, worldEventLines, debug)
+        pctx, pspan := tracer.Start(ctx, "perception")
+        perceived, perr := perception.GeneratePerceivedEventsForNPC(pctx, llmService, npcID, world, worldEvents, debug)
+        perceivedLines := events.Lines(perceived)
         if perr != nil && debug {
             log.Printf("[ERROR] Perception error for %s: %v", npcID, perr)
         }
+        if perr != nil {
+            // Fallback: an NPC whose perception failed acts as if it perceived nothing
+            if serr := game.StrictFallback(pctx, "perception parse", perr); serr != nil {
+                pspan.End()
+                return NPCActionMsg{NPCID: npcID, Debug: debug, StrictErr: serr}
+            }
+        }
         if debug {
-            if len(worldEventLines) == 0 {
+            if len(worldEvents) == 0 {
                 log.Printf("[DEBUG] NPC %s event input: (none)", npcID)
             } else {
-                log.Printf("[DEBUG] NPC %s event input (%d): %v", npcID, len(worldEventLines), worldEventLines)
+                log.Printf("[DEBUG] NPC %s event input (%d): %v", npcID, len(worldEvents), events.Lines(worldEvents))
             }
             if len(perceivedLines) == 0 {
                 log.Printf("[DEBUG] NPC %s perceived: (none)", npcID)
@@ -178,36 +221,51 @@ func GenerateNPCTurn(ctx context.Context, llmService *llm.Service, npcID string,
                 log.Printf("[DEBUG] NPC %s perceived (%d): %v", npcID, len(perceivedLines), perceivedLines)
             }
         }
+        // A room's ambience is perceived once, on the NPC's first turn there
+        ambience, heardAmbience := perception.AmbienceEvent(world, npcID)
+        if heardAmbience {
+            perceived = append([]events.WorldEvent{ambience}, perceived...)
+            perceivedLines = events.Lines(perceived)
+        }
+        quiet := perr == nil && isQuietTurn(world, npcID, perceived)
         pspan.SetAttributes(
             attribute.String("npc.id", npcID),
-            attribute.Int("events.input_count", len(worldEventLines)),
+            attribute.Int("events.input_count", len(worldEvents)),
             attribute.Int("events.perceived_count", len(perceivedLines)),
+            attribute.Bool("npc.skipped", quiet),
         )
         pspan.End()
-
-        // Lightweight situation narration to bridge "just happened" and "now"
-        if true { // always try to produce a minimal situation summary
-            sctx, sspan := otel.Tracer("perception").Start(ctx, "perception.situation")
-            s := buildNPCSituationUser(game.BuildWorldContext(world, []string{}, npcID), perceivedLines)
-            req := llm.TextCompletionRequest{
-                SystemPrompt:    `Summarize the immediate situation in 1-2 short sentences in present tense.
-Use only the provided world_context and perceived_events.
-Be concrete and neutral. No invention beyond those details.`,
-                UserPrompt:      s,
-                MaxTokens:       1000,
-                Model:           "gpt-5-mini",
-                ReasoningEffort: "minimal",
+        if quiet {
+            if debug {
+                log.Printf("[DEBUG] NPC %s is far away and perceived nothing; skipping its turn", npcID)
             }
-            sctx = llm.WithOperationType(sctx, "npc.situation")
-            out, serr := llmService.CompleteText(sctx, req)
-            if serr == nil {
-                situation = strings.TrimSpace(out)
-            } else if debug {
-                log.Printf("[ERROR] Situation summary failed for %s: %v", npcID, serr)
+            return NPCActionMsg{NPCID: npcID, Debug: debug, Skipped: true}
+        }
+
+        // What the NPC perceived moves its emotions before it thinks about it
+        shifts := AppraiseEmotions(npcID, perceived)
+        if len(shifts) > 0 {
+            world = world.Clone()
+            npc := world.NPCs[npcID]
+            npc.Emotions = ApplyEmotionShifts(npc.Emotions, shifts)
+            world.NPCs[npcID] = npc
+        }
+
+        // A matching authored line is said verbatim, without asking the LLM
+        if node, ok := authoredLine(world, npcID, perceived); ok {
+            line := dialogue.Render(world, npcID, node)
+            _, dspan := otel.Tracer("dialogue").Start(ctx, "dialogue.authored")
+            dspan.SetAttributes(attribute.String("npc.id", npcID), attribute.String("dialogue.node", node.ID))
+            dspan.End()
+            if debug {
+                log.Printf("[DEBUG] NPC %s says authored dialogue %s", npcID, node.ID)
             }
-            sspan.End()
+            return NPCActionMsg{NPCID: npcID, Action: "say " + line, Debug: debug, Dialogue: &node, EmotionShifts: shifts, AmbienceHeard: ambience.Content}
         }
 
+        // Lightweight situation narration to bridge "just happened" and "now"
+        situation = npcSituation(ctx, llmService, npcID, world, perceivedLines, debug)
+
         thoughtsMsg := GenerateNPCThoughts(ctx, llmService, npcID, world, gameHistory, debug, perceivedLines, situation)()
         if msg, ok := thoughtsMsg.(NPCThoughtsMsg); ok {
             thoughts = msg.Thoughts
@@ -231,6 +289,52 @@ Be concrete and neutral. No invention beyond those details.`,
             Thoughts:      thoughts,
             Action:        action,
             Debug:         debug,
+            EmotionShifts: shifts,
+            AmbienceHeard: ambience.Content,
+        }
+    }
+}
+
+// npcSituation summarizes the NPC's immediate situation in a sentence or two. NPCs in the
+// same room with the same perceptions share one summary per turn when ctx carries a
+// SituationCache.
+func npcSituation(ctx context.Context, llmService llm.Completer, npcID string, world game.WorldState, perceivedLines []string, debug bool) string {
+    location := world.NPCs[npcID].Location
+    key := newSituationKey(game.TurnIndexFromContext(ctx), location, perceivedLines)
+    cache := situationCacheFrom(ctx)
+    if cache != nil {
+        if situation, ok := cache.get(key); ok {
+            if debug {
+                log.Printf("[DEBUG] NPC %s reuses the situation summary for %s", npcID, location)
+            }
+            return situation
         }
     }
+
+    sctx, sspan := otel.Tracer("perception").Start(ctx, "perception.situation")
+    defer sspan.End()
+    sspan.SetAttributes(attribute.String("npc.id", npcID), attribute.String("location", location))
+    s := buildNPCSituationUser(game.CachedWorldContext(ctx, world, []string{}, npcID), perceivedLines)
+    req := llm.TextCompletionRequest{
+        SystemPrompt:    `Summarize the immediate situation in 1-2 short sentences in present tense.
+Use only the provided world_context and perceived_events.
+Be concrete and neutral. No invention beyond those details.`,
+        UserPrompt:      s,
+        MaxTokens:       1000,
+        Model:           "gpt-5-mini",
+        ReasoningEffort: "minimal",
+    }
+    sctx = llm.WithOperationType(sctx, "npc.situation")
+    out, err := llmService.CompleteText(sctx, req)
+    if err != nil {
+        if debug {
+            log.Printf("[ERROR] Situation summary failed for %s: %v", npcID, err)
+        }
+        return ""
+    }
+    situation := strings.TrimSpace(out)
+    if cache != nil {
+        cache.put(key, situation)
+    }
+    return situation
 }
@@ -0,0 +1,47 @@
+package actors
+
+import (
+	"sort"
+	"strings"
+
+	"textadventure/internal/game"
+)
+
+// IndependentPrefix returns how many NPCs from the front of queue can take their turns
+// at once: each must be at least two rooms from every other, so none sees or hears what
+// another does this turn. It is at least 1 for a non-empty queue. Stopping at the first
+// NPC that isn't independent keeps the queue's order for everything after it.
+func IndependentPrefix(world game.WorldState, queue []string) int {
+	if len(queue) == 0 {
+		return 0
+	}
+	n := 1
+	for ; n < len(queue); n++ {
+		location := world.NPCs[queue[n]].Location
+		for _, earlier := range queue[:n] {
+			distance := game.RoomDistance(world.Locations, world.NPCs[earlier].Location, location)
+			if distance >= 0 && distance < 2 {
+				return n
+			}
+		}
+	}
+	return n
+}
+
+// ActionItems returns the IDs of the items an NPC action mentions by ID or name, sorted.
+// Two NPCs whose actions share an item may contend for it, so their turns can't be
+// decided independently.
+func ActionItems(world game.WorldState, action string) []string {
+	text := strings.ToLower(action)
+	var items []string
+	for id, item := range world.Items {
+		for _, name := range []string{id, strings.ReplaceAll(id, "_", " "), item.Name} {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" && strings.Contains(text, name) {
+				items = append(items, id)
+				break
+			}
+		}
+	}
+	sort.Strings(items)
+	return items
+}
@@ -3,6 +3,8 @@ package actors
 import (
     "fmt"
     "strings"
+
+    "textadventure/internal/game"
 )
 
 func buildThoughtsPrompt(npcID string, recentThoughts []string, recentActions []string, personality string, backstory string, coreMemories []string) string {
@@ -51,16 +53,16 @@ Think like an actual human mind:
 - Base thoughts on what you can see, hear, remember, and what's been happening around you
 - Let your personality and background influence your reactions%s
 
-Return only realistic internal thoughts, nothing else. Keep it to one line.`, npcID, npcID, personalityContext, backstoryContext, coreMemoryContext, memoryContext)
+Return only realistic internal thoughts, nothing else. Keep it to one line.`, game.Mention(game.NPCName(npcID), npcID), game.NPCName(npcID), personalityContext, backstoryContext, coreMemoryContext, memoryContext)
 }
 
 // buildThoughtsPromptXML produces a clearer, sectioned system prompt for NPC thinking.
 // It uses simple XML-like tags to make parsing and emphasis reliable.
-func buildThoughtsPromptXML(npcID string, recentThoughts []string, recentActions []string, personality string, backstory string, coreMemories []string) string {
+func buildThoughtsPromptXML(npcID string, recentThoughts []string, recentActions []string, personality string, backstory string, coreMemories []string, goals []string, emotions string) string {
     b := &strings.Builder{}
-    fmt.Fprintf(b, `You are %s. Generate a single internal thought based on your current situation.`, npcID)
+    fmt.Fprintf(b, `You are %s. Generate a single internal thought based on your current situation.`, game.Mention(game.NPCName(npcID), npcID))
     b.WriteString("\n\n<character>\n")
-    fmt.Fprintf(b, "- name: %s\n", npcID)
+    fmt.Fprintf(b, "- name: %s\n", game.NPCName(npcID))
     if strings.TrimSpace(personality) != "" {
         fmt.Fprintf(b, "- personality: %s\n", personality)
     }
@@ -73,6 +75,9 @@ func buildThoughtsPromptXML(npcID string, recentThoughts []string, recentActions
             fmt.Fprintf(b, "  - %s\n", m)
         }
     }
+    if emotions != "" {
+        fmt.Fprintf(b, "- feeling: %s\n", emotions)
+    }
     b.WriteString("</character>\n\n")
 
     b.WriteString("<recent_memory>\n")
@@ -90,6 +95,14 @@ func buildThoughtsPromptXML(npcID string, recentThoughts []string, recentActions
     }
     b.WriteString("</recent_memory>\n\n")
 
+    if len(goals) > 0 {
+        b.WriteString("<goals>\n")
+        for _, g := range goals {
+            fmt.Fprintf(b, "- %s\n", g)
+        }
+        b.WriteString("</goals>\n\n")
+    }
+
     b.WriteString(`<style>
 - one line only
 - present tense; natural and practical
@@ -97,6 +110,9 @@ func buildThoughtsPromptXML(npcID string, recentThoughts []string, recentActions
 - no quotes; no role labels; no narration
 - avoid repeating identical prior thoughts; build on change
 - it's fine to be uncertain or to simply observe; don't force a plan
+- goals are what you're working toward across turns; let them shape what you notice and consider
+- feeling is how strongly you feel each emotion, from 0 to 1; let it color the thought
+- your attitude toward the player, in world_context, colors how you think about them
 </style>`)        
     return b.String()
 }
@@ -141,10 +157,10 @@ func xmlLineIf(tag, val string) string {
     return fmt.Sprintf("<%s>%s</%s>", tag, val, tag)
 }
 
-func buildActionPrompt(npcID string, npcThoughts string, recentActions []string, personality string, backstory string) string {
+func buildActionPrompt(npcID string, npcThoughts string, recentActions []string, personality string, backstory string, voiceExamples []string, goals []string, emotions string) string {
 	memoryContext := ""
 	if len(recentActions) > 0 {
-		memoryContext = fmt.Sprintf("\n\nYour recent actions: %v\nDon't repeat the same action unless something has changed.", recentActions)
+		memoryContext = fmt.Sprintf("\n\nYour recent actions:\n- %s\nDon't repeat the same action unless something has changed.", strings.Join(recentActions, "\n- "))
 	}
 
 	personalityContext := ""
@@ -157,15 +173,30 @@ func buildActionPrompt(npcID string, npcThoughts string, recentActions []string,
 		backstoryContext = fmt.Sprintf("- Background: %s\n", backstory)
 	}
 
+	feelingContext := ""
+	if emotions != "" {
+		feelingContext = fmt.Sprintf("- Feeling right now (0 to 1): %s\n", emotions)
+	}
+
+	goalContext := ""
+	if len(goals) > 0 {
+		goalContext = fmt.Sprintf("\n\nYour current goals (take steps toward them when the moment allows):\n- %s", strings.Join(goals, "\n- "))
+	}
+
+	voiceContext := ""
+	if len(voiceExamples) > 0 {
+		voiceContext = fmt.Sprintf("\n\nLines written for you, to show how you speak (don't repeat them):\n- %s", strings.Join(voiceExamples, "\n- "))
+	}
+
 	return fmt.Sprintf(`You are %s. React realistically to your current situation — you don't have to "pick an action" every turn.
 
 Your character:
 - Name: %s
-%s%s- You act naturally based on what you've noticed and what you're thinking
+%s%s%s- You act naturally based on what you've noticed and what you're thinking
 - You can move between rooms, talk to people, interact with objects, or simply pause to observe or think
 - Only act if it makes sense right now; it's valid to call out, look around, or do nothing
 
-Your current thoughts: "%s"%s
+Your current thoughts: "%s"%s%s%s
 
 Based on your thoughts and the world state, what do you want to do? You can:
 - Move to a different room (e.g., "go to kitchen") 
@@ -175,5 +206,120 @@ Based on your thoughts and the world state, what do you want to do? You can:
 - Call out (e.g., "say Is someone there?")
 - Do nothing (return empty string)
 
-Return only a brief action statement, or an empty string if you don't want to act.`, npcID, npcID, personalityContext, backstoryContext, npcThoughts, memoryContext)
+Return only a brief action statement, or an empty string if you don't want to act.`, game.Mention(game.NPCName(npcID), npcID), game.NPCName(npcID), personalityContext, backstoryContext, feelingContext, npcThoughts, goalContext, memoryContext, voiceContext)
+}
+
+// buildReplyPromptXML is the system prompt for an NPC answering the player, who just
+// spoke to them face to face.
+func buildReplyPromptXML(npcID string, recentThoughts []string, recentActions []string, personality string, backstory string, coreMemories []string, voiceExamples []string) string {
+    b := &strings.Builder{}
+    fmt.Fprintf(b, `You are %s. The player has just spoken to you. Reply to them aloud, in your own voice.`, game.Mention(game.NPCName(npcID), npcID))
+    b.WriteString("\n\n<character>\n")
+    fmt.Fprintf(b, "- name: %s\n", game.NPCName(npcID))
+    if strings.TrimSpace(personality) != "" {
+        fmt.Fprintf(b, "- personality: %s\n", personality)
+    }
+    if strings.TrimSpace(backstory) != "" {
+        fmt.Fprintf(b, "- backstory: %s\n", backstory)
+    }
+    if len(coreMemories) > 0 {
+        b.WriteString("- core_memories:\n")
+        for _, m := range coreMemories {
+            fmt.Fprintf(b, "  - %s\n", m)
+        }
+    }
+    b.WriteString("</character>\n\n")
+
+    b.WriteString("<recent_memory>\n")
+    if len(recentThoughts) > 0 {
+        b.WriteString("- thoughts:\n")
+        for _, t := range recentThoughts {
+            fmt.Fprintf(b, "  - %s\n", t)
+        }
+    }
+    if len(recentActions) > 0 {
+        b.WriteString("- actions:\n")
+        for _, a := range recentActions {
+            fmt.Fprintf(b, "  - %s\n", a)
+        }
+    }
+    b.WriteString("</recent_memory>\n\n")
+
+    if len(voiceExamples) > 0 {
+        b.WriteString("<voice>\n")
+        for _, line := range voiceExamples {
+            fmt.Fprintf(b, "- %s\n", line)
+        }
+        b.WriteString("</voice>\n\n")
+    }
+
+    b.WriteString(`<style>
+- only the words you say: no quotes, no name label, no narration or stage directions
+- one to three sentences, as you would actually talk
+- answer what the player said; you may refuse, deflect or ask something back
+- let your attitude toward the player, in world_context, set how warm or guarded you are
+- only mention what you know from world_context and your memory
+- don't repeat the voice lines; they only show how you speak
+</style>`)
+    return b.String()
+}
+
+// buildReplyUserXML wraps the world and the player's words for the reply step.
+func buildReplyUserXML(worldContext, words string) string {
+    b := &strings.Builder{}
+    b.WriteString("<world_context>\n")
+    b.WriteString(strings.TrimSpace(worldContext))
+    b.WriteString("\n</world_context>\n\n")
+    b.WriteString(xmlLineIf("player_says", strings.TrimSpace(words)))
+    return b.String()
+}
+
+// buildGoalReviewPromptXML is the system prompt for reviewing what an NPC is working
+// toward, every few turns.
+func buildGoalReviewPromptXML(npcID string, personality string, backstory string) string {
+    b := &strings.Builder{}
+    fmt.Fprintf(b, `You review the goals of %s, an NPC in a text adventure, against what they have recently thought and done.`, game.Mention(game.NPCName(npcID), npcID))
+    b.WriteString("\n\n<character>\n")
+    fmt.Fprintf(b, "- name: %s\n", game.NPCName(npcID))
+    if strings.TrimSpace(personality) != "" {
+        fmt.Fprintf(b, "- personality: %s\n", personality)
+    }
+    if strings.TrimSpace(backstory) != "" {
+        fmt.Fprintf(b, "- backstory: %s\n", backstory)
+    }
+    b.WriteString("</character>\n\n")
+
+    fmt.Fprintf(b, `<rules>
+- completed: current goals the recent memory shows achieved, or clearly abandoned; copy them exactly
+- added: new goals the recent memory shows the NPC has taken on, each a short phrase ("find out who took the key")
+- a goal is something pursued over several turns, not a single action or passing thought
+- at most %d goals in all; prefer keeping current goals to adding new ones
+- when nothing has changed, return empty lists
+</rules>`, MaxGoals)
+    return b.String()
+}
+
+// buildGoalReviewUserXML wraps the NPC's current goals and recent memory for the review.
+func buildGoalReviewUserXML(goals []string, recentThoughts []string, recentActions []string) string {
+    b := &strings.Builder{}
+    b.WriteString("<goals>\n")
+    for _, g := range goals {
+        fmt.Fprintf(b, "- %s\n", g)
+    }
+    b.WriteString("</goals>\n\n")
+    b.WriteString("<recent_memory>\n")
+    if len(recentThoughts) > 0 {
+        b.WriteString("- thoughts:\n")
+        for _, t := range recentThoughts {
+            fmt.Fprintf(b, "  - %s\n", t)
+        }
+    }
+    if len(recentActions) > 0 {
+        b.WriteString("- actions:\n")
+        for _, a := range recentActions {
+            fmt.Fprintf(b, "  - %s\n", a)
+        }
+    }
+    b.WriteString("</recent_memory>")
+    return b.String()
 }
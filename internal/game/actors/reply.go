@@ -0,0 +1,66 @@
+package actors
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/dialogue"
+	"textadventure/internal/llm"
+)
+
+// GenerateNPCReply asks an NPC what it says back to the player, who just spoke the
+// given words to it. The reply is in character, drawn from the NPC's personality,
+// backstory and memories, and comes back as the bare words, without quotes.
+func GenerateNPCReply(ctx context.Context, llmService llm.Completer, npcID, words string, world game.WorldState) (string, error) {
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return "", fmt.Errorf("no such NPC %q", npcID)
+	}
+	ctx, span := otel.Tracer("dialogue").Start(ctx, "dialogue.reply")
+	defer span.End()
+	span.SetAttributes(attribute.String("npc.id", npcID))
+
+	turn := game.TurnIndexFromContext(ctx)
+	req := llm.TextCompletionRequest{
+		SystemPrompt: buildReplyPromptXML(npcID,
+			game.RecentMemoryLines(npc.RecentThoughts, turn, RecentMemoryLimit),
+			game.RecentMemoryLines(npc.RecentActions, turn, RecentMemoryLimit),
+			npc.Personality, npc.Backstory, npc.Memories, dialogue.Examples(world, npcID)),
+		UserPrompt:      buildReplyUserXML(game.CachedWorldContext(ctx, world, []string{}, npcID), words),
+		MaxTokens:       2000,
+		Model:           "gpt-5-mini",
+		ReasoningEffort: "minimal",
+	}
+	ctx = llm.WithOperationType(ctx, "npc.reply")
+	ctx = llm.WithGameContext(ctx, map[string]interface{}{
+		"npc_id":   npcID,
+		"location": npc.Location,
+	})
+	out, err := llmService.CompleteText(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+	reply := cleanReply(npcID, out)
+	if reply == "" {
+		return "", fmt.Errorf("%s gave an empty reply", npcID)
+	}
+	return reply, nil
+}
+
+// cleanReply strips what the model adds around the spoken words despite the prompt: the
+// NPC's name as a label ("Elena: ..." or "elena says: ..."), surrounding quotes and line
+// breaks.
+func cleanReply(npcID, text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	label := regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(game.RefID(npcID)) + `(\s+(says|replies))?\s*:\s*`)
+	text = label.ReplaceAllString(text, "")
+	text = strings.Trim(text, `"“” `)
+	return strings.TrimSpace(text)
+}
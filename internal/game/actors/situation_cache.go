@@ -0,0 +1,70 @@
+package actors
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+type situationCacheKey struct{}
+
+// SituationCache shares NPC situation summaries within a turn. NPCs in the same room
+// that perceived the same events get the same summary, so it is generated once. The
+// owner clears it when the turn ends.
+type SituationCache struct {
+	mu      sync.Mutex
+	entries map[situationKey]string
+}
+
+// situationKey includes the turn so a summary written late by a cancelled turn is never
+// served to the next one.
+type situationKey struct {
+	turnIndex     int
+	location      string
+	perceivedHash uint64
+}
+
+// NewSituationCache creates an empty cache.
+func NewSituationCache() *SituationCache {
+	return &SituationCache{entries: make(map[situationKey]string)}
+}
+
+// WithSituationCache attaches the turn's situation cache to ctx.
+func WithSituationCache(ctx context.Context, cache *SituationCache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, situationCacheKey{}, cache)
+}
+
+func situationCacheFrom(ctx context.Context) *SituationCache {
+	cache, _ := ctx.Value(situationCacheKey{}).(*SituationCache)
+	return cache
+}
+
+// Clear drops every summary, ready for the next turn.
+func (c *SituationCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[situationKey]string)
+}
+
+func (c *SituationCache) get(key situationKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	situation, ok := c.entries[key]
+	return situation, ok
+}
+
+func (c *SituationCache) put(key situationKey, situation string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = situation
+}
+
+func newSituationKey(turnIndex int, location string, perceivedLines []string) situationKey {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(perceivedLines, "\x00")))
+	return situationKey{turnIndex: turnIndex, location: location, perceivedHash: h.Sum64()}
+}
@@ -0,0 +1,27 @@
+package actors
+
+import (
+	"regexp"
+	"strings"
+)
+
+// speechAction matches NPC actions that are just something said aloud, as the action
+// prompt asks for them: "say Hello there!", "call out: Is someone there?".
+var speechAction = regexp.MustCompile(`(?is)^\s*(say|says|shout|shouts|whisper|whispers|call out|calls out|reply|replies)\b\s*(to \w+)?\s*[:,]?\s*(.+)$`)
+
+// ParseNPCSpeech reports whether an NPC action is speech and returns the words spoken,
+// without the verb or surrounding quotes. Actions that do something else ("take key",
+// "go to kitchen") are not speech.
+func ParseNPCSpeech(action string) (string, bool) {
+	match := speechAction.FindStringSubmatch(action)
+	if match == nil {
+		return "", false
+	}
+	quote := strings.TrimSpace(match[3])
+	quote = strings.Trim(quote, `"“”`)
+	quote = strings.TrimSpace(quote)
+	if quote == "" {
+		return "", false
+	}
+	return quote, true
+}
@@ -0,0 +1,64 @@
+package actors
+
+import (
+	"sort"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+)
+
+// Weights for OrderNPCTurns. Starvation grows without bound, so an NPC that keeps
+// losing on proximity still gets a turn after a few rounds.
+const (
+	starvationWeight = 3 // per turn since the NPC last acted
+	colocatedWeight  = 4 // NPC shares the player's location
+	adjacentWeight   = 2 // NPC is one exit away from the player
+	activityWeight   = 2 // this turn's world events happened at the NPC's location
+)
+
+// NPCTurnScore is an NPC's place in the NPC phase queue and why.
+type NPCTurnScore struct {
+	NPCID     string
+	Score     int
+	IdleTurns int
+}
+
+// OrderNPCTurns ranks every NPC for the NPC phase by a fairness score: turns since it
+// last acted (idleTurns), closeness to the player, and whether this turn's events
+// happened where it is. Higher scores go first; ties are broken by NPC ID so the order
+// is deterministic. NPCs have no schedules yet, so schedule pressure isn't a factor.
+func OrderNPCTurns(world game.WorldState, idleTurns map[string]int, worldEvents []events.WorldEvent) []NPCTurnScore {
+	activeLocations := make(map[string]bool)
+	for _, e := range worldEvents {
+		if e.Location != "" {
+			activeLocations[e.Location] = true
+		}
+	}
+	adjacent := make(map[string]bool)
+	for _, dest := range world.Locations[world.Location].Exits {
+		adjacent[dest] = true
+	}
+
+	scores := make([]NPCTurnScore, 0, len(world.NPCs))
+	for npcID, npc := range world.NPCs {
+		idle := idleTurns[npcID]
+		score := idle * starvationWeight
+		switch {
+		case npc.Location == world.Location:
+			score += colocatedWeight
+		case adjacent[npc.Location]:
+			score += adjacentWeight
+		}
+		if activeLocations[npc.Location] {
+			score += activityWeight
+		}
+		scores = append(scores, NPCTurnScore{NPCID: npcID, Score: score, IdleTurns: idle})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].NPCID < scores[j].NPCID
+	})
+	return scores
+}
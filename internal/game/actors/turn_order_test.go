@@ -0,0 +1,97 @@
+package actors
+
+import (
+	"slices"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+)
+
+// orderWorld has the player in the hall, with the study next door and the attic two
+// rooms away. The cellar isn't connected.
+func orderWorld(npcs map[string]string) game.WorldState {
+	world := game.WorldState{
+		Location: "hall",
+		Locations: map[string]game.LocationInfo{
+			"hall":   {Exits: map[string]string{"north": "study"}},
+			"study":  {Exits: map[string]string{"south": "hall", "up": "attic"}},
+			"attic":  {Exits: map[string]string{"down": "study"}},
+			"cellar": {},
+		},
+		NPCs: make(map[string]game.NPCInfo),
+	}
+	for npcID, location := range npcs {
+		world.NPCs[npcID] = game.NPCInfo{Location: location}
+	}
+	return world
+}
+
+func TestOrderNPCTurns(t *testing.T) {
+	tests := []struct {
+		name   string
+		npcs   map[string]string
+		idle   map[string]int
+		events []events.WorldEvent
+		want   []string
+		scores []int
+	}{
+		{
+			name:   "proximity",
+			npcs:   map[string]string{"attic": "attic", "hall": "hall", "study": "study"},
+			want:   []string{"hall", "study", "attic"},
+			scores: []int{colocatedWeight, adjacentWeight, 0},
+		},
+		{
+			name:   "ties broken by ID",
+			npcs:   map[string]string{"marcus": "attic", "elena": "attic", "bob": "attic"},
+			want:   []string{"bob", "elena", "marcus"},
+			scores: []int{0, 0, 0},
+		},
+		{
+			name:   "starvation beats proximity",
+			npcs:   map[string]string{"near": "hall", "far": "attic"},
+			idle:   map[string]int{"far": 2},
+			want:   []string{"far", "near"},
+			scores: []int{2 * starvationWeight, colocatedWeight},
+		},
+		{
+			name:   "one idle turn is not enough",
+			npcs:   map[string]string{"near": "hall", "far": "attic"},
+			idle:   map[string]int{"far": 1},
+			want:   []string{"near", "far"},
+			scores: []int{colocatedWeight, starvationWeight},
+		},
+		{
+			name:   "activity at the NPC's location",
+			npcs:   map[string]string{"zed": "attic", "amy": "attic", "cy": "cellar"},
+			events: []events.WorldEvent{{Location: "cellar"}, {Location: ""}},
+			want:   []string{"cy", "amy", "zed"},
+			scores: []int{activityWeight, 0, 0},
+		},
+		{
+			name:   "activity adds to proximity",
+			npcs:   map[string]string{"hall": "hall", "study": "study"},
+			events: []events.WorldEvent{{Location: "study"}},
+			want:   []string{"hall", "study"}, // tied, so by ID
+			scores: []int{adjacentWeight + activityWeight, colocatedWeight},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := OrderNPCTurns(orderWorld(tt.npcs), tt.idle, tt.events)
+			var ids []string
+			var scores []int
+			for _, entry := range order {
+				ids = append(ids, entry.NPCID)
+				scores = append(scores, entry.Score)
+				if entry.IdleTurns != tt.idle[entry.NPCID] {
+					t.Errorf("%s idle turns = %d", entry.NPCID, entry.IdleTurns)
+				}
+			}
+			if !slices.Equal(ids, tt.want) || !slices.Equal(scores, tt.scores) {
+				t.Errorf("order = %q %v, want %q %v", ids, scores, tt.want, tt.scores)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package game
+
+import "strings"
+
+// AmbienceDue returns the ambience of the NPC's location if the NPC has not yet
+// perceived it there. Each NPC perceives a room's ambience once, on its first turn
+// there; leaving and coming back doesn't repeat it, but a changed ambience is new.
+func AmbienceDue(world WorldState, npcID string) (string, bool) {
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return "", false
+	}
+	ambience := strings.TrimSpace(world.Locations[npc.Location].Ambience)
+	if ambience == "" || npc.AmbienceHeard[npc.Location] == ambience {
+		return "", false
+	}
+	return ambience, true
+}
+
+// IsAmbience reports whether text only restates the ambience of a location, as an
+// event summary sometimes does. Such lines are background, not something that happened.
+func IsAmbience(world WorldState, location, text string) bool {
+	ambience := normalizeAmbience(world.Locations[location].Ambience)
+	if ambience == "" {
+		return false
+	}
+	text = normalizeAmbience(text)
+	return strings.Contains(text, ambience)
+}
+
+func normalizeAmbience(text string) string {
+	return strings.Trim(strings.ToLower(strings.Join(strings.Fields(text), " ")), " .!")
+}
+
+// formatAmbience renders a location's ambience for world context.
+func formatAmbience(location LocationInfo) string {
+	if strings.TrimSpace(location.Ambience) == "" {
+		return ""
+	}
+	return "Ambience (ongoing background, not an event): " + strings.TrimSpace(location.Ambience) + "\n"
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KnownConditions lists the player conditions the director may set.
+var KnownConditions = []string{"injured", "exhausted", "soaked", "cold"}
+
+// PlayerCondition is a physical state affecting the player. Turns counts how many
+// completed turns the condition has been active, and is what decay rules look at.
+type PlayerCondition struct {
+	Name  string
+	Turns int
+}
+
+// conditionDecayRule clears a condition once it has lasted AfterTurns turns. When
+// IndoorsOnly is set, only turns spent indoors count towards the total.
+type conditionDecayRule struct {
+	AfterTurns  int
+	IndoorsOnly bool
+}
+
+// Injuries don't heal by themselves; everything else wears off.
+var conditionDecayRules = map[string]conditionDecayRule{
+	"soaked":    {AfterTurns: 5, IndoorsOnly: true},
+	"cold":      {AfterTurns: 8, IndoorsOnly: true},
+	"exhausted": {AfterTurns: 6},
+}
+
+// IsKnownCondition reports whether name is one of KnownConditions.
+func IsKnownCondition(name string) bool {
+	for _, known := range KnownConditions {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCondition reports whether the player currently has the named condition.
+func (ws WorldState) HasCondition(name string) bool {
+	for _, condition := range ws.Conditions {
+		if condition.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AdvanceConditions ages the player's conditions by one turn and drops any whose decay
+// rule is satisfied. It returns the remaining conditions and the names that expired.
+func AdvanceConditions(world WorldState) ([]PlayerCondition, []string) {
+	indoors := !world.Locations[world.Location].Outdoors
+
+	var remaining []PlayerCondition
+	var expired []string
+	for _, condition := range world.Conditions {
+		rule, decays := conditionDecayRules[condition.Name]
+		if decays && (indoors || !rule.IndoorsOnly) {
+			condition.Turns++
+		}
+		if decays && condition.Turns >= rule.AfterTurns {
+			expired = append(expired, condition.Name)
+			continue
+		}
+		remaining = append(remaining, condition)
+	}
+	return remaining, expired
+}
+
+// FormatConditions renders the player's conditions for world context, e.g.
+// "Your condition: soaked, cold". It returns an empty string when there are none.
+func FormatConditions(conditions []PlayerCondition) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		names = append(names, condition.Name)
+	}
+	return fmt.Sprintf("Your condition: %s", strings.Join(names, ", "))
+}
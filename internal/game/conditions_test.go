@@ -0,0 +1,124 @@
+package game
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func conditionNames(conditions []PlayerCondition) []string {
+	var names []string
+	for _, condition := range conditions {
+		names = append(names, condition.Name)
+	}
+	return names
+}
+
+func TestAdvanceConditionsDecay(t *testing.T) {
+	tests := []struct {
+		name        string
+		outdoors    bool
+		conditions  []PlayerCondition
+		wantLeft    []PlayerCondition
+		wantExpired []string
+	}{
+		{
+			name:       "soaked dries indoors",
+			conditions: []PlayerCondition{{Name: "soaked", Turns: 3}},
+			wantLeft:   []PlayerCondition{{Name: "soaked", Turns: 4}},
+		},
+		{
+			name:        "soaked clears after five turns indoors",
+			conditions:  []PlayerCondition{{Name: "soaked", Turns: 4}},
+			wantExpired: []string{"soaked"},
+		},
+		{
+			name:       "soaked stays outdoors",
+			outdoors:   true,
+			conditions: []PlayerCondition{{Name: "soaked", Turns: 4}},
+			wantLeft:   []PlayerCondition{{Name: "soaked", Turns: 4}},
+		},
+		{
+			name:        "exhausted wears off anywhere",
+			outdoors:    true,
+			conditions:  []PlayerCondition{{Name: "exhausted", Turns: 5}},
+			wantExpired: []string{"exhausted"},
+		},
+		{
+			name:       "injured never heals by itself",
+			conditions: []PlayerCondition{{Name: "injured", Turns: 100}},
+			wantLeft:   []PlayerCondition{{Name: "injured", Turns: 100}},
+		},
+		{
+			name:        "only expired conditions are dropped",
+			conditions:  []PlayerCondition{{Name: "cold", Turns: 7}, {Name: "injured"}, {Name: "soaked", Turns: 1}},
+			wantLeft:    []PlayerCondition{{Name: "injured"}, {Name: "soaked", Turns: 2}},
+			wantExpired: []string{"cold"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := NewDefaultWorldState()
+			foyer := world.Locations["foyer"]
+			foyer.Outdoors = tt.outdoors
+			world.Locations["foyer"] = foyer
+			world.Conditions = tt.conditions
+
+			left, expired := AdvanceConditions(world)
+			if !reflect.DeepEqual(left, tt.wantLeft) {
+				t.Errorf("remaining = %+v, want %+v", left, tt.wantLeft)
+			}
+			if !reflect.DeepEqual(expired, tt.wantExpired) {
+				t.Errorf("expired = %v, want %v", expired, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestSoakedClearsAfterFiveTurnsIndoors(t *testing.T) {
+	world := NewDefaultWorldState()
+	world.Conditions = []PlayerCondition{{Name: "soaked"}}
+	for turn := 1; turn <= 5; turn++ {
+		var expired []string
+		world.Conditions, expired = AdvanceConditions(world)
+		if turn < 5 && len(expired) > 0 {
+			t.Fatalf("soaked expired after %d turns", turn)
+		}
+		if turn == 5 && !reflect.DeepEqual(expired, []string{"soaked"}) {
+			t.Fatalf("after 5 turns expired = %v, conditions = %v", expired, conditionNames(world.Conditions))
+		}
+	}
+	if world.HasCondition("soaked") {
+		t.Error("player is still soaked")
+	}
+}
+
+func TestIsKnownCondition(t *testing.T) {
+	for _, name := range KnownConditions {
+		if !IsKnownCondition(name) {
+			t.Errorf("%q is not known", name)
+		}
+	}
+	for _, name := range []string{"", "wet", "Soaked", "poisoned"} {
+		if IsKnownCondition(name) {
+			t.Errorf("%q is known", name)
+		}
+	}
+}
+
+func TestConditionsInWorldContext(t *testing.T) {
+	if got := FormatConditions(nil); got != "" {
+		t.Errorf("FormatConditions(nil) = %q, want empty", got)
+	}
+	world := NewDefaultWorldState()
+	if strings.Contains(BuildWorldContext(world, nil), "Your condition") {
+		t.Error("context mentions a condition the player doesn't have")
+	}
+	world.Conditions = []PlayerCondition{{Name: "soaked"}, {Name: "cold", Turns: 2}}
+	if !strings.Contains(BuildWorldContext(world, nil), "Your condition: soaked, cold\n") {
+		t.Errorf("context does not list the conditions:\n%s", BuildWorldContext(world, nil))
+	}
+	if strings.Contains(BuildWorldContext(world, nil, "elena"), "Your condition") {
+		t.Error("an NPC's context lists the player's condition as its own")
+	}
+}
@@ -0,0 +1,192 @@
+// Package conflict resolves contested actions, like grabbing a knife before someone else
+// does or blocking a doorway, so the outcome comes from the actors' state and a seeded
+// roll rather than from whatever the narrator finds dramatic.
+package conflict
+
+import (
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/rng"
+)
+
+// Die is the size of the die each side rolls.
+const Die = 20
+
+// Modifiers applied to a side's roll.
+const (
+	// DefenderBonus goes to the side being contested, which holds its ground. Ties go
+	// to the defender too.
+	DefenderBonus = 1
+	// PossessionBonus goes to a side already holding the item at stake.
+	PossessionBonus = 2
+)
+
+// conditionPenalties are what the player's conditions cost them in a contest.
+var conditionPenalties = map[string]int{
+	"injured":   -3,
+	"exhausted": -2,
+	"cold":      -1,
+	"soaked":    -1,
+}
+
+// Contest is one actor's attempt against another. Actors are "player" or NPC IDs.
+type Contest struct {
+	Attacker string
+	Defender string
+	Action   string // what the attacker attempts, e.g. "grab the knife before she does"
+	Stakes   string // what is at stake: an item ID, or a short description
+}
+
+// Side is one actor's part in a resolved contest.
+type Side struct {
+	Actor    string
+	Roll     int
+	Modifier int
+	Reasons  []string // what made up Modifier, e.g. "injured -3"
+}
+
+// Total is the roll plus the modifier.
+func (s Side) Total() int {
+	return s.Roll + s.Modifier
+}
+
+// Outcome is a resolved contest.
+type Outcome struct {
+	Contest
+	Attacker Side
+	Defender Side
+}
+
+// AttackerWins reports whether the attacker beat the defender. Ties go to the defender.
+func (o Outcome) AttackerWins() bool {
+	return o.Attacker.Total() > o.Defender.Total()
+}
+
+// Winner returns the actor who won.
+func (o Outcome) Winner() string {
+	if o.AttackerWins() {
+		return o.Attacker.Actor
+	}
+	return o.Defender.Actor
+}
+
+// Loser returns the actor who lost.
+func (o Outcome) Loser() string {
+	if o.AttackerWins() {
+		return o.Defender.Actor
+	}
+	return o.Attacker.Actor
+}
+
+// Summary describes the outcome for the narrator and the event log, e.g.
+// "Contest over knife (grab the knife): player beats elena, 15 to 9".
+func (o Outcome) Summary() string {
+	winner, loser := o.Attacker, o.Defender
+	if !o.AttackerWins() {
+		winner, loser = o.Defender, o.Attacker
+	}
+	return fmt.Sprintf("Contest over %s (%s): %s beats %s, %s to %s",
+		o.Stakes, o.Action, game.RefID(winner.Actor), game.RefID(loser.Actor), describe(winner), describe(loser))
+}
+
+func describe(side Side) string {
+	if len(side.Reasons) == 0 {
+		return fmt.Sprintf("%d", side.Total())
+	}
+	return fmt.Sprintf("%d (rolled %d, %s)", side.Total(), side.Roll, strings.Join(side.Reasons, ", "))
+}
+
+// Resolve decides a contest between two actors in the same room. Each side rolls a die
+// from stream and adds its modifiers (see Modifiers); the higher total wins. The same
+// world, contest and stream state always give the same outcome.
+func Resolve(world game.WorldState, contest Contest, stream *rng.Stream) (Outcome, error) {
+	if contest.Attacker == contest.Defender {
+		return Outcome{}, fmt.Errorf("%s can't contest against themselves", contest.Attacker)
+	}
+	attackerLocation, err := actorLocation(world, contest.Attacker)
+	if err != nil {
+		return Outcome{}, err
+	}
+	defenderLocation, err := actorLocation(world, contest.Defender)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if attackerLocation != defenderLocation {
+		return Outcome{}, fmt.Errorf("%s and %s are not in the same place", contest.Attacker, contest.Defender)
+	}
+
+	outcome := Outcome{Contest: contest}
+	outcome.Attacker = side(world, contest, contest.Attacker, false)
+	outcome.Defender = side(world, contest, contest.Defender, true)
+	outcome.Attacker.Roll = stream.Intn(Die) + 1
+	outcome.Defender.Roll = stream.Intn(Die) + 1
+	return outcome, nil
+}
+
+func side(world game.WorldState, contest Contest, actor string, defending bool) Side {
+	modifier, reasons := Modifiers(world, contest, actor, defending)
+	return Side{Actor: actor, Modifier: modifier, Reasons: reasons}
+}
+
+// Modifiers returns what actor adds to its roll in contest, and why: penalties for the
+// player's conditions, DefenderBonus when defending and PossessionBonus when the actor
+// already holds the item at stake. NPCs have no conditions, and dispositions aren't
+// recorded in the world yet, so neither affects NPCs.
+func Modifiers(world game.WorldState, contest Contest, actor string, defending bool) (int, []string) {
+	total := 0
+	var reasons []string
+	add := func(amount int, reason string) {
+		total += amount
+		reasons = append(reasons, fmt.Sprintf("%s %+d", reason, amount))
+	}
+	if actor == "player" {
+		for _, condition := range world.Conditions {
+			if penalty, ok := conditionPenalties[condition.Name]; ok {
+				add(penalty, condition.Name)
+			}
+		}
+	}
+	if defending {
+		add(DefenderBonus, "defending")
+	}
+	if holds(world, actor, contest.Stakes) {
+		add(PossessionBonus, "holds "+contest.Stakes)
+	}
+	return total, reasons
+}
+
+// actorLocation returns where the player or an NPC is.
+func actorLocation(world game.WorldState, actor string) (string, error) {
+	if actor == "player" {
+		return world.Location, nil
+	}
+	npc, ok := world.NPCs[actor]
+	if !ok {
+		return "", fmt.Errorf("unknown actor %q", actor)
+	}
+	return npc.Location, nil
+}
+
+// holds reports whether actor carries the item stakes names.
+func holds(world game.WorldState, actor, stakes string) bool {
+	itemID, err := game.ResolveItemID(world, stakes)
+	if err != nil {
+		return false
+	}
+	if actor == "player" {
+		for _, carried := range world.Inventory {
+			if carried == itemID {
+				return true
+			}
+		}
+		return false
+	}
+	for _, carried := range world.NPCs[actor].Inventory {
+		if carried == itemID {
+			return true
+		}
+	}
+	return false
+}
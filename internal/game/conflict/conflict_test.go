@@ -0,0 +1,111 @@
+package conflict
+
+import (
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/rng"
+)
+
+// sharedRoom has elena in the foyer with the player, holding the knife.
+func sharedRoom() game.WorldState {
+	world := game.NewDefaultWorldState()
+	elena := world.NPCs["elena"]
+	elena.Location = "foyer"
+	elena.Inventory = []string{"knife"}
+	world.NPCs["elena"] = elena
+	world.Items = map[string]game.ItemInfo{"knife": {Name: "kitchen knife", Location: "elena"}}
+	return world
+}
+
+func TestModifiers(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  []string
+		actor       string
+		defending   bool
+		wantTotal   int
+		wantReasons string
+	}{
+		{"unhurt attacker", nil, "player", false, 0, ""},
+		{"injured and soaked attacker", []string{"injured", "soaked"}, "player", false, -4, "injured -3, soaked -1"},
+		{"exhausted defender", []string{"exhausted"}, "player", true, -1, "exhausted -2, defending +1"},
+		{"NPC holding the stakes", []string{"injured"}, "elena", false, 2, "holds knife +2"},
+		{"NPC defending what it holds", nil, "elena", true, 3, "defending +1, holds knife +2"},
+	}
+	for _, tt := range tests {
+		world := sharedRoom()
+		for _, name := range tt.conditions {
+			world.Conditions = append(world.Conditions, game.PlayerCondition{Name: name})
+		}
+		contest := Contest{Attacker: "player", Defender: "elena", Action: "wrest the knife away", Stakes: "knife"}
+		total, reasons := Modifiers(world, contest, tt.actor, tt.defending)
+		if total != tt.wantTotal || strings.Join(reasons, ", ") != tt.wantReasons {
+			t.Errorf("%s: %d %q, want %d %q", tt.name, total, reasons, tt.wantTotal, tt.wantReasons)
+		}
+	}
+}
+
+func TestResolveIsDeterministic(t *testing.T) {
+	world := sharedRoom()
+	contest := Contest{Attacker: "player", Defender: "elena", Action: "wrest the knife away", Stakes: "knife"}
+	first, err := Resolve(world, contest, rng.New(11, "conflict"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := Resolve(world, contest, rng.New(11, "conflict"))
+	if first.Summary() != again.Summary() {
+		t.Errorf("same seed, different outcomes: %q and %q", first.Summary(), again.Summary())
+	}
+	for _, side := range []Side{first.Attacker, first.Defender} {
+		if side.Roll < 1 || side.Roll > Die {
+			t.Errorf("%s rolled %d, outside 1..%d", side.Actor, side.Roll, Die)
+		}
+	}
+	if first.Defender.Modifier != DefenderBonus+PossessionBonus {
+		t.Errorf("defender modifier = %d", first.Defender.Modifier)
+	}
+}
+
+func TestResolveRefusesImpossibleContests(t *testing.T) {
+	apart := game.NewDefaultWorldState() // elena is in the library
+	tests := []struct {
+		name    string
+		world   game.WorldState
+		contest Contest
+		wantErr string
+	}{
+		{"same actor", sharedRoom(), Contest{Attacker: "elena", Defender: "elena"}, "themselves"},
+		{"unknown attacker", sharedRoom(), Contest{Attacker: "butler", Defender: "player"}, "unknown actor"},
+		{"unknown defender", sharedRoom(), Contest{Attacker: "player", Defender: "butler"}, "unknown actor"},
+		{"different rooms", apart, Contest{Attacker: "player", Defender: "elena"}, "not in the same place"},
+	}
+	for _, tt := range tests {
+		_, err := Resolve(tt.world, tt.contest, rng.New(1, "conflict"))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want one mentioning %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	contest := Contest{Attacker: "player", Defender: "elena", Action: "grab the knife", Stakes: "knife"}
+	tie := Outcome{
+		Contest:  contest,
+		Attacker: Side{Actor: "player", Roll: 12},
+		Defender: Side{Actor: "elena", Roll: 11, Modifier: 1, Reasons: []string{"defending +1"}},
+	}
+	if tie.AttackerWins() || tie.Winner() != "elena" || tie.Loser() != "player" {
+		t.Errorf("a tie went to %s, want the defender", tie.Winner())
+	}
+	if got, want := tie.Summary(), "Contest over knife (grab the knife): elena beats player, 12 (rolled 11, defending +1) to 12"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	win := tie
+	win.Attacker.Roll = 13
+	if !win.AttackerWins() || win.Winner() != "player" || win.Loser() != "elena" {
+		t.Errorf("13 against 12 went to %s", win.Winner())
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"slices"
+	"strings"
+)
+
+// ContainerOpenedFact is the fact recorded on a container once the player has opened
+// it or looked inside. Its contents appear in world context only after that, so the
+// narrator can't reveal what the player hasn't seen.
+const ContainerOpenedFact = "The player has looked inside"
+
+// ContainerOpened reports whether the player has opened or looked inside the item.
+func ContainerOpened(item ItemInfo) bool {
+	return slices.Contains(item.Facts, ContainerOpenedFact)
+}
+
+// WithinReach reports whether an actor (the player when actingNPCID is empty) can get
+// at an item: they carry it, or it lies in the room they are in.
+func WithinReach(world WorldState, itemID, actingNPCID string) bool {
+	item, ok := world.Items[itemID]
+	if !ok {
+		return false
+	}
+	if actingNPCID == "" {
+		return slices.Contains(world.Inventory, itemID) || item.Location == "player" || item.Location == world.Location
+	}
+	npc, ok := world.NPCs[actingNPCID]
+	if !ok {
+		return false
+	}
+	return slices.Contains(npc.Inventory, itemID) || item.Location == actingNPCID || item.Location == npc.Location
+}
+
+// formatContainer describes a container's state for world context: locked, not yet
+// opened, or what it holds.
+func formatContainer(item ItemInfo, refs *References) string {
+	switch {
+	case item.Locked:
+		return " [locked container]"
+	case !ContainerOpened(item):
+		return " [closed container; contents unknown until opened]"
+	case len(item.Contains) == 0:
+		return " [open container, empty]"
+	}
+	return " [open container holding: " + strings.Join(refs.Refs(item.Contains), ", ") + "]"
+}
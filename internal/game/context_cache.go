@@ -0,0 +1,114 @@
+package game
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+type contextCacheKey struct{}
+
+// ContextCache memoizes BuildWorldContext output for a single world version.
+// The owner bumps the version whenever it replaces its world, which drops every
+// cached entry built against the previous world.
+type ContextCache struct {
+	mu      sync.Mutex
+	version uint64
+	entries map[worldContextKey]string
+	hits    int
+	misses  int
+}
+
+type worldContextKey struct {
+	perspective string
+	fullMap     bool
+	historyHash uint64
+}
+
+type cacheBinding struct {
+	cache   *ContextCache
+	version uint64
+}
+
+// NewContextCache creates an empty cache.
+func NewContextCache() *ContextCache {
+	return &ContextCache{entries: make(map[worldContextKey]string)}
+}
+
+// WithContextCache attaches the cache and the world version the caller's world snapshot
+// belongs to. Commands dispatched with this context build world context through the cache.
+func WithContextCache(ctx context.Context, cache *ContextCache, worldVersion uint64) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextCacheKey{}, cacheBinding{cache: cache, version: worldVersion})
+}
+
+// CachedWorldContext returns BuildWorldContext output, reusing a previous result when the
+// context carries a cache for the same world version, perspective, map view, and history.
+func CachedWorldContext(ctx context.Context, world WorldState, gameHistory []string, actingNPCID ...string) string {
+	fullMap := fullMapFrom(ctx)
+	binding, ok := ctx.Value(contextCacheKey{}).(cacheBinding)
+	if !ok {
+		return buildWorldContext(world, gameHistory, fullMap, actingNPCID...)
+	}
+	perspective := ""
+	if len(actingNPCID) > 0 {
+		perspective = actingNPCID[0]
+	}
+	key := worldContextKey{perspective: perspective, fullMap: fullMap, historyHash: hashHistory(gameHistory)}
+	return binding.cache.get(binding.version, key, func() string {
+		return buildWorldContext(world, gameHistory, fullMap, actingNPCID...)
+	})
+}
+
+func (c *ContextCache) get(version uint64, key worldContextKey, build func() string) string {
+	c.mu.Lock()
+	if version != c.version {
+		if version < c.version {
+			// Stale snapshot from before a refresh: build without polluting the cache.
+			c.mu.Unlock()
+			return build()
+		}
+		c.version = version
+		c.entries = make(map[worldContextKey]string)
+	}
+	if cached, ok := c.entries[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+		return cached
+	}
+	c.mu.Unlock()
+
+	built := build()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version == c.version {
+		c.entries[key] = built
+	}
+	c.misses++
+	return built
+}
+
+// Invalidate drops every entry and advances the cache to the given world version.
+func (c *ContextCache) Invalidate(version uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version = version
+	c.entries = make(map[worldContextKey]string)
+}
+
+// Stats returns the number of cache hits and misses since creation.
+func (c *ContextCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+func hashHistory(gameHistory []string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(gameHistory, "\x00")))
+	return h.Sum64()
+}
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestContextCacheReusesSameInputs(t *testing.T) {
+	cache := NewContextCache()
+	ctx := WithContextCache(context.Background(), cache, 1)
+	world := NewDefaultWorldState()
+	history := []string{"> look", "The foyer is quiet."}
+
+	first := CachedWorldContext(ctx, world, history)
+	second := CachedWorldContext(ctx, world, history)
+	if first != second {
+		t.Fatal("cached context differs from the first build")
+	}
+	if first != BuildWorldContext(world, history) {
+		t.Error("cached context differs from an uncached build")
+	}
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("hits, misses = %d, %d; want 1, 1", hits, misses)
+	}
+}
+
+func TestContextCacheKeysPerspectiveAndHistory(t *testing.T) {
+	cache := NewContextCache()
+	ctx := WithContextCache(context.Background(), cache, 1)
+	world := NewDefaultWorldState()
+	history := []string{"> look"}
+
+	CachedWorldContext(ctx, world, history)
+	CachedWorldContext(ctx, world, history, "elena")
+	CachedWorldContext(ctx, world, append(history, "> wait"))
+	CachedWorldContext(WithFullMap(ctx), world, history)
+	if hits, misses := cache.Stats(); hits != 0 || misses != 4 {
+		t.Errorf("hits, misses = %d, %d; want 0, 4", hits, misses)
+	}
+}
+
+func TestContextCacheInvalidatedByMutationRefresh(t *testing.T) {
+	cache := NewContextCache()
+	world := NewDefaultWorldState()
+	history := []string{"> go north"}
+
+	before := CachedWorldContext(WithContextCache(context.Background(), cache, 1), world, history)
+
+	// A refresh after the player moved replaces the world and bumps its version.
+	refreshed := world.Clone()
+	refreshed.Location = "study"
+	cache.Invalidate(2)
+	after := CachedWorldContext(WithContextCache(context.Background(), cache, 2), refreshed, history)
+
+	if after == before {
+		t.Fatal("context after the refresh reused the pre-mutation build")
+	}
+	if after != BuildWorldContext(refreshed, history) {
+		t.Error("context after the refresh differs from an uncached build of the new world")
+	}
+	if hits, _ := cache.Stats(); hits != 0 {
+		t.Errorf("hits = %d, want 0", hits)
+	}
+}
+
+func TestContextCacheNewerVersionDropsEntries(t *testing.T) {
+	cache := NewContextCache()
+	world := NewDefaultWorldState()
+
+	CachedWorldContext(WithContextCache(context.Background(), cache, 1), world, nil)
+	// A command bound to the next version arrives before Invalidate was called.
+	moved := world.Clone()
+	moved.Location = "kitchen"
+	got := CachedWorldContext(WithContextCache(context.Background(), cache, 2), moved, nil)
+	if got != BuildWorldContext(moved, nil) {
+		t.Error("newer version was served a stale entry")
+	}
+}
+
+func TestContextCacheStaleSnapshotDoesNotPollute(t *testing.T) {
+	cache := NewContextCache()
+	cache.Invalidate(5)
+	world := NewDefaultWorldState()
+	stale := world.Clone()
+	stale.Location = "library"
+
+	// A command still holding a version 4 snapshot builds its own context.
+	got := CachedWorldContext(WithContextCache(context.Background(), cache, 4), stale, nil)
+	if got != BuildWorldContext(stale, nil) {
+		t.Error("stale snapshot got the wrong context")
+	}
+	current := CachedWorldContext(WithContextCache(context.Background(), cache, 5), world, nil)
+	if current != BuildWorldContext(world, nil) {
+		t.Error("current version was served the stale snapshot's context")
+	}
+}
+
+func TestCachedWorldContextWithoutCache(t *testing.T) {
+	world := NewDefaultWorldState()
+	got := CachedWorldContext(context.Background(), world, nil, "elena")
+	if got != BuildWorldContext(world, nil, "elena") {
+		t.Error("uncached context differs from BuildWorldContext")
+	}
+	if !strings.Contains(got, "library") {
+		t.Errorf("elena's context does not mention where she is:\n%s", got)
+	}
+}
+
+func benchmarkHistory() []string {
+	history := make([]string, 0, 40)
+	for i := 0; i < 20; i++ {
+		history = append(history, "> look around", "Dust hangs in the air of the foyer, and the stairs creak.")
+	}
+	return history
+}
+
+// BenchmarkWorldContextPerTurn builds the contexts one turn needs (director, summarizer,
+// perception, thoughts, action and narration) without a cache.
+func BenchmarkWorldContextPerTurn(b *testing.B) {
+	world := NewDefaultWorldState()
+	history := benchmarkHistory()
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 6; j++ {
+			CachedWorldContext(ctx, world, history)
+		}
+		CachedWorldContext(ctx, world, history, "elena")
+	}
+}
+
+// BenchmarkWorldContextPerTurnCached builds the same contexts through a cache
+// invalidated at the start of every turn, as the UI does.
+func BenchmarkWorldContextPerTurnCached(b *testing.B) {
+	world := NewDefaultWorldState()
+	history := benchmarkHistory()
+	cache := NewContextCache()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		version := uint64(i + 1)
+		cache.Invalidate(version)
+		ctx := WithContextCache(context.Background(), cache, version)
+		for j := 0; j < 6; j++ {
+			CachedWorldContext(ctx, world, history)
+		}
+		CachedWorldContext(ctx, world, history, "elena")
+	}
+}
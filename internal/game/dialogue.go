@@ -0,0 +1,16 @@
+package game
+
+// DialogueNode is a line a scenario author wants an NPC to say exactly, when When holds
+// (see the dialogue package for the condition language). Nodes are checked in order
+// before the NPC's free-form turn; the first that matches is said verbatim.
+type DialogueNode struct {
+	ID      string
+	When    string
+	Line    string   // said verbatim; {location} and {npc} are filled in
+	Options []string // follow-ups offered to the player after the line
+	Effects []ScheduledMutation
+	// QuestUpdates sets quest statuses when the line is said, keyed by quest ID.
+	QuestUpdates map[string]string
+	// Repeat lets the node be said again; otherwise it is said at most once.
+	Repeat bool
+}
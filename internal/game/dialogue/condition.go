@@ -0,0 +1,367 @@
+package dialogue
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"textadventure/internal/game"
+)
+
+// Condition is a parsed trigger condition, evaluated against the world.
+type Condition interface {
+	Eval(world game.WorldState) bool
+	String() string
+}
+
+// Conditions are "and", "or" and "not" over predicates, with parentheses for grouping;
+// "not" binds tightest and "or" loosest. Arguments are bare IDs or double-quoted text:
+//
+//	met(elena) and not spoken(elena, intro)
+//	quest(find_key, active) or (has(brass_key) and at(study))
+//	fact(library, "smells of smoke")
+//
+// An empty condition always holds.
+
+// predicate evaluates one predicate's arguments against the world.
+type predicate struct {
+	arity int
+	eval  func(world game.WorldState, args []string) bool
+}
+
+// predicates are the state checks conditions can use.
+var predicates = map[string]predicate{
+	// met(npc): the player has met the NPC.
+	"met": {1, func(world game.WorldState, args []string) bool {
+		return slices.Contains(world.MetNPCs, args[0])
+	}},
+	// has(item): the player carries the item.
+	"has": {1, func(world game.WorldState, args []string) bool {
+		return slices.Contains(world.Inventory, args[0])
+	}},
+	// holds(npc, item): the NPC carries the item.
+	"holds": {2, func(world game.WorldState, args []string) bool {
+		return slices.Contains(world.NPCs[args[0]].Inventory, args[1])
+	}},
+	// at(location): the player is there.
+	"at": {1, func(world game.WorldState, args []string) bool {
+		return world.Location == args[0]
+	}},
+	// npc_at(npc, location): the NPC is there.
+	"npc_at": {2, func(world game.WorldState, args []string) bool {
+		npc, ok := world.NPCs[args[0]]
+		return ok && npc.Location == args[1]
+	}},
+	// visited(location): the location is on the player's known map.
+	"visited": {1, func(world game.WorldState, args []string) bool {
+		return world.Location == args[0] || slices.Contains(world.VisitedLocations, args[0])
+	}},
+	// condition(name): the player has the condition, e.g. condition(injured).
+	"condition": {1, func(world game.WorldState, args []string) bool {
+		return slices.ContainsFunc(world.Conditions, func(c game.PlayerCondition) bool { return c.Name == args[0] })
+	}},
+	// quest(id, status): the quest has the status. A quest without one is active.
+	"quest": {2, func(world game.WorldState, args []string) bool {
+		for _, quest := range world.Quests {
+			if quest.ID != args[0] {
+				continue
+			}
+			status := quest.Status
+			if status == "" {
+				status = game.QuestActive
+			}
+			return strings.EqualFold(status, args[1])
+		}
+		return false
+	}},
+	// fact(subject, "text"): a fact about the location, NPC or item contains text,
+	// ignoring case.
+	"fact": {2, func(world game.WorldState, args []string) bool {
+		var facts []string
+		if location, ok := world.Locations[args[0]]; ok {
+			facts = append(facts, location.Facts...)
+		}
+		if npc, ok := world.NPCs[args[0]]; ok {
+			facts = append(facts, npc.Facts...)
+		}
+		if item, ok := world.Items[args[0]]; ok {
+			facts = append(facts, item.Facts...)
+		}
+		text := strings.ToLower(args[1])
+		return slices.ContainsFunc(facts, func(fact string) bool { return strings.Contains(strings.ToLower(fact), text) })
+	}},
+	// spoken(npc, node): the NPC has already said the dialogue node.
+	"spoken": {2, func(world game.WorldState, args []string) bool {
+		return slices.Contains(world.NPCs[args[0]].DialogueSpoken, args[1])
+	}},
+}
+
+type always struct{}
+
+func (always) Eval(game.WorldState) bool { return true }
+func (always) String() string            { return "" }
+
+type and struct{ left, right Condition }
+
+func (c and) Eval(world game.WorldState) bool { return c.left.Eval(world) && c.right.Eval(world) }
+func (c and) String() string                  { return fmt.Sprintf("(%s and %s)", c.left, c.right) }
+
+type or struct{ left, right Condition }
+
+func (c or) Eval(world game.WorldState) bool { return c.left.Eval(world) || c.right.Eval(world) }
+func (c or) String() string                  { return fmt.Sprintf("(%s or %s)", c.left, c.right) }
+
+type not struct{ inner Condition }
+
+func (c not) Eval(world game.WorldState) bool { return !c.inner.Eval(world) }
+func (c not) String() string                  { return fmt.Sprintf("not %s", c.inner) }
+
+type call struct {
+	name string
+	args []string
+}
+
+func (c call) Eval(world game.WorldState) bool { return predicates[c.name].eval(world, c.args) }
+
+func (c call) String() string {
+	args := make([]string, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg
+		if !isIdent(arg) {
+			args[i] = fmt.Sprintf("%q", arg)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", c.name, strings.Join(args, ", "))
+}
+
+// Parse reads a condition. Unknown predicates and wrong argument counts are errors, so
+// a scenario's mistakes show up when it loads rather than as nodes that never match.
+func Parse(source string) (Condition, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return always{}, nil
+	}
+	p := &parser{tokens: tokens}
+	condition, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %s at %d", p.peek(), p.peek().pos)
+	}
+	return condition, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // character offset in the source, for errors
+}
+
+func (t token) String() string {
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (t token) keyword(word string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, word)
+}
+
+func lex(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenOpen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenClose, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case r == '"':
+			start := i
+			var text strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					text.WriteRune(runes[i])
+					continue
+				}
+				if runes[i] == '"' {
+					break
+				}
+				text.WriteRune(runes[i])
+			}
+			i++
+			tokens = append(tokens, token{tokenString, text.String(), start})
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+func isIdent(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if !isIdentRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) done() bool {
+	return p.next >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) or() (Condition, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().keyword("or") {
+		p.next++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Condition, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().keyword("and") {
+		p.next++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (Condition, error) {
+	if p.done() {
+		return nil, fmt.Errorf("condition ends too early")
+	}
+	t := p.peek()
+	switch {
+	case t.keyword("not"):
+		p.next++
+		inner, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return not{inner}, nil
+	case t.kind == tokenOpen:
+		p.next++
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenClose, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case t.kind == tokenIdent && !t.keyword("and") && !t.keyword("or"):
+		return p.call()
+	}
+	return nil, fmt.Errorf("unexpected %s at %d", t, t.pos)
+}
+
+func (p *parser) call() (Condition, error) {
+	name := p.peek()
+	p.next++
+	pred, ok := predicates[strings.ToLower(name.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown predicate %q at %d", name.text, name.pos)
+	}
+	if err := p.expect(tokenOpen, "'(' after "+name.text); err != nil {
+		return nil, err
+	}
+	var args []string
+	for !p.done() && p.peek().kind != tokenClose {
+		if len(args) > 0 {
+			if err := p.expect(tokenComma, "','"); err != nil {
+				return nil, err
+			}
+		}
+		if p.done() {
+			break
+		}
+		arg := p.peek()
+		if arg.kind != tokenIdent && arg.kind != tokenString {
+			return nil, fmt.Errorf("expected an argument to %s at %d, got %s", name.text, arg.pos, arg)
+		}
+		args = append(args, arg.text)
+		p.next++
+	}
+	if err := p.expect(tokenClose, "')' after "+name.text+" arguments"); err != nil {
+		return nil, err
+	}
+	if len(args) != pred.arity {
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", name.text, pred.arity, len(args))
+	}
+	return call{name: strings.ToLower(name.text), args: args}, nil
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if p.done() {
+		return fmt.Errorf("expected %s, but the condition ends", what)
+	}
+	if t := p.peek(); t.kind != kind {
+		return fmt.Errorf("expected %s at %d, got %s", what, t.pos, t)
+	}
+	p.next++
+	return nil
+}
@@ -0,0 +1,174 @@
+package dialogue
+
+import (
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+// conditionWorld has the player in the study with the brass key, having met elena,
+// who waits in the library holding a letter and has already given her intro.
+func conditionWorld() game.WorldState {
+	world := game.NewDefaultWorldState()
+	world.Location = "study"
+	world.VisitedLocations = []string{"foyer", "kitchen"}
+	world.Inventory = []string{"brass_key"}
+	world.MetNPCs = []string{"elena"}
+	world.Conditions = []game.PlayerCondition{{Name: "soaked"}}
+	world.Quests = []game.Quest{
+		{ID: "find_key", Status: "Completed"},
+		{ID: "escape"},
+	}
+	library := world.Locations["library"]
+	library.Facts = []string{"The air Smells Of Smoke"}
+	world.Locations["library"] = library
+	elena := world.NPCs["elena"]
+	elena.Inventory = []string{"letter"}
+	elena.Facts = []string{"afraid of the dark"}
+	elena.DialogueSpoken = []string{"intro"}
+	world.NPCs["elena"] = elena
+	world.Items = map[string]game.ItemInfo{
+		"brass_key": {Name: "brass key", Location: "player", Facts: []string{"engraved with an owl"}},
+		"letter":    {Name: "letter", Location: "elena"},
+	}
+	return world
+}
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"met(elena)", "met(elena)"},
+		{"met(elena) and has(brass_key)", "(met(elena) and has(brass_key))"},
+		{"at(study) or at(foyer) or at(library)", "((at(study) or at(foyer)) or at(library))"},
+		// "and" binds tighter than "or", "not" tighter than both
+		{"at(study) or met(elena) and has(key)", "(at(study) or (met(elena) and has(key)))"},
+		{"not met(elena) and at(study)", "(not met(elena) and at(study))"},
+		{"not (met(elena) and at(study))", "not (met(elena) and at(study))"},
+		{"(at(study) or at(foyer)) and has(key)", "((at(study) or at(foyer)) and has(key))"},
+		{"not not at(study)", "not not at(study)"},
+		// Keywords and predicate names ignore case
+		{"MET(elena) AND Not at(study) Or has(key)", "((met(elena) and not at(study)) or has(key))"},
+		// Quoted arguments are unquoted, and quoted again only when they need it
+		{`fact(library, "smells of smoke")`, `fact(library, "smells of smoke")`},
+		{`met("elena")`, "met(elena)"},
+		{`fact(elena, "says \"hush\" \\ softly")`, `fact(elena, "says \"hush\" \\ softly")`},
+		{`fact(elena, "")`, `fact(elena, "")`},
+		{"npc_at(elena, library.west-wing_2)", "npc_at(elena, library.west-wing_2)"},
+	}
+	for _, tt := range tests {
+		cond, err := Parse(tt.source)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.source, err)
+			continue
+		}
+		if got := cond.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.source, got, tt.want)
+		}
+		// The printed form reads back as the same condition
+		again, err := Parse(cond.String())
+		if err != nil || again.String() != tt.want {
+			t.Errorf("reparsing %q: %v, %v", cond.String(), again, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"knows(elena)", `unknown predicate "knows" at 0`},
+		{"met(elena) and Sees(elena)", `unknown predicate "Sees" at 15`},
+		{"met()", "met takes 1 argument(s), got 0"},
+		{"holds(elena)", "holds takes 2 argument(s), got 1"},
+		{"at(study, foyer)", "at takes 1 argument(s), got 2"},
+		{`fact(library, "smoke`, "unterminated string at 14"},
+		{`fact(library, "smoke\"`, "unterminated string at 14"},
+		{"met(elena) & at(study)", `unexpected '&' at 11`},
+		{"met(elena) at(study)", `unexpected "at" at 11`},
+		{"met(elena))", `unexpected ")" at 10`},
+		{"and met(elena)", `unexpected "and" at 0`},
+		{"met(elena) or", "condition ends too early"},
+		{"not", "condition ends too early"},
+		{"(met(elena)", "expected ')', but the condition ends"},
+		{"met", "expected '(' after met, but the condition ends"},
+		{"met elena", `expected '(' after met at 4, got "elena"`},
+		{"met(elena", "expected ')' after met arguments, but the condition ends"},
+		{"holds(elena letter)", `expected ',' at 12, got "letter"`},
+		{"holds(elena,)", `expected an argument to holds at 12, got ")"`},
+		{"met(,)", `expected an argument to met at 4, got ","`},
+	}
+	for _, tt := range tests {
+		cond, err := Parse(tt.source)
+		if err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", tt.source, cond)
+			continue
+		}
+		if err.Error() != tt.wantErr {
+			t.Errorf("Parse(%q) error = %q, want %q", tt.source, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"", true},
+		{"met(elena)", true},
+		{"met(marcus)", false},
+		{"has(brass_key)", true},
+		{"has(letter)", false},
+		{"holds(elena, letter)", true},
+		{"holds(elena, brass_key)", false},
+		{"holds(marcus, letter)", false},
+		{"at(study)", true},
+		{"at(foyer)", false},
+		{"npc_at(elena, library)", true},
+		{"npc_at(elena, study)", false},
+		{"npc_at(marcus, library)", false},
+		// The current location counts as visited even before it is recorded
+		{"visited(study)", true},
+		{"visited(kitchen)", true},
+		{"visited(library)", false},
+		{"condition(soaked)", true},
+		{"condition(injured)", false},
+		{"quest(find_key, completed)", true},
+		{"quest(find_key, active)", false},
+		{"quest(escape, active)", true},
+		{"quest(escape, ACTIVE)", true},
+		{"quest(unknown, active)", false},
+		// Facts match by case-insensitive substring, on locations, NPCs and items
+		{`fact(library, "smells of smoke")`, true},
+		{`fact(library, "smells of roses")`, false},
+		{`fact(elena, "DARK")`, true},
+		{`fact(brass_key, owl)`, true},
+		{`fact(foyer, smoke)`, false},
+		{`fact(nowhere, smoke)`, false},
+		{"spoken(elena, intro)", true},
+		{"spoken(elena, warning)", false},
+		{"spoken(marcus, intro)", false},
+		{"met(elena) and not spoken(elena, warning)", true},
+		{"met(elena) and not spoken(elena, intro)", false},
+		{"at(foyer) or has(brass_key)", true},
+		{"at(foyer) or has(letter)", false},
+		{"not (at(foyer) or has(letter))", true},
+		{"quest(find_key, completed) and (at(foyer) or holds(elena, letter))", true},
+	}
+	world := conditionWorld()
+	for _, tt := range tests {
+		cond, err := Parse(tt.source)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.source, err)
+			continue
+		}
+		if got := cond.Eval(world); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package dialogue matches scenario-authored NPC lines against the world. Authors give
+// an NPC dialogue nodes for conversations the plot depends on; when a node's condition
+// holds, the NPC says its line verbatim instead of improvising one.
+package dialogue
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"textadventure/internal/game"
+)
+
+// maxExamples caps the authored lines shown to the LLM as examples of an NPC's voice.
+const maxExamples = 3
+
+// parsed caches conditions by source, since the same nodes are checked every turn.
+var parsed sync.Map // string -> Condition
+
+func condition(source string) (Condition, error) {
+	if cached, ok := parsed.Load(source); ok {
+		return cached.(Condition), nil
+	}
+	cond, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Store(source, cond)
+	return cond, nil
+}
+
+// Match returns the first of the NPC's nodes whose condition holds and that it may
+// still say. Nodes whose condition doesn't parse never match; Check reports them.
+func Match(world game.WorldState, npcID string) (game.DialogueNode, bool) {
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return game.DialogueNode{}, false
+	}
+	for _, node := range npc.Dialogue {
+		if !node.Repeat && slices.Contains(npc.DialogueSpoken, node.ID) {
+			continue
+		}
+		cond, err := condition(node.When)
+		if err != nil {
+			continue
+		}
+		if cond.Eval(world) {
+			return node, true
+		}
+	}
+	return game.DialogueNode{}, false
+}
+
+// Render fills a node's line in for the world: {location} becomes the name of the
+// player's location and {npc} the speaking NPC's name.
+func Render(world game.WorldState, npcID string, node game.DialogueNode) string {
+	return strings.NewReplacer(
+		"{location}", game.DisplayName(world, world.Location),
+		"{npc}", game.NPCName(npcID),
+	).Replace(node.Line)
+}
+
+// Examples returns a few of the NPC's authored lines, to show the LLM the NPC's voice
+// when no node matches.
+func Examples(world game.WorldState, npcID string) []string {
+	var examples []string
+	for _, node := range world.NPCs[npcID].Dialogue {
+		if len(examples) == maxExamples {
+			break
+		}
+		if line := strings.TrimSpace(node.Line); line != "" {
+			examples = append(examples, line)
+		}
+	}
+	return examples
+}
+
+// Check reports problems with every NPC's nodes: missing IDs or lines, duplicate IDs and
+// conditions that don't parse.
+func Check(world game.WorldState) []error {
+	var problems []error
+	for _, npcID := range sortedNPCs(world) {
+		seen := map[string]bool{}
+		for i, node := range world.NPCs[npcID].Dialogue {
+			name := node.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+				problems = append(problems, fmt.Errorf("%s dialogue node %s has no id", npcID, name))
+			} else if seen[node.ID] {
+				problems = append(problems, fmt.Errorf("%s has more than one dialogue node %q", npcID, node.ID))
+			}
+			seen[node.ID] = true
+			if strings.TrimSpace(node.Line) == "" {
+				problems = append(problems, fmt.Errorf("%s dialogue node %s has no line", npcID, name))
+			}
+			if _, err := condition(node.When); err != nil {
+				problems = append(problems, fmt.Errorf("%s dialogue node %s: %w", npcID, name, err))
+			}
+		}
+	}
+	return problems
+}
+
+func sortedNPCs(world game.WorldState) []string {
+	ids := make([]string, 0, len(world.NPCs))
+	for id := range world.NPCs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,122 @@
+package dialogue
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+// withDialogue gives elena the nodes in conditionWorld, having said the spoken ones.
+func withDialogue(nodes []game.DialogueNode, spoken ...string) game.WorldState {
+	world := conditionWorld()
+	elena := world.NPCs["elena"]
+	elena.Dialogue = nodes
+	elena.DialogueSpoken = spoken
+	world.NPCs["elena"] = elena
+	return world
+}
+
+func TestMatch(t *testing.T) {
+	nodes := []game.DialogueNode{
+		{ID: "broken", When: "met(elena", Line: "never said"},
+		{ID: "intro", When: "met(elena)", Line: "I don't remember this place."},
+		{ID: "key", When: "has(brass_key)", Line: "Where did you find that key?"},
+		{ID: "lost", When: "at(foyer)", Line: "Not the foyer again."},
+		{ID: "hum", When: "", Line: "She hums to herself.", Repeat: true},
+	}
+	tests := []struct {
+		name   string
+		spoken []string
+		want   string
+	}{
+		{"first node that holds", nil, "intro"},
+		{"spoken nodes are skipped", []string{"intro"}, "key"},
+		{"nodes that don't hold are skipped", []string{"intro", "key"}, "hum"},
+		{"repeatable nodes are said again", []string{"intro", "key", "hum"}, "hum"},
+	}
+	for _, tt := range tests {
+		node, ok := Match(withDialogue(nodes, tt.spoken...), "elena")
+		if !ok || node.ID != tt.want {
+			t.Errorf("%s: Match = %q, %v; want %q", tt.name, node.ID, ok, tt.want)
+		}
+	}
+
+	onlyOnce := nodes[:4]
+	if node, ok := Match(withDialogue(onlyOnce, "intro", "key"), "elena"); ok {
+		t.Errorf("matched %q with every holding node spoken", node.ID)
+	}
+	if node, ok := Match(withDialogue(nodes), "marcus"); ok {
+		t.Errorf("matched %q for an unknown NPC", node.ID)
+	}
+}
+
+func TestRender(t *testing.T) {
+	world := conditionWorld()
+	study := world.Locations["study"]
+	study.Name = "Dusty Study"
+	world.Locations["study"] = study
+
+	node := game.DialogueNode{Line: "{npc} looks around the {location}. \"{npc}, that's me.\""}
+	want := "Elena looks around the Dusty Study. \"Elena, that's me.\""
+	if got := Render(world, "elena", node); got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+	plain := game.DialogueNode{Line: "No placeholders here."}
+	if got := Render(world, "elena", plain); got != plain.Line {
+		t.Errorf("Render = %q, want the line unchanged", got)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	nodes := []game.DialogueNode{
+		{ID: "a", Line: "  First.  "},
+		{ID: "b", Line: "   "},
+		{ID: "c", Line: "Second."},
+		{ID: "d", Line: "Third."},
+		{ID: "e", Line: "Fourth."},
+	}
+	want := []string{"First.", "Second.", "Third."}
+	if got := Examples(withDialogue(nodes), "elena"); !slices.Equal(got, want) {
+		t.Errorf("Examples = %q, want %q", got, want)
+	}
+	if got := Examples(withDialogue(nil), "elena"); got != nil {
+		t.Errorf("Examples without dialogue = %q", got)
+	}
+	if got := Examples(withDialogue(nodes), "marcus"); got != nil {
+		t.Errorf("Examples for an unknown NPC = %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	world := withDialogue([]game.DialogueNode{
+		{ID: "intro", When: "met(elena)", Line: "Hello."},
+		{ID: "", When: "", Line: "Who are you?"},
+		{ID: "intro", When: "", Line: "Hello again."},
+		{ID: "silent", When: "at(study)", Line: " "},
+		{ID: "typo", When: "mett(elena)", Line: "Hm."},
+	})
+	world.NPCs["aaron"] = game.NPCInfo{Location: "kitchen", Dialogue: []game.DialogueNode{
+		{ID: "greet", When: "at(kitchen", Line: "Welcome."},
+	}}
+
+	var got []string
+	for _, err := range Check(world) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"aaron dialogue node greet: expected ')' after at arguments, but the condition ends",
+		"elena dialogue node #2 has no id",
+		`elena has more than one dialogue node "intro"`,
+		"elena dialogue node silent has no line",
+		`elena dialogue node typo: unknown predicate "mett" at 0`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Check =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if problems := Check(conditionWorld()); len(problems) != 0 {
+		t.Errorf("Check on a world without dialogue = %v", problems)
+	}
+}
@@ -0,0 +1,142 @@
+package director
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/game/director/tools"
+	"textadventure/internal/mcp"
+)
+
+// Tools that act on the player or only make sense for one kind of actor, by scope.
+// Every other registered tool is shared.
+var wantToolScopes = map[string]tools.ActorScope{
+	"move_player":             tools.PlayerOnly,
+	"add_to_inventory":        tools.PlayerOnly,
+	"remove_from_inventory":   tools.PlayerOnly,
+	"examine_inventory_item":  tools.PlayerOnly,
+	"open_container":          tools.PlayerOnly,
+	"unlock_door":             tools.PlayerOnly,
+	"speak_to_npc":            tools.PlayerOnly,
+	"give_item_to_npc":        tools.PlayerOnly,
+	"update_relationship":     tools.PlayerOnly,
+	"npc_take_item":           tools.NPCOnly,
+	"npc_drop_item":           tools.NPCOnly,
+	"npc_give_item_to_player": tools.NPCOnly,
+}
+
+func TestRegisteredToolScopes(t *testing.T) {
+	for _, name := range registeredToolNames() {
+		want, ok := wantToolScopes[name]
+		if !ok {
+			want = tools.Shared
+		}
+		if got := toolRegistry[name].Actors(); got != want {
+			t.Errorf("%s is %s, want %s", name, got, want)
+		}
+	}
+	for name := range wantToolScopes {
+		if _, ok := GetTool(name); !ok {
+			t.Errorf("%s is not registered", name)
+		}
+	}
+}
+
+func TestActorScopeAllows(t *testing.T) {
+	tests := []struct {
+		scope  tools.ActorScope
+		player bool
+		npc    bool
+	}{
+		{tools.Shared, true, true},
+		{tools.PlayerOnly, true, false},
+		{tools.NPCOnly, false, true},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.Allows(""); got != tt.player {
+			t.Errorf("%s allows the player = %v, want %v", tt.scope, got, tt.player)
+		}
+		if got := tt.scope.Allows("elena"); got != tt.npc {
+			t.Errorf("%s allows an NPC = %v, want %v", tt.scope, got, tt.npc)
+		}
+	}
+}
+
+func TestToolDescriptionsOfferOnlyAllowedTools(t *testing.T) {
+	for _, actor := range []string{"", "elena"} {
+		described := map[string]bool{}
+		for _, line := range strings.Split(FormatToolDescriptions(nil, actor), "\n") {
+			name, _, _ := strings.Cut(line, "(")
+			described[name] = true
+		}
+		for _, name := range registeredToolNames() {
+			tool := toolRegistry[name]
+			if tool.Usage() == "" {
+				continue
+			}
+			if allowed := tool.Actors().Allows(actor); described[name] != allowed {
+				t.Errorf("actor %q: %s described = %v, allowed = %v", actor, name, described[name], allowed)
+			}
+		}
+	}
+}
+
+func TestNPCPromptListsRestrictedTools(t *testing.T) {
+	restricted := restrictedToolNames("elena")
+	for name, scope := range wantToolScopes {
+		if scope == tools.PlayerOnly && !contains(restricted, name) {
+			t.Errorf("restricted tools for an NPC do not include %s", name)
+		}
+	}
+	for _, name := range restrictedToolNames("") {
+		if wantToolScopes[name] != tools.NPCOnly {
+			t.Errorf("the player is restricted from %s", name)
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// An NPC plan that moves the player must never teleport them, whether it is caught by
+// plan validation or reaches the executor.
+func TestNPCPlanWithMovePlayerIsRejected(t *testing.T) {
+	world := game.NewDefaultWorldState()
+	plan := []MutationRequest{
+		{Tool: "move_npc", Args: map[string]interface{}{"npc_id": "elena", "location": "foyer"}},
+		{Tool: "move_player", Args: map[string]interface{}{"location": "library"}},
+	}
+
+	violations := ValidatePlan(world, plan, "elena")
+	if len(violations) != 1 || !strings.Contains(violations[0], "Rejected move_player") || !strings.Contains(violations[0], "player-only") {
+		t.Errorf("violations = %q", violations)
+	}
+
+	logger := debug.NewLogger(debug.Off, filepath.Join(t.TempDir(), "debug.log"))
+	successes, failures := ExecuteMutations(context.Background(), plan[1:], &mcp.WorldStateClient{}, logger, world, "elena")
+	if len(successes) != 0 {
+		t.Errorf("successes = %q", successes)
+	}
+	if len(failures) != 1 || failures[0] != "elena cannot use move_player: the tool is player-only" {
+		t.Errorf("failures = %q", failures)
+	}
+}
+
+func TestPlayerPlanWithNPCOnlyToolIsRejected(t *testing.T) {
+	world := game.NewDefaultWorldState()
+	plan := []MutationRequest{{Tool: "npc_take_item", Args: map[string]interface{}{"item": "key"}}}
+	violations := ValidatePlan(world, plan)
+	if len(violations) != 1 || !strings.Contains(violations[0], "npc-only") {
+		t.Errorf("violations = %q", violations)
+	}
+}
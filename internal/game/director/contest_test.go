@@ -0,0 +1,59 @@
+package director
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/game/rng"
+	"textadventure/internal/mcp"
+)
+
+// An executed contest runs the winner's mutations against the world and drops the
+// loser's, whichever side wins.
+func TestContestRunsOnlyTheWinnersMutations(t *testing.T) {
+	world := game.NewDefaultWorldState()
+	elena := world.NPCs["elena"]
+	elena.Location = "foyer"
+	world.NPCs["elena"] = elena
+	world.Items = map[string]game.ItemInfo{"candlestick": {Name: "candlestick", Location: "foyer"}}
+	logger := debug.NewLogger(debug.Off, filepath.Join(t.TempDir(), "debug.log"))
+
+	seen := map[string]bool{}
+	for seed := int64(1); seed <= 20; seed++ {
+		rng.Seed(seed)
+		client := mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(world))
+		plan := []MutationRequest{{Tool: "contest", Args: map[string]interface{}{
+			"actor_a": "elena",
+			"actor_b": "player",
+			"action":  "grab the candlestick first",
+			"stakes":  "candlestick",
+			"a_wins":  []interface{}{map[string]interface{}{"tool": "npc_take_item", "args": map[string]interface{}{"item": "candlestick"}}},
+			"b_wins":  []interface{}{map[string]interface{}{"tool": "move_npc", "args": map[string]interface{}{"npc_id": "elena", "location": "kitchen"}}},
+		}}}
+		successes, failures := ExecuteMutations(context.Background(), plan, client, logger, world, "elena")
+		if len(failures) > 0 || len(successes) != 2 {
+			t.Fatalf("seed %d: successes %q, failures %q", seed, successes, failures)
+		}
+
+		after := client.World()
+		elenaWon := strings.Contains(successes[0], "elena beats player")
+		if elenaWon {
+			seen["elena"] = true
+			if after.Items["candlestick"].Location != "elena" || after.NPCs["elena"].Location != "foyer" {
+				t.Errorf("seed %d: elena won but holds nothing or left: %q", seed, successes)
+			}
+		} else {
+			seen["player"] = true
+			if after.Items["candlestick"].Location != "foyer" || after.NPCs["elena"].Location != "kitchen" {
+				t.Errorf("seed %d: player won but elena's win ran: %q", seed, successes)
+			}
+		}
+	}
+	if !seen["elena"] || !seen["player"] {
+		t.Errorf("20 seeds only ever had %v win", seen)
+	}
+}
@@ -0,0 +1,45 @@
+package director
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// ApplyDialogue carries out an authored dialogue node an NPC has just said. Its effects
+// run like a scheduled event's mutations, then the server records the node as said and
+// applies its quest updates, so a node that isn't repeatable never fires twice.
+func ApplyDialogue(ctx context.Context, npcID string, node game.DialogueNode, mcpClient mcp.WorldClient, debugLogger *debug.Logger, world game.WorldState) ([]string, []string) {
+	tracer := otel.Tracer("director")
+	ctx, span := tracer.Start(ctx, "director.apply_dialogue")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("npc.id", npcID),
+		attribute.String("dialogue.node", node.ID),
+		attribute.Int("dialogue.effect_count", len(node.Effects)),
+	)
+
+	mutations := make([]MutationRequest, 0, len(node.Effects))
+	for _, effect := range node.Effects {
+		args := make(map[string]interface{}, len(effect.Args))
+		for key, value := range effect.Args {
+			args[key] = value
+		}
+		mutations = append(mutations, MutationRequest{Tool: effect.Tool, Args: args})
+	}
+	successes, failures := ExecuteMutations(ctx, mutations, mcpClient, debugLogger, world, "")
+	if _, err := mcpClient.RecordDialogue(ctx, npcID, node.ID, node.QuestUpdates); err != nil {
+		failures = append(failures, fmt.Sprintf("record_dialogue %s/%s: %v", npcID, node.ID, err))
+	}
+	span.SetAttributes(
+		attribute.Int("result.success_count", len(successes)),
+		attribute.Int("result.failure_count", len(failures)),
+	)
+	return successes, failures
+}
@@ -3,13 +3,17 @@ package director
 import (
     "context"
     "encoding/json"
+    "errors"
     "fmt"
     "strings"
+    "time"
 
     tea "github.com/charmbracelet/bubbletea"
 
     "textadventure/internal/debug"
     "textadventure/internal/game"
+    "textadventure/internal/game/director/tools"
+    "textadventure/internal/game/events"
     "textadventure/internal/game/sensory"
     "textadventure/internal/llm"
     "textadventure/internal/logging"
@@ -23,14 +27,19 @@ import (
 // It serves as the central controller that interprets user intent and executes corresponding
 // world changes through MCP tools.
 type Director struct {
-	llmService   *llm.Service
-	mcpClient    *mcp.WorldStateClient
+	llmService   llm.Completer
+	mcpClient    mcp.WorldClient
 	debugLogger  *debug.Logger
+	eventStore   *events.Store
 }
 
 // NewDirector creates a new Director with the required dependencies for LLM interaction,
 // world state management, and debug logging.
-func NewDirector(llmService *llm.Service, mcpClient *mcp.WorldStateClient, debugLogger *debug.Logger) *Director {
+func NewDirector(llmService llm.Completer, mcpClient mcp.WorldClient, debugLogger *debug.Logger) *Director {
+	if client, ok := mcpClient.(*mcp.WorldStateClient); ok && client == nil {
+		// A nil *WorldStateClient means no server; keep it comparable to nil
+		mcpClient = nil
+	}
 	return &Director{
 		llmService:  llmService,
 		mcpClient:   mcpClient,
@@ -38,19 +47,29 @@ func NewDirector(llmService *llm.Service, mcpClient *mcp.WorldStateClient, debug
 	}
 }
 
-// IntentBuilder provides a fluent interface for configuring and executing user intent processing.
-// Use ProcessIntent() to create a builder, configure it with With* methods, then call Execute().
+// SetEventStore makes the director log each action's world events to store, under the
+// turn index carried by the action's context (see game.WithTurnIndex).
+func (d *Director) SetEventStore(store *events.Store) {
+	d.eventStore = store
+}
+
+// IntentBuilder configures and runs the director on one action. It is the entry point
+// for every caller: Execute returns a tea.Cmd for the UI, ExecuteSync runs the action on
+// the calling goroutine for headless callers.
+//
+//	cmd, err := d.ProcessIntent(input).WithContext(ctx).WithWorld(world).WithHistory(history).Execute()
 type IntentBuilder struct {
 	director    *Director
 	intent      string
+	ctx         context.Context
 	world       *game.WorldState
 	history     []string
 	actorID     string
 	logger      *logging.CompletionLogger
+	budget      time.Duration
 }
 
 // ProcessIntent creates a new IntentBuilder for the given user intent string.
-// This is the entry point for the fluent API pattern.
 func (d *Director) ProcessIntent(intent string) *IntentBuilder {
 	return &IntentBuilder{
 		director: d,
@@ -58,8 +77,15 @@ func (d *Director) ProcessIntent(intent string) *IntentBuilder {
 	}
 }
 
-// WithWorld sets the current world state context for intent processing.
-// This is required - Execute() will panic if not called.
+// WithContext sets the context the action runs under, carrying the turn's span and
+// cancellation. Without it the action runs under context.Background().
+func (b *IntentBuilder) WithContext(ctx context.Context) *IntentBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// WithWorld sets the current world state context for intent processing. It is
+// required; Execute and ExecuteSync return an error without it.
 func (b *IntentBuilder) WithWorld(world game.WorldState) *IntentBuilder {
 	b.world = &world
 	return b
@@ -83,23 +109,54 @@ func (b *IntentBuilder) WithLogger(logger *logging.CompletionLogger) *IntentBuil
 	return b
 }
 
-func (b *IntentBuilder) Execute() tea.Cmd {
-	return b.ExecuteWithContext(context.Background())
+// WithTurnBudget caps how long the action may take, interpretation, mutations and the
+// world refresh together. An action that runs out of time ends like a cancelled one,
+// reporting the mutations that landed. Zero means no cap.
+func (b *IntentBuilder) WithTurnBudget(budget time.Duration) *IntentBuilder {
+	b.budget = budget
+	return b
 }
 
-func (b *IntentBuilder) ExecuteWithContext(ctx context.Context) tea.Cmd {
+func (b *IntentBuilder) validate() error {
 	if b.world == nil {
-		panic("world state required - call WithWorld() before Execute()")
+		return fmt.Errorf("director: world state required, call WithWorld before executing %q", b.intent)
 	}
-	
-	return b.director.ProcessPlayerActionWithContext(
-		ctx,
-		b.intent,
-		*b.world,
-		b.history,
-		b.logger,
-		b.actorID,
-	)
+	if b.budget < 0 {
+		return fmt.Errorf("director: turn budget must not be negative, got %s", b.budget)
+	}
+	return nil
+}
+
+// Execute returns a command that runs the action and yields its MutationsGeneratedMsg.
+func (b *IntentBuilder) Execute() (tea.Cmd, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	run := *b
+	return func() tea.Msg {
+		return run.run()
+	}, nil
+}
+
+// ExecuteSync runs the action on the calling goroutine and returns its result.
+func (b *IntentBuilder) ExecuteSync() (MutationsGeneratedMsg, error) {
+	if err := b.validate(); err != nil {
+		return MutationsGeneratedMsg{}, err
+	}
+	return b.run(), nil
+}
+
+func (b *IntentBuilder) run() MutationsGeneratedMsg {
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if b.budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.budget)
+		defer cancel()
+	}
+	return b.director.handleAction(ctx, b.intent, *b.world, b.history, b.actorID)
 }
 
 // ActionPlan represents the LLM's interpretation of user intent as a series of mutations.
@@ -111,6 +168,7 @@ type ActionPlan struct {
 type ExecutionResult struct {
 	Successes []string
 	Failures  []string
+	Executed  []MutationRequest // the mutations behind Successes, in the same order
 }
 
 // MutationsGeneratedMsg is the Bubble Tea message sent after processing player actions.
@@ -119,24 +177,40 @@ type MutationsGeneratedMsg struct {
     Successes     []string
     Failures      []string
     SensoryEvents *sensory.SensoryEventResponse
-    WorldEventLines []string
+    WorldEvents   []events.WorldEvent
     NewWorld      game.WorldState
     UserInput     string
     Debug         bool
     ActingNPCID   string
     ActionContext string // What the actor did (for narrator context)
+    RefreshFailed bool   // NewWorld is the pre-turn world because GetWorldState failed
+    Invariants    []game.InvariantViolation // what RepairRefreshedWorld found wrong with NewWorld
+    Cancelled     bool   // the turn was cancelled; only Successes is set
+    StrictErrors  []error // fallbacks strict mode refused this turn (see game.WithStrict)
+    Addressed     []Address // NPCs the player spoke to, who reply before the NPC turns
+}
+
+// Address is the player speaking to an NPC in the same room, as a speak_to_npc mutation.
+type Address struct {
+    NPCID string
+    Words string
 }
 
 // InterpretIntent uses the LLM to understand user input and generate an action plan.
 // It analyzes the user's intent in the context of the current world state and returns
 // a plan containing the specific MCP tool mutations needed to fulfill that intent.
 func (d *Director) InterpretIntent(ctx context.Context, userInput string, world game.WorldState, gameHistory []string, actingNPCID string) (*ActionPlan, error) {
-    toolDescriptions := getCoreDirectorTools()
+    if plan, ok := parseFastPath(userInput, world, actingNPCID); ok {
+        d.debugLogger.Printf("Fast path matched %q: %d mutations", userInput, len(plan.Mutations))
+        return plan, nil
+    }
 
-	actionLabel := getActionLabel(actingNPCID)
+    toolDescriptions := d.directorToolDescriptions(ctx, actingNPCID)
+
+	actionLabel := game.ActionLabel(actingNPCID)
 	
 	req := llm.JSONCompletionRequest{
-		SystemPrompt:    buildDirectorPrompt(toolDescriptions, world, gameHistory, actionLabel, actingNPCID),
+		SystemPrompt:    buildDirectorPrompt(ctx, toolDescriptions, world, gameHistory, actionLabel, actingNPCID),
 		UserPrompt:      fmt.Sprintf("%s: %s", actionLabel, userInput),
 		MaxTokens:       2000,
 		Model:           "gpt-5-mini",
@@ -152,6 +226,10 @@ func (d *Director) InterpretIntent(ctx context.Context, userInput string, world
 	
 	if err := json.Unmarshal([]byte(content), &actionPlan); err != nil {
 		d.debugLogger.Printf("Failed to parse LLM response: %v", err)
+		// Fallback: an unparseable plan plays as a turn where nothing changes
+		if serr := game.StrictFallback(ctx, "director parse", err); serr != nil {
+			return nil, serr
+		}
 		return &ActionPlan{Mutations: []MutationRequest{}}, nil
 	}
 
@@ -177,102 +255,157 @@ func (d *Director) ExecuteIntent(ctx context.Context, userInput string, world ga
 	return d.executeWithRetry(ctx, userInput, world, gameHistory, actingNPCID, actionPlan.Mutations)
 }
 
-func (d *Director) ProcessPlayerAction(userInput string, world game.WorldState, gameHistory []string, logger *logging.CompletionLogger, actingNPCID ...string) tea.Cmd {
-	ctx := context.Background()
-	return d.ProcessPlayerActionWithContext(ctx, userInput, world, gameHistory, logger, actingNPCID...)
-}
-
-func (d *Director) ProcessPlayerActionWithContext(ctx context.Context, userInput string, world game.WorldState, gameHistory []string, logger *logging.CompletionLogger, actingNPCID ...string) tea.Cmd {
-    return func() tea.Msg {
-        tracer := otel.Tracer("director")
-        ctx, span := tracer.Start(ctx, "director.handle_action",
-            trace.WithAttributes(
-                attribute.String("user.input", userInput),
-            ),
-        )
-        // Attach session/turn/game context to the wrapper span
-        llm.CopyGameContextToSpan(ctx, span)
-        defer span.End()
-        var npcID string
-        if len(actingNPCID) > 0 {
-            npcID = actingNPCID[0]
-        }
-        if npcID != "" {
-            span.SetAttributes(attribute.String("acting_npc", npcID))
+// handleAction interprets and executes an action, refreshes the world and summarizes
+// what happened. An empty npcID is the player.
+func (d *Director) handleAction(ctx context.Context, userInput string, world game.WorldState, gameHistory []string, npcID string) MutationsGeneratedMsg {
+    tracer := otel.Tracer("director")
+    ctx, span := tracer.Start(ctx, "director.handle_action",
+        trace.WithAttributes(
+            attribute.String("user.input", userInput),
+        ),
+    )
+    // Attach session/turn/game context to the wrapper span
+    llm.CopyGameContextToSpan(ctx, span)
+    defer span.End()
+    if npcID != "" {
+        span.SetAttributes(attribute.String("acting_npc", npcID))
+    }
+    var strictErrors []error
+    executionResult, err := d.ExecuteIntent(ctx, userInput, world, gameHistory, npcID)
+    if err != nil {
+        executionResult = &ExecutionResult{
+            Successes: []string{},
+            Failures:  []string{fmt.Sprintf("Failed to process action: %v", err)},
         }
-        executionResult, err := d.ExecuteIntent(ctx, userInput, world, gameHistory, npcID)
-        if err != nil {
-            executionResult = &ExecutionResult{
-                Successes: []string{},
-                Failures:  []string{fmt.Sprintf("Failed to process action: %v", err)},
-            }
-            span.RecordError(err)
+        span.RecordError(err)
+        if errors.Is(err, game.ErrStrictFallback) {
+            strictErrors = append(strictErrors, err)
         }
-        
-        mcpWorld, err := d.mcpClient.GetWorldState(ctx)
-        var newWorld game.WorldState
-        if err != nil {
-            newWorld = world
-        } else {
-            newWorld = mcp.MCPToGameWorldState(mcpWorld)
+    }
+    if ctx.Err() != nil {
+        // The turn was cancelled; report what landed so the UI knows its world is stale
+        span.SetAttributes(attribute.Bool("turn.cancelled", true))
+        return MutationsGeneratedMsg{Successes: executionResult.Successes, ActingNPCID: npcID, Cancelled: true}
+    }
+    
+    mcpWorld, err := d.mcpClient.GetWorldState(ctx)
+    var newWorld game.WorldState
+    var invariants []game.InvariantViolation
+    var locationDrift string
+    refreshFailed := err != nil
+    if refreshFailed {
+        newWorld = world
+        span.SetAttributes(attribute.Bool("world.refresh_failed", true))
+        d.debugLogger.Errorf("world refresh failed after %q: %v", userInput, err)
+    } else {
+        newWorld, invariants = RepairRefreshedWorld(span, d.debugLogger, world, mcp.MCPToGameWorldState(mcpWorld))
+        if expected := ExpectedPlayerLocation(world.Location, executionResult.Successes); newWorld.Location != expected {
+            locationDrift = fmt.Sprintf("[WARNING] Location drift: local expected %q, server has %q", expected, newWorld.Location)
+            span.SetAttributes(
+                attribute.String("world.expected_location", expected),
+                attribute.String("world.server_location", newWorld.Location),
+            )
+            d.debugLogger.Printf("%s", locationDrift)
         }
+    }
 
-        // Summarize canonical world event lines for this turn using the LLM
-        worldEventLines := d.summarizeTurnEvents(ctx, userInput, npcID, world, newWorld, executionResult.Successes, executionResult.Failures)
+    // Summarize canonical world event lines for this turn using the LLM
+    worldEvents, err := d.summarizeTurnEvents(ctx, userInput, npcID, world, newWorld, executionResult)
+    if err != nil {
+        strictErrors = append(strictErrors, err)
+    }
+    if d.eventStore != nil {
+        if err := d.eventStore.Append(game.TurnIndexFromContext(ctx), worldEvents); err != nil {
+            d.debugLogger.Errorf("failed to log world events: %v", err)
+        }
+        if err := d.eventStore.AppendItemMoves(game.TurnIndexFromContext(ctx), events.ItemMoves(actorName(npcID), world, newWorld)); err != nil {
+            d.debugLogger.Errorf("failed to log item moves: %v", err)
+        }
+    }
 
-        var allMessages []string
-		if d.debugLogger != nil && d.debugLogger.IsEnabled() {
-			allMessages = append(allMessages, "[MUTATIONS]")
-			if len(executionResult.Successes) > 0 {
-				allMessages = append(allMessages, executionResult.Successes...)
-			}
-			if len(executionResult.Failures) > 0 {
-				for _, failure := range executionResult.Failures {
-					allMessages = append(allMessages, "[ERROR] "+failure)
-				}
-			}
-			if len(executionResult.Successes) == 0 && len(executionResult.Failures) == 0 {
-				allMessages = append(allMessages, "No mutations needed")
+    var allMessages []string
+	if d.debugLogger != nil && d.debugLogger.IsEnabled() {
+		allMessages = append(allMessages, "[MUTATIONS]")
+		if len(executionResult.Successes) > 0 {
+			allMessages = append(allMessages, executionResult.Successes...)
+		}
+		if len(executionResult.Failures) > 0 {
+			for _, failure := range executionResult.Failures {
+				allMessages = append(allMessages, "[ERROR] "+failure)
 			}
-        }
+		}
+		if len(executionResult.Successes) == 0 && len(executionResult.Failures) == 0 {
+			allMessages = append(allMessages, "No mutations needed")
+		}
+		if locationDrift != "" {
+			allMessages = append(allMessages, locationDrift)
+		}
+    }
 
-        // Create action context for narrator (what actually happened)
-        var actionContext string
-        if npcID != "" {
-            actionContext = fmt.Sprintf("%s: %s", strings.ToUpper(npcID), userInput)
-        } else {
-            actionContext = fmt.Sprintf("PLAYER: %s", userInput)
-        }
+    // Create action context for narrator (what actually happened)
+    var actionContext string
+    if npcID != "" {
+        actionContext = fmt.Sprintf("%s: %s", game.RefID(npcID), userInput)
+    } else {
+        actionContext = fmt.Sprintf("player: %s", userInput)
+    }
 
-        span.SetAttributes(
-            attribute.Int("result.success_count", len(executionResult.Successes)),
-            attribute.Int("result.failure_count", len(executionResult.Failures)),
-        )
-
-        return MutationsGeneratedMsg{
-            Mutations:     allMessages,
-            Successes:     executionResult.Successes,
-            Failures:      executionResult.Failures,
-            SensoryEvents: nil,
-            WorldEventLines: worldEventLines,
-            NewWorld:      newWorld,
-            UserInput:     userInput,
-            Debug:         d.debugLogger.IsEnabled(),
-            ActingNPCID:   npcID,
-            ActionContext: actionContext,
+    span.SetAttributes(
+        attribute.Int("result.success_count", len(executionResult.Successes)),
+        attribute.Int("result.failure_count", len(executionResult.Failures)),
+    )
+
+    return MutationsGeneratedMsg{
+        Mutations:     allMessages,
+        Successes:     executionResult.Successes,
+        Failures:      executionResult.Failures,
+        SensoryEvents: nil,
+        WorldEvents:   worldEvents,
+        NewWorld:      newWorld,
+        UserInput:     userInput,
+        Debug:         d.debugLogger.IsEnabled(),
+        ActingNPCID:   npcID,
+        ActionContext: actionContext,
+        RefreshFailed: refreshFailed,
+        Invariants:    invariants,
+        StrictErrors:  strictErrors,
+        Addressed:     addressedNPCs(executionResult),
+    }
+}
+
+// ExpectedPlayerLocation is where the player should be after a turn's mutations: the
+// destination of the last successful move, or the starting location if they didn't move.
+func ExpectedPlayerLocation(start string, successes []string) string {
+    expected := start
+    for _, success := range successes {
+        if dest, ok := strings.CutPrefix(success, tools.MovedToPrefix); ok {
+            expected = dest
         }
     }
+    return expected
 }
 
 // executeWithRetry handles mutation execution with automatic retry on failures.
 // If the first attempt fails, it asks the LLM to generate an alternative approach.
 func (d *Director) executeWithRetry(ctx context.Context, userInput string, world game.WorldState, gameHistory []string, actingNPCID string, mutations []MutationRequest) (*ExecutionResult, error) {
 	pendingMutations := mutations
+	var allExecuted []MutationRequest
 	var allSuccesses []string
 	var allFailures []string
 	
 	for attempt := 0; attempt < 2 && len(pendingMutations) > 0; attempt++ {
-		successes, failures := ExecuteMutations(ctx, pendingMutations, d.mcpClient, d.debugLogger, world, actingNPCID)
+		// Mutations the local world already rules out fail here, without a server call;
+		// the rest still fail through the server as before. A retry is checked against
+		// the world as the first attempt's successes left it.
+		planned := world.Clone()
+		checkPlan(&planned, allExecuted, actingNPCID)
+		valid, violations := checkPlan(&planned, pendingMutations, actingNPCID)
+		if len(violations) > 0 {
+			d.debugLogger.Printf("Plan check rejected %d of %d mutations", len(violations), len(pendingMutations))
+		}
+		executed, successes, failures := executeMutations(ctx, valid, d.mcpClient, d.debugLogger, world, actingNPCID)
+		failures = append(violations, failures...)
+		allExecuted = append(allExecuted, executed...)
 		allSuccesses = append(allSuccesses, successes...)
 		
 		if len(failures) == 0 {
@@ -295,29 +428,40 @@ func (d *Director) executeWithRetry(ctx context.Context, userInput string, world
 		}
 	}
 	
-	return &ExecutionResult{Successes: allSuccesses, Failures: allFailures}, nil
+	return &ExecutionResult{Successes: allSuccesses, Failures: allFailures, Executed: allExecuted}, nil
 }
 
 
-// getActionLabel returns the appropriate action label for logging and prompts.
-func getActionLabel(actingNPCID string) string {
-    if actingNPCID != "" {
-        return fmt.Sprintf("NPC %s ACTION", strings.ToUpper(actingNPCID))
-    }
-    return "Player action"
-}
-
-// summarizeTurnEvents asks the LLM to produce short, human-readable event lines
-// that describe what happened this turn, including successes, non-mutating actions,
-// and failures. No invented events.
-func (d *Director) summarizeTurnEvents(ctx context.Context, userInput, npcID string, oldWorld, newWorld game.WorldState, successes, failures []string) []string {
+// summarizeTurnEvents asks the LLM to produce short, human-readable events that describe
+// what happened this turn, including successes, non-mutating actions, and failures. No
+// invented events. Every event is the actor's and happens where the actor ends the turn;
+// the actor's attempt always comes first. When the summary fails the events come from
+// the executed mutations and failures instead.
+// The only error it returns is strict mode refusing one of its fallbacks, in which case
+// there are no events.
+func (d *Director) summarizeTurnEvents(ctx context.Context, userInput, npcID string, oldWorld, newWorld game.WorldState, result *ExecutionResult) ([]events.WorldEvent, error) {
+    successes, failures := result.Successes, result.Failures
     tracer := otel.Tracer("events")
     ctx, span := tracer.Start(ctx, "events.summarize")
     defer span.End()
 
-    actor := "PLAYER"
+    actor := "player"
+    location := newWorld.Location
     if npcID != "" {
-        actor = strings.ToUpper(npcID)
+        actor = game.RefID(npcID)
+        if n, ok := newWorld.NPCs[npcID]; ok && n.Location != "" {
+            location = n.Location
+        }
+    }
+    // The actor's attempt is always an event, for perception routing
+    var attempt *events.WorldEvent
+    if strings.TrimSpace(userInput) != "" {
+        attemptType := events.EventAction
+        if strings.ContainsAny(userInput, "\"“”") {
+            attemptType = events.EventSpeech
+        }
+        e := events.New(attemptType, actor, location, userInput)
+        attempt = &e
     }
 
     worldDeltaHint := ""
@@ -336,6 +480,10 @@ func (d *Director) summarizeTurnEvents(ctx context.Context, userInput, npcID str
     if worldDeltaHint != "" {
         fmt.Fprintf(sb, "WORLD HINT: %s\n", worldDeltaHint)
     }
+    // The ambience as it was, so a turn that changes it can still report the change
+    if ambience := strings.TrimSpace(oldWorld.Locations[location].Ambience); ambience != "" {
+        fmt.Fprintf(sb, "AMBIENCE (ongoing background, never an event): %s\n", ambience)
+    }
 
     schema := map[string]interface{}{
         "type": "object",
@@ -343,9 +491,20 @@ func (d *Director) summarizeTurnEvents(ctx context.Context, userInput, npcID str
             "events": map[string]interface{}{
                 "type": "array",
                 "items": map[string]interface{}{
-                    "type": "string",
+                    "type": "object",
+                    "properties": map[string]interface{}{
+                        "type": map[string]interface{}{
+                            "type": "string",
+                            "enum": summaryTypeNames(),
+                        },
+                        "content": map[string]interface{}{
+                            "type": "string",
+                        },
+                    },
+                    "required":             []string{"type", "content"},
+                    "additionalProperties": false,
                 },
-                "description": "Array of short, human-readable lines describing what actually happened this turn",
+                "description": "Short, human-readable events describing what actually happened this turn",
             },
         },
         "required": []string{"events"},
@@ -354,8 +513,10 @@ func (d *Director) summarizeTurnEvents(ctx context.Context, userInput, npcID str
 
     req := llm.JSONSchemaCompletionRequest{
         SystemPrompt:    `You summarize the outcome of a single game turn.
-Output the events as an array of short, human-readable lines describing what actually happened this turn.
-Use present tense. Do not invent events. It's OK if some lines describe attempts that didn't change state (like examining).`,
+Output the events as an array of short, human-readable lines describing what actually happened this turn, each with its type:
+movement (someone goes somewhere), item_transfer (an item changes hands or place), inventory (the player picks up or drops something), speak (someone says something aloud), sound (a noise others could hear), state_change (something in the world changes, like a door unlocking), action (anything else, including attempts that didn't change state, like examining).
+Use present tense. Do not invent events. Contest outcomes are recorded separately; describe what the winner does, not the contest itself.
+The AMBIENCE, if given, is the room's ongoing background; it is already known, so never report it or its sounds as an event.`,
         UserPrompt:      sb.String(),
         MaxTokens:       4000,
         Model:           "gpt-5-mini",
@@ -370,26 +531,11 @@ Use present tense. Do not invent events. It's OK if some lines describe attempts
         if d.debugLogger != nil {
             d.debugLogger.Errorf("event summarization failed: %v", err)
         }
-        // Fallback: derive lines from successes/failures/user input conservatively
-        lines := []string{}
-        if userInput != "" {
-            if npcID != "" {
-                npcLoc := newWorld.Location
-                if n, ok := newWorld.NPCs[npcID]; ok && n.Location != "" {
-                    npcLoc = n.Location
-                }
-                lines = append(lines, fmt.Sprintf("%s@%s: %s", actor, npcLoc, userInput))
-            } else {
-                lines = append(lines, fmt.Sprintf("Player@%s: %s", newWorld.Location, userInput))
-            }
-        }
-        for _, s := range successes {
-            lines = append(lines, s)
-        }
-        for _, f := range failures {
-            lines = append(lines, f)
+        // Fallback: derive events from executed mutations/failures conservatively
+        if serr := game.StrictFallback(ctx, "event summarizer", err); serr != nil {
+            return nil, serr
         }
-        return lines
+        return withAttempt(attempt, mutationEvents(actor, location, result)), nil
     }
     if d.debugLogger != nil && d.debugLogger.IsEnabled() {
         // Log raw content for debugging (truncate to avoid huge logs)
@@ -401,48 +547,122 @@ Use present tense. Do not invent events. It's OK if some lines describe attempts
     }
 
     var response struct {
-        Events []string `json:"events"`
+        Events []struct {
+            Type    events.WorldEventType `json:"type"`
+            Content string                `json:"content"`
+        } `json:"events"`
     }
-    var arr []string
+    var summarized []events.WorldEvent
+    var parseErr error
     if jerr := json.Unmarshal([]byte(content), &response); jerr != nil {
         if d.debugLogger != nil {
             d.debugLogger.Errorf("event summarization JSON parse failed: %v", jerr)
         }
+        parseErr = jerr
     } else {
-        arr = response.Events
+        for _, e := range response.Events {
+            if strings.TrimSpace(e.Content) == "" {
+                continue
+            }
+            if game.IsAmbience(oldWorld, location, e.Content) {
+                continue
+            }
+            eventType := e.Type
+            if !isSummaryType(eventType) {
+                eventType = events.EventAction
+            }
+            summarized = append(summarized, events.New(eventType, actor, location, strings.TrimSpace(e.Content)))
+        }
     }
     // If still empty, fallback conservatively (request succeeded but format unexpected)
-    if len(arr) == 0 {
-        lines := []string{}
-        // attempt line with tags added below after we compute it
-        for _, s := range successes { lines = append(lines, s) }
-        for _, f := range failures { lines = append(lines, f) }
-        arr = lines
-    }
-    // Always include a canonical attempt line with location tag for perception routing
-    attempt := ""
-    if npcID != "" {
-        npcLoc := newWorld.Location
-        if n, ok := newWorld.NPCs[npcID]; ok && n.Location != "" {
-            npcLoc = n.Location
+    if len(summarized) == 0 {
+        if parseErr == nil {
+            parseErr = errors.New("no events in summary")
+        }
+        if serr := game.StrictFallback(ctx, "event summarizer", parseErr); serr != nil {
+            return nil, serr
         }
-        attempt = fmt.Sprintf("%s@%s: %s", actor, npcLoc, userInput)
+        summarized = mutationEvents(actor, location, result)
     } else {
-        attempt = fmt.Sprintf("Player@%s: %s", newWorld.Location, userInput)
-    }
-    // Prepend attempt if not already present
-    hasAttempt := false
-    for _, line := range arr {
-        if strings.TrimSpace(strings.ToLower(line)) == strings.TrimSpace(strings.ToLower(attempt)) {
-            hasAttempt = true
-            break
+        summarized = append(contestEvents(actor, location, result), summarized...)
+    }
+    evs := withAttempt(attempt, summarized)
+    if d.debugLogger != nil && d.debugLogger.IsEnabled() {
+        d.debugLogger.Printf("[DEBUG] events.final_lines (%d): %v", len(evs), events.Lines(evs))
+    }
+    return evs, nil
+}
+
+// withAttempt puts the actor's attempt in front of the summarized events, unless the
+// summary already repeats it.
+func withAttempt(attempt *events.WorldEvent, summarized []events.WorldEvent) []events.WorldEvent {
+    if attempt == nil {
+        return summarized
+    }
+    for _, e := range summarized {
+        if strings.EqualFold(strings.TrimSpace(e.Content), strings.TrimSpace(attempt.Content)) {
+            return summarized
         }
     }
-    if !hasAttempt {
-        arr = append([]string{attempt}, arr...)
+    return append([]events.WorldEvent{*attempt}, summarized...)
+}
+
+// mutationEvents are the events of a turn whose summary failed: one per executed
+// mutation, typed by its tool and described by its success message, then one per failure.
+func mutationEvents(actor, location string, result *ExecutionResult) []events.WorldEvent {
+    evs := make([]events.WorldEvent, 0, len(result.Successes)+len(result.Failures))
+    for i, success := range result.Successes {
+        eventType := events.EventMutation
+        if i < len(result.Executed) {
+            eventType = events.TypeOfTool(result.Executed[i].Tool)
+        }
+        evs = append(evs, events.New(eventType, actor, location, success))
     }
-    if d.debugLogger != nil && d.debugLogger.IsEnabled() {
-        d.debugLogger.Printf("[DEBUG] events.final_lines (%d): %v", len(arr), arr)
+    for _, failure := range result.Failures {
+        evs = append(evs, events.New(events.EventAction, actor, location, failure))
+    }
+    return evs
+}
+
+// contestEvents are the outcomes of the turn's contests, which are recorded as they were
+// rolled rather than as summarized.
+func contestEvents(actor, location string, result *ExecutionResult) []events.WorldEvent {
+    var evs []events.WorldEvent
+    for i, mutation := range result.Executed {
+        if events.TypeOfTool(mutation.Tool) == events.EventContest && i < len(result.Successes) {
+            evs = append(evs, events.New(events.EventContest, actor, location, result.Successes[i]))
+        }
+    }
+    return evs
+}
+
+// addressedNPCs are the NPCs the player spoke to this turn, in the order they were spoken to.
+func addressedNPCs(result *ExecutionResult) []Address {
+    var addressed []Address
+    for _, mutation := range result.Executed {
+        if mutation.Tool != "speak_to_npc" {
+            continue
+        }
+        npcID, _ := mutation.Args["npc_id"].(string)
+        words, _ := mutation.Args["words"].(string)
+        addressed = append(addressed, Address{NPCID: npcID, Words: words})
+    }
+    return addressed
+}
+
+func summaryTypeNames() []string {
+    names := make([]string, 0, len(events.SummaryTypes))
+    for _, t := range events.SummaryTypes {
+        names = append(names, string(t))
+    }
+    return names
+}
+
+func isSummaryType(eventType events.WorldEventType) bool {
+    for _, t := range events.SummaryTypes {
+        if t == eventType {
+            return true
+        }
     }
-    return arr
+    return false
 }
@@ -0,0 +1,35 @@
+package director
+
+import (
+	"regexp"
+	"strings"
+
+	"textadventure/internal/game"
+)
+
+// possessiveExamine matches "look at my key", "examine the key in my pocket", etc.
+var possessiveExamine = regexp.MustCompile(`^(?:examine|inspect|study|check|look at|look closely at)\s+(?:(my)\s+(.+?)|(?:the\s+)?(.+?)\s+in\s+my\s+(?:pocket|pockets|bag|hand|hands|inventory))[.!?]?$`)
+
+// parseFastPath recognizes player inputs that map to a single obvious mutation so the
+// director can skip the LLM call. It only returns a plan when the match is unambiguous.
+func parseFastPath(userInput string, world game.WorldState, actingNPCID string) (*ActionPlan, bool) {
+	if actingNPCID != "" {
+		return nil, false
+	}
+	input := strings.ToLower(strings.TrimSpace(userInput))
+	match := possessiveExamine.FindStringSubmatch(input)
+	if match == nil {
+		return nil, false
+	}
+	ref := match[2]
+	if ref == "" {
+		ref = match[3]
+	}
+	itemID, carried := game.FindCarriedItem(world, ref)
+	if !carried {
+		return nil, false
+	}
+	return &ActionPlan{Mutations: []MutationRequest{
+		{Tool: "examine_inventory_item", Args: map[string]interface{}{"item": itemID}},
+	}}, true
+}
@@ -0,0 +1,124 @@
+package director
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/llm"
+	"textadventure/internal/mcp"
+)
+
+// carryingWorld has the player carrying a brass key and an old lantern, with a music
+// box left in the foyer.
+func carryingWorld() game.WorldState {
+	world := game.NewDefaultWorldState()
+	world.Inventory = []string{"brass_key", "lantern_01"}
+	world.Items = map[string]game.ItemInfo{
+		"brass_key":  {Name: "brass key", Location: "player", Facts: []string{"its bow is shaped like a heron"}},
+		"lantern_01": {Name: "Old Lantern", Location: "player", Facts: []string{"the glass is cracked", "it smells of lamp oil"}},
+		"music_box":  {Name: "music box", Location: "foyer"},
+	}
+	return world
+}
+
+func TestParseFastPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // the examined item, or "" for no fast path
+	}{
+		{"look at my key", "brass_key"},
+		{"examine my brass key", "brass_key"},
+		{"Look At My Brass Key.", "brass_key"},
+		{"  inspect my lantern!  ", "lantern_01"},
+		{"look closely at my old lantern", "lantern_01"},
+		{"check my brass_key", "brass_key"},
+		{"study the key in my pocket", "brass_key"},
+		{"examine key in my bag", "brass_key"},
+		{"look at the lantern in my hands?", "lantern_01"},
+		// Not carried, not possessive, or not an examination: the director decides
+		{"look at my music box", ""},
+		{"look at my sword", ""},
+		{"look at the key", ""},
+		{"examine the music box", ""},
+		{"take my key", ""},
+		{"look at my key and open the door", ""},
+		{"look at my", ""},
+	}
+	world := carryingWorld()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			plan, ok := parseFastPath(tt.input, world, "")
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("matched: %+v", plan.Mutations)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("no fast path")
+			}
+			if len(plan.Mutations) != 1 || plan.Mutations[0].Tool != "examine_inventory_item" || plan.Mutations[0].Args["item"] != tt.want {
+				t.Errorf("mutations = %+v, want examine_inventory_item %s", plan.Mutations, tt.want)
+			}
+		})
+	}
+}
+
+func TestFastPathIsPlayerOnly(t *testing.T) {
+	if plan, ok := parseFastPath("look at my key", carryingWorld(), "elena"); ok {
+		t.Errorf("an NPC took the fast path: %+v", plan.Mutations)
+	}
+}
+
+// A fast-path match skips the model; anything else asks it.
+func TestInterpretIntentFastPath(t *testing.T) {
+	service := llm.NewMockService().OnOperation("director", `{"mutations": []}`)
+	d := NewDirector(service, mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(carryingWorld())), debug.NewLogger(debug.Off, filepath.Join(t.TempDir(), "debug.log")))
+	ctx := llm.WithOperationType(context.Background(), "director")
+
+	plan, err := d.InterpretIntent(ctx, "look at my lantern", carryingWorld(), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Mutations) != 1 || plan.Mutations[0].Args["item"] != "lantern_01" {
+		t.Errorf("plan = %+v", plan.Mutations)
+	}
+	if calls := service.Calls(); len(calls) != 0 {
+		t.Errorf("the fast path called the model %d times", len(calls))
+	}
+
+	if _, err := d.InterpretIntent(ctx, "look at my music box", carryingWorld(), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if calls := service.Calls(); len(calls) != 1 {
+		t.Errorf("model calls = %d, want the director asked once", len(calls))
+	}
+}
+
+// The director sees what the world has established about the items the player carries,
+// so it examines the same object rather than inventing one.
+func TestDirectorPromptIncludesItemCanon(t *testing.T) {
+	service := llm.NewMockService().OnOperation("director", `{"mutations": []}`)
+	d := NewDirector(service, mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(carryingWorld())), debug.NewLogger(debug.Off, filepath.Join(t.TempDir(), "debug.log")))
+	if _, err := d.InterpretIntent(llm.WithOperationType(context.Background(), "director"), "hold the lantern up to the light", carryingWorld(), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	calls := service.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("model calls = %d", len(calls))
+	}
+	for _, want := range []string{
+		"examine_inventory_item",
+		"Player Inventory:",
+		"its bow is shaped like a heron",
+		"the glass is cracked; it smells of lamp oil",
+	} {
+		if !strings.Contains(calls[0].SystemPrompt, want) {
+			t.Errorf("prompt is missing %q", want)
+		}
+	}
+}
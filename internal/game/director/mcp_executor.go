@@ -2,6 +2,7 @@ package director
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.opentelemetry.io/otel"
@@ -19,7 +20,14 @@ type MutationRequest struct {
 	Args map[string]interface{} `json:"args"`
 }
 
-func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClient *mcp.WorldStateClient, debugLogger *debug.Logger, world game.WorldState, actingNPCID string) ([]string, []string) {
+func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClient mcp.WorldClient, debugLogger *debug.Logger, world game.WorldState, actingNPCID string) ([]string, []string) {
+	_, successes, failures := executeMutations(ctx, mutations, mcpClient, debugLogger, world, actingNPCID)
+	return successes, failures
+}
+
+// executeMutations runs mutations like ExecuteMutations and also returns the ones that
+// succeeded, in the order of their success messages.
+func executeMutations(ctx context.Context, mutations []MutationRequest, mcpClient mcp.WorldClient, debugLogger *debug.Logger, world game.WorldState, actingNPCID string) ([]MutationRequest, []string, []string) {
 	tracer := otel.Tracer("mcp-executor")
 	
 	attrs := []attribute.KeyValue{
@@ -40,6 +48,7 @@ func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClien
 	)
 	defer span.End()
 	
+	var executed []MutationRequest
 	var successes []string
 	var failures []string
 	
@@ -48,6 +57,7 @@ func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClien
 			trace.WithAttributes(
 				attribute.String("tool_name", mutation.Tool),
 				attribute.Int("mutation_index", i),
+				attribute.Int64("mcp.call_timeout_ms", mcpClient.CallTimeout().Milliseconds()),
 			),
 		)
 		
@@ -60,6 +70,14 @@ func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClien
 			continue
 		}
 		
+		if scope := tool.Actors(); !scope.Allows(actingNPCID) {
+			failure := fmt.Sprintf("%s cannot use %s: the tool is %s", actorName(actingNPCID), mutation.Tool, scope)
+			failures = append(failures, failure)
+			mutSpan.SetAttributes(attribute.String("error_type", "actor_not_allowed"))
+			mutSpan.End()
+			continue
+		}
+		
 		if err := tool.Validate(mutation.Args); err != nil {
 			failure := fmt.Sprintf("Invalid args for %s: %v", mutation.Tool, err)
 			failures = append(failures, failure)
@@ -69,15 +87,50 @@ func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClien
 			continue
 		}
 		
-		if err := tool.Execute(ctx, mutation.Args, mcpClient, world, actingNPCID); err != nil {
+		if err := resolveItemArgs(tool, mutation.Args, world); err != nil {
+			failure := fmt.Sprintf("Invalid args for %s: %v", mutation.Tool, err)
+			failures = append(failures, failure)
+			mutSpan.SetAttributes(attribute.String("error_type", "unknown_item"))
+			mutSpan.RecordError(err)
+			mutSpan.End()
+			continue
+		}
+		
+		if err := resolveNPCArgs(tool, mutation.Args, world); err != nil {
+			failure := fmt.Sprintf("Invalid args for %s: %v", mutation.Tool, err)
+			failures = append(failures, failure)
+			mutSpan.SetAttributes(attribute.String("error_type", "unknown_npc"))
+			mutSpan.RecordError(err)
+			mutSpan.End()
+			continue
+		}
+		
+		if err := tool.Execute(ctx, mutation.Args, mcpClient, world, actingNPCID); errors.Is(err, mcp.ErrWorldTimeout) {
+			failure := fmt.Sprintf("World server timed out running %s", mutation.Tool)
+			failures = append(failures, failure)
+			mutSpan.SetAttributes(attribute.String("error_type", "timeout"))
+			mutSpan.RecordError(err)
+		} else if err != nil {
 			failure := fmt.Sprintf("Failed to execute %s: %v", mutation.Tool, err)
 			failures = append(failures, failure)
 			mutSpan.SetAttributes(attribute.String("error_type", "execution_failed"))
 			mutSpan.RecordError(err)
 		} else {
 			success := tool.SuccessMessage(mutation.Args, actingNPCID)
+			if worldAware, ok := tool.(WorldAwareTool); ok {
+				success = worldAware.SuccessMessageWithWorld(mutation.Args, world, actingNPCID)
+			}
+			executed = append(executed, mutation)
 			successes = append(successes, success)
 			mutSpan.SetAttributes(attribute.String("result", "success"))
+			if next := followUpsOf(tool, mutation.Args); len(next) > 0 {
+				mutSpan.End()
+				nextExecuted, nextSuccesses, nextFailures := executeMutations(ctx, next, mcpClient, debugLogger, world, actingNPCID)
+				executed = append(executed, nextExecuted...)
+				successes = append(successes, nextSuccesses...)
+				failures = append(failures, nextFailures...)
+				continue
+			}
 		}
 		mutSpan.End()
 	}
@@ -95,5 +148,25 @@ func ExecuteMutations(ctx context.Context, mutations []MutationRequest, mcpClien
 		attribute.StringSlice("successes", successes),
 	)
 	
-	return successes, failures
+	return executed, successes, failures
+}
+
+func actorName(actingNPCID string) string {
+	if actingNPCID == "" {
+		return "player"
+	}
+	return actingNPCID
+}
+
+// followUpsOf returns the mutations an executed FollowUpTool asks for, if any.
+func followUpsOf(tool MCPTool, args map[string]interface{}) []MutationRequest {
+	followUp, ok := tool.(FollowUpTool)
+	if !ok {
+		return nil
+	}
+	var requests []MutationRequest
+	for _, mutation := range followUp.FollowUps(args) {
+		requests = append(requests, MutationRequest{Tool: mutation.Tool, Args: mutation.Args})
+	}
+	return requests
 }
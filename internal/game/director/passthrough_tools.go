@@ -0,0 +1,52 @@
+package director
+
+import (
+	"fmt"
+	"sort"
+
+	"textadventure/internal/game/director/tools"
+	"textadventure/internal/mcp"
+)
+
+// RegisterPassthroughTools wraps the scenario's allow-listed server tools that the
+// registry doesn't implement, so the director can plan with them like core tools. It
+// uses the cached tool catalog, so call it after LoadToolCatalog and before any turn
+// runs. It returns the names registered and a problem for each allow-listed tool that
+// couldn't be.
+func RegisterPassthroughTools(allowed map[string]mcp.PassthroughTool) ([]string, []error) {
+	toolCatalog.mu.Lock()
+	specs := toolCatalog.specs
+	toolCatalog.mu.Unlock()
+
+	byName := make(map[string]mcp.ToolSpec, len(specs))
+	for _, spec := range specs {
+		byName[spec.Name] = spec
+	}
+	names := make([]string, 0, len(allowed))
+	for name := range allowed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var registered []string
+	var problems []error
+	for _, name := range names {
+		if _, exists := toolRegistry[name]; exists {
+			continue
+		}
+		spec, ok := byName[name]
+		if !ok {
+			problems = append(problems, fmt.Errorf("passthrough tool %s is not on the world-state server", name))
+			continue
+		}
+		config := allowed[name]
+		actors, err := tools.ParseActorScope(config.Actors)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("passthrough tool %s: %w", name, err))
+			continue
+		}
+		RegisterTool(tools.NewPassthroughTool(spec, config.Usage, actors))
+		registered = append(registered, name)
+	}
+	return registered, problems
+}
@@ -0,0 +1,68 @@
+package director
+
+import (
+	"fmt"
+	"maps"
+
+	"textadventure/internal/game"
+)
+
+// ValidatePlan checks a plan's mutations against the local world before any of them
+// reach the server, returning why each one that would fail is rejected: an unknown tool
+// or actor, bad args, no exit, a locked door, a missing item or NPC. The mutations are
+// checked in order, each against the world as the earlier ones leave it, so "go north
+// and take the lamp" checks the lamp in the room to the north. actingNPCID is the NPC
+// whose plan it is; a plan without one is the player's.
+func ValidatePlan(world game.WorldState, mutations []MutationRequest, actingNPCID ...string) []string {
+	actor := ""
+	if len(actingNPCID) > 0 {
+		actor = actingNPCID[0]
+	}
+	planned := world.Clone()
+	_, violations := checkPlan(&planned, mutations, actor)
+	return violations
+}
+
+// checkPlan splits a plan into the mutations that pass ValidatePlan, in order, and the
+// violations of the rest, applying the passing ones to world. A passing mutation can
+// still fail at the server.
+func checkPlan(world *game.WorldState, mutations []MutationRequest, actingNPCID string) ([]MutationRequest, []string) {
+	var valid []MutationRequest
+	var violations []string
+	for _, mutation := range mutations {
+		if err := checkMutation(world, mutation, actingNPCID); err != nil {
+			violations = append(violations, fmt.Sprintf("Rejected %s: %v", mutation.Tool, err))
+			continue
+		}
+		valid = append(valid, mutation)
+	}
+	return valid, violations
+}
+
+// checkMutation runs the executor's own checks on a copy of the mutation's args, then
+// the tool's preconditions, applying the mutation to world when they hold. Tools
+// without preconditions are left to the server.
+func checkMutation(world *game.WorldState, mutation MutationRequest, actingNPCID string) error {
+	tool, exists := GetTool(mutation.Tool)
+	if !exists {
+		return fmt.Errorf("unknown tool")
+	}
+	if scope := tool.Actors(); !scope.Allows(actingNPCID) {
+		return fmt.Errorf("%s cannot use it: the tool is %s", actorName(actingNPCID), scope)
+	}
+	if err := tool.Validate(mutation.Args); err != nil {
+		return err
+	}
+	args := maps.Clone(mutation.Args)
+	if err := resolveItemArgs(tool, args, *world); err != nil {
+		return err
+	}
+	if err := resolveNPCArgs(tool, args, *world); err != nil {
+		return err
+	}
+	planTool, ok := tool.(PlanCheckTool)
+	if !ok {
+		return nil
+	}
+	return planTool.CheckPlan(args, world, actingNPCID)
+}
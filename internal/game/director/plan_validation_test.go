@@ -0,0 +1,176 @@
+package director
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+// planWorld has the player in the foyer with a lamp. The study to the north holds a
+// brass key, a chest with a coin in it, and elena carrying a letter; the library door
+// to the east is locked.
+func planWorld() game.WorldState {
+	return game.WorldState{
+		Location:  "foyer",
+		Inventory: []string{"lamp"},
+		Locations: map[string]game.LocationInfo{
+			"foyer": {Name: "foyer", Exits: map[string]string{"north": "study", "east": "library"},
+				Doors: map[string]game.DoorInfo{"east": {Locked: true, Description: "oak door"}}},
+			"study":   {Name: "study", Exits: map[string]string{"south": "foyer"}},
+			"library": {Name: "library", Exits: map[string]string{"west": "foyer"}},
+		},
+		NPCs: map[string]game.NPCInfo{
+			"elena": {Location: "study", Inventory: []string{"letter"}},
+		},
+		Items: map[string]game.ItemInfo{
+			"lamp":      {Name: "lamp", Location: "player"},
+			"brass_key": {Name: "brass key", Location: "study"},
+			"letter":    {Name: "letter", Location: "elena"},
+			"chest":     {Name: "chest", Location: "study", IsContainer: true, Contains: []string{"coin"}},
+			"coin":      {Name: "coin", Location: "chest"},
+		},
+	}
+}
+
+func mutation(tool string, keyValues ...string) MutationRequest {
+	args := map[string]interface{}{}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		args[keyValues[i]] = keyValues[i+1]
+	}
+	return MutationRequest{Tool: tool, Args: args}
+}
+
+func TestValidatePlan(t *testing.T) {
+	north := mutation("move_player", "location", "study")
+	south := mutation("move_player", "location", "foyer")
+	east := mutation("move_player", "location", "library")
+	takeKey := mutation("add_to_inventory", "item", "brass key")
+	unlock := mutation("unlock_door", "location", "foyer", "direction", "east", "key_item", "brass_key")
+
+	tests := []struct {
+		name  string
+		actor string
+		plan  []MutationRequest
+		want  []string // violations, in order
+	}{
+		{"empty plan", "", nil, nil},
+		{"move then take from the new room", "", []MutationRequest{north, takeKey}, nil},
+		{"take before moving", "", []MutationRequest{takeKey, north}, []string{
+			"Rejected add_to_inventory: item 'brass_key' is not available in foyer"}},
+		{"through a locked door", "", []MutationRequest{east}, []string{
+			"Rejected move_player: the door to the east in foyer is locked"}},
+		{"unlock without the key", "", []MutationRequest{unlock, east}, []string{
+			"Rejected unlock_door: player is not carrying brass_key",
+			"Rejected move_player: the door to the east in foyer is locked"}},
+		{"fetch the key, unlock and go through", "", []MutationRequest{north, takeKey, south, unlock, east}, nil},
+		{"unlock twice", "", []MutationRequest{north, takeKey, south, unlock, unlock}, []string{
+			"Rejected unlock_door: the door to the east in foyer is not locked"}},
+		{"no exit", "", []MutationRequest{north, mutation("move_player", "location", "library")}, []string{
+			"Rejected move_player: cannot move directly from study to library"}},
+		{"unknown location", "", []MutationRequest{mutation("move_player", "location", "attic")}, []string{
+			"Rejected move_player: location attic does not exist"}},
+		{"unknown tool", "", []MutationRequest{mutation("teleport", "location", "library")}, []string{
+			"Rejected teleport: unknown tool"}},
+		{"bad args", "", []MutationRequest{mutation("move_player")}, []string{
+			"Rejected move_player: move_player requires 'location' parameter"}},
+		{"unknown item", "", []MutationRequest{mutation("add_to_inventory", "item", "sword")}, []string{
+			"Rejected add_to_inventory: item: "}},
+		{"NPC-only tool", "", []MutationRequest{mutation("npc_take_item", "item", "lamp")}, []string{
+			"Rejected npc_take_item: player cannot use it: the tool is npc-only"}},
+		{"player-only tool", "elena", []MutationRequest{mutation("add_to_inventory", "item", "brass_key")}, []string{
+			"Rejected add_to_inventory: elena cannot use it: the tool is player-only"}},
+		{"drop twice", "", []MutationRequest{mutation("remove_from_inventory", "item", "lamp"), mutation("remove_from_inventory", "item", "lamp")}, []string{
+			"Rejected remove_from_inventory: item 'lamp' is not in inventory"}},
+		{"drop then pick up again", "", []MutationRequest{mutation("remove_from_inventory", "item", "lamp"), mutation("add_to_inventory", "item", "lamp")}, nil},
+		{"give to an NPC elsewhere", "", []MutationRequest{mutation("give_item_to_npc", "item", "lamp", "npc_id", "elena")}, []string{
+			"Rejected give_item_to_npc: "}},
+		{"go to an NPC and give", "", []MutationRequest{north, mutation("give_item_to_npc", "item", "lamp", "npc_id", "elena"), mutation("remove_from_inventory", "item", "lamp")}, []string{
+			"Rejected remove_from_inventory: item 'lamp' is not in inventory"}},
+		{"take from a container", "", []MutationRequest{north, mutation("take_item_from_container", "item", "coin", "container", "chest", "to_location", "player"),
+			mutation("take_item_from_container", "item", "coin", "container", "chest", "to_location", "player")}, []string{
+			"Rejected take_item_from_container: coin is not in chest"}},
+		{"container out of reach", "", []MutationRequest{mutation("put_item_in_container", "item", "lamp", "container", "chest")}, []string{
+			"Rejected put_item_in_container: chest is out of reach"}},
+		{"put away then take back", "", []MutationRequest{north, mutation("put_item_in_container", "item", "lamp", "container", "chest"),
+			mutation("take_item_from_container", "item", "lamp", "container", "chest", "to_location", "player")}, nil},
+		{"NPC gives to a player elsewhere", "elena", []MutationRequest{mutation("npc_give_item_to_player", "item", "letter")}, []string{
+			"Rejected npc_give_item_to_player: the player is not here"}},
+		{"NPC walks over and gives", "elena", []MutationRequest{mutation("move_npc", "npc_id", "elena", "location", "foyer"), mutation("npc_give_item_to_player", "item", "letter"),
+			mutation("npc_drop_item", "item", "letter")}, []string{
+			"Rejected npc_drop_item: elena is not carrying letter"}},
+		{"NPC takes what is in its room", "elena", []MutationRequest{mutation("npc_take_item", "item", "brass_key"), mutation("npc_drop_item", "item", "brass_key")}, nil},
+		{"NPC takes what is elsewhere", "elena", []MutationRequest{mutation("npc_take_item", "item", "lamp")}, []string{
+			"Rejected npc_take_item: lamp is not in the study with elena"}},
+		{"unknown NPC moves", "", []MutationRequest{mutation("move_npc", "npc_id", "marcus", "location", "foyer")}, []string{
+			"Rejected move_npc: npc_id: "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := planWorld()
+			got := ValidatePlan(world, tt.plan, tt.actor)
+			if len(got) != len(tt.want) {
+				t.Fatalf("violations = %q, want %q", got, tt.want)
+			}
+			for i := range tt.want {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("violation %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+			if !reflect.DeepEqual(world, planWorld()) {
+				t.Error("ValidatePlan changed the world it was given")
+			}
+		})
+	}
+}
+
+// checkPlan applies each passing mutation to the plan's world, keeping an item's holder
+// and its Location in step, and leaves the world alone for rejected ones.
+func TestCheckPlanKeepsTheWorldConsistent(t *testing.T) {
+	world := planWorld()
+	plan := []MutationRequest{
+		mutation("move_player", "location", "study"),
+		mutation("add_to_inventory", "item", "lamp"), // already carried: rejected
+		mutation("take_item_from_container", "item", "coin", "container", "chest", "to_location", "player"),
+		mutation("give_item_to_npc", "item", "lamp", "npc_id", "Elena"),
+		mutation("transfer_item", "item", "brass_key", "from_location", "study", "to_location", "elena"),
+		mutation("move_player", "location", "attic"), // rejected
+	}
+	valid, violations := checkPlan(&world, plan, "")
+	if len(violations) != 2 {
+		t.Fatalf("violations = %q, want 2", violations)
+	}
+	var tools []string
+	for _, m := range valid {
+		tools = append(tools, m.Tool)
+	}
+	if want := []string{"move_player", "take_item_from_container", "give_item_to_npc", "transfer_item"}; !slices.Equal(tools, want) {
+		t.Errorf("valid = %q, want %q", tools, want)
+	}
+	if plan[3].Args["npc_id"] != "Elena" {
+		t.Error("checkPlan rewrote the plan's own args")
+	}
+
+	if world.Location != "study" {
+		t.Errorf("player at %s", world.Location)
+	}
+	if !slices.Equal(world.Inventory, []string{"coin"}) {
+		t.Errorf("player carries %q", world.Inventory)
+	}
+	if got := world.NPCs["elena"].Inventory; !slices.Equal(got, []string{"letter", "lamp", "brass_key"}) {
+		t.Errorf("elena carries %q", got)
+	}
+	if got := world.Items["chest"].Contains; len(got) != 0 {
+		t.Errorf("chest still holds %q", got)
+	}
+	for item, holder := range map[string]string{"coin": "player", "lamp": "elena", "brass_key": "elena", "letter": "elena"} {
+		if got := world.Items[item].Location; got != holder {
+			t.Errorf("%s at %s, want %s", item, got, holder)
+		}
+	}
+	if violations := game.CheckWorld(world); len(violations) != 0 {
+		t.Errorf("the planned world breaks invariants: %v", violations)
+	}
+}
@@ -0,0 +1,25 @@
+package director
+
+import (
+	"context"
+
+	"textadventure/internal/game"
+)
+
+// PlanPreview is what the director would do with an action, without doing it: the plan
+// it generated and why ValidatePlan would reject any of its mutations.
+type PlanPreview struct {
+	Plan       *ActionPlan
+	Violations []string
+}
+
+// PreviewIntent interprets an action like ExecuteIntent but stops before execution, so
+// nothing reaches the world-state server. It is for checking what a prompt change does
+// to planning.
+func (d *Director) PreviewIntent(ctx context.Context, userInput string, world game.WorldState, gameHistory []string, actingNPCID string) (*PlanPreview, error) {
+	plan, err := d.InterpretIntent(ctx, userInput, world, gameHistory, actingNPCID)
+	if err != nil {
+		return nil, err
+	}
+	return &PlanPreview{Plan: plan, Violations: ValidatePlan(world, plan.Mutations, actingNPCID)}, nil
+}
@@ -1,25 +1,42 @@
 package director
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	
 	"textadventure/internal/game"
 )
 
-func buildDirectorPrompt(toolDescriptions string, world game.WorldState, gameHistory []string, actionLabel string, actingNPCID string) string {
+func buildDirectorPrompt(ctx context.Context, toolDescriptions string, world game.WorldState, gameHistory []string, actionLabel string, actingNPCID string) string {
     var movementGuideline string
     var pickupGuidelines string
-    var exampleDestination string
+    var examplePickup string
+    var exampleMove string
+    var unmetPeople string
 
     if actingNPCID != "" {
+        actingNPCID = game.RefID(actingNPCID)
         movementGuideline = fmt.Sprintf("- Movement: use move_npc with npc_id=\"%s\".", actingNPCID)
-        pickupGuidelines = fmt.Sprintf("- Pick up item: use transfer_item from location → %s.\n- If NPC introduces themselves: use mark_npc_as_met with npc_id=\"%s\".", actingNPCID, actingNPCID)
-        exampleDestination = actingNPCID
+        pickupGuidelines = fmt.Sprintf("- Pick up item: use npc_take_item; the item must be in %[1]s's room.\n- Drop item: use npc_drop_item; %[1]s must be carrying it.\n- If NPC introduces themselves: use mark_npc_as_met with npc_id=\"%[1]s\".", actingNPCID)
+        examplePickup = `{"tool": "npc_take_item", "args": {"item": "key"}}`
+        exampleMove = fmt.Sprintf(`{"tool": "move_npc", "args": {"npc_id": "%s", "location": "kitchen"}}`, actingNPCID)
+        if restricted := restrictedToolNames(actingNPCID); len(restricted) > 0 {
+            movementGuideline += fmt.Sprintf("\n- Only the player can use %s; never emit them for %s. Mutations using them are rejected.", strings.Join(restricted, ", "), game.NPCName(actingNPCID))
+        }
     } else {
         movementGuideline = "- Movement: use move_player."
-        pickupGuidelines = "- Pick up item: use transfer_item from location → player, then add_to_inventory.\n- If meeting someone who gives their name: use mark_npc_as_met with their npc_id."
-        exampleDestination = "player"
+        pickupGuidelines = "- Pick up item: use transfer_item from location → player, then add_to_inventory.\n- Drop item: remove_from_inventory, then transfer_item to current location.\n- If meeting someone who gives their name: use mark_npc_as_met with their npc_id (their alias if unmet)."
+        if unmet := formatUnmetPeople(world); unmet != "" {
+            unmetPeople = "\n<unmet_people>\n" + unmet + "</unmet_people>\n"
+            pickupGuidelines += "\n- People the player hasn't met are known only by description. When the player refers to one (\"the woman in the library\"), use their alias from <unmet_people> wherever an npc_id is expected. If nobody matches, produce no mutations."
+        }
+        if quests := formatQuestContext(world); quests != "" {
+            unmetPeople += "\n<player_purpose>\n" + quests + "</player_purpose>\n"
+            pickupGuidelines += "\n- <player_purpose> explains references like \"finish what I came here to do\": resolve them to the next physical step toward the quest from where the player stands. Quests grant no shortcuts; never move the player to the objective, or hand them what it needs, in one step. If the next step isn't clear, produce no mutations."
+        }
+        examplePickup = `{"tool": "transfer_item", "args": {"item": "key", "from_location": "foyer", "to_location": "player"}}`
+        exampleMove = `{"tool": "move_player", "args": {"location": "kitchen"}}`
     }
 
     return fmt.Sprintf(`You are the Director of a text adventure game. Generate only the world mutations required to fulfill the user's intent.
@@ -31,6 +48,7 @@ func buildDirectorPrompt(toolDescriptions string, world game.WorldState, gameHis
 <context>
 %s
 </context>
+%s
 
 <guidelines>
 - Interpret the %s and produce only necessary mutations using the available tools.
@@ -38,30 +56,79 @@ func buildDirectorPrompt(toolDescriptions string, world game.WorldState, gameHis
 - Be conservative; avoid speculative or unrelated changes.
 %s
 %s
-- Drop item: remove_from_inventory, then transfer_item to current location.
 - Examine/look at environment: usually no mutations needed.
+- Examine something the player already carries ("look at my key", "the key in my pocket"): use examine_inventory_item. Never transfer or pick up an item that is already in the inventory.
 - Examine/look at NPCs or specific items: may need mutations to trigger detailed descriptions or NPC reactions.
 - NPCs may only affect items at their location or move themselves.
+- Player condition: use set_player_condition when the action clearly changes it (falling in water → add soaked; resting by a fire → remove cold). Respect the current condition: an exhausted player cannot run, an injured player cannot climb; produce no mutations for actions their condition rules out.
+- Contested actions, where the actor tries something another actor in the same room would resist or race for ("grab the knife before she does", "block the doorway", "shove past the guard"): emit a single contest, with the actor as actor_a, and put what happens if each side wins in a_wins and b_wins. Never decide the winner yourself. Actions nobody present opposes need no contest.
+- Speaking to an NPC in the same room ("say hello to Elena", "ask the guard about the key"): emit speak_to_npc with the player's words, verbatim where they were quoted. The NPC's reply is handled separately; never write it yourself.
+- NPC goals: use set_npc_goal to add a goal when an NPC takes on something lasting ("Elena agrees to find the key" → add "find the brass key"), and to complete it once the goal is clearly achieved. NPCs may only change their own goals. Don't add goals for passing whims.
+- NPC emotions: use adjust_npc_emotion when an action plainly moves how an NPC present feels toward the actor or the situation (comforting them → trust up, fear down; threatening them → fear up, trust down), with the action as cause. Startling noises are already handled; don't adjust for them.
+- Relationships: when the player helps, threatens or lies to an NPC (and the NPC knows or later finds out), also emit update_relationship for that NPC, positive for help and negative for threats and lies, with what the player did as interaction. Small talk and ordinary requests don't change a relationship.
+- Containers: putting something in a container item ("put the journal in the drawer") is put_item_in_container; taking something out is take_item_from_container, to the actor (player or the NPC's ID). Opening or looking inside one is open_container. Never transfer_item into or out of a container, and never open or use a locked container.
+- Giving: the player handing something they carry to an NPC in the room ("give the key to Elena") is give_item_to_npc; an NPC handing something they carry to the player is npc_give_item_to_player. Never use transfer_item between the player and an NPC.
+- Locked doors: an exit marked (locked) can't be used. Never emit move_player or move_npc through it; if the player carries a key for it and tries it, emit unlock_door first.
 </guidelines>
 
 <example_output>
 {"mutations": [
-  {"tool": "move_player", "args": {"location": "kitchen"}},
-  {"tool": "transfer_item", "args": {"item": "key", "from_location": "foyer", "to_location": "%s"}}
+  %s,
+  %s
 ]}
 </example_output>
-`, toolDescriptions, game.BuildWorldContext(world, gameHistory, actingNPCID), actionLabel, movementGuideline, pickupGuidelines, exampleDestination)
+`, toolDescriptions, game.CachedWorldContext(game.WithFullMap(ctx), world, gameHistory, actingNPCID), unmetPeople, actionLabel, movementGuideline, pickupGuidelines, exampleMove, examplePickup)
 }
 
-func getCoreDirectorTools() string {
-	coreTools := []string{
-		"move_player(location: string) - Move the player to a specific location",
-		"move_npc(npc_id: string, location: string) - Move an NPC to a specific location", 
-		"transfer_item(item: string, from_location: string, to_location: string) - Move an item between locations or entities",
-		"add_to_inventory(item: string) - Add an item from current location to player's inventory",
-		"remove_from_inventory(item: string) - Remove an item from player's inventory to current location",
-		"mark_npc_as_met(npc_id: string) - Mark that the player has met and learned an NPC's name",
-	}
-	
-	return strings.Join(coreTools, "\n")
+// formatUnmetPeople lists unmet NPCs by alias and description, one per line. Their
+// names stay out of the prompt; the executor maps aliases back to NPC IDs.
+func formatUnmetPeople(world game.WorldState) string {
+    var sb strings.Builder
+    for _, unmet := range game.UnmetNPCReferences(world) {
+        fmt.Fprintf(&sb, "- %s: %s (in the %s)\n", unmet.Alias, unmet.Description, unmet.Location)
+    }
+    return sb.String()
+}
+
+const (
+    // maxPromptQuests and maxPromptGoals cap how much of the player's purpose the
+    // director sees; questLineBudget caps each line, in characters.
+    maxPromptQuests = 5
+    maxPromptGoals  = 3
+    questLineBudget = 200
+)
+
+// formatQuestContext lists the player's active quests and most recent journal goals,
+// budgeted so a long quest log can't crowd out the world context.
+func formatQuestContext(world game.WorldState) string {
+    var sb strings.Builder
+    quests := game.ActiveQuests(world)
+    if len(quests) > maxPromptQuests {
+        quests = quests[:maxPromptQuests]
+    }
+    for _, quest := range quests {
+        line := quest.Title
+        if quest.Objective != "" {
+            line += ": " + quest.Objective
+        }
+        fmt.Fprintf(&sb, "- Quest: %s\n", truncateRunes(line, questLineBudget))
+    }
+    goals := world.Goals
+    if len(goals) > maxPromptGoals {
+        goals = goals[len(goals)-maxPromptGoals:]
+    }
+    for _, goal := range goals {
+        if goal = strings.TrimSpace(goal); goal != "" {
+            fmt.Fprintf(&sb, "- Goal: %s\n", truncateRunes(goal, questLineBudget))
+        }
+    }
+    return sb.String()
+}
+
+func truncateRunes(text string, limit int) string {
+    runes := []rune(text)
+    if len(runes) <= limit {
+        return text
+    }
+    return string(runes[:limit]) + "…"
 }
@@ -0,0 +1,57 @@
+package director
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+	"textadventure/internal/mcp"
+)
+
+// FiredEvents is the outcome of running the scheduled events due this turn.
+type FiredEvents struct {
+	Successes   []string
+	Failures    []string
+	WorldEvents []events.WorldEvent
+}
+
+// FireScheduledEvents runs the mutations of due events in firing order, each against
+// the world as the previous events left it. Every fired event contributes its
+// description as a world event, even if some of its mutations fail.
+func FireScheduledEvents(ctx context.Context, due []game.ScheduledEvent, mcpClient mcp.WorldClient, debugLogger *debug.Logger, world game.WorldState) FiredEvents {
+	tracer := otel.Tracer("director")
+	ctx, span := tracer.Start(ctx, "director.fire_scheduled_events")
+	defer span.End()
+	span.SetAttributes(attribute.Int("scheduled.due_count", len(due)))
+
+	var fired FiredEvents
+	for _, event := range due {
+		mutations := make([]MutationRequest, 0, len(event.Mutations))
+		for _, mutation := range event.Mutations {
+			args := make(map[string]interface{}, len(mutation.Args))
+			for key, value := range mutation.Args {
+				args[key] = value
+			}
+			mutations = append(mutations, MutationRequest{Tool: mutation.Tool, Args: args})
+		}
+		successes, failures := ExecuteMutations(ctx, mutations, mcpClient, debugLogger, world, "")
+		fired.Successes = append(fired.Successes, successes...)
+		fired.Failures = append(fired.Failures, failures...)
+		fired.WorldEvents = append(fired.WorldEvents, events.New(events.EventScheduled, "event", event.Location, event.Description))
+
+		if len(successes) > 0 {
+			if mcpWorld, err := mcpClient.GetWorldState(ctx); err == nil {
+				world, _ = RepairRefreshedWorld(span, debugLogger, world, mcp.MCPToGameWorldState(mcpWorld))
+			}
+		}
+	}
+	span.SetAttributes(
+		attribute.Int("result.success_count", len(fired.Successes)),
+		attribute.Int("result.failure_count", len(fired.Failures)),
+	)
+	return fired
+}
@@ -0,0 +1,151 @@
+package director
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/mcp"
+)
+
+// toolCatalog caches the server's ListTools result, fetched once per process.
+var toolCatalog struct {
+	mu     sync.Mutex
+	specs  []mcp.ToolSpec
+	loaded bool
+}
+
+// LoadToolCatalog fetches and caches the server's tools for the director prompt, and logs a warning for registry tools the server doesn't provide.
+func LoadToolCatalog(ctx context.Context, client mcp.WorldClient, debugLogger *debug.Logger) error {
+	specs, err := client.ListToolSpecs(ctx)
+	if err != nil {
+		return err
+	}
+	if missing, unexecutable := compareToolRegistry(specs); len(missing) > 0 || len(unexecutable) > 0 {
+		if len(missing) > 0 {
+			debugLogger.Printf("WARNING: registry tools missing from world-state server: %s", strings.Join(missing, ", "))
+		}
+		if len(unexecutable) > 0 {
+			debugLogger.Printf("Server tools not offered to the director: %s", strings.Join(unexecutable, ", "))
+		}
+	}
+
+	toolCatalog.mu.Lock()
+	defer toolCatalog.mu.Unlock()
+	toolCatalog.specs = specs
+	toolCatalog.loaded = true
+	return nil
+}
+
+// directorToolDescriptions returns the tool block for the acting actor, loading the
+// catalog on first use. If the server can't be asked, it falls back to names and usage
+// from the registry alone.
+func (d *Director) directorToolDescriptions(ctx context.Context, actingNPCID string) string {
+	toolCatalog.mu.Lock()
+	specs, loaded := toolCatalog.specs, toolCatalog.loaded
+	toolCatalog.mu.Unlock()
+	if loaded {
+		return FormatToolDescriptions(specs, actingNPCID)
+	}
+	if d.mcpClient == nil {
+		return FormatToolDescriptions(nil, actingNPCID)
+	}
+	if err := LoadToolCatalog(ctx, d.mcpClient, d.debugLogger); err != nil {
+		d.debugLogger.Printf("Failed to load tool catalog, using registry only: %v", err)
+		return FormatToolDescriptions(nil, actingNPCID)
+	}
+	toolCatalog.mu.Lock()
+	defer toolCatalog.mu.Unlock()
+	return FormatToolDescriptions(toolCatalog.specs, actingNPCID)
+}
+
+// FormatToolDescriptions renders one line per tool the director may use, e.g.
+// "move_player(location: string) - Move the player to a specific location". Only tools
+// in the registry with usage guidance that the acting actor may use are included;
+// params come from the server's schema, or from the tool itself for Go-only tools.
+func FormatToolDescriptions(specs []mcp.ToolSpec, actingNPCID string) string {
+	byName := make(map[string]mcp.ToolSpec, len(specs))
+	for _, spec := range specs {
+		byName[spec.Name] = spec
+	}
+
+	var lines []string
+	for _, name := range registeredToolNames() {
+		tool := toolRegistry[name]
+		if tool.Usage() == "" || !tool.Actors().Allows(actingNPCID) {
+			continue
+		}
+		var params []mcp.ToolParam
+		if local, ok := tool.(LocalTool); ok {
+			params = local.LocalParams()
+		} else if spec, ok := byName[name]; ok {
+			params = spec.Params
+		} else if len(specs) > 0 {
+			// The server doesn't provide it, so the director can't use it
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s(%s) - %s", name, formatParams(params), tool.Usage()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatParams(params []mcp.ToolParam) string {
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		part := fmt.Sprintf("%s: %s", param.Name, param.Type)
+		if !param.Required {
+			part += " (optional)"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MissingServerTools returns the director's tools that need the server but aren't
+// among specs, sorted.
+func MissingServerTools(specs []mcp.ToolSpec) []string {
+	missing, _ := compareToolRegistry(specs)
+	return missing
+}
+
+// compareToolRegistry returns registry tools the server lacks and server tools the
+// registry can't execute.
+func compareToolRegistry(specs []mcp.ToolSpec) (missing, unexecutable []string) {
+	onServer := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		onServer[spec.Name] = true
+		if _, ok := toolRegistry[spec.Name]; !ok {
+			unexecutable = append(unexecutable, spec.Name)
+		}
+	}
+	for _, name := range registeredToolNames() {
+		if _, local := toolRegistry[name].(LocalTool); !local && !onServer[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing, unexecutable
+}
+
+func registeredToolNames() []string {
+	names := make([]string, 0, len(toolRegistry))
+	for name := range toolRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// restrictedToolNames lists offered tools the acting actor may not use.
+func restrictedToolNames(actingNPCID string) []string {
+	var names []string
+	for _, name := range registeredToolNames() {
+		tool := toolRegistry[name]
+		if tool.Usage() != "" && !tool.Actors().Allows(actingNPCID) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -2,6 +2,7 @@ package director
 
 import (
 	"context"
+	"fmt"
 
 	"textadventure/internal/game"
 	"textadventure/internal/game/director/tools"
@@ -10,9 +11,55 @@ import (
 
 type MCPTool interface {
 	Validate(args map[string]interface{}) error
-	Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error
+	Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error
 	SuccessMessage(args map[string]interface{}, actingNPCID string) string
 	Name() string
+	// Usage is the one-line guidance shown to the director. Tools returning "" are
+	// executable but not offered to the director.
+	Usage() string
+	// Actors says whether the player, NPCs, or both may use the tool. ExecuteMutations
+	// rejects mutations from actors outside the scope.
+	Actors() tools.ActorScope
+}
+
+// LocalTool is implemented by tools handled entirely in Go. They don't exist on the
+// world-state server, so they describe their own params for the director prompt.
+type LocalTool interface {
+	LocalParams() []mcp.ToolParam
+}
+
+// WorldAwareTool is implemented by tools whose success message needs details from
+// the world, such as the facts of an examined item.
+type WorldAwareTool interface {
+	SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string
+}
+
+// ItemArgsTool is implemented by tools whose args name items. The executor rewrites
+// those args to canonical item IDs before executing, so naming variants like
+// "brass key" and "Brass_Key" reach the world server as "brass_key".
+type ItemArgsTool interface {
+	ItemArgs() []string
+}
+
+// NPCArgsTool is implemented by tools whose args name NPCs. The executor maps unmet
+// NPC aliases ("person_1") to real NPC IDs before executing.
+type NPCArgsTool interface {
+	NPCArgs() []string
+}
+
+// FollowUpTool is implemented by tools that decide, once executed, which further
+// mutations run, such as a contest running only its winner's. The executor runs them
+// straight after the tool, as the same actor.
+type FollowUpTool interface {
+	FollowUps(args map[string]interface{}) []game.ScheduledMutation
+}
+
+// PlanCheckTool is implemented by tools whose preconditions can be checked against the
+// local world, so ValidatePlan rejects a doomed mutation without a server round trip.
+// CheckPlan reports why the mutation would fail; when it wouldn't, it applies the
+// mutation's effect to world so the rest of the plan is checked against it.
+type PlanCheckTool interface {
+	CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error
 }
 
 var toolRegistry = make(map[string]MCPTool)
@@ -27,6 +74,22 @@ func init() {
 	RegisterTool(&tools.UnlockDoorTool{})
 	RegisterTool(&tools.UpdateNPCMemoryTool{})
 	RegisterTool(&tools.MarkNPCAsMetTool{})
+	RegisterTool(&tools.SetPlayerConditionTool{})
+	RegisterTool(&tools.ExamineInventoryItemTool{})
+	RegisterTool(&tools.ScheduleEventTool{})
+	RegisterTool(&tools.ContestTool{})
+	RegisterTool(&tools.SpeakToNPCTool{})
+	RegisterTool(&tools.SetNPCGoalTool{})
+	RegisterTool(&tools.AdjustNPCEmotionTool{})
+	RegisterTool(&tools.SetLocationAmbienceTool{})
+	RegisterTool(&tools.UpdateRelationshipTool{})
+	RegisterTool(&tools.PutItemInContainerTool{})
+	RegisterTool(&tools.TakeItemFromContainerTool{})
+	RegisterTool(&tools.OpenContainerTool{})
+	RegisterTool(&tools.NPCTakeItemTool{})
+	RegisterTool(&tools.NPCDropItemTool{})
+	RegisterTool(&tools.GiveItemToNPCTool{})
+	RegisterTool(&tools.NPCGiveItemToPlayerTool{})
 }
 
 func RegisterTool(tool MCPTool) {
@@ -36,4 +99,53 @@ func RegisterTool(tool MCPTool) {
 func GetTool(name string) (MCPTool, bool) {
 	tool, exists := toolRegistry[name]
 	return tool, exists
-}
\ No newline at end of file
+}
+
+// resolveItemArgs rewrites a tool's item args in place to canonical item IDs.
+func resolveItemArgs(tool MCPTool, args map[string]interface{}, world game.WorldState) error {
+	itemTool, ok := tool.(ItemArgsTool)
+	if !ok {
+		return nil
+	}
+	for _, key := range itemTool.ItemArgs() {
+		ref, ok := args[key].(string)
+		if !ok {
+			continue
+		}
+		itemID, err := game.ResolveItemID(world, ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		args[key] = itemID
+	}
+	return nil
+}
+
+// resolveNPCArgs rewrites a tool's NPC args in place to NPC IDs. Aliases in other args
+// (such as a transfer_item destination) are mapped too, since NPCs can hold items.
+func resolveNPCArgs(tool MCPTool, args map[string]interface{}, world game.WorldState) error {
+	npcArgs := map[string]bool{}
+	if npcTool, ok := tool.(NPCArgsTool); ok {
+		for _, key := range npcTool.NPCArgs() {
+			npcArgs[key] = true
+		}
+	}
+	for key, value := range args {
+		ref, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if !npcArgs[key] {
+			if npcID, ok := game.ResolveNPCAlias(world, ref); ok {
+				args[key] = npcID
+			}
+			continue
+		}
+		npcID, err := game.ResolveNPCID(world, ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		args[key] = npcID
+	}
+	return nil
+}
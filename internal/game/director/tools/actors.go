@@ -0,0 +1,55 @@
+package tools
+
+import "fmt"
+
+// ActorScope says which actors may trigger a tool.
+type ActorScope int
+
+const (
+	// Shared tools may be used on behalf of the player or an NPC.
+	Shared ActorScope = iota
+	// PlayerOnly tools act on the player and are rejected in NPC plans.
+	PlayerOnly
+	// NPCOnly tools are rejected in player plans.
+	NPCOnly
+)
+
+// Allows reports whether the acting actor may use a tool with this scope. An empty
+// actingNPCID means the player is acting.
+func (s ActorScope) Allows(actingNPCID string) bool {
+	switch s {
+	case PlayerOnly:
+		return actingNPCID == ""
+	case NPCOnly:
+		return actingNPCID != ""
+	default:
+		return true
+	}
+}
+
+func (s ActorScope) String() string {
+	switch s {
+	case PlayerOnly:
+		return "player-only"
+	case NPCOnly:
+		return "npc-only"
+	default:
+		return "shared"
+	}
+}
+
+// ParseActorScope reads a scope as written in scenario files: "player-only", "npc-only"
+// or "shared". An empty scope is player-only, the safe default for tools without Go-side
+// checks.
+func ParseActorScope(value string) (ActorScope, error) {
+	switch value {
+	case "", "player-only":
+		return PlayerOnly, nil
+	case "npc-only":
+		return NPCOnly, nil
+	case "shared":
+		return Shared, nil
+	default:
+		return PlayerOnly, fmt.Errorf("unknown actor scope %q", value)
+	}
+}
@@ -14,6 +14,18 @@ func (t *AddToInventoryTool) Name() string {
 	return "add_to_inventory"
 }
 
+func (t *AddToInventoryTool) Usage() string {
+	return "Add an item from current location to player's inventory"
+}
+
+func (t *AddToInventoryTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+func (t *AddToInventoryTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
 func (t *AddToInventoryTool) Validate(args map[string]interface{}) error {
 	item, ok := args["item"].(string)
 	if !ok || item == "" {
@@ -22,7 +34,7 @@ func (t *AddToInventoryTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *AddToInventoryTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *AddToInventoryTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	item := args["item"].(string)
 	_, err := client.AddToInventory(ctx, item)
 	return err
@@ -31,4 +43,14 @@ func (t *AddToInventoryTool) Execute(ctx context.Context, args map[string]interf
 func (t *AddToInventoryTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
 	item := args["item"].(string)
 	return fmt.Sprintf("Added %s to inventory", item)
-}
\ No newline at end of file
+}
+
+// CheckPlan checks that the item is in the player's location.
+func (t *AddToInventoryTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if !holds(*world, world.Location, item) {
+		return fmt.Errorf("item '%s' is not available in %s", item, world.Location)
+	}
+	moveItem(world, item, "player")
+	return nil
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type AdjustNPCEmotionTool struct{}
+
+func (t *AdjustNPCEmotionTool) Name() string {
+	return "adjust_npc_emotion"
+}
+
+func (t *AdjustNPCEmotionTool) Usage() string {
+	return "Raise or lower how an NPC feels (emotion is one of " + strings.Join(game.KnownEmotions, "|") + "; delta is -1 to 1, e.g. 0.3 for a fright; cause is the event that moved them)"
+}
+
+func (t *AdjustNPCEmotionTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *AdjustNPCEmotionTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
+func (t *AdjustNPCEmotionTool) Validate(args map[string]interface{}) error {
+	npcID, ok := args["npc_id"].(string)
+	if !ok || npcID == "" {
+		return fmt.Errorf("adjust_npc_emotion requires 'npc_id' parameter")
+	}
+	emotion, ok := args["emotion"].(string)
+	if !ok || !game.IsKnownEmotion(emotion) {
+		return fmt.Errorf("adjust_npc_emotion requires 'emotion' parameter, one of %s", strings.Join(game.KnownEmotions, ", "))
+	}
+	delta, ok := floatArg(args["delta"])
+	if !ok || delta < -1 || delta > 1 || delta == 0 {
+		return fmt.Errorf("adjust_npc_emotion requires a non-zero 'delta' between -1 and 1")
+	}
+	if cause, ok := args["cause"]; ok {
+		if _, isString := cause.(string); !isString {
+			return fmt.Errorf("adjust_npc_emotion 'cause' must be a string")
+		}
+	}
+	return nil
+}
+
+func (t *AdjustNPCEmotionTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	npcID := args["npc_id"].(string)
+	if _, ok := world.NPCs[npcID]; !ok {
+		return fmt.Errorf("NPC %s does not exist", npcID)
+	}
+	delta, _ := floatArg(args["delta"])
+	cause, _ := args["cause"].(string)
+	_, err := client.AdjustNPCEmotion(ctx, npcID, args["emotion"].(string), delta, cause)
+	return err
+}
+
+func (t *AdjustNPCEmotionTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	delta, _ := floatArg(args["delta"])
+	message := fmt.Sprintf("%s %s %+.2f", args["npc_id"].(string), args["emotion"].(string), delta)
+	if cause, _ := args["cause"].(string); cause != "" {
+		message += fmt.Sprintf(" (cause: %s)", cause)
+	}
+	return message
+}
+
+func floatArg(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
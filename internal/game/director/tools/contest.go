@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/conflict"
+	"textadventure/internal/game/rng"
+	"textadventure/internal/mcp"
+)
+
+// maxContestMutations caps each side's mutations in a contest, like a scheduled event's.
+const maxContestMutations = game.MaxScheduledMutations
+
+// ContestTool resolves a contested action between two actors in Go (see the conflict
+// package). Executing it rolls the contest and records the outcome in its args; the
+// executor then runs the winner's mutations and drops the loser's.
+type ContestTool struct{}
+
+func (t *ContestTool) Name() string {
+	return "contest"
+}
+
+func (t *ContestTool) Usage() string {
+	return fmt.Sprintf("Resolve a contested action instead of deciding it yourself (actor_a attempts action against actor_b, both player or NPC IDs in the same room; stakes is what is fought over, an item ID if there is one; a_wins and b_wins are lists of up to %d {tool, args} calls run for whoever wins)", maxContestMutations)
+}
+
+func (t *ContestTool) Actors() ActorScope {
+	return Shared
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *ContestTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{
+		{Name: "actor_a", Type: "string", Required: true},
+		{Name: "actor_b", Type: "string", Required: true},
+		{Name: "action", Type: "string", Required: true},
+		{Name: "stakes", Type: "string", Required: true},
+		{Name: "a_wins", Type: "array"},
+		{Name: "b_wins", Type: "array"},
+	}
+}
+
+func (t *ContestTool) Validate(args map[string]interface{}) error {
+	for _, key := range []string{"actor_a", "actor_b", "action", "stakes"} {
+		if value, ok := args[key].(string); !ok || value == "" {
+			return fmt.Errorf("contest requires '%s' parameter", key)
+		}
+	}
+	for _, key := range []string{"a_wins", "b_wins"} {
+		if _, err := contestMutations(key, args[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ContestTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	contest, err := contestOf(args, world)
+	if err != nil {
+		return err
+	}
+	outcome, err := conflict.Resolve(world, contest, rng.For("conflict"))
+	if err != nil {
+		return err
+	}
+	args["winner"] = outcome.Winner()
+	args["outcome"] = outcome.Summary()
+	return nil
+}
+
+func (t *ContestTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	if summary, ok := args["outcome"].(string); ok {
+		return summary
+	}
+	return fmt.Sprintf("Contest over %s: %s against %s", args["stakes"], args["actor_a"], args["actor_b"])
+}
+
+// FollowUps returns the winner's mutations once the contest has been executed.
+func (t *ContestTool) FollowUps(args map[string]interface{}) []game.ScheduledMutation {
+	winner, ok := args["winner"].(string)
+	if !ok {
+		return nil
+	}
+	key := "b_wins"
+	if winner == args["actor_a"] {
+		key = "a_wins"
+	}
+	mutations, _ := contestMutations(key, args[key])
+	return mutations
+}
+
+// contestOf reads the contest from args, rewriting both actors in args to "player" or
+// an NPC ID so FollowUps can tell which one won.
+func contestOf(args map[string]interface{}, world game.WorldState) (conflict.Contest, error) {
+	contest := conflict.Contest{
+		Action: args["action"].(string),
+		Stakes: args["stakes"].(string),
+	}
+	for _, actor := range []struct {
+		key    string
+		target *string
+	}{{"actor_a", &contest.Attacker}, {"actor_b", &contest.Defender}} {
+		id := "player"
+		if ref := args[actor.key].(string); !strings.EqualFold(ref, "player") {
+			npcID, err := game.ResolveNPCID(world, ref)
+			if err != nil {
+				return conflict.Contest{}, fmt.Errorf("%s: %w", actor.key, err)
+			}
+			id = npcID
+		}
+		*actor.target = id
+		args[actor.key] = id
+	}
+	return contest, nil
+}
+
+// contestMutations checks one side's mutations. Contests can't nest, and can't schedule
+// events, so a single roll stays a single roll.
+func contestMutations(key string, value interface{}) ([]game.ScheduledMutation, error) {
+	if value == nil {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("contest '%s' must be a list of {tool, args}", key)
+	}
+	if len(list) > maxContestMutations {
+		return nil, fmt.Errorf("contest allows at most %d mutations per side, got %d in '%s'", maxContestMutations, len(list), key)
+	}
+	mutations := make([]game.ScheduledMutation, 0, len(list))
+	for i, entry := range list {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("contest %s mutation %d must be an object with 'tool' and 'args'", key, i+1)
+		}
+		tool, ok := fields["tool"].(string)
+		if !ok || tool == "" {
+			return nil, fmt.Errorf("contest %s mutation %d requires 'tool'", key, i+1)
+		}
+		if tool == "contest" || tool == "schedule_event" {
+			return nil, fmt.Errorf("contest mutations can't use %s", tool)
+		}
+		args, _ := fields["args"].(map[string]interface{})
+		mutations = append(mutations, game.ScheduledMutation{Tool: tool, Args: args})
+	}
+	return mutations, nil
+}
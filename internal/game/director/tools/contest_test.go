@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/rng"
+)
+
+func contestArgs() map[string]interface{} {
+	return map[string]interface{}{
+		"actor_a": "Player",
+		"actor_b": "elena",
+		"action":  "grab the candlestick first",
+		"stakes":  "candlestick",
+		"a_wins": []interface{}{
+			map[string]interface{}{"tool": "add_to_inventory", "args": map[string]interface{}{"item": "candlestick"}},
+		},
+		"b_wins": []interface{}{
+			map[string]interface{}{"tool": "npc_take_item", "args": map[string]interface{}{"item": "candlestick"}},
+			map[string]interface{}{"tool": "update_npc_memory", "args": map[string]interface{}{"npc_id": "elena", "action": "snatched the candlestick"}},
+		},
+	}
+}
+
+// contestWorld has elena in the foyer with the player.
+func contestWorld() game.WorldState {
+	world := game.NewDefaultWorldState()
+	elena := world.NPCs["elena"]
+	elena.Location = "foyer"
+	world.NPCs["elena"] = elena
+	world.Items = map[string]game.ItemInfo{"candlestick": {Name: "candlestick", Location: "foyer"}}
+	return world
+}
+
+func TestContestValidate(t *testing.T) {
+	without := func(key string) map[string]interface{} {
+		args := contestArgs()
+		delete(args, key)
+		return args
+	}
+	with := func(key string, value interface{}) map[string]interface{} {
+		args := contestArgs()
+		args[key] = value
+		return args
+	}
+	tooMany := make([]interface{}, maxContestMutations+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]interface{}{"tool": "move_npc", "args": map[string]interface{}{"npc_id": "elena", "location": "study"}}
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"complete", contestArgs(), ""},
+		{"no mutations either side", without("b_wins"), ""},
+		{"missing actor_a", without("actor_a"), "'actor_a'"},
+		{"missing actor_b", without("actor_b"), "'actor_b'"},
+		{"missing action", without("action"), "'action'"},
+		{"empty stakes", with("stakes", ""), "'stakes'"},
+		{"side not a list", with("a_wins", "take the candlestick"), "must be a list"},
+		{"mutation not an object", with("a_wins", []interface{}{"add_to_inventory"}), "mutation 1 must be an object"},
+		{"mutation without tool", with("b_wins", []interface{}{map[string]interface{}{"args": map[string]interface{}{}}}), "mutation 1 requires 'tool'"},
+		{"nested contest", with("a_wins", []interface{}{map[string]interface{}{"tool": "contest"}}), "can't use contest"},
+		{"scheduled event", with("b_wins", []interface{}{map[string]interface{}{"tool": "schedule_event"}}), "can't use schedule_event"},
+		{"too many mutations", with("a_wins", tooMany), fmt.Sprintf("at most %d", maxContestMutations)},
+	}
+	tool := &ContestTool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tool.Validate(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContestExecuteRecordsOutcome(t *testing.T) {
+	tool := &ContestTool{}
+	rng.Seed(7)
+	args := contestArgs()
+	if err := tool.Execute(context.Background(), args, nil, contestWorld(), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if args["actor_a"] != "player" || args["actor_b"] != "elena" {
+		t.Errorf("actors = %v, %v; want them rewritten to player and elena", args["actor_a"], args["actor_b"])
+	}
+	winner, _ := args["winner"].(string)
+	if winner != "player" && winner != "elena" {
+		t.Fatalf("winner = %q", winner)
+	}
+	summary, _ := args["outcome"].(string)
+	if !strings.HasPrefix(summary, "Contest over candlestick (grab the candlestick first): ") {
+		t.Errorf("outcome = %q", summary)
+	}
+	if got := tool.SuccessMessage(args, ""); got != summary {
+		t.Errorf("SuccessMessage = %q, want the outcome %q", got, summary)
+	}
+
+	followUps := tool.FollowUps(args)
+	wantTool, wantCount := "add_to_inventory", 1
+	if winner == "elena" {
+		wantTool, wantCount = "npc_take_item", 2
+	}
+	if len(followUps) != wantCount || followUps[0].Tool != wantTool {
+		t.Errorf("winner %s gets follow-ups %v, want %d starting with %s", winner, followUps, wantCount, wantTool)
+	}
+
+	// The same seed replays the same contest
+	rng.Seed(7)
+	replay := contestArgs()
+	if err := tool.Execute(context.Background(), replay, nil, contestWorld(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if replay["outcome"] != summary {
+		t.Errorf("replayed outcome = %q, want %q", replay["outcome"], summary)
+	}
+}
+
+func TestContestExecuteRefusesImpossibleContests(t *testing.T) {
+	apart := game.NewDefaultWorldState() // elena is in the library
+	tests := []struct {
+		name    string
+		world   game.WorldState
+		actorB  string
+		wantErr string
+	}{
+		{"unknown NPC", contestWorld(), "the butler", "actor_b"},
+		{"not in the same room", apart, "elena", "not in the same place"},
+		{"against themselves", contestWorld(), "player", "themselves"},
+	}
+	tool := &ContestTool{}
+	for _, tt := range tests {
+		args := contestArgs()
+		args["actor_b"] = tt.actorB
+		err := tool.Execute(context.Background(), args, nil, tt.world, "")
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want one mentioning %q", tt.name, err, tt.wantErr)
+		}
+		if _, ok := args["winner"]; ok {
+			t.Errorf("%s: a refused contest recorded a winner", tt.name)
+		}
+	}
+}
+
+func TestContestBeforeExecution(t *testing.T) {
+	tool := &ContestTool{}
+	args := contestArgs()
+	if followUps := tool.FollowUps(args); followUps != nil {
+		t.Errorf("follow-ups before the roll = %v", followUps)
+	}
+	if got := tool.SuccessMessage(args, ""); got != "Contest over candlestick: Player against elena" {
+		t.Errorf("SuccessMessage = %q", got)
+	}
+}
+
+func TestContestFollowUpsPickTheWinnersSide(t *testing.T) {
+	tool := &ContestTool{}
+	for winner, want := range map[string]string{"player": "add_to_inventory", "elena": "npc_take_item"} {
+		args := contestArgs()
+		args["actor_a"], args["winner"] = "player", winner
+		followUps := tool.FollowUps(args)
+		if len(followUps) == 0 || followUps[0].Tool != want {
+			t.Errorf("%s wins: follow-ups %v, want %s first", winner, followUps, want)
+		}
+	}
+}
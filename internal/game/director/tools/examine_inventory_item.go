@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// ExamineInventoryItemTool is a no-op mutation: it only checks the item is carried and
+// carries the item's established facts through to the narrator.
+type ExamineInventoryItemTool struct{}
+
+func (t *ExamineInventoryItemTool) Name() string {
+	return "examine_inventory_item"
+}
+
+func (t *ExamineInventoryItemTool) Usage() string {
+	return "Examine an item the player is carrying (no world change)"
+}
+
+func (t *ExamineInventoryItemTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *ExamineInventoryItemTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{{Name: "item", Type: "string", Required: true}}
+}
+
+func (t *ExamineInventoryItemTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
+func (t *ExamineInventoryItemTool) Validate(args map[string]interface{}) error {
+	item, ok := args["item"].(string)
+	if !ok || item == "" {
+		return fmt.Errorf("examine_inventory_item requires 'item' parameter")
+	}
+	return nil
+}
+
+func (t *ExamineInventoryItemTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if _, carried := game.FindCarriedItem(world, item); !carried {
+		return fmt.Errorf("player is not carrying %s", item)
+	}
+	return nil
+}
+
+func (t *ExamineInventoryItemTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	item := args["item"].(string)
+	return fmt.Sprintf("Player examines their %s", item)
+}
+
+// SuccessMessageWithWorld includes the item's established facts so the narrator
+// describes the same object the world knows about.
+func (t *ExamineInventoryItemTool) SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string {
+	item := args["item"].(string)
+	itemID, _ := game.FindCarriedItem(world, item)
+	info, ok := world.Items[itemID]
+	if !ok {
+		return t.SuccessMessage(args, actingNPCID)
+	}
+	name := info.Name
+	if name == "" {
+		name = itemID
+	}
+	if len(info.Facts) == 0 {
+		return fmt.Sprintf("Player examines their %s", name)
+	}
+	return fmt.Sprintf("Player examines their %s (Established Facts: %s)", name, strings.Join(info.Facts, "; "))
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+func examineWorld() game.WorldState {
+	world := game.NewDefaultWorldState()
+	world.Inventory = []string{"brass_key", "pebble"}
+	world.Items = map[string]game.ItemInfo{
+		"brass_key": {Name: "brass key", Location: "player", Facts: []string{"its bow is shaped like a heron", "it is warm to the touch"}},
+		"music_box": {Name: "music box", Location: "foyer"},
+	}
+	return world
+}
+
+func TestExamineInventoryItemValidate(t *testing.T) {
+	tool := &ExamineInventoryItemTool{}
+	for _, args := range []map[string]interface{}{{}, {"item": ""}, {"item": 3}} {
+		if err := tool.Validate(args); err == nil {
+			t.Errorf("Validate(%v) accepted", args)
+		}
+	}
+	if err := tool.Validate(map[string]interface{}{"item": "brass_key"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExamineInventoryItemOnlyExaminesCarriedItems(t *testing.T) {
+	tool := &ExamineInventoryItemTool{}
+	world := examineWorld()
+	for _, item := range []string{"brass_key", "brass key", "key"} {
+		if err := tool.Execute(context.Background(), map[string]interface{}{"item": item}, nil, world, ""); err != nil {
+			t.Errorf("%s: %v", item, err)
+		}
+	}
+	for _, item := range []string{"music_box", "sword"} {
+		if err := tool.Execute(context.Background(), map[string]interface{}{"item": item}, nil, world, ""); err == nil {
+			t.Errorf("examined %s, which the player isn't carrying", item)
+		}
+	}
+}
+
+// The narrator is handed the item's established facts, by its display name.
+func TestExamineInventoryItemCarriesTheCanon(t *testing.T) {
+	tool := &ExamineInventoryItemTool{}
+	world := examineWorld()
+	tests := []struct {
+		item string
+		want string
+	}{
+		{"key", "Player examines their brass key (Established Facts: its bow is shaped like a heron; it is warm to the touch)"},
+		{"pebble", "Player examines their pebble"}, // carried but not in the registry
+	}
+	for _, tt := range tests {
+		if got := tool.SuccessMessageWithWorld(map[string]interface{}{"item": tt.item}, world, ""); got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.item, got, tt.want)
+		}
+	}
+	world.Items["pebble"] = game.ItemInfo{Location: "player"}
+	if got := tool.SuccessMessageWithWorld(map[string]interface{}{"item": "pebble"}, world, ""); got != "Player examines their pebble" {
+		t.Errorf("item without facts: %q", got)
+	}
+}
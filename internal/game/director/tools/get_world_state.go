@@ -13,11 +13,20 @@ func (t *GetWorldStateTool) Name() string {
 	return "get_world_state"
 }
 
+// Usage is empty: the director already sees the world state, so this tool isn't offered.
+func (t *GetWorldStateTool) Usage() string {
+	return ""
+}
+
+func (t *GetWorldStateTool) Actors() ActorScope {
+	return Shared
+}
+
 func (t *GetWorldStateTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *GetWorldStateTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *GetWorldStateTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	_, err := client.GetWorldState(ctx)
 	return err
 }
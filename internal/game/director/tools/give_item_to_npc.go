@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// GiveItemToNPCTool hands an item from the player's inventory to an NPC in the same
+// room, who remembers being given it.
+type GiveItemToNPCTool struct{}
+
+func (t *GiveItemToNPCTool) Name() string {
+	return "give_item_to_npc"
+}
+
+func (t *GiveItemToNPCTool) Usage() string {
+	return "The player gives an item they carry to an NPC in the same room"
+}
+
+func (t *GiveItemToNPCTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *GiveItemToNPCTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{
+		{Name: "item", Type: "string", Required: true},
+		{Name: "npc_id", Type: "string", Required: true},
+	}
+}
+
+func (t *GiveItemToNPCTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
+func (t *GiveItemToNPCTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
+func (t *GiveItemToNPCTool) Validate(args map[string]interface{}) error {
+	for _, key := range []string{"item", "npc_id"} {
+		if value, ok := args[key].(string); !ok || value == "" {
+			return fmt.Errorf("give_item_to_npc requires '%s' parameter", key)
+		}
+	}
+	return nil
+}
+
+func (t *GiveItemToNPCTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	npcID := args["npc_id"].(string)
+	if err := t.check(args, world); err != nil {
+		return err
+	}
+	if _, err := client.TransferItemToNPC(ctx, item, "player", npcID); err != nil {
+		return err
+	}
+	thought := fmt.Sprintf("The player gave me the %s", itemLabel(world, item))
+	_, err := client.UpdateNPCMemory(ctx, npcID, thought, "", game.TurnIndexFromContext(ctx))
+	return err
+}
+
+// check reports why the player can't give the item: the NPC isn't with them, or they
+// don't have it.
+func (t *GiveItemToNPCTool) check(args map[string]interface{}, world game.WorldState) error {
+	item := args["item"].(string)
+	npcID := args["npc_id"].(string)
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return fmt.Errorf("no such person %q", npcID)
+	}
+	if npc.Location != world.Location {
+		return fmt.Errorf("%s is not here", game.MaskUnmetNPCNames(world, npcID))
+	}
+	if !slices.Contains(world.Inventory, item) {
+		return fmt.Errorf("you don't have the %s", itemLabel(world, item))
+	}
+	return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world.
+func (t *GiveItemToNPCTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	if err := t.check(args, *world); err != nil {
+		return err
+	}
+	moveItem(world, args["item"].(string), args["npc_id"].(string))
+	return nil
+}
+
+func (t *GiveItemToNPCTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("Player gave the %s to %s", args["item"].(string), args["npc_id"].(string))
+}
+
+func (t *GiveItemToNPCTool) SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string {
+	return fmt.Sprintf("Player gave the %s to %s", itemLabel(world, args["item"].(string)), args["npc_id"].(string))
+}
@@ -14,6 +14,18 @@ func (t *MarkNPCAsMetTool) Name() string {
 	return "mark_npc_as_met"
 }
 
+func (t *MarkNPCAsMetTool) Usage() string {
+	return "Mark that the player has met and learned an NPC's name"
+}
+
+func (t *MarkNPCAsMetTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *MarkNPCAsMetTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
 func (t *MarkNPCAsMetTool) Validate(args map[string]interface{}) error {
 	npcID, ok := args["npc_id"].(string)
 	if !ok || npcID == "" {
@@ -22,7 +34,7 @@ func (t *MarkNPCAsMetTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *MarkNPCAsMetTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *MarkNPCAsMetTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	npcID := args["npc_id"].(string)
 	_, err := client.MarkNPCAsMetMethod(ctx, npcID)
 	return err
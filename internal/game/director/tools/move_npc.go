@@ -14,6 +14,18 @@ func (t *MoveNPCTool) Name() string {
 	return "move_npc"
 }
 
+func (t *MoveNPCTool) Usage() string {
+	return "Move an NPC to a specific location"
+}
+
+func (t *MoveNPCTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *MoveNPCTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
 func (t *MoveNPCTool) Validate(args map[string]interface{}) error {
 	npcID, hasNPC := args["npc_id"].(string)
 	location, hasLocation := args["location"].(string)
@@ -27,7 +39,7 @@ func (t *MoveNPCTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *MoveNPCTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *MoveNPCTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	npcID := args["npc_id"].(string)
 	location := args["location"].(string)
 	_, err := client.MoveNPC(ctx, npcID, location)
@@ -38,4 +50,21 @@ func (t *MoveNPCTool) SuccessMessage(args map[string]interface{}, actingNPCID st
 	npcID := args["npc_id"].(string)
 	location := args["location"].(string)
 	return fmt.Sprintf("NPC %s moved to %s", npcID, location)
-}
\ No newline at end of file
+}
+
+// CheckPlan checks the move against the local world: the NPC must exist and have an
+// open exit to the location.
+func (t *MoveNPCTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	npcID := args["npc_id"].(string)
+	location := args["location"].(string)
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return fmt.Errorf("NPC '%s' does not exist", npcID)
+	}
+	if err := checkMove(*world, npc.Location, location); err != nil {
+		return err
+	}
+	npc.Location = location
+	world.NPCs[npcID] = npc
+	return nil
+}
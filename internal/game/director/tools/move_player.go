@@ -8,12 +8,23 @@ import (
 	"textadventure/internal/mcp"
 )
 
+// MovedToPrefix starts move_player's success message; the destination follows it.
+const MovedToPrefix = "Moved to "
+
 type MovePlayerTool struct{}
 
 func (t *MovePlayerTool) Name() string {
 	return "move_player"
 }
 
+func (t *MovePlayerTool) Usage() string {
+	return "Move the player to a specific location"
+}
+
+func (t *MovePlayerTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
 func (t *MovePlayerTool) Validate(args map[string]interface{}) error {
 	location, ok := args["location"].(string)
 	if !ok || location == "" {
@@ -22,7 +33,7 @@ func (t *MovePlayerTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *MovePlayerTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *MovePlayerTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	location := args["location"].(string)
 	_, err := client.MovePlayer(ctx, location)
 	return err
@@ -30,5 +41,16 @@ func (t *MovePlayerTool) Execute(ctx context.Context, args map[string]interface{
 
 func (t *MovePlayerTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
 	location := args["location"].(string)
-	return fmt.Sprintf("Moved to %s", location)
-}
\ No newline at end of file
+	return MovedToPrefix + location
+}
+
+// CheckPlan checks the move against the local world: the player needs an open exit to
+// the location.
+func (t *MovePlayerTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	location := args["location"].(string)
+	if err := checkMove(*world, world.Location, location); err != nil {
+		return err
+	}
+	world.Location = location
+	return nil
+}
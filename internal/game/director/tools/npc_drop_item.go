@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// NPCDropItemTool has the acting NPC put down an item they carry, in the room they are in.
+type NPCDropItemTool struct{}
+
+func (t *NPCDropItemTool) Name() string {
+	return "npc_drop_item"
+}
+
+func (t *NPCDropItemTool) Usage() string {
+	return "The acting NPC drops an item they carry in their room"
+}
+
+func (t *NPCDropItemTool) Actors() ActorScope {
+	return NPCOnly
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *NPCDropItemTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{
+		{Name: "item", Type: "string", Required: true},
+	}
+}
+
+func (t *NPCDropItemTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
+func (t *NPCDropItemTool) Validate(args map[string]interface{}) error {
+	item, ok := args["item"].(string)
+	if !ok || item == "" {
+		return fmt.Errorf("npc_drop_item requires 'item' parameter")
+	}
+	return nil
+}
+
+func (t *NPCDropItemTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if err := t.check(args, world, actingNPCID); err != nil {
+		return err
+	}
+	_, err := client.TransferItemFromNPC(ctx, item, actingNPCID, world.NPCs[actingNPCID].Location)
+	return err
+}
+
+// check reports why the NPC can't drop the item: they aren't carrying it.
+func (t *NPCDropItemTool) check(args map[string]interface{}, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	npc, ok := world.NPCs[actingNPCID]
+	if !ok {
+		return fmt.Errorf("NPC '%s' does not exist", actingNPCID)
+	}
+	if !slices.Contains(npc.Inventory, item) {
+		return fmt.Errorf("%s is not carrying %s", actingNPCID, item)
+	}
+	return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world.
+func (t *NPCDropItemTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	if err := t.check(args, *world, actingNPCID); err != nil {
+		return err
+	}
+	moveItem(world, args["item"].(string), world.NPCs[actingNPCID].Location)
+	return nil
+}
+
+func (t *NPCDropItemTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("%s dropped the %s", actingNPCID, args["item"].(string))
+}
+
+func (t *NPCDropItemTool) SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string {
+	return fmt.Sprintf("%s dropped the %s", actingNPCID, itemLabel(world, args["item"].(string)))
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// NPCGiveItemToPlayerTool has the acting NPC hand an item they carry to the player,
+// who must be in the same room.
+type NPCGiveItemToPlayerTool struct{}
+
+func (t *NPCGiveItemToPlayerTool) Name() string {
+	return "npc_give_item_to_player"
+}
+
+func (t *NPCGiveItemToPlayerTool) Usage() string {
+	return "The acting NPC gives an item they carry to the player in the same room"
+}
+
+func (t *NPCGiveItemToPlayerTool) Actors() ActorScope {
+	return NPCOnly
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *NPCGiveItemToPlayerTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{
+		{Name: "item", Type: "string", Required: true},
+	}
+}
+
+func (t *NPCGiveItemToPlayerTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
+func (t *NPCGiveItemToPlayerTool) Validate(args map[string]interface{}) error {
+	item, ok := args["item"].(string)
+	if !ok || item == "" {
+		return fmt.Errorf("npc_give_item_to_player requires 'item' parameter")
+	}
+	return nil
+}
+
+func (t *NPCGiveItemToPlayerTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if err := t.check(args, world, actingNPCID); err != nil {
+		return err
+	}
+	_, err := client.TransferItemFromNPC(ctx, item, actingNPCID, "player")
+	return err
+}
+
+// check reports why the NPC can't give the item: the player isn't with them, or they
+// don't have it.
+func (t *NPCGiveItemToPlayerTool) check(args map[string]interface{}, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	npc, ok := world.NPCs[actingNPCID]
+	if !ok {
+		return fmt.Errorf("NPC '%s' does not exist", actingNPCID)
+	}
+	if npc.Location != world.Location {
+		return fmt.Errorf("the player is not here")
+	}
+	if !slices.Contains(npc.Inventory, item) {
+		return fmt.Errorf("%s doesn't have the %s", actingNPCID, itemLabel(world, item))
+	}
+	return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world.
+func (t *NPCGiveItemToPlayerTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	if err := t.check(args, *world, actingNPCID); err != nil {
+		return err
+	}
+	moveItem(world, args["item"].(string), "player")
+	return nil
+}
+
+func (t *NPCGiveItemToPlayerTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("%s gave the %s to the player", actingNPCID, args["item"].(string))
+}
+
+func (t *NPCGiveItemToPlayerTool) SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string {
+	return fmt.Sprintf("%s gave the %s to the player", actingNPCID, itemLabel(world, args["item"].(string)))
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// NPCTakeItemTool has the acting NPC pick up an item from the room they are in.
+type NPCTakeItemTool struct{}
+
+func (t *NPCTakeItemTool) Name() string {
+	return "npc_take_item"
+}
+
+func (t *NPCTakeItemTool) Usage() string {
+	return "The acting NPC picks up an item in their room"
+}
+
+func (t *NPCTakeItemTool) Actors() ActorScope {
+	return NPCOnly
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *NPCTakeItemTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{
+		{Name: "item", Type: "string", Required: true},
+	}
+}
+
+func (t *NPCTakeItemTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
+func (t *NPCTakeItemTool) Validate(args map[string]interface{}) error {
+	item, ok := args["item"].(string)
+	if !ok || item == "" {
+		return fmt.Errorf("npc_take_item requires 'item' parameter")
+	}
+	return nil
+}
+
+func (t *NPCTakeItemTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if err := t.check(args, world, actingNPCID); err != nil {
+		return err
+	}
+	_, err := client.TransferItemToNPC(ctx, item, world.NPCs[actingNPCID].Location, actingNPCID)
+	return err
+}
+
+// check reports why the NPC can't pick the item up: it isn't in their room.
+func (t *NPCTakeItemTool) check(args map[string]interface{}, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	npc, ok := world.NPCs[actingNPCID]
+	if !ok {
+		return fmt.Errorf("NPC '%s' does not exist", actingNPCID)
+	}
+	if world.Items[item].Location != npc.Location {
+		return fmt.Errorf("%s is not in the %s with %s", item, npc.Location, actingNPCID)
+	}
+	return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world.
+func (t *NPCTakeItemTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	if err := t.check(args, *world, actingNPCID); err != nil {
+		return err
+	}
+	moveItem(world, args["item"].(string), actingNPCID)
+	return nil
+}
+
+func (t *NPCTakeItemTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("%s picked up the %s", actingNPCID, args["item"].(string))
+}
+
+func (t *NPCTakeItemTool) SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string {
+	return fmt.Sprintf("%s picked up the %s", actingNPCID, itemLabel(world, args["item"].(string)))
+}
+
+// itemLabel names an item in a success message: by its name, or by its ID when it has none.
+func itemLabel(world game.WorldState, itemID string) string {
+	if name := strings.TrimSpace(world.Items[itemID].Name); name != "" {
+		return name
+	}
+	return itemID
+}
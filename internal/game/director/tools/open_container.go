@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// OpenContainerTool records that the player has looked inside a container, which is
+// what lets its contents into world context.
+type OpenContainerTool struct{}
+
+func (t *OpenContainerTool) Name() string {
+	return "open_container"
+}
+
+func (t *OpenContainerTool) Usage() string {
+	return "Open or look inside a container the player can reach, revealing what it holds"
+}
+
+func (t *OpenContainerTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+func (t *OpenContainerTool) ItemArgs() []string {
+	return []string{"container"}
+}
+
+func (t *OpenContainerTool) Validate(args map[string]interface{}) error {
+	container, ok := args["container"].(string)
+	if !ok || container == "" {
+		return fmt.Errorf("open_container requires 'container' parameter")
+	}
+	return nil
+}
+
+func (t *OpenContainerTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	container := args["container"].(string)
+	if err := checkContainer(world, container, actingNPCID); err != nil {
+		return err
+	}
+	_, err := client.OpenContainer(ctx, container)
+	return err
+}
+
+func (t *OpenContainerTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("Player opens %s", args["container"].(string))
+}
+
+// SuccessMessageWithWorld lists what the container holds, so the narrator reveals the
+// contents the player now sees and nothing else.
+func (t *OpenContainerTool) SuccessMessageWithWorld(args map[string]interface{}, world game.WorldState, actingNPCID string) string {
+	containerID := args["container"].(string)
+	refs := game.NewReferences(world)
+	contents := world.Items[containerID].Contains
+	if len(contents) == 0 {
+		return fmt.Sprintf("Player opens %s: it is empty", refs.Ref(containerID))
+	}
+	return fmt.Sprintf("Player opens %s: it holds %s", refs.Ref(containerID), strings.Join(refs.Refs(contents), ", "))
+}
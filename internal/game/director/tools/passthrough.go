@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// PassthroughTool runs a scenario-specific server tool (ring_bell, pull_lever) that has
+// no Go implementation. Its args are checked against the params the server reports and
+// the call goes to the server as-is.
+type PassthroughTool struct {
+	name   string
+	usage  string
+	params []mcp.ToolParam
+	actors ActorScope
+}
+
+// NewPassthroughTool wraps a server tool. An empty usage falls back to the first line of
+// the tool's server description.
+func NewPassthroughTool(spec mcp.ToolSpec, usage string, actors ActorScope) *PassthroughTool {
+	if strings.TrimSpace(usage) == "" {
+		usage, _, _ = strings.Cut(strings.TrimSpace(spec.Description), "\n")
+	}
+	if strings.TrimSpace(usage) == "" {
+		usage = strings.ReplaceAll(spec.Name, "_", " ")
+	}
+	return &PassthroughTool{name: spec.Name, usage: usage, params: spec.Params, actors: actors}
+}
+
+func (t *PassthroughTool) Name() string {
+	return t.name
+}
+
+func (t *PassthroughTool) Usage() string {
+	return t.usage
+}
+
+func (t *PassthroughTool) Actors() ActorScope {
+	return t.actors
+}
+
+func (t *PassthroughTool) Validate(args map[string]interface{}) error {
+	known := make(map[string]bool, len(t.params))
+	for _, param := range t.params {
+		known[param.Name] = true
+		value, ok := args[param.Name]
+		if !ok || value == nil {
+			if param.Required {
+				return fmt.Errorf("%s requires '%s' parameter", t.name, param.Name)
+			}
+			continue
+		}
+		if !matchesParamType(value, param.Type) {
+			return fmt.Errorf("%s '%s' must be %s", t.name, param.Name, param.Type)
+		}
+	}
+	var unknown []string
+	for name := range args {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s does not take %s", t.name, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func (t *PassthroughTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	_, err := client.CallToolValidated(ctx, t.name, args)
+	return err
+}
+
+// SuccessMessage renders the call from its name and args, e.g. "Ring bell (rope: north)".
+func (t *PassthroughTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	verb := strings.ReplaceAll(t.name, "_", " ")
+	verb = strings.ToUpper(verb[:1]) + verb[1:]
+	if len(args) == 0 {
+		return verb
+	}
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, args[name]))
+	}
+	return fmt.Sprintf("%s (%s)", verb, strings.Join(parts, ", "))
+}
+
+// matchesParamType checks a decoded JSON value against a param type as rendered by
+// mcp.ToolSpecsFromTools ("string", "integer", "string[]", ...).
+func matchesParamType(value interface{}, paramType string) bool {
+	if elemType, ok := strings.CutSuffix(paramType, "[]"); ok {
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, elem := range list {
+			if !matchesParamType(elem, elemType) {
+				return false
+			}
+		}
+		return true
+	}
+	switch paramType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		_, ok := intArg(value)
+		return ok
+	case "number":
+		switch value.(type) {
+		case float64, int:
+			return true
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	default:
+		return true
+	}
+}
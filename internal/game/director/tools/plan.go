@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+
+	"textadventure/internal/game"
+)
+
+// checkMove reports why nothing can walk from one location straight to another: the
+// destination doesn't exist, there is no exit to it, or the door on that exit is locked.
+func checkMove(world game.WorldState, from, to string) error {
+	if _, ok := world.Locations[to]; !ok {
+		return fmt.Errorf("location %s does not exist", to)
+	}
+	for direction, destination := range world.Locations[from].Exits {
+		if destination != to {
+			continue
+		}
+		if door, ok := world.Locations[from].Doors[direction]; ok && door.Locked {
+			return fmt.Errorf("the door to the %s in %s is locked", direction, from)
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot move directly from %s to %s", from, to)
+}
+
+// holds reports whether holder (a location, an NPC, a container or "player") has the
+// item. Without an item registry there is nothing to check against, so it does.
+func holds(world game.WorldState, holder, itemID string) bool {
+	if len(world.Items) == 0 {
+		return true
+	}
+	switch {
+	case holder == "player" && slices.Contains(world.Inventory, itemID):
+		return true
+	case slices.Contains(world.NPCs[holder].Inventory, itemID):
+		return true
+	}
+	return world.Items[itemID].Location == holder
+}
+
+// moveItem applies an item changing hands to a plan's world, so later mutations in the
+// plan see it with its new holder.
+func moveItem(world *game.WorldState, itemID, holder string) {
+	item, ok := world.Items[itemID]
+	from := item.Location
+	if ok && world.Items[from].IsContainer {
+		container := world.Items[from]
+		container.Contains = slices.DeleteFunc(container.Contains, func(id string) bool { return id == itemID })
+		world.Items[from] = container
+	}
+	world.Inventory = slices.DeleteFunc(world.Inventory, func(id string) bool { return id == itemID })
+	for npcID, npc := range world.NPCs {
+		if slices.Contains(npc.Inventory, itemID) {
+			npc.Inventory = slices.DeleteFunc(slices.Clone(npc.Inventory), func(id string) bool { return id == itemID })
+			world.NPCs[npcID] = npc
+		}
+	}
+	switch {
+	case holder == "player":
+		world.Inventory = append(world.Inventory, itemID)
+	case world.Items[holder].IsContainer:
+		container := world.Items[holder]
+		container.Contains = append(container.Contains, itemID)
+		world.Items[holder] = container
+	default:
+		if npc, isNPC := world.NPCs[holder]; isNPC {
+			npc.Inventory = append(slices.Clone(npc.Inventory), itemID)
+			world.NPCs[holder] = npc
+		}
+	}
+	if ok {
+		item.Location = holder
+		world.Items[itemID] = item
+	}
+}
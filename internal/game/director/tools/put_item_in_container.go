@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type PutItemInContainerTool struct{}
+
+func (t *PutItemInContainerTool) Name() string {
+	return "put_item_in_container"
+}
+
+func (t *PutItemInContainerTool) Usage() string {
+	return "Put an item the actor carries or can reach into a container (a drawer, a box, a bag)"
+}
+
+func (t *PutItemInContainerTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *PutItemInContainerTool) ItemArgs() []string {
+	return []string{"item", "container"}
+}
+
+func (t *PutItemInContainerTool) Validate(args map[string]interface{}) error {
+	item, ok := args["item"].(string)
+	if !ok || item == "" {
+		return fmt.Errorf("put_item_in_container requires 'item' parameter")
+	}
+	container, ok := args["container"].(string)
+	if !ok || container == "" {
+		return fmt.Errorf("put_item_in_container requires 'container' parameter")
+	}
+	return nil
+}
+
+func (t *PutItemInContainerTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	container := args["container"].(string)
+	if err := t.check(args, world, actingNPCID); err != nil {
+		return err
+	}
+	if _, err := client.PutItemInContainer(ctx, item, container); err != nil {
+		return err
+	}
+	return markOpened(ctx, client, world, container, actingNPCID)
+}
+
+func (t *PutItemInContainerTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("Put %s in %s", args["item"].(string), args["container"].(string))
+}
+
+// check reports why the actor can't put the item in the container.
+func (t *PutItemInContainerTool) check(args map[string]interface{}, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if err := checkContainer(world, args["container"].(string), actingNPCID); err != nil {
+		return err
+	}
+	if !game.WithinReach(world, item, actingNPCID) {
+		return fmt.Errorf("%s is out of reach", item)
+	}
+	return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world.
+func (t *PutItemInContainerTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	if err := t.check(args, *world, actingNPCID); err != nil {
+		return err
+	}
+	moveItem(world, args["item"].(string), args["container"].(string))
+	return nil
+}
+
+// checkContainer reports why an actor can't use a container: it isn't one, it's locked,
+// or it's out of their reach.
+func checkContainer(world game.WorldState, containerID, actingNPCID string) error {
+	container := world.Items[containerID]
+	if !container.IsContainer {
+		return fmt.Errorf("%s is not a container", containerID)
+	}
+	if container.Locked {
+		return fmt.Errorf("%s is locked", containerID)
+	}
+	if !game.WithinReach(world, containerID, actingNPCID) {
+		return fmt.Errorf("%s is out of reach", containerID)
+	}
+	return nil
+}
+
+// markOpened records that the player has looked inside a container they just used, so
+// its contents enter world context from now on. NPCs using one reveal nothing.
+func markOpened(ctx context.Context, client mcp.WorldClient, world game.WorldState, containerID, actingNPCID string) error {
+	if actingNPCID != "" || game.ContainerOpened(world.Items[containerID]) {
+		return nil
+	}
+	_, err := client.OpenContainer(ctx, containerID)
+	return err
+}
@@ -14,6 +14,18 @@ func (t *RemoveFromInventoryTool) Name() string {
 	return "remove_from_inventory"
 }
 
+func (t *RemoveFromInventoryTool) Usage() string {
+	return "Remove an item from player's inventory to current location"
+}
+
+func (t *RemoveFromInventoryTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+func (t *RemoveFromInventoryTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
 func (t *RemoveFromInventoryTool) Validate(args map[string]interface{}) error {
 	item, ok := args["item"].(string)
 	if !ok || item == "" {
@@ -22,7 +34,7 @@ func (t *RemoveFromInventoryTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *RemoveFromInventoryTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *RemoveFromInventoryTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	item := args["item"].(string)
 	_, err := client.RemoveFromInventory(ctx, item)
 	return err
@@ -31,4 +43,14 @@ func (t *RemoveFromInventoryTool) Execute(ctx context.Context, args map[string]i
 func (t *RemoveFromInventoryTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
 	item := args["item"].(string)
 	return fmt.Sprintf("Removed %s from inventory", item)
-}
\ No newline at end of file
+}
+
+// CheckPlan checks that the player is carrying the item.
+func (t *RemoveFromInventoryTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	if !holds(*world, "player", item) {
+		return fmt.Errorf("item '%s' is not in inventory", item)
+	}
+	moveItem(world, item, world.Location)
+	return nil
+}
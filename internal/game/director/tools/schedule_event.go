@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// ScheduleEventTool defers mutations to a later turn, for consequences like a lit fuse.
+// The engine counts the event down and runs its mutations when it fires.
+type ScheduleEventTool struct{}
+
+func (t *ScheduleEventTool) Name() string {
+	return "schedule_event"
+}
+
+func (t *ScheduleEventTool) Usage() string {
+	return fmt.Sprintf("Make something happen later (delay_turns 1-%d; mutations is a list of up to %d {tool, args} calls run when it fires; description is what happens)", game.MaxScheduleDelay, game.MaxScheduledMutations)
+}
+
+func (t *ScheduleEventTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *ScheduleEventTool) Validate(args map[string]interface{}) error {
+	delay, ok := intArg(args["delay_turns"])
+	if !ok || delay < 1 || delay > game.MaxScheduleDelay {
+		return fmt.Errorf("schedule_event requires 'delay_turns' between 1 and %d", game.MaxScheduleDelay)
+	}
+	description, ok := args["description"].(string)
+	if !ok || description == "" {
+		return fmt.Errorf("schedule_event requires 'description' parameter")
+	}
+	if _, err := scheduledMutations(args["mutations"]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ScheduleEventTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	delay, _ := intArg(args["delay_turns"])
+	mutations, _ := scheduledMutations(args["mutations"])
+	location := world.Location
+	if npc, ok := world.NPCs[actingNPCID]; ok && npc.Location != "" {
+		location = npc.Location
+	}
+	_, err := client.ScheduleEvent(ctx, delay, args["description"].(string), location, mutations)
+	return err
+}
+
+func (t *ScheduleEventTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	delay, _ := intArg(args["delay_turns"])
+	return fmt.Sprintf("Scheduled in %d turns: %s", delay, args["description"].(string))
+}
+
+// scheduledMutations checks the mutations of a schedule_event call. Events can't
+// schedule further events, so chains stay bounded.
+func scheduledMutations(value interface{}) ([]game.ScheduledMutation, error) {
+	if value == nil {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schedule_event 'mutations' must be a list of {tool, args}")
+	}
+	if len(list) > game.MaxScheduledMutations {
+		return nil, fmt.Errorf("schedule_event allows at most %d mutations, got %d", game.MaxScheduledMutations, len(list))
+	}
+	mutations := make([]game.ScheduledMutation, 0, len(list))
+	for i, entry := range list {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schedule_event mutation %d must be an object with 'tool' and 'args'", i+1)
+		}
+		tool, ok := fields["tool"].(string)
+		if !ok || tool == "" {
+			return nil, fmt.Errorf("schedule_event mutation %d requires 'tool'", i+1)
+		}
+		if tool == "schedule_event" {
+			return nil, fmt.Errorf("scheduled events can't schedule further events")
+		}
+		args, _ := fields["args"].(map[string]interface{})
+		mutations = append(mutations, game.ScheduledMutation{Tool: tool, Args: args})
+	}
+	return mutations, nil
+}
+
+// intArg reads a whole-number arg, which arrives as float64 when decoded from JSON.
+func intArg(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		if v != float64(int(v)) {
+			return 0, false
+		}
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type SetLocationAmbienceTool struct{}
+
+func (t *SetLocationAmbienceTool) Name() string {
+	return "set_location_ambience"
+}
+
+func (t *SetLocationAmbienceTool) Usage() string {
+	return "Set a location's ongoing background ambience when an action changes it for good (the fridge is unplugged → \"the kitchen is silent\"); an empty ambience clears it"
+}
+
+func (t *SetLocationAmbienceTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *SetLocationAmbienceTool) Validate(args map[string]interface{}) error {
+	locationID, ok := args["location_id"].(string)
+	if !ok || locationID == "" {
+		return fmt.Errorf("set_location_ambience requires 'location_id' parameter")
+	}
+	if _, ok := args["ambience"].(string); !ok {
+		return fmt.Errorf("set_location_ambience requires 'ambience' parameter")
+	}
+	return nil
+}
+
+func (t *SetLocationAmbienceTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	locationID := args["location_id"].(string)
+	if _, ok := world.Locations[locationID]; !ok {
+		return fmt.Errorf("location %s does not exist", locationID)
+	}
+	_, err := client.SetLocationAmbience(ctx, locationID, args["ambience"].(string))
+	return err
+}
+
+func (t *SetLocationAmbienceTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	locationID := args["location_id"].(string)
+	ambience := strings.TrimSpace(args["ambience"].(string))
+	if ambience == "" {
+		return fmt.Sprintf("The background of %s falls quiet", locationID)
+	}
+	return fmt.Sprintf("The background of %s is now: %s", locationID, ambience)
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type SetNPCGoalTool struct{}
+
+func (t *SetNPCGoalTool) Name() string {
+	return "set_npc_goal"
+}
+
+func (t *SetNPCGoalTool) Usage() string {
+	return "Give an NPC a goal to pursue over the coming turns, or mark one of its goals complete (action is add or complete; goal is a short phrase, exactly as listed when completing)"
+}
+
+func (t *SetNPCGoalTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *SetNPCGoalTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
+func (t *SetNPCGoalTool) Validate(args map[string]interface{}) error {
+	npcID, ok := args["npc_id"].(string)
+	if !ok || npcID == "" {
+		return fmt.Errorf("set_npc_goal requires 'npc_id' parameter")
+	}
+	action, ok := args["action"].(string)
+	if !ok || (action != "add" && action != "complete") {
+		return fmt.Errorf("set_npc_goal requires 'action' parameter of \"add\" or \"complete\"")
+	}
+	goal, ok := args["goal"].(string)
+	if !ok || goal == "" {
+		return fmt.Errorf("set_npc_goal requires 'goal' parameter")
+	}
+	return nil
+}
+
+func (t *SetNPCGoalTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	npcID := args["npc_id"].(string)
+	if actingNPCID != "" && npcID != actingNPCID {
+		return fmt.Errorf("NPCs can only change their own goals")
+	}
+	if _, ok := world.NPCs[npcID]; !ok {
+		return fmt.Errorf("NPC %s does not exist", npcID)
+	}
+	_, err := client.SetNPCGoal(ctx, npcID, args["action"].(string), args["goal"].(string))
+	return err
+}
+
+func (t *SetNPCGoalTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	npcID := args["npc_id"].(string)
+	goal := args["goal"].(string)
+	if args["action"].(string) == "complete" {
+		return fmt.Sprintf("%s completed goal: %s", npcID, goal)
+	}
+	return fmt.Sprintf("%s has a new goal: %s", npcID, goal)
+}
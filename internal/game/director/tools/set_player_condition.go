@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type SetPlayerConditionTool struct{}
+
+func (t *SetPlayerConditionTool) Name() string {
+	return "set_player_condition"
+}
+
+func (t *SetPlayerConditionTool) Usage() string {
+	return "Add or remove a physical condition on the player (action is add or remove; condition is one of " + strings.Join(game.KnownConditions, "|") + ")"
+}
+
+func (t *SetPlayerConditionTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *SetPlayerConditionTool) Validate(args map[string]interface{}) error {
+	action, ok := args["action"].(string)
+	if !ok || (action != "add" && action != "remove") {
+		return fmt.Errorf("set_player_condition requires 'action' parameter of \"add\" or \"remove\"")
+	}
+	condition, ok := args["condition"].(string)
+	if !ok || condition == "" {
+		return fmt.Errorf("set_player_condition requires 'condition' parameter")
+	}
+	if !game.IsKnownCondition(condition) {
+		return fmt.Errorf("unknown condition %q (known: %s)", condition, strings.Join(game.KnownConditions, ", "))
+	}
+	return nil
+}
+
+func (t *SetPlayerConditionTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	if actingNPCID != "" {
+		return fmt.Errorf("NPCs cannot change the player's condition")
+	}
+	action := args["action"].(string)
+	condition := args["condition"].(string)
+	_, err := client.SetPlayerCondition(ctx, action, condition)
+	return err
+}
+
+func (t *SetPlayerConditionTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	condition := args["condition"].(string)
+	if args["action"].(string) == "remove" {
+		return "Player is no longer " + condition
+	}
+	return "Player is now " + condition
+}
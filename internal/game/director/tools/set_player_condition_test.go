@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+func TestSetPlayerConditionValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr bool
+	}{
+		{"add known", map[string]interface{}{"action": "add", "condition": "soaked"}, false},
+		{"remove known", map[string]interface{}{"action": "remove", "condition": "injured"}, false},
+		{"unknown condition", map[string]interface{}{"action": "add", "condition": "poisoned"}, true},
+		{"wrong case", map[string]interface{}{"action": "add", "condition": "Cold"}, true},
+		{"unknown action", map[string]interface{}{"action": "toggle", "condition": "cold"}, true},
+		{"missing action", map[string]interface{}{"condition": "cold"}, true},
+		{"missing condition", map[string]interface{}{"action": "add"}, true},
+		{"empty condition", map[string]interface{}{"action": "add", "condition": ""}, true},
+		{"non-string condition", map[string]interface{}{"action": "add", "condition": 3}, true},
+	}
+	tool := &SetPlayerConditionTool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tool.Validate(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetPlayerConditionRejectsNPCs(t *testing.T) {
+	tool := &SetPlayerConditionTool{}
+	args := map[string]interface{}{"action": "add", "condition": "cold"}
+	if err := tool.Execute(context.Background(), args, nil, game.NewDefaultWorldState(), "elena"); err == nil {
+		t.Error("an NPC changed the player's condition")
+	}
+}
+
+func TestSetPlayerConditionSuccessMessage(t *testing.T) {
+	tool := &SetPlayerConditionTool{}
+	if got := tool.SuccessMessage(map[string]interface{}{"action": "add", "condition": "cold"}, ""); got != "Player is now cold" {
+		t.Errorf("add message = %q", got)
+	}
+	if got := tool.SuccessMessage(map[string]interface{}{"action": "remove", "condition": "cold"}, ""); got != "Player is no longer cold" {
+		t.Errorf("remove message = %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// SpeakToNPCTool is a no-op mutation marking that the player addressed an NPC in the
+// same room. It only checks the NPC is there; the game then has the NPC reply.
+type SpeakToNPCTool struct{}
+
+func (t *SpeakToNPCTool) Name() string {
+	return "speak_to_npc"
+}
+
+func (t *SpeakToNPCTool) Usage() string {
+	return "The player says something to an NPC in the same room, who will reply (words is what the player says, verbatim)"
+}
+
+func (t *SpeakToNPCTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+// LocalParams describes the args of this Go-only tool, since the server doesn't list it.
+func (t *SpeakToNPCTool) LocalParams() []mcp.ToolParam {
+	return []mcp.ToolParam{
+		{Name: "npc_id", Type: "string", Required: true},
+		{Name: "words", Type: "string", Required: true},
+	}
+}
+
+func (t *SpeakToNPCTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
+func (t *SpeakToNPCTool) Validate(args map[string]interface{}) error {
+	for _, key := range []string{"npc_id", "words"} {
+		if value, ok := args[key].(string); !ok || value == "" {
+			return fmt.Errorf("speak_to_npc requires '%s' parameter", key)
+		}
+	}
+	return nil
+}
+
+func (t *SpeakToNPCTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	npcID := args["npc_id"].(string)
+	npc, ok := world.NPCs[npcID]
+	if !ok {
+		return fmt.Errorf("no such person %q", npcID)
+	}
+	if npc.Location != world.Location {
+		return fmt.Errorf("%s is not here", game.MaskUnmetNPCNames(world, npcID))
+	}
+	return nil
+}
+
+func (t *SpeakToNPCTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("Player says to %s: %q", args["npc_id"], args["words"])
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type TakeItemFromContainerTool struct{}
+
+func (t *TakeItemFromContainerTool) Name() string {
+	return "take_item_from_container"
+}
+
+func (t *TakeItemFromContainerTool) Usage() string {
+	return "Take an item out of a container the actor can reach, to player, an NPC or a location"
+}
+
+func (t *TakeItemFromContainerTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *TakeItemFromContainerTool) ItemArgs() []string {
+	return []string{"item", "container"}
+}
+
+func (t *TakeItemFromContainerTool) Validate(args map[string]interface{}) error {
+	for _, key := range []string{"item", "container", "to_location"} {
+		if value, ok := args[key].(string); !ok || value == "" {
+			return fmt.Errorf("take_item_from_container requires '%s' parameter", key)
+		}
+	}
+	return nil
+}
+
+func (t *TakeItemFromContainerTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	container := args["container"].(string)
+	if err := t.check(args, world, actingNPCID); err != nil {
+		return err
+	}
+	if _, err := client.TakeItemFromContainer(ctx, item, container, args["to_location"].(string)); err != nil {
+		return err
+	}
+	return markOpened(ctx, client, world, container, actingNPCID)
+}
+
+func (t *TakeItemFromContainerTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	return fmt.Sprintf("Took %s from %s to %s", args["item"].(string), args["container"].(string), args["to_location"].(string))
+}
+
+// check reports why the actor can't take the item out of the container.
+func (t *TakeItemFromContainerTool) check(args map[string]interface{}, world game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	container := args["container"].(string)
+	if err := checkContainer(world, container, actingNPCID); err != nil {
+		return err
+	}
+	if world.Items[item].Location != container {
+		return fmt.Errorf("%s is not in %s", item, container)
+	}
+	return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world.
+func (t *TakeItemFromContainerTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	if err := t.check(args, *world, actingNPCID); err != nil {
+		return err
+	}
+	moveItem(world, args["item"].(string), args["to_location"].(string))
+	return nil
+}
@@ -14,6 +14,18 @@ func (t *TransferItemTool) Name() string {
 	return "transfer_item"
 }
 
+func (t *TransferItemTool) Usage() string {
+	return "Move an item between locations or entities"
+}
+
+func (t *TransferItemTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *TransferItemTool) ItemArgs() []string {
+	return []string{"item"}
+}
+
 func (t *TransferItemTool) Validate(args map[string]interface{}) error {
 	item, hasItem := args["item"].(string)
 	fromLoc, hasFrom := args["from_location"].(string)
@@ -31,7 +43,7 @@ func (t *TransferItemTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *TransferItemTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *TransferItemTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	item := args["item"].(string)
 	fromLoc := args["from_location"].(string)
 	toLoc := args["to_location"].(string)
@@ -46,4 +58,15 @@ func (t *TransferItemTool) SuccessMessage(args map[string]interface{}, actingNPC
 	toLoc := args["to_location"].(string)
 	
 	return fmt.Sprintf("Transferred %s from %s to %s", item, fromLoc, toLoc)
-}
\ No newline at end of file
+}
+
+// CheckPlan checks that the item is where the transfer takes it from.
+func (t *TransferItemTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+	item := args["item"].(string)
+	fromLoc := args["from_location"].(string)
+	if !holds(*world, fromLoc, item) {
+		return fmt.Errorf("item '%s' is not in %s", item, fromLoc)
+	}
+	moveItem(world, item, args["to_location"].(string))
+	return nil
+}
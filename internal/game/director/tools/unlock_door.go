@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"textadventure/internal/game"
 	"textadventure/internal/mcp"
@@ -14,6 +15,18 @@ func (t *UnlockDoorTool) Name() string {
 	return "unlock_door"
 }
 
+func (t *UnlockDoorTool) Usage() string {
+	return "Unlock a door with a key the player is carrying"
+}
+
+func (t *UnlockDoorTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+func (t *UnlockDoorTool) ItemArgs() []string {
+    return []string{"key_item"}
+}
+
 func (t *UnlockDoorTool) Validate(args map[string]interface{}) error {
     loc, hasLoc := args["location"].(string)
     dir, hasDir := args["direction"].(string)
@@ -31,15 +44,59 @@ func (t *UnlockDoorTool) Validate(args map[string]interface{}) error {
     return nil
 }
 
-func (t *UnlockDoorTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *UnlockDoorTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
     loc := args["location"].(string)
     dir := args["direction"].(string)
     key := args["key_item"].(string)
 
+    // Check the local world first, so the director hears why rather than the server's
+    // generic refusal
+    if err := t.check(args, world); err != nil {
+        return err
+    }
+
     _, err := client.UnlockDoor(ctx, loc, dir, key)
     return err
 }
 
+// check reports why the door can't be unlocked: it doesn't exist, it isn't locked, or
+// the player isn't carrying the key.
+func (t *UnlockDoorTool) check(args map[string]interface{}, world game.WorldState) error {
+    loc := args["location"].(string)
+    dir := args["direction"].(string)
+    key := args["key_item"].(string)
+
+    location, ok := world.Locations[loc]
+    if !ok {
+        return fmt.Errorf("location %s does not exist", loc)
+    }
+    door, ok := location.Doors[dir]
+    if !ok {
+        return fmt.Errorf("there is no door to the %s in %s", dir, loc)
+    }
+    if !door.Locked {
+        return fmt.Errorf("the door to the %s in %s is not locked", dir, loc)
+    }
+    if !slices.Contains(world.Inventory, key) {
+        return fmt.Errorf("player is not carrying %s", key)
+    }
+    return nil
+}
+
+// CheckPlan runs the same checks as Execute against a plan's world and unlocks the door
+// there.
+func (t *UnlockDoorTool) CheckPlan(args map[string]interface{}, world *game.WorldState, actingNPCID string) error {
+    if err := t.check(args, *world); err != nil {
+        return err
+    }
+    loc := args["location"].(string)
+    dir := args["direction"].(string)
+    door := world.Locations[loc].Doors[dir]
+    door.Locked = false
+    world.Locations[loc].Doors[dir] = door
+    return nil
+}
+
 func (t *UnlockDoorTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
     loc := args["location"].(string)
     dir := args["direction"].(string)
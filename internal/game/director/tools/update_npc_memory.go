@@ -14,6 +14,18 @@ func (t *UpdateNPCMemoryTool) Name() string {
 	return "update_npc_memory"
 }
 
+func (t *UpdateNPCMemoryTool) Usage() string {
+	return "Record a thought or action in an NPC's memory"
+}
+
+func (t *UpdateNPCMemoryTool) Actors() ActorScope {
+	return Shared
+}
+
+func (t *UpdateNPCMemoryTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
 func (t *UpdateNPCMemoryTool) Validate(args map[string]interface{}) error {
 	npcID, ok := args["npc_id"].(string)
 	if !ok || npcID == "" {
@@ -22,13 +34,13 @@ func (t *UpdateNPCMemoryTool) Validate(args map[string]interface{}) error {
 	return nil
 }
 
-func (t *UpdateNPCMemoryTool) Execute(ctx context.Context, args map[string]interface{}, client *mcp.WorldStateClient, world game.WorldState, actingNPCID string) error {
+func (t *UpdateNPCMemoryTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
 	npcID := args["npc_id"].(string)
 	
 	thought, _ := args["thought"].(string)
 	action, _ := args["action"].(string)
 	
-	_, err := client.UpdateNPCMemory(ctx, npcID, thought, action)
+	_, err := client.UpdateNPCMemory(ctx, npcID, thought, action, game.TurnIndexFromContext(ctx))
 	return err
 }
 
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+type UpdateRelationshipTool struct{}
+
+func (t *UpdateRelationshipTool) Name() string {
+	return "update_relationship"
+}
+
+func (t *UpdateRelationshipTool) Usage() string {
+	return fmt.Sprintf("Change how an NPC regards the player after the player helps, threatens or lies to them (delta is -%d to %d, e.g. 2 for real help, -3 for a threat; interaction is what the player did, in a short phrase)", game.MaxAffinityChange, game.MaxAffinityChange)
+}
+
+func (t *UpdateRelationshipTool) Actors() ActorScope {
+	return PlayerOnly
+}
+
+func (t *UpdateRelationshipTool) NPCArgs() []string {
+	return []string{"npc_id"}
+}
+
+func (t *UpdateRelationshipTool) Validate(args map[string]interface{}) error {
+	npcID, ok := args["npc_id"].(string)
+	if !ok || npcID == "" {
+		return fmt.Errorf("update_relationship requires 'npc_id' parameter")
+	}
+	delta, ok := intArg(args["delta"])
+	if !ok || delta == 0 || delta < -game.MaxAffinityChange || delta > game.MaxAffinityChange {
+		return fmt.Errorf("update_relationship requires a non-zero whole 'delta' between -%d and %d", game.MaxAffinityChange, game.MaxAffinityChange)
+	}
+	interaction, ok := args["interaction"].(string)
+	if !ok || interaction == "" {
+		return fmt.Errorf("update_relationship requires 'interaction' parameter")
+	}
+	return nil
+}
+
+func (t *UpdateRelationshipTool) Execute(ctx context.Context, args map[string]interface{}, client mcp.WorldClient, world game.WorldState, actingNPCID string) error {
+	npcID := args["npc_id"].(string)
+	if _, ok := world.NPCs[npcID]; !ok {
+		return fmt.Errorf("NPC %s does not exist", npcID)
+	}
+	delta, _ := intArg(args["delta"])
+	_, err := client.UpdateRelationship(ctx, npcID, delta, args["interaction"].(string))
+	return err
+}
+
+func (t *UpdateRelationshipTool) SuccessMessage(args map[string]interface{}, actingNPCID string) string {
+	delta, _ := intArg(args["delta"])
+	return fmt.Sprintf("%s affinity for the player %+d (%s)", args["npc_id"].(string), delta, args["interaction"].(string))
+}
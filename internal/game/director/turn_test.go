@@ -0,0 +1,154 @@
+package director_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+	"textadventure/internal/game/director"
+	"textadventure/internal/game/narration"
+	"textadventure/internal/llm"
+	"textadventure/internal/logging"
+	"textadventure/internal/mcp"
+)
+
+// A player turn runs end to end against canned model responses and an in-memory world:
+// the director plans, the mutations land, the world is refreshed and the narrator
+// streams the result.
+func TestPlayerTurn(t *testing.T) {
+	world := game.NewDefaultWorldState()
+	world.Items = map[string]game.ItemInfo{
+		"brass_key": {Name: "brass key", Location: "study"},
+	}
+	client := mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(world))
+	service := llm.NewMockService().
+		OnOperation("director", `{"mutations": [
+			{"tool": "move_player", "args": {"location": "study"}},
+			{"tool": "add_to_inventory", "args": {"item": "brass_key"}}
+		]}`).
+		OnOperation("events.summarize", `{"events": [
+			{"type": "movement", "content": "player goes north into the study"},
+			{"type": "inventory", "content": "player picks up the brass key"}
+		]}`).
+		OnOperation("narration.generate", "You climb the step into the study and pocket the brass key.")
+
+	tmp := t.TempDir()
+	d := director.NewDirector(service, client, debug.NewLogger(debug.Off, tmp))
+	ctx := llm.WithOperationType(context.Background(), "director.player_input")
+	msg, err := d.ProcessIntent("go north and take the key").WithContext(ctx).WithWorld(world).ExecuteSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msg.Failures) > 0 {
+		t.Fatalf("failures: %q", msg.Failures)
+	}
+	wantSuccesses := []string{"Moved to study", "Added brass_key to inventory"}
+	if !slices.Equal(msg.Successes, wantSuccesses) {
+		t.Errorf("successes = %q, want %q", msg.Successes, wantSuccesses)
+	}
+	if msg.RefreshFailed || len(msg.Invariants) > 0 {
+		t.Errorf("refresh failed = %v, invariants = %v", msg.RefreshFailed, msg.Invariants)
+	}
+	if msg.NewWorld.Location != "study" {
+		t.Errorf("player is in %q, want study", msg.NewWorld.Location)
+	}
+	if !slices.Contains(msg.NewWorld.Inventory, "brass_key") || msg.NewWorld.Items["brass_key"].Location != "player" {
+		t.Errorf("brass_key not carried: inventory %q, location %q", msg.NewWorld.Inventory, msg.NewWorld.Items["brass_key"].Location)
+	}
+	if !slices.Contains(msg.NewWorld.VisitedLocations, "study") {
+		t.Errorf("visited = %q, want study in it", msg.NewWorld.VisitedLocations)
+	}
+	if world.Location != "foyer" || len(world.Inventory) != 0 {
+		t.Error("the turn changed the caller's world")
+	}
+
+	var tools []string
+	for _, call := range client.Calls() {
+		tools = append(tools, call.Tool)
+	}
+	wantTools := []string{"move_player", "add_to_inventory", "get_world_state"}
+	if !slices.Equal(tools, wantTools) {
+		t.Errorf("tool calls = %q, want %q", tools, wantTools)
+	}
+
+	var lines []string
+	for _, event := range msg.WorldEvents {
+		lines = append(lines, event.Content)
+	}
+	if !slices.Contains(lines, "player picks up the brass key") {
+		t.Errorf("world events = %q, want the summarized pickup", lines)
+	}
+
+	logger, err := logging.NewCompletionLogger(filepath.Join(tmp, "completions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+	narrCtx := llm.WithOperationType(context.Background(), "narration.generate")
+	started, ok := narration.StartLLMStream(narrCtx, service, msg.UserInput, msg.NewWorld, nil, logger, false, msg.ActionContext, msg.Successes, msg.WorldEvents)().(narration.StreamStartedMsg)
+	if !ok {
+		t.Fatal("narration stream did not start")
+	}
+	defer started.Span.End()
+	var streamed strings.Builder
+	var complete narration.StreamCompleteMsg
+	for done := false; !done; {
+		switch next := narration.ReadNextChunk(started.Stream, false, &started)().(type) {
+		case narration.StreamChunkMsg:
+			streamed.WriteString(next.Chunk)
+		case narration.StreamCompleteMsg:
+			complete, done = next, true
+		default:
+			t.Fatalf("unexpected stream message %T", next)
+		}
+	}
+	want := "You climb the step into the study and pocket the brass key."
+	if complete.Response != want || streamed.String() != want {
+		t.Errorf("narration = %q (streamed %q), want %q", complete.Response, streamed.String(), want)
+	}
+
+	var operations []string
+	for _, call := range service.Calls() {
+		operations = append(operations, call.Operation)
+	}
+	wantOperations := []string{"director.player_input", "events.summarize", "narration.generate"}
+	if !slices.Equal(operations, wantOperations) {
+		t.Fatalf("model calls = %q, want %q", operations, wantOperations)
+	}
+	narrated := service.Calls()[2]
+	if !strings.Contains(narrated.SystemPrompt, "Moved to study") {
+		t.Errorf("narration prompt is missing the turn's mutations:\n%s", narrated.SystemPrompt)
+	}
+	if !strings.Contains(narrated.UserPrompt, "PLAYER ACTION: go north and take the key") {
+		t.Errorf("narration prompt is missing the player's action:\n%s", narrated.UserPrompt)
+	}
+}
+
+// A mutation the world refuses fails the turn's plan and the director's retry, and the
+// refreshed world is left as it was.
+func TestPlayerTurnRefusedByWorld(t *testing.T) {
+	world := game.NewDefaultWorldState()
+	client := mcp.NewFakeWorldClient(mcp.GameToMCPWorldState(world))
+	client.FailTool("move_player", mcp.ErrWorldTimeout)
+	service := llm.NewMockService().
+		OnOperation("director", `{"mutations": [{"tool": "move_player", "args": {"location": "study"}}]}`).
+		OnOperation("events.summarize", `{"events": []}`)
+
+	d := director.NewDirector(service, client, debug.NewLogger(debug.Off, t.TempDir()))
+	ctx := llm.WithOperationType(context.Background(), "director.player_input")
+	msg, err := d.ProcessIntent("go north").WithContext(ctx).WithWorld(world).ExecuteSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Successes) != 0 || len(msg.Failures) == 0 {
+		t.Errorf("successes = %q, failures = %q; want only failures", msg.Successes, msg.Failures)
+	}
+	if msg.NewWorld.Location != "foyer" {
+		t.Errorf("player is in %q, want foyer", msg.NewWorld.Location)
+	}
+}
@@ -0,0 +1,33 @@
+package director
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"textadventure/internal/debug"
+	"textadventure/internal/game"
+)
+
+// RepairRefreshedWorld checks a world read back after a batch of mutations against the
+// world's invariants and repairs what it can (see game.RepairWorld), before the world
+// reaches any prompt. Each violation is logged as an error and recorded on span.
+func RepairRefreshedWorld(span trace.Span, debugLogger *debug.Logger, before, after game.WorldState) (game.WorldState, []game.InvariantViolation) {
+	repaired, violations := game.RepairWorld(after, before)
+	if len(violations) == 0 {
+		return after, nil
+	}
+	for _, violation := range violations {
+		debugLogger.Errorf("world invariant violated: %s", violation)
+		if span != nil {
+			span.AddEvent("world.invariant_violation", trace.WithAttributes(
+				attribute.String("invariant.rule", violation.Rule),
+				attribute.String("invariant.detail", violation.Detail),
+				attribute.Bool("invariant.repaired", violation.Repaired),
+			))
+		}
+	}
+	if span != nil {
+		span.SetAttributes(attribute.Int("world.invariant_violations", len(violations)))
+	}
+	return repaired, violations
+}
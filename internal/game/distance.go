@@ -0,0 +1,64 @@
+package game
+
+import "sync"
+
+// RoomDistance is the number of exits on the shortest path between two locations, or
+// -1 when there is no path.
+func RoomDistance(locations map[string]LocationInfo, from, to string) int {
+	if from == to {
+		return 0
+	}
+	type step struct {
+		location string
+		distance int
+	}
+	visited := map[string]bool{from: true}
+	queue := []step{{from, 0}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, destination := range locations[current.location].Exits {
+			if destination == to {
+				return current.distance + 1
+			}
+			if !visited[destination] {
+				visited[destination] = true
+				queue = append(queue, step{destination, current.distance + 1})
+			}
+		}
+	}
+	return -1
+}
+
+// DistanceCache memoizes RoomDistance for one world version at a time. Exits rarely
+// change, but any world change starts a fresh cache so unlocked routes are seen.
+type DistanceCache struct {
+	mu      sync.Mutex
+	version uint64
+	entries map[[2]string]int
+}
+
+// NewDistanceCache creates an empty cache.
+func NewDistanceCache() *DistanceCache {
+	return &DistanceCache{entries: make(map[[2]string]int)}
+}
+
+// Distance returns RoomDistance in world, which must be the world at version.
+func (c *DistanceCache) Distance(version uint64, world WorldState, from, to string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version != c.version {
+		if version < c.version {
+			return RoomDistance(world.Locations, from, to)
+		}
+		c.version = version
+		c.entries = make(map[[2]string]int)
+	}
+	key := [2]string{from, to}
+	if distance, ok := c.entries[key]; ok {
+		return distance
+	}
+	distance := RoomDistance(world.Locations, from, to)
+	c.entries[key] = distance
+	return distance
+}
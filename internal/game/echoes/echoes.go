@@ -0,0 +1,124 @@
+// Package echoes keeps embedded narration paragraphs from earlier in the session so
+// the narrator can be reminded of semantically similar past moments.
+package echoes
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// DefaultThreshold is the minimum cosine similarity for a past moment to be included.
+	DefaultThreshold = 0.45
+	// DefaultLimit is how many past moments are included at most.
+	DefaultLimit = 3
+)
+
+// Moment is a past narration paragraph with its embedding.
+type Moment struct {
+	TurnIndex int
+	Text      string
+	Vector    []float64
+}
+
+// Match is a moment and its similarity to the query.
+type Match struct {
+	Moment
+	Score float64
+}
+
+// Store holds the session's embedded narration. It is safe for concurrent use since
+// embedding and retrieval run inside tea.Cmds.
+type Store struct {
+	mu      sync.RWMutex
+	moments []Moment
+}
+
+// NewStore creates an empty store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Add records a narration paragraph.
+func (s *Store) Add(moment Moment) {
+	if len(moment.Vector) == 0 || moment.Text == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.moments = append(s.moments, moment)
+}
+
+// Len returns the number of stored moments.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.moments)
+}
+
+// Similar returns up to limit moments whose similarity to query is at least threshold,
+// best first. Moments from beforeTurn onwards are ignored so the current turn can't echo itself.
+func (s *Store) Similar(query []float64, limit int, threshold float64, beforeTurn int) []Match {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []Match
+	for _, moment := range s.moments {
+		if moment.TurnIndex >= beforeTurn {
+			continue
+		}
+		score := Cosine(query, moment.Vector)
+		if score >= threshold {
+			matches = append(matches, Match{Moment: moment, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 if they can't be compared.
+func Cosine(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+type storeKey struct{}
+
+type storeBinding struct {
+	store     *Store
+	turnIndex int
+}
+
+// WithStore attaches the session's store and the current turn index to ctx so
+// narration can retrieve echoes without threading the store through every call.
+func WithStore(ctx context.Context, store *Store, turnIndex int) context.Context {
+	if store == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, storeKey{}, storeBinding{store: store, turnIndex: turnIndex})
+}
+
+// FromContext returns the store and turn index attached by WithStore.
+func FromContext(ctx context.Context) (*Store, int, bool) {
+	binding, ok := ctx.Value(storeKey{}).(storeBinding)
+	if !ok {
+		return nil, 0, false
+	}
+	return binding.store, binding.turnIndex, true
+}
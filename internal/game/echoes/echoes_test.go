@@ -0,0 +1,99 @@
+package echoes
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{"identical", []float64{3, 4}, []float64{3, 4}, 1},
+		{"scaled", []float64{3, 4}, []float64{6, 8}, 1},
+		{"orthogonal", []float64{1, 0}, []float64{0, 1}, 0},
+		{"opposite", []float64{1, 0}, []float64{-1, 0}, -1},
+		{"partial", []float64{1, 0}, []float64{3, 4}, 0.6},
+		{"different lengths", []float64{1, 0}, []float64{1, 0, 0}, 0},
+		{"empty", nil, nil, 0},
+		{"zero vector", []float64{0, 0}, []float64{1, 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Cosine = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarThreshold(t *testing.T) {
+	store := NewStore()
+	// Against the query {1, 0} these score 1, 0.8, 0.466, 0.447 and 0.
+	store.Add(Moment{TurnIndex: 1, Text: "exact", Vector: []float64{1, 0}})
+	store.Add(Moment{TurnIndex: 2, Text: "close", Vector: []float64{4, 3}})
+	store.Add(Moment{TurnIndex: 3, Text: "just above", Vector: []float64{1, 1.9}})
+	store.Add(Moment{TurnIndex: 4, Text: "just below", Vector: []float64{1, 2}})
+	store.Add(Moment{TurnIndex: 5, Text: "unrelated", Vector: []float64{0, 1}})
+
+	tests := []struct {
+		name       string
+		limit      int
+		threshold  float64
+		beforeTurn int
+		want       []string
+	}{
+		{"default threshold", 10, DefaultThreshold, 10, []string{"exact", "close", "just above"}},
+		{"default limit", DefaultLimit, 0, 10, []string{"exact", "close", "just above"}},
+		{"limit", 2, DefaultThreshold, 10, []string{"exact", "close"}},
+		{"score equal to threshold", 10, 0.8, 10, []string{"exact", "close"}},
+		{"score under threshold", 10, 0.81, 10, []string{"exact"}},
+		{"current turn excluded", 10, DefaultThreshold, 2, []string{"exact"}},
+		{"nothing similar enough", 10, 1.01, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, match := range store.Similar([]float64{1, 0}, tt.limit, tt.threshold, tt.beforeTurn) {
+				got = append(got, match.Text)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Similar = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarScores(t *testing.T) {
+	store := NewStore()
+	store.Add(Moment{TurnIndex: 1, Text: "close", Vector: []float64{4, 3}})
+	matches := store.Similar([]float64{1, 0}, DefaultLimit, DefaultThreshold, 2)
+	if len(matches) != 1 || math.Abs(matches[0].Score-0.8) > 1e-9 || matches[0].TurnIndex != 1 {
+		t.Errorf("matches = %+v", matches)
+	}
+}
+
+func TestAddSkipsEmptyMoments(t *testing.T) {
+	store := NewStore()
+	store.Add(Moment{TurnIndex: 1, Text: "no vector"})
+	store.Add(Moment{TurnIndex: 1, Vector: []float64{1, 0}})
+	if store.Len() != 0 {
+		t.Errorf("stored %d empty moments", store.Len())
+	}
+}
+
+func TestStoreContext(t *testing.T) {
+	if _, _, ok := FromContext(context.Background()); ok {
+		t.Error("a bare context has a store")
+	}
+	if ctx := WithStore(context.Background(), nil, 3); ctx != context.Background() {
+		t.Error("a nil store was attached")
+	}
+	store := NewStore()
+	got, turnIndex, ok := FromContext(WithStore(context.Background(), store, 3))
+	if !ok || got != store || turnIndex != 3 {
+		t.Errorf("read back %p, turn %d, %v", got, turnIndex, ok)
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// KnownEmotions lists the emotions an NPC can feel. Each is an intensity from 0 to 1.
+var KnownEmotions = []string{"fear", "trust", "curiosity"}
+
+// emotionDecay is the share of an emotion's intensity lost each turn. Fear and
+// curiosity fade on their own; trust is built and lost only by what happens.
+var emotionDecay = map[string]float64{
+	"fear":      0.25,
+	"curiosity": 0.2,
+}
+
+const (
+	// emotionFloor is the intensity below which an emotion is dropped as gone.
+	emotionFloor = 0.05
+	// dominantEmotion is the intensity an emotion needs to show in prompts.
+	dominantEmotion = 0.2
+)
+
+// IsKnownEmotion reports whether name is one of KnownEmotions.
+func IsKnownEmotion(name string) bool {
+	for _, known := range KnownEmotions {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AdjustEmotion returns the intensity of an emotion after changing it by delta,
+// kept between 0 and 1.
+func AdjustEmotion(emotions map[string]float64, emotion string, delta float64) float64 {
+	return math.Round(math.Max(0, math.Min(1, emotions[emotion]+delta))*100) / 100
+}
+
+// DecayEmotions returns an NPC's emotions one turn on, with those that fade reduced
+// and any that fade away dropped. It reports whether anything changed.
+func DecayEmotions(emotions map[string]float64) (map[string]float64, bool) {
+	if len(emotions) == 0 {
+		return emotions, false
+	}
+	decayed := make(map[string]float64, len(emotions))
+	changed := false
+	for emotion, intensity := range emotions {
+		if rate, ok := emotionDecay[emotion]; ok {
+			intensity = math.Round(intensity*(1-rate)*100) / 100
+			changed = true
+		}
+		if intensity < emotionFloor {
+			changed = true
+			continue
+		}
+		decayed[emotion] = intensity
+	}
+	return decayed, changed
+}
+
+// FormatEmotions renders an NPC's dominant emotions for a prompt, strongest first,
+// e.g. "fear 0.7, curiosity 0.3". It returns an empty string when none are strong
+// enough to show.
+func FormatEmotions(emotions map[string]float64) string {
+	var names []string
+	for emotion, intensity := range emotions {
+		if intensity >= dominantEmotion {
+			names = append(names, emotion)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if emotions[names[i]] != emotions[names[j]] {
+			return emotions[names[i]] > emotions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %.1f", name, emotions[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/llm"
+)
+
+// LLMTagger tags turns with a small model call.
+type LLMTagger struct {
+	service llm.Completer
+}
+
+// NewLLMTagger creates a tagger backed by service.
+func NewLLMTagger(service llm.Completer) *LLMTagger {
+	return &LLMTagger{service: service}
+}
+
+var tagPrompt = `You label turns of a text adventure for analytics.
+Pick one or more tags that describe what the turn was about: ` + strings.Join(AllTags, ", ") + `.
+"quiet" means little or nothing happened. Respond with JSON: {"tags": ["<tag>", ...]}`
+
+// TagTurn implements TurnTagger.
+func (t *LLMTagger) TagTurn(ctx context.Context, record game.TurnRecord) ([]string, error) {
+	ctx = llm.WithOperationType(ctx, "turn.classify")
+	var user strings.Builder
+	fmt.Fprintf(&user, "PLAYER: %s\n", record.PlayerInput)
+	for _, line := range record.NPCActions {
+		fmt.Fprintf(&user, "NPC: %s\n", line)
+	}
+	for _, line := range record.WorldEvents {
+		fmt.Fprintf(&user, "EVENT: %s\n", line)
+	}
+	for _, line := range record.Mutations {
+		fmt.Fprintf(&user, "CHANGE: %s\n", line)
+	}
+	fmt.Fprintf(&user, "NARRATION: %s\n", record.Narration)
+
+	content, err := t.service.CompleteJSON(ctx, llm.JSONCompletionRequest{
+		SystemPrompt:    tagPrompt,
+		UserPrompt:      user.String(),
+		MaxTokens:       200,
+		Model:           "gpt-5-mini",
+		ReasoningEffort: "minimal",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("turn tagging failed: %w", err)
+	}
+	var response struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse turn tags: %w", err)
+	}
+	return response.Tags, nil
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/llm"
+)
+
+// ambiguousTurn is a turn the rules can't tag.
+var ambiguousTurn = game.TurnRecord{
+	PlayerInput: "xyzzy",
+	Mutations:   []string{"Player condition cold added"},
+	Narration:   "A chill settles over you.",
+}
+
+func TestClassifierFallsBackToTheTagger(t *testing.T) {
+	service := llm.NewMockService().OnOperation("turn.classify", `{"tags": ["Exploration", "romance"]}`)
+	classifier := NewClassifier(NewLLMTagger(service), 1)
+
+	tags, useFallback := classifier.Rules(ambiguousTurn)
+	if tags != nil || !useFallback {
+		t.Fatalf("Rules = %q, %v; want the fallback", tags, useFallback)
+	}
+	tags, err := classifier.Fallback(context.Background(), ambiguousTurn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tags, []string{TagExploration}) {
+		t.Errorf("fallback tags = %q", tags)
+	}
+	calls := service.Calls()
+	if len(calls) != 1 || !strings.Contains(calls[0].UserPrompt, "PLAYER: xyzzy\n") || !strings.Contains(calls[0].UserPrompt, "CHANGE: Player condition cold added\n") {
+		t.Errorf("calls = %+v", calls)
+	}
+
+	// The budget is spent; later ambiguous turns stay untagged without a call.
+	if _, useFallback := classifier.Rules(ambiguousTurn); useFallback {
+		t.Error("the fallback ran past its budget")
+	}
+}
+
+func TestClassifierSkipsTheFallback(t *testing.T) {
+	service := llm.NewMockService()
+	tests := []struct {
+		name       string
+		classifier *Classifier
+		record     game.TurnRecord
+	}{
+		{"turn the rules can tag", NewClassifier(NewLLMTagger(service), 5), game.TurnRecord{PlayerInput: "look"}},
+		{"no fallback", NewClassifier(nil, 5), ambiguousTurn},
+		{"no budget", NewClassifier(NewLLMTagger(service), 0), ambiguousTurn},
+		{"nil classifier", nil, ambiguousTurn},
+	}
+	for _, tt := range tests {
+		if _, useFallback := tt.classifier.Rules(tt.record); useFallback {
+			t.Errorf("%s: the fallback would run", tt.name)
+		}
+	}
+}
+
+func TestClassifierFallbackErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		service *llm.MockService
+		want    string
+	}{
+		{"call fails", llm.NewMockService().FailOperation("turn.classify", errors.New("rate limited")), "turn tagging failed: "},
+		{"bad JSON", llm.NewMockService().OnOperation("turn.classify", "exploration"), "failed to parse turn tags: "},
+	}
+	for _, tt := range tests {
+		tags, err := NewClassifier(NewLLMTagger(tt.service), 1).Fallback(context.Background(), ambiguousTurn)
+		if tags != nil || err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Fallback = %q, %v; want %q", tt.name, tags, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package engine holds turn-level logic that runs on a completed turn, independent of
+// the UI that drives it.
+package engine
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"textadventure/internal/game"
+)
+
+// Turn tags, used for analytics and the timeline.
+const (
+	TagExploration = "exploration"
+	TagDialogue    = "dialogue"
+	TagItem        = "item"
+	TagMovement    = "movement"
+	TagConflict    = "conflict"
+	TagQuiet       = "quiet"
+)
+
+// AllTags lists every tag in display order.
+var AllTags = []string{TagExploration, TagDialogue, TagItem, TagMovement, TagConflict, TagQuiet}
+
+// tagRule matches a tag against one kind of turn text.
+type tagRule struct {
+	tag     string
+	pattern *regexp.Regexp
+}
+
+// Mutation summaries are generated by the director tools, so their wording is stable.
+var mutationRules = []tagRule{
+	{TagMovement, regexp.MustCompile(`^(Moved to|NPC \S+ moved to) `)},
+	{TagItem, regexp.MustCompile(`^(Added .* to inventory|Removed .* from inventory|Transferred |Player examines |Unlocked )`)},
+	{TagDialogue, regexp.MustCompile(`^Player has now met `)},
+	{TagConflict, regexp.MustCompile(`^Player is now injured`)},
+}
+
+// Input, NPC actions and world events are free text, matched on verbs.
+var textRules = []tagRule{
+	{TagDialogue, regexp.MustCompile(`(?i)\b(say|says|said|ask|asks|asked|tell|tells|told|talk|talks|shout|shouts|shouted|whisper|whispers|whispered|call out|calls out|reply|replies|greet|greets)\b|"`)},
+	{TagConflict, regexp.MustCompile(`(?i)\b(attack|attacks|hit|hits|punch|punches|kick|kicks|fight|fights|stab|stabs|struggle|struggles|threaten|threatens|shove|shoves|grab .* by)\b`)},
+	{TagMovement, regexp.MustCompile(`(?i)\b(go|goes|went|walk|walks|walked|head|heads|headed|enter|enters|entered|leave|leaves|left the|climb|climbs|run to|runs to|moved? (to|from|into))\b`)},
+	{TagItem, regexp.MustCompile(`(?i)\b(pick up|picks up|picked up|take|takes|took|drop|drops|dropped|give|gives|gave|unlock|unlocks|unlocked|put|puts)\b`)},
+	{TagExploration, regexp.MustCompile(`(?i)\b(look|looks|looked|examine|examines|examined|search|searches|searched|inspect|inspects|inspected|explore|explores|explored|study|studies|read|reads|peer|peers|listen|listens)\b`)},
+}
+
+// quietInput matches inputs that deliberately let time pass.
+var quietInput = regexp.MustCompile(`(?i)^\s*(wait|rest|sleep|sit|pause|do nothing|z)\b`)
+
+// ClassifyTurn tags a turn with deterministic rules over its mutations, NPC actions,
+// world events and input. A turn where nothing changed and nothing was said is quiet.
+// ambiguous is true when something happened but no rule recognised it; those turns
+// are worth an LLM fallback.
+func ClassifyTurn(record game.TurnRecord) (tags []string, ambiguous bool) {
+	found := make(map[string]bool)
+	for _, mutation := range record.Mutations {
+		for _, rule := range mutationRules {
+			if rule.pattern.MatchString(mutation) {
+				found[rule.tag] = true
+			}
+		}
+	}
+	texts := append([]string{record.PlayerInput}, record.NPCActions...)
+	texts = append(texts, record.WorldEvents...)
+	for _, text := range texts {
+		for _, rule := range textRules {
+			if rule.pattern.MatchString(text) {
+				found[rule.tag] = true
+			}
+		}
+	}
+
+	eventful := len(record.Mutations) > 0 || len(record.NPCActions) > 0 || len(record.WorldEvents) > 0
+	if len(found) == 0 {
+		if !eventful || quietInput.MatchString(record.PlayerInput) {
+			return []string{TagQuiet}, false
+		}
+		return nil, true
+	}
+	return orderTags(found), false
+}
+
+// NormalizeTags lowercases tags, drops unknown ones and duplicates, and returns them
+// in display order.
+func NormalizeTags(tags []string) []string {
+	found := make(map[string]bool)
+	for _, tag := range tags {
+		found[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	return orderTags(found)
+}
+
+func orderTags(found map[string]bool) []string {
+	var tags []string
+	for _, tag := range AllTags {
+		if found[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// TurnTagger tags turns the rules can't, typically with an LLM call.
+type TurnTagger interface {
+	TagTurn(ctx context.Context, record game.TurnRecord) ([]string, error)
+}
+
+// Classifier runs the rules and falls back to a TurnTagger for ambiguous turns, at most
+// budget times per session so tagging never becomes a per-turn LLM cost.
+type Classifier struct {
+	fallback TurnTagger
+	budget   int
+
+	mu   sync.Mutex
+	used int
+}
+
+// NewClassifier creates a classifier. A nil fallback or zero budget uses rules only.
+func NewClassifier(fallback TurnTagger, budget int) *Classifier {
+	return &Classifier{fallback: fallback, budget: budget}
+}
+
+// Rules classifies with the rules alone and reports whether the fallback should run.
+// A true result reserves one unit of the fallback budget.
+func (c *Classifier) Rules(record game.TurnRecord) (tags []string, useFallback bool) {
+	tags, ambiguous := ClassifyTurn(record)
+	if !ambiguous || c == nil || c.fallback == nil {
+		return tags, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.used >= c.budget {
+		return tags, false
+	}
+	c.used++
+	return tags, true
+}
+
+// Fallback asks the tagger. Unknown tags are dropped; errors or empty answers leave the
+// turn untagged rather than guessing.
+func (c *Classifier) Fallback(ctx context.Context, record game.TurnRecord) ([]string, error) {
+	tags, err := c.fallback.TagTurn(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	return NormalizeTags(tags), nil
+}
+
+// TagCounts tallies tags across turns, for session stats.
+type TagCounts map[string]int
+
+// Add counts one turn's tags; untagged turns are not counted.
+func (c TagCounts) Add(tags []string) {
+	for _, tag := range tags {
+		c[tag]++
+	}
+}
+
+// Sorted returns tags by count, most frequent first, ties in display order.
+func (c TagCounts) Sorted() []string {
+	tags := make([]string, 0, len(c))
+	for _, tag := range AllTags {
+		if c[tag] > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return c[tags[i]] > c[tags[j]] })
+	return tags
+}
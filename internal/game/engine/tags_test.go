@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+func TestClassifyTurn(t *testing.T) {
+	tests := []struct {
+		name      string
+		record    game.TurnRecord
+		want      []string
+		ambiguous bool
+	}{
+		{"movement mutation", game.TurnRecord{PlayerInput: "north", Mutations: []string{"Moved to study"}}, []string{TagMovement}, false},
+		{"NPC movement mutation", game.TurnRecord{Mutations: []string{"NPC elena moved to kitchen"}}, []string{TagMovement}, false},
+		{"item mutation", game.TurnRecord{PlayerInput: "grab it", Mutations: []string{"Added lamp to inventory"}}, []string{TagItem}, false},
+		{"unlock mutation", game.TurnRecord{Mutations: []string{"Unlocked the iron door"}}, []string{TagItem}, false},
+		{"meeting mutation", game.TurnRecord{Mutations: []string{"Player has now met elena"}}, []string{TagDialogue}, false},
+		{"injury mutation", game.TurnRecord{Mutations: []string{"Player is now injured"}}, []string{TagConflict}, false},
+		{"speech in input", game.TurnRecord{PlayerInput: "ask Elena about the key"}, []string{TagDialogue}, false},
+		{"quoted NPC speech", game.TurnRecord{NPCActions: []string{`elena: "Who are you?"`}}, []string{TagDialogue}, false},
+		{"examine input", game.TurnRecord{PlayerInput: "examine the desk"}, []string{TagExploration}, false},
+		{"conflict in a world event", game.TurnRecord{WorldEvents: []string{"Marcus shoves Elena aside"}}, []string{TagConflict}, false},
+		{"tags in display order", game.TurnRecord{PlayerInput: "take the lamp and look around", Mutations: []string{"Moved to study"}}, []string{TagExploration, TagItem, TagMovement}, false},
+		{"matching is case-insensitive", game.TurnRecord{PlayerInput: "LOOK"}, []string{TagExploration}, false},
+		{"nothing happened", game.TurnRecord{PlayerInput: "hmm"}, []string{TagQuiet}, false},
+		{"waiting while things happen", game.TurnRecord{PlayerInput: "wait", NPCActions: []string{"elena: hums quietly"}}, []string{TagQuiet}, false},
+		{"unrecognised events", game.TurnRecord{PlayerInput: "xyzzy", Mutations: []string{"Player condition cold added"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, ambiguous := ClassifyTurn(tt.record)
+			if !slices.Equal(tags, tt.want) || ambiguous != tt.ambiguous {
+				t.Errorf("ClassifyTurn = %q, %v; want %q, %v", tags, ambiguous, tt.want, tt.ambiguous)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{" Movement", "dialogue", "romance", "movement", ""})
+	if want := []string{TagDialogue, TagMovement}; !slices.Equal(got, want) {
+		t.Errorf("NormalizeTags = %q, want %q", got, want)
+	}
+}
+
+func TestTagCountsSorted(t *testing.T) {
+	counts := TagCounts{}
+	counts.Add([]string{TagMovement, TagItem})
+	counts.Add([]string{TagItem})
+	counts.Add([]string{TagDialogue})
+	counts.Add(nil)
+	want := []string{TagItem, TagDialogue, TagMovement}
+	if got := counts.Sorted(); !slices.Equal(got, want) {
+		t.Errorf("Sorted = %q, want %q", got, want)
+	}
+}
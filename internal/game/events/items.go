@@ -0,0 +1,112 @@
+package events
+
+import (
+	"slices"
+	"sort"
+
+	"textadventure/internal/game"
+)
+
+// ItemMove is an item changing hands: From and To are its holders before and after (a
+// location, a container, an NPC or "player"), and FromRoom and ToRoom the rooms those
+// holders were in.
+type ItemMove struct {
+	TurnIndex int
+	Item      string
+	Actor     string
+	From      string
+	FromRoom  string
+	To        string
+	ToRoom    string
+}
+
+// ItemMoves returns the items whose holder differs between two worlds, sorted by item,
+// as moves made by actor.
+func ItemMoves(actor string, before, after game.WorldState) []ItemMove {
+	var moves []ItemMove
+	for id, item := range after.Items {
+		from := before.Items[id].Location
+		if from == item.Location {
+			continue
+		}
+		moves = append(moves, ItemMove{
+			Item:     id,
+			Actor:    actor,
+			From:     from,
+			FromRoom: holderRoom(before, from),
+			To:       item.Location,
+			ToRoom:   holderRoom(after, item.Location),
+		})
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].Item < moves[j].Item })
+	return moves
+}
+
+// holderRoom returns the room a holder is in: a location is its own room, a container is
+// in whatever room holds it, and NPCs and the player are where they stand.
+func holderRoom(world game.WorldState, holder string) string {
+	// Bounded by the number of items, in case containers hold each other
+	for range len(world.Items) + 1 {
+		if holder == "player" {
+			return world.Location
+		}
+		if _, ok := world.Locations[holder]; ok {
+			return holder
+		}
+		if npc, ok := world.NPCs[holder]; ok {
+			return npc.Location
+		}
+		item, ok := world.Items[holder]
+		if !ok {
+			return ""
+		}
+		holder = item.Location
+	}
+	return ""
+}
+
+// Sighting is what the player can know of an item's whereabouts: that they carry it, or
+// the holder and room they last saw it in, as of TurnIndex.
+type Sighting struct {
+	Carried   bool
+	Holder    string
+	Room      string
+	TurnIndex int
+}
+
+// LastSeen answers "where did I leave it?" for an item from what the player can know:
+// whether they carry it, and otherwise the newest place in its movement log (oldest
+// first) that lies in a room they have visited, falling back to where it started. Items
+// in a container the player hasn't looked inside don't count as seen. It reports false
+// when the player can't know where the item is.
+func LastSeen(world game.WorldState, itemID string, moves []ItemMove) (Sighting, bool) {
+	if slices.Contains(world.Inventory, itemID) || world.Items[itemID].Location == "player" {
+		return Sighting{Carried: true}, true
+	}
+	seen := func(holder, room string) bool {
+		if room == "" || !slices.Contains(world.VisitedLocations, room) {
+			return false
+		}
+		container, ok := world.Items[holder]
+		return !ok || !container.IsContainer || game.ContainerOpened(container)
+	}
+	for i := len(moves) - 1; i >= 0; i-- {
+		if move := moves[i]; seen(move.To, move.ToRoom) {
+			return Sighting{Holder: move.To, Room: move.ToRoom, TurnIndex: move.TurnIndex}, true
+		}
+	}
+	if len(moves) > 0 {
+		if first := moves[0]; seen(first.From, first.FromRoom) {
+			return Sighting{Holder: first.From, Room: first.FromRoom}, true
+		}
+		return Sighting{}, false
+	}
+	item, ok := world.Items[itemID]
+	if !ok {
+		return Sighting{}, false
+	}
+	if room := holderRoom(world, item.Location); seen(item.Location, room) {
+		return Sighting{Holder: item.Location, Room: room}, true
+	}
+	return Sighting{}, false
+}
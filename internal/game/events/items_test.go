@@ -0,0 +1,202 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+
+	"textadventure/internal/game"
+)
+
+// itemWorld has the player in the foyer, having visited the study, with a lamp. The
+// study holds an unopened chest with a coin in it and elena carrying a letter; the
+// cellar has never been visited.
+func itemWorld() game.WorldState {
+	return game.WorldState{
+		Location:         "foyer",
+		Inventory:        []string{"lamp"},
+		VisitedLocations: []string{"foyer", "study"},
+		Locations: map[string]game.LocationInfo{
+			"foyer":  {Name: "foyer"},
+			"study":  {Name: "study"},
+			"cellar": {Name: "cellar"},
+		},
+		NPCs: map[string]game.NPCInfo{
+			"elena": {Location: "study", Inventory: []string{"letter"}},
+		},
+		Items: map[string]game.ItemInfo{
+			"lamp":   {Name: "lamp", Location: "player"},
+			"letter": {Name: "letter", Location: "elena"},
+			"chest":  {Name: "chest", Location: "study", IsContainer: true, Contains: []string{"coin"}},
+			"coin":   {Name: "coin", Location: "chest"},
+			"wine":   {Name: "wine", Location: "cellar"},
+		},
+	}
+}
+
+func TestItemMoves(t *testing.T) {
+	before := itemWorld()
+	after := before.Clone()
+	after.Location = "study"
+	moveTo := func(id, holder string) {
+		item := after.Items[id]
+		item.Location = holder
+		after.Items[id] = item
+	}
+	moveTo("lamp", "chest")
+	moveTo("coin", "player")
+	moveTo("letter", "cellar")
+	after.Items["candle"] = game.ItemInfo{Name: "candle", Location: "study"}
+
+	want := []ItemMove{
+		{Item: "candle", Actor: "player", From: "", FromRoom: "", To: "study", ToRoom: "study"},
+		{Item: "coin", Actor: "player", From: "chest", FromRoom: "study", To: "player", ToRoom: "study"},
+		{Item: "lamp", Actor: "player", From: "player", FromRoom: "foyer", To: "chest", ToRoom: "study"},
+		{Item: "letter", Actor: "player", From: "elena", FromRoom: "study", To: "cellar", ToRoom: "cellar"},
+	}
+	got := ItemMoves("player", before, after)
+	if len(got) != len(want) {
+		t.Fatalf("ItemMoves = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("move %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if moves := ItemMoves("player", before, before.Clone()); len(moves) != 0 {
+		t.Errorf("an unchanged world has moves %+v", moves)
+	}
+}
+
+func TestHolderRoom(t *testing.T) {
+	world := itemWorld()
+	world.Items["box"] = game.ItemInfo{Name: "box", Location: "chest", IsContainer: true}
+	world.Items["ring"] = game.ItemInfo{Name: "ring", Location: "box"}
+	// Containers holding each other have no room, rather than looping forever
+	world.Items["left"] = game.ItemInfo{Location: "right", IsContainer: true}
+	world.Items["right"] = game.ItemInfo{Location: "left", IsContainer: true}
+
+	tests := map[string]string{
+		"player":  "foyer",
+		"study":   "study",
+		"elena":   "study",
+		"chest":   "study",
+		"box":     "study",
+		"ring":    "study",
+		"left":    "",
+		"nowhere": "",
+		"":        "",
+	}
+	for holder, want := range tests {
+		if got := holderRoom(world, holder); got != want {
+			t.Errorf("holderRoom(%q) = %q, want %q", holder, got, want)
+		}
+	}
+}
+
+func TestLastSeen(t *testing.T) {
+	opened := itemWorld()
+	chest := opened.Items["chest"]
+	chest.Facts = []string{game.ContainerOpenedFact}
+	opened.Items["chest"] = chest
+
+	tests := []struct {
+		name   string
+		world  game.WorldState
+		item   string
+		moves  []ItemMove
+		want   Sighting
+		wantOK bool
+	}{
+		{"carried", itemWorld(), "lamp", nil, Sighting{Carried: true}, true},
+		{"carried by item location alone", func() game.WorldState {
+			w := itemWorld()
+			w.Items["wine"] = game.ItemInfo{Name: "wine", Location: "player"}
+			return w
+		}(), "wine", nil, Sighting{Carried: true}, true},
+		{"never moved, held by an NPC in a visited room", itemWorld(), "letter", nil, Sighting{Holder: "elena", Room: "study"}, true},
+		{"never moved, in an unvisited room", itemWorld(), "wine", nil, Sighting{}, false},
+		{"never moved, in an unopened container", itemWorld(), "coin", nil, Sighting{}, false},
+		{"never moved, in an opened container", opened, "coin", nil, Sighting{Holder: "chest", Room: "study"}, true},
+		{"unknown item", itemWorld(), "sword", nil, Sighting{}, false},
+		{"newest move into a visited room", itemWorld(), "letter", []ItemMove{
+			{TurnIndex: 2, Item: "letter", From: "elena", FromRoom: "study", To: "foyer", ToRoom: "foyer"},
+			{TurnIndex: 5, Item: "letter", From: "foyer", FromRoom: "foyer", To: "study", ToRoom: "study"},
+		}, Sighting{Holder: "study", Room: "study", TurnIndex: 5}, true},
+		{"moves into unvisited rooms are skipped", itemWorld(), "letter", []ItemMove{
+			{TurnIndex: 2, Item: "letter", From: "elena", FromRoom: "study", To: "foyer", ToRoom: "foyer"},
+			{TurnIndex: 5, Item: "letter", From: "foyer", FromRoom: "foyer", To: "cellar", ToRoom: "cellar"},
+		}, Sighting{Holder: "foyer", Room: "foyer", TurnIndex: 2}, true},
+		{"only moves out of sight falls back to where it started", itemWorld(), "letter", []ItemMove{
+			{TurnIndex: 3, Item: "letter", From: "elena", FromRoom: "study", To: "cellar", ToRoom: "cellar"},
+		}, Sighting{Holder: "elena", Room: "study"}, true},
+		{"started and went out of sight", itemWorld(), "wine", []ItemMove{
+			{TurnIndex: 3, Item: "wine", From: "cellar", FromRoom: "cellar", To: "elena", ToRoom: "cellar"},
+		}, Sighting{}, false},
+		{"moved into an unopened container is not seen there", func() game.WorldState {
+			w := itemWorld()
+			w.Inventory = nil
+			w.Items["lamp"] = game.ItemInfo{Name: "lamp", Location: "chest"}
+			return w
+		}(), "lamp", []ItemMove{
+			{TurnIndex: 4, Item: "lamp", From: "player", FromRoom: "study", To: "chest", ToRoom: "study"},
+		}, Sighting{Holder: "player", Room: "study"}, true},
+	}
+	for _, tt := range tests {
+		got, ok := LastSeen(tt.world, tt.item, tt.moves)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: LastSeen = %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestStoreItemMoves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	store, err := OpenStore(path, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.AppendItemMoves(1, nil); err != nil {
+		t.Fatal(err)
+	}
+	first := []ItemMove{
+		{Item: "coin", Actor: "player", From: "chest", FromRoom: "study", To: "player", ToRoom: "study"},
+		{Item: "lamp", Actor: "player", From: "player", FromRoom: "study", To: "study", ToRoom: "study"},
+	}
+	second := []ItemMove{
+		{Item: "coin", Actor: "elena", From: "player", FromRoom: "study", To: "elena", ToRoom: "study"},
+	}
+	if err := store.AppendItemMoves(3, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AppendItemMoves(4, second); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := OpenStore(path, "session-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := other.AppendItemMoves(9, []ItemMove{{Item: "coin", Actor: "player", From: "cellar", To: "player"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	moves, err := store.ItemMoves("coin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ItemMove{first[0], second[0]}
+	want[0].TurnIndex, want[1].TurnIndex = 3, 4
+	if len(moves) != len(want) {
+		t.Fatalf("coin moves = %+v, want %+v", moves, want)
+	}
+	for i := range want {
+		if moves[i] != want[i] {
+			t.Errorf("move %d = %+v, want %+v", i, moves[i], want[i])
+		}
+	}
+	if moves, err := store.ItemMoves("wine"); err != nil || len(moves) != 0 {
+		t.Errorf("wine moves = %+v, %v", moves, err)
+	}
+}
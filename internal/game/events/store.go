@@ -0,0 +1,209 @@
+package events
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"textadventure/internal/redact"
+)
+
+const worldEventsSchema = `
+CREATE TABLE IF NOT EXISTS world_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	turn_id INTEGER NOT NULL,
+	actor TEXT NOT NULL,
+	type TEXT NOT NULL,
+	location TEXT NOT NULL,
+	content TEXT NOT NULL,
+	timestamp DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_world_events_turn ON world_events(session_id, turn_id);
+CREATE INDEX IF NOT EXISTS idx_world_events_location ON world_events(location);
+
+CREATE TABLE IF NOT EXISTS item_moves (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	turn_id INTEGER NOT NULL,
+	item TEXT NOT NULL,
+	actor TEXT NOT NULL,
+	from_holder TEXT NOT NULL,
+	from_room TEXT NOT NULL,
+	to_holder TEXT NOT NULL,
+	to_room TEXT NOT NULL,
+	timestamp DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_moves_item ON item_moves(session_id, item);
+`
+
+// Stored is an event read back from the log, with the session and turn it happened on.
+type Stored struct {
+	SessionID string
+	TurnIndex int
+	WorldEvent
+}
+
+// Store is the persistent log of one session's world events, kept in the completions
+// database next to the completion and turn logs. It is safe for concurrent use.
+type Store struct {
+	db        *sql.DB
+	sessionID string
+}
+
+// OpenStore opens (creating if needed) the world event log at path for a session.
+func OpenStore(path, sessionID string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+	db := sql.OpenDB(redact.NewConnector(&sqlite3.SQLiteDriver{}, path))
+	if _, err := db.Exec(worldEventsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create world event tables: %w", err)
+	}
+	return &Store{db: db, sessionID: sessionID}, nil
+}
+
+// Append records events as having happened on turnIndex.
+func (s *Store) Append(turnIndex int, evs []WorldEvent) error {
+	if len(evs) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin world event transaction: %w", err)
+	}
+	for _, e := range evs {
+		timestamp := e.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		_, err := tx.Exec(`
+			INSERT INTO world_events (session_id, turn_id, actor, type, location, content, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, s.sessionID, turnIndex, e.Actor, string(e.Type), e.Location, e.Content, timestamp)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to write world event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// AppendItemMoves records item moves as having happened on turnIndex.
+func (s *Store) AppendItemMoves(turnIndex int, moves []ItemMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin item move transaction: %w", err)
+	}
+	for _, move := range moves {
+		_, err := tx.Exec(`
+			INSERT INTO item_moves (session_id, turn_id, item, actor, from_holder, from_room, to_holder, to_room, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, s.sessionID, turnIndex, move.Item, move.Actor, move.From, move.FromRoom, move.To, move.ToRoom, time.Now())
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to write item move: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ItemMoves returns the session's moves of an item, oldest first.
+func (s *Store) ItemMoves(itemID string) ([]ItemMove, error) {
+	rows, err := s.db.Query(`
+		SELECT turn_id, item, actor, from_holder, from_room, to_holder, to_room
+		FROM item_moves
+		WHERE session_id = ? AND item = ?
+		ORDER BY id
+	`, s.sessionID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query item moves: %w", err)
+	}
+	defer rows.Close()
+
+	var moves []ItemMove
+	for rows.Next() {
+		var move ItemMove
+		if err := rows.Scan(&move.TurnIndex, &move.Item, &move.Actor, &move.From, &move.FromRoom, &move.To, &move.ToRoom); err != nil {
+			return nil, fmt.Errorf("failed to read item move: %w", err)
+		}
+		moves = append(moves, move)
+	}
+	return moves, rows.Err()
+}
+
+// EventsSince returns the session's events from turnIndex on, oldest first.
+func (s *Store) EventsSince(turnIndex int) ([]Stored, error) {
+	return s.query(`
+		SELECT id, session_id, turn_id, actor, type, location, content, timestamp
+		FROM world_events
+		WHERE session_id = ? AND turn_id >= ?
+		ORDER BY id
+	`, s.sessionID, turnIndex)
+}
+
+// Last returns the session's n most recent events, oldest first.
+func (s *Store) Last(n int) ([]Stored, error) {
+	stored, err := s.query(`
+		SELECT id, session_id, turn_id, actor, type, location, content, timestamp
+		FROM world_events
+		WHERE session_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`, s.sessionID, n)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(stored)-1; i < j; i, j = i+1, j-1 {
+		stored[i], stored[j] = stored[j], stored[i]
+	}
+	return stored, nil
+}
+
+// SessionEvents returns the events of every session whose ID starts with
+// sessionPrefix, by session and then in the order they were logged.
+func (s *Store) SessionEvents(sessionPrefix string) ([]Stored, error) {
+	return s.query(`
+		SELECT id, session_id, turn_id, actor, type, location, content, timestamp
+		FROM world_events
+		WHERE session_id LIKE ? || '%'
+		ORDER BY session_id, id
+	`, sessionPrefix)
+}
+
+func (s *Store) query(query string, args ...interface{}) ([]Stored, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query world events: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Stored
+	for rows.Next() {
+		var stored Stored
+		var id int64
+		var eventType string
+		if err := rows.Scan(&id, &stored.SessionID, &stored.TurnIndex, &stored.Actor, &eventType, &stored.Location, &stored.Content, &stored.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to read world event: %w", err)
+		}
+		stored.ID = fmt.Sprintf("ev_%d", id)
+		stored.Type = WorldEventType(eventType)
+		result = append(result, stored)
+	}
+	return result, rows.Err()
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := OpenStore(filepath.Join(t.TempDir(), "events.db"), "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStoreRedactsEventContent(t *testing.T) {
+	store := openTestStore(t)
+	event := WorldEvent{Type: EventSpeech, Actor: "elena", Location: "library", Content: "Write to me at elena@example.com"}
+	if err := store.Append(1, []WorldEvent{event}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := store.Last(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("got %d events, want 1", len(stored))
+	}
+	content := stored[0].Content
+	if strings.Contains(content, "elena@example.com") || !strings.HasPrefix(content, "Write to me at [REDACTED:email:") {
+		t.Errorf("content = %q", content)
+	}
+	if stored[0].Actor != "elena" || stored[0].Location != "library" || stored[0].TurnIndex != 1 {
+		t.Errorf("event = %+v", stored[0])
+	}
+}
+
+func TestSessionEventsMatchesByPrefix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	for _, sessionID := range []string{"abc-2", "abc-1", "xyz-1"} {
+		store, err := OpenStore(path, sessionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		evs := []WorldEvent{{Type: EventMovement, Actor: "player", Location: "foyer", Content: sessionID + " first"}, {Type: EventSound, Location: "foyer", Content: sessionID + " second"}}
+		if err := store.Append(3, evs); err != nil {
+			t.Fatal(err)
+		}
+		store.Close()
+	}
+
+	store, err := OpenStore(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	stored, err := store.SessionEvents("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, event := range stored {
+		if !strings.HasPrefix(event.Content, event.SessionID+" ") || event.TurnIndex != 3 {
+			t.Errorf("event = %+v", event)
+		}
+		got = append(got, event.Content)
+	}
+	want := []string{"abc-1 first", "abc-1 second", "abc-2 first", "abc-2 second"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
@@ -2,7 +2,11 @@ package events
 
 import (
     "fmt"
+    "strings"
+    "sync/atomic"
     "time"
+
+    "textadventure/internal/game"
 )
 
 // WorldEventType represents the canonical type of an in-game event.
@@ -16,8 +20,15 @@ const (
     EventSound         WorldEventType = "sound"
     EventStateChange   WorldEventType = "state_change"
     EventMutation      WorldEventType = "mutation"
+    EventAction        WorldEventType = "action"    // an attempt, or anything that changed nothing
+    EventScheduled     WorldEventType = "scheduled" // a scheduled event firing
+    EventContest       WorldEventType = "contest"   // the outcome of a contested action
+    EventAmbience      WorldEventType = "ambience"  // a room's ongoing background, perceived once per NPC
 )
 
+// SummaryTypes are the types a turn summary may give its events.
+var SummaryTypes = []WorldEventType{EventMovement, EventItemTransfer, EventInventory, EventSpeech, EventSound, EventStateChange, EventAction}
+
 // WorldEvent is the canonical record of something that happened in the world.
 type WorldEvent struct {
     ID        string                 `json:"id"`
@@ -30,6 +41,71 @@ type WorldEvent struct {
     Timestamp time.Time              `json:"timestamp"`
 }
 
+// eventSeq keeps IDs of events created in the same instant apart.
+var eventSeq atomic.Uint64
+
+// New creates an event that happens now.
+func New(eventType WorldEventType, actor, location, content string) WorldEvent {
+    ts := time.Now()
+    return WorldEvent{
+        ID:        fmt.Sprintf("ev_%d_%d", ts.UnixNano(), eventSeq.Add(1)),
+        Type:      eventType,
+        Actor:     actor,
+        Location:  location,
+        Content:   content,
+        Timestamp: ts,
+    }
+}
+
+// Line renders the event for a prompt, tagged with who acted and where
+// ("elena@library: ..."). An event without a location is just its content.
+func (e WorldEvent) Line() string {
+    if e.Location == "" {
+        return e.Content
+    }
+    return fmt.Sprintf("%s@%s: %s", game.RefID(e.Actor), game.RefID(e.Location), e.Content)
+}
+
+// Lines renders events for a prompt, one line each.
+func Lines(evs []WorldEvent) []string {
+    lines := make([]string, 0, len(evs))
+    for _, e := range evs {
+        if line := strings.TrimSpace(e.Line()); line != "" {
+            lines = append(lines, line)
+        }
+    }
+    return lines
+}
+
+// At returns the events that happened at location, plus those not tied to a place.
+func At(evs []WorldEvent, location string) []WorldEvent {
+    out := make([]WorldEvent, 0, len(evs))
+    for _, e := range evs {
+        if e.Location == "" || e.Location == location {
+            out = append(out, e)
+        }
+    }
+    return out
+}
+
+// TypeOfTool is the canonical event type of a mutation made with tool.
+func TypeOfTool(tool string) WorldEventType {
+    switch tool {
+    case "move_player", "move_npc":
+        return EventMovement
+    case "transfer_item", "give_item_to_npc", "npc_give_item_to_player":
+        return EventItemTransfer
+    case "add_to_inventory", "remove_from_inventory", "npc_take_item", "npc_drop_item":
+        return EventInventory
+    case "contest":
+        return EventContest
+    case "speak_to_npc":
+        return EventSpeech
+    default:
+        return EventMutation
+    }
+}
+
 // Mutation is a lightweight representation of a planned mutation.
 type Mutation struct {
     Tool string
@@ -76,6 +152,26 @@ func FromMutations(actor string, location string, muts []Mutation) []WorldEvent
             item, _ := m.Args["item"].(string)
             ev.Content = fmt.Sprintf("%s %s %s", actor, m.Tool, item)
             ev.Target = item
+        case "give_item_to_npc":
+            ev.Type = EventItemTransfer
+            item, _ := m.Args["item"].(string)
+            npcID, _ := m.Args["npc_id"].(string)
+            ev.Content = fmt.Sprintf("%s gave %s to %s", actor, item, npcID)
+            ev.Target = item
+        case "npc_give_item_to_player":
+            ev.Type = EventItemTransfer
+            item, _ := m.Args["item"].(string)
+            ev.Content = fmt.Sprintf("%s gave %s to the player", actor, item)
+            ev.Target = item
+        case "npc_take_item", "npc_drop_item":
+            ev.Type = EventInventory
+            item, _ := m.Args["item"].(string)
+            verb := "picked up"
+            if m.Tool == "npc_drop_item" {
+                verb = "dropped"
+            }
+            ev.Content = fmt.Sprintf("%s %s %s", actor, verb, item)
+            ev.Target = item
         }
         out = append(out, ev)
     }
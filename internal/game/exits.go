@@ -0,0 +1,74 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnexploredExit stands in for the destination of an exit into a room the player has
+// never entered.
+const UnexploredExit = "an unexplored doorway"
+
+type fullMapKey struct{}
+
+// WithFullMap marks world context built with ctx as director-facing: exits name every
+// destination, since the director needs real IDs to plan moves. Narrator and NPC
+// contexts leave it off so unexplored rooms stay unnamed.
+func WithFullMap(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fullMapKey{}, true)
+}
+
+func fullMapFrom(ctx context.Context) bool {
+	fullMap, _ := ctx.Value(fullMapKey{}).(bool)
+	return fullMap
+}
+
+// HasVisited reports whether the location is on the player's known map. The player's
+// current location always is.
+func HasVisited(world WorldState, locationID string) bool {
+	if locationID == world.Location {
+		return true
+	}
+	for _, visited := range world.VisitedLocations {
+		if visited == locationID {
+			return true
+		}
+	}
+	return false
+}
+
+// formatExits lists a location's exits by direction ("north → Study (study)"), marking
+// those behind a locked door ("north (locked: oak door) → Study (study)"). Unless
+// fullMap is set, exits into rooms the player hasn't visited show only the direction.
+func formatExits(world WorldState, locationID string, fullMap bool, refs *References) string {
+	location := world.Locations[locationID]
+	exits := location.Exits
+	directions := make([]string, 0, len(exits))
+	for direction := range exits {
+		directions = append(directions, direction)
+	}
+	sort.Strings(directions)
+
+	parts := make([]string, 0, len(directions))
+	for _, direction := range directions {
+		destination := exits[direction]
+		label := direction
+		if door, ok := location.Doors[direction]; ok && door.Locked {
+			label += " (locked)"
+			if description := strings.TrimSpace(door.Description); description != "" {
+				label = fmt.Sprintf("%s (locked: %s)", direction, description)
+			}
+		}
+		if fullMap || HasVisited(world, destination) {
+			parts = append(parts, fmt.Sprintf("%s → %s", label, refs.Ref(destination)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s → %s", label, UnexploredExit))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -18,7 +18,7 @@ type FactAttribution struct {
 	Skipped       []string            `json:"skipped"`
 }
 
-func AttributeFacts(ctx context.Context, llmService *llm.Service, extractedFacts []string, worldState *game.WorldState) (*FactAttribution, error) {
+func AttributeFacts(ctx context.Context, llmService llm.Completer, extractedFacts []string, worldState *game.WorldState) (*FactAttribution, error) {
 	tracer := otel.Tracer("facts")
 	ctx, span := tracer.Start(ctx, "facts.attribute")
 	defer span.End()
@@ -36,6 +36,7 @@ func AttributeFacts(ctx context.Context, llmService *llm.Service, extractedFacts
 
 	userPrompt := fmt.Sprintf("Attribute these extracted facts: %s", strings.Join(extractedFacts, ", "))
 
+	ctx = llm.WithOperationType(ctx, "facts.attribute")
 	response, err := llmService.CompleteJSON(ctx, llm.JSONCompletionRequest{
 		SystemPrompt:    systemPrompt,
 		UserPrompt:      userPrompt,
@@ -72,13 +73,14 @@ func AttributeFacts(ctx context.Context, llmService *llm.Service, extractedFacts
 
 func buildAttributionPrompt(worldState *game.WorldState, extractedFacts []string) string {
 	var contextBuilder strings.Builder
+	refs := game.NewReferences(*worldState)
 	
 	contextBuilder.WriteString("You are attributing facts extracted from player narration to the correct entities in a text adventure game.\n\n")
 
 	contextBuilder.WriteString("CURRENT WORLD CONTEXT:\n")
 	
 	currentLocation := worldState.Locations[worldState.Location]
-	contextBuilder.WriteString(fmt.Sprintf("Player is currently in: %s\n", currentLocation.Name))
+	contextBuilder.WriteString(fmt.Sprintf("Player is currently in: %s\n", refs.Ref(worldState.Location)))
 	if len(currentLocation.Facts) > 0 {
 		contextBuilder.WriteString(fmt.Sprintf("Existing location facts: %v\n", currentLocation.Facts))
 	}
@@ -86,12 +88,12 @@ func buildAttributionPrompt(worldState *game.WorldState, extractedFacts []string
 	contextBuilder.WriteString("\nAVAILABLE ENTITIES:\n")
 	contextBuilder.WriteString("Locations:\n")
 	for locID, loc := range worldState.Locations {
-		contextBuilder.WriteString(fmt.Sprintf("- %s (%s): existing facts %v\n", locID, loc.Name, loc.Facts))
+		contextBuilder.WriteString(fmt.Sprintf("- %s: existing facts %v\n", game.Mention(game.DisplayName(*worldState, locID), locID), loc.Facts))
 	}
 
 	contextBuilder.WriteString("\nNPCs:\n")
 	for npcID, npc := range worldState.NPCs {
-		contextBuilder.WriteString(fmt.Sprintf("- %s: location=%s, existing facts %v\n", npcID, npc.Location, npc.Facts))
+		contextBuilder.WriteString(fmt.Sprintf("- %s: location=%s, existing facts %v\n", game.Mention(game.NPCName(npcID), npcID), game.RefID(npc.Location), npc.Facts))
 	}
 
 	contextBuilder.WriteString("\nItems: (items are created dynamically - you can reference any item mentioned in the facts)\n")
@@ -118,6 +120,8 @@ Return JSON with this exact structure:
   "skipped": ["fact (reason: similar to existing 'other fact')"]
 }
 
+Entities are named by display name with their id in parentheses on first mention, e.g. "Elena (elena)". Always key the output by the lowercase id, never the display name.
+
 Only include entities that have facts to add. Use empty objects {} for sections with no facts.`)
 
 	return contextBuilder.String()
@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"textadventure/internal/game"
 	"textadventure/internal/llm"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
-func ExtractLocationFacts(ctx context.Context, llmService *llm.Service, narrationText, locationID string, existingFacts []string) ([]string, error) {
+// ExtractLocationFacts mines narration for permanent facts about a location.
+// The prompt names the location by locationName, never its ID, and facts that still
+// open with either have it stripped. observerNPCID names the NPC whose perspective the
+// narration was written from; pass an empty string for player narration.
+func ExtractLocationFacts(ctx context.Context, llmService llm.Completer, narrationText, locationID, locationName string, existingFacts []string, observerNPCID string) ([]string, error) {
 	if strings.TrimSpace(narrationText) == "" {
 		return []string{}, nil
 	}
@@ -28,6 +35,8 @@ Write facts as short, precise descriptions WITHOUT repeating the location name:
 - GOOD: "has slanted light", "smells of old paper", "doormat is scuffed"
 - BAD: "Old Foyer has slanted light", "The Old Foyer smells of old paper"
 
+Never put an ID in a fact. IDs are lowercase words joined by underscores such as "old_foyer" or "brass_key"; describe things in plain words instead.
+
 INCLUDE physical/architectural details that the player directly perceived:
 - Physical features: "has tall windows", "made of oak", "dusty atmosphere"  
 - Architectural elements: "stone floors", "vaulted ceiling", "narrow doorway"
@@ -40,6 +49,11 @@ EXCLUDE temporary states and actions:
 - Temporary conditions: "door is open" → NO
 - Time-specific states: "morning light streams" → NO
 
+EXCLUDE anything about the observer themselves:
+- Feelings and inner states: "feels disoriented", "is uneasy" → NO
+- The observer's body or actions: "my hands are cold", "she steadies herself" → NO
+- First-person phrasing: "I notice a draft" → rewrite as "has a draft" or skip
+
 AVOID semantic duplicates of existing facts:
 - If existing facts include "dusty atmosphere", don't extract "dust particles in air" or "covered in dust"
 - If existing facts include "wooden door", don't extract "made of wood" for the same door
@@ -56,11 +70,35 @@ Existing Facts (DO NOT duplicate):
 %s`, strings.Join(existingFacts, "\n"))
 	}
 
+	// The observer is the NPC, or for player narration the player character, who in
+	// third-person narration goes by the protagonist's name
+	observer := observerNPCID
+	firstPerson := observerNPCID != ""
+	perspectiveSection := ""
+	if observerNPCID != "" {
+		perspectiveSection = fmt.Sprintf(`
+
+Perspective: the observer is the NPC %s. Extract only facts about the physical space, never about %s's feelings, body, or actions.`, observerNPCID, observerNPCID)
+	} else {
+		switch pov := game.POVFromContext(ctx); pov.Person {
+		case game.FirstPerson:
+			firstPerson = true
+			perspectiveSection = `
+
+Perspective: the narration is in the first person; "I", "me" and "my" are the observer. Facts describe the space alone and never contain those words.`
+		case game.ThirdPerson:
+			observer = pov.Protagonist
+			perspectiveSection = fmt.Sprintf(`
+
+Perspective: the narration follows %s, the observer, in the third person. Facts describe the space alone and never name %s or say "he", "she" or "they" about them.`, pov.Protagonist, pov.Protagonist)
+		}
+	}
+
 	userPrompt := fmt.Sprintf(`Location: %s
 
-Narration: %s%s
+Narration: %s%s%s
 
-Extract permanent canonical facts about this location:`, locationID, narrationText, existingFactsSection)
+Extract permanent canonical facts about this location:`, locationName, narrationText, existingFactsSection, perspectiveSection)
 
 	req := llm.JSONCompletionRequest{
 		SystemPrompt:    systemPrompt,
@@ -75,6 +113,7 @@ Extract permanent canonical facts about this location:`, locationID, narrationTe
 		attribute.String("langfuse.observation.type", "generation"),
 		attribute.String("facts.narration_input", narrationText),
 		attribute.String("facts.location_id", locationID),
+		attribute.String("facts.location_name", locationName),
 	)
 
 	content, err := llmService.CompleteJSON(ctx, req)
@@ -94,7 +133,9 @@ Extract permanent canonical facts about this location:`, locationID, narrationTe
 			return []string{}, fmt.Errorf("fact extraction JSON parse failed: %w", jerr)
 		}
 		
-		// Look for array in common object keys
+		// Look for array in common object keys. Fallback: a reply with none of them
+		// reads as no facts
+		found := false
 		for _, key := range []string{"facts", "extracted_facts", "results", "items"} {
 			if val, exists := objResponse[key]; exists {
 				if arr, ok := val.([]interface{}); ok {
@@ -104,23 +145,111 @@ Extract permanent canonical facts about this location:`, locationID, narrationTe
 							facts = append(facts, str)
 						}
 					}
+					found = true
 					break
 				}
 			}
 		}
+		if !found {
+			if serr := game.StrictFallback(ctx, "facts extract", jerr); serr != nil {
+				return []string{}, serr
+			}
+		}
 	}
 
+	// Facts are compared by factKey, so one differing only in spacing, case or a final
+	// full stop from an existing fact, or an earlier one, is a duplicate
+	seen := make(map[string]bool, len(existingFacts)+len(facts))
+	for _, fact := range existingFacts {
+		seen[factKey(fact)] = true
+	}
 	cleanFacts := make([]string, 0, len(facts))
+	var rejected []string
 	for _, fact := range facts {
-		fact = strings.TrimSpace(fact)
-		if fact != "" {
-			cleanFacts = append(cleanFacts, fact)
+		fact = stripLeadingNames(normalizeFact(fact), locationID, locationName)
+		if fact == "" || seen[factKey(fact)] {
+			continue
 		}
+		if isAboutObserver(fact, observer, firstPerson) {
+			rejected = append(rejected, fact)
+			continue
+		}
+		seen[factKey(fact)] = true
+		cleanFacts = append(cleanFacts, fact)
 	}
 
 	span.SetAttributes(
 		attribute.Int("facts.extracted_count", len(cleanFacts)),
+		attribute.Int("facts.rejected_observer_count", len(rejected)),
 	)
+	if len(rejected) > 0 {
+		span.SetAttributes(attribute.StringSlice("facts.rejected_observer", rejected))
+	}
 
 	return cleanFacts, nil
 }
+
+// normalizeFact collapses runs of whitespace and drops a final full stop.
+func normalizeFact(fact string) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(fact), " "), ".")
+}
+
+// factKey is the form facts are deduplicated by: normalized and lowercase.
+func factKey(fact string) string {
+	return strings.ToLower(normalizeFact(fact))
+}
+
+// stripLeadingNames removes a location's name or ID from the front of a fact, with an
+// optional "the" and any possessive or separator after it: "foyer has tall windows",
+// "The Old Foyer's floor is marble" and "old_foyer: dusty" lose everything up to what
+// is said about the place. A fact that is nothing but the name comes back empty.
+func stripLeadingNames(fact string, names ...string) string {
+	for _, name := range names {
+		for _, variant := range []string{name, strings.ReplaceAll(name, "_", " ")} {
+			variant = strings.TrimSpace(variant)
+			if variant == "" {
+				continue
+			}
+			for _, prefix := range []string{"the " + variant, variant} {
+				if len(fact) < len(prefix) || !strings.EqualFold(fact[:len(prefix)], prefix) {
+					continue
+				}
+				rest := fact[len(prefix):]
+				if next, _ := utf8.DecodeRuneInString(rest); unicode.IsLetter(next) || next == '_' {
+					continue
+				}
+				rest = strings.TrimPrefix(strings.TrimPrefix(rest, "'s"), "’s")
+				return strings.TrimLeft(rest, " :,-–—")
+			}
+		}
+	}
+	return fact
+}
+
+var firstPersonWords = map[string]struct{}{
+	"i": {}, "i'm": {}, "i've": {}, "i'd": {}, "i'll": {},
+	"me": {}, "my": {}, "mine": {}, "myself": {},
+	"we": {}, "us": {}, "our": {}, "ours": {},
+}
+
+// isAboutObserver reports whether a fact describes the observer rather than the space:
+// anything phrased in the first person when the observer narrates (an NPC, or the player
+// in first-person narration), or naming the observer (an NPC ID or, for a named
+// protagonist, any word of their name). Otherwise "us" is just as likely a US flag.
+func isAboutObserver(fact string, observer string, firstPerson bool) bool {
+	names := strings.Fields(strings.ToLower(observer))
+	for _, word := range strings.FieldsFunc(strings.ToLower(fact), func(r rune) bool {
+		return !(unicode.IsLetter(r) || r == '\'' || r == '_')
+	}) {
+		word = strings.Trim(word, "'")
+		if _, ok := firstPersonWords[word]; ok && firstPerson {
+			return true
+		}
+		for _, name := range names {
+			if word == name || word == name+"'s" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package facts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/llm"
+)
+
+func TestIsAboutObserver(t *testing.T) {
+	tests := []struct {
+		fact        string
+		observer    string
+		firstPerson bool
+		want        bool
+	}{
+		// NPC-perspective narration that used to be filed as location facts
+		{"elena feels disoriented", "elena", true, true},
+		{"Elena's hands are shaking", "elena", true, true},
+		{"I feel a chill run down my spine", "elena", true, true},
+		{"My footsteps echo on the marble", "elena", true, true},
+		{"We are not alone here", "elena", true, true},
+		{"I'm certain someone was here", "", true, true},
+		{"The room makes me uneasy", "", true, true},
+		{"Mara notices a draft", "Mara Quinn", false, true},
+		{"A portrait of Quinn hangs crooked", "Mara Quinn", false, true},
+		// Facts about the space itself
+		{"The floor is cold marble", "elena", true, false},
+		{"A draft comes from the north window", "elena", true, false},
+		{"Dust covers the mantelpiece", "", true, false},
+		{"The chandelier is missing its candles", "Mara Quinn", false, false},
+		// Pronouns in narration the observer is not telling
+		{"A faded US flag hangs by the door", "", false, false},
+		{"A sign reads \"We never close\"", "", false, false},
+		{"Our Lady of the Rocks is painted above the altar", "Mara Quinn", false, false},
+		// Words that only contain a pronoun or a name
+		{"Mildew stains the ceiling", "", true, false},
+		{"The elenas of the portraits stare down", "elena", true, false},
+	}
+	for _, tt := range tests {
+		if got := isAboutObserver(tt.fact, tt.observer, tt.firstPerson); got != tt.want {
+			t.Errorf("isAboutObserver(%q, %q, %v) = %v, want %v", tt.fact, tt.observer, tt.firstPerson, got, tt.want)
+		}
+	}
+}
+
+func TestExtractLocationFactsRejectsObserverFacts(t *testing.T) {
+	mock := llm.NewMockService().OnOperation("facts.extract",
+		`["The floor is cold marble", "elena feels disoriented", "I notice a draft", "Dust covers the mantelpiece"]`)
+	facts, err := ExtractLocationFacts(context.Background(), mock, "Elena steps into the foyer.", "foyer", "Old Foyer", nil, "elena")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"The floor is cold marble", "Dust covers the mantelpiece"}
+	if strings.Join(facts, "|") != strings.Join(want, "|") {
+		t.Errorf("facts = %q, want %q", facts, want)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(calls))
+	}
+	if !strings.Contains(calls[0].UserPrompt, "the observer is the NPC elena") {
+		t.Errorf("prompt has no NPC perspective:\n%s", calls[0].UserPrompt)
+	}
+}
+
+func TestStripLeadingNames(t *testing.T) {
+	tests := []struct {
+		fact string
+		want string
+	}{
+		// The ID, the name and either with "the", any case
+		{"foyer has tall windows", "has tall windows"},
+		{"old_foyer: dusty", "dusty"},
+		{"Old Foyer - the floor is marble", "the floor is marble"},
+		{"The Old Foyer's floor is marble", "floor is marble"},
+		{"the old foyer’s windows rattle", "windows rattle"},
+		{"OLD FOYER, with tall windows", "with tall windows"},
+		{"old foyer — quiet", "quiet"},
+		// Nothing but the name
+		{"The Old Foyer", ""},
+		{"old_foyer", ""},
+		// Only a whole name at the very front counts
+		{"Old Foyers are drafty", "Old Foyers are drafty"},
+		{"foyer_b is locked", "foyer_b is locked"},
+		{"A draft crosses the old foyer", "A draft crosses the old foyer"},
+		{"The floor is cold marble", "The floor is cold marble"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := stripLeadingNames(tt.fact, "old_foyer", "Old Foyer", "foyer"); got != tt.want {
+			t.Errorf("stripLeadingNames(%q) = %q, want %q", tt.fact, got, tt.want)
+		}
+	}
+	if got := stripLeadingNames("Foyer is dark", "", "  "); got != "Foyer is dark" {
+		t.Errorf("empty names stripped %q", got)
+	}
+}
+
+func TestExtractLocationFactsStripsNamesAndDuplicates(t *testing.T) {
+	mock := llm.NewMockService().OnOperation("facts.extract",
+		`{"facts": ["The Old Foyer's floor is cold marble.", "old_foyer: tall  windows", "The Old Foyer", "Tall windows", "Dust covers the mantelpiece"]}`)
+	facts, err := ExtractLocationFacts(context.Background(), mock, "You step into the foyer.", "old_foyer", "Old Foyer", []string{"dust covers the mantelpiece."}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"floor is cold marble", "tall windows"}
+	if strings.Join(facts, "|") != strings.Join(want, "|") {
+		t.Errorf("facts = %q, want %q", facts, want)
+	}
+	if calls := mock.Calls(); len(calls) != 1 || !strings.Contains(calls[0].UserPrompt, "Location: Old Foyer") {
+		t.Errorf("prompt does not name the location")
+	}
+}
+
+// In player narration "us" and "we" are as likely a flag or a sign as the observer, so
+// only first-person narration has its pronouns filtered.
+func TestExtractLocationFactsKeepsPronounsOutsideFirstPerson(t *testing.T) {
+	reply := `["A faded US flag hangs by the door", "I feel watched", "Dust covers the mantelpiece"]`
+	tests := []struct {
+		name string
+		pov  game.POV
+		want []string
+	}{
+		{"second person", game.POV{}, []string{"A faded US flag hangs by the door", "I feel watched", "Dust covers the mantelpiece"}},
+		{"first person", game.POV{Person: game.FirstPerson}, []string{"Dust covers the mantelpiece"}},
+	}
+	for _, tt := range tests {
+		mock := llm.NewMockService().OnOperation("facts.extract", reply)
+		ctx := game.WithPOV(context.Background(), tt.pov)
+		facts, err := ExtractLocationFacts(ctx, mock, "You step into the foyer.", "foyer", "Foyer", nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(facts, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: facts = %q, want %q", tt.name, facts, tt.want)
+		}
+	}
+}
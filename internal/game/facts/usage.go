@@ -0,0 +1,177 @@
+package facts
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// EchoOverlap is the share of a fact's content words that must appear in the
+	// narration for the fact to count as echoed.
+	EchoOverlap = 0.6
+	// UnusedFactExposures is how many times a fact can be shown to the narrator without
+	// being echoed before it becomes a consolidation candidate.
+	UnusedFactExposures = 5
+)
+
+// stopWords are left out of token overlap so facts don't match on filler.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"are": true, "was": true, "were": true, "has": true, "have": true, "had": true,
+	"its": true, "from": true, "into": true, "onto": true, "there": true, "their": true,
+	"here": true, "some": true, "one": true, "but": true, "not": true, "all": true,
+	"near": true, "over": true, "under": true, "which": true, "while": true,
+}
+
+// normalizeText lowercases text and replaces everything but letters and digits with
+// single spaces.
+func normalizeText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// contentTokens returns the distinct content words of normalized text, with a plural
+// "s" trimmed so "candles" matches "candle".
+func contentTokens(normalized string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, word := range strings.Fields(normalized) {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = strings.TrimSuffix(word, "s")
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
+// EchoedFacts returns the facts the narration repeats, in their original order. A fact
+// is echoed when its normalized text appears in the narration, or when at least
+// EchoOverlap of its content words (and at least two) do. The match is deterministic so
+// usage counts are comparable between runs.
+func EchoedFacts(facts []string, narrationText string) []string {
+	narration := normalizeText(narrationText)
+	if narration == "" {
+		return nil
+	}
+	narrationTokens := contentTokens(narration)
+
+	var echoed []string
+	for _, fact := range facts {
+		normalized := normalizeText(fact)
+		if normalized == "" {
+			continue
+		}
+		if strings.Contains(" "+narration+" ", " "+normalized+" ") {
+			echoed = append(echoed, fact)
+			continue
+		}
+		factTokens := contentTokens(normalized)
+		if len(factTokens) < 2 {
+			continue
+		}
+		shared := 0
+		for token := range factTokens {
+			if narrationTokens[token] {
+				shared++
+			}
+		}
+		if shared >= 2 && float64(shared)/float64(len(factTokens)) >= EchoOverlap {
+			echoed = append(echoed, fact)
+		}
+	}
+	return echoed
+}
+
+// FactUsage is how often a fact was offered to the narrator and how often the narration
+// echoed it. LocationID and FirstSeenTurn record where and when the fact was first seen.
+type FactUsage struct {
+	LocationID    string
+	Fact          string
+	FirstSeenTurn int
+	Exposures     int
+	Uses          int
+	LastUsedTurn  int
+}
+
+type usageKey struct {
+	locationID string
+	fact       string
+}
+
+// UsageStore accumulates per-fact usage over the session. It is safe for concurrent use.
+type UsageStore struct {
+	mu    sync.RWMutex
+	usage map[usageKey]*FactUsage
+}
+
+// NewUsageStore creates an empty store.
+func NewUsageStore() *UsageStore {
+	return &UsageStore{usage: make(map[usageKey]*FactUsage)}
+}
+
+// Record counts one exposure of each of a location's facts for the turn and one use of
+// each fact the narration echoed. It returns the echoed facts.
+func (s *UsageStore) Record(locationID string, facts []string, narrationText string, turnIndex int) []string {
+	echoed := EchoedFacts(facts, narrationText)
+	used := make(map[string]bool, len(echoed))
+	for _, fact := range echoed {
+		used[fact] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fact := range facts {
+		key := usageKey{locationID: locationID, fact: fact}
+		entry, ok := s.usage[key]
+		if !ok {
+			entry = &FactUsage{LocationID: locationID, Fact: fact, FirstSeenTurn: turnIndex}
+			s.usage[key] = entry
+		}
+		entry.Exposures++
+		if used[fact] {
+			entry.Uses++
+			entry.LastUsedTurn = turnIndex
+		}
+	}
+	return echoed
+}
+
+// Usage returns the recorded usage of a fact, if any.
+func (s *UsageStore) Usage(locationID, fact string) (FactUsage, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.usage[usageKey{locationID: locationID, fact: fact}]
+	if !ok {
+		return FactUsage{}, false
+	}
+	return *entry, true
+}
+
+// ConsolidationCandidates returns facts that have never been echoed after at least
+// minExposures exposures, most exposed first, so consolidation can start with them.
+func (s *UsageStore) ConsolidationCandidates(minExposures int) []FactUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var candidates []FactUsage
+	for _, entry := range s.usage {
+		if entry.Uses == 0 && entry.Exposures >= minExposures {
+			candidates = append(candidates, *entry)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Exposures != candidates[j].Exposures {
+			return candidates[i].Exposures > candidates[j].Exposures
+		}
+		if candidates[i].LocationID != candidates[j].LocationID {
+			return candidates[i].LocationID < candidates[j].LocationID
+		}
+		return candidates[i].Fact < candidates[j].Fact
+	})
+	return candidates
+}
@@ -0,0 +1,122 @@
+// Package guide answers questions the player asks about the game rather than in it
+// ("what can I do here?", "who am I?"). Answers come from the world state and
+// established facts, out of character, and don't use up a turn.
+package guide
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/llm"
+)
+
+// Verdict is how an input was classified.
+type Verdict int
+
+const (
+	// InFiction inputs are actions or speech; they go to the director as usual.
+	InFiction Verdict = iota
+	// Meta inputs are questions to the game; the guide answers them.
+	Meta
+	// Unsure inputs read like a question but could be either; ask the model.
+	Unsure
+)
+
+// MetaThreshold is the confidence the model must report before an unsure input is
+// answered by the guide. Misrouting an action costs the player their action, so the
+// bar is high; below it the input is played as a turn.
+const MetaThreshold = 0.8
+
+// metaPatterns are questions that are about the player's situation or the game itself,
+// never something a character would do.
+var metaPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(what|which) (can|could|should|do) i (do|try)\b`),
+	regexp.MustCompile(`(?i)^what are my (options|choices|goals?)\b`),
+	regexp.MustCompile(`(?i)^(who|what) am i\b`),
+	regexp.MustCompile(`(?i)^(where am i|where was i)\s*\??$`),
+	regexp.MustCompile(`(?i)^what (am i|was i) (doing|supposed to)\b`),
+	regexp.MustCompile(`(?i)^(what is|what's) (my goal|the goal|the point|going on)\b`),
+	regexp.MustCompile(`(?i)^how do (i|you) play\b`),
+	regexp.MustCompile(`(?i)^(help|hint|hints|i'?m stuck|i am stuck)\s*[?!.]*$`),
+}
+
+// questionStart marks inputs that read like a question the player might be asking the
+// game. Combined with a first-person reference they are worth asking the model about.
+var questionStart = regexp.MustCompile(`(?i)^(what|who|where|why|how|am|can|could|should|do|did|is|are)\b`)
+
+var firstPerson = regexp.MustCompile(`(?i)\b(i|i'm|me|my|myself)\b`)
+
+// Classify sorts an input with keyword rules. Speech is always in-fiction; clear meta
+// questions are Meta; first-person questions that match no rule are Unsure.
+func Classify(input string) Verdict {
+	text := strings.TrimSpace(input)
+	if text == "" || strings.ContainsAny(text, "\"“”") {
+		return InFiction
+	}
+	for _, pattern := range metaPatterns {
+		if pattern.MatchString(text) {
+			return Meta
+		}
+	}
+	if questionStart.MatchString(text) && firstPerson.MatchString(text) {
+		return Unsure
+	}
+	return InFiction
+}
+
+const classifyPrompt = `You route a text adventure player's input.
+"meta" inputs are questions to the game about the player's situation, options, identity or how to play ("what can I do here?", "who am I supposed to be?").
+Anything the player's character says or does in the story, including questions asked aloud to someone or wondered in character ("is anyone here?", "what is this room?"), is not meta.
+Respond with JSON: {"meta": true|false, "confidence": 0.0-1.0}`
+
+// IsMeta asks the model about an input Classify was unsure of. It only answers true at
+// or above MetaThreshold.
+func IsMeta(ctx context.Context, llmService llm.Completer, input string) (bool, error) {
+	ctx = llm.WithOperationType(ctx, "guide.classify")
+	content, err := llmService.CompleteJSON(ctx, llm.JSONCompletionRequest{
+		SystemPrompt:    classifyPrompt,
+		UserPrompt:      input,
+		MaxTokens:       200,
+		Model:           "gpt-5-mini",
+		ReasoningEffort: "minimal",
+	})
+	if err != nil {
+		return false, fmt.Errorf("guide classification failed: %w", err)
+	}
+	var response struct {
+		Meta       bool    `json:"meta"`
+		Confidence float64 `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return false, fmt.Errorf("failed to parse guide classification: %w", err)
+	}
+	return response.Meta && response.Confidence >= MetaThreshold, nil
+}
+
+// answerPrompt is the guide's system prompt; %s is the point of view's GuideRule, so
+// hints talk about the player character the way the narration does.
+const answerPrompt = `You are the guide of a text adventure, answering the player out of character.
+%s, in two to four short sentences.
+Use only the world context below: where the player is, what they carry, who is here, the exits and the established facts. Never invent people, places, items, backstory or goals that aren't in it; if it doesn't say, tell the player that plainly and suggest looking around or asking someone.
+When asked what they can do, point to a few concrete things in the context (an exit, an item, a person) without solving anything for them.
+Plain text only, no headings or lists.`
+
+// Answer replies to a meta question from the player's view of the world.
+func Answer(ctx context.Context, llmService llm.Completer, question string, world game.WorldState, history []string) (string, error) {
+	ctx = llm.WithOperationType(ctx, "guide.answer")
+	answer, err := llmService.CompleteText(ctx, llm.TextCompletionRequest{
+		SystemPrompt:    fmt.Sprintf(answerPrompt, game.POVFromContext(ctx).GuideRule()),
+		UserPrompt:      game.CachedWorldContext(ctx, world, history) + "PLAYER QUESTION: " + question,
+		MaxTokens:       1000,
+		Model:           "gpt-5-mini",
+		ReasoningEffort: "minimal",
+	})
+	if err != nil {
+		return "", fmt.Errorf("guide answer failed: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
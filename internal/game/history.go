@@ -2,57 +2,195 @@ package game
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// HistoryEntryKind says who produced a history entry.
+type HistoryEntryKind string
+
+const (
+	HistoryPlayer   HistoryEntryKind = "player"
+	HistoryNarrator HistoryEntryKind = "narrator"
+	HistoryNPC      HistoryEntryKind = "npc"
+	HistorySpeech   HistoryEntryKind = "speech" // something an NPC said aloud
+)
+
+// IntroInput is what the opening turn is directed with. The player hasn't typed anything
+// yet, so the director and narrator are told the player character is waking up.
+const IntroInput = "awakening"
+
+// IsSyntheticInput reports whether input was made up by the engine to drive a turn,
+// rather than typed by the player.
+func IsSyntheticInput(input string) bool {
+	return input == IntroInput
+}
+
+// IsEngineError reports whether text is an error from the engine or the world-state
+// server, e.g. "Error: NPC 'x' does not exist" or "[ERROR] stream failed".
+func IsEngineError(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(text, "Error:") || strings.HasPrefix(text, "[ERROR]") || strings.HasPrefix(text, "\033[31m[ERROR]")
+}
+
+// HistoryEntry is one exchange of the conversation, with who produced it and where.
+// Location is the speaker's location at the time, and Witnesses the NPCs who were
+// there; both are empty for entries restored from saves, which don't carry them.
+type HistoryEntry struct {
+	Kind      HistoryEntryKind
+	Speaker   string
+	Location  string
+	Witnesses []string
+	Text      string
+}
+
+// String formats the entry the way prompts and saves show it, e.g. "Player: look".
+func (e HistoryEntry) String() string {
+	switch e.Kind {
+	case HistoryPlayer:
+		return "Player: " + e.Text
+	case HistoryNarrator:
+		return "Narrator: " + e.Text
+	case HistorySpeech:
+		return FormatNPCSpeech(e.Speaker, e.Text)
+	default:
+		return fmt.Sprintf("%s: %s", e.Speaker, e.Text)
+	}
+}
+
+// ParseHistoryEntry reads an entry back from its String form. Location is unknown.
+func ParseHistoryEntry(line string) HistoryEntry {
+	speaker, text, ok := strings.Cut(line, ": ")
+	if !ok {
+		return HistoryEntry{Kind: HistoryNarrator, Speaker: "narrator", Text: line}
+	}
+	switch speaker {
+	case "Player":
+		return HistoryEntry{Kind: HistoryPlayer, Speaker: "player", Text: text}
+	case "Narrator":
+		return HistoryEntry{Kind: HistoryNarrator, Speaker: "narrator", Text: text}
+	}
+	if quote, ok := unquoteSpeech(text); ok {
+		return HistoryEntry{Kind: HistorySpeech, Speaker: strings.ToLower(speaker), Text: quote}
+	}
+	return HistoryEntry{Kind: HistoryNPC, Speaker: speaker, Text: text}
+}
+
+// FormatNPCSpeech renders a line of NPC dialogue, e.g. `Elena: "Hello there!"`.
+func FormatNPCSpeech(npcID, quote string) string {
+	return fmt.Sprintf("%s: %q", NPCName(npcID), quote)
+}
+
+// unquoteSpeech reads back a quote written by FormatNPCSpeech.
+func unquoteSpeech(text string) (string, bool) {
+	if len(text) < 2 || !strings.HasPrefix(text, `"`) || !strings.HasSuffix(text, `"`) {
+		return "", false
+	}
+	quote, err := strconv.Unquote(text)
+	if err != nil {
+		return text[1 : len(text)-1], true
+	}
+	return quote, true
+}
+
 type History struct {
-	exchanges []string
+	exchanges []HistoryEntry
 	maxSize   int
 }
 
 func NewHistory(maxSize int) *History {
 	return &History{
-		exchanges: make([]string, 0, maxSize),
+		exchanges: make([]HistoryEntry, 0, maxSize),
 		maxSize:   maxSize,
 	}
 }
 
-func (h *History) AddPlayerAction(input string) {
-	h.add("Player: " + input)
+// AddPlayerAction records what the player typed, where they are in world. Synthetic
+// inputs that drive a turn the player didn't type (see IntroInput) are engine
+// machinery, not part of the story, and are never recorded.
+func (h *History) AddPlayerAction(input string, world WorldState) {
+	if IsSyntheticInput(input) {
+		return
+	}
+	h.add(historyEntry(HistoryPlayer, "player", world.Location, input, world))
+}
+
+func (h *History) AddNarratorResponse(response string, world WorldState) {
+	h.add(historyEntry(HistoryNarrator, "narrator", world.Location, response, world))
 }
 
-func (h *History) AddNarratorResponse(response string) {
-	h.add("Narrator: " + response)
+func (h *History) AddNPCAction(npcID, action string, world WorldState) {
+	h.add(historyEntry(HistoryNPC, npcID, world.NPCs[npcID].Location, action, world))
 }
 
-func (h *History) AddNPCAction(npcID, action string) {
-	h.add(fmt.Sprintf("%s: %s", npcID, action))
+// AddNPCSpeech records dialogue the player could hear, without the verb the NPC used
+// to act it out.
+func (h *History) AddNPCSpeech(npcID, quote string, world WorldState) {
+	h.add(historyEntry(HistorySpeech, npcID, world.NPCs[npcID].Location, quote, world))
 }
 
-func (h *History) AddError(err error) {
-	h.add("Error: " + err.Error())
+// historyEntry builds an entry said or done at location, witnessed by the NPCs there.
+func historyEntry(kind HistoryEntryKind, speaker, location, text string, world WorldState) HistoryEntry {
+	var witnesses []string
+	for npcID, npc := range world.NPCs {
+		if location != "" && npc.Location == location {
+			witnesses = append(witnesses, npcID)
+		}
+	}
+	sort.Strings(witnesses)
+	return HistoryEntry{Kind: kind, Speaker: speaker, Location: location, Witnesses: witnesses, Text: text}
 }
 
-func (h *History) add(entry string) {
+// add appends an entry, dropping empty ones and engine error text, which must never
+// reach a prompt.
+func (h *History) add(entry HistoryEntry) {
+	if strings.TrimSpace(entry.Text) == "" || IsEngineError(entry.Text) {
+		return
+	}
 	h.exchanges = append(h.exchanges, entry)
-	
+
 	if len(h.exchanges) > h.maxSize {
 		h.exchanges = h.exchanges[len(h.exchanges)-h.maxSize:]
 	}
 }
 
+// Restore replaces the history with previously saved entries, keeping the newest maxSize.
+func (h *History) Restore(entries []string) {
+	h.exchanges = h.exchanges[:0]
+	for _, entry := range entries {
+		h.add(ParseHistoryEntry(entry))
+	}
+}
+
+// GetEntries returns every entry formatted, oldest first. Prompts should use For so
+// each consumer gets the slice its policy allows.
 func (h *History) GetEntries() []string {
 	result := make([]string, len(h.exchanges))
-	copy(result, h.exchanges)
+	for i, entry := range h.exchanges {
+		result[i] = entry.String()
+	}
 	return result
 }
 
+// Entries returns a copy of the structured entries, oldest first.
+func (h *History) Entries() []HistoryEntry {
+	result := make([]HistoryEntry, len(h.exchanges))
+	copy(result, h.exchanges)
+	return result
+}
 
 // BuildWorldContext creates a comprehensive formatted context string for LLMs.
 // It handles both player and NPC perspectives, including co-location detection,
-// world state, and conversation history.
+// world state, and conversation history. Exits into rooms the player hasn't visited
+// are shown as directions only; see WithFullMap for the director's view.
 func BuildWorldContext(world WorldState, gameHistory []string, actingNPCID ...string) string {
+	return buildWorldContext(world, gameHistory, false, actingNPCID...)
+}
+
+func buildWorldContext(world WorldState, gameHistory []string, fullMap bool, actingNPCID ...string) string {
 	var context strings.Builder
+	refs := NewReferences(world)
 	
 	context.WriteString("WORLD STATE:\n")
 	
@@ -61,7 +199,7 @@ func BuildWorldContext(world WorldState, gameHistory []string, actingNPCID ...st
 		npcID := actingNPCID[0]
         if npc, exists := world.NPCs[npcID]; exists {
             currentLoc := world.Locations[npc.Location]
-            context.WriteString(fmt.Sprintf("NPC %s Location: %s\n", npcID, currentLoc.Name))
+            context.WriteString(fmt.Sprintf("NPC %s Location: %s\n", refs.Ref(npcID), refs.Ref(npc.Location)))
             
             // Show established facts about the location
             if len(currentLoc.Facts) > 0 {
@@ -70,30 +208,37 @@ func BuildWorldContext(world WorldState, gameHistory []string, actingNPCID ...st
                     context.WriteString(fmt.Sprintf("- %s\n", fact))
                 }
             }
+            context.WriteString(formatAmbience(currentLoc))
 
             // People context first
             if world.Location == npc.Location {
                 context.WriteString("Player is also here\n")
-                context.WriteString(fmt.Sprintf("Player Inventory: %v\n", world.Inventory))
+                context.WriteString(formatItems(world, "Player Inventory", world.Inventory, refs))
+            }
+            if attitude := FormatRelationship(npc.Relationship); attitude != "" {
+                context.WriteString(fmt.Sprintf("Attitude toward the player: %s\n", attitude))
             }
             var otherNPCs []string
             for otherNPCID, otherNPC := range world.NPCs {
                 if otherNPCID != npcID && otherNPC.Location == npc.Location {
-                    otherNPCs = append(otherNPCs, otherNPCID)
+                    otherNPCs = append(otherNPCs, refs.Ref(otherNPCID))
                 }
             }
             if len(otherNPCs) > 0 {
                 context.WriteString(fmt.Sprintf("Other NPCs here: %v\n", otherNPCs))
             }
 
+            context.WriteString(formatItems(world, "Items here", ItemsAt(world, npc.Location), refs))
+            context.WriteString(formatItems(world, "Carrying", npc.Inventory, refs))
+
             // Navigation next
-            context.WriteString(fmt.Sprintf("Available Exits: %v\n", currentLoc.Exits))
+            context.WriteString(fmt.Sprintf("Available Exits: %s\n", formatExits(world, npc.Location, fullMap, refs)))
 
         }
 	} else {
 		// Player perspective
 		currentLoc := world.Locations[world.Location]
-		context.WriteString("Player Location: " + currentLoc.Name + "\n")
+		context.WriteString("Player Location: " + refs.Ref(world.Location) + "\n")
         
         // Show established facts about the location
         if len(currentLoc.Facts) > 0 {
@@ -102,25 +247,15 @@ func BuildWorldContext(world WorldState, gameHistory []string, actingNPCID ...st
                 context.WriteString(fmt.Sprintf("- %s\n", fact))
             }
         }
+        context.WriteString(formatAmbience(currentLoc))
         // People context first
         var npcsHere []string
         for npcID, npc := range world.NPCs {
             if npc.Location == world.Location {
-                met := false
-                for _, metNPC := range world.MetNPCs {
-                    if metNPC == npcID {
-                        met = true
-                        break
-                    }
-                }
-                if met {
-                    npcsHere = append(npcsHere, npcID)
+                if HasMetNPC(world, npcID) {
+                    npcsHere = append(npcsHere, refs.Ref(npcID))
                 } else {
-                    description := npc.Description
-                    if description == "" {
-                        description = "someone"
-                    }
-                    npcsHere = append(npcsHere, description)
+                    npcsHere = append(npcsHere, NPCDescription(world, npcID))
                 }
             }
         }
@@ -128,9 +263,16 @@ func BuildWorldContext(world WorldState, gameHistory []string, actingNPCID ...st
             context.WriteString(fmt.Sprintf("People here: %v\n", npcsHere))
         }
         // Navigation next
-        context.WriteString(fmt.Sprintf("Available Exits: %v\n", currentLoc.Exits))
+        context.WriteString(fmt.Sprintf("Available Exits: %s\n", formatExits(world, world.Location, fullMap, refs)))
         // Inventory and items last
-        context.WriteString(fmt.Sprintf("Player Inventory: %v\n", world.Inventory))
+        context.WriteString(formatItems(world, "Items here", ItemsAt(world, world.Location), refs))
+        if len(world.Inventory) == 0 {
+            context.WriteString("Player Inventory: empty\n")
+        }
+        context.WriteString(formatItems(world, "Player Inventory", world.Inventory, refs))
+        if conditions := FormatConditions(world.Conditions); conditions != "" {
+            context.WriteString(conditions + "\n")
+        }
 	}
 	
 	
@@ -0,0 +1,59 @@
+package game
+
+import "slices"
+
+// Prompt consumers with their own history policy.
+const (
+	HistoryForDirector  = "director"
+	HistoryForNarration = "narration"
+	HistoryForNPC       = "npc"
+)
+
+// HistoryPolicy controls which history entries a prompt sees.
+type HistoryPolicy struct {
+	MaxEntries int                // newest entries kept after filtering; 0 keeps all
+	Kinds      []HistoryEntryKind // entry kinds included; nil includes all
+}
+
+// HistoryPolicies configures each prompt consumer. The director only needs the last
+// player/narrator pair plus the current input to resolve references like "it" or
+// "her"; the narrator keeps more for continuity. Unknown operations get everything.
+var HistoryPolicies = map[string]HistoryPolicy{
+	HistoryForDirector:  {MaxEntries: 3, Kinds: []HistoryEntryKind{HistoryPlayer, HistoryNarrator}},
+	HistoryForNarration: {MaxEntries: 8},
+	HistoryForNPC:       {MaxEntries: 6},
+}
+
+// For returns the formatted entries the operation's policy allows. When npcID is set
+// the prompt is from that NPC's perspective, so only entries it witnessed are kept.
+func (h *History) For(operation, npcID string) []string {
+	return h.Select(HistoryPolicies[operation], npcID)
+}
+
+// Select applies policy to the history. A non-empty npcID restricts it to entries by
+// that NPC or that it was present for; entries restored from a save record no
+// witnesses, so no NPC sees them.
+func (h *History) Select(policy HistoryPolicy, npcID string) []string {
+	var kinds map[HistoryEntryKind]bool
+	if policy.Kinds != nil {
+		kinds = make(map[HistoryEntryKind]bool, len(policy.Kinds))
+		for _, kind := range policy.Kinds {
+			kinds[kind] = true
+		}
+	}
+
+	var selected []string
+	for _, entry := range h.exchanges {
+		if kinds != nil && !kinds[entry.Kind] {
+			continue
+		}
+		if npcID != "" && entry.Speaker != npcID && !slices.Contains(entry.Witnesses, npcID) {
+			continue
+		}
+		selected = append(selected, entry.String())
+	}
+	if policy.MaxEntries > 0 && len(selected) > policy.MaxEntries {
+		selected = selected[len(selected)-policy.MaxEntries:]
+	}
+	return selected
+}
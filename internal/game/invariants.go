@@ -0,0 +1,362 @@
+package game
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// The invariants every world keeps, by the names violations report.
+const (
+	// InvariantPlayerLocation: the player is in a location that exists.
+	InvariantPlayerLocation = "player_location"
+	// InvariantNPCLocation: every NPC is in a location that exists.
+	InvariantNPCLocation = "npc_location"
+	// InvariantItemPlacement: every item is in one place, a location, an NPC, a
+	// container or the player, and a holder that keeps a list of its items lists it.
+	InvariantItemPlacement = "item_placement"
+	// InvariantReference: inventories, container contents, met NPCs, visited
+	// locations and exits only name things that exist.
+	InvariantReference = "reference"
+)
+
+// InvariantViolation is a way a world breaks one of its invariants. Repaired reports
+// whether RepairWorld fixed it (or, from CheckWorld, would).
+type InvariantViolation struct {
+	Rule     string
+	Detail   string
+	Repaired bool
+}
+
+func (v InvariantViolation) String() string {
+	if v.Repaired {
+		return fmt.Sprintf("%s: %s (repaired)", v.Rule, v.Detail)
+	}
+	return fmt.Sprintf("%s: %s", v.Rule, v.Detail)
+}
+
+// CheckWorld returns the world's invariant violations without changing it.
+func CheckWorld(world WorldState) []InvariantViolation {
+	_, violations := RepairWorld(world, world)
+	return violations
+}
+
+// RepairWorld checks a world read back after a batch of mutations and repairs what it
+// can, returning the repaired copy and every violation found. The server moves items
+// by their holders' lists and keeps each item's location in step, so where the two
+// disagree the lists win when exactly one holder lists the item, and the item's own
+// location otherwise. An actor or item left somewhere that doesn't exist goes back to
+// where it was in before, the world ahead of the batch, if that place still exists.
+// Dangling references are dropped.
+func RepairWorld(world, before WorldState) (WorldState, []InvariantViolation) {
+	repaired := world.Clone()
+	r := &worldRepair{world: &repaired, before: before}
+	r.dropDanglingReferences()
+	r.placePlayer()
+	r.placeNPCs()
+	r.placeItems()
+	return repaired, r.violations
+}
+
+// worldRepair collects violations while RepairWorld fixes a world in place.
+type worldRepair struct {
+	world      *WorldState
+	before     WorldState
+	violations []InvariantViolation
+}
+
+func (r *worldRepair) report(rule string, repaired bool, format string, args ...any) {
+	r.violations = append(r.violations, InvariantViolation{Rule: rule, Detail: fmt.Sprintf(format, args...), Repaired: repaired})
+}
+
+func (r *worldRepair) locationExists(id string) bool {
+	_, ok := r.world.Locations[id]
+	return ok
+}
+
+// dropDanglingReferences removes list entries and exits naming things that don't
+// exist. Items are only checked against a registry; without one there is nothing to
+// check them against.
+func (r *worldRepair) dropDanglingReferences() {
+	w := r.world
+	w.MetNPCs = r.dropMissing(w.MetNPCs, "met NPC", func(id string) bool { _, ok := w.NPCs[id]; return ok })
+	w.VisitedLocations = r.dropMissing(w.VisitedLocations, "visited location", r.locationExists)
+	for _, locID := range sortedKeys(w.Locations) {
+		loc := w.Locations[locID]
+		for _, direction := range sortedKeys(loc.Exits) {
+			if target := loc.Exits[direction]; !r.locationExists(target) {
+				r.report(InvariantReference, true, "exit %s from %s leads to missing location %q", direction, locID, target)
+				loc.Exits = maps.Clone(loc.Exits)
+				delete(loc.Exits, direction)
+				if _, hasDoor := loc.Doors[direction]; hasDoor {
+					loc.Doors = maps.Clone(loc.Doors)
+					delete(loc.Doors, direction)
+				}
+				w.Locations[locID] = loc
+			}
+		}
+	}
+	if len(w.Items) == 0 {
+		return
+	}
+	itemExists := func(id string) bool { _, ok := w.Items[id]; return ok }
+	w.Inventory = r.dropMissing(w.Inventory, "player inventory item", itemExists)
+	for _, npcID := range sortedKeys(w.NPCs) {
+		npc := w.NPCs[npcID]
+		npc.Inventory = r.dropMissing(npc.Inventory, npcID+" inventory item", itemExists)
+		w.NPCs[npcID] = npc
+	}
+	for _, itemID := range sortedKeys(w.Items) {
+		item := w.Items[itemID]
+		item.Contains = r.dropMissing(item.Contains, itemID+" content", itemExists)
+		w.Items[itemID] = item
+	}
+}
+
+// dropMissing returns ids without the ones that don't exist, reporting each.
+func (r *worldRepair) dropMissing(ids []string, what string, exists func(string) bool) []string {
+	var kept []string
+	for _, id := range ids {
+		if exists(id) {
+			kept = append(kept, id)
+			continue
+		}
+		r.report(InvariantReference, true, "%s %q does not exist", what, id)
+	}
+	if len(kept) == len(ids) {
+		return ids
+	}
+	return kept
+}
+
+// placePlayer moves a player in a missing location back to where they were before.
+func (r *worldRepair) placePlayer() {
+	w := r.world
+	if r.locationExists(w.Location) {
+		return
+	}
+	if r.locationExists(r.before.Location) {
+		r.report(InvariantPlayerLocation, true, "player is in missing location %q; moved back to %s", w.Location, r.before.Location)
+		w.Location = r.before.Location
+		return
+	}
+	r.report(InvariantPlayerLocation, false, "player is in missing location %q", w.Location)
+}
+
+// placeNPCs moves NPCs in missing locations back to where they were before.
+func (r *worldRepair) placeNPCs() {
+	w := r.world
+	for _, npcID := range sortedKeys(w.NPCs) {
+		npc := w.NPCs[npcID]
+		if r.locationExists(npc.Location) {
+			continue
+		}
+		if previous := r.before.NPCs[npcID].Location; r.locationExists(previous) {
+			r.report(InvariantNPCLocation, true, "NPC %s is in missing location %q; moved back to %s", npcID, npc.Location, previous)
+			npc.Location = previous
+			w.NPCs[npcID] = npc
+			continue
+		}
+		r.report(InvariantNPCLocation, false, "NPC %s is in missing location %q", npcID, npc.Location)
+	}
+}
+
+// placeItems gives every item one place: listed by exactly the holder its location
+// names. Without a registry, an item carried by more than one holder stays with the
+// first of the player and the NPCs in ID order.
+func (r *worldRepair) placeItems() {
+	w := r.world
+	if len(w.Items) == 0 {
+		r.dedupeCarried()
+		return
+	}
+	for _, itemID := range sortedKeys(w.Items) {
+		item := w.Items[itemID]
+		listed := r.holdersListing(itemID)
+		holder := item.Location
+		switch {
+		case len(listed) == 1:
+			holder = listed[0]
+		case len(listed) > 1 && !slices.Contains(listed, item.Location):
+			holder = listed[0]
+		case len(listed) == 0 && !r.holderExists(holder):
+			previous, ok := r.before.Items[itemID]
+			if !ok || !r.holderExists(previous.Location) {
+				r.report(InvariantItemPlacement, false, "item %s is in missing location %q", itemID, item.Location)
+				continue
+			}
+			r.report(InvariantItemPlacement, true, "item %s is in missing location %q; moved back to %s", itemID, item.Location, previous.Location)
+			holder = previous.Location
+		}
+		if holder != item.Location {
+			if len(listed) > 0 {
+				r.report(InvariantItemPlacement, true, "item %s is at %q but held by %s; moved to %s", itemID, item.Location, describeHolders(listed), holder)
+			}
+			item.Location = holder
+			w.Items[itemID] = item
+		}
+		for _, other := range listed {
+			if other != holder {
+				r.report(InvariantItemPlacement, true, "item %s is also listed by %s; removed", itemID, other)
+				r.unlist(other, itemID)
+			}
+		}
+		if !slices.Contains(listed, holder) && r.listsItems(holder) {
+			r.report(InvariantItemPlacement, true, "item %s is at %s but not in its list; added", itemID, holder)
+			r.list(holder, itemID)
+		}
+	}
+}
+
+// dedupeCarried keeps each carried item with one holder when there is no registry.
+func (r *worldRepair) dedupeCarried() {
+	w := r.world
+	carriers := make(map[string]string)
+	w.Inventory = r.dedupe("player", w.Inventory, carriers)
+	for _, npcID := range sortedKeys(w.NPCs) {
+		npc := w.NPCs[npcID]
+		npc.Inventory = r.dedupe(npcID, npc.Inventory, carriers)
+		w.NPCs[npcID] = npc
+	}
+}
+
+// dedupe drops the items in holder's list that carriers already has a holder for,
+// recording the rest as holder's.
+func (r *worldRepair) dedupe(holder string, ids []string, carriers map[string]string) []string {
+	var kept []string
+	for _, id := range ids {
+		if first, ok := carriers[id]; ok {
+			r.report(InvariantItemPlacement, true, "item %s is carried by both %s and %s; kept with %s", id, first, holder, first)
+			continue
+		}
+		carriers[id] = holder
+		kept = append(kept, id)
+	}
+	if len(kept) == len(ids) {
+		return ids
+	}
+	return kept
+}
+
+// holdersListing returns the holders whose lists include the item: the player first,
+// then NPCs and containers in ID order. A list naming it twice counts once, and the
+// duplicate is dropped.
+func (r *worldRepair) holdersListing(itemID string) []string {
+	w := r.world
+	var holders []string
+	if r.countListed(w.Inventory, "player", itemID) {
+		holders = append(holders, "player")
+	}
+	for _, npcID := range sortedKeys(w.NPCs) {
+		if r.countListed(w.NPCs[npcID].Inventory, npcID, itemID) {
+			holders = append(holders, npcID)
+		}
+	}
+	for _, containerID := range sortedKeys(w.Items) {
+		if r.countListed(w.Items[containerID].Contains, containerID, itemID) {
+			holders = append(holders, containerID)
+		}
+	}
+	return holders
+}
+
+// countListed reports whether ids includes itemID, dropping any repeats from holder's
+// list.
+func (r *worldRepair) countListed(ids []string, holder, itemID string) bool {
+	count := 0
+	for _, id := range ids {
+		if id == itemID {
+			count++
+		}
+	}
+	if count > 1 {
+		r.report(InvariantItemPlacement, true, "%s lists item %s %d times; kept once", holder, itemID, count)
+		r.unlist(holder, itemID)
+		r.list(holder, itemID)
+	}
+	return count > 0
+}
+
+// holderExists reports whether an item can be at holder.
+func (r *worldRepair) holderExists(holder string) bool {
+	if holder == "player" || r.locationExists(holder) {
+		return true
+	}
+	if _, ok := r.world.NPCs[holder]; ok {
+		return true
+	}
+	return r.world.Items[holder].IsContainer
+}
+
+// listsItems reports whether holder keeps a list of the items it has: the player, an
+// NPC or a container. Locations don't; their items are known by location alone.
+func (r *worldRepair) listsItems(holder string) bool {
+	if holder == "player" {
+		return true
+	}
+	if _, ok := r.world.NPCs[holder]; ok {
+		return true
+	}
+	return r.world.Items[holder].IsContainer
+}
+
+// list adds itemID to holder's list.
+func (r *worldRepair) list(holder, itemID string) {
+	w := r.world
+	switch {
+	case holder == "player":
+		w.Inventory = append(slices.Clip(w.Inventory), itemID)
+	case r.world.Items[holder].IsContainer:
+		container := w.Items[holder]
+		container.Contains = append(slices.Clip(container.Contains), itemID)
+		w.Items[holder] = container
+	default:
+		if npc, ok := w.NPCs[holder]; ok {
+			npc.Inventory = append(slices.Clip(npc.Inventory), itemID)
+			w.NPCs[holder] = npc
+		}
+	}
+}
+
+// unlist removes every mention of itemID from holder's list.
+func (r *worldRepair) unlist(holder, itemID string) {
+	w := r.world
+	without := func(ids []string) []string {
+		return slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return id == itemID })
+	}
+	if holder == "player" {
+		w.Inventory = without(w.Inventory)
+		return
+	}
+	if npc, ok := w.NPCs[holder]; ok {
+		npc.Inventory = without(npc.Inventory)
+		w.NPCs[holder] = npc
+		return
+	}
+	if container, ok := w.Items[holder]; ok {
+		container.Contains = without(container.Contains)
+		w.Items[holder] = container
+	}
+}
+
+// describeHolders names the holders listing an item for a violation.
+func describeHolders(holders []string) string {
+	switch len(holders) {
+	case 0:
+		return "nothing"
+	case 1:
+		return holders[0]
+	}
+	return fmt.Sprintf("%v", holders)
+}
+
+// sortedKeys returns a map's keys in order, so repairs and their reports are stable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,277 @@
+package game
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// invariantWorld keeps every invariant: the player in the foyer with a lamp, a rug on
+// the floor, and elena in the study with a letter, next to a chest holding a coin.
+func invariantWorld() WorldState {
+	return WorldState{
+		Location:         "foyer",
+		Inventory:        []string{"lamp"},
+		MetNPCs:          []string{"elena"},
+		VisitedLocations: []string{"foyer", "study"},
+		Locations: map[string]LocationInfo{
+			"foyer": {Name: "foyer", Exits: map[string]string{"north": "study"}},
+			"study": {Name: "study", Exits: map[string]string{"south": "foyer"}},
+		},
+		NPCs: map[string]NPCInfo{
+			"elena": {Location: "study", Inventory: []string{"letter"}},
+		},
+		Items: map[string]ItemInfo{
+			"lamp":   {Name: "lamp", Location: "player"},
+			"rug":    {Name: "rug", Location: "foyer"},
+			"letter": {Name: "letter", Location: "elena"},
+			"chest":  {Name: "chest", Location: "study", IsContainer: true, Contains: []string{"coin"}},
+			"coin":   {Name: "coin", Location: "chest"},
+		},
+	}
+}
+
+func setItem(w *WorldState, id string, item ItemInfo) {
+	w.Items[id] = item
+}
+
+func setNPC(w *WorldState, id string, edit func(*NPCInfo)) {
+	npc := w.NPCs[id]
+	edit(&npc)
+	w.NPCs[id] = npc
+}
+
+func TestRepairWorld(t *testing.T) {
+	tests := []struct {
+		name   string
+		breaks func(w *WorldState)
+		before func(w *WorldState) // changes to the world ahead of the batch, if any
+		want   []string
+		check  func(t *testing.T, w WorldState)
+	}{
+		{
+			name:   "player in a missing location",
+			breaks: func(w *WorldState) { w.Location = "attic" },
+			want:   []string{`player_location: player is in missing location "attic"; moved back to foyer (repaired)`},
+			check: func(t *testing.T, w WorldState) {
+				if w.Location != "foyer" {
+					t.Errorf("player at %s", w.Location)
+				}
+			},
+		},
+		{
+			name:   "player in a missing location with nowhere to go back to",
+			breaks: func(w *WorldState) { w.Location = "attic" },
+			before: func(w *WorldState) { w.Location = "cellar" },
+			want:   []string{`player_location: player is in missing location "attic"`},
+		},
+		{
+			name:   "NPC in a missing location",
+			breaks: func(w *WorldState) { setNPC(w, "elena", func(n *NPCInfo) { n.Location = "attic" }) },
+			want:   []string{`npc_location: NPC elena is in missing location "attic"; moved back to study (repaired)`},
+			check: func(t *testing.T, w WorldState) {
+				if got := w.NPCs["elena"].Location; got != "study" {
+					t.Errorf("elena at %s", got)
+				}
+			},
+		},
+		{
+			name:   "new NPC in a missing location",
+			breaks: func(w *WorldState) { w.NPCs["marcus"] = NPCInfo{Location: "attic"} },
+			want:   []string{`npc_location: NPC marcus is in missing location "attic"`},
+		},
+		{
+			name:   "item location out of step with its holder",
+			breaks: func(w *WorldState) { setItem(w, "letter", ItemInfo{Name: "letter", Location: "foyer"}) },
+			want:   []string{`item_placement: item letter is at "foyer" but held by elena; moved to elena (repaired)`},
+			check: func(t *testing.T, w WorldState) {
+				if got := w.Items["letter"].Location; got != "elena" {
+					t.Errorf("letter at %s", got)
+				}
+			},
+		},
+		{
+			name:   "two holders, one matching the item",
+			breaks: func(w *WorldState) { w.Inventory = append(w.Inventory, "letter") },
+			want:   []string{"item_placement: item letter is also listed by player; removed (repaired)"},
+			check: func(t *testing.T, w WorldState) {
+				if !slices.Equal(w.Inventory, []string{"lamp"}) || !slices.Equal(w.NPCs["elena"].Inventory, []string{"letter"}) {
+					t.Errorf("player carries %q, elena %q", w.Inventory, w.NPCs["elena"].Inventory)
+				}
+			},
+		},
+		{
+			name: "two holders, neither matching the item",
+			breaks: func(w *WorldState) {
+				w.Inventory = append(w.Inventory, "letter")
+				setItem(w, "letter", ItemInfo{Name: "letter", Location: "study"})
+			},
+			want: []string{
+				`item_placement: item letter is at "study" but held by [player elena]; moved to player (repaired)`,
+				"item_placement: item letter is also listed by elena; removed (repaired)",
+			},
+			check: func(t *testing.T, w WorldState) {
+				if w.Items["letter"].Location != "player" || len(w.NPCs["elena"].Inventory) != 0 {
+					t.Errorf("letter at %s, elena carries %q", w.Items["letter"].Location, w.NPCs["elena"].Inventory)
+				}
+			},
+		},
+		{
+			name:   "item created at the player",
+			breaks: func(w *WorldState) { setItem(w, "map", ItemInfo{Name: "map", Location: "player"}) },
+			want:   []string{"item_placement: item map is at player but not in its list; added (repaired)"},
+			check: func(t *testing.T, w WorldState) {
+				if !slices.Equal(w.Inventory, []string{"lamp", "map"}) {
+					t.Errorf("player carries %q", w.Inventory)
+				}
+			},
+		},
+		{
+			name: "container not listing its item",
+			breaks: func(w *WorldState) {
+				setItem(w, "chest", ItemInfo{Name: "chest", Location: "study", IsContainer: true})
+			},
+			want: []string{"item_placement: item coin is at chest but not in its list; added (repaired)"},
+			check: func(t *testing.T, w WorldState) {
+				if !slices.Equal(w.Items["chest"].Contains, []string{"coin"}) {
+					t.Errorf("chest holds %q", w.Items["chest"].Contains)
+				}
+			},
+		},
+		{
+			name:   "item at a missing holder",
+			breaks: func(w *WorldState) { setItem(w, "rug", ItemInfo{Name: "rug", Location: "attic"}) },
+			want:   []string{`item_placement: item rug is in missing location "attic"; moved back to foyer (repaired)`},
+			check: func(t *testing.T, w WorldState) {
+				if got := w.Items["rug"].Location; got != "foyer" {
+					t.Errorf("rug at %s", got)
+				}
+			},
+		},
+		{
+			name:   "new item at a missing holder",
+			breaks: func(w *WorldState) { setItem(w, "ghost", ItemInfo{Name: "ghost", Location: "attic"}) },
+			want:   []string{`item_placement: item ghost is in missing location "attic"`},
+		},
+		{
+			name:   "item in something that is not a container",
+			breaks: func(w *WorldState) { setItem(w, "rug", ItemInfo{Name: "rug", Location: "lamp"}) },
+			want:   []string{`item_placement: item rug is in missing location "lamp"; moved back to foyer (repaired)`},
+		},
+		{
+			name: "dangling exit and its door",
+			breaks: func(w *WorldState) {
+				w.Locations["foyer"] = LocationInfo{Name: "foyer",
+					Exits: map[string]string{"north": "study", "west": "cellar"},
+					Doors: map[string]DoorInfo{"west": {Locked: true}}}
+			},
+			want: []string{`reference: exit west from foyer leads to missing location "cellar" (repaired)`},
+			check: func(t *testing.T, w WorldState) {
+				foyer := w.Locations["foyer"]
+				if _, ok := foyer.Exits["west"]; ok || len(foyer.Doors) != 0 || foyer.Exits["north"] != "study" {
+					t.Errorf("foyer exits %v, doors %v", foyer.Exits, foyer.Doors)
+				}
+			},
+		},
+		{
+			name: "dangling list entries",
+			breaks: func(w *WorldState) {
+				w.MetNPCs = append(w.MetNPCs, "marcus")
+				w.VisitedLocations = append(w.VisitedLocations, "attic")
+				w.Inventory = append(w.Inventory, "sword")
+				setNPC(w, "elena", func(n *NPCInfo) { n.Inventory = append(slices.Clone(n.Inventory), "dagger") })
+				setItem(w, "chest", ItemInfo{Name: "chest", Location: "study", IsContainer: true, Contains: []string{"coin", "pearl"}})
+			},
+			want: []string{
+				`reference: met NPC "marcus" does not exist (repaired)`,
+				`reference: visited location "attic" does not exist (repaired)`,
+				`reference: player inventory item "sword" does not exist (repaired)`,
+				`reference: elena inventory item "dagger" does not exist (repaired)`,
+				`reference: chest content "pearl" does not exist (repaired)`,
+			},
+			check: func(t *testing.T, w WorldState) {
+				if !reflect.DeepEqual(w, invariantWorld()) {
+					t.Errorf("repaired world = %+v", w)
+				}
+			},
+		},
+		{
+			name: "an item listed twice",
+			breaks: func(w *WorldState) {
+				setNPC(w, "elena", func(n *NPCInfo) { n.Inventory = []string{"letter", "letter"} })
+			},
+			want: []string{"item_placement: elena lists item letter 2 times; kept once (repaired)"},
+			check: func(t *testing.T, w WorldState) {
+				if !slices.Equal(w.NPCs["elena"].Inventory, []string{"letter"}) {
+					t.Errorf("elena carries %q", w.NPCs["elena"].Inventory)
+				}
+			},
+		},
+		{
+			name: "carried twice without an item registry",
+			breaks: func(w *WorldState) {
+				w.Items = nil
+				w.Inventory = []string{"lamp", "letter"}
+			},
+			want: []string{"item_placement: item letter is carried by both player and elena; kept with player (repaired)"},
+			check: func(t *testing.T, w WorldState) {
+				if !slices.Equal(w.Inventory, []string{"lamp", "letter"}) || len(w.NPCs["elena"].Inventory) != 0 {
+					t.Errorf("player carries %q, elena %q", w.Inventory, w.NPCs["elena"].Inventory)
+				}
+			},
+		},
+		{
+			name: "unknown items without a registry are left alone",
+			breaks: func(w *WorldState) {
+				w.Items = nil
+				w.Inventory = []string{"anything"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken := invariantWorld()
+			tt.breaks(&broken)
+			before := invariantWorld()
+			if tt.before != nil {
+				tt.before(&before)
+			}
+			untouched := invariantWorld()
+			tt.breaks(&untouched)
+
+			repaired, violations := RepairWorld(broken, before)
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("violations:\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if !reflect.DeepEqual(broken, untouched) {
+				t.Error("RepairWorld changed the world it was given")
+			}
+			if tt.check != nil {
+				tt.check(t, repaired)
+			}
+
+			// Detection matches repair, and a fully repaired world keeps every invariant
+			if checked := CheckWorld(broken); len(checked) != len(violations) {
+				t.Errorf("CheckWorld found %d violations, RepairWorld %d", len(checked), len(violations))
+			}
+			if !slices.ContainsFunc(violations, func(v InvariantViolation) bool { return !v.Repaired }) {
+				if again := CheckWorld(repaired); len(again) != 0 {
+					t.Errorf("repaired world still breaks %v", again)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckWorldOnAValidWorld(t *testing.T) {
+	for name, world := range map[string]WorldState{"fixture": invariantWorld(), "default": NewDefaultWorldState()} {
+		if violations := CheckWorld(world); len(violations) != 0 {
+			t.Errorf("%s world breaks %v", name, violations)
+		}
+	}
+}
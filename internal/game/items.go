@@ -0,0 +1,181 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FindCarriedItem resolves a player's reference ("key", "brass key", "brass_key") to
+// the ID of an item in their inventory. Exact ID or name matches win over partial ones.
+func FindCarriedItem(world WorldState, ref string) (string, bool) {
+	ref = normalizeItemRef(ref)
+	if ref == "" {
+		return "", false
+	}
+
+	partial := ""
+	for _, itemID := range world.Inventory {
+		candidates := []string{normalizeItemRef(itemID)}
+		if item, ok := world.Items[itemID]; ok && item.Name != "" {
+			candidates = append(candidates, normalizeItemRef(item.Name))
+		}
+		for _, candidate := range candidates {
+			if candidate == ref {
+				return itemID, true
+			}
+			if partial == "" && (strings.HasSuffix(candidate, " "+ref) || strings.HasPrefix(candidate, ref+" ")) {
+				partial = itemID
+			}
+		}
+	}
+	return partial, partial != ""
+}
+
+func normalizeItemRef(ref string) string {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	ref = strings.ReplaceAll(ref, "_", " ")
+	ref = strings.TrimPrefix(ref, "the ")
+	return strings.Join(strings.Fields(ref), " ")
+}
+
+// ResolveItemID maps an item reference from the director ("brass key", "Brass_Key",
+// "key") to its canonical ID in world.Items. IDs are matched before display names, and
+// a partial match is only accepted when it is unambiguous. Near-misses are never
+// accepted; the error lists them (or every known item) so the director can retry.
+// With no item registry the reference is returned unchanged for the server to judge.
+func ResolveItemID(world WorldState, ref string) (string, error) {
+	if len(world.Items) == 0 {
+		return ref, nil
+	}
+	if _, ok := world.Items[ref]; ok {
+		return ref, nil
+	}
+	norm := normalizeItemRef(ref)
+	if norm == "" {
+		return "", fmt.Errorf("empty item reference")
+	}
+
+	ids := make([]string, 0, len(world.Items))
+	for id := range world.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if normalizeItemRef(id) == norm {
+			return id, nil
+		}
+	}
+	for _, id := range ids {
+		if name := world.Items[id].Name; name != "" && normalizeItemRef(name) == norm {
+			return id, nil
+		}
+	}
+
+	var partial []string
+	for _, id := range ids {
+		for _, candidate := range itemCandidates(id, world.Items[id]) {
+			if strings.HasSuffix(candidate, " "+norm) || strings.HasPrefix(candidate, norm+" ") ||
+				strings.HasSuffix(norm, " "+candidate) || strings.HasPrefix(norm, candidate+" ") {
+				partial = append(partial, id)
+				break
+			}
+		}
+	}
+	if len(partial) == 1 {
+		return partial[0], nil
+	}
+	if len(partial) > 1 {
+		return "", fmt.Errorf("item %q is ambiguous (could be %s)", ref, strings.Join(partial, ", "))
+	}
+
+	var close []string
+	for _, id := range ids {
+		for _, candidate := range itemCandidates(id, world.Items[id]) {
+			if levenshtein(candidate, norm) <= maxItemTypos(norm) {
+				close = append(close, id)
+				break
+			}
+		}
+	}
+	if len(close) > 0 {
+		return "", fmt.Errorf("unknown item %q (did you mean %s?)", ref, strings.Join(close, ", "))
+	}
+	return "", fmt.Errorf("unknown item %q (known items: %s)", ref, strings.Join(ids, ", "))
+}
+
+func itemCandidates(id string, item ItemInfo) []string {
+	candidates := []string{normalizeItemRef(id)}
+	if item.Name != "" {
+		candidates = append(candidates, normalizeItemRef(item.Name))
+	}
+	return candidates
+}
+
+// maxItemTypos is how many edits a reference may be from an item before it stops
+// counting as a likely typo.
+func maxItemTypos(ref string) int {
+	if len(ref) < 5 {
+		return 1
+	}
+	return 2
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// ItemsAt returns the IDs of the items whose location is holder (a location ID, an NPC
+// ID or "player"), sorted.
+func ItemsAt(world WorldState, holder string) []string {
+	var ids []string
+	for id, item := range world.Items {
+		if item.Location == holder {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// formatItems renders a labelled list of items for world context, each with the facts
+// established about it and, for containers, what the player knows they hold. It returns
+// an empty string when there are none.
+func formatItems(world WorldState, label string, itemIDs []string, refs *References) string {
+	if len(itemIDs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(label + ":\n")
+	for _, id := range itemIDs {
+		line := "- " + refs.Ref(id)
+		if item := world.Items[id]; item.IsContainer {
+			line += formatContainer(item, refs)
+		}
+		// The opened marker is already rendered as the container's state
+		facts := slices.DeleteFunc(slices.Clone(world.Items[id].Facts), func(fact string) bool { return fact == ContainerOpenedFact })
+		if len(facts) > 0 {
+			line += ": " + strings.Join(facts, "; ")
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func itemWorld() WorldState {
+	world := NewDefaultWorldState()
+	world.Items = map[string]ItemInfo{
+		"brass_key":   {Name: "brass key", Location: "foyer"},
+		"iron_key":    {Name: "iron key", Location: "study"},
+		"lantern_01":  {Name: "Old Lantern", Location: "player"},
+		"letter":      {Name: "sealed letter", Location: "elena"},
+		"music_box":   {Name: "music box", Location: "library"},
+		"silver_coin": {Location: "kitchen"},
+	}
+	return world
+}
+
+func TestResolveItemIDNamingVariants(t *testing.T) {
+	world := itemWorld()
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		// Exact IDs
+		{"brass_key", "brass_key"},
+		{"lantern_01", "lantern_01"},
+		// IDs with different case, spacing and separators
+		{"brass key", "brass_key"},
+		{"Brass_Key", "brass_key"},
+		{"BRASS KEY", "brass_key"},
+		{"  brass   key ", "brass_key"},
+		{"the brass key", "brass_key"},
+		{"Silver Coin", "silver_coin"},
+		// Display names that differ from the ID
+		{"old lantern", "lantern_01"},
+		{"Old_Lantern", "lantern_01"},
+		{"the Old Lantern", "lantern_01"},
+		{"sealed letter", "letter"},
+		// Unambiguous partial names
+		{"lantern", "lantern_01"},
+		{"box", "music_box"},
+		{"coin", "silver_coin"},
+		{"the sealed letter from elena", "letter"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveItemID(world, tt.ref)
+		if err != nil {
+			t.Errorf("ResolveItemID(%q) error: %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveItemID(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestResolveItemIDFailures(t *testing.T) {
+	world := itemWorld()
+	tests := []struct {
+		ref      string
+		wantText []string
+	}{
+		{"key", []string{"ambiguous", "brass_key", "iron_key"}},
+		{"brass kye", []string{"did you mean", "brass_key"}},
+		{"old lanturn", []string{"did you mean", "lantern_01"}},
+		{"golden chalice", []string{"unknown item", "known items", "brass_key", "silver_coin"}},
+		{"   ", []string{"empty"}},
+	}
+	for _, tt := range tests {
+		got, err := ResolveItemID(world, tt.ref)
+		if err == nil {
+			t.Errorf("ResolveItemID(%q) = %q, want an error", tt.ref, got)
+			continue
+		}
+		for _, text := range tt.wantText {
+			if !strings.Contains(err.Error(), text) {
+				t.Errorf("ResolveItemID(%q) error %q does not mention %q", tt.ref, err, text)
+			}
+		}
+	}
+}
+
+func TestResolveItemIDWithoutRegistry(t *testing.T) {
+	world := NewDefaultWorldState()
+	got, err := ResolveItemID(world, "Brass Key")
+	if err != nil || got != "Brass Key" {
+		t.Errorf("ResolveItemID without items = %q, %v; want the reference unchanged", got, err)
+	}
+}
+
+func TestFindCarriedItem(t *testing.T) {
+	world := itemWorld()
+	world.Inventory = []string{"lantern_01", "brass_key"}
+	tests := []struct {
+		ref    string
+		want   string
+		wantOK bool
+	}{
+		{"brass_key", "brass_key", true},
+		{"Brass Key", "brass_key", true},
+		{"old lantern", "lantern_01", true},
+		{"key", "brass_key", true},
+		{"iron key", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := FindCarriedItem(world, tt.ref)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("FindCarriedItem(%q) = %q, %v; want %q, %v", tt.ref, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package narration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/game/echoes"
+	"textadventure/internal/llm"
+)
+
+func TestRetrieveEchoes(t *testing.T) {
+	service := llm.NewMockService()
+	embed := func(text string) []float64 {
+		vector, err := service.Embed(context.Background(), text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vector
+	}
+	store := echoes.NewStore()
+	store.Add(echoes.Moment{TurnIndex: 1, Text: "The lamp sputters into life.", Vector: embed("light the lamp")})
+	store.Add(echoes.Moment{TurnIndex: 4, Text: "The lamp flares again.", Vector: embed("light the lamp")})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		input      string
+		contextLen int
+		want       []string
+	}{
+		{"similar earlier turn", echoes.WithStore(context.Background(), store, 3), "light the lamp", 100, []string{"The lamp sputters into life."}},
+		{"dissimilar input", echoes.WithStore(context.Background(), store, 3), "xyzzy", 100, nil},
+		{"no store", context.Background(), "light the lamp", 100, nil},
+		{"empty input", echoes.WithStore(context.Background(), store, 3), "  ", 100, nil},
+		{"context too large", echoes.WithStore(context.Background(), store, 3), "light the lamp", maxContextForEchoes + 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, match := range retrieveEchoes(tt.ctx, service, tt.input, tt.contextLen, false) {
+				got = append(got, match.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("echoes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNarrationPromptEchoes(t *testing.T) {
+	prompt := buildNarrationPrompt("You light the lamp.", nil, []string{"The lamp is lit"}, []string{"  The lamp sputters into life.  "}, "", []string{"Elena is watching"}, Style{}, nil, game.POV{})
+
+	events := strings.Index(prompt, "WORLD EVENTS FOR THIS TURN:")
+	echoSection := strings.Index(prompt, "ECHOES OF EARLIER EVENTS")
+	notes := strings.Index(prompt, "PRIVATE DIRECTION (from")
+	if events < 0 || echoSection < 0 || notes < 0 {
+		t.Fatalf("missing section: events %d, echoes %d, notes %d", events, echoSection, notes)
+	}
+	if !(events < echoSection && echoSection < notes) {
+		t.Errorf("echoes at %d, want between world events at %d and private direction at %d", echoSection, events, notes)
+	}
+	if !strings.Contains(prompt, "\n- The lamp sputters into life.\n") {
+		t.Error("the echo is not listed trimmed")
+	}
+
+	if prompt := buildNarrationPrompt("You light the lamp.", nil, nil, nil, "", nil, Style{}, nil, game.POV{}); strings.Contains(prompt, "ECHOES OF EARLIER EVENTS") {
+		t.Error("an empty echoes section was included")
+	}
+}
@@ -3,10 +3,13 @@ package narration
 import (
     "fmt"
     "strings"
+
+    "textadventure/internal/game"
+    "textadventure/internal/game/events"
 )
 
 // buildNPCNarrationPrompt builds a system prompt for NPC-perspective narration.
-func BuildNPCNarrationPrompt(npcID string, actionContext string, mutationResults []string, worldEventLines []string) string {
+func BuildNPCNarrationPrompt(npcID string, actionContext string, mutationResults []string, worldEvents []events.WorldEvent) string {
     var actionAndMutationContext string
     if strings.TrimSpace(actionContext) != "" {
         actionAndMutationContext = fmt.Sprintf("\n\nACTION THAT JUST OCCURRED:\n%s", actionContext)
@@ -17,9 +20,9 @@ func BuildNPCNarrationPrompt(npcID string, actionContext string, mutationResults
     }
 
     var eventsContext string
-    if len(worldEventLines) > 0 {
+    if len(worldEvents) > 0 {
         eventsContext = "\n\nWORLD EVENTS FOR THIS TURN:\n"
-        for _, line := range worldEventLines {
+        for _, line := range events.Lines(worldEvents) {
             eventsContext += fmt.Sprintf("- %s\n", strings.TrimSpace(line))
         }
     }
@@ -39,5 +42,5 @@ Rules:
 - If some events failed, briefly reflect their consequence without advice.
 - If little changed, write a short beat of stillness and texture.
 
-Only use information from the inputs below:%s%s`, strings.ToUpper(npcID), strings.ToUpper(npcID), actionAndMutationContext, eventsContext)
+Only use information from the inputs below:%s%s`, game.Mention(game.NPCName(npcID), npcID), game.NPCName(npcID), actionAndMutationContext, eventsContext)
 }
@@ -0,0 +1,84 @@
+package narration
+
+import (
+	"strings"
+	"time"
+)
+
+// Defaults for StreamPacer. A UI that takes longer than LagThreshold to ask for the
+// next chunk is falling behind, typically because rendering is blocked on a slow
+// terminal; updates are then batched to at most one per BatchInterval.
+const (
+	defaultLagThreshold  = 50 * time.Millisecond
+	defaultBatchInterval = 200 * time.Millisecond
+)
+
+// StreamPacer decides when the stream reader hands text to the UI. While the UI keeps
+// up, every chunk is sent as it arrives. When it falls behind, chunks are accumulated
+// and sent as one consolidated update per BatchInterval, so each re-render covers more
+// text. It also keeps the full response, so completion never depends on which updates
+// the UI saw. Only one read command runs at a time, so it needs no locking.
+type StreamPacer struct {
+	LagThreshold  time.Duration
+	BatchInterval time.Duration
+
+	lastEmit time.Time
+	batching bool
+	pending  strings.Builder
+	full     strings.Builder
+	batches  int
+}
+
+// NewStreamPacer returns a pacer with the default thresholds.
+func NewStreamPacer() *StreamPacer {
+	return &StreamPacer{LagThreshold: defaultLagThreshold, BatchInterval: defaultBatchInterval}
+}
+
+// Resume is called when the UI asks for more text. The time since the last update was
+// handed over is how long the UI took to process it; batching switches on above
+// LagThreshold and back off once the UI is comfortably under it again.
+func (p *StreamPacer) Resume(now time.Time) {
+	if p.lastEmit.IsZero() {
+		return
+	}
+	lag := now.Sub(p.lastEmit)
+	switch {
+	case lag > p.LagThreshold:
+		p.batching = true
+	case lag < p.LagThreshold/2:
+		p.batching = false
+	}
+}
+
+// Add records a chunk and reports whether the pending text should be sent now.
+func (p *StreamPacer) Add(chunk string, now time.Time) bool {
+	p.pending.WriteString(chunk)
+	p.full.WriteString(chunk)
+	return !p.batching || now.Sub(p.lastEmit) >= p.BatchInterval
+}
+
+// Take returns the pending text and marks it sent.
+func (p *StreamPacer) Take(now time.Time) string {
+	text := p.pending.String()
+	p.pending.Reset()
+	if p.batching {
+		p.batches++
+	}
+	p.lastEmit = now
+	return text
+}
+
+// Batching reports whether updates are currently being consolidated.
+func (p *StreamPacer) Batching() bool {
+	return p.batching
+}
+
+// Batches counts the consolidated updates sent while batching.
+func (p *StreamPacer) Batches() int {
+	return p.batches
+}
+
+// Full returns every chunk seen so far, sent or not.
+func (p *StreamPacer) Full() string {
+	return p.full.String()
+}
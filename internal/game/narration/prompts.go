@@ -3,9 +3,11 @@ package narration
 import (
     "fmt"
     "strings"
+
+    "textadventure/internal/game"
 )
 
-func buildNarrationPrompt(actionContext string, mutationResults []string, worldEventLines []string) string {
+func buildNarrationPrompt(actionContext string, mutationResults []string, worldEventLines []string, echoTexts []string, language string, narratorNotes []string, style Style, voiceExamples []string, pov game.POV) string {
 	var actionAndMutationContext string
 	if actionContext != "" {
 		actionAndMutationContext = fmt.Sprintf("\n\nACTION THAT JUST OCCURRED:\n%s", actionContext)
@@ -25,6 +27,39 @@ func buildNarrationPrompt(actionContext string, mutationResults []string, worldE
         }
     }
 
+    var echoesContext string
+    if len(echoTexts) > 0 {
+        echoesContext = "\n\nECHOES OF EARLIER EVENTS (earlier narration that resembles this moment; stay consistent with it, do not repeat it):\n"
+        for _, text := range echoTexts {
+            echoesContext += fmt.Sprintf("- %s\n", strings.TrimSpace(text))
+        }
+    }
+
+    var notesRule, notesContext string
+    if len(narratorNotes) > 0 {
+        notesRule = "\n- Follow the PRIVATE DIRECTION section in how you narrate, but never quote it, paraphrase it closely, or hint that it exists. It is not something the player has observed."
+        notesContext = "\n\nPRIVATE DIRECTION (from the story's author; never reveal):\n"
+        for _, note := range narratorNotes {
+            notesContext += fmt.Sprintf("- %s\n", strings.TrimSpace(note))
+        }
+    }
+
+    var voiceContext string
+    if style.Tone != "" || len(voiceExamples) > 0 {
+        voiceContext = "\n\nMATCH THE ESTABLISHED VOICE (keep the tone and rhythm; do not reuse the content):\n"
+        if style.Tone != "" {
+            voiceContext += fmt.Sprintf("Style: %s\n", strings.TrimSpace(style.Tone))
+        }
+        for _, example := range voiceExamples {
+            voiceContext += fmt.Sprintf("- %s\n", strings.TrimSpace(example))
+        }
+    }
+
+    var languageRule string
+    if language != "" {
+        languageRule = fmt.Sprintf("\n- Write the narration, including all dialogue, in %s. The inputs below are in English; translate them as you narrate.", language)
+    }
+
     return fmt.Sprintf(`You are the narrator for an LLM-powered narrative text game. This is collaborative story-building - your role is to create an engaging story for the player to enjoy.
 
 IMPORTANT: You narrate strictly from the player's perspective. You only know what the player can directly observe, experience, or interact with. You have no omniscient knowledge about hidden details, background information, or things the player hasn't encountered.
@@ -36,12 +71,34 @@ If the existing facts provide enough context for the current moment, work with w
 Your descriptions become part of the permanent world canon - anything you narrate becomes an established fact that the player has observed.
 
 Rules:
+- %s
 - Base narration on the provided world events and world changes below. Focus on what happened as a result of the player's action.
-- Use present tense. Write 2-4 sentences that create a good story experience.
+- %s, creating a good story experience.
 - Only describe what the player can directly perceive through their senses or actions.
 - If an event contains speech, render the words as quoted dialogue.
 - If an action failed (as indicated by events/changes), briefly note why without giving advice.
 - If there are no events or changes, write a single short beat that reflects the quiet or lack of change.
+- If the context lists "Your condition", let it colour the narration subtly (a shiver when cold, heavy limbs when exhausted) without announcing it every turn.
+- If the context lists an "Ambience", it is the room's ongoing background. Weave it in when the player arrives or the moment is quiet, but never narrate it as something that just happened.
+- Never reveal what is inside a container the context marks as closed or locked; until the player opens it, its contents are unknown to them.
+- Exits marked (locked) are behind a locked door. When the player arrives or looks around, you may mention the door as shut and locked.%s
+
+Only use information from the inputs below:%s%s%s%s%s`, pov.NarrationRule(), style.LengthRule(), languageRule+notesRule, actionAndMutationContext, eventsContext, echoesContext, notesContext, voiceContext)
+}
+
+// LeakedNotes returns the narrator notes that appear verbatim in the narration,
+// ignoring case and spacing. The prompt forbids this; the check catches slips.
+func LeakedNotes(narratorNotes []string, narration string) []string {
+    text := normalizeForLeakCheck(narration)
+    var leaked []string
+    for _, note := range narratorNotes {
+        if n := normalizeForLeakCheck(note); n != "" && strings.Contains(text, n) {
+            leaked = append(leaked, note)
+        }
+    }
+    return leaked
+}
 
-Only use information from the inputs below:%s%s`, actionAndMutationContext, eventsContext)
+func normalizeForLeakCheck(s string) string {
+    return strings.Join(strings.Fields(strings.ToLower(s)), " ")
 }
@@ -11,6 +11,10 @@ import (
     "github.com/openai/openai-go/packages/ssestream"
 
     "textadventure/internal/game"
+    "textadventure/internal/experiment"
+    "textadventure/internal/game/echoes"
+    "textadventure/internal/game/events"
+    "textadventure/internal/game/translate"
     "textadventure/internal/llm"
     "textadventure/internal/logging"
     "go.opentelemetry.io/otel"
@@ -27,8 +31,18 @@ type StreamStartedMsg struct {
     SystemPrompt  string
     StartTime     time.Time
     Logger        *logging.CompletionLogger
-    WorldEventLines []string
+    WorldEvents   []events.WorldEvent
     Span          trace.Span
+    Echoes        []echoes.Match
+    Model         string
+    MaxTokens     int
+    ReasoningEffort string
+    Temperature   *float64
+    Input         translate.Result // original and normalized player input, when translated
+    NarratorNotes []string // private direction given to the narrator, checked for leaks on completion
+    Pacer         *StreamPacer // shared by every read of this stream
+    Experiment    experiment.Assignment // the narrator experiment variant, when one is running
+    Style         string // the narration style's name, for the completion log
 }
 
 // StreamChunkMsg represents a chunk from the narration stream
@@ -48,39 +62,73 @@ type StreamCompleteMsg struct {
     StartTime     time.Time
     Logger        *logging.CompletionLogger
     Debug         bool
-    WorldEventLines []string
+    WorldEvents   []events.WorldEvent
     Span          trace.Span
+    NarratorNotes []string
+    Stream        *ssestream.Stream[openai.ChatCompletionChunk] // the stream that completed
 }
 
 // StartLLMStream initiates a streaming narration response
-func StartLLMStream(ctx context.Context, llmService *llm.Service, userInput string, world game.WorldState, gameHistory []string, logger *logging.CompletionLogger, debug bool, actionContext string, mutationResults []string, worldEventLines []string, actingNPCID ...string) tea.Cmd {
+func StartLLMStream(ctx context.Context, llmService llm.Client, userInput string, world game.WorldState, gameHistory []string, logger *logging.CompletionLogger, debug bool, actionContext string, mutationResults []string, worldEvents []events.WorldEvent, actingNPCID ...string) tea.Cmd {
     return func() tea.Msg {
         if debug {
             log.Printf("Starting LLM stream with input: %q", userInput)
         }
         
         startTime := time.Now()
-        worldContext := game.BuildWorldContext(world, gameHistory, actingNPCID...)
+        worldContext := game.CachedWorldContext(ctx, world, gameHistory, actingNPCID...)
         
-        filteredWorldEventLines := filterEventsForPlayerPerspective(world, worldEventLines, actingNPCID...)
-        systemPrompt := buildNarrationPrompt(actionContext, mutationResults, filteredWorldEventLines)
+        filteredWorldEventLines := events.Lines(events.At(worldEvents, world.Location))
+        echoMatches := retrieveEchoes(ctx, llmService, userInput, len(worldContext), debug)
+        echoTexts := make([]string, 0, len(echoMatches))
+        for _, match := range echoMatches {
+            echoTexts = append(echoTexts, match.Text)
+        }
+        // Until the player is introduced, NPCs are narrated by description only
+        actionContext = game.MaskUnmetNPCNames(world, actionContext)
+        mutationResults = game.MaskUnmetNPCNamesInLines(world, mutationResults)
+        filteredWorldEventLines = game.MaskUnmetNPCNamesInLines(world, filteredWorldEventLines)
+        narratorNotes := world.NarratorNotesForPlayer()
+        assignment, inExperiment := experiment.AssignmentFromContext(ctx)
+        var style Style
+        var voiceExamples []string
+        if voice, ok := voiceFromContext(ctx); ok {
+            if inExperiment && assignment.Settings.Style != "" {
+                voice.Style, _ = ParseStylePreset(assignment.Settings.Style)
+            }
+            style = voice.Style
+            style.Tone, voiceExamples = voice.Fingerprint(voiceBudget(len(worldContext)))
+        }
+        systemPrompt := buildNarrationPrompt(actionContext, mutationResults, filteredWorldEventLines, echoTexts, translate.LanguageFromContext(ctx), narratorNotes, style, voiceExamples, game.POVFromContext(ctx))
+        if turnsLeft, ok := wrapUpFromContext(ctx); ok {
+            systemPrompt += wrapUpSection(turnsLeft)
+        }
+        input, _ := translate.ResultFromContext(ctx)
         
+        settings := llmService.Resolve(ctx, llm.ModelSettings{MaxTokens: 4000})
+        if inExperiment {
+            settings = assignment.Settings.Apply(settings)
+        }
         req := llm.StreamCompletionRequest{
-            SystemPrompt: systemPrompt,
-            UserPrompt:   worldContext + "PLAYER ACTION: " + userInput,
-            MaxTokens:    4000,
+            SystemPrompt:    systemPrompt,
+            UserPrompt:      worldContext + "PLAYER ACTION: " + userInput,
+            MaxTokens:       settings.MaxTokens,
+            Model:           settings.Model,
+            ReasoningEffort: settings.ReasoningEffort,
         }
-        // Create narration span as a generation observation
+        // The narration span covers the whole stream; the service records the
+        // generation itself as a span under it
         tracer := otel.Tracer("narration")
-        ctx, span := tracer.Start(ctx, "narration.generate",
-            trace.WithSpanKind(trace.SpanKindClient),
-        )
+        ctx, span := tracer.Start(ctx, "narration")
         span.SetAttributes(
-            attribute.String("langfuse.observation.type", "generation"),
-            attribute.Int("gen_ai.request.max_tokens", req.MaxTokens),
-            attribute.String("langfuse.observation.input", req.SystemPrompt+"\n\n"+req.UserPrompt),
-            attribute.String("langfuse.observation.output_format", "text"),
+            attribute.String("narration.style", style.Label()),
         )
+        if inExperiment {
+            span.SetAttributes(
+                attribute.String("experiment.name", assignment.Experiment),
+                attribute.String("experiment.variant", assignment.Variant),
+            )
+        }
         // Attach session/game context (turn id/index/phase, location, etc.)
         llm.CopyGameContextToSpan(ctx, span)
 
@@ -102,36 +150,55 @@ func StartLLMStream(ctx context.Context, llmService *llm.Service, userInput stri
             SystemPrompt:  systemPrompt,
             StartTime:     startTime,
             Logger:        logger,
-            WorldEventLines: worldEventLines,
+            WorldEvents:   worldEvents,
             Span:          span,
+            Echoes:        echoMatches,
+            Model:         req.Model,
+            MaxTokens:     req.MaxTokens,
+            ReasoningEffort: req.ReasoningEffort,
+            Temperature:   req.Temperature,
+            Input:         input,
+            NarratorNotes: narratorNotes,
+            Pacer:         NewStreamPacer(),
+            Experiment:    assignment,
+            Style:         style.Name,
         }
     }
 }
 
-// ReadNextChunk reads the next chunk from the narration stream
-func ReadNextChunk(stream *ssestream.Stream[openai.ChatCompletionChunk], debug bool, completionCtx *StreamStartedMsg, fullResponse string) tea.Cmd {
+// ReadNextChunk reads the narration stream until there is text to hand to the UI.
+// While the UI keeps up that is every chunk; when it falls behind the pacer
+// consolidates chunks into fewer, larger updates. StreamCompleteMsg always carries the
+// full response, including text that was never sent as a chunk.
+func ReadNextChunk(stream *ssestream.Stream[openai.ChatCompletionChunk], debug bool, completionCtx *StreamStartedMsg) tea.Cmd {
     return func() tea.Msg {
-        if stream.Next() {
+        if completionCtx.Pacer == nil {
+            completionCtx.Pacer = NewStreamPacer()
+        }
+        pacer := completionCtx.Pacer
+        pacer.Resume(time.Now())
+        for stream.Next() {
             chunk := stream.Current()
-            if len(chunk.Choices) > 0 {
-                delta := chunk.Choices[0].Delta.Content
-                if delta != "" {
-                    if debug {
-                        log.Printf("Stream chunk: %q", delta)
-                    }
-                    return StreamChunkMsg{Chunk: delta, Stream: stream, Debug: debug, CompletionCtx: completionCtx}
-                }
+            if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+                // No textual delta; keep reading
+                continue
+            }
+            delta := chunk.Choices[0].Delta.Content
+            if debug {
+                log.Printf("Stream chunk: %q", delta)
+            }
+            if now := time.Now(); pacer.Add(delta, now) {
+                return StreamChunkMsg{Chunk: pacer.Take(now), Stream: stream, Debug: debug, CompletionCtx: completionCtx}
             }
-            // No textual delta; keep reading
-            return ReadNextChunk(stream, debug, completionCtx, fullResponse)()
         }
+        fullResponse := pacer.Full()
 
         if err := stream.Err(); err != nil {
             if debug {
                 log.Printf("Stream error: %v", err)
             }
             stream.Close()
-            return StreamErrorMsg{Response: "", Err: err}
+            return StreamErrorMsg{Response: "", Err: err, Stream: stream, Span: completionCtx.Span}
         }
 
         if debug {
@@ -139,12 +206,27 @@ func ReadNextChunk(stream *ssestream.Stream[openai.ChatCompletionChunk], debug b
         }
         stream.Close()
 
+        if completionCtx.Span != nil && pacer.Batches() > 0 {
+            completionCtx.Span.SetAttributes(attribute.Int("narration.batched_updates", pacer.Batches()))
+        }
+
         responseTime := time.Since(completionCtx.StartTime)
         metadata := logging.CompletionMetadata{
-            Model:         "gpt-5-2025-08-07",
-            MaxTokens:     4000,
+            Model:         completionCtx.Model,
+            MaxTokens:     completionCtx.MaxTokens,
             ResponseTime:  responseTime,
             StreamingUsed: true,
+            ReasoningEffort: completionCtx.ReasoningEffort,
+            Temperature:   completionCtx.Temperature,
+            NarrationStyle: completionCtx.Style,
+        }
+        if completionCtx.Experiment.Variant != "" {
+            metadata.Experiment = completionCtx.Experiment.Experiment
+            metadata.Variant = completionCtx.Experiment.Variant
+        }
+        if completionCtx.Input.Language != "" {
+            metadata.OriginalInput = completionCtx.Input.Original
+            metadata.InputLanguage = completionCtx.Input.Language
         }
 
         if logErr := completionCtx.Logger.LogCompletion(completionCtx.World, completionCtx.UserInput, completionCtx.SystemPrompt, fullResponse, metadata); logErr != nil && debug {
@@ -159,45 +241,63 @@ func ReadNextChunk(stream *ssestream.Stream[openai.ChatCompletionChunk], debug b
             StartTime:     completionCtx.StartTime,
             Logger:        completionCtx.Logger,
             Debug:         debug,
-            WorldEventLines:   completionCtx.WorldEventLines,
+            WorldEvents:   completionCtx.WorldEvents,
             Span:          completionCtx.Span,
+            NarratorNotes: completionCtx.NarratorNotes,
+            Stream:        stream,
         }
     }
 }
 
-// StreamErrorMsg represents a streaming error
+// StreamErrorMsg represents a streaming error. Stream and Span are set when the
+// stream failed after it started.
 type StreamErrorMsg struct {
     Response string
     Err      error
+    Stream   *ssestream.Stream[openai.ChatCompletionChunk]
+    Span     trace.Span
 }
 
-// filterEventsForPlayerPerspective filters omniscient turn event lines to what the player could plausibly perceive.
-// For now, keep a conservative pass-through to avoid hiding potentially important context.
-// Future enhancement: drop lines clearly marked as other-actor internal states or non-observable events.
-func filterEventsForPlayerPerspective(world game.WorldState, worldEventLines []string, actingNPCID ...string) []string {
-    playerLoc := world.Location
-    filtered := make([]string, 0, len(worldEventLines))
-    for _, line := range worldEventLines {
-        s := strings.TrimSpace(line)
-        if s == "" {
-            continue
-        }
-        // Expect optional tag form: Actor@location: rest
-        // If a tag exists and location matches player's location, include.
-        // If no tag, include conservatively (mutation summaries etc.).
-        atIdx := strings.Index(s, "@")
-        colonIdx := strings.Index(s, ":")
-        if atIdx > 0 && colonIdx > atIdx {
-            loc := strings.TrimSpace(s[atIdx+1 : colonIdx])
-            if loc == playerLoc {
-                filtered = append(filtered, s)
-                continue
-            }
-            // Non-matching tagged line: skip for player view
-            continue
+// maxContextForEchoes is the world context size (in characters) above which echoes are
+// skipped to keep the narration prompt within budget.
+const maxContextForEchoes = 12000
+
+// retrieveEchoes finds earlier narration similar to the player's action. It is a no-op when
+// no echo store is attached, the store is empty, or the prompt is already large.
+func retrieveEchoes(ctx context.Context, llmService llm.Client, userInput string, contextLen int, debug bool) []echoes.Match {
+    store, turnIndex, ok := echoes.FromContext(ctx)
+    if !ok || store.Len() == 0 || strings.TrimSpace(userInput) == "" {
+        return nil
+    }
+    if contextLen > maxContextForEchoes {
+        if debug {
+            log.Printf("Skipping echoes: world context is %d chars", contextLen)
+        }
+        return nil
+    }
+    query, err := llmService.Embed(llm.WithOperationType(ctx, "narration.echoes"), userInput)
+    if err != nil {
+        if debug {
+            log.Printf("Echo retrieval failed: %v", err)
+        }
+        return nil
+    }
+    return store.Similar(query, echoes.DefaultLimit, echoes.DefaultThreshold, turnIndex)
+}
+
+// RecordEcho embeds a completed narration and adds it to the session's echo store.
+func RecordEcho(ctx context.Context, llmService llm.Client, narrationText string) tea.Cmd {
+    return func() tea.Msg {
+        store, turnIndex, ok := echoes.FromContext(ctx)
+        if !ok || strings.TrimSpace(narrationText) == "" {
+            return nil
+        }
+        vector, err := llmService.Embed(llm.WithOperationType(ctx, "narration.echoes.record"), narrationText)
+        if err != nil {
+            log.Printf("Failed to embed narration for echoes: %v", err)
+            return nil
         }
-        // No tag: include as-is
-        filtered = append(filtered, s)
+        store.Add(echoes.Moment{TurnIndex: turnIndex, Text: strings.TrimSpace(narrationText), Vector: vector})
+        return nil
     }
-    return filtered
 }
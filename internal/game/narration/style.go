@@ -0,0 +1,162 @@
+package narration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"textadventure/internal/game"
+)
+
+// Default narration length and tense, for styles that leave them out.
+const (
+	defaultMinSentences = 2
+	defaultMaxSentences = 4
+	defaultTense        = "present"
+)
+
+// Style is how narration is told: how many sentences, in which tense and point of view,
+// and in what tone. Zero fields keep the defaults: 2-4 sentences in the present tense,
+// from the session's point of view (NARRATION_POV), with no particular tone.
+type Style struct {
+	Name         string `json:"name,omitempty"`
+	MinSentences int    `json:"min_sentences,omitempty"`
+	MaxSentences int    `json:"max_sentences,omitempty"`
+	Tense        string `json:"tense,omitempty"`       // "present" or "past"
+	Perspective  string `json:"perspective,omitempty"` // a NARRATION_POV value, e.g. "third:Mara"
+	Tone         string `json:"tone,omitempty"`        // free-form, e.g. "noir" or a sentence describing the voice
+}
+
+// StylePresets are the narration styles NARRATION_STYLE and /style can pick, by name.
+var StylePresets = map[string]Style{
+	"classic":      {Tone: "Measured, vivid prose; concrete sensory detail, no jokes at the player's expense."},
+	"noir":         {MaxSentences: 3, Tone: "Clipped, world-weary sentences; shadows, smoke and bad weather; dry understatement."},
+	"gothic":       {MinSentences: 3, MaxSentences: 5, Tone: "Slow dread; decay, candlelight and old stone; long sentences that tighten when danger is near."},
+	"whimsical":    {Tone: "Light and playful; warm asides and odd little details, but never mocking the player."},
+	"terse":        {MinSentences: 1, MaxSentences: 2, Tone: "Plain and brief; one clear image per sentence, no adjectives that aren't earned."},
+	"cozy":         {Tone: "Warm and unhurried; firelight, small comforts and kindly detail; danger stays at a distance."},
+	"lovecraftian": {MinSentences: 3, MaxSentences: 5, Tense: "past", Tone: "Antiquarian and uneasy; half-glimpsed wrongness, strange geometry and a mind straining not to understand."},
+}
+
+// StylePresetNames returns the preset names, sorted.
+func StylePresetNames() []string {
+	names := make([]string, 0, len(StylePresets))
+	for name := range StylePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseStylePreset returns the named style preset. An empty name is no preset.
+func ParseStylePreset(name string) (Style, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Style{}, nil
+	}
+	if style, ok := StylePresets[name]; ok {
+		style.Name = name
+		return style, nil
+	}
+	return Style{}, fmt.Errorf("unknown narration style %q (want one of %s)", name, strings.Join(StylePresetNames(), ", "))
+}
+
+// LoadStyle reads a style from a JSON file, e.g.
+//
+//	{"name": "campfire", "min_sentences": 3, "max_sentences": 6, "tense": "past", "tone": "a tale told around a campfire"}
+//
+// A style without a name is named after its file.
+func LoadStyle(path string) (Style, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Style{}, fmt.Errorf("read narration style: %w", err)
+	}
+	var style Style
+	if err := json.Unmarshal(data, &style); err != nil {
+		return Style{}, fmt.Errorf("parse narration style %s: %w", path, err)
+	}
+	if strings.TrimSpace(style.Name) == "" {
+		style.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := style.validate(); err != nil {
+		return Style{}, fmt.Errorf("narration style %s: %w", path, err)
+	}
+	return style, nil
+}
+
+// ParseStyle reads a NARRATION_STYLE setting: a preset name, or the path of a JSON style
+// file.
+func ParseStyle(value string) (Style, error) {
+	if value = strings.TrimSpace(value); strings.EqualFold(filepath.Ext(value), ".json") {
+		return LoadStyle(value)
+	}
+	return ParseStylePreset(value)
+}
+
+func (s Style) validate() error {
+	if s.MinSentences < 0 || s.MaxSentences < 0 {
+		return fmt.Errorf("sentence counts can't be negative")
+	}
+	if fewest, most := s.sentences(); fewest > most {
+		return fmt.Errorf("min_sentences %d is above max_sentences %d", fewest, most)
+	}
+	switch strings.ToLower(strings.TrimSpace(s.Tense)) {
+	case "", "present", "past":
+	default:
+		return fmt.Errorf("unknown tense %q (want present or past)", s.Tense)
+	}
+	_, err := game.ParsePOV(s.Perspective)
+	return err
+}
+
+// sentences returns the style's sentence range, filling in the defaults.
+func (s Style) sentences() (int, int) {
+	fewest, most := s.MinSentences, s.MaxSentences
+	if fewest == 0 {
+		fewest = defaultMinSentences
+	}
+	if most == 0 {
+		most = defaultMaxSentences
+	}
+	return fewest, most
+}
+
+// LengthRule is the narration rule for the style's tense and length, e.g. "Use present
+// tense. Write 2-4 sentences".
+func (s Style) LengthRule() string {
+	tense := strings.ToLower(strings.TrimSpace(s.Tense))
+	if tense == "" {
+		tense = defaultTense
+	}
+	sentences := "1 sentence"
+	switch fewest, most := s.sentences(); {
+	case fewest == most && fewest > 1:
+		sentences = fmt.Sprintf("%d sentences", fewest)
+	case fewest != most:
+		sentences = fmt.Sprintf("%d-%d sentences", fewest, most)
+	}
+	return fmt.Sprintf("Use %s tense. Write %s", tense, sentences)
+}
+
+// POV returns the point of view the style narrates from: its perspective if it sets
+// one, otherwise fallback.
+func (s Style) POV(fallback game.POV) game.POV {
+	if strings.TrimSpace(s.Perspective) == "" {
+		return fallback
+	}
+	if pov, err := game.ParsePOV(s.Perspective); err == nil {
+		return pov
+	}
+	return fallback
+}
+
+// Label names the style for logs and /style: its name, or "default" for none.
+func (s Style) Label() string {
+	if s.Name == "" {
+		return "default"
+	}
+	return s.Name
+}
@@ -0,0 +1,93 @@
+package narration
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// voiceParagraphs is how many recent narration paragraphs the voice keeps.
+	voiceParagraphs = 2
+	// VoiceTokenBudget caps the tokens the voice adds to a narration prompt.
+	VoiceTokenBudget = 300
+	// tightVoiceTokenBudget is the budget when the world context is already large,
+	// leaving room for the style's tone but rarely for examples.
+	tightVoiceTokenBudget = 60
+)
+
+// Voice is the narrator's established voice: the configured style and the last few
+// narration paragraphs, shown to the narrator as examples to match. Unlike the history,
+// which is about what happened, it is only about how it is told, so it is kept apart
+// and never trimmed with the history.
+type Voice struct {
+	Style      Style
+	paragraphs []string // most recent last
+}
+
+// Record adds a completed narration's paragraphs, keeping the most recent.
+func (v *Voice) Record(narration string) {
+	var paragraphs []string
+	for _, p := range strings.Split(narration, "\n\n") {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	if len(paragraphs) == 0 {
+		return
+	}
+	kept := append(append([]string{}, v.paragraphs...), paragraphs...)
+	if len(kept) > voiceParagraphs {
+		kept = kept[len(kept)-voiceParagraphs:]
+	}
+	v.paragraphs = kept
+}
+
+// Fingerprint returns the style's tone and the example paragraphs that fit in budget
+// tokens, oldest example first. The tone goes in first; examples are added newest first
+// while they fit whole, so a tight budget drops them rather than cutting one off
+// mid-sentence.
+func (v Voice) Fingerprint(budget int) (string, []string) {
+	tone := ""
+	if v.Style.Tone != "" && approxTokens(v.Style.Tone) <= budget {
+		tone = v.Style.Tone
+		budget -= approxTokens(tone)
+	}
+	var examples []string
+	for i := len(v.paragraphs) - 1; i >= 0; i-- {
+		cost := approxTokens(v.paragraphs[i])
+		if cost > budget {
+			break
+		}
+		budget -= cost
+		examples = append([]string{v.paragraphs[i]}, examples...)
+	}
+	return tone, examples
+}
+
+// approxTokens estimates the tokens in text at about four characters a token.
+func approxTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+type voiceKey struct{}
+
+// WithVoice attaches the narrator's voice for the narration started with ctx. Leave it
+// off for turns whose narration shouldn't echo it, such as a failed action.
+func WithVoice(ctx context.Context, voice Voice) context.Context {
+	return context.WithValue(ctx, voiceKey{}, voice)
+}
+
+// voiceFromContext returns the voice attached by WithVoice.
+func voiceFromContext(ctx context.Context) (Voice, bool) {
+	voice, ok := ctx.Value(voiceKey{}).(Voice)
+	return voice, ok
+}
+
+// voiceBudget is the token budget for the voice, tightened like echoes when the world
+// context is already large.
+func voiceBudget(contextLen int) int {
+	if contextLen > maxContextForEchoes {
+		return tightVoiceTokenBudget
+	}
+	return VoiceTokenBudget
+}
@@ -0,0 +1,36 @@
+package narration
+
+import (
+	"context"
+	"fmt"
+)
+
+type wrapUpKey struct{}
+
+// WithWrapUp marks the narration started with ctx as part of a session's wrap-up, with
+// turnsLeft turns to go after it. Leave it off outside the wrap-up.
+func WithWrapUp(ctx context.Context, turnsLeft int) context.Context {
+	return context.WithValue(ctx, wrapUpKey{}, turnsLeft)
+}
+
+func wrapUpFromContext(ctx context.Context) (int, bool) {
+	turnsLeft, ok := ctx.Value(wrapUpKey{}).(int)
+	return turnsLeft, ok
+}
+
+// wrapUpSection steers a narration toward closure as the session runs out: threads
+// converge over the last few turns, and the final turn is told as an epilogue.
+func wrapUpSection(turnsLeft int) string {
+	if turnsLeft <= 0 {
+		return `
+
+THE SESSION ENDS WITH THIS TURN: after narrating the player's action, close the story with a short epilogue. Resolve what can be resolved, let what can't rest, and end on a settled note. Don't invite another action.`
+	}
+	turns := fmt.Sprintf("%d more turns", turnsLeft)
+	if turnsLeft == 1 {
+		turns = "one more turn"
+	}
+	return fmt.Sprintf(`
+
+THE SESSION IS ENDING (%s after this one): steer toward closure. Draw open threads together and echo earlier moments, and don't introduce new mysteries, characters or places.`, turns)
+}
@@ -0,0 +1,38 @@
+package narration
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWrapUpSection(t *testing.T) {
+	tests := []struct {
+		turnsLeft int
+		want      string
+		not       string
+	}{
+		{3, "THE SESSION IS ENDING (3 more turns after this one)", "epilogue"},
+		{1, "THE SESSION IS ENDING (one more turn after this one)", "epilogue"},
+		{0, "THE SESSION ENDS WITH THIS TURN", "more turn"},
+		{-1, "THE SESSION ENDS WITH THIS TURN", "more turn"},
+	}
+	for _, tt := range tests {
+		section := wrapUpSection(tt.turnsLeft)
+		if !strings.HasPrefix(section, "\n\n") || !strings.Contains(section, tt.want) || strings.Contains(section, tt.not) {
+			t.Errorf("wrapUpSection(%d) = %q, want %q", tt.turnsLeft, section, tt.want)
+		}
+	}
+}
+
+func TestWrapUpContext(t *testing.T) {
+	if _, ok := wrapUpFromContext(context.Background()); ok {
+		t.Error("a bare context is wrapping up")
+	}
+	for _, turnsLeft := range []int{0, 2} {
+		got, ok := wrapUpFromContext(WithWrapUp(context.Background(), turnsLeft))
+		if !ok || got != turnsLeft {
+			t.Errorf("WithWrapUp(%d) read back as %d, %v", turnsLeft, got, ok)
+		}
+	}
+}
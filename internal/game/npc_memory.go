@@ -0,0 +1,56 @@
+package game
+
+import (
+	"context"
+	"fmt"
+)
+
+// NPCMemoryEntry is one of an NPC's recent thoughts or actions and the turn it
+// happened on. Turn is 0 when unknown, as for entries from servers that store plain
+// strings.
+type NPCMemoryEntry struct {
+	Text string
+	Turn int
+}
+
+// RecentMemoryLines formats the newest limit entries for a prompt, oldest first, each
+// prefixed with its age relative to currentTurn ("3 turns ago: ..."). Entries of
+// unknown age, or when currentTurn is unknown (0), are shown as they are. A limit of
+// 0 or less includes every entry.
+func RecentMemoryLines(entries []NPCMemoryEntry, currentTurn, limit int) []string {
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, memoryAge(entry.Turn, currentTurn)+entry.Text)
+	}
+	return lines
+}
+
+func memoryAge(turn, currentTurn int) string {
+	if turn <= 0 || currentTurn <= 0 || turn > currentTurn {
+		return ""
+	}
+	switch age := currentTurn - turn; age {
+	case 0:
+		return "this turn: "
+	case 1:
+		return "1 turn ago: "
+	default:
+		return fmt.Sprintf("%d turns ago: ", age)
+	}
+}
+
+type turnIndexKey struct{}
+
+// WithTurnIndex records the current turn index so prompt builders can date memories.
+func WithTurnIndex(ctx context.Context, turnIndex int) context.Context {
+	return context.WithValue(ctx, turnIndexKey{}, turnIndex)
+}
+
+// TurnIndexFromContext returns the turn index set by WithTurnIndex, or 0.
+func TurnIndexFromContext(ctx context.Context) int {
+	turnIndex, _ := ctx.Value(turnIndexKey{}).(int)
+	return turnIndex
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// UnmetAliasPrefix starts the alias tokens that stand in for NPCs the player has not
+// met yet, so the director can target them without learning their names.
+const UnmetAliasPrefix = "person_"
+
+// NPCReference is how an unmet NPC is presented to the director: by what the player
+// can see and a token that resolves to the real NPC ID Go-side.
+type NPCReference struct {
+	Alias       string
+	NPCID       string
+	Description string
+	Location    string
+}
+
+// HasMetNPC reports whether the player has learned the NPC's name.
+func HasMetNPC(world WorldState, npcID string) bool {
+	for _, met := range world.MetNPCs {
+		if met == npcID {
+			return true
+		}
+	}
+	return false
+}
+
+// NPCDescription is the public description of an NPC, falling back to "someone".
+func NPCDescription(world WorldState, npcID string) string {
+	if npc, ok := world.NPCs[npcID]; ok && strings.TrimSpace(npc.Description) != "" {
+		return npc.Description
+	}
+	return "someone"
+}
+
+// UnmetNPCReferences lists every NPC the player has not met, ordered by NPC ID so the
+// aliases are stable for a given world state.
+func UnmetNPCReferences(world WorldState) []NPCReference {
+	ids := make([]string, 0, len(world.NPCs))
+	for id := range world.NPCs {
+		if !HasMetNPC(world, id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	refs := make([]NPCReference, 0, len(ids))
+	for i, id := range ids {
+		refs = append(refs, NPCReference{
+			Alias:       fmt.Sprintf("%s%d", UnmetAliasPrefix, i+1),
+			NPCID:       id,
+			Description: NPCDescription(world, id),
+			Location:    world.NPCs[id].Location,
+		})
+	}
+	return refs
+}
+
+// ResolveNPCAlias maps an unmet-NPC alias to the real NPC ID. Anything that isn't a
+// current alias is reported as not resolved.
+func ResolveNPCAlias(world WorldState, ref string) (string, bool) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if !strings.HasPrefix(ref, UnmetAliasPrefix) {
+		return "", false
+	}
+	for _, unmet := range UnmetNPCReferences(world) {
+		if unmet.Alias == ref {
+			return unmet.NPCID, true
+		}
+	}
+	return "", false
+}
+
+// ResolveNPCID maps an NPC reference from the director (an NPC ID or an unmet alias)
+// to the NPC ID. References to nobody are errors; the error never names unmet NPCs.
+// With no NPC registry the reference is returned unchanged for the server to judge.
+func ResolveNPCID(world WorldState, ref string) (string, error) {
+	if npcID, ok := ResolveNPCAlias(world, ref); ok {
+		return npcID, nil
+	}
+	if len(world.NPCs) == 0 {
+		return ref, nil
+	}
+	if _, ok := world.NPCs[ref]; ok {
+		return ref, nil
+	}
+	if id := strings.ToLower(strings.TrimSpace(ref)); id != "" {
+		if _, ok := world.NPCs[id]; ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("no such person %q; refer to people the player hasn't met by their alias", ref)
+}
+
+// MaskUnmetNPCNames replaces the IDs of NPCs the player has not met with their public
+// description, so text bound for the narrator can't give a name away early.
+func MaskUnmetNPCNames(world WorldState, text string) string {
+	for _, unmet := range UnmetNPCReferences(world) {
+		pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(unmet.NPCID) + `\b`)
+		text = pattern.ReplaceAllLiteralString(text, unmet.Description)
+	}
+	return text
+}
+
+// MaskUnmetNPCNamesInLines applies MaskUnmetNPCNames to each line.
+func MaskUnmetNPCNamesInLines(world WorldState, lines []string) []string {
+	if len(lines) == 0 {
+		return lines
+	}
+	masked := make([]string, len(lines))
+	for i, line := range lines {
+		masked[i] = MaskUnmetNPCNames(world, line)
+	}
+	return masked
+}
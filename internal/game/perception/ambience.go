@@ -0,0 +1,29 @@
+package perception
+
+import (
+	"textadventure/internal/game"
+	"textadventure/internal/game/events"
+)
+
+// AmbienceEvent returns the NPC perceiving its room's ambience, if it hasn't perceived
+// it there yet (see game.AmbienceDue). The caller records it as heard.
+func AmbienceEvent(world game.WorldState, npcID string) (events.WorldEvent, bool) {
+	ambience, ok := game.AmbienceDue(world, npcID)
+	if !ok {
+		return events.WorldEvent{}, false
+	}
+	return events.New(events.EventAmbience, "ambience", world.NPCs[npcID].Location, ambience), true
+}
+
+// withoutAmbience drops events that only restate the ambience of where they happened,
+// so NPCs don't react to the same background every turn.
+func withoutAmbience(world game.WorldState, worldEvents []events.WorldEvent) []events.WorldEvent {
+	out := make([]events.WorldEvent, 0, len(worldEvents))
+	for _, e := range worldEvents {
+		if e.Type != events.EventAmbience && game.IsAmbience(world, e.Location, e.Content) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
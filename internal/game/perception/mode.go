@@ -0,0 +1,47 @@
+package perception
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Mode chooses how NPC perception is decided.
+type Mode string
+
+const (
+	// ModeAuto decides obvious events by rule and asks the LLM about the rest.
+	ModeAuto Mode = "auto"
+	// ModeRules decides by rule alone; events the rules can't place aren't perceived.
+	ModeRules Mode = "rules"
+	// ModeLLM asks the LLM about every event, as before the rules existed.
+	ModeLLM Mode = "llm"
+)
+
+// ParseMode reads a mode name, case-insensitively. An empty name is ModeAuto.
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "":
+		return ModeAuto, nil
+	case ModeAuto, ModeRules, ModeLLM:
+		return mode, nil
+	default:
+		return ModeAuto, fmt.Errorf("unknown perception mode %q (expected auto, rules or llm)", name)
+	}
+}
+
+type modeKey struct{}
+
+// WithMode forces perception for calls made with ctx down one path, for comparing the
+// rules with the LLM.
+func WithMode(ctx context.Context, mode Mode) context.Context {
+	return context.WithValue(ctx, modeKey{}, mode)
+}
+
+// ModeFromContext returns the mode set by WithMode, or ModeAuto.
+func ModeFromContext(ctx context.Context) Mode {
+	if mode, ok := ctx.Value(modeKey{}).(Mode); ok && mode != "" {
+		return mode
+	}
+	return ModeAuto
+}
@@ -8,21 +8,116 @@ import (
     "strings"
 
     "textadventure/internal/game"
+    "textadventure/internal/game/events"
+    "textadventure/internal/game/sensory"
     "textadventure/internal/llm"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
 )
 
-// GeneratePerceivedEventsForNPC asks the LLM to select which of the given
-// world event lines this NPC would reasonably perceive, given the current world state.
-// Returns a slice of lines (subset of input), with no inventions.
-func GeneratePerceivedEventsForNPC(ctx context.Context, llmService *llm.Service, npcID string, world game.WorldState, worldEventLines []string, debug bool) ([]string, error) {
-    if len(worldEventLines) == 0 {
-        return []string{}, nil
+// GeneratePerceivedEventsForNPC selects which of the given world events this NPC would
+// reasonably perceive, given the current world state. Rules decide the obvious cases
+// (see perceiveByRules) and the LLM the rest, unless ctx forces one path (see WithMode).
+// Returns a subset of the input events, with no inventions; speech heard from the next
+// room is annotated as such. Events that only restate a room's ambience are never
+// perceived; see AmbienceEvent for how an NPC takes the ambience in.
+func GeneratePerceivedEventsForNPC(ctx context.Context, llmService llm.Completer, npcID string, world game.WorldState, worldEvents []events.WorldEvent, debug bool) ([]events.WorldEvent, error) {
+    worldEvents = withoutAmbience(world, worldEvents)
+    if len(worldEvents) == 0 {
+        return []events.WorldEvent{}, nil
+    }
+    span := trace.SpanFromContext(ctx)
+    mode := ModeFromContext(ctx)
+    if mode == ModeLLM {
+        span.SetAttributes(attribute.String("perception.path", "llm"))
+        return perceiveWithLLM(ctx, llmService, npcID, world, worldEvents, debug)
+    }
+
+    decided, ambiguous := perceiveByRules(npcID, world, worldEvents)
+    span.SetAttributes(
+        attribute.Int("perception.rule_decided_count", len(worldEvents)-len(ambiguous)),
+        attribute.Int("perception.ambiguous_count", len(ambiguous)),
+    )
+    if len(ambiguous) == 0 || mode == ModeRules {
+        span.SetAttributes(attribute.String("perception.path", "rules"))
+        return perceivedInOrder(worldEvents, decided, nil), nil
     }
 
-    worldCtx := game.BuildWorldContext(world, []string{}, npcID)
+    span.SetAttributes(attribute.String("perception.path", "rules+llm"))
+    ambiguousEvents := make([]events.WorldEvent, 0, len(ambiguous))
+    for _, i := range ambiguous {
+        ambiguousEvents = append(ambiguousEvents, worldEvents[i])
+    }
+    fromLLM, err := perceiveWithLLM(ctx, llmService, npcID, world, ambiguousEvents, debug)
+    return perceivedInOrder(worldEvents, decided, fromLLM), err
+}
+
+// perceiveByRules decides the events whose answer is obvious from where they happened:
+// the NPC perceives everything in its own room and speech from an adjacent one, and
+// nothing further away. decided maps the index of every event perceived by rule to
+// what the NPC perceives; ambiguous lists the indices of events without a location, or
+// all of them when the NPC's own location is unknown.
+func perceiveByRules(npcID string, world game.WorldState, worldEvents []events.WorldEvent) (map[int]events.WorldEvent, []int) {
+    decided := make(map[int]events.WorldEvent)
+    var ambiguous []int
+    npcLoc := world.NPCs[npcID].Location
+    _, known := world.Locations[npcLoc]
+    for i, e := range worldEvents {
+        if e.Location == "" || !known {
+            ambiguous = append(ambiguous, i)
+            continue
+        }
+        if e.Location == npcLoc {
+            decided[i] = e
+            continue
+        }
+        speech := e.Type == events.EventSpeech || isSpeechLike(strings.ToLower(e.Content))
+        if speech && sensory.CalculateRoomDistance(npcLoc, e.Location, world.Locations) == 1 {
+            e.Content = fmt.Sprintf("%s (heard from %s)", e.Content, game.Mention(world.Locations[e.Location].Name, e.Location))
+            decided[i] = e
+        }
+    }
+    return decided, ambiguous
+}
+
+// perceivedInOrder merges what the rules decided with what the LLM selected, in the
+// order the events happened.
+func perceivedInOrder(worldEvents []events.WorldEvent, decided map[int]events.WorldEvent, fromLLM []events.WorldEvent) []events.WorldEvent {
+    selected := make(map[string]bool, len(fromLLM))
+    for _, e := range fromLLM {
+        selected[e.ID] = true
+    }
+    out := make([]events.WorldEvent, 0, len(decided)+len(fromLLM))
+    for i, e := range worldEvents {
+        if perceived, ok := decided[i]; ok {
+            out = append(out, perceived)
+        } else if selected[e.ID] {
+            out = append(out, e)
+            delete(selected, e.ID)
+        }
+    }
+    return out
+}
+
+// perceiveWithLLM asks the LLM to select which of the given world events this NPC would
+// reasonably perceive. Speech-like events in this and adjacent rooms are always added.
+func perceiveWithLLM(ctx context.Context, llmService llm.Completer, npcID string, world game.WorldState, worldEvents []events.WorldEvent, debug bool) ([]events.WorldEvent, error) {
+    // The model picks events by their rendered line
+    byLine := make(map[string]events.WorldEvent, len(worldEvents))
+    worldEventLines := make([]string, 0, len(worldEvents))
+    for _, e := range worldEvents {
+        line := strings.TrimSpace(e.Line())
+        if _, dup := byLine[line]; line == "" || dup {
+            continue
+        }
+        byLine[line] = e
+        worldEventLines = append(worldEventLines, line)
+    }
+
+    worldCtx := game.CachedWorldContext(ctx, world, []string{}, npcID)
 
     sb := &strings.Builder{}
-    fmt.Fprintf(sb, "NPC: %s\n\n", npcID)
+    fmt.Fprintf(sb, "NPC: %s\n\n", game.Mention(game.NPCName(npcID), npcID))
     fmt.Fprintf(sb, "WORLD SNAPSHOT (for reasoning):\n%s\n\n", worldCtx)
     fmt.Fprintf(sb, "EVENT LINES:\n%s\n", strings.Join(worldEventLines, "\n"))
 
@@ -47,7 +142,7 @@ Given a world snapshot and a list of canonical event lines from this turn, selec
 Rules:
 - Return a JSON object with an "events" array containing strings strictly chosen from the provided event lines.
 - Do not invent or paraphrase; copy the exact lines that would be perceived.
-- Event lines may include tags of the form "Actor@location: ...". Prefer selecting lines where the location matches the NPC's current room.
+- Event lines may include tags of the form "actor@location: ...", using lowercase ids. Prose names an entity by its display name with its id in parentheses on first mention, e.g. "Elena (elena)"; both refer to the same entity. Prefer selecting lines where the location matches the NPC's current room.
 - Consider location, proximity, and what could be seen or heard (e.g., speech may carry to nearby rooms; be conservative).
 - If nothing is perceived, return {"events": []}`,
         UserPrompt:      sb.String(),
@@ -61,7 +156,7 @@ Rules:
     ctx = llm.WithOperationType(ctx, "npc.perceive")
     content, err := llmService.CompleteJSONSchema(ctx, req)
     if err != nil {
-        return []string{}, err
+        return []events.WorldEvent{}, err
     }
 
     if debug {
@@ -76,57 +171,43 @@ Rules:
         response.Events = []string{}
     } else if jerr := json.Unmarshal([]byte(content), &response); jerr != nil {
         // JSON schema should prevent malformed responses, but handle gracefully
-        return []string{}, fmt.Errorf("failed to parse perception response: %w", jerr)
+        return []events.WorldEvent{}, fmt.Errorf("failed to parse perception response: %w", jerr)
     }
     
-    arr := response.Events
     // Ensure we only return exact matches from input (defensive)
-    allowed := make(map[string]struct{}, len(worldEventLines))
-    for _, l := range worldEventLines {
-        allowed[strings.TrimSpace(l)] = struct{}{}
-    }
     selected := make(map[string]struct{})
-    out := make([]string, 0, len(arr))
-    for _, l := range arr {
+    out := make([]events.WorldEvent, 0, len(response.Events))
+    for _, l := range response.Events {
         s := strings.TrimSpace(l)
-        if _, ok := allowed[s]; ok {
+        if e, ok := byLine[s]; ok {
             if _, seen := selected[s]; !seen {
                 selected[s] = struct{}{}
-                out = append(out, s)
+                out = append(out, e)
             }
         }
     }
 
-    // Deterministic addition: include speech-like attempts from adjacent rooms
+    // Deterministic addition: include speech-like attempts from this and adjacent rooms
     npcLoc := world.NPCs[npcID].Location
     adj := make(map[string]struct{})
     if loc, ok := world.Locations[npcLoc]; ok {
         for _, v := range loc.Exits { adj[v] = struct{}{} }
     }
-    for _, l := range worldEventLines {
-        s := strings.TrimSpace(l)
-        at := strings.Index(s, "@")
-        colon := strings.Index(s, ":")
-        if at > 0 && colon > at {
-            locTag := strings.TrimSpace(s[at+1 : colon])
-            content := strings.TrimSpace(s[colon+1:])
-            lc := strings.ToLower(content)
-            if _, ok := allowed[s]; ok {
-                if locTag == npcLoc {
-                    // already same room, it should have been selected by LLM if relevant; keep union semantics
-                    if _, seen := selected[s]; !seen && isSpeechLike(lc) {
-                        selected[s] = struct{}{}
-                        out = append(out, s)
-                    }
-                    continue
-                }
-                if _, isAdj := adj[locTag]; isAdj && isSpeechLike(lc) {
-                    if _, seen := selected[s]; !seen {
-                        selected[s] = struct{}{}
-                        out = append(out, s)
-                    }
-                }
-            }
+    for _, line := range worldEventLines {
+        e := byLine[line]
+        if e.Location == "" {
+            continue
+        }
+        if _, isAdj := adj[e.Location]; e.Location != npcLoc && !isAdj {
+            continue
+        }
+        if e.Type != events.EventSpeech && !isSpeechLike(strings.ToLower(e.Content)) {
+            continue
+        }
+        // Same-room speech should have been selected by the LLM if relevant; keep union semantics
+        if _, seen := selected[line]; !seen {
+            selected[line] = struct{}{}
+            out = append(out, e)
         }
     }
 
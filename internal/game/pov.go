@@ -0,0 +1,92 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Person is the grammatical person narration is told in.
+type Person int
+
+const (
+	// SecondPerson addresses the player: "You step into the study."
+	SecondPerson Person = iota
+	// FirstPerson speaks as the player character: "I step into the study."
+	FirstPerson
+	// ThirdPerson follows a named protagonist: "Mara steps into the study."
+	ThirdPerson
+)
+
+// POV is the narrative point of view: the person narration is told in and, in the third
+// person, the protagonist's name. The zero value is second person.
+type POV struct {
+	Person      Person
+	Protagonist string
+}
+
+func (p POV) String() string {
+	switch p.Person {
+	case FirstPerson:
+		return "first"
+	case ThirdPerson:
+		return "third:" + p.Protagonist
+	}
+	return "second"
+}
+
+// ParsePOV reads a point-of-view setting: "second" (or empty), "first", or "third:Name"
+// for the third person following Name.
+func ParsePOV(value string) (POV, error) {
+	person, name, _ := strings.Cut(strings.TrimSpace(value), ":")
+	switch strings.ToLower(strings.TrimSpace(person)) {
+	case "", "second":
+		return POV{}, nil
+	case "first":
+		return POV{Person: FirstPerson}, nil
+	case "third":
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return POV{}, fmt.Errorf("third-person narration needs a protagonist, e.g. third:Mara")
+		}
+		return POV{Person: ThirdPerson, Protagonist: name}, nil
+	}
+	return POV{}, fmt.Errorf("unknown point of view %q (want second, first or third:Name)", value)
+}
+
+// NarrationRule tells a narrator which person to narrate the player character in.
+func (p POV) NarrationRule() string {
+	switch p.Person {
+	case FirstPerson:
+		return `Narrate in the first person, as the player character ("I step into the study", "my hands are cold"). Never address the player as "you".`
+	case ThirdPerson:
+		return fmt.Sprintf(`Narrate in the third person, following the player character, %s ("%s steps into the study"). Never address the player as "you" or narrate as "I".`, p.Protagonist, p.Protagonist)
+	}
+	return `Narrate in the second person, addressing the player character as "you" ("You step into the study").`
+}
+
+// GuideRule tells the guide how to phrase an answer about the player character, so
+// hints match the narration.
+func (p POV) GuideRule() string {
+	switch p.Person {
+	case FirstPerson:
+		return `Answer in the first person, as the player character thinking it through ("I am...", "I could...")`
+	case ThirdPerson:
+		return fmt.Sprintf(`Answer in the third person about the player character, %s ("%s is...", "%s could...")`, p.Protagonist, p.Protagonist, p.Protagonist)
+	}
+	return `Answer the player's question in second person ("You are...", "You could...")`
+}
+
+type povKey struct{}
+
+// WithPOV sets the point of view narration, hints and fact extraction assume for calls
+// made with ctx.
+func WithPOV(ctx context.Context, pov POV) context.Context {
+	return context.WithValue(ctx, povKey{}, pov)
+}
+
+// POVFromContext returns the point of view set by WithPOV, second person if none was.
+func POVFromContext(ctx context.Context) POV {
+	pov, _ := ctx.Value(povKey{}).(POV)
+	return pov
+}
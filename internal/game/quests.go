@@ -0,0 +1,25 @@
+package game
+
+// QuestActive is the status of a quest the player is still pursuing.
+const QuestActive = "active"
+
+// Quest is something the scenario has set the player to do. Objective says what
+// finishing it takes, in the scenario author's words.
+type Quest struct {
+	ID        string
+	Title     string
+	Objective string
+	Status    string
+}
+
+// ActiveQuests returns the quests the player is still pursuing, in scenario order. A
+// quest without a status counts as active.
+func ActiveQuests(world WorldState) []Quest {
+	var active []Quest
+	for _, quest := range world.Quests {
+		if quest.Status == "" || quest.Status == QuestActive {
+			active = append(active, quest)
+		}
+	}
+	return active
+}
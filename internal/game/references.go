@@ -0,0 +1,94 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prompts refer to entities in one canonical format so the model never sees the same
+// NPC as "elena", "ELENA" and "NPC elena" in one request. Prose names an entity by its
+// display name, with the id in parentheses on first mention ("Elena (elena)"); machine-
+// facing lines such as event tags and action contexts use the bare lowercase id.
+
+// RefID returns the machine-facing form of an entity id: trimmed and lowercase.
+func RefID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// NPCName returns an NPC's display name. NPCs have no separate name, so it is the id
+// with its first letter capitalized, matching how speech lines are written.
+func NPCName(npcID string) string {
+	id := RefID(npcID)
+	if id == "" {
+		return ""
+	}
+	return strings.ToUpper(id[:1]) + id[1:]
+}
+
+// DisplayName returns the prose name of a location, item or NPC. Locations and items
+// use their Name when set; anything else falls back to NPCName.
+func DisplayName(world WorldState, id string) string {
+	name := ""
+	if loc, ok := world.Locations[id]; ok {
+		name = loc.Name
+	} else if item, ok := world.Items[id]; ok {
+		name = item.Name
+	}
+	if strings.TrimSpace(name) == "" {
+		return NPCName(id)
+	}
+	name = strings.TrimSpace(name)
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// Mention renders the first mention of an entity: its display name followed by its id
+// in parentheses.
+func Mention(name, id string) string {
+	id = RefID(id)
+	if name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", name, id)
+}
+
+// ActionLabel names whose action a prompt is interpreting: "Player action" or, for an
+// NPC, "NPC Elena (elena) action".
+func ActionLabel(actingNPCID string) string {
+	if actingNPCID == "" {
+		return "Player action"
+	}
+	return fmt.Sprintf("NPC %s action", Mention(NPCName(actingNPCID), actingNPCID))
+}
+
+// References tracks which entities a prompt has already named, so only the first
+// mention carries the id. Use one per built prompt.
+type References struct {
+	world     WorldState
+	mentioned map[string]bool
+}
+
+// NewReferences creates a tracker for one prompt over the given world.
+func NewReferences(world WorldState) *References {
+	return &References{world: world, mentioned: make(map[string]bool)}
+}
+
+// Ref returns the entity's first-mention form the first time it is called for an id
+// and its display name afterwards.
+func (r *References) Ref(id string) string {
+	key := RefID(id)
+	name := DisplayName(r.world, id)
+	if r.mentioned[key] {
+		return name
+	}
+	r.mentioned[key] = true
+	return Mention(name, key)
+}
+
+// Refs applies Ref to each id in order.
+func (r *References) Refs(ids []string) []string {
+	refs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, r.Ref(id))
+	}
+	return refs
+}
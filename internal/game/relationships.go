@@ -0,0 +1,71 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxAffinity bounds an NPC's affinity for the player, from -MaxAffinity (hostile)
+	// to MaxAffinity (trusting).
+	MaxAffinity = 10
+	// MaxAffinityChange is how far one interaction can move affinity.
+	MaxAffinityChange = 3
+	// MaxInteractions is how many notable interactions a relationship keeps, newest last.
+	MaxInteractions = 5
+)
+
+// Relationship is how an NPC regards the player: an affinity score and the notable
+// interactions that moved it.
+type Relationship struct {
+	Affinity     int
+	Interactions []string
+}
+
+// IsZero reports whether nothing has happened between the NPC and the player yet.
+func (r Relationship) IsZero() bool {
+	return r.Affinity == 0 && len(r.Interactions) == 0
+}
+
+// UpdateRelationship returns the relationship after an interaction that changed
+// affinity by delta, kept within ±MaxAffinity, remembering the interaction.
+func UpdateRelationship(r Relationship, delta int, interaction string) Relationship {
+	r.Affinity = max(-MaxAffinity, min(MaxAffinity, r.Affinity+delta))
+	interactions := append([]string(nil), r.Interactions...)
+	if interaction = strings.TrimSpace(interaction); interaction != "" {
+		interactions = append(interactions, interaction)
+	}
+	if len(interactions) > MaxInteractions {
+		interactions = interactions[len(interactions)-MaxInteractions:]
+	}
+	r.Interactions = interactions
+	return r
+}
+
+// Attitude describes an affinity in words, e.g. "wary of the player".
+func Attitude(affinity int) string {
+	switch {
+	case affinity <= -6:
+		return "hostile toward the player"
+	case affinity <= -2:
+		return "wary of the player"
+	case affinity >= 6:
+		return "trusts the player"
+	case affinity >= 2:
+		return "warm toward the player"
+	}
+	return "neutral toward the player"
+}
+
+// FormatRelationship renders an NPC's attitude toward the player for a prompt, with the
+// interactions behind it. It returns an empty string when nothing has happened yet.
+func FormatRelationship(r Relationship) string {
+	if r.IsZero() {
+		return ""
+	}
+	text := Attitude(r.Affinity)
+	if len(r.Interactions) > 0 {
+		text += fmt.Sprintf(" (%s)", strings.Join(r.Interactions, "; "))
+	}
+	return text
+}
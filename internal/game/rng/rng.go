@@ -0,0 +1,156 @@
+// Package rng is the single source of game randomness. Each consumer draws from its own
+// named stream (For("weather"), For("tables")) derived from one master seed, so a run
+// is reproducible from its seed and adding draws to one stream, or a new stream
+// altogether, never shifts the values another stream produces.
+package rng
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	mu      sync.Mutex
+	master  = time.Now().UnixNano()
+	streams = map[string]*Stream{}
+)
+
+// Seed sets the master seed and restarts every stream from it. Call it before
+// anything draws, normally from the --seed flag at startup.
+func Seed(seed int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	master = seed
+	streams = map[string]*Stream{}
+}
+
+// MasterSeed returns the seed the streams derive from, so a run can be reproduced.
+func MasterSeed() int64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return master
+}
+
+// For returns the named stream for the current master seed, creating it on first use.
+func For(name string) *Stream {
+	mu.Lock()
+	defer mu.Unlock()
+	stream, ok := streams[name]
+	if !ok {
+		stream = New(master, name)
+		streams[name] = stream
+	}
+	return stream
+}
+
+// New returns a stream for name derived from seed, independent of the package-level
+// streams. The same seed and name always give the same sequence.
+func New(seed int64, name string) *Stream {
+	return &Stream{r: rand.New(rand.NewSource(deriveSeed(seed, name)))}
+}
+
+// deriveSeed mixes the stream name into the master seed (FNV-1a, then a splitmix64
+// finaliser) so similar names and adjacent seeds give unrelated streams.
+func deriveSeed(seed int64, name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	z := uint64(seed) ^ h.Sum64()
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// Stream is one named sequence of random values. It is safe for concurrent use.
+type Stream struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// Float64 returns a value in [0, 1).
+func (s *Stream) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Float64()
+}
+
+// Intn returns a value in [0, n). It panics if n <= 0.
+func (s *Stream) Intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Intn(n)
+}
+
+// Chance returns true with probability p.
+func (s *Stream) Chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	return s.Float64() < p
+}
+
+// Shuffle randomises the order of n elements using swap.
+func (s *Stream) Shuffle(n int, swap func(i, j int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.r.Shuffle(n, swap)
+}
+
+// WeightedIndex picks an index with probability proportional to its weight. Negative
+// weights count as zero; it returns -1 if no weight is positive.
+func (s *Stream) WeightedIndex(weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	target := s.Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		last = i
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	// Rounding can leave target just past the end; the last positive weight gets it
+	return last
+}
+
+// Pick returns a uniformly chosen element of items, or false if items is empty.
+func Pick[T any](s *Stream, items []T) (T, bool) {
+	var zero T
+	if len(items) == 0 {
+		return zero, false
+	}
+	return items[s.Intn(len(items))], true
+}
+
+// WeightedPick returns an element chosen with probability proportional to weight, or
+// false if no element has a positive weight.
+func WeightedPick[T any](s *Stream, items []T, weight func(T) float64) (T, bool) {
+	weights := make([]float64, len(items))
+	for i, item := range items {
+		weights[i] = weight(item)
+	}
+	i := s.WeightedIndex(weights)
+	if i < 0 {
+		var zero T
+		return zero, false
+	}
+	return items[i], true
+}
+
+// ShuffleSlice randomises items in place.
+func ShuffleSlice[T any](s *Stream, items []T) {
+	s.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+}
@@ -0,0 +1,116 @@
+package rng
+
+import (
+	"reflect"
+	"testing"
+)
+
+func draws(s *Stream, n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = s.Intn(1000000)
+	}
+	return out
+}
+
+func TestSameSeedSameSequence(t *testing.T) {
+	a := draws(New(42, "weather"), 20)
+	b := draws(New(42, "weather"), 20)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed and name gave %v and %v", a, b)
+	}
+	if reflect.DeepEqual(a, draws(New(43, "weather"), 20)) {
+		t.Error("adjacent seeds gave the same sequence")
+	}
+	if reflect.DeepEqual(a, draws(New(42, "tables"), 20)) {
+		t.Error("different stream names gave the same sequence")
+	}
+}
+
+func TestStreamsAreIndependent(t *testing.T) {
+	Seed(7)
+	want := draws(For("tables"), 10)
+
+	// Drawing from another stream, or creating a new one, doesn't shift "tables".
+	Seed(7)
+	draws(For("weather"), 50)
+	For("chaos").Float64()
+	tables := For("tables")
+	draws(For("weather"), 50)
+	if got := draws(tables, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("tables after other draws = %v, want %v", got, want)
+	}
+}
+
+func TestForReturnsTheSameStream(t *testing.T) {
+	Seed(1)
+	if For("weather") != For("weather") {
+		t.Error("For returned different streams for one name")
+	}
+	first := For("weather").Intn(1000000)
+	Seed(1)
+	if got := For("weather").Intn(1000000); got != first {
+		t.Errorf("reseeding gave %d, want %d", got, first)
+	}
+	if MasterSeed() != 1 {
+		t.Errorf("MasterSeed() = %d, want 1", MasterSeed())
+	}
+}
+
+func TestForMatchesNew(t *testing.T) {
+	Seed(99)
+	if got, want := draws(For("fairness"), 5), draws(New(99, "fairness"), 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("For = %v, New = %v", got, want)
+	}
+}
+
+func TestHelpersAreDeterministic(t *testing.T) {
+	run := func() ([]string, []int, string) {
+		s := New(5, "helpers")
+		items := []string{"a", "b", "c", "d", "e"}
+		ShuffleSlice(s, items)
+		var picks []int
+		for i := 0; i < 10; i++ {
+			picks = append(picks, s.WeightedIndex([]float64{1, 0, 3, -2, 2}))
+		}
+		pick, _ := Pick(s, items)
+		return items, picks, pick
+	}
+	items1, picks1, pick1 := run()
+	items2, picks2, pick2 := run()
+	if !reflect.DeepEqual(items1, items2) || !reflect.DeepEqual(picks1, picks2) || pick1 != pick2 {
+		t.Error("the same seed gave different helper results")
+	}
+	for _, i := range picks1 {
+		if i == 1 || i == 3 {
+			t.Errorf("picked index %d with a non-positive weight", i)
+		}
+	}
+}
+
+func TestWeightedEdgeCases(t *testing.T) {
+	s := New(1, "edges")
+	if got := s.WeightedIndex(nil); got != -1 {
+		t.Errorf("WeightedIndex(nil) = %d, want -1", got)
+	}
+	if got := s.WeightedIndex([]float64{0, -1}); got != -1 {
+		t.Errorf("WeightedIndex without positive weights = %d, want -1", got)
+	}
+	for i := 0; i < 20; i++ {
+		if got := s.WeightedIndex([]float64{0, 5, 0}); got != 1 {
+			t.Fatalf("WeightedIndex with one positive weight = %d, want 1", got)
+		}
+	}
+	if _, ok := Pick(s, []int{}); ok {
+		t.Error("Pick from an empty slice succeeded")
+	}
+	if _, ok := WeightedPick(s, []string{"x"}, func(string) float64 { return 0 }); ok {
+		t.Error("WeightedPick without positive weights succeeded")
+	}
+	if s.Chance(0) {
+		t.Error("Chance(0) returned true")
+	}
+	if !s.Chance(1) {
+		t.Error("Chance(1) returned false")
+	}
+}
@@ -0,0 +1,43 @@
+package game
+
+import "sort"
+
+const (
+	// MaxScheduleDelay is the furthest ahead, in turns, an event can be scheduled.
+	MaxScheduleDelay = 20
+	// MaxScheduledMutations caps the mutations a single scheduled event can carry.
+	MaxScheduledMutations = 5
+)
+
+// ScheduledMutation is a director tool call held until its event fires.
+type ScheduledMutation struct {
+	Tool string
+	Args map[string]interface{}
+}
+
+// ScheduledEvent is a delayed consequence of an earlier action, such as a lit fuse.
+// TurnsLeft counts down once per turn; the event fires on the turn it reaches zero.
+// ID orders events scheduled for the same turn, earliest scheduled first.
+type ScheduledEvent struct {
+	ID          int
+	TurnsLeft   int
+	Description string
+	Location    string
+	Mutations   []ScheduledMutation
+}
+
+// AdvanceScheduledEvents counts every pending event down by one turn. It returns the
+// events still pending and the events that fire this turn, the latter in firing order.
+func AdvanceScheduledEvents(events []ScheduledEvent) ([]ScheduledEvent, []ScheduledEvent) {
+	var pending, due []ScheduledEvent
+	for _, event := range events {
+		event.TurnsLeft--
+		if event.TurnsLeft <= 0 {
+			due = append(due, event)
+			continue
+		}
+		pending = append(pending, event)
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	return pending, due
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAdvanceScheduledEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []ScheduledEvent
+		wantPending string
+		wantDue     string
+	}{
+		{"none", nil, "[]", "[]"},
+		{"counts down", []ScheduledEvent{{ID: 1, TurnsLeft: 3}}, "[1:2]", "[]"},
+		{"fires at zero", []ScheduledEvent{{ID: 1, TurnsLeft: 1}, {ID: 2, TurnsLeft: 2}}, "[2:1]", "[1:0]"},
+		{"overdue fires", []ScheduledEvent{{ID: 1, TurnsLeft: 0}}, "[]", "[1:-1]"},
+		{"same turn fires by ID", []ScheduledEvent{{ID: 4, TurnsLeft: 1}, {ID: 2, TurnsLeft: 1}, {ID: 3, TurnsLeft: 2}, {ID: 1, TurnsLeft: 1}}, "[3:1]", "[1:0 2:0 4:0]"},
+		{"pending keep their order", []ScheduledEvent{{ID: 5, TurnsLeft: 4}, {ID: 2, TurnsLeft: 3}}, "[5:3 2:2]", "[]"},
+	}
+	format := func(events []ScheduledEvent) string {
+		parts := make([]string, len(events))
+		for i, event := range events {
+			parts[i] = fmt.Sprintf("%d:%d", event.ID, event.TurnsLeft)
+		}
+		return fmt.Sprint(parts)
+	}
+	for _, tt := range tests {
+		pending, due := AdvanceScheduledEvents(tt.events)
+		if format(pending) != tt.wantPending || format(due) != tt.wantDue {
+			t.Errorf("%s: pending %s, due %s; want %s, %s", tt.name, format(pending), format(due), tt.wantPending, tt.wantDue)
+		}
+	}
+}
@@ -25,19 +25,19 @@ type SensoryEventResponse struct {
 }
 
 // GenerateSensoryEvents generates sensory events (sounds, etc.) for player or NPC actions
-func GenerateSensoryEvents(ctx context.Context, llmService *llm.Service, userInput string, successfulMutations []string, world game.WorldState, debugLogger *debug.Logger, actingNPCID ...string) (*SensoryEventResponse, error) {
+func GenerateSensoryEvents(ctx context.Context, llmService llm.Completer, userInput string, successfulMutations []string, world game.WorldState, debugLogger *debug.Logger, actingNPCID ...string) (*SensoryEventResponse, error) {
 	var actionLabel string
 	var currentLocation string
 	
 	if len(actingNPCID) > 0 && actingNPCID[0] != "" {
-		actionLabel = fmt.Sprintf("NPC %s ACTION", strings.ToUpper(actingNPCID[0]))
+		actionLabel = game.ActionLabel(actingNPCID[0])
 		if npc, exists := world.NPCs[actingNPCID[0]]; exists {
 			currentLocation = npc.Location
 		} else {
 			currentLocation = world.Location
 		}
 	} else {
-		actionLabel = "Player action"
+		actionLabel = game.ActionLabel("")
 		currentLocation = world.Location
 	}
 	
@@ -48,6 +48,9 @@ func GenerateSensoryEvents(ctx context.Context, llmService *llm.Service, userInp
 	} else {
 		contextMsg = fmt.Sprintf("%s: %s\nCurrent location: %s", actionLabel, userInput, currentLocation)
 	}
+	if ambience := strings.TrimSpace(world.Locations[currentLocation].Ambience); ambience != "" {
+		contextMsg += fmt.Sprintf("\nBackground ambience (ongoing, not an event): %s", ambience)
+	}
 	
 	req := llm.JSONCompletionRequest{
 		SystemPrompt: buildSensoryEventPrompt(),
@@ -68,6 +71,10 @@ func GenerateSensoryEvents(ctx context.Context, llmService *llm.Service, userInp
 	var eventResp SensoryEventResponse
 	if err := json.Unmarshal([]byte(content), &eventResp); err != nil {
 		debugLogger.Printf("JSON unmarshal failed: %v", err)
+		// Fallback: an unparseable reply reads as nothing being heard
+		if serr := game.StrictFallback(ctx, "sensory parse", err); serr != nil {
+			return nil, serr
+		}
 		return &SensoryEventResponse{AuditoryEvents: []SensoryEvent{}}, nil
 	}
 
@@ -76,44 +83,7 @@ func GenerateSensoryEvents(ctx context.Context, llmService *llm.Service, userInp
 
 // CalculateRoomDistance calculates the shortest path distance between two locations
 func CalculateRoomDistance(fromLocation, toLocation string, locations map[string]game.LocationInfo) int {
-	if fromLocation == toLocation {
-		return 0
-	}
-	
-	// BFS to find shortest path
-	visited := make(map[string]bool)
-	queue := []struct {
-		location string
-		distance int
-	}{{fromLocation, 0}}
-	
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		
-		if visited[current.location] {
-			continue
-		}
-		visited[current.location] = true
-		
-		if current.location == toLocation {
-			return current.distance
-		}
-		
-		// Add all connected rooms to queue
-		if loc, exists := locations[current.location]; exists {
-			for _, destination := range loc.Exits {
-				if !visited[destination] {
-					queue = append(queue, struct {
-						location string
-						distance int
-					}{destination, current.distance + 1})
-				}
-			}
-		}
-	}
-	
-	return -1 // No path found
+	return game.RoomDistance(locations, fromLocation, toLocation)
 }
 
 // ApplyVolumeDecay applies volume decay based on distance for sound propagation
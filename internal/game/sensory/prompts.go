@@ -6,6 +6,7 @@ func buildSensoryEventPrompt() string {
 Rules:
 - Generate only ONE self-contained event per action
 - Events represent what happened in THIS turn only - not ongoing states
+- Never generate the background ambience; it is always there, so it is not an event
 - ONLY describe what can actually be HEARD - no visual details or object identification
 - Use complete descriptions: "someone walked from foyer to library" not just "footsteps"
 - Use objective third-person descriptions: "someone shouted", "door creaking", "rustling sounds"
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWrapUpTurns is how many turns before a session limit narration starts steering
+// the story toward a close, unless configured otherwise.
+const DefaultWrapUpTurns = 3
+
+// SessionLimit ends a session after MaxTurns player turns or MaxDuration of play,
+// whichever comes first; a zero value leaves that limit off. The last WrapUpTurns turns
+// before the limit are the wrap-up, in which narration steers toward closure.
+type SessionLimit struct {
+	MaxTurns    int
+	MaxDuration time.Duration
+	WrapUpTurns int
+}
+
+// ParseSessionLimit reads the session limit settings: a turn count, a duration such as
+// "15m" and the wrap-up length. Empty settings leave their part off (or, for the
+// wrap-up, at DefaultWrapUpTurns).
+func ParseSessionLimit(maxTurns, maxDuration, wrapUpTurns string) (SessionLimit, error) {
+	limit := SessionLimit{WrapUpTurns: DefaultWrapUpTurns}
+	if value := strings.TrimSpace(maxTurns); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return SessionLimit{}, fmt.Errorf("max turns %q is not a whole number of turns", maxTurns)
+		}
+		limit.MaxTurns = n
+	}
+	if value := strings.TrimSpace(maxDuration); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return SessionLimit{}, fmt.Errorf("max duration %q is not a duration such as 15m", maxDuration)
+		}
+		limit.MaxDuration = d
+	}
+	if value := strings.TrimSpace(wrapUpTurns); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return SessionLimit{}, fmt.Errorf("wrap-up turns %q is not a whole number of turns", wrapUpTurns)
+		}
+		limit.WrapUpTurns = n
+	}
+	return limit, nil
+}
+
+// Enabled reports whether either limit is set.
+func (l SessionLimit) Enabled() bool {
+	return l.MaxTurns > 0 || l.MaxDuration > 0
+}
+
+// Reached reports whether a session that has played turnsPlayed turns over elapsed is
+// over.
+func (l SessionLimit) Reached(turnsPlayed int, elapsed time.Duration) bool {
+	return (l.MaxTurns > 0 && turnsPlayed >= l.MaxTurns) || (l.MaxDuration > 0 && elapsed >= l.MaxDuration)
+}
+
+// TurnsLeft returns how many turns remain after turnsPlayed, over elapsed. Against a
+// duration it is an estimate from the average turn so far, so it is unknown until a
+// turn has been played. It reports false when no limit applies yet.
+func (l SessionLimit) TurnsLeft(turnsPlayed int, elapsed time.Duration) (int, bool) {
+	left, known := 0, false
+	if l.MaxTurns > 0 {
+		left, known = max(0, l.MaxTurns-turnsPlayed), true
+	}
+	if average := elapsed / time.Duration(max(turnsPlayed, 1)); l.MaxDuration > 0 && turnsPlayed > 0 && average > 0 {
+		byTime := 0
+		if remaining := l.MaxDuration - elapsed; remaining > 0 {
+			byTime = int(remaining / average)
+		}
+		if !known || byTime < left {
+			left, known = byTime, true
+		}
+	}
+	return left, known
+}
+
+// WrappingUp reports whether the session is in its wrap-up: fewer than WrapUpTurns
+// turns are left after turnsPlayed.
+func (l SessionLimit) WrappingUp(turnsPlayed int, elapsed time.Duration) bool {
+	left, ok := l.TurnsLeft(turnsPlayed, elapsed)
+	return ok && left < l.WrapUpTurns
+}
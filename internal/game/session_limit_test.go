@@ -0,0 +1,87 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSessionLimit(t *testing.T) {
+	tests := []struct {
+		turns, duration, wrapUp string
+		want                    SessionLimit
+		wantErr                 string
+	}{
+		{"", "", "", SessionLimit{WrapUpTurns: DefaultWrapUpTurns}, ""},
+		{" 20 ", "", "", SessionLimit{MaxTurns: 20, WrapUpTurns: DefaultWrapUpTurns}, ""},
+		{"", "15m", "5", SessionLimit{MaxDuration: 15 * time.Minute, WrapUpTurns: 5}, ""},
+		{"12", "1h30m", "0", SessionLimit{MaxTurns: 12, MaxDuration: 90 * time.Minute}, ""},
+		{"ten", "", "", SessionLimit{}, "max turns"},
+		{"-1", "", "", SessionLimit{}, "max turns"},
+		{"", "15", "", SessionLimit{}, "max duration"},
+		{"", "-5m", "", SessionLimit{}, "max duration"},
+		{"", "", "2.5", SessionLimit{}, "wrap-up turns"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSessionLimit(tt.turns, tt.duration, tt.wrapUp)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSessionLimit(%q, %q, %q) error = %v, want one mentioning %q", tt.turns, tt.duration, tt.wrapUp, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSessionLimit(%q, %q, %q) = %+v, %v; want %+v", tt.turns, tt.duration, tt.wrapUp, got, err, tt.want)
+		}
+	}
+}
+
+func TestSessionLimit(t *testing.T) {
+	byTurns := SessionLimit{MaxTurns: 10, WrapUpTurns: 3}
+	byTime := SessionLimit{MaxDuration: 10 * time.Minute, WrapUpTurns: 3}
+	both := SessionLimit{MaxTurns: 10, MaxDuration: 10 * time.Minute, WrapUpTurns: 3}
+
+	tests := []struct {
+		name         string
+		limit        SessionLimit
+		played       int
+		elapsed      time.Duration
+		wantLeft     int
+		wantKnown    bool
+		wantWrapping bool
+		wantReached  bool
+	}{
+		{"no limit", SessionLimit{WrapUpTurns: 3}, 50, time.Hour, 0, false, false, false},
+		{"turns: early on", byTurns, 2, 0, 8, true, false, false},
+		{"turns: one before the wrap-up", byTurns, 7, 0, 3, true, false, false},
+		{"turns: wrap-up starts", byTurns, 8, 0, 2, true, true, false},
+		{"turns: last turn", byTurns, 9, 0, 1, true, true, false},
+		{"turns: reached", byTurns, 10, 0, 0, true, true, true},
+		{"turns: past the limit", byTurns, 12, 0, 0, true, true, true},
+		{"time: nothing played yet", byTime, 0, 0, 0, false, false, false},
+		{"time: estimated from the average turn", byTime, 2, 2 * time.Minute, 8, true, false, false},
+		{"time: wrap-up by estimate", byTime, 4, 8 * time.Minute, 1, true, true, false},
+		{"time: reached", byTime, 5, 10 * time.Minute, 0, true, true, true},
+		{"both: the nearer limit wins", both, 4, 8 * time.Minute, 1, true, true, false},
+		{"both: turns run out first", both, 9, time.Minute, 1, true, true, false},
+	}
+	for _, tt := range tests {
+		left, known := tt.limit.TurnsLeft(tt.played, tt.elapsed)
+		if left != tt.wantLeft || known != tt.wantKnown {
+			t.Errorf("%s: TurnsLeft = %d, %v; want %d, %v", tt.name, left, known, tt.wantLeft, tt.wantKnown)
+		}
+		if got := tt.limit.WrappingUp(tt.played, tt.elapsed); got != tt.wantWrapping {
+			t.Errorf("%s: WrappingUp = %v, want %v", tt.name, got, tt.wantWrapping)
+		}
+		if got := tt.limit.Reached(tt.played, tt.elapsed); got != tt.wantReached {
+			t.Errorf("%s: Reached = %v, want %v", tt.name, got, tt.wantReached)
+		}
+	}
+
+	if (SessionLimit{WrapUpTurns: 3}).Enabled() || !byTurns.Enabled() || !byTime.Enabled() {
+		t.Error("Enabled disagrees with the limits set")
+	}
+	if (SessionLimit{MaxTurns: 10}).WrappingUp(9, 0) {
+		t.Error("a session without a wrap-up is wrapping up")
+	}
+}
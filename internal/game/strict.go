@@ -0,0 +1,44 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrStrictFallback marks an error strict mode raised where the game would otherwise
+// have quietly degraded, e.g. played an empty plan after the director's reply failed
+// to parse.
+var ErrStrictFallback = errors.New("strict mode refused fallback")
+
+type strictKey struct{}
+
+// WithStrict marks calls made with ctx as strict: fallback branches return an error
+// instead of degrading, so broken prompts and parsers show up while developing rather
+// than as a slightly duller game.
+func WithStrict(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictKey{}, strict)
+}
+
+// Strict reports whether ctx was marked strict by WithStrict.
+func Strict(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictKey{}).(bool)
+	return strict
+}
+
+// StrictFallback is called from a fallback branch before it degrades. It returns nil
+// when ctx isn't strict, so the branch carries on as before; otherwise it returns an
+// ErrStrictFallback naming site and cause, and records it on ctx's span.
+func StrictFallback(ctx context.Context, site string, cause error) error {
+	if !Strict(ctx) {
+		return nil
+	}
+	err := fmt.Errorf("%w: %s: %v", ErrStrictFallback, site, cause)
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
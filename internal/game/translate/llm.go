@@ -0,0 +1,46 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"textadventure/internal/llm"
+)
+
+// LLMTranslator translates with a small model call.
+type LLMTranslator struct {
+	service llm.Completer
+}
+
+// NewLLMTranslator creates a translator backed by service.
+func NewLLMTranslator(service llm.Completer) *LLMTranslator {
+	return &LLMTranslator{service: service}
+}
+
+const translatePrompt = `You translate player commands for a text adventure into English.
+Keep the meaning, tone, and any quoted speech; translate quoted speech too. Keep names as written.
+Respond with JSON: {"language": "<name of the input language in English>", "english": "<the command in English>"}`
+
+// Translate implements Translator.
+func (t *LLMTranslator) Translate(ctx context.Context, text string) (string, string, error) {
+	ctx = llm.WithOperationType(ctx, "input.translate")
+	content, err := t.service.CompleteJSON(ctx, llm.JSONCompletionRequest{
+		SystemPrompt:    translatePrompt,
+		UserPrompt:      text,
+		MaxTokens:       500,
+		Model:           "gpt-5-mini",
+		ReasoningEffort: "minimal",
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("translation failed: %w", err)
+	}
+	var response struct {
+		Language string `json:"language"`
+		English  string `json:"english"`
+	}
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return "", "", fmt.Errorf("failed to parse translation: %w", err)
+	}
+	return response.English, response.Language, nil
+}
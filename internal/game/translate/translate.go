@@ -0,0 +1,143 @@
+// Package translate normalizes player input to English so world and event lines stay
+// canonical, while remembering the player's language so narration can be written in it.
+package translate
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Translator turns text into English and reports the language it was written in.
+type Translator interface {
+	Translate(ctx context.Context, text string) (english string, language string, err error)
+}
+
+// Result is the outcome of normalizing one input. Language is empty when the input
+// was already English.
+type Result struct {
+	Original   string
+	Normalized string
+	Language   string
+	Skipped    bool // no translation was attempted because the input looked English
+}
+
+// Normalizer translates non-English input, caching results per input text.
+type Normalizer struct {
+	translator Translator
+	mu         sync.Mutex
+	cache      map[string]Result
+}
+
+// NewNormalizer creates a normalizer backed by translator.
+func NewNormalizer(translator Translator) *Normalizer {
+	return &Normalizer{translator: translator, cache: make(map[string]Result)}
+}
+
+// Normalize returns the English form of input. Input that looks English is passed
+// through without calling the translator.
+func (n *Normalizer) Normalize(ctx context.Context, input string) (Result, error) {
+	input = strings.TrimSpace(input)
+	if LooksEnglish(input) {
+		return Result{Original: input, Normalized: input, Skipped: true}, nil
+	}
+
+	key := strings.ToLower(input)
+	n.mu.Lock()
+	cached, ok := n.cache[key]
+	n.mu.Unlock()
+	if ok {
+		cached.Original = input
+		return cached, nil
+	}
+
+	english, language, err := n.translator.Translate(ctx, input)
+	if err != nil {
+		return Result{Original: input, Normalized: input}, err
+	}
+	result := Result{Original: input, Normalized: strings.TrimSpace(english), Language: language}
+	if result.Normalized == "" {
+		result.Normalized = input
+	}
+	if strings.EqualFold(language, "english") {
+		result.Language = ""
+	}
+
+	n.mu.Lock()
+	n.cache[key] = result
+	n.mu.Unlock()
+	return result, nil
+}
+
+// englishWords are common words that mark input as English.
+var englishWords = map[string]bool{
+	"the": true, "a": true, "an": true, "to": true, "at": true, "my": true, "i": true,
+	"go": true, "look": true, "take": true, "open": true, "talk": true, "with": true,
+	"and": true, "is": true, "what": true, "who": true, "where": true, "pick": true,
+	"up": true, "use": true, "on": true, "in": true, "of": true, "it": true, "her": true,
+	"him": true, "ask": true, "about": true, "north": true, "south": true, "east": true, "west": true,
+}
+
+// foreignWords are common function words from the languages players have used that
+// aren't also English words.
+var foreignWords = map[string]bool{
+	"el": true, "la": true, "los": true, "las": true, "un": true, "una": true, "del": true,
+	"al": true, "con": true, "mi": true, "que": true, "por": true, "para": true, "hablar": true,
+	"mirar": true, "tomar": true, "abrir": true, "ir": true, "le": true, "les": true, "avec": true,
+	"je": true, "der": true, "die": true, "das": true, "und": true, "ich": true, "mit": true,
+}
+
+// LooksEnglish is a cheap heuristic: accented letters or inverted punctuation, or more
+// foreign function words than English ones, mean the input needs translating.
+func LooksEnglish(text string) bool {
+	for _, r := range text {
+		if r == '¿' || r == '¡' || (unicode.IsLetter(r) && r > unicode.MaxASCII) {
+			return false
+		}
+	}
+	english, foreign := 0, 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if englishWords[word] {
+			english++
+		}
+		if foreignWords[word] {
+			foreign++
+		}
+	}
+	return foreign <= english
+}
+
+type resultKey struct{}
+type languageKey struct{}
+
+// WithResult attaches the current turn's normalization result, so completion logging
+// can record the player's original input next to the normalized one.
+func WithResult(ctx context.Context, result Result) context.Context {
+	if result.Original == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, resultKey{}, result)
+}
+
+// ResultFromContext returns the result attached by WithResult.
+func ResultFromContext(ctx context.Context) (Result, bool) {
+	result, ok := ctx.Value(resultKey{}).(Result)
+	return result, ok
+}
+
+// WithLanguage sets the language narration should be written in. Empty means English.
+func WithLanguage(ctx context.Context, language string) context.Context {
+	if language == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// LanguageFromContext returns the narration language, or "" for English.
+func LanguageFromContext(ctx context.Context) string {
+	language, _ := ctx.Value(languageKey{}).(string)
+	return language
+}
@@ -0,0 +1,26 @@
+package game
+
+import "time"
+
+// TurnRecord is the player-facing summary of a completed turn. It deliberately
+// carries no debug output so subscribers can publish it as-is.
+type TurnRecord struct {
+	SessionID   string
+	TurnID      string
+	TurnIndex   int
+	Location    string
+	PlayerInput string
+	Narration   string
+	WorldEvents []string
+	Mutations   []string // successful mutation summaries, player and NPCs
+	Failures    []string // failed mutation summaries
+	NPCActions  []string // "npc_id: action" lines; speech is `npc_id: "quote"`
+	Tags        []string // what the turn was about (exploration, dialogue, ...); empty if unclassified
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// TurnSubscriber is notified once per turn, after the narration phase completes.
+type TurnSubscriber interface {
+	OnTurnComplete(record TurnRecord) error
+}
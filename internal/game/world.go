@@ -1,19 +1,46 @@
 package game
 
-import "strings"
+import (
+	"maps"
+	"sort"
+	"strings"
+)
 
 type WorldState struct {
 	Location  string
 	Inventory []string
 	MetNPCs   []string
+	// VisitedLocations is the player's known map: rooms they have entered at least once.
+	VisitedLocations []string
+	Conditions []PlayerCondition
 	Locations map[string]LocationInfo
 	NPCs      map[string]NPCInfo
+	Items     map[string]ItemInfo
+	ScheduledEvents []ScheduledEvent
+	Quests []Quest
+	// Goals are the player's own recent goals from their journal, newest last.
+	Goals []string
 }
 
 type LocationInfo struct {
 	Name        string
 	Exits       map[string]string
 	Facts       []string
+	Outdoors    bool
+	// NarratorNotes is private direction from the scenario author. Only the narrator
+	// sees it, while the player is here; it never enters the director or NPC context.
+	NarratorNotes []string
+	// Ambience is the room's ongoing background ("the refrigerator drones"). It is
+	// established atmosphere, never an event of its own.
+	Ambience string
+	// Doors are the doors on the room's exits, by direction. An exit without one is open.
+	Doors map[string]DoorInfo
+}
+
+// DoorInfo is a door on an exit. A locked door blocks the exit until it is unlocked.
+type DoorInfo struct {
+	Locked      bool
+	Description string
 }
 
 type NPCInfo struct {
@@ -21,24 +48,48 @@ type NPCInfo struct {
 	DebugColor    string
 	Description   string
 	Inventory     []string
-	RecentThoughts []string
-	RecentActions []string
+	RecentThoughts []NPCMemoryEntry
+	RecentActions []NPCMemoryEntry
 	Personality   string
 	Backstory     string
 	Memories      []string
 	Facts         []string
+	// NarratorNotes is private direction for narrating this NPC while the player is
+	// with them.
+	NarratorNotes []string
+	// Dialogue is the NPC's authored lines, and DialogueSpoken the IDs of those said.
+	Dialogue       []DialogueNode
+	DialogueSpoken []string
+	// Goals are what the NPC is currently trying to achieve, steering its actions
+	// across turns.
+	Goals []string
+	// Emotions are the NPC's feelings by name (see KnownEmotions), each from 0 to 1.
+	Emotions map[string]float64
+	// AmbienceHeard maps each location whose ambience the NPC has perceived to the
+	// ambience it perceived there, so each is perceived once.
+	AmbienceHeard map[string]string
+	// Relationship is how the NPC regards the player.
+	Relationship Relationship
 }
 
 type ItemInfo struct {
 	Name     string
 	Facts    []string
 	Location string
+	// IsContainer items hold other items, listed in Contains; an item inside one has
+	// the container's ID as its Location. A Locked container can't be opened, and a
+	// positive Capacity caps how many items it holds.
+	IsContainer bool
+	Contains    []string
+	Locked      bool
+	Capacity    int
 }
 
 func NewDefaultWorldState() WorldState {
 	return WorldState{
 		Location:  "foyer",
 		Inventory: []string{},
+		VisitedLocations: []string{"foyer"},
 		Locations: map[string]LocationInfo{
 			"foyer": {
 				Name:  "foyer",
@@ -72,16 +123,74 @@ func NewDefaultWorldState() WorldState {
 					"feeling disoriented and cautious",
 				},
 				Facts:           []string{},
-				RecentThoughts:  []string{},
-				RecentActions:   []string{},
+				RecentThoughts:  []NPCMemoryEntry{},
+				RecentActions:   []NPCMemoryEntry{},
 				Inventory:       []string{},
 				DebugColor:      "yellow",
-				Description:     "someone",
+				Description:     "a woman in her thirties with dark hair loose and slightly disheveled, wearing a simple gray dress",
 			},
 		},
 	}
 }
 
+// Clone returns a copy whose maps and slices can be modified without affecting the
+// original. Commands still running hold the original by value, so in-place edits must
+// go through a clone to keep their snapshot stable.
+func (ws WorldState) Clone() WorldState {
+	clone := ws
+	clone.Inventory = append([]string(nil), ws.Inventory...)
+	clone.MetNPCs = append([]string(nil), ws.MetNPCs...)
+	clone.VisitedLocations = append([]string(nil), ws.VisitedLocations...)
+	clone.Conditions = append([]PlayerCondition(nil), ws.Conditions...)
+	clone.ScheduledEvents = append([]ScheduledEvent(nil), ws.ScheduledEvents...)
+	clone.Quests = append([]Quest(nil), ws.Quests...)
+	clone.Goals = append([]string(nil), ws.Goals...)
+	clone.Locations = make(map[string]LocationInfo, len(ws.Locations))
+	for id, loc := range ws.Locations {
+		loc.Facts = append([]string(nil), loc.Facts...)
+		loc.NarratorNotes = append([]string(nil), loc.NarratorNotes...)
+		loc.Doors = maps.Clone(loc.Doors)
+		clone.Locations[id] = loc
+	}
+	clone.NPCs = make(map[string]NPCInfo, len(ws.NPCs))
+	for id, npc := range ws.NPCs {
+		npc.Facts = append([]string(nil), npc.Facts...)
+		npc.RecentThoughts = append([]NPCMemoryEntry(nil), npc.RecentThoughts...)
+		npc.RecentActions = append([]NPCMemoryEntry(nil), npc.RecentActions...)
+		npc.NarratorNotes = append([]string(nil), npc.NarratorNotes...)
+		npc.DialogueSpoken = append([]string(nil), npc.DialogueSpoken...)
+		npc.Goals = append([]string(nil), npc.Goals...)
+		npc.Emotions = maps.Clone(npc.Emotions)
+		npc.AmbienceHeard = maps.Clone(npc.AmbienceHeard)
+		npc.Relationship.Interactions = append([]string(nil), npc.Relationship.Interactions...)
+		clone.NPCs[id] = npc
+	}
+	clone.Items = make(map[string]ItemInfo, len(ws.Items))
+	for id, item := range ws.Items {
+		item.Facts = append([]string(nil), item.Facts...)
+		item.Contains = append([]string(nil), item.Contains...)
+		clone.Items[id] = item
+	}
+	return clone
+}
+
+// NarratorNotesForPlayer returns the narrator notes for the player's location and the
+// NPCs there, NPCs in ID order.
+func (ws WorldState) NarratorNotesForPlayer() []string {
+	notes := append([]string(nil), ws.Locations[ws.Location].NarratorNotes...)
+	var npcIDs []string
+	for id, npc := range ws.NPCs {
+		if npc.Location == ws.Location && len(npc.NarratorNotes) > 0 {
+			npcIDs = append(npcIDs, id)
+		}
+	}
+	sort.Strings(npcIDs)
+	for _, id := range npcIDs {
+		notes = append(notes, ws.NPCs[id].NarratorNotes...)
+	}
+	return notes
+}
+
 func (ws *WorldState) AccumulateLocationFacts(locationID string, newFacts []string) {
 	if len(newFacts) == 0 {
 		return
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// Compatibility lists the request parameters an OpenAI-compatible endpoint doesn't
+// support. Local servers such as Ollama and llama.cpp take most chat completion
+// requests but reject or ignore reasoning_effort and strict JSON schema mode.
+type Compatibility struct {
+	// NoReasoningEffort leaves reasoning_effort off every request.
+	NoReasoningEffort bool
+	// NoJSONSchema sends schema completions in JSON-object mode, with the schema in the
+	// system prompt, and validates the response against the schema client-side.
+	NoJSONSchema bool
+}
+
+// CompatibilityFor returns the compatibility for an endpoint. The OpenAI API (no base
+// URL, or api.openai.com) supports everything; any other endpoint is assumed to be a
+// local server and gets both fallbacks. LLM_NO_REASONING_EFFORT and LLM_NO_JSON_SCHEMA
+// ("1"/"true" or "0"/"false") override either guess.
+func CompatibilityFor(baseURL string) Compatibility {
+	local := isLocalEndpoint(baseURL)
+	return Compatibility{
+		NoReasoningEffort: envFlag("LLM_NO_REASONING_EFFORT", local),
+		NoJSONSchema:      envFlag("LLM_NO_JSON_SCHEMA", local),
+	}
+}
+
+func isLocalEndpoint(baseURL string) bool {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return false
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return true
+	}
+	return !strings.EqualFold(parsed.Hostname(), "api.openai.com")
+}
+
+func envFlag(name string, fallback bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(name))) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	default:
+		return fallback
+	}
+}
+
+// SetCompatibility tells the service which request parameters its endpoint supports.
+func (s *Service) SetCompatibility(compat Compatibility) {
+	s.compat = compat
+}
+
+// schemaInstructions is appended to the system prompt of a schema completion sent in
+// JSON-object mode, so the model still knows the shape it must produce.
+func schemaInstructions(name string, schema interface{}) (string, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("encode schema %s: %w", name, err)
+	}
+	return fmt.Sprintf("\n\nRespond with only a JSON object that matches this JSON schema (%s):\n%s", name, data), nil
+}
+
+// validateJSONSchema checks a completion against the schema it was asked for, standing
+// in for strict mode on endpoints that don't enforce it.
+func validateJSONSchema(content string, schema interface{}) error {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("encode schema: %w", err)
+	}
+	var parsed jsonschema.Schema
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("parse schema: %w", err)
+	}
+	resolved, err := parsed.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("resolve schema: %w", err)
+	}
+	var instance any
+	if err := json.Unmarshal([]byte(content), &instance); err != nil {
+		return fmt.Errorf("response is not JSON: %w", err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		return fmt.Errorf("response doesn't match schema: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+)
+
+// Completer is the completion API game code depends on. *Service implements it against
+// OpenAI; MockService implements it with canned responses so turns can run offline.
+type Completer interface {
+	CompleteText(ctx context.Context, req TextCompletionRequest) (string, error)
+	CompleteJSON(ctx context.Context, req JSONCompletionRequest) (string, error)
+	CompleteJSONSchema(ctx context.Context, req JSONSchemaCompletionRequest) (string, error)
+	CompleteStream(ctx context.Context, req StreamCompletionRequest) (*ssestream.Stream[openai.ChatCompletionChunk], error)
+}
+
+// Client is a Completer that also embeds text, resolves per-operation model settings
+// and tracks usage. Narration needs all of it.
+type Client interface {
+	Completer
+	Embed(ctx context.Context, text string) ([]float64, error)
+	Resolve(ctx context.Context, requested ModelSettings) ModelSettings
+	Usage() *UsageTracker
+}
+
+var (
+	_ Client = (*Service)(nil)
+	_ Client = (*MockService)(nil)
+)
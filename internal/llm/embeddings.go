@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openai/openai-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const embeddingModel = openai.EmbeddingModelTextEmbedding3Small
+
+// Embed returns the embedding vector for text.
+func (s *Service) Embed(ctx context.Context, text string) ([]float64, error) {
+	spanName := getOperationType(ctx)
+	if spanName == "" {
+		spanName = "llm.embed"
+	}
+	ctx, span := s.tracer.Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("gen_ai.system", "openai"),
+			attribute.String("gen_ai.request.model", string(embeddingModel)),
+			attribute.String("langfuse.observation.type", "embedding"),
+		),
+	)
+	defer span.End()
+	CopyGameContextToSpan(ctx, span)
+
+	startTime := time.Now()
+	resp, err := s.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{OfString: openai.String(text)},
+		Model: embeddingModel,
+	})
+	if err != nil {
+		span.SetAttributes(attribute.String("error.type", "llm_embedding_error"))
+		span.RecordError(err)
+		return nil, fmt.Errorf("embedding failed: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		err := fmt.Errorf("no embedding returned")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	s.usage.Record(spanName, string(embeddingModel), resp.Usage.PromptTokens, 0)
+	span.SetAttributes(
+		attribute.Int64("gen_ai.usage.input_tokens", resp.Usage.PromptTokens),
+		attribute.Int64("response_time_ms", time.Since(startTime).Milliseconds()),
+	)
+	return resp.Data[0].Embedding, nil
+}
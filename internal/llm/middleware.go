@@ -0,0 +1,522 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"textadventure/internal/debug"
+	"textadventure/internal/observability"
+)
+
+// CallKind is which of the service's completion methods a call came from.
+type CallKind int
+
+const (
+	TextCall CallKind = iota
+	JSONCall
+	JSONSchemaCall
+	StreamCall
+)
+
+// defaultOperation is the operation type of a call whose context doesn't name one.
+func (k CallKind) defaultOperation() string {
+	switch k {
+	case JSONCall:
+		return "json_completion"
+	case JSONSchemaCall:
+		return "json_schema_completion"
+	case StreamCall:
+		return "stream_completion"
+	default:
+		return "text_completion"
+	}
+}
+
+// label names the kind in debug output ("LLM JSON Schema Completion ...").
+func (k CallKind) label() string {
+	switch k {
+	case JSONCall:
+		return "JSON"
+	case JSONSchemaCall:
+		return "JSON Schema"
+	case StreamCall:
+		return "Stream"
+	default:
+		return "Text"
+	}
+}
+
+// methodSuffix is the completion method's name after "Complete".
+func (k CallKind) methodSuffix() string {
+	switch k {
+	case JSONCall:
+		return "JSON"
+	case JSONSchemaCall:
+		return "JSONSchema"
+	case StreamCall:
+		return "Stream"
+	default:
+		return "Text"
+	}
+}
+
+// errorPrefix starts the error of a completion the API refused ("JSON schema completion failed").
+func (k CallKind) errorPrefix() string {
+	switch k {
+	case JSONCall:
+		return "JSON"
+	case JSONSchemaCall:
+		return "JSON schema"
+	case StreamCall:
+		return "stream"
+	default:
+		return "text"
+	}
+}
+
+// outputFormat is the langfuse.observation.output_format of the kind's completions.
+func (k CallKind) outputFormat() string {
+	switch k {
+	case JSONCall:
+		return "json"
+	case JSONSchemaCall:
+		return "json_schema"
+	default:
+		return "text"
+	}
+}
+
+// Call is one completion request on its way through the middleware chain. Model
+// settings are already resolved for the operation.
+type Call struct {
+	Kind            CallKind
+	Operation       string // the operation type from the context, or the kind's default
+	SystemPrompt    string
+	UserPrompt      string
+	MaxTokens       int
+	Model           string
+	ReasoningEffort string
+	Temperature     *float64
+	// ResponseFormat is "" for text, "json" for JSON-object mode, "json_schema" for
+	// strict schema mode and "json_object" for a schema completion sent in JSON-object
+	// mode to an endpoint without strict mode (see Compatibility).
+	ResponseFormat string
+	SchemaName     string
+	Schema         interface{}
+}
+
+// Result is what the provider answered. Stream calls only set Stream; their text and
+// usage arrive as the caller reads it.
+type Result struct {
+	Content      string
+	FinishReason string
+	Refusal      string
+	Choices      int
+	InputTokens  int64
+	OutputTokens int64
+	Stream       *ssestream.Stream[openai.ChatCompletionChunk]
+}
+
+// Handler completes a call. A handler may return a result together with an error when
+// the provider answered but the answer was rejected, e.g. a reply that doesn't match
+// its schema; the tokens were still spent.
+type Handler func(ctx context.Context, call *Call) (*Result, error)
+
+// Middleware wraps a handler with behavior that applies to every kind of call.
+type Middleware func(next Handler) Handler
+
+// Chain wraps handler in middleware, the first outermost.
+func Chain(handler Handler, middleware ...Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// providerError is an error the provider reports along with the error.type its span
+// records. The span records cause, the error as the API or the validator gave it.
+type providerError struct {
+	errType string
+	cause   error
+	err     error
+}
+
+func (e *providerError) Error() string { return e.err.Error() }
+func (e *providerError) Unwrap() error { return e.err }
+
+// recordError records err on span, with its error.type when the provider gave one.
+func recordError(span trace.Span, err error) {
+	var perr *providerError
+	if !errors.As(err, &perr) {
+		span.RecordError(err)
+		return
+	}
+	span.SetAttributes(attribute.String("error.type", perr.errType))
+	span.RecordError(perr.cause)
+}
+
+// LoggingMiddleware writes each call's request and response to the debug log. It runs
+// outside tracing so it can report calls made without a parent span.
+func LoggingMiddleware(logger *debug.Logger) Middleware {
+	return func(next Handler) Handler {
+		if logger == nil {
+			return next
+		}
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			label := call.Kind.label()
+			if sc := trace.SpanFromContext(ctx).SpanContext(); !sc.IsValid() {
+				logger.Printf("NO PARENT: ctx missing active span for %s", call.Operation)
+			} else {
+				logger.Printf("Complete%s trace=%s parentSpan=%s op=%s", call.Kind.methodSuffix(), sc.TraceID(), sc.SpanID(), call.Operation)
+			}
+			switch call.Kind {
+			case JSONSchemaCall:
+				logger.Printf("LLM %s Completion - MaxTokens: %d, Schema: %s", label, call.MaxTokens, call.SchemaName)
+			default:
+				logger.Printf("LLM %s Completion - MaxTokens: %d, SystemPrompt length: %d", label, call.MaxTokens, len(call.SystemPrompt))
+			}
+			if call.Kind == JSONCall || call.Kind == StreamCall {
+				logger.Printf("LLM %s Request - Model: %s, ResponseFormat: %s", label, call.Model, call.ResponseFormat)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, call)
+			if result != nil && call.Kind != StreamCall {
+				logger.Printf("%s Response: content=%q, finish_reason=%s, choices_count=%d", label, result.Content, result.FinishReason, result.Choices)
+				if result.FinishReason == "length" {
+					logger.Printf("%s Length Debug: input_tokens=%d, completion_tokens=%d, total_available=%d, message_refusal=%q",
+						label, result.InputTokens, result.OutputTokens, call.MaxTokens, result.Refusal)
+				}
+			}
+			if err != nil {
+				logger.Printf("LLM %s Completion error: %v", label, err)
+				return result, err
+			}
+			if call.Kind != StreamCall {
+				logger.Printf("LLM %s Completion response length: %d, tokens: %d/%d, duration: %v",
+					label, len(result.Content), result.InputTokens, result.OutputTokens, time.Since(start))
+			}
+			return result, nil
+		}
+	}
+}
+
+// TracingMiddleware records each call as a generation span named after its operation,
+// with the request, the session and game context, the response and token usage. A
+// stream call's span stays open while the stream is read and ends with it, once its
+// text and usage are known.
+func TracingMiddleware(tracer trace.Tracer) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			ctx, span := tracer.Start(ctx, call.Operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					observability.CreateGenAIAttributes("openai", call.Model, 0, 0, -1)...,
+				),
+			)
+
+			attrs := []attribute.KeyValue{
+				attribute.Int("gen_ai.request.max_tokens", call.MaxTokens),
+				attribute.String("langfuse.observation.type", "generation"),
+			}
+			if call.ResponseFormat != "" {
+				attrs = append(attrs, attribute.String("response_format", call.ResponseFormat))
+			}
+			attrs = append(attrs, attribute.String("game.operation_type", call.Operation))
+			attrs = append(attrs, RequestAttributes(call.ReasoningEffort, call.Temperature)...)
+			attrs = append(attrs, gameContextAttributes(ctx)...)
+			span.SetAttributes(attrs...)
+
+			span.AddEvent("gen_ai.user.message", trace.WithAttributes(
+				attribute.String("gen_ai.system", "openai"),
+				attribute.String("content", call.UserPrompt),
+			))
+
+			start := time.Now()
+			result, err := next(ctx, call)
+			if err != nil {
+				recordError(span, err)
+				span.End()
+				return result, err
+			}
+			if call.Kind == StreamCall {
+				result.Stream = watchStream(result.Stream, func(content string, usage openai.CompletionUsage, err error) {
+					if err != nil {
+						recordError(span, err)
+					} else {
+						recordGeneration(span, call, content, usage.PromptTokens, usage.CompletionTokens, start)
+					}
+					span.End()
+				})
+				return result, nil
+			}
+			recordGeneration(span, call, result.Content, result.InputTokens, result.OutputTokens, start)
+			span.End()
+			return result, nil
+		}
+	}
+}
+
+// recordGeneration sets what the provider answered on a call's span.
+func recordGeneration(span trace.Span, call *Call, content string, inputTokens, outputTokens int64, start time.Time) {
+	span.SetAttributes(
+		attribute.Int64("gen_ai.usage.input_tokens", inputTokens),
+		attribute.Int64("gen_ai.usage.output_tokens", outputTokens),
+		attribute.Int64("response_time_ms", time.Since(start).Milliseconds()),
+		attribute.String("langfuse.observation.input", call.SystemPrompt+"\n\n"+call.UserPrompt),
+		attribute.String("langfuse.observation.output", content),
+		attribute.String("langfuse.observation.output_format", call.Kind.outputFormat()),
+		attribute.String("langfuse.observation.model.name", call.Model),
+	)
+	span.AddEvent("gen_ai.choice", trace.WithAttributes(
+		attribute.String("gen_ai.system", "openai"),
+		attribute.String("content", content),
+	))
+}
+
+// watchStream returns a stream with the same chunks as stream that collects their text
+// and usage as they are read, and calls done once when the stream runs out or is closed.
+func watchStream(stream *ssestream.Stream[openai.ChatCompletionChunk], done func(content string, usage openai.CompletionUsage, err error)) *ssestream.Stream[openai.ChatCompletionChunk] {
+	return ssestream.NewStream[openai.ChatCompletionChunk](&watchedDecoder{stream: stream, done: done}, nil)
+}
+
+// watchedDecoder feeds a stream the chunks of the stream it watches, as raw events.
+type watchedDecoder struct {
+	stream  *ssestream.Stream[openai.ChatCompletionChunk]
+	event   ssestream.Event
+	content strings.Builder
+	usage   openai.CompletionUsage
+	done    func(content string, usage openai.CompletionUsage, err error)
+}
+
+func (d *watchedDecoder) Next() bool {
+	if !d.stream.Next() {
+		d.finish()
+		return false
+	}
+	chunk := d.stream.Current()
+	if len(chunk.Choices) > 0 {
+		d.content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	if chunk.Usage.TotalTokens > 0 {
+		d.usage = chunk.Usage
+	}
+	d.event = ssestream.Event{Data: []byte(chunk.RawJSON())}
+	return true
+}
+
+func (d *watchedDecoder) Event() ssestream.Event {
+	return d.event
+}
+
+func (d *watchedDecoder) Err() error {
+	return d.stream.Err()
+}
+
+func (d *watchedDecoder) Close() error {
+	d.finish()
+	return d.stream.Close()
+}
+
+func (d *watchedDecoder) finish() {
+	if d.done == nil {
+		return
+	}
+	done := d.done
+	d.done = nil
+	done(d.content.String(), d.usage, d.stream.Err())
+}
+
+// RetryPolicy is how RetryMiddleware retries calls.
+type RetryPolicy struct {
+	MaxAttempts int           // including the first call; below 2 never retries
+	Backoff     time.Duration // the wait before the first retry, doubled for each one after
+}
+
+// DefaultRetryPolicy retries twice, after half a second and then a second.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 500 * time.Millisecond}
+
+// RetryMiddleware retries calls the provider failed transiently: rate limits, timeouts,
+// server errors and requests that got no answer at all. Answers the provider gave,
+// even ones rejected afterwards, are never retried. Each retry is an event on the
+// call's span. A stream call is retried only when the stream couldn't be opened.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			backoff := policy.Backoff
+			for attempt := 1; ; attempt++ {
+				result, err := next(ctx, call)
+				if err == nil || attempt >= policy.MaxAttempts || !isTransient(err) || ctx.Err() != nil {
+					return result, err
+				}
+				trace.SpanFromContext(ctx).AddEvent("llm.retry", trace.WithAttributes(
+					attribute.Int("llm.retry.attempt", attempt),
+					attribute.String("llm.retry.error", err.Error()),
+				))
+				select {
+				case <-ctx.Done():
+					return result, err
+				case <-time.After(backoff):
+				}
+				backoff *= 2
+			}
+		}
+	}
+}
+
+// isTransient reports whether a call failed in a way that trying again may fix.
+func isTransient(err error) bool {
+	var perr *providerError
+	if !errors.As(err, &perr) || perr.errType != "llm_completion_error" {
+		// Not a failed request, e.g. an answer that didn't match its schema
+		return false
+	}
+	var apiErr *openai.Error
+	if errors.As(perr.cause, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+			return true
+		}
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	// No answer at all, e.g. a dropped connection, unless the caller gave up
+	return !errors.Is(perr.cause, context.Canceled) && !errors.Is(perr.cause, context.DeadlineExceeded)
+}
+
+// cachedOperations are the operation types whose answers depend only on their prompts,
+// so the service caches them.
+var cachedOperations = []string{"input.translate", "turn.classify", "guide.classify"}
+
+// defaultCacheSize is how many answers the service's cache holds.
+const defaultCacheSize = 256
+
+// ResponseCache holds answers to calls, keyed by everything that shapes the answer. It
+// only takes calls under its operation types, and holds at most size answers, dropping
+// the oldest first.
+type ResponseCache struct {
+	mu         sync.Mutex
+	operations []string
+	size       int
+	entries    map[string]Result
+	order      []string
+}
+
+// NewResponseCache creates a cache for calls whose operation type is one of operations
+// or falls under one, as "npc" covers "npc.think".
+func NewResponseCache(size int, operations ...string) *ResponseCache {
+	return &ResponseCache{operations: operations, size: size, entries: make(map[string]Result)}
+}
+
+func (c *ResponseCache) covers(operation string) bool {
+	for _, op := range c.operations {
+		if operation == op || strings.HasPrefix(operation, op+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ResponseCache) get(key string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.entries[key]
+	return result, ok
+}
+
+func (c *ResponseCache) put(key string, result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok || c.size <= 0 {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = result
+	c.order = append(c.order, key)
+}
+
+// cacheKey identifies a call by its kind, settings, schema and prompts.
+func cacheKey(call *Call) string {
+	schema, _ := json.Marshal(call.Schema)
+	temperature := ""
+	if call.Temperature != nil {
+		temperature = strconv.FormatFloat(*call.Temperature, 'g', -1, 64)
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		call.Kind.methodSuffix(), call.Operation, call.Model, strconv.Itoa(call.MaxTokens),
+		call.ReasoningEffort, temperature, call.ResponseFormat, call.SchemaName, string(schema),
+		call.SystemPrompt, call.UserPrompt,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CacheMiddleware answers the calls cache covers from it when it can, and keeps the
+// complete answers the provider gives them. A cached answer spent no tokens and is
+// marked llm.cache_hit on the call's span. Stream calls are never cached.
+func CacheMiddleware(cache *ResponseCache) Middleware {
+	return func(next Handler) Handler {
+		if cache == nil {
+			return next
+		}
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			if call.Kind == StreamCall || !cache.covers(call.Operation) {
+				return next(ctx, call)
+			}
+			key := cacheKey(call)
+			if cached, ok := cache.get(key); ok {
+				trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("llm.cache_hit", true))
+				cached.InputTokens, cached.OutputTokens = 0, 0
+				return &cached, nil
+			}
+			result, err := next(ctx, call)
+			if err == nil && result.FinishReason != "length" {
+				cache.put(key, *result)
+			}
+			return result, err
+		}
+	}
+}
+
+// UsageMiddleware adds each answered call's tokens to tracker, including answers that
+// were rejected afterwards. A stream's usage arrives in its last chunk, so it is
+// recorded once the stream has been read.
+func UsageMiddleware(tracker *UsageTracker) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*Result, error) {
+			result, err := next(ctx, call)
+			if result == nil {
+				return result, err
+			}
+			if call.Kind == StreamCall {
+				if err == nil {
+					result.Stream = watchStream(result.Stream, func(_ string, usage openai.CompletionUsage, _ error) {
+						if usage.TotalTokens > 0 {
+							tracker.Record(call.Operation, call.Model, usage.PromptTokens, usage.CompletionTokens)
+						}
+					})
+				}
+				return result, err
+			}
+			tracker.Record(call.Operation, call.Model, result.InputTokens, result.OutputTokens)
+			return result, err
+		}
+	}
+}
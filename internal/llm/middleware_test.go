@@ -0,0 +1,431 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs an in-memory span exporter as the global tracer provider for
+// the rest of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func spanAttributes(span tracetest.SpanStub) map[string]attribute.Value {
+	attrs := make(map[string]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[string(kv.Key)] = kv.Value
+	}
+	return attrs
+}
+
+func spanEvents(span tracetest.SpanStub) []string {
+	var names []string
+	for _, event := range span.Events {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+// fakeOpenAI answers chat completions with answer, streaming it when asked, and reports
+// 10 prompt and 5 completion tokens either way.
+func fakeOpenAI(t *testing.T, answer string) *httptest.Server {
+	t.Helper()
+	usage := map[string]int{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &req)
+		if !req.Stream {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": "fake", "object": "chat.completion", "model": "fake",
+				"choices": []map[string]interface{}{{"index": 0, "finish_reason": "stop",
+					"message": map[string]string{"role": "assistant", "content": answer}}},
+				"usage": usage,
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []map[string]interface{}{
+			{"choices": []map[string]interface{}{{"index": 0, "delta": map[string]string{"content": answer[:len(answer)/2]}}}},
+			{"choices": []map[string]interface{}{{"index": 0, "delta": map[string]string{"content": answer[len(answer)/2:]}}}},
+			{"choices": []map[string]interface{}{}, "usage": usage},
+		}
+		for _, chunk := range chunks {
+			chunk["id"], chunk["object"], chunk["model"] = "fake", "chat.completion.chunk", "fake"
+			data, _ := json.Marshal(chunk)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Text, JSON-schema and stream completions run through the same chain and get the same
+// span: same name, attributes and events, with the stream's usage and output recorded
+// once it has been read.
+func TestServiceSpansAreTheSameForEveryKind(t *testing.T) {
+	exporter := recordSpans(t)
+	answer := `{"answer":"the lamp"}`
+	server := fakeOpenAI(t, answer)
+	s := NewService("test-key", nil, option.WithBaseURL(server.URL))
+	ctx := WithGameContext(WithOperationType(context.Background(), "npc.think"), map[string]interface{}{"turn_index": 3})
+
+	if _, err := s.CompleteText(ctx, TextCompletionRequest{SystemPrompt: "system", UserPrompt: "user", MaxTokens: 100, ReasoningEffort: "low"}); err != nil {
+		t.Fatal(err)
+	}
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           map[string]interface{}{"answer": map[string]interface{}{"type": "string"}},
+		"required":             []string{"answer"},
+		"additionalProperties": false,
+	}
+	if _, err := s.CompleteJSONSchema(ctx, JSONSchemaCompletionRequest{SystemPrompt: "system", UserPrompt: "user", MaxTokens: 100, ReasoningEffort: "low", SchemaName: "answer", Schema: schema}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := s.CompleteStream(ctx, StreamCompletionRequest{SystemPrompt: "system", UserPrompt: "user", MaxTokens: 100, ReasoningEffort: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(exporter.GetSpans()); n != 2 {
+		t.Fatalf("%d spans ended before the stream was read, want 2", n)
+	}
+	var streamed strings.Builder
+	for stream.Next() {
+		if chunk := stream.Current(); len(chunk.Choices) > 0 {
+			streamed.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	stream.Close()
+	if streamed.String() != answer {
+		t.Errorf("streamed %q, want %q", streamed.String(), answer)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	text, schemaSpan, streamSpan := spanAttributes(spans[0]), spanAttributes(spans[1]), spanAttributes(spans[2])
+	for i, span := range spans {
+		if span.Name != "npc.think" {
+			t.Errorf("span %d is named %q, want npc.think", i, span.Name)
+		}
+		if events := spanEvents(span); !slices.Equal(events, []string{"gen_ai.user.message", "gen_ai.choice"}) {
+			t.Errorf("span %d events = %q", i, events)
+		}
+	}
+
+	// Only the response format and the output format tell the kinds apart
+	differ := map[string]bool{"response_format": true, "langfuse.observation.output_format": true, "response_time_ms": true}
+	for name, attrs := range map[string]map[string]attribute.Value{"json schema": schemaSpan, "stream": streamSpan} {
+		for key, want := range text {
+			got, ok := attrs[key]
+			if !ok {
+				t.Errorf("%s span is missing %s", name, key)
+			} else if !differ[key] && got != want {
+				t.Errorf("%s span %s = %v, text span has %v", name, key, got.Emit(), want.Emit())
+			}
+		}
+		for key := range attrs {
+			if _, ok := text[key]; !ok && key != "response_format" {
+				t.Errorf("%s span has %s, text span doesn't", name, key)
+			}
+		}
+	}
+	checks := map[string]string{
+		"gen_ai.usage.input_tokens":          "10",
+		"gen_ai.usage.output_tokens":         "5",
+		"gen_ai.request.max_tokens":          "100",
+		"gen_ai.request.reasoning_effort":    "low",
+		"game.operation_type":                "npc.think",
+		"game.turn_index":                    "3",
+		"langfuse.observation.type":          "generation",
+		"langfuse.observation.output":        answer,
+		"langfuse.observation.input":         "system\n\nuser",
+		"langfuse.observation.output_format": "text",
+	}
+	for key, want := range checks {
+		if got := streamSpan[key].Emit(); got != want {
+			t.Errorf("stream span %s = %q, want %q", key, got, want)
+		}
+	}
+	if got := schemaSpan["response_format"].Emit(); got != "json_schema" {
+		t.Errorf("json schema span response_format = %q", got)
+	}
+	if got := schemaSpan["langfuse.observation.output_format"].Emit(); got != "json_schema" {
+		t.Errorf("json schema span output_format = %q", got)
+	}
+}
+
+// A stream closed before its end still ends its span, with what was read so far.
+func TestTracingMiddlewareEndsStreamSpanOnClose(t *testing.T) {
+	exporter := recordSpans(t)
+	handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+		return &Result{Stream: mockStream("one two three")}, nil
+	}, TracingMiddleware(otel.Tracer("test")))
+
+	result, err := handler(context.Background(), &Call{Kind: StreamCall, Operation: "narration.generate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.Stream.Next()
+	result.Stream.Close()
+	result.Stream.Close()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if got := spanAttributes(spans[0])["langfuse.observation.output"].Emit(); got != "one " {
+		t.Errorf("output = %q, want the first chunk", got)
+	}
+}
+
+func TestTracingMiddlewareRecordsProviderErrors(t *testing.T) {
+	exporter := recordSpans(t)
+	for _, kind := range []CallKind{TextCall, StreamCall} {
+		handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+			return nil, &providerError{errType: "llm_completion_error", cause: errors.New("boom"), err: errors.New("text completion failed: boom")}
+		}, TracingMiddleware(otel.Tracer("test")))
+		if _, err := handler(context.Background(), &Call{Kind: kind, Operation: "npc.act"}); err == nil {
+			t.Fatalf("%s: no error", kind.label())
+		}
+	}
+	for _, span := range exporter.GetSpans() {
+		if got := spanAttributes(span)["error.type"].Emit(); got != "llm_completion_error" {
+			t.Errorf("error.type = %q", got)
+		}
+		if events := spanEvents(span); !slices.Contains(events, "exception") {
+			t.Errorf("events = %q, want the error recorded", events)
+		}
+	}
+}
+
+// A stream's usage is recorded under its own operation once the stream has been read,
+// like any other call's.
+func TestUsageMiddlewareRecordsStreams(t *testing.T) {
+	server := fakeOpenAI(t, "The lamp flickers.")
+	s := NewService("test-key", nil, option.WithBaseURL(server.URL))
+	ctx := WithOperationType(context.Background(), "npc.narrate")
+
+	if _, err := s.CompleteText(ctx, TextCompletionRequest{SystemPrompt: "system", UserPrompt: "user", MaxTokens: 100}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := s.CompleteStream(ctx, StreamCompletionRequest{SystemPrompt: "system", UserPrompt: "user", MaxTokens: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows := s.Usage().Rows(); len(rows) != 1 || rows[0].Calls != 1 {
+		t.Fatalf("usage before the stream was read = %+v", rows)
+	}
+	for stream.Next() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	stream.Close()
+
+	rows := s.Usage().Rows()
+	if len(rows) != 1 {
+		t.Fatalf("usage = %+v, want one row", rows)
+	}
+	want := UsageTotals{Calls: 2, InputTokens: 20, OutputTokens: 10}
+	if rows[0].Operation != "npc.narrate" || rows[0].UsageTotals != want {
+		t.Errorf("usage = %+v, want npc.narrate %+v", rows[0], want)
+	}
+}
+
+// A stream closed before its usage chunk records nothing rather than a guess.
+func TestUsageMiddlewareSkipsStreamsWithoutUsage(t *testing.T) {
+	tracker := NewUsageTracker()
+	handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+		return &Result{Stream: mockStream("one two three")}, nil
+	}, UsageMiddleware(tracker))
+
+	result, err := handler(context.Background(), &Call{Kind: StreamCall, Operation: "narration.generate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.Stream.Next()
+	result.Stream.Close()
+	if rows := tracker.Rows(); len(rows) != 0 {
+		t.Errorf("usage = %+v", rows)
+	}
+}
+
+func apiError(status int) error {
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example/chat/completions", nil)
+	cause := &openai.Error{StatusCode: status, Request: req, Response: &http.Response{StatusCode: status}}
+	return &providerError{errType: "llm_completion_error", cause: cause, err: fmt.Errorf("text completion failed: %w", cause)}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error // the provider's answer to each attempt; nil succeeds
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success", []error{nil}, 1, false},
+		{"rate limited once", []error{apiError(429), nil}, 2, false},
+		{"server errors until it gives up", []error{apiError(503), apiError(500), apiError(502), nil}, 3, true},
+		{"no answer", []error{&providerError{errType: "llm_completion_error", cause: io.ErrUnexpectedEOF, err: io.ErrUnexpectedEOF}, nil}, 2, false},
+		{"bad request", []error{apiError(400), nil}, 1, true},
+		{"rejected answer", []error{&providerError{errType: "schema_validation_error", cause: errors.New("missing answer"), err: errors.New("missing answer")}, nil}, 1, true},
+		{"not from the provider", []error{errors.New("no completion choices returned"), nil}, 1, true},
+		{"caller gave up", []error{&providerError{errType: "llm_completion_error", cause: context.Canceled, err: context.Canceled}, nil}, 1, true},
+	}
+	for _, tt := range tests {
+		calls := 0
+		handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+			err := tt.errs[calls]
+			calls++
+			if err != nil {
+				return nil, err
+			}
+			return &Result{Content: "ok"}, nil
+		}, RetryMiddleware(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+
+		_, err := handler(context.Background(), &Call{Kind: TextCall, Operation: "npc.act"})
+		if calls != tt.wantCalls || (err != nil) != tt.wantErr {
+			t.Errorf("%s: %d calls, err %v; want %d calls, error %v", tt.name, calls, err, tt.wantCalls, tt.wantErr)
+		}
+	}
+}
+
+func TestRetryMiddlewareStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+		calls++
+		cancel()
+		return nil, apiError(503)
+	}, RetryMiddleware(RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}))
+
+	if _, err := handler(ctx, &Call{Kind: TextCall}); err == nil || calls != 1 {
+		t.Errorf("%d calls, err %v; want 1 call and its error", calls, err)
+	}
+}
+
+// Retries happen inside the call's span, one event each.
+func TestRetriesAreEventsOnTheCallSpan(t *testing.T) {
+	exporter := recordSpans(t)
+	var mu sync.Mutex
+	calls := 0
+	handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			return nil, apiError(429)
+		}
+		return &Result{Content: "ok"}, nil
+	}, TracingMiddleware(otel.Tracer("test")), RetryMiddleware(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+
+	if _, err := handler(context.Background(), &Call{Kind: JSONCall, Operation: "facts.extract"}); err != nil {
+		t.Fatal(err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if events := spanEvents(spans[0]); !slices.Equal(events, []string{"gen_ai.user.message", "llm.retry", "gen_ai.choice"}) {
+		t.Errorf("events = %q", events)
+	}
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	exporter := recordSpans(t)
+	calls := 0
+	cache := NewResponseCache(2, "input.translate", "turn")
+	handler := Chain(func(ctx context.Context, call *Call) (*Result, error) {
+		calls++
+		if strings.Contains(call.UserPrompt, "fail") {
+			return nil, apiError(500)
+		}
+		return &Result{Content: "answer to " + call.UserPrompt, InputTokens: 10, OutputTokens: 5}, nil
+	}, TracingMiddleware(otel.Tracer("test")), CacheMiddleware(cache))
+
+	complete := func(kind CallKind, operation, prompt string) *Result {
+		t.Helper()
+		result, _ := handler(context.Background(), &Call{Kind: kind, Operation: operation, UserPrompt: prompt})
+		return result
+	}
+
+	first := complete(TextCall, "input.translate", "hola")
+	hit := complete(TextCall, "input.translate", "hola")
+	if calls != 1 {
+		t.Fatalf("repeated call reached the provider: %d calls", calls)
+	}
+	if hit.Content != first.Content || hit.InputTokens != 0 || hit.OutputTokens != 0 {
+		t.Errorf("hit = %+v, want the cached content and no tokens", hit)
+	}
+	if first.InputTokens != 10 {
+		t.Error("serving a hit changed the first result")
+	}
+	spans := exporter.GetSpans()
+	if cacheHit := spanAttributes(spans[1])["llm.cache_hit"]; !cacheHit.AsBool() {
+		t.Error("the hit's span is not marked llm.cache_hit")
+	}
+	if _, ok := spanAttributes(spans[0])["llm.cache_hit"]; ok {
+		t.Error("the miss's span is marked llm.cache_hit")
+	}
+
+	misses := []struct {
+		kind      CallKind
+		operation string
+		prompt    string
+	}{
+		{TextCall, "input.translate", "adios"}, // another prompt
+		{JSONCall, "input.translate", "hola"},  // another kind
+		{TextCall, "npc.think", "hola"},        // an operation the cache doesn't cover
+		{TextCall, "npc.think", "hola"},        // and it stays uncached
+		{StreamCall, "turn.classify", "hola"},  // streams are never cached
+		{TextCall, "turn.classify", "fail"},    // failures aren't kept
+		{TextCall, "turn.classify", "fail"},    // so they are asked again
+	}
+	for _, miss := range misses {
+		before := calls
+		complete(miss.kind, miss.operation, miss.prompt)
+		if calls != before+1 {
+			t.Errorf("%s %s %q was answered from the cache", miss.kind.label(), miss.operation, miss.prompt)
+		}
+	}
+
+	// The cache holds two answers; the oldest, "hola", is gone
+	before := calls
+	complete(TextCall, "input.translate", "hola")
+	if calls != before+1 {
+		t.Error("the oldest answer was not evicted")
+	}
+}
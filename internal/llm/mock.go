@@ -0,0 +1,191 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/ssestream"
+)
+
+// MockCall is one request a MockService answered.
+type MockCall struct {
+	Method       string // "text", "json", "json_schema" or "stream"
+	Operation    string
+	SystemPrompt string
+	UserPrompt   string
+	Response     string
+}
+
+type mockResponse struct {
+	content string
+	err     error
+}
+
+type promptResponse struct {
+	substring string
+	mockResponse
+}
+
+// MockService is a Client that answers from canned responses instead of calling a
+// model. A request is answered by the first prompt rule whose substring appears in its
+// system or user prompt, then by the response for its operation type (the one set with
+// WithOperationType; "director" also answers "director.player_input"). Requests
+// nothing matches fail, so a test notices the call it didn't expect.
+type MockService struct {
+	mu          sync.Mutex
+	byOperation map[string]mockResponse
+	byPrompt    []promptResponse
+	calls       []MockCall
+	usage       *UsageTracker
+}
+
+// NewMockService creates a mock with no responses.
+func NewMockService() *MockService {
+	return &MockService{byOperation: make(map[string]mockResponse), usage: NewUsageTracker()}
+}
+
+// OnOperation answers requests made under the operation type with content.
+func (m *MockService) OnOperation(operation, content string) *MockService {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byOperation[operation] = mockResponse{content: content}
+	return m
+}
+
+// OnPrompt answers requests whose prompt contains substring with content. Prompt rules
+// are tried in the order they were added, before operation responses.
+func (m *MockService) OnPrompt(substring, content string) *MockService {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byPrompt = append(m.byPrompt, promptResponse{substring: substring, mockResponse: mockResponse{content: content}})
+	return m
+}
+
+// FailOperation makes requests under the operation type return err.
+func (m *MockService) FailOperation(operation string, err error) *MockService {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byOperation[operation] = mockResponse{err: err}
+	return m
+}
+
+// Calls returns the requests answered so far, in order.
+func (m *MockService) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+func (m *MockService) respond(ctx context.Context, method, systemPrompt, userPrompt string) (string, error) {
+	operation := getOperationType(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	response, ok := m.match(operation, systemPrompt+"\n"+userPrompt)
+	if !ok {
+		return "", fmt.Errorf("mock: no response for %s request (operation %q)", method, operation)
+	}
+	if response.err != nil {
+		return "", response.err
+	}
+	m.calls = append(m.calls, MockCall{Method: method, Operation: operation, SystemPrompt: systemPrompt, UserPrompt: userPrompt, Response: response.content})
+	m.usage.Record(operation, "mock", int64(len(systemPrompt)+len(userPrompt))/4, int64(len(response.content))/4)
+	return response.content, nil
+}
+
+func (m *MockService) match(operation, prompt string) (mockResponse, bool) {
+	for _, rule := range m.byPrompt {
+		if strings.Contains(prompt, rule.substring) {
+			return rule.mockResponse, true
+		}
+	}
+	for op := operation; op != ""; {
+		if response, ok := m.byOperation[op]; ok {
+			return response, true
+		}
+		dot := strings.LastIndex(op, ".")
+		if dot < 0 {
+			break
+		}
+		op = op[:dot]
+	}
+	return mockResponse{}, false
+}
+
+func (m *MockService) CompleteText(ctx context.Context, req TextCompletionRequest) (string, error) {
+	return m.respond(ctx, "text", req.SystemPrompt, req.UserPrompt)
+}
+
+func (m *MockService) CompleteJSON(ctx context.Context, req JSONCompletionRequest) (string, error) {
+	return m.respond(ctx, "json", req.SystemPrompt, req.UserPrompt)
+}
+
+func (m *MockService) CompleteJSONSchema(ctx context.Context, req JSONSchemaCompletionRequest) (string, error) {
+	content, err := m.respond(ctx, "json_schema", req.SystemPrompt, req.UserPrompt)
+	if err != nil {
+		return "", err
+	}
+	// Hold canned responses to the schema, as strict mode would
+	if err := validateJSONSchema(content, req.Schema); err != nil {
+		return "", fmt.Errorf("mock %s: %w", req.SchemaName, err)
+	}
+	return content, nil
+}
+
+// CompleteStream streams the canned response word by word, like a real stream.
+func (m *MockService) CompleteStream(ctx context.Context, req StreamCompletionRequest) (*ssestream.Stream[openai.ChatCompletionChunk], error) {
+	content, err := m.respond(ctx, "stream", req.SystemPrompt, req.UserPrompt)
+	if err != nil {
+		return nil, err
+	}
+	return mockStream(content), nil
+}
+
+// mockStream streams content word by word in chat completion chunks.
+func mockStream(content string) *ssestream.Stream[openai.ChatCompletionChunk] {
+	var body strings.Builder
+	for _, word := range strings.SplitAfter(content, " ") {
+		chunk, _ := json.Marshal(map[string]interface{}{
+			"id": "mock", "object": "chat.completion.chunk", "model": "mock",
+			"choices": []map[string]interface{}{{"index": 0, "delta": map[string]string{"content": word}}},
+		})
+		fmt.Fprintf(&body, "data: %s\n\n", chunk)
+	}
+	body.WriteString("data: [DONE]\n\n")
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       io.NopCloser(strings.NewReader(body.String())),
+	}
+	return ssestream.NewStream[openai.ChatCompletionChunk](ssestream.NewDecoder(res), nil)
+}
+
+// Embed returns a small vector derived from the text, so identical texts match and
+// different ones mostly don't.
+func (m *MockService) Embed(ctx context.Context, text string) ([]float64, error) {
+	vector := make([]float64, 8)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		vector[h.Sum32()%uint32(len(vector))]++
+	}
+	return vector, nil
+}
+
+// Resolve keeps the requested settings, naming the model "mock" when none is set.
+func (m *MockService) Resolve(ctx context.Context, requested ModelSettings) ModelSettings {
+	if requested.Model == "" {
+		requested.Model = "mock"
+	}
+	return requested
+}
+
+func (m *MockService) Usage() *UsageTracker {
+	return m.usage
+}
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ModelSettings are the request settings an operation type runs with. Zero fields leave
+// the call site's own value in place. Model is passed through as-is, so any name the
+// endpoint serves works, e.g. "llama3.1:70b" against Ollama.
+type ModelSettings struct {
+	Model           string `json:"model,omitempty"`
+	MaxTokens       int    `json:"max_tokens,omitempty"`
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
+}
+
+// ModelConfig maps operation types (the ones set with WithOperationType, e.g.
+// "narration.generate" or "npc.perceive") to settings. A key also covers every
+// operation type under it, so "director" applies to "director.player_input" and
+// "director.npc_action"; the most specific key wins, then "default".
+type ModelConfig map[string]ModelSettings
+
+// defaultOperation is the config key covering every operation type without its own entry.
+const defaultOperation = "default"
+
+// operationAliases lets the config use the names people know operations by.
+var operationAliases = map[string]string{
+	"perception": "npc.perceive",
+}
+
+// LoadModelConfig reads a JSON model config, e.g.
+//
+//	{"narration": {"model": "gpt-5-2025-08-07"}, "perception": {"model": "gpt-5-nano", "reasoning_effort": "minimal"}}
+func LoadModelConfig(path string) (ModelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read model config: %w", err)
+	}
+	var raw map[string]ModelSettings
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse model config %s: %w", path, err)
+	}
+	config := make(ModelConfig, len(raw))
+	for op, settings := range raw {
+		op = strings.TrimSpace(op)
+		if alias, ok := operationAliases[op]; ok {
+			op = alias
+		}
+		config[op] = settings
+	}
+	return config, nil
+}
+
+// LoadModelConfigFromEnv reads the model config named by LLM_MODEL_CONFIG. It returns
+// nil, and no error, when the variable is unset.
+func LoadModelConfigFromEnv() (ModelConfig, error) {
+	path := strings.TrimSpace(os.Getenv("LLM_MODEL_CONFIG"))
+	if path == "" {
+		return nil, nil
+	}
+	return LoadModelConfig(path)
+}
+
+// lookup returns the settings of the most specific key covering the operation type,
+// falling back to the default entry.
+func (c ModelConfig) lookup(operationType string) (ModelSettings, bool) {
+	for op := operationType; op != ""; {
+		if settings, ok := c[op]; ok {
+			return settings, true
+		}
+		dot := strings.LastIndex(op, ".")
+		if dot < 0 {
+			break
+		}
+		op = op[:dot]
+	}
+	if settings, ok := c[defaultOperation]; ok {
+		return settings, true
+	}
+	return ModelSettings{}, false
+}
+
+// SetModelConfig installs per-operation model settings. Operations without an entry
+// keep the settings their call site asks for.
+func (s *Service) SetModelConfig(config ModelConfig) {
+	s.models = config
+}
+
+// Resolve returns the settings a request runs with under the operation type carried by
+// ctx: configured values where set, then the call site's, then the service's default model.
+// Reasoning effort is dropped for endpoints that don't support it (see Compatibility).
+func (s *Service) Resolve(ctx context.Context, requested ModelSettings) ModelSettings {
+	settings := requested
+	if configured, ok := s.models.lookup(getOperationType(ctx)); ok {
+		if strings.TrimSpace(configured.Model) != "" {
+			settings.Model = configured.Model
+		}
+		if configured.MaxTokens > 0 {
+			settings.MaxTokens = configured.MaxTokens
+		}
+		if configured.ReasoningEffort != "" {
+			settings.ReasoningEffort = configured.ReasoningEffort
+		}
+	}
+	if strings.TrimSpace(settings.Model) == "" {
+		settings.Model = s.model
+	}
+	if s.compat.NoReasoningEffort {
+		settings.ReasoningEffort = ""
+	}
+	return settings
+}
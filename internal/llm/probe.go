@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// probeMaxTokens keeps probe completions as cheap as a completion can be.
+const probeMaxTokens = 5
+
+// Models lists every model the service may call, sorted: its default model and each
+// one named in the model config.
+func (s *Service) Models() []string {
+	seen := map[string]bool{s.model: true}
+	for _, settings := range s.models {
+		if model := strings.TrimSpace(settings.Model); model != "" {
+			seen[model] = true
+		}
+	}
+	models := make([]string, 0, len(seen))
+	for model := range seen {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// ProbeModel sends a tiny completion to model, bypassing the model config, to check the
+// endpoint serves it. Reasoning effort is left off so models without it accept the call.
+func (s *Service) ProbeModel(ctx context.Context, model string) error {
+	_, err := Chain(s.provide, s.middleware...)(ctx, &Call{
+		Kind:         TextCall,
+		Operation:    "doctor.model",
+		SystemPrompt: "Reply with OK.",
+		UserPrompt:   "ping",
+		MaxTokens:    probeMaxTokens,
+		Model:        model,
+	})
+	return err
+}
+
+// ProbeJSONSchema checks schema completions work the way the game will send them, and
+// returns the response format they use: "json_schema", or "json_object" when the
+// endpoint is configured without strict mode (see Compatibility).
+func (s *Service) ProbeJSONSchema(ctx context.Context) (string, error) {
+	format := "json_schema"
+	if s.compat.NoJSONSchema {
+		format = "json_object"
+	}
+	content, err := s.CompleteJSONSchema(WithOperationType(ctx, "doctor.json_schema"), JSONSchemaCompletionRequest{
+		SystemPrompt: `Answer {"ok": true}.`,
+		UserPrompt:   "ping",
+		MaxTokens:    200,
+		SchemaName:   "probe",
+		Schema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"ok": map[string]interface{}{"type": "boolean"}},
+			"required":             []string{"ok"},
+			"additionalProperties": false,
+		},
+	})
+	if err != nil {
+		return format, err
+	}
+	var answer struct {
+		OK *bool `json:"ok"`
+	}
+	if err := json.Unmarshal([]byte(content), &answer); err != nil || answer.OK == nil {
+		return format, fmt.Errorf("%s response doesn't match the schema: %.80q", format, content)
+	}
+	return format, nil
+}
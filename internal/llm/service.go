@@ -1,10 +1,8 @@
 package llm
 
 import (
-    "context"
-    "fmt"
-    "time"
-    "strings"
+	"context"
+	"fmt"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -27,472 +25,263 @@ const (
 	sessionIDKey     contextKey = "session_id"
 )
 
+// Service completes requests against the OpenAI API. Every completion, whatever its
+// kind, runs through the same middleware chain around a single provider call: logging,
+// tracing, usage accounting, caching and retries, then any middleware added with Use.
 type Service struct {
-	client *openai.Client
-	model  string
-	debug  *debug.Logger
-	tracer trace.Tracer
+	client     *openai.Client
+	model      string
+	models     ModelConfig
+	compat     Compatibility
+	usage      *UsageTracker
+	debug      *debug.Logger
+	tracer     trace.Tracer
+	middleware []Middleware
 }
 
-func NewService(apiKey string, debug *debug.Logger) *Service {
-    client := openai.NewClient(option.WithAPIKey(apiKey))
-    return &Service{
+// NewService creates the LLM service. Extra request options (e.g. middleware) are
+// applied to every request made by the underlying client. Retries are the chain's, so
+// the client makes none of its own.
+func NewService(apiKey string, debug *debug.Logger, opts ...option.RequestOption) *Service {
+	client := openai.NewClient(append([]option.RequestOption{option.WithAPIKey(apiKey), option.WithMaxRetries(0)}, opts...)...)
+	s := &Service{
 		client: &client,
 		model:  "gpt-5-2025-08-07",
+		usage:  NewUsageTracker(),
 		debug:  debug,
 		tracer: otel.Tracer("llm-service"),
 	}
+	s.middleware = []Middleware{
+		LoggingMiddleware(debug),
+		TracingMiddleware(s.tracer),
+		UsageMiddleware(s.usage),
+		CacheMiddleware(NewResponseCache(defaultCacheSize, cachedOperations...)),
+		RetryMiddleware(DefaultRetryPolicy),
+	}
+	return s
+}
+
+// Use adds middleware to every completion, inside the built-in middleware and in the
+// order given, so the span covers what it does, usage counts every answer the provider
+// gives and a retry runs it again.
+func (s *Service) Use(middleware ...Middleware) {
+	s.middleware = append(s.middleware, middleware...)
 }
 
 type TextCompletionRequest struct {
-    SystemPrompt    string
-    UserPrompt      string
-    MaxTokens       int
-    Model           string // optional override
-    ReasoningEffort string // optional: minimal, low, medium, high
+	SystemPrompt    string
+	UserPrompt      string
+	MaxTokens       int
+	Model           string   // optional override
+	ReasoningEffort string   // optional: minimal, low, medium, high
+	Temperature     *float64 // optional; nil leaves the provider default
 }
 
 type JSONCompletionRequest struct {
-    SystemPrompt    string
-    UserPrompt      string
-    MaxTokens       int
-    Model           string // optional override
-    ReasoningEffort string // optional: minimal, low, medium, high
+	SystemPrompt    string
+	UserPrompt      string
+	MaxTokens       int
+	Model           string   // optional override
+	ReasoningEffort string   // optional: minimal, low, medium, high
+	Temperature     *float64 // optional; nil leaves the provider default
 }
 
 type StreamCompletionRequest struct {
-    SystemPrompt    string
-    UserPrompt      string
-    MaxTokens       int
-    Model           string // optional override
-    ReasoningEffort string // optional: minimal, low, medium, high
+	SystemPrompt    string
+	UserPrompt      string
+	MaxTokens       int
+	Model           string   // optional override
+	ReasoningEffort string   // optional: minimal, low, medium, high
+	Temperature     *float64 // optional; nil leaves the provider default
 }
 
 type JSONSchemaCompletionRequest struct {
-    SystemPrompt    string
-    UserPrompt      string
-    MaxTokens       int
-    Model           string // optional override
-    ReasoningEffort string // optional: minimal, low, medium, high
-    SchemaName      string
-    Schema          interface{}
+	SystemPrompt    string
+	UserPrompt      string
+	MaxTokens       int
+	Model           string   // optional override
+	ReasoningEffort string   // optional: minimal, low, medium, high
+	Temperature     *float64 // optional; nil leaves the provider default
+	SchemaName      string
+	Schema          interface{}
 }
 
 func (s *Service) CompleteText(ctx context.Context, req TextCompletionRequest) (string, error) {
-    operationType := "text_completion"
-    if opType := getOperationType(ctx); opType != "" {
-        operationType = opType
-    }
-	
-	sc := trace.SpanFromContext(ctx).SpanContext()
-	if s.debug != nil {
-		if !sc.IsValid() {
-			s.debug.Printf("NO PARENT: ctx missing active span for %s", operationType)
-		} else {
-			s.debug.Printf("CompleteText trace=%s parentSpan=%s op=%s", sc.TraceID(), sc.SpanID(), operationType)
-		}
-	}
-	
-    spanName := operationType
-    if spanName == "" {
-        spanName = "llm.complete_text"
-    }
-    model := s.model
-    if strings.TrimSpace(req.Model) != "" {
-        model = req.Model
-    }
-    ctx, span := s.tracer.Start(ctx, spanName,
-        trace.WithSpanKind(trace.SpanKindClient),
-        trace.WithAttributes(
-            observability.CreateGenAIAttributes("openai", model, 0, 0, 0.0)...,
-        ),
-    )
-	defer span.End()
-
-	attrs := []attribute.KeyValue{
-		attribute.Int("gen_ai.request.max_tokens", req.MaxTokens),
-		attribute.String("langfuse.observation.type", "generation"),
-		attribute.String("game.operation_type", operationType),
-	}
-	
-	if sessionID := getSessionID(ctx); sessionID != "" {
-		attrs = append(attrs, 
-			attribute.String("langfuse.session.id", sessionID),
-			attribute.String("session.id", sessionID),
-		)
-	}
-	
-	if gameCtx := getGameContext(ctx); gameCtx != nil {
-		for k, v := range gameCtx {
-			switch val := v.(type) {
-			case string:
-				attrs = append(attrs, attribute.String("game."+k, val))
-			case int:
-				attrs = append(attrs, attribute.Int("game."+k, val))
-			case []string:
-				attrs = append(attrs, attribute.StringSlice("game."+k, val))
-			}
-		}
-	}
-	
-	span.SetAttributes(attrs...)
-
-	span.AddEvent("gen_ai.user.message", trace.WithAttributes(
-		attribute.String("gen_ai.system", "openai"),
-		attribute.String("content", req.UserPrompt),
-	))
-
-	startTime := time.Now()
-
-    openaiReq := openai.ChatCompletionNewParams{
-        Model: shared.ChatModel(model),
-        Messages: []openai.ChatCompletionMessageParamUnion{
-            openai.SystemMessage(req.SystemPrompt),
-            openai.UserMessage(req.UserPrompt),
-        },
-        MaxCompletionTokens: openai.Int(int64(req.MaxTokens)),
-    }
-    
-    if req.ReasoningEffort != "" {
-        openaiReq.ReasoningEffort = shared.ReasoningEffort(req.ReasoningEffort)
-    }
-
-	if s.debug != nil {
-		s.debug.Printf("LLM Text Completion - MaxTokens: %d, SystemPrompt length: %d", req.MaxTokens, len(req.SystemPrompt))
-	}
-
-	resp, err := s.client.Chat.Completions.New(ctx, openaiReq)
+	result, err := s.complete(ctx, &Call{
+		Kind:            TextCall,
+		SystemPrompt:    req.SystemPrompt,
+		UserPrompt:      req.UserPrompt,
+		MaxTokens:       req.MaxTokens,
+		Model:           req.Model,
+		ReasoningEffort: req.ReasoningEffort,
+		Temperature:     req.Temperature,
+	})
 	if err != nil {
-		span.SetAttributes(attribute.String("error.type", "llm_completion_error"))
-		span.RecordError(err)
-		if s.debug != nil {
-			s.debug.Printf("LLM Text Completion error: %v", err)
-		}
-		return "", fmt.Errorf("text completion failed: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		err := fmt.Errorf("no completion choices returned")
-		span.RecordError(err)
 		return "", err
 	}
-
-	content := resp.Choices[0].Message.Content
-	duration := time.Since(startTime)
-	
-	if s.debug != nil {
-		s.debug.Printf("JSON Response Debug: content=%q, finish_reason=%s, choices_count=%d", 
-			content, resp.Choices[0].FinishReason, len(resp.Choices))
-	}
-
-    span.SetAttributes(
-        attribute.Int64("gen_ai.usage.input_tokens", resp.Usage.PromptTokens),
-        attribute.Int64("gen_ai.usage.output_tokens", resp.Usage.CompletionTokens),
-        attribute.Int64("response_time_ms", duration.Milliseconds()),
-        attribute.String("langfuse.observation.input", req.SystemPrompt+"\n\n"+req.UserPrompt),
-        attribute.String("langfuse.observation.output", content),
-        attribute.String("langfuse.observation.output_format", "text"),
-        attribute.String("langfuse.observation.model.name", model),
-    )
-
-	span.AddEvent("gen_ai.choice", trace.WithAttributes(
-		attribute.String("gen_ai.system", "openai"),
-		attribute.String("content", content),
-	))
-
-	if s.debug != nil {
-		s.debug.Printf("LLM Text Completion response length: %d, tokens: %d/%d, duration: %v", 
-			len(content), resp.Usage.PromptTokens, resp.Usage.CompletionTokens, duration)
-	}
-
-	return content, nil
+	return result.Content, nil
 }
 
 func (s *Service) CompleteJSON(ctx context.Context, req JSONCompletionRequest) (string, error) {
-    operationType := "json_completion"
-    if opType := getOperationType(ctx); opType != "" {
-        operationType = opType
-    }
-	
-	sc := trace.SpanFromContext(ctx).SpanContext()
-	if s.debug != nil {
-		if !sc.IsValid() {
-			s.debug.Printf("NO PARENT: ctx missing active span for %s", operationType)
-		} else {
-			s.debug.Printf("CompleteJSON trace=%s parentSpan=%s op=%s", sc.TraceID(), sc.SpanID(), operationType)
-		}
-	}
-	
-    spanName := operationType
-    if spanName == "" {
-        spanName = "llm.complete_json"
-    }
-    model := s.model
-    if strings.TrimSpace(req.Model) != "" {
-        model = req.Model
-    }
-    ctx, span := s.tracer.Start(ctx, spanName,
-        trace.WithSpanKind(trace.SpanKindClient),
-        trace.WithAttributes(
-            observability.CreateGenAIAttributes("openai", model, 0, 0, 0.0)...,
-        ),
-    )
-	defer span.End()
-
-	attrs := []attribute.KeyValue{
-		attribute.Int("gen_ai.request.max_tokens", req.MaxTokens),
-		attribute.String("langfuse.observation.type", "generation"),
-		attribute.String("response_format", "json"),
-		attribute.String("game.operation_type", operationType),
-	}
-	
-	if sessionID := getSessionID(ctx); sessionID != "" {
-		attrs = append(attrs, 
-			attribute.String("langfuse.session.id", sessionID),
-			attribute.String("session.id", sessionID),
-		)
-	}
-	
-	if gameCtx := getGameContext(ctx); gameCtx != nil {
-		for k, v := range gameCtx {
-			switch val := v.(type) {
-			case string:
-				attrs = append(attrs, attribute.String("game."+k, val))
-			case int:
-				attrs = append(attrs, attribute.Int("game."+k, val))
-			case []string:
-				attrs = append(attrs, attribute.StringSlice("game."+k, val))
-			}
-		}
+	result, err := s.complete(ctx, &Call{
+		Kind:            JSONCall,
+		SystemPrompt:    req.SystemPrompt,
+		UserPrompt:      req.UserPrompt,
+		MaxTokens:       req.MaxTokens,
+		Model:           req.Model,
+		ReasoningEffort: req.ReasoningEffort,
+		Temperature:     req.Temperature,
+		ResponseFormat:  "json",
+	})
+	if err != nil {
+		return "", err
 	}
-	
-	span.SetAttributes(attrs...)
-
-	span.AddEvent("gen_ai.user.message", trace.WithAttributes(
-		attribute.String("gen_ai.system", "openai"),
-		attribute.String("content", req.UserPrompt),
-	))
-
-	startTime := time.Now()
-
-    openaiReq := openai.ChatCompletionNewParams{
-        Model: shared.ChatModel(model),
-        Messages: []openai.ChatCompletionMessageParamUnion{
-            openai.SystemMessage(req.SystemPrompt),
-            openai.UserMessage(req.UserPrompt),
-        },
-        MaxCompletionTokens: openai.Int(int64(req.MaxTokens)),
-        ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
-            OfJSONObject: func() *shared.ResponseFormatJSONObjectParam {
-                p := shared.NewResponseFormatJSONObjectParam()
-                return &p
-            }(),
-        },
-    }
-    
-    if req.ReasoningEffort != "" {
-        openaiReq.ReasoningEffort = shared.ReasoningEffort(req.ReasoningEffort)
-    }
+	return result.Content, nil
+}
 
-	if s.debug != nil {
-		s.debug.Printf("LLM JSON Completion - MaxTokens: %d, SystemPrompt length: %d", req.MaxTokens, len(req.SystemPrompt))
-		s.debug.Printf("LLM JSON Request - MaxCompletionTokens param: %v", openaiReq.MaxCompletionTokens)
-		s.debug.Printf("LLM JSON Request - Model: %v", openaiReq.Model)
-		s.debug.Printf("LLM JSON Request - ResponseFormat: %+v", openaiReq.ResponseFormat)
+func (s *Service) CompleteJSONSchema(ctx context.Context, req JSONSchemaCompletionRequest) (string, error) {
+	responseFormat := "json_schema"
+	if s.compat.NoJSONSchema {
+		responseFormat = "json_object"
 	}
-
-	resp, err := s.client.Chat.Completions.New(ctx, openaiReq)
+	result, err := s.complete(ctx, &Call{
+		Kind:            JSONSchemaCall,
+		SystemPrompt:    req.SystemPrompt,
+		UserPrompt:      req.UserPrompt,
+		MaxTokens:       req.MaxTokens,
+		Model:           req.Model,
+		ReasoningEffort: req.ReasoningEffort,
+		Temperature:     req.Temperature,
+		ResponseFormat:  responseFormat,
+		SchemaName:      req.SchemaName,
+		Schema:          req.Schema,
+	})
 	if err != nil {
-		span.SetAttributes(attribute.String("error.type", "llm_completion_error"))
-		span.RecordError(err)
-		if s.debug != nil {
-			s.debug.Printf("LLM JSON Completion error: %v", err)
-		}
-		return "", fmt.Errorf("JSON completion failed: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		err := fmt.Errorf("no completion choices returned")
-		span.RecordError(err)
 		return "", err
 	}
+	return result.Content, nil
+}
 
-
-	content := resp.Choices[0].Message.Content
-	duration := time.Since(startTime)
-	
-	if s.debug != nil {
-		s.debug.Printf("JSON Response Debug: content=%q, finish_reason=%s, choices_count=%d", 
-			content, resp.Choices[0].FinishReason, len(resp.Choices))
-		if resp.Choices[0].FinishReason == "length" {
-			s.debug.Printf("JSON Length Debug: input_tokens=%d, completion_tokens=%d, total_available=%d", 
-				resp.Usage.PromptTokens, resp.Usage.CompletionTokens, req.MaxTokens)
-			s.debug.Printf("JSON Length Debug: message_refusal=%q", resp.Choices[0].Message.Refusal)
-		}
+func (s *Service) CompleteStream(ctx context.Context, req StreamCompletionRequest) (*ssestream.Stream[openai.ChatCompletionChunk], error) {
+	result, err := s.complete(ctx, &Call{
+		Kind:            StreamCall,
+		SystemPrompt:    req.SystemPrompt,
+		UserPrompt:      req.UserPrompt,
+		MaxTokens:       req.MaxTokens,
+		Model:           req.Model,
+		ReasoningEffort: req.ReasoningEffort,
+		Temperature:     req.Temperature,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return result.Stream, nil
+}
 
-    span.SetAttributes(
-        attribute.Int64("gen_ai.usage.input_tokens", resp.Usage.PromptTokens),
-        attribute.Int64("gen_ai.usage.output_tokens", resp.Usage.CompletionTokens),
-        attribute.Int64("response_time_ms", duration.Milliseconds()),
-        attribute.String("langfuse.observation.input", req.SystemPrompt+"\n\n"+req.UserPrompt),
-        attribute.String("langfuse.observation.output", content),
-        attribute.String("langfuse.observation.output_format", "json"),
-        attribute.String("langfuse.observation.model.name", model),
-    )
-
-	span.AddEvent("gen_ai.choice", trace.WithAttributes(
-		attribute.String("gen_ai.system", "openai"),
-		attribute.String("content", content),
-	))
-
-	if s.debug != nil {
-		s.debug.Printf("LLM JSON Completion response length: %d, tokens: %d/%d, duration: %v", 
-			len(content), resp.Usage.PromptTokens, resp.Usage.CompletionTokens, duration)
+// complete resolves the call's operation and model settings and runs it through the
+// middleware chain.
+func (s *Service) complete(ctx context.Context, call *Call) (*Result, error) {
+	call.Operation = getOperationType(ctx)
+	if call.Operation == "" {
+		call.Operation = call.Kind.defaultOperation()
 	}
-
-	return content, nil
+	settings := s.Resolve(ctx, ModelSettings{Model: call.Model, MaxTokens: call.MaxTokens, ReasoningEffort: call.ReasoningEffort})
+	call.Model, call.MaxTokens, call.ReasoningEffort = settings.Model, settings.MaxTokens, settings.ReasoningEffort
+	return Chain(s.provide, s.middleware...)(ctx, call)
 }
 
-func (s *Service) CompleteJSONSchema(ctx context.Context, req JSONSchemaCompletionRequest) (string, error) {
-    operationType := "json_schema_completion"
-    if opType := getOperationType(ctx); opType != "" {
-        operationType = opType
-    }
-	
-	sc := trace.SpanFromContext(ctx).SpanContext()
-	if s.debug != nil {
-		if !sc.IsValid() {
-			s.debug.Printf("NO PARENT: ctx missing active span for %s", operationType)
-		} else {
-			s.debug.Printf("CompleteJSONSchema trace=%s parentSpan=%s op=%s", sc.TraceID(), sc.SpanID(), operationType)
-		}
+// provide sends a call to the API. It is the innermost handler of the chain, and the
+// only place that knows the shape of an OpenAI request.
+func (s *Service) provide(ctx context.Context, call *Call) (*Result, error) {
+	params := openai.ChatCompletionNewParams{
+		Model: shared.ChatModel(call.Model),
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(call.SystemPrompt),
+			openai.UserMessage(call.UserPrompt),
+		},
+		MaxCompletionTokens: openai.Int(int64(call.MaxTokens)),
 	}
-	
-    spanName := operationType
-    if spanName == "" {
-        spanName = "llm.complete_json_schema"
-    }
-    model := s.model
-    if strings.TrimSpace(req.Model) != "" {
-        model = req.Model
-    }
-    ctx, span := s.tracer.Start(ctx, spanName,
-        trace.WithSpanKind(trace.SpanKindClient),
-        trace.WithAttributes(
-            observability.CreateGenAIAttributes("openai", model, 0, 0, 0.0)...,
-        ),
-    )
-	defer span.End()
-
-	attrs := []attribute.KeyValue{
-		attribute.Int("gen_ai.request.max_tokens", req.MaxTokens),
-		attribute.String("langfuse.observation.type", "generation"),
-		attribute.String("response_format", "json_schema"),
-		attribute.String("game.operation_type", operationType),
+	if call.ReasoningEffort != "" {
+		params.ReasoningEffort = shared.ReasoningEffort(call.ReasoningEffort)
 	}
-	
-	if sessionID := getSessionID(ctx); sessionID != "" {
-		attrs = append(attrs, 
-			attribute.String("langfuse.session.id", sessionID),
-			attribute.String("session.id", sessionID),
-		)
+	if call.Temperature != nil {
+		params.Temperature = openai.Float(*call.Temperature)
 	}
-	
-	if gameCtx := getGameContext(ctx); gameCtx != nil {
-		for k, v := range gameCtx {
-			switch val := v.(type) {
-			case string:
-				attrs = append(attrs, attribute.String("game."+k, val))
-			case int:
-				attrs = append(attrs, attribute.Int("game."+k, val))
-			case []string:
-				attrs = append(attrs, attribute.StringSlice("game."+k, val))
-			}
+
+	switch call.ResponseFormat {
+	case "json":
+		p := shared.NewResponseFormatJSONObjectParam()
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{OfJSONObject: &p}
+	case "json_schema":
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+				Type: constant.JSONSchema("json_schema"),
+				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   call.SchemaName,
+					Schema: call.Schema,
+					Strict: openai.Bool(true),
+				},
+			},
+		}
+	case "json_object":
+		// No strict mode: ask for a JSON object, describe the schema, validate the reply
+		instructions, err := schemaInstructions(call.SchemaName, call.Schema)
+		if err != nil {
+			return nil, err
 		}
+		params.Messages[0] = openai.SystemMessage(call.SystemPrompt + instructions)
+		p := shared.NewResponseFormatJSONObjectParam()
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{OfJSONObject: &p}
 	}
-	
-	span.SetAttributes(attrs...)
-
-	span.AddEvent("gen_ai.user.message", trace.WithAttributes(
-		attribute.String("gen_ai.system", "openai"),
-		attribute.String("content", req.UserPrompt),
-	))
-
-	startTime := time.Now()
-
-    openaiReq := openai.ChatCompletionNewParams{
-        Model: shared.ChatModel(model),
-        Messages: []openai.ChatCompletionMessageParamUnion{
-            openai.SystemMessage(req.SystemPrompt),
-            openai.UserMessage(req.UserPrompt),
-        },
-        MaxCompletionTokens: openai.Int(int64(req.MaxTokens)),
-        ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
-            OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
-                Type: constant.JSONSchema("json_schema"),
-                JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
-                    Name: req.SchemaName,
-                    Schema: req.Schema,
-                    Strict: openai.Bool(true),
-                },
-            },
-        },
-    }
-    
-    if req.ReasoningEffort != "" {
-        openaiReq.ReasoningEffort = shared.ReasoningEffort(req.ReasoningEffort)
-    }
 
-	if s.debug != nil {
-		s.debug.Printf("LLM JSON Schema Completion - MaxTokens: %d, Schema: %s", req.MaxTokens, req.SchemaName)
+	if call.Kind == StreamCall {
+		// The last chunk carries the token usage; the stream's reader records it
+		params.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
+		stream := s.client.Chat.Completions.NewStreaming(ctx, params)
+		if err := stream.Err(); err != nil {
+			// The request itself failed, before anything streamed
+			return nil, &providerError{errType: "llm_completion_error", cause: err, err: fmt.Errorf("%s completion failed: %w", call.Kind.errorPrefix(), err)}
+		}
+		return &Result{Stream: stream}, nil
 	}
 
-	resp, err := s.client.Chat.Completions.New(ctx, openaiReq)
+	resp, err := s.client.Chat.Completions.New(ctx, params)
 	if err != nil {
-		span.SetAttributes(attribute.String("error.type", "llm_completion_error"))
-		span.RecordError(err)
-		if s.debug != nil {
-			s.debug.Printf("LLM JSON Schema Completion error: %v", err)
-		}
-		return "", fmt.Errorf("JSON schema completion failed: %w", err)
+		return nil, &providerError{errType: "llm_completion_error", cause: err, err: fmt.Errorf("%s completion failed: %w", call.Kind.errorPrefix(), err)}
 	}
-
 	if len(resp.Choices) == 0 {
-		err := fmt.Errorf("no completion choices returned")
-		span.RecordError(err)
-		return "", err
+		return nil, fmt.Errorf("no completion choices returned")
 	}
 
-	content := resp.Choices[0].Message.Content
-	duration := time.Since(startTime)
-	
-	if s.debug != nil {
-		s.debug.Printf("JSON Schema Response: content=%q, finish_reason=%s", 
-			content, resp.Choices[0].FinishReason)
+	choice := resp.Choices[0]
+	result := &Result{
+		Content:      choice.Message.Content,
+		FinishReason: string(choice.FinishReason),
+		Refusal:      choice.Message.Refusal,
+		Choices:      len(resp.Choices),
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
 	}
-
-    span.SetAttributes(
-        attribute.Int64("gen_ai.usage.input_tokens", resp.Usage.PromptTokens),
-        attribute.Int64("gen_ai.usage.output_tokens", resp.Usage.CompletionTokens),
-        attribute.Int64("response_time_ms", duration.Milliseconds()),
-        attribute.String("langfuse.observation.input", req.SystemPrompt+"\n\n"+req.UserPrompt),
-        attribute.String("langfuse.observation.output", content),
-        attribute.String("langfuse.observation.output_format", "json_schema"),
-        attribute.String("langfuse.observation.model.name", model),
-    )
-
-	span.AddEvent("gen_ai.choice", trace.WithAttributes(
-		attribute.String("gen_ai.system", "openai"),
-		attribute.String("content", content),
-	))
-
-	if s.debug != nil {
-		s.debug.Printf("LLM JSON Schema Completion response length: %d, tokens: %d/%d, duration: %v", 
-			len(content), resp.Usage.PromptTokens, resp.Usage.CompletionTokens, duration)
+	if call.ResponseFormat == "json_object" {
+		if err := validateJSONSchema(result.Content, call.Schema); err != nil {
+			err = fmt.Errorf("%s: %w", call.SchemaName, err)
+			return result, &providerError{errType: "schema_validation_error", cause: err, err: err}
+		}
 	}
+	return result, nil
+}
 
-	return content, nil
+// RequestAttributes returns span attributes for the tuning knobs actually set on a request.
+// Unset values are omitted so dashboards don't confuse "default" with an explicit value.
+func RequestAttributes(reasoningEffort string, temperature *float64) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if reasoningEffort != "" {
+		attrs = append(attrs, attribute.String("gen_ai.request.reasoning_effort", reasoningEffort))
+	}
+	if temperature != nil {
+		attrs = append(attrs, attribute.Float64("gen_ai.request.temperature", *temperature))
+	}
+	return attrs
 }
 
 func WithOperationType(ctx context.Context, opType string) context.Context {
@@ -500,18 +289,18 @@ func WithOperationType(ctx context.Context, opType string) context.Context {
 }
 
 func WithGameContext(ctx context.Context, gameCtx map[string]interface{}) context.Context {
-    // Merge with any existing game context instead of overwriting
-    if existing, ok := ctx.Value(gameContextKey).(map[string]interface{}); ok && existing != nil {
-        merged := make(map[string]interface{}, len(existing)+len(gameCtx))
-        for k, v := range existing {
-            merged[k] = v
-        }
-        for k, v := range gameCtx {
-            merged[k] = v
-        }
-        return context.WithValue(ctx, gameContextKey, merged)
-    }
-    return context.WithValue(ctx, gameContextKey, gameCtx)
+	// Merge with any existing game context instead of overwriting
+	if existing, ok := ctx.Value(gameContextKey).(map[string]interface{}); ok && existing != nil {
+		merged := make(map[string]interface{}, len(existing)+len(gameCtx))
+		for k, v := range existing {
+			merged[k] = v
+		}
+		for k, v := range gameCtx {
+			merged[k] = v
+		}
+		return context.WithValue(ctx, gameContextKey, merged)
+	}
+	return context.WithValue(ctx, gameContextKey, gameCtx)
 }
 
 func WithSessionID(ctx context.Context, sessionID string) context.Context {
@@ -533,57 +322,36 @@ func getGameContext(ctx context.Context) map[string]interface{} {
 }
 
 func getSessionID(ctx context.Context) string {
-    return observability.GetSessionIDFromContext(ctx)
+	return observability.GetSessionIDFromContext(ctx)
 }
 
 // CopyGameContextToSpan attaches game context and session id attributes to an existing span.
 func CopyGameContextToSpan(ctx context.Context, span trace.Span) {
-    if span == nil {
-        return
-    }
-    if sid := getSessionID(ctx); sid != "" {
-        span.SetAttributes(
-            attribute.String("langfuse.session.id", sid),
-            attribute.String("session.id", sid),
-        )
-    }
-    if gameCtx := getGameContext(ctx); gameCtx != nil {
-        for k, v := range gameCtx {
-            switch val := v.(type) {
-            case string:
-                span.SetAttributes(attribute.String("game."+k, val))
-            case int:
-                span.SetAttributes(attribute.Int("game."+k, val))
-            case []string:
-                span.SetAttributes(attribute.StringSlice("game."+k, val))
-            }
-        }
-    }
+	if span == nil {
+		return
+	}
+	span.SetAttributes(gameContextAttributes(ctx)...)
 }
 
-func (s *Service) CompleteStream(ctx context.Context, req StreamCompletionRequest) (*ssestream.Stream[openai.ChatCompletionChunk], error) {
-    model := s.model
-    if strings.TrimSpace(req.Model) != "" {
-        model = req.Model
-    }
-    openaiReq := openai.ChatCompletionNewParams{
-        Model: shared.ChatModel(model),
-        Messages: []openai.ChatCompletionMessageParamUnion{
-            openai.SystemMessage(req.SystemPrompt),
-            openai.UserMessage(req.UserPrompt),
-        },
-        MaxCompletionTokens: openai.Int(int64(req.MaxTokens)),
-    }
-    
-    if req.ReasoningEffort != "" {
-        openaiReq.ReasoningEffort = shared.ReasoningEffort(req.ReasoningEffort)
-    }
-
-	if s.debug != nil {
-		s.debug.Printf("LLM Stream Completion - MaxTokens: %d, SystemPrompt length: %d", req.MaxTokens, len(req.SystemPrompt))
-		s.debug.Printf("LLM Stream Request - Model: %s", model)
+// gameContextAttributes are the session id and game context carried by ctx, as span
+// attributes.
+func gameContextAttributes(ctx context.Context) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if sid := getSessionID(ctx); sid != "" {
+		attrs = append(attrs,
+			attribute.String("langfuse.session.id", sid),
+			attribute.String("session.id", sid),
+		)
 	}
-
-	stream := s.client.Chat.Completions.NewStreaming(ctx, openaiReq)
-	return stream, nil
+	for k, v := range getGameContext(ctx) {
+		switch val := v.(type) {
+		case string:
+			attrs = append(attrs, attribute.String("game."+k, val))
+		case int:
+			attrs = append(attrs, attribute.Int("game."+k, val))
+		case []string:
+			attrs = append(attrs, attribute.StringSlice("game."+k, val))
+		}
+	}
+	return attrs
 }
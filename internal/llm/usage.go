@@ -0,0 +1,171 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UsageTotals is the token usage of a group of completions.
+type UsageTotals struct {
+	Calls        int   `json:"calls"`
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+func (t *UsageTotals) add(other UsageTotals) {
+	t.Calls += other.Calls
+	t.InputTokens += other.InputTokens
+	t.OutputTokens += other.OutputTokens
+}
+
+// UsageRow is the usage of one operation type on one model.
+type UsageRow struct {
+	Operation string `json:"operation"`
+	Model     string `json:"model"`
+	UsageTotals
+	// CostUSD is the estimated cost; PriceKnown is false when the model has no price.
+	CostUSD    float64 `json:"cost_usd"`
+	PriceKnown bool    `json:"price_known"`
+}
+
+type usageKey struct {
+	operation string
+	model     string
+}
+
+// UsageTracker adds up the tokens every completion of a session uses, by operation
+// type and model. The service records into it; the UI reads it for /usage and the
+// session summary.
+type UsageTracker struct {
+	mu     sync.Mutex
+	usage  map[usageKey]UsageTotals
+	prices Prices
+}
+
+// NewUsageTracker creates an empty tracker priced with DefaultPrices.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{usage: make(map[usageKey]UsageTotals), prices: DefaultPrices()}
+}
+
+// Record adds one completion's tokens.
+func (u *UsageTracker) Record(operation, model string, inputTokens, outputTokens int64) {
+	if u == nil {
+		return
+	}
+	if operation == "" {
+		operation = "unknown"
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	totals := u.usage[usageKey{operation, model}]
+	totals.add(UsageTotals{Calls: 1, InputTokens: inputTokens, OutputTokens: outputTokens})
+	u.usage[usageKey{operation, model}] = totals
+}
+
+// SetPrices replaces the prices costs are estimated with.
+func (u *UsageTracker) SetPrices(prices Prices) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.prices = prices
+}
+
+// Rows returns the usage so far, most expensive first, then by operation and model.
+func (u *UsageTracker) Rows() []UsageRow {
+	if u == nil {
+		return nil
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	rows := make([]UsageRow, 0, len(u.usage))
+	for key, totals := range u.usage {
+		cost, known := u.prices.Cost(key.model, totals.InputTokens, totals.OutputTokens)
+		rows = append(rows, UsageRow{Operation: key.operation, Model: key.model, UsageTotals: totals, CostUSD: cost, PriceKnown: known})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].CostUSD != rows[j].CostUSD {
+			return rows[i].CostUSD > rows[j].CostUSD
+		}
+		if rows[i].Operation != rows[j].Operation {
+			return rows[i].Operation < rows[j].Operation
+		}
+		return rows[i].Model < rows[j].Model
+	})
+	return rows
+}
+
+// Total sums rows. The cost covers only models with a known price.
+func Total(rows []UsageRow) (UsageTotals, float64) {
+	var totals UsageTotals
+	var cost float64
+	for _, row := range rows {
+		totals.add(row.UsageTotals)
+		cost += row.CostUSD
+	}
+	return totals, cost
+}
+
+// ModelPrice is what a model costs, in US dollars per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64 `json:"input"`
+	OutputPerMillion float64 `json:"output"`
+}
+
+// Prices maps model names to prices. A key also covers dated and suffixed versions of
+// the model ("gpt-5" prices "gpt-5-2025-08-07"); the longest matching key wins.
+type Prices map[string]ModelPrice
+
+// DefaultPrices are the list prices of the models the game uses out of the box.
+func DefaultPrices() Prices {
+	return Prices{
+		"gpt-5":                  {InputPerMillion: 1.25, OutputPerMillion: 10},
+		"gpt-5-mini":             {InputPerMillion: 0.25, OutputPerMillion: 2},
+		"gpt-5-nano":             {InputPerMillion: 0.05, OutputPerMillion: 0.4},
+		"text-embedding-3-small": {InputPerMillion: 0.02},
+	}
+}
+
+// LoadPricesFromEnv returns DefaultPrices overlaid with the JSON price file named by
+// LLM_PRICE_CONFIG, e.g. {"gpt-5": {"input": 1.25, "output": 10}}.
+func LoadPricesFromEnv() (Prices, error) {
+	prices := DefaultPrices()
+	path := strings.TrimSpace(os.Getenv("LLM_PRICE_CONFIG"))
+	if path == "" {
+		return prices, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return prices, fmt.Errorf("read price config: %w", err)
+	}
+	var configured Prices
+	if err := json.Unmarshal(data, &configured); err != nil {
+		return prices, fmt.Errorf("parse price config %s: %w", path, err)
+	}
+	for model, price := range configured {
+		prices[model] = price
+	}
+	return prices, nil
+}
+
+// Cost estimates what the tokens cost on model, and reports whether the model has a price.
+func (p Prices) Cost(model string, inputTokens, outputTokens int64) (float64, bool) {
+	best := ""
+	for key := range p {
+		if (model == key || strings.HasPrefix(model, key+"-")) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	price := p[best]
+	return (float64(inputTokens)*price.InputPerMillion + float64(outputTokens)*price.OutputPerMillion) / 1e6, true
+}
+
+// Usage returns the tracker every completion made through the service is recorded in.
+func (s *Service) Usage() *UsageTracker {
+	return s.usage
+}
@@ -4,9 +4,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
+
+	"textadventure/internal/redact"
 )
 
 type CompletionLog struct {
@@ -24,18 +29,37 @@ type CompletionMetadata struct {
 	MaxTokens       int           `json:"max_tokens"`
 	ResponseTime    time.Duration `json:"response_time_ms"`
 	StreamingUsed   bool          `json:"streaming_used"`
+	ReasoningEffort string        `json:"reasoning_effort,omitempty"`
+	Temperature     *float64      `json:"temperature,omitempty"`
+	OriginalInput   string        `json:"original_input,omitempty"` // player's untranslated input; user_input holds the English form
+	InputLanguage   string        `json:"input_language,omitempty"`
+	Experiment      string        `json:"experiment,omitempty"`         // the narrator experiment, if the completion ran under one
+	Variant         string        `json:"experiment_variant,omitempty"` // its variant, "A" or "B"
+	NarrationStyle  string        `json:"narration_style,omitempty"`    // the narration style preset or file, for narration completions
 	Error           *string       `json:"error,omitempty"`
 }
 
+// worldSnapshotEvery is how many completions are logged with a world diff between two
+// full world snapshots, bounding how far a reconstruction has to walk back.
+const worldSnapshotEvery = 25
+
 type CompletionLogger struct {
 	db *sql.DB
+
+	mu             sync.Mutex
+	fullWorldState bool
+	lastID         int64  // the last completion logged by this process
+	lastWorld      []byte // its world state, before redaction
+	sinceSnapshot  int    // completions logged since the last full snapshot
 }
 
-func NewCompletionLogger() (*CompletionLogger, error) {
-	db, err := sql.Open("sqlite3", "./completions.db")
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+// NewCompletionLogger opens (creating if needed) the completions database at path.
+func NewCompletionLogger(path string) (*CompletionLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
+	// Every write goes through the redactor, so no insert has to remember to redact
+	db := sql.OpenDB(redact.NewConnector(&sqlite3.SQLiteDriver{}, path))
 
 	logger := &CompletionLogger{db: db}
 	if err := logger.createTables(); err != nil {
@@ -60,8 +84,60 @@ func (cl *CompletionLogger) createTables() error {
 	CREATE INDEX IF NOT EXISTS idx_completions_timestamp ON completions(timestamp);
 	`
 
-	_, err := cl.db.Exec(schema)
-	return err
+	if _, err := cl.db.Exec(schema); err != nil {
+		return err
+	}
+	if _, err := cl.db.Exec(sessionSummariesSchema); err != nil {
+		return err
+	}
+	if _, err := cl.db.Exec(narrationRatingsSchema); err != nil {
+		return err
+	}
+	return cl.migrateWorldStateFormat()
+}
+
+// migrateWorldStateFormat adds the columns that let a completion store a world diff
+// instead of the full world. Rows logged before them are full snapshots.
+func (cl *CompletionLogger) migrateWorldStateFormat() error {
+	columns := map[string]bool{}
+	rows, err := cl.db.Query(`PRAGMA table_info(completions)`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !columns["world_state_format"] {
+		if _, err := cl.db.Exec(`ALTER TABLE completions ADD COLUMN world_state_format TEXT NOT NULL DEFAULT 'full'`); err != nil {
+			return err
+		}
+	}
+	if !columns["base_id"] {
+		if _, err := cl.db.Exec(`ALTER TABLE completions ADD COLUMN base_id INTEGER`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFullWorldState logs every completion with the full world state, as before world
+// diffs, instead of a diff against the previous completion.
+func (cl *CompletionLogger) SetFullWorldState(full bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.fullWorldState = full
 }
 
 func (cl *CompletionLogger) LogCompletion(
@@ -81,12 +157,36 @@ func (cl *CompletionLogger) LogCompletion(
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	_, err = cl.db.Exec(`
-		INSERT INTO completions (world_state, user_input, system_prompt, response, metadata)
-		VALUES (?, ?, ?, ?, ?)
-	`, string(worldStateJson), userInput, systemPrompt, response, string(metadataJson))
+	// Rows are logged one at a time so each diff is against the row before it
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
 
-	return err
+	format, stored, baseID := worldStateFull, worldStateJson, sql.NullInt64{}
+	if !cl.fullWorldState && cl.lastWorld != nil && cl.sinceSnapshot < worldSnapshotEvery {
+		if diff, err := DiffWorldJSON(cl.lastWorld, worldStateJson); err == nil {
+			format, stored, baseID = worldStateDiff, diff, sql.NullInt64{Int64: cl.lastID, Valid: true}
+		}
+	}
+
+	result, err := cl.db.Exec(`
+		INSERT INTO completions (world_state, user_input, system_prompt, response, metadata, world_state_format, base_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, string(stored), userInput, systemPrompt, response, string(metadataJson), format, baseID)
+	if err != nil {
+		return err
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		cl.lastID, cl.lastWorld = id, worldStateJson
+		if format == worldStateFull {
+			cl.sinceSnapshot = 0
+		} else {
+			cl.sinceSnapshot++
+		}
+	} else {
+		cl.lastWorld = nil
+	}
+	return nil
 }
 
 func (cl *CompletionLogger) Close() error {
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+)
+
+// Probe checks the completions database can be written and read back. The write happens
+// in a transaction that is rolled back, so nothing is left behind; a database another
+// process holds locked fails here rather than mid-game.
+func (cl *CompletionLogger) Probe(ctx context.Context) error {
+	tx, err := cl.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS doctor_probe (value TEXT NOT NULL)`); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO doctor_probe (value) VALUES (?)`, "ping"); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+	var value string
+	if err := tx.QueryRowContext(ctx, `SELECT value FROM doctor_probe LIMIT 1`).Scan(&value); err != nil {
+		return fmt.Errorf("failed to read back: %w", err)
+	}
+	if value != "ping" {
+		return fmt.Errorf("read back %q, wrote %q", value, "ping")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NarrationRating is the player's thumbs up or down on a turn's narration, with the
+// experiment variant it was narrated under, if any.
+type NarrationRating struct {
+	SessionID  string
+	TurnIndex  int
+	Experiment string
+	Variant    string
+	Rating     int // 1 for thumbs up, -1 for thumbs down
+	RatedAt    time.Time
+}
+
+const narrationRatingsSchema = `
+CREATE TABLE IF NOT EXISTS narration_ratings (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	turn_index INTEGER NOT NULL,
+	experiment TEXT NOT NULL,
+	variant TEXT NOT NULL,
+	rating INTEGER NOT NULL,
+	rated_at DATETIME NOT NULL,
+	UNIQUE (session_id, turn_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_narration_ratings_experiment ON narration_ratings(experiment, variant);
+`
+
+// LogRating records a rating. Rating a turn again replaces its earlier rating.
+func (cl *CompletionLogger) LogRating(rating NarrationRating) error {
+	_, err := cl.db.Exec(`
+		INSERT INTO narration_ratings (session_id, turn_index, experiment, variant, rating, rated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (session_id, turn_index) DO UPDATE SET rating = excluded.rating, rated_at = excluded.rated_at
+	`, rating.SessionID, rating.TurnIndex, rating.Experiment, rating.Variant, rating.Rating, rating.RatedAt)
+	return err
+}
+
+// Ratings returns the ratings given under an experiment, or under every experiment when
+// experiment is empty. Ratings of narration outside an experiment are left out.
+func (cl *CompletionLogger) Ratings(experiment string) ([]NarrationRating, error) {
+	rows, err := cl.db.Query(`
+		SELECT session_id, turn_index, experiment, variant, rating, rated_at
+		FROM narration_ratings
+		WHERE experiment != '' AND (? = '' OR experiment = ?)
+		ORDER BY rated_at
+	`, experiment, experiment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var result []NarrationRating
+	for rows.Next() {
+		var rating NarrationRating
+		if err := rows.Scan(&rating.SessionID, &rating.TurnIndex, &rating.Experiment, &rating.Variant, &rating.Rating, &rating.RatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read rating: %w", err)
+		}
+		result = append(result, rating)
+	}
+	return result, rows.Err()
+}
+
+// ExperimentNarration is a narration completion logged under an experiment variant.
+type ExperimentNarration struct {
+	Experiment   string
+	Variant      string
+	ResponseTime time.Duration
+}
+
+// ExperimentNarrations returns the narration completions logged under an experiment, or
+// under every experiment when experiment is empty.
+func (cl *CompletionLogger) ExperimentNarrations(experiment string) ([]ExperimentNarration, error) {
+	rows, err := cl.db.Query(`SELECT metadata FROM completions WHERE metadata LIKE '%"experiment_variant"%'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query completions: %w", err)
+	}
+	defer rows.Close()
+
+	var result []ExperimentNarration
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("failed to read completion: %w", err)
+		}
+		var metadata CompletionMetadata
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil || metadata.Variant == "" {
+			continue
+		}
+		if experiment != "" && metadata.Experiment != experiment {
+			continue
+		}
+		result = append(result, ExperimentNarration{Experiment: metadata.Experiment, Variant: metadata.Variant, ResponseTime: metadata.ResponseTime})
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testEmail  = "player@example.com"
+	testAPIKey = "sk-abcdefghijklmnopqrstuvwx"
+)
+
+// tableText returns every text value stored in table.
+func tableText(t *testing.T, db *sql.DB, table string) string {
+	t.Helper()
+	rows, err := db.Query("SELECT * FROM " + table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			t.Fatal(err)
+		}
+		for _, value := range values {
+			b.WriteString(value.String + "\n")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// Every insert the completion logger makes is redacted by the database layer, not by
+// its call site, so none can skip it.
+func TestCompletionLoggerRedactsEveryInsert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "completions.db")
+	cl, err := NewCompletionLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	secret := "mail " + testEmail + " key " + testAPIKey
+	world := map[string]string{"note": secret}
+	if err := cl.LogCompletion(world, secret, secret, secret, CompletionMetadata{Model: "mock", OriginalInput: secret}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.LogCompletion(map[string]string{"note": secret, "more": testEmail}, "look", "system", "ok", CompletionMetadata{}); err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now()
+	if err := cl.LogRating(NarrationRating{SessionID: "s1", TurnIndex: 1, Experiment: secret, Variant: "A", Rating: 1, RatedAt: started}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.LogSessionSummary(SessionSummary{SessionID: "s1", EndedAt: started, Breakdown: []string{secret}}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+	for _, table := range []string{"completions", "narration_ratings", "session_summaries"} {
+		text := tableText(t, raw, table)
+		if text == "" {
+			t.Errorf("%s is empty", table)
+		}
+		for _, original := range []string{testEmail, testAPIKey} {
+			if strings.Contains(text, original) {
+				t.Errorf("%s stores %q", table, original)
+			}
+		}
+		if !strings.Contains(text, "[REDACTED:") {
+			t.Errorf("%s has no placeholder:\n%s", table, text)
+		}
+	}
+}
+
+// Rows are read back through the same database, so redacted values still match up.
+func TestRatingsReadBackAfterRedaction(t *testing.T) {
+	cl, err := NewCompletionLogger(filepath.Join(t.TempDir(), "completions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	rated := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := cl.LogRating(NarrationRating{SessionID: "s1", TurnIndex: 2, Experiment: "terse", Variant: "B", Rating: -1, RatedAt: rated}); err != nil {
+		t.Fatal(err)
+	}
+	ratings, err := cl.Ratings("terse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ratings) != 1 || ratings[0].Variant != "B" || ratings[0].Rating != -1 || !ratings[0].RatedAt.Equal(rated) {
+		t.Errorf("ratings = %+v", ratings)
+	}
+}
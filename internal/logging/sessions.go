@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SessionSummary is the LLM usage of a whole play session. Breakdown holds the
+// per-operation, per-model rows as reported by the usage tracker.
+type SessionSummary struct {
+	SessionID        string
+	EndedAt          time.Time
+	Calls            int
+	InputTokens      int64
+	OutputTokens     int64
+	EstimatedCostUSD float64
+	Breakdown        interface{}
+}
+
+const sessionSummariesSchema = `
+CREATE TABLE IF NOT EXISTS session_summaries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	ended_at DATETIME NOT NULL,
+	calls INTEGER NOT NULL,
+	input_tokens INTEGER NOT NULL,
+	output_tokens INTEGER NOT NULL,
+	estimated_cost_usd REAL NOT NULL,
+	breakdown TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_summaries_session ON session_summaries(session_id);
+`
+
+// LogSessionSummary records a session's usage totals when it ends.
+func (cl *CompletionLogger) LogSessionSummary(summary SessionSummary) error {
+	breakdown, err := json.Marshal(summary.Breakdown)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage breakdown: %w", err)
+	}
+	_, err = cl.db.Exec(`
+		INSERT INTO session_summaries (session_id, ended_at, calls, input_tokens, output_tokens, estimated_cost_usd, breakdown)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, summary.SessionID, summary.EndedAt, summary.Calls, summary.InputTokens, summary.OutputTokens, summary.EstimatedCostUSD, string(breakdown))
+	return err
+}
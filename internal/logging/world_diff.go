@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// World states are logged as JSON merge patches (RFC 7386) against the previous row:
+// objects are diffed key by key, a removed key is set to null, and any other changed
+// value, arrays included, is replaced whole. A key whose value became null is removed
+// on reconstruction, which decodes to the same world.
+
+// Formats of the completions table's world_state column.
+const (
+	worldStateFull = "full"
+	worldStateDiff = "diff"
+)
+
+// DiffWorldJSON returns the merge patch that turns the JSON object prev into next.
+func DiffWorldJSON(prev, next []byte) ([]byte, error) {
+	prevObject, err := decodeObject(prev)
+	if err != nil {
+		return nil, err
+	}
+	nextObject, err := decodeObject(next)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(diffObjects(prevObject, nextObject))
+}
+
+// ApplyWorldDiff applies a merge patch from DiffWorldJSON to the JSON object base.
+func ApplyWorldDiff(base, diff []byte) ([]byte, error) {
+	baseObject, err := decodeObject(base)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := decodeObject(diff)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(applyPatch(baseObject, patch))
+}
+
+func decodeObject(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("failed to decode world state: %w", err)
+	}
+	if object == nil {
+		object = map[string]interface{}{}
+	}
+	return object, nil
+}
+
+func diffObjects(prev, next map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key := range prev {
+		if _, kept := next[key]; !kept {
+			patch[key] = nil
+		}
+	}
+	for key, nextValue := range next {
+		prevValue, existed := prev[key]
+		if !existed {
+			patch[key] = nextValue
+			continue
+		}
+		prevObject, prevIsObject := prevValue.(map[string]interface{})
+		nextObject, nextIsObject := nextValue.(map[string]interface{})
+		if prevIsObject && nextIsObject {
+			if nested := diffObjects(prevObject, nextObject); len(nested) > 0 {
+				patch[key] = nested
+			}
+			continue
+		}
+		if !reflect.DeepEqual(prevValue, nextValue) {
+			patch[key] = nextValue
+		}
+	}
+	return patch
+}
+
+func applyPatch(base, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+		if nested, isObject := value.(map[string]interface{}); isObject {
+			target, _ := base[key].(map[string]interface{})
+			if target == nil {
+				target = map[string]interface{}{}
+			}
+			base[key] = applyPatch(target, nested)
+			continue
+		}
+		base[key] = value
+	}
+	return base
+}
+
+// WorldStateAt reconstructs the full world state JSON completion id was logged with,
+// walking back through the diffs to the nearest full snapshot.
+func (cl *CompletionLogger) WorldStateAt(id int64) (string, error) {
+	var diffs []string
+	for {
+		var worldState, format string
+		var baseID *int64
+		err := cl.db.QueryRow(`SELECT world_state, world_state_format, base_id FROM completions WHERE id = ?`, id).Scan(&worldState, &format, &baseID)
+		if err != nil {
+			return "", fmt.Errorf("failed to read completion %d: %w", id, err)
+		}
+		if format != worldStateDiff {
+			state := []byte(worldState)
+			for i := len(diffs) - 1; i >= 0; i-- {
+				if state, err = ApplyWorldDiff(state, []byte(diffs[i])); err != nil {
+					return "", err
+				}
+			}
+			return string(state), nil
+		}
+		if baseID == nil {
+			return "", fmt.Errorf("completion %d is a world diff without a base", id)
+		}
+		diffs = append(diffs, worldState)
+		id = *baseID
+	}
+}
@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SetLocationAmbience sets a location's ongoing background ambience, or clears it when
+// ambience is empty.
+func (w *WorldStateClient) SetLocationAmbience(ctx context.Context, locationID, ambience string) (string, error) {
+	if strings.TrimSpace(locationID) == "" {
+		return "", fmt.Errorf("set_location_ambience requires 'location_id'")
+	}
+	return errorResponse(w.CallToolValidated(ctx, "set_location_ambience", map[string]interface{}{
+		"location_id": locationID,
+		"ambience":    ambience,
+	}))
+}
+
+// MarkAmbienceHeard records that an NPC has perceived the given ambience at a location.
+func (w *WorldStateClient) MarkAmbienceHeard(ctx context.Context, npcID, locationID, ambience string) (string, error) {
+	return errorResponse(w.CallToolValidated(ctx, "mark_ambience_heard", map[string]interface{}{
+		"npc_id":      npcID,
+		"location_id": locationID,
+		"ambience":    ambience,
+	}))
+}
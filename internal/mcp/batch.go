@@ -0,0 +1,79 @@
+package mcp
+
+import "context"
+
+// ToolCall is one call in a batch sent with CallTools.
+type ToolCall struct {
+	Tool string
+	Args map[string]interface{}
+}
+
+// ToolResult is how one call in a batch went.
+type ToolResult struct {
+	Call     ToolCall
+	Response string
+	Err      error
+}
+
+// CallTools makes the calls in order, each checked against its tool's schema, and
+// returns a result for every one. A failed call doesn't stop the ones after it.
+func (w *WorldStateClient) CallTools(ctx context.Context, calls []ToolCall) []ToolResult {
+	results := make([]ToolResult, 0, len(calls))
+	for _, call := range calls {
+		response, err := errorResponse(w.CallToolValidated(ctx, call.Tool, call.Args))
+		results = append(results, ToolResult{Call: call, Response: response, Err: err})
+	}
+	return results
+}
+
+// NPCMemoryCall is UpdateNPCMemory as a call for CallTools.
+func (w *WorldStateClient) NPCMemoryCall(npcID, thought, action string, turn int) ToolCall {
+	args := map[string]interface{}{"npc_id": npcID}
+	if thought != "" {
+		args["thought"] = thought
+	}
+	if action != "" {
+		args["action"] = action
+	}
+	if turn > 0 && w.ToolAcceptsArg("update_npc_memory", "turn") {
+		args["turn"] = turn
+	}
+	return ToolCall{Tool: "update_npc_memory", Args: args}
+}
+
+// LocationFactsCall is AddLocationFacts as a call for CallTools.
+func LocationFactsCall(locationID string, facts []string) ToolCall {
+	return ToolCall{Tool: "add_location_facts", Args: map[string]interface{}{
+		"location_id": locationID,
+		"new_facts":   facts,
+	}}
+}
+
+// ItemFactsCall is AddItemFacts as a call for CallTools.
+func ItemFactsCall(itemID string, facts []string) ToolCall {
+	return ToolCall{Tool: "add_item_facts", Args: map[string]interface{}{
+		"item_id":   itemID,
+		"new_facts": facts,
+	}}
+}
+
+// NPCFactsCall is AddNPCFacts as a call for CallTools.
+func NPCFactsCall(npcID string, facts []string) ToolCall {
+	return ToolCall{Tool: "add_npc_facts", Args: map[string]interface{}{
+		"npc_id":    npcID,
+		"new_facts": facts,
+	}}
+}
+
+// CreateItemCall is CreateItem as a call for CallTools.
+func CreateItemCall(itemID, name, location string, initialFacts []string) ToolCall {
+	args := map[string]interface{}{
+		"item_id":  itemID,
+		"name":     name,
+		"location": location,
+	}
+	if len(initialFacts) > 0 {
+		args["initial_facts"] = initialFacts
+	}
+	return ToolCall{Tool: "create_item", Args: args}
+}
@@ -0,0 +1,31 @@
+package mcp
+
+import "strings"
+
+// Briefing is the scenario's out-of-fiction scene setting, shown to the player before
+// the intro narration. It is player-facing meta text: MCPToGameWorldState leaves it
+// out, so it can never reach a prompt.
+type Briefing struct {
+	Title           string   `json:"title"`
+	Premise         string   `json:"premise"`
+	ContentWarnings []string `json:"content_warnings,omitempty"`
+	SuggestedVerbs  []string `json:"suggested_verbs,omitempty"`
+}
+
+// Text renders the briefing as plain text, one section per paragraph.
+func (b Briefing) Text() string {
+	var sections []string
+	if b.Title != "" {
+		sections = append(sections, b.Title)
+	}
+	if b.Premise != "" {
+		sections = append(sections, b.Premise)
+	}
+	if len(b.ContentWarnings) > 0 {
+		sections = append(sections, "Content warnings: "+strings.Join(b.ContentWarnings, ", "))
+	}
+	if len(b.SuggestedVerbs) > 0 {
+		sections = append(sections, "Try: "+strings.Join(b.SuggestedVerbs, ", "))
+	}
+	return strings.Join(sections, "\n\n")
+}
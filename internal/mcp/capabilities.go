@@ -0,0 +1,43 @@
+package mcp
+
+import "context"
+
+// FactTools are the server tools used to persist facts extracted from narration.
+// Older servers may not implement them; facts then only accumulate locally.
+var FactTools = []string{"add_location_facts", "create_item", "add_item_facts", "add_npc_facts"}
+
+// ProbeTools records which tools the server provides so optional calls can be skipped
+// when they are missing. It returns the missing names from want.
+func (w *WorldStateClient) ProbeTools(ctx context.Context, want ...string) ([]string, error) {
+	specs, err := w.ListToolSpecs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		available[spec.Name] = true
+	}
+	w.mu.Lock()
+	w.available = available
+	w.mu.Unlock()
+	return w.MissingTools(want...), nil
+}
+
+// HasTool reports whether the server provides a tool. Before a successful probe every
+// tool is assumed present, so calls fail loudly rather than being skipped.
+func (w *WorldStateClient) HasTool(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.available == nil || w.available[name]
+}
+
+// MissingTools returns the names the server is known not to provide.
+func (w *WorldStateClient) MissingTools(names ...string) []string {
+	var missing []string
+	for _, name := range names {
+		if !w.HasTool(name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
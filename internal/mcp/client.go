@@ -3,18 +3,113 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"textadventure/internal/worldstate"
 )
 
 type WorldStateClient struct {
-	client  *mcp.Client
-	session *mcp.ClientSession
-	debug   bool
+	client   *mcp.Client
+	session  *mcp.ClientSession
+	debug    bool
+	wrappers []func(ToolCaller) ToolCaller
+	mu       sync.Mutex
+	// available is the set of server tools seen by ProbeTools; nil until probed.
+	available map[string]bool
+	// schemas holds the input schema of each tool from the last ListToolSpecs.
+	schemas map[string]*toolSchema
+	// unavailable is set when the connection was lost and could not be restored.
+	unavailable bool
+	reconnectMu sync.Mutex
+	// reconnects counts server restarts attempted after a lost connection or by Reconnect.
+	reconnects int
+	// lastWorld is the world last read from or restored to the server, replayed to a
+	// restarted server.
+	lastWorld *WorldState
+	// callTimeout bounds each call to the server; see WithCallTimeout.
+	callTimeout time.Duration
+	// inProcess serves the world-state tools from Go when WORLD_STATE_SERVER=go;
+	// nil means the Python server is started as a subprocess.
+	inProcess *mcp.Server
+	// serverDown, when set, fails every connection attempt with its error; the fake
+	// uses it to play a server that won't come back.
+	serverDown func() error
+}
+
+// ToolCaller is the part of the MCP session used to invoke world-state tools.
+type ToolCaller interface {
+	CallTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error)
+}
+
+// ToolCallerFunc adapts a function to ToolCaller.
+type ToolCallerFunc func(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error)
+
+func (f ToolCallerFunc) CallTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	return f(ctx, params)
+}
+
+// WrapToolCaller installs a decorator around every tool call made by the client.
+// Decorators are applied in the order they were added.
+func (w *WorldStateClient) WrapToolCaller(wrap func(ToolCaller) ToolCaller) {
+	w.wrappers = append(w.wrappers, wrap)
+}
+
+// callTool invokes a tool through the installed decorators. A lost connection is
+// retried after reconnecting; once reconnecting fails, calls fail with ErrWorldUnavailable.
+func (w *WorldStateClient) callTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	if w.Unavailable() {
+		return nil, ErrWorldUnavailable
+	}
+	session := w.currentSession()
+	result, err := w.callWithTimeout(ctx, session, params)
+	if err == nil || !isConnectionLost(err) {
+		return result, err
+	}
+	if err := w.recoverConnection(ctx, session); err != nil {
+		return nil, err
+	}
+	return w.callWithTimeout(ctx, w.currentSession(), params)
+}
+
+// callWithTimeout makes one call bounded by the client's call timeout. Running out of
+// time is reported as ErrWorldTimeout; the caller's own deadline or cancellation is not.
+func (w *WorldStateClient) callWithTimeout(ctx context.Context, session *mcp.ClientSession, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	callCtx, cancel := w.withCallTimeout(ctx)
+	defer cancel()
+	result, err := w.caller(session).CallTool(callCtx, params)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: %s got no answer within %s", ErrWorldTimeout, params.Name, w.callTimeout)
+	}
+	return result, err
+}
+
+func (w *WorldStateClient) withCallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if w.callTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, w.callTimeout)
+}
+
+// CallTimeout is how long a single call to the server may take; zero means unbounded.
+func (w *WorldStateClient) CallTimeout() time.Duration {
+	return w.callTimeout
+}
+
+func (w *WorldStateClient) caller(session *mcp.ClientSession) ToolCaller {
+	var caller ToolCaller = session
+	for _, wrap := range w.wrappers {
+		caller = wrap(caller)
+	}
+	return caller
 }
 
 type WorldState struct {
@@ -22,12 +117,31 @@ type WorldState struct {
 	Locations map[string]Location  `json:"locations"`
 	Items     map[string]Item      `json:"items"`
 	NPCs      map[string]NPC       `json:"npcs"`
+	Briefing  *Briefing            `json:"briefing,omitempty"`
+	ScheduledEvents []ScheduledEvent `json:"scheduled_events,omitempty"`
+	PassthroughTools map[string]PassthroughTool `json:"passthrough_tools,omitempty"`
+	Quests    []Quest              `json:"quests,omitempty"`
 }
 
 type Player struct {
-	Location  string   `json:"location"`
-	Inventory []string `json:"inventory"`
-	MetNPCs   []string `json:"met_npcs"`
+	Location   string      `json:"location"`
+	Inventory  []string    `json:"inventory"`
+	MetNPCs    []string    `json:"met_npcs"`
+	VisitedLocations []string `json:"visited_locations,omitempty"`
+	Conditions []Condition `json:"conditions"`
+	Goals      []string    `json:"goals,omitempty"`
+}
+
+type Quest struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Objective string `json:"objective,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+type Condition struct {
+	Name  string `json:"name"`
+	Turns int    `json:"turns"`
 }
 
 type Location struct {
@@ -35,6 +149,10 @@ type Location struct {
 	Facts       []string          `json:"facts"`
 	Exits       map[string]string `json:"exits"`
 	DoorStates  map[string]Door   `json:"door_states"`
+	Items       []string          `json:"items"`
+	Outdoors    bool              `json:"outdoors"`
+	NarratorNotes []string        `json:"narrator_notes,omitempty"`
+	Ambience      string          `json:"ambience,omitempty"`
 }
 
 type Door struct {
@@ -43,60 +161,135 @@ type Door struct {
 }
 
 type Item struct {
-	Name      string   `json:"name"`
-	Facts     []string `json:"facts"`
-	Location  string   `json:"location"`
-	CanUnlock []string `json:"can_unlock"`
+	Name        string   `json:"name"`
+	Facts       []string `json:"facts"`
+	Location    string   `json:"location"`
+	CanUnlock   []string `json:"can_unlock"`
+	IsContainer bool     `json:"is_container,omitempty"`
+	Contains    []string `json:"contains,omitempty"`
+	Locked      bool     `json:"locked,omitempty"`
+	Capacity    int      `json:"capacity,omitempty"`
 }
 
 type NPC struct {
 	Name          string   `json:"name"`
+	Description   string   `json:"description"`
 	Location      string   `json:"location"`
 	DebugColor    string   `json:"debug_color"`
 	Facts         []string `json:"facts"`
 	Inventory     []string `json:"inventory"`
-	RecentThoughts []string `json:"recent_thoughts"`
-	RecentActions []string `json:"recent_actions"`
+	RecentThoughts []MemoryEntry `json:"recent_thoughts"`
+	RecentActions []MemoryEntry `json:"recent_actions"`
 	Personality   string   `json:"personality"`
 	Backstory     string   `json:"backstory"`
 	Memories      []string `json:"memories"`
+	NarratorNotes []string `json:"narrator_notes,omitempty"`
+	Dialogue      []DialogueNode `json:"dialogue,omitempty"`
+	DialogueSpoken []string `json:"dialogue_spoken,omitempty"`
+	Goals         []string `json:"goals,omitempty"`
+	Emotions      map[string]float64 `json:"emotions,omitempty"`
+	AmbienceHeard map[string]string `json:"ambience_heard,omitempty"`
+	Relationship  *Relationship `json:"relationship,omitempty"`
 }
 
-func NewWorldStateClient(debug bool) (*WorldStateClient, error) {
+// Relationship is how an NPC regards the player.
+type Relationship struct {
+	Affinity     int      `json:"affinity"`
+	Interactions []string `json:"interactions,omitempty"`
+}
+
+// DefaultCallTimeout is how long a call to the world-state server may take before it
+// fails with ErrWorldTimeout, unless WithCallTimeout says otherwise.
+const DefaultCallTimeout = 10 * time.Second
+
+// ClientOption configures a WorldStateClient.
+type ClientOption func(*WorldStateClient)
+
+// WithCallTimeout bounds every call to the server by timeout instead of
+// DefaultCallTimeout. Zero or less leaves calls bounded only by their context.
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(w *WorldStateClient) {
+		w.callTimeout = timeout
+	}
+}
+
+// NewWorldStateClient creates a client for the world-state server WORLD_STATE_SERVER
+// names: "python" (the default) runs services/worldstate/world_state.py with uv, "go"
+// serves the same tools in process from internal/worldstate. Both use the same world file.
+func NewWorldStateClient(debug bool, opts ...ClientOption) (*WorldStateClient, error) {
 	client := mcp.NewClient(&mcp.Implementation{
 		Name:    "text-adventure-client",
 		Version: "v1.0.0",
 	}, nil)
 
-	return &WorldStateClient{
-		client: client,
-		debug:  debug,
-	}, nil
+	w := &WorldStateClient{
+		client:      client,
+		debug:       debug,
+		callTimeout: DefaultCallTimeout,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	switch server := strings.ToLower(strings.TrimSpace(os.Getenv("WORLD_STATE_SERVER"))); server {
+	case "", "python":
+	case "go":
+		w.inProcess = worldstate.NewServer(worldstate.NewStore(worldstate.DefaultPath))
+	default:
+		return nil, fmt.Errorf("unknown WORLD_STATE_SERVER %q (expected python or go)", server)
+	}
+	return w, nil
 }
 
 func (w *WorldStateClient) Connect(ctx context.Context) error {
-	cmd := exec.Command("uv", "run", "python", "world_state.py")
-	cmd.Dir = "services/worldstate"
-	
-	transport := mcp.NewCommandTransport(cmd)
+	transport, err := w.transport(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
 
 	session, err := w.client.Connect(ctx, transport)
 	if err != nil {
 		return fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
 
+	w.mu.Lock()
 	w.session = session
+	w.mu.Unlock()
 
 	if w.debug {
-		log.Println("Connected to MCP world state server")
+		if w.inProcess != nil {
+			log.Println("Connected to in-process world state server")
+		} else {
+			log.Println("Connected to MCP world state server")
+		}
 	}
 
 	return nil
 }
 
+// transport starts a server session to connect to: a new session on the in-process
+// server, or a new Python server process.
+func (w *WorldStateClient) transport(ctx context.Context) (mcp.Transport, error) {
+	if w.serverDown != nil {
+		if err := w.serverDown(); err != nil {
+			return nil, err
+		}
+	}
+	if w.inProcess != nil {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		if _, err := w.inProcess.Connect(ctx, serverTransport); err != nil {
+			return nil, err
+		}
+		return clientTransport, nil
+	}
+
+	cmd := exec.Command("uv", "run", "python", "world_state.py")
+	cmd.Dir = "services/worldstate"
+	return mcp.NewCommandTransport(cmd), nil
+}
+
 func (w *WorldStateClient) Close() error {
-	if w.session != nil {
-		w.session.Close()
+	if session := w.currentSession(); session != nil {
+		session.Close()
 	}
 	return nil
 }
@@ -107,14 +300,14 @@ func (w *WorldStateClient) GetWorldState(ctx context.Context) (*WorldState, erro
 		Arguments: nil,
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get world state: %w", err)
 	}
 
 	if result.IsError {
 		errorMsg := result.Content[0].(*mcp.TextContent).Text
-		return nil, fmt.Errorf(errorMsg)
+		return nil, errors.New(errorMsg)
 	}
 
 	var worldState WorldState
@@ -122,6 +315,8 @@ func (w *WorldStateClient) GetWorldState(ctx context.Context) (*WorldState, erro
 		return nil, fmt.Errorf("failed to parse world state: %w", err)
 	}
 
+	w.rememberWorld(&worldState)
+
 	if w.debug {
 		log.Printf("Retrieved world state: player at %s", worldState.Player.Location)
 	}
@@ -135,14 +330,14 @@ func (w *WorldStateClient) MovePlayer(ctx context.Context, location string) (str
 		Arguments: map[string]interface{}{"location": location},
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to move player: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Move player result: %s", response)
@@ -160,14 +355,14 @@ func (w *WorldStateClient) MoveNPC(ctx context.Context, npcID, location string)
 		},
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to move NPC: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Move NPC result: %s", response)
@@ -182,14 +377,14 @@ func (w *WorldStateClient) AddToInventory(ctx context.Context, item string) (str
 		Arguments: map[string]interface{}{"item": item},
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to add to inventory: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Add to inventory result: %s", response)
@@ -204,14 +399,14 @@ func (w *WorldStateClient) RemoveFromInventory(ctx context.Context, item string)
 		Arguments: map[string]interface{}{"item": item},
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to remove from inventory: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Remove from inventory result: %s", response)
@@ -230,14 +425,14 @@ func (w *WorldStateClient) UnlockDoor(ctx context.Context, location, direction,
 		},
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to unlock door: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Unlock door result: %s", response)
@@ -256,14 +451,14 @@ func (w *WorldStateClient) TransferItem(ctx context.Context, item, fromLocation,
 		},
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to transfer item: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Transfer item result: %s", response)
@@ -272,7 +467,9 @@ func (w *WorldStateClient) TransferItem(ctx context.Context, item, fromLocation,
 	return response, nil
 }
 
-func (w *WorldStateClient) UpdateNPCMemory(ctx context.Context, npcID, thought, action string) (string, error) {
+// UpdateNPCMemory records an NPC's latest thought and action. turn dates them; it is
+// sent only to servers whose update_npc_memory accepts it.
+func (w *WorldStateClient) UpdateNPCMemory(ctx context.Context, npcID, thought, action string, turn int) (string, error) {
 	args := map[string]interface{}{
 		"npc_id": npcID,
 	}
@@ -283,20 +480,27 @@ func (w *WorldStateClient) UpdateNPCMemory(ctx context.Context, npcID, thought,
 	if action != "" {
 		args["action"] = action
 	}
+	if turn > 0 && w.ToolAcceptsArg("update_npc_memory", "turn") {
+		args["turn"] = turn
+	}
 	
+	if err := w.ValidateToolArgs("update_npc_memory", args); err != nil {
+		return "", fmt.Errorf("failed to update NPC memory: %w", err)
+	}
+
 	params := &mcp.CallToolParams{
 		Name:      "update_npc_memory",
 		Arguments: args,
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to update NPC memory: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Update NPC memory result: %s", response)
@@ -308,7 +512,9 @@ func (w *WorldStateClient) UpdateNPCMemory(ctx context.Context, npcID, thought,
 func (w *WorldStateClient) ListTools(ctx context.Context) (string, error) {
 	params := &mcp.ListToolsParams{}
 	
-	result, err := w.session.ListTools(ctx, params)
+	ctx, cancel := w.withCallTimeout(ctx)
+	defer cancel()
+	result, err := w.currentSession().ListTools(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to list tools: %w", err)
 	}
@@ -346,14 +552,14 @@ func (w *WorldStateClient) ConfigureNPC(ctx context.Context, npcID, personality,
 		Arguments: args,
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to configure NPC: %w", err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Configure NPC result: %s", response)
@@ -370,14 +576,14 @@ func (w *WorldStateClient) MarkNPCAsMetMethod(ctx context.Context, npcID string)
 		},
 	}
 	
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("mark_npc_as_met tool call failed: %w", err)
 	}
 	
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Mark NPC as met result: %s", response)
@@ -386,20 +592,104 @@ func (w *WorldStateClient) MarkNPCAsMetMethod(ctx context.Context, npcID string)
 	return response, nil
 }
 
+func (w *WorldStateClient) SetPlayerCondition(ctx context.Context, action, condition string) (string, error) {
+	params := &mcp.CallToolParams{
+		Name: "set_player_condition",
+		Arguments: map[string]interface{}{
+			"action":    action,
+			"condition": condition,
+		},
+	}
+
+	result, err := w.callTool(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("set_player_condition tool call failed: %w", err)
+	}
+
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError || strings.HasPrefix(response, "Error:") {
+		return response, errors.New(response)
+	}
+	if w.debug {
+		log.Printf("Set player condition result: %s", response)
+	}
+
+	return response, nil
+}
+
+// SyncPlayerConditions replaces the stored player conditions, e.g. after per-turn decay.
+func (w *WorldStateClient) SyncPlayerConditions(ctx context.Context, conditions []Condition) (string, error) {
+	entries := make([]map[string]interface{}, 0, len(conditions))
+	for _, condition := range conditions {
+		entries = append(entries, map[string]interface{}{"name": condition.Name, "turns": condition.Turns})
+	}
+	params := &mcp.CallToolParams{
+		Name: "sync_player_conditions",
+		Arguments: map[string]interface{}{
+			"conditions": entries,
+		},
+	}
+
+	result, err := w.callTool(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("sync_player_conditions tool call failed: %w", err)
+	}
+
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError || strings.HasPrefix(response, "Error:") {
+		return response, errors.New(response)
+	}
+	if w.debug {
+		log.Printf("Sync player conditions result: %s", response)
+	}
+
+	return response, nil
+}
+
+// RestoreWorldState replaces the server's world with a previously saved snapshot.
+func (w *WorldStateClient) RestoreWorldState(ctx context.Context, world *WorldState) (string, error) {
+	data, err := json.Marshal(world)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal world state: %w", err)
+	}
+	params := &mcp.CallToolParams{
+		Name: "restore_world_state",
+		Arguments: map[string]interface{}{
+			"state": string(data),
+		},
+	}
+
+	result, err := w.callTool(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("restore_world_state tool call failed: %w", err)
+	}
+
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError || strings.HasPrefix(response, "Error:") {
+		return response, errors.New(response)
+	}
+	w.rememberWorld(world)
+	if w.debug {
+		log.Printf("Restore world state result: %s", response)
+	}
+
+	return response, nil
+}
+
 func (w *WorldStateClient) CallTool(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
 	params := &mcp.CallToolParams{
 		Name:      toolName,
 		Arguments: arguments,
 	}
 
-	result, err := w.session.CallTool(ctx, params)
+	result, err := w.callTool(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to call tool %s: %w", toolName, err)
 	}
 
 	response := result.Content[0].(*mcp.TextContent).Text
 	if result.IsError {
-		return response, fmt.Errorf(response)
+		return response, errors.New(response)
 	}
 	if w.debug {
 		log.Printf("Tool %s result: %s", toolName, response)
@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrWorldUnavailable is returned once the server connection was lost and every
+// reconnect attempt failed. Calls fail fast with it until Reconnect succeeds.
+var ErrWorldUnavailable = errors.New("world-state server unavailable")
+
+// ErrWorldTimeout is returned when the server doesn't answer a call within the client's
+// call timeout, as opposed to a tool reporting a failure.
+var ErrWorldTimeout = errors.New("world server timed out")
+
+// reconnectAttempts and reconnectBackoff bound the automatic recovery after a lost
+// connection; the wait grows linearly with each attempt.
+const (
+	reconnectAttempts = 3
+	reconnectBackoff  = 500 * time.Millisecond
+)
+
+// isConnectionLost reports whether err means the server process or its pipe is gone,
+// as opposed to a tool-level error.
+func isConnectionLost(err error) bool {
+	return errors.Is(err, mcp.ErrConnectionClosed) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe)
+}
+
+// Unavailable reports whether the client has given up on the server.
+func (w *WorldStateClient) Unavailable() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.unavailable
+}
+
+// Reconnect replaces the session with a freshly started server and clears the
+// unavailable state on success.
+func (w *WorldStateClient) Reconnect(ctx context.Context) error {
+	w.reconnectMu.Lock()
+	defer w.reconnectMu.Unlock()
+	return w.reconnect(ctx)
+}
+
+func (w *WorldStateClient) reconnect(ctx context.Context) error {
+	if session := w.currentSession(); session != nil {
+		session.Close()
+	}
+	w.mu.Lock()
+	w.reconnects++
+	w.mu.Unlock()
+	if err := w.Connect(ctx); err != nil {
+		return err
+	}
+	if err := w.replayWorld(ctx); err != nil && w.debug {
+		// The server still has the world it persisted itself; carry on with that
+		log.Printf("Replaying world state after reconnect failed: %v", err)
+	}
+	w.mu.Lock()
+	w.unavailable = false
+	w.mu.Unlock()
+	return nil
+}
+
+// ReconnectAttempts reports how many times the client has restarted the server.
+func (w *WorldStateClient) ReconnectAttempts() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.reconnects
+}
+
+func (w *WorldStateClient) rememberWorld(world *WorldState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastWorld = world
+}
+
+// replayWorld pushes the last known world to a freshly started server, so a server that
+// lost its state (or came back with an older file) agrees with the game again.
+func (w *WorldStateClient) replayWorld(ctx context.Context) error {
+	w.mu.Lock()
+	world := w.lastWorld
+	w.mu.Unlock()
+	if world == nil {
+		return nil
+	}
+	data, err := json.Marshal(world)
+	if err != nil {
+		return fmt.Errorf("failed to marshal world state: %w", err)
+	}
+	result, err := w.callWithTimeout(ctx, w.currentSession(), &mcp.CallToolParams{
+		Name:      "restore_world_state",
+		Arguments: map[string]interface{}{"state": string(data)},
+	})
+	if err != nil {
+		return err
+	}
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError || strings.HasPrefix(response, "Error:") {
+		return errors.New(response)
+	}
+	return nil
+}
+
+// HealthCheck pings the server and, when the connection turns out to be lost, recovers
+// it the way a failed tool call would. It lets an idle game notice a crashed server
+// before the player's next action does.
+func (w *WorldStateClient) HealthCheck(ctx context.Context) error {
+	if w.Unavailable() {
+		return ErrWorldUnavailable
+	}
+	session := w.currentSession()
+	if session == nil {
+		return ErrWorldUnavailable
+	}
+	pingCtx, cancel := w.withCallTimeout(ctx)
+	defer cancel()
+	err := session.Ping(pingCtx, nil)
+	if err == nil || !isConnectionLost(err) {
+		return err
+	}
+	return w.recoverConnection(ctx, session)
+}
+
+// recoverConnection retries the connection after a call failed on session. Concurrent
+// callers that lost the same session share one recovery. When every attempt fails the
+// client is marked unavailable.
+func (w *WorldStateClient) recoverConnection(ctx context.Context, failed *mcp.ClientSession) error {
+	w.reconnectMu.Lock()
+	defer w.reconnectMu.Unlock()
+	if current := w.currentSession(); current != failed && current != nil {
+		return nil
+	}
+	if w.Unavailable() {
+		return ErrWorldUnavailable
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(attempt) * reconnectBackoff):
+		}
+		if lastErr = ctx.Err(); lastErr != nil {
+			break
+		}
+		if lastErr = w.reconnect(ctx); lastErr == nil {
+			return nil
+		}
+	}
+	if ctx.Err() != nil {
+		// The caller gave up, not the server; a later call will try again
+		return lastErr
+	}
+	w.mu.Lock()
+	w.unavailable = true
+	w.mu.Unlock()
+	return fmt.Errorf("%w: %v", ErrWorldUnavailable, lastErr)
+}
+
+func (w *WorldStateClient) currentSession() *mcp.ClientSession {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.session
+}
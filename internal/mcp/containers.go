@@ -0,0 +1,29 @@
+package mcp
+
+import "context"
+
+// PutItemInContainer moves an item from wherever it is into a container item.
+func (w *WorldStateClient) PutItemInContainer(ctx context.Context, item, container string) (string, error) {
+	return errorResponse(w.CallToolValidated(ctx, "put_item_in_container", map[string]interface{}{
+		"item":      item,
+		"container": container,
+	}))
+}
+
+// TakeItemFromContainer moves an item out of a container to a location, an NPC or the
+// player.
+func (w *WorldStateClient) TakeItemFromContainer(ctx context.Context, item, container, toLocation string) (string, error) {
+	return errorResponse(w.CallToolValidated(ctx, "take_item_from_container", map[string]interface{}{
+		"item":        item,
+		"container":   container,
+		"to_location": toLocation,
+	}))
+}
+
+// OpenContainer records that the player has opened or looked inside a container, so its
+// contents enter world context.
+func (w *WorldStateClient) OpenContainer(ctx context.Context, container string) (string, error) {
+	return errorResponse(w.CallToolValidated(ctx, "open_container", map[string]interface{}{
+		"container": container,
+	}))
+}
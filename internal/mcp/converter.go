@@ -1,6 +1,10 @@
 package mcp
 
-import "textadventure/internal/game"
+import (
+	"sort"
+
+	"textadventure/internal/game"
+)
 
 func MCPToGameWorldState(mcpWorld *WorldState) game.WorldState {
 	gameLocations := make(map[string]game.LocationInfo)
@@ -10,6 +14,10 @@ func MCPToGameWorldState(mcpWorld *WorldState) game.WorldState {
 			Name:  mcpLoc.Name,
 			Facts: mcpLoc.Facts,
 			Exits: mcpLoc.Exits,
+			Outdoors: mcpLoc.Outdoors,
+			NarratorNotes: mcpLoc.NarratorNotes,
+			Ambience: mcpLoc.Ambience,
+			Doors: doorsToGame(mcpLoc.DoorStates),
 		}
 	}
 	
@@ -18,23 +26,54 @@ func MCPToGameWorldState(mcpWorld *WorldState) game.WorldState {
 		gameNPCs[npcID] = game.NPCInfo{
 			Location:       mcpNPC.Location,
 			DebugColor:     mcpNPC.DebugColor,
-			Description:    mcpNPC.Name,
+			Description:    npcDescription(mcpNPC),
 			Inventory:      mcpNPC.Inventory,
-			RecentThoughts: mcpNPC.RecentThoughts,
-			RecentActions:  mcpNPC.RecentActions,
+			RecentThoughts: memoryToGame(mcpNPC.RecentThoughts),
+			RecentActions:  memoryToGame(mcpNPC.RecentActions),
 			Personality:    mcpNPC.Personality,
 			Backstory:      mcpNPC.Backstory,
 			Memories:       mcpNPC.Memories,
 			Facts:          mcpNPC.Facts,
+			NarratorNotes:  mcpNPC.NarratorNotes,
+			Dialogue:       dialogueToGame(mcpNPC.Dialogue),
+			DialogueSpoken: mcpNPC.DialogueSpoken,
+			Goals:          mcpNPC.Goals,
+			Emotions:       mcpNPC.Emotions,
+			AmbienceHeard:  mcpNPC.AmbienceHeard,
+			Relationship:   relationshipToGame(mcpNPC.Relationship),
+		}
+	}
+	
+	gameItems := make(map[string]game.ItemInfo)
+	for itemID, mcpItem := range mcpWorld.Items {
+		gameItems[itemID] = game.ItemInfo{
+			Name:        mcpItem.Name,
+			Facts:       mcpItem.Facts,
+			Location:    mcpItem.Location,
+			IsContainer: mcpItem.IsContainer,
+			Contains:    mcpItem.Contains,
+			Locked:      mcpItem.Locked,
+			Capacity:    mcpItem.Capacity,
 		}
 	}
 	
+	var conditions []game.PlayerCondition
+	for _, condition := range mcpWorld.Player.Conditions {
+		conditions = append(conditions, game.PlayerCondition{Name: condition.Name, Turns: condition.Turns})
+	}
+	
 	return game.WorldState{
 		Location:  mcpWorld.Player.Location,
 		Inventory: mcpWorld.Player.Inventory,
 		MetNPCs:   mcpWorld.Player.MetNPCs,
+		VisitedLocations: mcpWorld.Player.VisitedLocations,
+		Conditions: conditions,
 		Locations: gameLocations,
 		NPCs:      gameNPCs,
+		Items:     gameItems,
+		ScheduledEvents: scheduledEventsToGame(mcpWorld.ScheduledEvents),
+		Quests:    questsToGame(mcpWorld.Quests),
+		Goals:     mcpWorld.Player.Goals,
 	}
 }
 
@@ -46,7 +85,11 @@ func GameToMCPWorldState(gameWorld game.WorldState) *WorldState {
 			Name:       gameLoc.Name,
 			Facts:      gameLoc.Facts,
 			Exits:      gameLoc.Exits,
-			DoorStates: make(map[string]Door),
+			DoorStates: doorsFromGame(gameLoc.Doors),
+			Items:      locationItems(gameWorld, locID),
+			Outdoors:   gameLoc.Outdoors,
+			NarratorNotes: gameLoc.NarratorNotes,
+			Ambience:   gameLoc.Ambience,
 		}
 	}
 	
@@ -54,26 +97,101 @@ func GameToMCPWorldState(gameWorld game.WorldState) *WorldState {
 	for npcID, gameNPC := range gameWorld.NPCs {
 		mcpNPCs[npcID] = NPC{
 			Name:           gameNPC.Description,
+			Description:    gameNPC.Description,
 			Location:       gameNPC.Location,
 			DebugColor:     gameNPC.DebugColor,
 			Facts:          gameNPC.Facts,
 			Inventory:      gameNPC.Inventory,
-			RecentThoughts: gameNPC.RecentThoughts,
-			RecentActions:  gameNPC.RecentActions,
+			RecentThoughts: memoryFromGame(gameNPC.RecentThoughts),
+			RecentActions:  memoryFromGame(gameNPC.RecentActions),
 			Personality:    gameNPC.Personality,
 			Backstory:      gameNPC.Backstory,
 			Memories:       gameNPC.Memories,
+			NarratorNotes:  gameNPC.NarratorNotes,
+			Dialogue:       dialogueFromGame(gameNPC.Dialogue),
+			DialogueSpoken: gameNPC.DialogueSpoken,
+			Goals:          gameNPC.Goals,
+			Emotions:       gameNPC.Emotions,
+			AmbienceHeard:  gameNPC.AmbienceHeard,
+			Relationship:   relationshipFromGame(gameNPC.Relationship),
+		}
+	}
+	
+	mcpItems := make(map[string]Item)
+	for itemID, gameItem := range gameWorld.Items {
+		mcpItems[itemID] = Item{
+			Name:        gameItem.Name,
+			Facts:       gameItem.Facts,
+			Location:    gameItem.Location,
+			IsContainer: gameItem.IsContainer,
+			Contains:    gameItem.Contains,
+			Locked:      gameItem.Locked,
+			Capacity:    gameItem.Capacity,
 		}
 	}
 	
 	return &WorldState{
 		Player: Player{
-			Location:  gameWorld.Location,
-			Inventory: gameWorld.Inventory,
-			MetNPCs:   gameWorld.MetNPCs,
+			Location:   gameWorld.Location,
+			Inventory:  gameWorld.Inventory,
+			MetNPCs:    gameWorld.MetNPCs,
+			VisitedLocations: gameWorld.VisitedLocations,
+			Conditions: GameToMCPConditions(gameWorld.Conditions),
+			Goals:      gameWorld.Goals,
 		},
 		Locations: mcpLocations,
-		Items:     make(map[string]Item),
+		Items:     mcpItems,
 		NPCs:      mcpNPCs,
+		ScheduledEvents: GameToMCPScheduledEvents(gameWorld.ScheduledEvents),
+		Quests:    gameToMCPQuests(gameWorld.Quests),
 	}
-}
\ No newline at end of file
+}
+
+// locationItems lists the items lying in a location, which the server keeps on the
+// location as well as on each item. The game only tracks the item side.
+func locationItems(gameWorld game.WorldState, locationID string) []string {
+	items := []string{}
+	for itemID, item := range gameWorld.Items {
+		if item.Location == locationID {
+			items = append(items, itemID)
+		}
+	}
+	sort.Strings(items)
+	return items
+}
+
+func GameToMCPConditions(conditions []game.PlayerCondition) []Condition {
+	mcpConditions := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		mcpConditions = append(mcpConditions, Condition{Name: condition.Name, Turns: condition.Turns})
+	}
+	return mcpConditions
+}
+
+// npcDescription prefers the server's public description, falling back to the legacy
+// name field used before descriptions were sent.
+func npcDescription(npc NPC) string {
+	if npc.Description != "" {
+		return npc.Description
+	}
+	return npc.Name
+}
+
+func doorsToGame(doors map[string]Door) map[string]game.DoorInfo {
+	if len(doors) == 0 {
+		return nil
+	}
+	gameDoors := make(map[string]game.DoorInfo, len(doors))
+	for direction, door := range doors {
+		gameDoors[direction] = game.DoorInfo{Locked: door.Locked, Description: door.Description}
+	}
+	return gameDoors
+}
+
+func doorsFromGame(doors map[string]game.DoorInfo) map[string]Door {
+	mcpDoors := make(map[string]Door, len(doors))
+	for direction, door := range doors {
+		mcpDoors[direction] = Door{Locked: door.Locked, Description: door.Description}
+	}
+	return mcpDoors
+}
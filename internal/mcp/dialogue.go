@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"textadventure/internal/game"
+)
+
+// DialogueNode is an authored NPC line as the scenario defines it.
+type DialogueNode struct {
+	ID           string              `json:"id"`
+	When         string              `json:"when,omitempty"`
+	Line         string              `json:"line"`
+	Options      []string            `json:"options,omitempty"`
+	Effects      []ScheduledMutation `json:"effects,omitempty"`
+	QuestUpdates map[string]string   `json:"quest_updates,omitempty"`
+	Repeat       bool                `json:"repeat,omitempty"`
+}
+
+func dialogueToGame(nodes []DialogueNode) []game.DialogueNode {
+	if nodes == nil {
+		return nil
+	}
+	result := make([]game.DialogueNode, len(nodes))
+	for i, node := range nodes {
+		effects := make([]game.ScheduledMutation, len(node.Effects))
+		for j, effect := range node.Effects {
+			effects[j] = game.ScheduledMutation{Tool: effect.Tool, Args: effect.Args}
+		}
+		result[i] = game.DialogueNode{
+			ID:           node.ID,
+			When:         node.When,
+			Line:         node.Line,
+			Options:      node.Options,
+			Effects:      effects,
+			QuestUpdates: node.QuestUpdates,
+			Repeat:       node.Repeat,
+		}
+	}
+	return result
+}
+
+func dialogueFromGame(nodes []game.DialogueNode) []DialogueNode {
+	if nodes == nil {
+		return nil
+	}
+	result := make([]DialogueNode, len(nodes))
+	for i, node := range nodes {
+		result[i] = DialogueNode{
+			ID:           node.ID,
+			When:         node.When,
+			Line:         node.Line,
+			Options:      node.Options,
+			Effects:      scheduledMutationsFromGame(node.Effects),
+			QuestUpdates: node.QuestUpdates,
+			Repeat:       node.Repeat,
+		}
+	}
+	return result
+}
+
+// RecordDialogue marks an NPC's dialogue node as said and applies its quest updates.
+func (w *WorldStateClient) RecordDialogue(ctx context.Context, npcID, nodeID string, questUpdates map[string]string) (string, error) {
+	if questUpdates == nil {
+		questUpdates = map[string]string{}
+	}
+	params := &mcp.CallToolParams{
+		Name: "record_dialogue",
+		Arguments: map[string]interface{}{
+			"npc_id":        npcID,
+			"node_id":       nodeID,
+			"quest_updates": questUpdates,
+		},
+	}
+
+	result, err := w.callTool(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("record_dialogue tool call failed: %w", err)
+	}
+
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError {
+		return response, errors.New(response)
+	}
+	if w.debug {
+		log.Printf("Record dialogue result: %s", response)
+	}
+	return response, nil
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// AdjustNPCEmotion changes one of an NPC's emotions by delta, clamped to 0..1 on the
+// server. cause is what the NPC perceived that changed it, kept for the server's log.
+func (w *WorldStateClient) AdjustNPCEmotion(ctx context.Context, npcID, emotion string, delta float64, cause string) (string, error) {
+	args := map[string]interface{}{
+		"npc_id":  npcID,
+		"emotion": emotion,
+		"delta":   delta,
+	}
+	if cause != "" {
+		args["cause"] = cause
+	}
+	return errorResponse(w.CallToolValidated(ctx, "adjust_npc_emotion", args))
+}
+
+// SyncNPCEmotions replaces an NPC's stored emotions, e.g. after per-turn decay.
+func (w *WorldStateClient) SyncNPCEmotions(ctx context.Context, npcID string, emotions map[string]float64) (string, error) {
+	if emotions == nil {
+		emotions = map[string]float64{}
+	}
+	return errorResponse(w.CallToolValidated(ctx, "sync_npc_emotions", map[string]interface{}{
+		"npc_id":   npcID,
+		"emotions": emotions,
+	}))
+}
+
+// errorResponse turns a tool response the server marked as an error in its text,
+// rather than with IsError, into an error.
+func errorResponse(response string, err error) (string, error) {
+	if err == nil && strings.HasPrefix(response, "Error:") {
+		return response, errors.New(response)
+	}
+	return response, err
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// AddLocationFacts appends established facts to a location.
+func (w *WorldStateClient) AddLocationFacts(ctx context.Context, locationID string, facts []string) (string, error) {
+	if err := requireFacts("add_location_facts", "location_id", locationID, facts); err != nil {
+		return "", err
+	}
+	return w.CallToolValidated(ctx, "add_location_facts", map[string]interface{}{
+		"location_id": locationID,
+		"new_facts":   facts,
+	})
+}
+
+// AddItemFacts appends established facts to an existing item.
+func (w *WorldStateClient) AddItemFacts(ctx context.Context, itemID string, facts []string) (string, error) {
+	if err := requireFacts("add_item_facts", "item_id", itemID, facts); err != nil {
+		return "", err
+	}
+	return w.CallToolValidated(ctx, "add_item_facts", map[string]interface{}{
+		"item_id":   itemID,
+		"new_facts": facts,
+	})
+}
+
+// AddNPCFacts appends established facts to an NPC.
+func (w *WorldStateClient) AddNPCFacts(ctx context.Context, npcID string, facts []string) (string, error) {
+	if err := requireFacts("add_npc_facts", "npc_id", npcID, facts); err != nil {
+		return "", err
+	}
+	return w.CallToolValidated(ctx, "add_npc_facts", map[string]interface{}{
+		"npc_id":    npcID,
+		"new_facts": facts,
+	})
+}
+
+// CreateItem registers a new item at a location. It fails if the item already exists.
+func (w *WorldStateClient) CreateItem(ctx context.Context, itemID, name, location string, initialFacts []string) (string, error) {
+	for arg, value := range map[string]string{"item_id": itemID, "name": name, "location": location} {
+		if strings.TrimSpace(value) == "" {
+			return "", fmt.Errorf("create_item requires '%s'", arg)
+		}
+	}
+	args := map[string]interface{}{
+		"item_id":  itemID,
+		"name":     name,
+		"location": location,
+	}
+	if len(initialFacts) > 0 {
+		args["initial_facts"] = initialFacts
+	}
+	return w.CallToolValidated(ctx, "create_item", args)
+}
+
+func requireFacts(tool, idArg, id string, facts []string) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("%s requires '%s'", tool, idArg)
+	}
+	for _, fact := range facts {
+		if strings.TrimSpace(fact) != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s requires at least one non-empty fact", tool)
+}
@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"textadventure/internal/worldstate"
+)
+
+// FakeCall is one tool call a FakeWorldClient answered.
+type FakeCall struct {
+	Tool string
+	Args map[string]interface{}
+}
+
+// FakeWorldClient is a WorldClient for tests that need no server process: the real
+// client, connected to the Go world-state server in process, over a world kept in
+// memory. Every tool follows the server's rules and answers as the server does, refusals
+// included, so tests see what a game would. It also records the calls made and can make
+// a tool fail.
+type FakeWorldClient struct {
+	*WorldStateClient
+	store *worldstate.Store
+	mu    sync.Mutex
+	fails map[string]error
+	calls []FakeCall
+	down  error
+}
+
+// NewFakeWorldClient creates a fake serving a copy of world.
+func NewFakeWorldClient(world *WorldState) *FakeWorldClient {
+	data, err := json.Marshal(world)
+	if err != nil {
+		panic(fmt.Sprintf("fake world client: encode world: %v", err))
+	}
+	f := &FakeWorldClient{store: worldstate.NewMemoryStore(data), fails: make(map[string]error)}
+	f.WorldStateClient = &WorldStateClient{
+		client:    mcp.NewClient(&mcp.Implementation{Name: "text-adventure-fake", Version: "v1.0.0"}, nil),
+		inProcess: worldstate.NewServer(f.store),
+		serverDown: func() error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			return f.down
+		},
+	}
+	f.WrapToolCaller(f.record)
+	if err := f.Connect(context.Background()); err != nil {
+		panic(fmt.Sprintf("fake world client: %v", err))
+	}
+	return f
+}
+
+// record logs each call before it reaches the server, and fails it instead when its
+// tool was set to fail.
+func (f *FakeWorldClient) record(next ToolCaller) ToolCaller {
+	return ToolCallerFunc(func(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+		args, _ := params.Arguments.(map[string]interface{})
+		f.mu.Lock()
+		f.calls = append(f.calls, FakeCall{Tool: params.Name, Args: args})
+		err := f.fails[params.Name]
+		f.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return next.CallTool(ctx, params)
+	})
+}
+
+// FailTool makes calls to the tool return err without changing the world.
+func (f *FakeWorldClient) FailTool(name string, err error) *FakeWorldClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fails[name] = err
+	return f
+}
+
+// Crash drops the connection and keeps the server down: calls lose the connection
+// and every reconnect fails with err, until Restart.
+func (f *FakeWorldClient) Crash(err error) {
+	f.mu.Lock()
+	f.down = err
+	f.mu.Unlock()
+	if session := f.currentSession(); session != nil {
+		session.Close()
+	}
+}
+
+// Restart lets reconnects after a Crash succeed again. The world is as the crash left it.
+func (f *FakeWorldClient) Restart() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = nil
+}
+
+// Calls returns the tool calls made so far, in order.
+func (f *FakeWorldClient) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+// World returns a copy of the world as the calls so far have left it, without making a
+// call of its own.
+func (f *FakeWorldClient) World() *WorldState {
+	data, err := f.store.Snapshot()
+	if err != nil {
+		panic(fmt.Sprintf("fake world client: %v", err))
+	}
+	var world WorldState
+	if err := json.Unmarshal(data, &world); err != nil {
+		panic(fmt.Sprintf("fake world client: decode world: %v", err))
+	}
+	return &world
+}
+
+var _ WorldClient = (*FakeWorldClient)(nil)
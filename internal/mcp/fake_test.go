@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func fakeWorld() *WorldState {
+	return &WorldState{
+		Player: Player{Location: "foyer", Inventory: []string{"lamp"}},
+		Locations: map[string]Location{
+			"foyer": {Name: "foyer", Exits: map[string]string{"north": "study", "east": "vault"},
+				DoorStates: map[string]Door{"east": {Locked: true, Description: "iron door"}}},
+			"study": {Name: "study", Exits: map[string]string{"south": "foyer"}, Items: []string{"key"}},
+			"vault": {Name: "vault", Exits: map[string]string{"west": "foyer"}},
+		},
+		Items: map[string]Item{
+			"lamp": {Name: "lamp", Location: "player"},
+			"key":  {Name: "key", Location: "study", CanUnlock: []string{"foyer_east"}},
+		},
+		NPCs: map[string]NPC{"elena": {Name: "Elena", Location: "study"}},
+	}
+}
+
+func TestFakeWorldClientRefusesWhatTheServerRefuses(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		call func(f *FakeWorldClient) (string, error)
+		want string
+	}{
+		{"unknown location", func(f *FakeWorldClient) (string, error) { return f.MovePlayer(ctx, "attic") }, "does not exist"},
+		{"no exit", func(f *FakeWorldClient) (string, error) { return f.MoveNPC(ctx, "elena", "vault") }, "Cannot move directly"},
+		{"locked door", func(f *FakeWorldClient) (string, error) { return f.MovePlayer(ctx, "vault") }, "iron door is locked"},
+		{"item elsewhere", func(f *FakeWorldClient) (string, error) { return f.AddToInventory(ctx, "key") }, "not available in foyer"},
+		{"not carried", func(f *FakeWorldClient) (string, error) { return f.RemoveFromInventory(ctx, "key") }, "not in inventory"},
+		{"wrong holder", func(f *FakeWorldClient) (string, error) { return f.TransferItemToNPC(ctx, "lamp", "foyer", "elena") }, "not in location"},
+		{"key not carried", func(f *FakeWorldClient) (string, error) { return f.UnlockDoor(ctx, "foyer", "east", "key") }, "does not have key"},
+		{"unknown condition", func(f *FakeWorldClient) (string, error) { return f.SetPlayerCondition(ctx, "add", "cursed") }, "Unknown condition"},
+	}
+	for _, tt := range tests {
+		f := NewFakeWorldClient(fakeWorld())
+		// Refusals come back as the server's "Error: ..." answer
+		response, err := tt.call(f)
+		if !strings.HasPrefix(response, "Error:") || !strings.Contains(response, tt.want) {
+			t.Errorf("%s: response = %q, %v; want an error mentioning %q", tt.name, response, err, tt.want)
+		}
+		if world := f.World(); world.Player.Location != "foyer" || !slices.Equal(world.Player.Inventory, []string{"lamp"}) {
+			t.Errorf("%s: a refused call changed the world", tt.name)
+		}
+	}
+}
+
+func TestFakeWorldClientAppliesMutations(t *testing.T) {
+	ctx := context.Background()
+	f := NewFakeWorldClient(fakeWorld())
+	steps := []func() (string, error){
+		func() (string, error) { return f.MovePlayer(ctx, "study") },
+		func() (string, error) { return f.AddToInventory(ctx, "key") },
+		func() (string, error) { return f.TransferItemToNPC(ctx, "lamp", "player", "elena") },
+		func() (string, error) { return f.MovePlayer(ctx, "foyer") },
+		func() (string, error) { return f.UnlockDoor(ctx, "foyer", "east", "key") },
+		func() (string, error) { return f.MovePlayer(ctx, "vault") },
+		func() (string, error) { return f.MarkNPCAsMetMethod(ctx, "elena") },
+		func() (string, error) { return f.SetPlayerCondition(ctx, "add", "soaked") },
+	}
+	for i, step := range steps {
+		if response, err := step(); err != nil || strings.HasPrefix(response, "Error:") {
+			t.Fatalf("step %d: %q, %v", i, response, err)
+		}
+	}
+
+	world, err := f.GetWorldState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world.Player.Location != "vault" || !slices.Equal(world.Player.Inventory, []string{"key"}) {
+		t.Errorf("player at %q carrying %q", world.Player.Location, world.Player.Inventory)
+	}
+	if world.Items["key"].Location != "player" || world.Items["lamp"].Location != "elena" {
+		t.Errorf("key at %q, lamp at %q", world.Items["key"].Location, world.Items["lamp"].Location)
+	}
+	if !slices.Equal(world.NPCs["elena"].Inventory, []string{"lamp"}) {
+		t.Errorf("elena carries %q", world.NPCs["elena"].Inventory)
+	}
+	if world.Locations["foyer"].DoorStates["east"].Locked {
+		t.Error("door still locked")
+	}
+	if !slices.Equal(world.Player.VisitedLocations, []string{"study", "foyer", "vault"}) || !slices.Equal(world.Player.MetNPCs, []string{"elena"}) {
+		t.Errorf("visited %q, met %q", world.Player.VisitedLocations, world.Player.MetNPCs)
+	}
+	if len(world.Player.Conditions) != 1 || world.Player.Conditions[0].Name != "soaked" {
+		t.Errorf("conditions = %v", world.Player.Conditions)
+	}
+
+	// The returned world is a copy
+	world.Player.Location = "attic"
+	if f.World().Player.Location != "vault" {
+		t.Error("editing a returned world changed the fake's")
+	}
+}
+
+func TestFakeWorldClientFailTool(t *testing.T) {
+	f := NewFakeWorldClient(fakeWorld()).FailTool("move_player", ErrWorldTimeout)
+	if _, err := f.MovePlayer(context.Background(), "study"); !errors.Is(err, ErrWorldTimeout) {
+		t.Errorf("err = %v, want ErrWorldTimeout", err)
+	}
+	if f.World().Player.Location != "foyer" {
+		t.Error("a failed tool changed the world")
+	}
+	if calls := f.Calls(); len(calls) != 1 || calls[0].Tool != "move_player" || calls[0].Args["location"] != "study" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+// Tools beyond movement and inventory change the world too, by the server's rules.
+func TestFakeWorldClientRunsEveryToolOnTheServer(t *testing.T) {
+	ctx := context.Background()
+	world := fakeWorld()
+	world.Items["box"] = Item{Name: "box", Location: "foyer", IsContainer: true}
+	foyer := world.Locations["foyer"]
+	foyer.Items = []string{"box"}
+	world.Locations["foyer"] = foyer
+	f := NewFakeWorldClient(world)
+
+	steps := []func() (string, error){
+		func() (string, error) { return f.PutItemInContainer(ctx, "lamp", "box") },
+		func() (string, error) { return f.OpenContainer(ctx, "box") },
+		func() (string, error) { return f.AdjustNPCEmotion(ctx, "elena", "fear", 0.5, "a scream") },
+		func() (string, error) { return f.SetNPCGoal(ctx, "elena", "add", "find the way out") },
+		func() (string, error) { return f.UpdateRelationship(ctx, "elena", 2, "shared the lamp") },
+	}
+	for i, step := range steps {
+		if response, err := step(); err != nil || strings.HasPrefix(response, "Error:") {
+			t.Fatalf("step %d: %q, %v", i, response, err)
+		}
+	}
+
+	after := f.World()
+	if len(after.Player.Inventory) != 0 || !slices.Equal(after.Items["box"].Contains, []string{"lamp"}) || after.Items["lamp"].Location != "box" {
+		t.Errorf("player carries %q, box holds %q", after.Player.Inventory, after.Items["box"].Contains)
+	}
+	elena := after.NPCs["elena"]
+	if elena.Emotions["fear"] != 0.5 || !slices.Equal(elena.Goals, []string{"find the way out"}) {
+		t.Errorf("elena feels %v and wants %q", elena.Emotions, elena.Goals)
+	}
+	if elena.Relationship == nil || elena.Relationship.Affinity != 2 {
+		t.Errorf("elena regards the player as %+v", elena.Relationship)
+	}
+	if response, _ := f.SetNPCGoal(ctx, "marcus", "add", "hide"); !strings.HasPrefix(response, "Error:") {
+		t.Errorf("a goal for a missing NPC was accepted: %q", response)
+	}
+}
@@ -0,0 +1,23 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SetNPCGoal adds a goal to an NPC (action "add") or marks one of its goals complete
+// (action "complete"), which removes it.
+func (w *WorldStateClient) SetNPCGoal(ctx context.Context, npcID, action, goal string) (string, error) {
+	if action != "add" && action != "complete" {
+		return "", fmt.Errorf("set_npc_goal action must be \"add\" or \"complete\", got %q", action)
+	}
+	if strings.TrimSpace(goal) == "" {
+		return "", fmt.Errorf("set_npc_goal requires 'goal'")
+	}
+	return errorResponse(w.CallToolValidated(ctx, "set_npc_goal", map[string]interface{}{
+		"npc_id": npcID,
+		"action": action,
+		"goal":   goal,
+	}))
+}
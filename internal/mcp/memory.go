@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"textadventure/internal/game"
+)
+
+// MemoryEntry is an NPC's recent thought or action as the server stores it. Older
+// servers store plain strings; those decode with Turn 0 (unknown).
+type MemoryEntry struct {
+	Text string `json:"text"`
+	Turn int    `json:"turn,omitempty"`
+}
+
+func (e *MemoryEntry) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*e = MemoryEntry{Text: text}
+		return nil
+	}
+	type plain MemoryEntry
+	var entry plain
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	*e = MemoryEntry(entry)
+	return nil
+}
+
+func memoryToGame(entries []MemoryEntry) []game.NPCMemoryEntry {
+	if entries == nil {
+		return nil
+	}
+	result := make([]game.NPCMemoryEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = game.NPCMemoryEntry{Text: entry.Text, Turn: entry.Turn}
+	}
+	return result
+}
+
+func memoryFromGame(entries []game.NPCMemoryEntry) []MemoryEntry {
+	if entries == nil {
+		return nil
+	}
+	result := make([]MemoryEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = MemoryEntry{Text: entry.Text, Turn: entry.Turn}
+	}
+	return result
+}
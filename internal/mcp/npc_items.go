@@ -0,0 +1,15 @@
+package mcp
+
+import "context"
+
+// TransferItemToNPC moves an item into an NPC's inventory from a location, or from the
+// player's inventory when from is "player".
+func (w *WorldStateClient) TransferItemToNPC(ctx context.Context, item, from, npcID string) (string, error) {
+	return errorResponse(w.TransferItem(ctx, item, from, npcID))
+}
+
+// TransferItemFromNPC moves an item from an NPC's inventory to a location, or to the
+// player's inventory when to is "player".
+func (w *WorldStateClient) TransferItemFromNPC(ctx context.Context, item, npcID, to string) (string, error) {
+	return errorResponse(w.TransferItem(ctx, item, npcID, to))
+}
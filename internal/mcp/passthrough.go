@@ -0,0 +1,9 @@
+package mcp
+
+// PassthroughTool allow-lists a scenario-specific server tool for the director. Actors
+// is "player-only" (the default), "npc-only" or "shared"; Usage overrides the tool's
+// server description in the director prompt.
+type PassthroughTool struct {
+	Actors string `json:"actors,omitempty"`
+	Usage  string `json:"usage,omitempty"`
+}
@@ -0,0 +1,25 @@
+package mcp
+
+import "textadventure/internal/game"
+
+func questsToGame(quests []Quest) []game.Quest {
+	if quests == nil {
+		return nil
+	}
+	result := make([]game.Quest, len(quests))
+	for i, quest := range quests {
+		result[i] = game.Quest{ID: quest.ID, Title: quest.Title, Objective: quest.Objective, Status: quest.Status}
+	}
+	return result
+}
+
+func gameToMCPQuests(quests []game.Quest) []Quest {
+	if quests == nil {
+		return nil
+	}
+	result := make([]Quest, len(quests))
+	for i, quest := range quests {
+		result[i] = Quest{ID: quest.ID, Title: quest.Title, Objective: quest.Objective, Status: quest.Status}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+)
+
+// UpdateRelationship changes how an NPC regards the player by delta, remembering the
+// interaction that caused it.
+func (w *WorldStateClient) UpdateRelationship(ctx context.Context, npcID string, delta int, interaction string) (string, error) {
+	if strings.TrimSpace(interaction) == "" {
+		return "", fmt.Errorf("update_relationship requires 'interaction'")
+	}
+	return errorResponse(w.CallToolValidated(ctx, "update_relationship", map[string]interface{}{
+		"npc_id":      npcID,
+		"delta":       delta,
+		"interaction": interaction,
+	}))
+}
+
+func relationshipToGame(r *Relationship) game.Relationship {
+	if r == nil {
+		return game.Relationship{}
+	}
+	return game.Relationship{Affinity: r.Affinity, Interactions: r.Interactions}
+}
+
+func relationshipFromGame(r game.Relationship) *Relationship {
+	if r.IsZero() {
+		return nil
+	}
+	return &Relationship{Affinity: r.Affinity, Interactions: r.Interactions}
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"textadventure/internal/game"
+)
+
+// ScheduledEvent is a pending delayed event as the server stores it.
+type ScheduledEvent struct {
+	ID          int                 `json:"id"`
+	TurnsLeft   int                 `json:"turns_left"`
+	Description string              `json:"description"`
+	Location    string              `json:"location,omitempty"`
+	Mutations   []ScheduledMutation `json:"mutations"`
+}
+
+// ScheduledMutation is a tool call held by a scheduled event.
+type ScheduledMutation struct {
+	Tool string                 `json:"tool"`
+	Args map[string]interface{} `json:"args"`
+}
+
+func scheduledEventsToGame(events []ScheduledEvent) []game.ScheduledEvent {
+	if events == nil {
+		return nil
+	}
+	result := make([]game.ScheduledEvent, len(events))
+	for i, event := range events {
+		mutations := make([]game.ScheduledMutation, len(event.Mutations))
+		for j, mutation := range event.Mutations {
+			mutations[j] = game.ScheduledMutation{Tool: mutation.Tool, Args: mutation.Args}
+		}
+		result[i] = game.ScheduledEvent{
+			ID:          event.ID,
+			TurnsLeft:   event.TurnsLeft,
+			Description: event.Description,
+			Location:    event.Location,
+			Mutations:   mutations,
+		}
+	}
+	return result
+}
+
+// GameToMCPScheduledEvents converts scheduled events for the server, e.g. after the
+// per-turn countdown.
+func GameToMCPScheduledEvents(events []game.ScheduledEvent) []ScheduledEvent {
+	result := make([]ScheduledEvent, len(events))
+	for i, event := range events {
+		result[i] = ScheduledEvent{
+			ID:          event.ID,
+			TurnsLeft:   event.TurnsLeft,
+			Description: event.Description,
+			Location:    event.Location,
+			Mutations:   scheduledMutationsFromGame(event.Mutations),
+		}
+	}
+	return result
+}
+
+func scheduledMutationsFromGame(mutations []game.ScheduledMutation) []ScheduledMutation {
+	result := make([]ScheduledMutation, len(mutations))
+	for i, mutation := range mutations {
+		args := mutation.Args
+		if args == nil {
+			args = map[string]interface{}{}
+		}
+		result[i] = ScheduledMutation{Tool: mutation.Tool, Args: args}
+	}
+	return result
+}
+
+// ScheduleEvent stores an event that fires delayTurns turns from now.
+func (w *WorldStateClient) ScheduleEvent(ctx context.Context, delayTurns int, description, location string, mutations []game.ScheduledMutation) (string, error) {
+	params := &mcp.CallToolParams{
+		Name: "schedule_event",
+		Arguments: map[string]interface{}{
+			"delay_turns": delayTurns,
+			"description": description,
+			"location":    location,
+			"mutations":   scheduledMutationsFromGame(mutations),
+		},
+	}
+
+	result, err := w.callTool(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("schedule_event tool call failed: %w", err)
+	}
+
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError || strings.HasPrefix(response, "Error:") {
+		return response, errors.New(response)
+	}
+	if w.debug {
+		log.Printf("Schedule event result: %s", response)
+	}
+
+	return response, nil
+}
+
+// SyncScheduledEvents replaces the stored pending events after the per-turn countdown.
+func (w *WorldStateClient) SyncScheduledEvents(ctx context.Context, events []ScheduledEvent) (string, error) {
+	params := &mcp.CallToolParams{
+		Name: "sync_scheduled_events",
+		Arguments: map[string]interface{}{
+			"events": events,
+		},
+	}
+
+	result, err := w.callTool(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("sync_scheduled_events tool call failed: %w", err)
+	}
+
+	response := result.Content[0].(*mcp.TextContent).Text
+	if result.IsError || strings.HasPrefix(response, "Error:") {
+		return response, errors.New(response)
+	}
+	if w.debug {
+		log.Printf("Sync scheduled events result: %s", response)
+	}
+
+	return response, nil
+}
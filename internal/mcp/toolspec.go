@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolSpec describes a tool exposed by the world-state server.
+type ToolSpec struct {
+	Name        string
+	Description string
+	Params      []ToolParam
+}
+
+// ToolParam is one argument of a tool, read from its JSON schema.
+type ToolParam struct {
+	Name     string
+	Type     string
+	Required bool
+}
+
+// ListToolSpecs returns the server's tools with their arguments, caching their input
+// schemas for ValidateToolArgs.
+func (w *WorldStateClient) ListToolSpecs(ctx context.Context) ([]ToolSpec, error) {
+	ctx, cancel := w.withCallTimeout(ctx)
+	defer cancel()
+	result, err := w.currentSession().ListTools(ctx, &mcp.ListToolsParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+	w.cacheToolSchemas(result.Tools)
+	return ToolSpecsFromTools(result.Tools), nil
+}
+
+// ToolSpecsFromTools converts a ListTools result into specs, sorted by tool name.
+// Required params come first in schema order, then optional ones alphabetically.
+func ToolSpecsFromTools(tools []*mcp.Tool) []ToolSpec {
+	specs := make([]ToolSpec, 0, len(tools))
+	for _, tool := range tools {
+		spec := ToolSpec{Name: tool.Name, Description: tool.Description}
+		if schema := tool.InputSchema; schema != nil {
+			required := make(map[string]bool, len(schema.Required))
+			for _, name := range schema.Required {
+				if prop, ok := schema.Properties[name]; ok {
+					spec.Params = append(spec.Params, ToolParam{Name: name, Type: schemaType(prop), Required: true})
+					required[name] = true
+				}
+			}
+			var optional []string
+			for name := range schema.Properties {
+				if !required[name] {
+					optional = append(optional, name)
+				}
+			}
+			sort.Strings(optional)
+			for _, name := range optional {
+				spec.Params = append(spec.Params, ToolParam{Name: name, Type: schemaType(schema.Properties[name])})
+			}
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// schemaType renders a property's type for prompts, e.g. "string", "string[]". Nullable
+// unions (as produced for Optional[...] params) collapse to the non-null type.
+func schemaType(schema *jsonschema.Schema) string {
+	if schema == nil {
+		return "any"
+	}
+	typ := schema.Type
+	if typ == "" {
+		for _, t := range schema.Types {
+			if t != "null" {
+				typ = t
+				break
+			}
+		}
+	}
+	if typ == "" {
+		for _, alt := range append(schema.AnyOf, schema.OneOf...) {
+			if alt.Type != "null" {
+				return schemaType(alt)
+			}
+		}
+		return "any"
+	}
+	if typ == "array" {
+		return schemaType(schema.Items) + "[]"
+	}
+	return typ
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// cacheToolSchemas keeps the resolved input schema of each listed tool so calls can be
+// checked before they reach the server. Schemas that fail to resolve are left out.
+func (w *WorldStateClient) cacheToolSchemas(tools []*mcp.Tool) {
+	schemas := make(map[string]*toolSchema, len(tools))
+	for _, tool := range tools {
+		if tool.InputSchema == nil {
+			continue
+		}
+		resolved, err := tool.InputSchema.Resolve(nil)
+		if err != nil {
+			continue
+		}
+		schemas[tool.Name] = &toolSchema{schema: tool.InputSchema, resolved: resolved}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.schemas = schemas
+}
+
+type toolSchema struct {
+	schema   *jsonschema.Schema
+	resolved *jsonschema.Resolved
+}
+
+// ValidateToolArgs checks args against the tool's cached input schema: every required
+// arg must be present, no unknown args may be given, and values must match their types.
+// Tools without a cached schema pass unchecked.
+func (w *WorldStateClient) ValidateToolArgs(toolName string, args map[string]interface{}) error {
+	w.mu.Lock()
+	cached := w.schemas[toolName]
+	w.mu.Unlock()
+	if cached == nil {
+		return nil
+	}
+
+	if len(cached.schema.Properties) > 0 {
+		var unknown []string
+		for name := range args {
+			if _, ok := cached.schema.Properties[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("%s: unknown args %s", toolName, strings.Join(unknown, ", "))
+		}
+	}
+
+	// Round-trip through JSON so typed Go values ([]string etc.) validate as the server sees them
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("%s: encode args: %w", toolName, err)
+	}
+	var instance map[string]any
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return fmt.Errorf("%s: encode args: %w", toolName, err)
+	}
+	if err := cached.resolved.Validate(instance); err != nil {
+		return fmt.Errorf("%s: %w", toolName, err)
+	}
+	return nil
+}
+
+// ToolAcceptsArg reports whether the tool's cached schema declares arg. Optional args
+// newer than some servers are only sent to servers that declare them.
+func (w *WorldStateClient) ToolAcceptsArg(toolName, arg string) bool {
+	w.mu.Lock()
+	cached := w.schemas[toolName]
+	w.mu.Unlock()
+	if cached == nil {
+		return false
+	}
+	_, ok := cached.schema.Properties[arg]
+	return ok
+}
+
+// CallToolValidated is CallTool with the args checked against the tool's schema first,
+// so misnamed or mistyped args fail before the call rather than on the server.
+func (w *WorldStateClient) CallToolValidated(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
+	if err := w.ValidateToolArgs(toolName, arguments); err != nil {
+		return "", fmt.Errorf("invalid args for %s: %w", toolName, err)
+	}
+	return w.CallTool(ctx, toolName, arguments)
+}
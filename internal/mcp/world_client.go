@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"textadventure/internal/game"
+)
+
+// WorldClient is the part of the world-state client the director and its tools use to
+// read and change the world. *WorldStateClient implements it over a server session;
+// FakeWorldClient implements it over the in-process server and an in-memory world.
+type WorldClient interface {
+	GetWorldState(ctx context.Context) (*WorldState, error)
+	CallToolValidated(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error)
+	ListToolSpecs(ctx context.Context) ([]ToolSpec, error)
+	CallTimeout() time.Duration
+
+	MovePlayer(ctx context.Context, location string) (string, error)
+	MoveNPC(ctx context.Context, npcID, location string) (string, error)
+	AddToInventory(ctx context.Context, item string) (string, error)
+	RemoveFromInventory(ctx context.Context, item string) (string, error)
+	UnlockDoor(ctx context.Context, location, direction, keyItem string) (string, error)
+	TransferItem(ctx context.Context, item, fromLocation, toLocation string) (string, error)
+	TransferItemToNPC(ctx context.Context, item, from, npcID string) (string, error)
+	TransferItemFromNPC(ctx context.Context, item, npcID, to string) (string, error)
+	PutItemInContainer(ctx context.Context, item, container string) (string, error)
+	TakeItemFromContainer(ctx context.Context, item, container, toLocation string) (string, error)
+	OpenContainer(ctx context.Context, container string) (string, error)
+
+	UpdateNPCMemory(ctx context.Context, npcID, thought, action string, turn int) (string, error)
+	MarkNPCAsMetMethod(ctx context.Context, npcID string) (string, error)
+	SetPlayerCondition(ctx context.Context, action, condition string) (string, error)
+	RecordDialogue(ctx context.Context, npcID, nodeID string, questUpdates map[string]string) (string, error)
+	SetLocationAmbience(ctx context.Context, locationID, ambience string) (string, error)
+	AdjustNPCEmotion(ctx context.Context, npcID, emotion string, delta float64, cause string) (string, error)
+	SetNPCGoal(ctx context.Context, npcID, action, goal string) (string, error)
+	UpdateRelationship(ctx context.Context, npcID string, delta int, interaction string) (string, error)
+	ScheduleEvent(ctx context.Context, delayTurns int, description, location string, mutations []game.ScheduledMutation) (string, error)
+}
+
+var _ WorldClient = (*WorldStateClient)(nil)
@@ -0,0 +1,37 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ProbeExporter checks the trace exporter's endpoint is reachable and accepts the
+// configured keys, by posting an empty OTLP export, which records nothing. It does
+// nothing when tracing is disabled.
+func ProbeExporter(ctx context.Context, config Config) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.PublicKey == "" || config.SecretKey == "" {
+		return fmt.Errorf("tracing is enabled but LANGFUSE_PUBLIC_KEY or LANGFUSE_SECRET_KEY is unset")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tracesEndpoint(config), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("bad LANGFUSE_HOST %q: %w", config.LangfuseHost, err)
+	}
+	req.Header.Set("Authorization", authorization(config))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", config.LangfuseHost, err)
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s rejected the keys (%s); check LANGFUSE_PUBLIC_KEY and LANGFUSE_SECRET_KEY", config.LangfuseHost, resp.Status)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("%s answered %s; check LANGFUSE_HOST", tracesEndpoint(config), resp.Status)
+	}
+	return nil
+}
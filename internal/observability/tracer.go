@@ -97,16 +97,10 @@ func (tp *TracerProvider) IsEnabled() bool {
 
 // createLangfuseExporter creates an OTLP HTTP exporter configured for Langfuse
 func createLangfuseExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, error) {
-	auth := base64.StdEncoding.EncodeToString([]byte(config.PublicKey + ":" + config.SecretKey))
-	
-	// Use the full traces endpoint path since we're using WithEndpointURL()
-	host := strings.TrimSuffix(config.LangfuseHost, "/")
-	baseEndpoint := fmt.Sprintf("%s/api/public/otel/v1/traces", host)
-	
 	exporter, err := otlptracehttp.New(ctx,
-		otlptracehttp.WithEndpointURL(baseEndpoint),
+		otlptracehttp.WithEndpointURL(tracesEndpoint(config)),
 		otlptracehttp.WithHeaders(map[string]string{
-			"Authorization": "Basic " + auth,
+			"Authorization": authorization(config),
 		}),
 		otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
 		otlptracehttp.WithTimeout(30*time.Second),
@@ -120,6 +114,16 @@ func createLangfuseExporter(ctx context.Context, config Config) (sdktrace.SpanEx
 	return exporter, nil
 }
 
+// tracesEndpoint is the full URL of Langfuse's OTLP traces endpoint, as WithEndpointURL needs.
+func tracesEndpoint(config Config) string {
+	return fmt.Sprintf("%s/api/public/otel/v1/traces", strings.TrimSuffix(config.LangfuseHost, "/"))
+}
+
+// authorization is the basic auth header Langfuse expects, from the project's key pair.
+func authorization(config Config) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(config.PublicKey+":"+config.SecretKey))
+}
+
 // createResource creates an OpenTelemetry resource with service metadata
 func createResource(config Config) (*resource.Resource, error) {
 	return resource.NewWithAttributes(
@@ -187,7 +191,8 @@ func CreateLangfuseAttributes(traceName, sessionID, userID string, tags []string
 	return attrs
 }
 
-// CreateGenAIAttributes creates GenAI semantic convention attributes for LLM spans
+// CreateGenAIAttributes creates GenAI semantic convention attributes for LLM spans.
+// Pass a negative temperature when the request doesn't set one.
 func CreateGenAIAttributes(system, model string, inputTokens, outputTokens int, temperature float64) []attribute.KeyValue {
 	attrs := []attribute.KeyValue{
 		attribute.String("gen_ai.operation.name", "chat"),
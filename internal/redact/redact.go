@@ -0,0 +1,172 @@
+// Package redact masks secrets and personal data before they reach log files or the
+// completions database. Matches become hash-tagged placeholders, so the same secret
+// always gets the same placeholder and log lines can still be correlated.
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Rule is a named pattern whose matches are redacted.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules cover common API key formats, bearer tokens, key=value secrets and
+// email addresses.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "private_key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+		{Name: "api_key", Pattern: regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`)},
+		{Name: "github_token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{Name: "aws_key", Pattern: regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+		{Name: "bearer", Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{8,}=*`)},
+		{Name: "secret", Pattern: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|password|passwd|token)\b["']?\s*[:=]\s*["']?[^\s"',;]{4,}`)},
+		{Name: "email", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	}
+}
+
+// Redactor replaces rule matches with placeholders like "[REDACTED:email:1a2b3c4d]".
+type Redactor struct {
+	rules []Rule
+}
+
+// New creates a redactor applying rules in order.
+func New(rules []Rule) *Redactor {
+	return &Redactor{rules: rules}
+}
+
+// Redact returns text with every rule match replaced by its placeholder. Matches are
+// found in the original text in one pass, so a placeholder is never matched again; where
+// matches overlap, the one starting first wins, then the longer one.
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	type span struct {
+		start, end int
+		rule       string
+	}
+	var spans []span
+	for _, rule := range r.rules {
+		for _, loc := range rule.Pattern.FindAllStringIndex(text, -1) {
+			if loc[1] > loc[0] {
+				spans = append(spans, span{start: loc[0], end: loc[1], rule: rule.Name})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return text
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	var sb strings.Builder
+	last := 0
+	for _, sp := range spans {
+		if sp.start < last {
+			continue
+		}
+		sb.WriteString(text[last:sp.start])
+		sb.WriteString(Placeholder(sp.rule, text[sp.start:sp.end]))
+		last = sp.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// Placeholder is the stand-in for a redacted value: the rule name and a short hash of
+// the value, so repeated values can be matched up without revealing them.
+func Placeholder(ruleName, value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("[REDACTED:%s:%s]", ruleName, hex.EncodeToString(sum[:4]))
+}
+
+// KeywordRule redacts each literal keyword, ignoring case.
+func KeywordRule(keywords []string) (Rule, bool) {
+	var quoted []string
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			quoted = append(quoted, regexp.QuoteMeta(keyword))
+		}
+	}
+	if len(quoted) == 0 {
+		return Rule{}, false
+	}
+	return Rule{Name: "keyword", Pattern: regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))}, true
+}
+
+// LoadFromEnv builds a redactor from the default rules plus LOG_REDACT_KEYWORDS
+// (comma-separated literals) and LOG_REDACT_PATTERNS (whitespace-separated regexes;
+// use \s inside a pattern to match spaces). Invalid patterns are returned as errors
+// and skipped.
+func LoadFromEnv() (*Redactor, []error) {
+	rules := DefaultRules()
+	if rule, ok := KeywordRule(strings.Split(os.Getenv("LOG_REDACT_KEYWORDS"), ",")); ok {
+		rules = append(rules, rule)
+	}
+	var errs []error
+	for i, pattern := range strings.Fields(os.Getenv("LOG_REDACT_PATTERNS")) {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("LOG_REDACT_PATTERNS entry %d: %w", i+1, err))
+			continue
+		}
+		rules = append(rules, Rule{Name: fmt.Sprintf("custom%d", i+1), Pattern: compiled})
+	}
+	return New(rules), errs
+}
+
+var (
+	mu      sync.RWMutex
+	current = New(DefaultRules())
+)
+
+// SetDefault replaces the redactor used by String and Writer.
+func SetDefault(r *Redactor) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = r
+}
+
+// String redacts text with the default redactor. Log sinks call this once on their
+// way out so call sites never have to.
+func String(text string) string {
+	mu.RLock()
+	r := current
+	mu.RUnlock()
+	return r.Redact(text)
+}
+
+// Writer redacts everything written through it with the default redactor. The
+// standard logger writes a whole line per call, so matches never span writes.
+type Writer struct {
+	out io.Writer
+}
+
+// NewWriter wraps out.
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{out: out}
+}
+
+// Write redacts p before passing it on. It reports len(p) on success, since callers
+// only care that all of p was consumed.
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
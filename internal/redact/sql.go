@@ -0,0 +1,140 @@
+package redact
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// NewConnector wraps a database driver so every string argument of every write is
+// redacted on its way to the database. Open databases with sql.OpenDB on it and no
+// insert can skip redaction. Queries are passed through unchanged.
+func NewConnector(d driver.Driver, dsn string) driver.Connector {
+	return &connector{driver: d, dsn: dsn}
+}
+
+type connector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &redactingConn{Conn: conn}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// redactingConn redacts the args of statements executed directly on the connection
+// and of statements it prepares.
+type redactingConn struct {
+	driver.Conn
+}
+
+func (c *redactingConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &redactingStmt{Stmt: stmt}, nil
+}
+
+func (c *redactingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	preparer, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	stmt, err := preparer.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &redactingStmt{Stmt: stmt}, nil
+}
+
+func (c *redactingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return execer.ExecContext(ctx, query, redactArgs(args))
+}
+
+func (c *redactingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *redactingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *redactingConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// redactingStmt redacts the args of a prepared statement's executions.
+type redactingStmt struct {
+	driver.Stmt
+}
+
+func (s *redactingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	redacted := make([]driver.Value, len(args))
+	for i, arg := range args {
+		redacted[i] = redactValue(arg)
+	}
+	return s.Stmt.Exec(redacted)
+}
+
+func (s *redactingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, redactArgs(args))
+	}
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return s.Exec(values)
+}
+
+func (s *redactingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return s.Stmt.Query(values)
+}
+
+func redactArgs(args []driver.NamedValue) []driver.NamedValue {
+	redacted := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		arg.Value = redactValue(arg.Value)
+		redacted[i] = arg
+	}
+	return redacted
+}
+
+func redactValue(value driver.Value) driver.Value {
+	switch v := value.(type) {
+	case string:
+		return String(v)
+	case []byte:
+		return []byte(String(string(v)))
+	}
+	return value
+}
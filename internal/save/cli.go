@@ -0,0 +1,262 @@
+package save
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RunCLI implements `textadventure save <list|show|edit|migrate>`.
+func RunCLI(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: textadventure save <list|show|edit|migrate> [args]")
+	}
+	switch args[0] {
+	case "list":
+		return listSaves(out)
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: textadventure save show <name>")
+		}
+		return showSave(args[1], out)
+	case "edit":
+		return editSave(args[1:], out)
+	case "migrate":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: textadventure save migrate <name>")
+		}
+		return migrateSave(args[1], out)
+	default:
+		return fmt.Errorf("unknown save command %q (expected list, show, edit or migrate)", args[0])
+	}
+}
+
+func listSaves(out io.Writer) error {
+	var paths []string
+	for _, dir := range []string{Dir, BookmarkDir} {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return err
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(out, "No saves found")
+		return nil
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		snapshot, err := Read(path)
+		if err != nil {
+			fmt.Fprintf(out, "%-40s  unreadable: %v\n", path, err)
+			continue
+		}
+		fmt.Fprintf(out, "%-40s  %s  turn %-4d  %s\n",
+			path,
+			snapshot.Metadata.CreatedAt.Format("2006-01-02 15:04"),
+			snapshot.Metadata.TurnIndex,
+			snapshot.World.Player.Location,
+		)
+	}
+	return nil
+}
+
+func showSave(name string, out io.Writer) error {
+	path, err := Resolve(name)
+	if err != nil {
+		return err
+	}
+	snapshot, err := Read(path)
+	if err != nil {
+		return err
+	}
+	world := snapshot.World
+	meta := snapshot.Metadata
+
+	fmt.Fprintf(out, "Save:       %s (schema v%d)\n", path, snapshot.Version)
+	fmt.Fprintf(out, "Created:    %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(out, "Session:    %s (turn %d)\n", meta.SessionID, meta.TurnIndex)
+	if meta.BranchedFrom != "" {
+		fmt.Fprintf(out, "Branched:   %s\n", meta.BranchedFrom)
+	}
+	fmt.Fprintf(out, "Location:   %s\n", world.Player.Location)
+	fmt.Fprintf(out, "Inventory:  %s\n", joinOrNone(world.Player.Inventory))
+	fmt.Fprintf(out, "Met NPCs:   %s\n", joinOrNone(world.Player.MetNPCs))
+	fmt.Fprintf(out, "Visited:    %s\n", joinOrNone(world.Player.VisitedLocations))
+	var conditions []string
+	for _, condition := range world.Player.Conditions {
+		conditions = append(conditions, condition.Name)
+	}
+	fmt.Fprintf(out, "Conditions: %s\n", joinOrNone(conditions))
+
+	fmt.Fprintln(out, "Locations:")
+	for _, locID := range sortedKeys(world.Locations) {
+		loc := world.Locations[locID]
+		fmt.Fprintf(out, "  %-10s %-20s %d facts, exits %v\n", locID, loc.Name, len(loc.Facts), loc.Exits)
+	}
+	fmt.Fprintln(out, "NPCs:")
+	for _, npcID := range sortedKeys(world.NPCs) {
+		npc := world.NPCs[npcID]
+		fmt.Fprintf(out, "  %-10s in %s, %d facts, inventory %s\n", npcID, npc.Location, len(npc.Facts), joinOrNone(npc.Inventory))
+	}
+	if len(world.Items) > 0 {
+		fmt.Fprintln(out, "Items:")
+		for _, itemID := range sortedKeys(world.Items) {
+			item := world.Items[itemID]
+			fmt.Fprintf(out, "  %-10s in %s, %d facts\n", itemID, item.Location, len(item.Facts))
+		}
+	}
+	if len(world.ScheduledEvents) > 0 {
+		fmt.Fprintln(out, "Scheduled events:")
+		for _, event := range world.ScheduledEvents {
+			fmt.Fprintf(out, "  #%-3d in %d turns at %s: %s\n", event.ID, event.TurnsLeft, event.Location, event.Description)
+		}
+	}
+	return nil
+}
+
+// multiFlag collects a repeatable string flag.
+type multiFlag []string
+
+func (f *multiFlag) String() string     { return strings.Join(*f, ",") }
+func (f *multiFlag) Set(v string) error { *f = append(*f, v); return nil }
+
+func editSave(args []string, out io.Writer) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: textadventure save edit <name> [--set key=value] [--add-inventory item] [--remove-inventory item]")
+	}
+	name := args[0]
+	fs := flag.NewFlagSet("save edit", flag.ContinueOnError)
+	fs.SetOutput(out)
+	var sets, adds, removes multiFlag
+	fs.Var(&sets, "set", "set a field: player.location=<loc> or npcs.<id>.location=<loc> (repeatable)")
+	fs.Var(&adds, "add-inventory", "add an item ID to the player's inventory (repeatable)")
+	fs.Var(&removes, "remove-inventory", "remove an item ID from the player's inventory (repeatable)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	path, err := Resolve(name)
+	if err != nil {
+		return err
+	}
+	snapshot, err := Read(path)
+	if err != nil {
+		return err
+	}
+	Migrate(&snapshot)
+	world := snapshot.World
+
+	for _, assignment := range sets {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return fmt.Errorf("--set expects key=value, got %q", assignment)
+		}
+		parts := strings.Split(key, ".")
+		switch {
+		case key == "player.location":
+			world.Player.Location = value
+		case len(parts) == 3 && parts[0] == "npcs" && parts[2] == "location":
+			npc, exists := world.NPCs[parts[1]]
+			if !exists {
+				return fmt.Errorf("unknown NPC %q", parts[1])
+			}
+			npc.Location = value
+			world.NPCs[parts[1]] = npc
+		default:
+			return fmt.Errorf("unsupported field %q (supported: player.location, npcs.<id>.location)", key)
+		}
+	}
+	for _, itemID := range adds {
+		item, exists := world.Items[itemID]
+		if !exists {
+			return fmt.Errorf("unknown item %q", itemID)
+		}
+		if !contains(world.Player.Inventory, itemID) {
+			world.Player.Inventory = append(world.Player.Inventory, itemID)
+		}
+		item.Location = "player"
+		world.Items[itemID] = item
+	}
+	for _, itemID := range removes {
+		if !contains(world.Player.Inventory, itemID) {
+			return fmt.Errorf("inventory does not contain %q", itemID)
+		}
+		world.Player.Inventory = remove(world.Player.Inventory, itemID)
+		if item, exists := world.Items[itemID]; exists && item.Location == "player" {
+			item.Location = world.Player.Location
+			world.Items[itemID] = item
+		}
+	}
+
+	if err := Validate(world); err != nil {
+		return fmt.Errorf("refusing to write %s: %w", path, err)
+	}
+	if err := Write(path, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Updated %s\n", path)
+	return nil
+}
+
+func migrateSave(name string, out io.Writer) error {
+	path, err := Resolve(name)
+	if err != nil {
+		return err
+	}
+	snapshot, err := Read(path)
+	if err != nil {
+		return err
+	}
+	from := snapshot.Version
+	if from == 0 {
+		from = 1
+	}
+	if !Migrate(&snapshot) {
+		fmt.Fprintf(out, "%s is already at schema v%d\n", path, CurrentVersion)
+		return nil
+	}
+	if err := Write(path, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Migrated %s from schema v%d to v%d\n", path, from, CurrentVersion)
+	return nil
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,194 @@
+package save
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// testWorld is the default world with a lamp on the foyer floor and a key in the
+// player's pocket.
+func testWorld() *mcp.WorldState {
+	world := game.NewDefaultWorldState()
+	world.Inventory = []string{"key"}
+	world.Items = map[string]game.ItemInfo{
+		"lamp": {Name: "brass lamp", Location: "foyer"},
+		"key":  {Name: "iron key", Location: "player"},
+	}
+	return mcp.GameToMCPWorldState(world)
+}
+
+// writeTestSave writes testWorld as the save "slot" in a fresh directory.
+func writeTestSave(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := Write(SavePath("slot"), Snapshot{Metadata: Metadata{Name: "slot"}, World: testWorld()}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestSave(t *testing.T) *mcp.WorldState {
+	t.Helper()
+	snapshot, err := Read(SavePath("slot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot.World
+}
+
+func TestEditSave(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		check func(t *testing.T, world *mcp.WorldState)
+	}{
+		{"set player location", []string{"--set", "player.location=study"}, func(t *testing.T, world *mcp.WorldState) {
+			if world.Player.Location != "study" {
+				t.Errorf("player in %s", world.Player.Location)
+			}
+		}},
+		{"set NPC location", []string{"--set", "npcs.elena.location=kitchen"}, func(t *testing.T, world *mcp.WorldState) {
+			if world.NPCs["elena"].Location != "kitchen" {
+				t.Errorf("elena in %s", world.NPCs["elena"].Location)
+			}
+		}},
+		{"add inventory", []string{"--add-inventory", "lamp"}, func(t *testing.T, world *mcp.WorldState) {
+			if !slices.Equal(world.Player.Inventory, []string{"key", "lamp"}) || world.Items["lamp"].Location != "player" {
+				t.Errorf("inventory %q, lamp at %s", world.Player.Inventory, world.Items["lamp"].Location)
+			}
+		}},
+		{"add inventory already held", []string{"--add-inventory", "key"}, func(t *testing.T, world *mcp.WorldState) {
+			if !slices.Equal(world.Player.Inventory, []string{"key"}) {
+				t.Errorf("inventory %q", world.Player.Inventory)
+			}
+		}},
+		{"remove inventory", []string{"--remove-inventory", "key"}, func(t *testing.T, world *mcp.WorldState) {
+			if len(world.Player.Inventory) != 0 || world.Items["key"].Location != "foyer" {
+				t.Errorf("inventory %q, key at %s", world.Player.Inventory, world.Items["key"].Location)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestSave(t)
+			if err := editSave(append([]string{"slot"}, tt.args...), io.Discard); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, readTestSave(t))
+		})
+	}
+}
+
+func TestEditSaveRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no save name", []string{"--set", "player.location=study"}, "usage:"},
+		{"missing save", []string{"other"}, `no save or bookmark named "other"`},
+		{"set without value", []string{"slot", "--set", "player.location"}, "--set expects key=value"},
+		{"unsupported field", []string{"slot", "--set", "player.name=Ada"}, `unsupported field "player.name"`},
+		{"unknown NPC", []string{"slot", "--set", "npcs.marcus.location=study"}, `unknown NPC "marcus"`},
+		{"unknown item", []string{"slot", "--add-inventory", "sword"}, `unknown item "sword"`},
+		{"item not carried", []string{"slot", "--remove-inventory", "lamp"}, `inventory does not contain "lamp"`},
+		{"missing player location", []string{"slot", "--set", "player.location=cellar"}, `player is in missing location "cellar"`},
+		{"missing NPC location", []string{"slot", "--set", "npcs.elena.location=cellar"}, `NPC elena is in missing location "cellar"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestSave(t)
+			err := editSave(tt.args, io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+			if world := readTestSave(t); world.Player.Location != "foyer" || world.NPCs["elena"].Location != "library" || !slices.Equal(world.Player.Inventory, []string{"key"}) {
+				t.Error("a rejected edit was written")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(testWorld()); err != nil {
+		t.Fatalf("valid world rejected: %v", err)
+	}
+	if err := Validate(nil); err == nil {
+		t.Error("missing world accepted")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(world *mcp.WorldState)
+		want   string
+	}{
+		{"player location", func(w *mcp.WorldState) { w.Player.Location = "cellar" }, `player is in missing location "cellar"`},
+		{"NPC location", func(w *mcp.WorldState) {
+			npc := w.NPCs["elena"]
+			npc.Location = "cellar"
+			w.NPCs["elena"] = npc
+		}, `NPC elena is in missing location "cellar"`},
+		{"item location", func(w *mcp.WorldState) {
+			lamp := w.Items["lamp"]
+			lamp.Location = "cellar"
+			w.Items["lamp"] = lamp
+		}, `item lamp is in missing location "cellar"`},
+		{"unknown inventory item", func(w *mcp.WorldState) { w.Player.Inventory = append(w.Player.Inventory, "sword") }, `player inventory item "sword" does not exist`},
+		{"inventory listed twice", func(w *mcp.WorldState) { w.Player.Inventory = append(w.Player.Inventory, "key") }, "player lists item key 2 times"},
+		{"met NPC", func(w *mcp.WorldState) { w.Player.MetNPCs = []string{"marcus"} }, `met NPC "marcus" does not exist`},
+		{"visited location", func(w *mcp.WorldState) { w.Player.VisitedLocations = []string{"cellar"} }, `visited location "cellar" does not exist`},
+		{"exit", func(w *mcp.WorldState) { w.Locations["study"].Exits["down"] = "cellar" }, `exit down from study leads to missing location "cellar"`},
+		{"condition", func(w *mcp.WorldState) { w.Player.Conditions = []mcp.Condition{{Name: "haunted", Turns: 3}} }, `unknown player condition "haunted"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := testWorld()
+			tt.mutate(world)
+			err := Validate(world)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateSave(t *testing.T) {
+	t.Chdir(t.TempDir())
+	world := testWorld()
+	world.Player.MetNPCs = nil
+	data, err := json.Marshal(Snapshot{World: world})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(SavePath("old"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := migrateSave("old", &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateSave("old", &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "Migrated saves/old.json from schema v1 to v2\nsaves/old.json is already at schema v2\n"
+	if got := out.String(); got != want {
+		t.Errorf("out = %q, want %q", got, want)
+	}
+	snapshot, err := Read(SavePath("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Version != CurrentVersion || snapshot.World.Player.MetNPCs == nil {
+		t.Errorf("migrated to v%d, met NPCs %v", snapshot.Version, snapshot.World.Player.MetNPCs)
+	}
+}
@@ -0,0 +1,44 @@
+package save
+
+import "textadventure/internal/mcp"
+
+// CurrentVersion is the snapshot schema version written by this build.
+//
+// Version history:
+//   - 1: initial bookmark format (written without a version field)
+//   - 2: explicit version field; player conditions, met NPCs and items always present
+const CurrentVersion = 2
+
+// Migrate upgrades a snapshot in place to CurrentVersion. It reports whether
+// anything changed.
+func Migrate(snapshot *Snapshot) bool {
+	if snapshot.Version == 0 {
+		snapshot.Version = 1
+	}
+	if snapshot.Version >= CurrentVersion {
+		return false
+	}
+	if snapshot.Version < 2 {
+		migrateToV2(snapshot.World)
+	}
+	snapshot.Version = CurrentVersion
+	return true
+}
+
+func migrateToV2(world *mcp.WorldState) {
+	if world.Player.Inventory == nil {
+		world.Player.Inventory = []string{}
+	}
+	if world.Player.MetNPCs == nil {
+		world.Player.MetNPCs = []string{}
+	}
+	if world.Player.Conditions == nil {
+		world.Player.Conditions = []mcp.Condition{}
+	}
+	if world.Items == nil {
+		world.Items = make(map[string]mcp.Item)
+	}
+	if world.NPCs == nil {
+		world.NPCs = make(map[string]mcp.NPC)
+	}
+}
@@ -0,0 +1,184 @@
+// Package save reads and writes full world snapshots. Bookmarks use the same
+// format as saves and can be branched into new saves to replay from a point.
+package save
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"textadventure/internal/mcp"
+)
+
+const (
+	// Dir is where saves are written, relative to the project root.
+	Dir = "saves"
+	// BookmarkDir holds named bookmarks taken during play.
+	BookmarkDir = "saves/bookmarks"
+)
+
+// Metadata records where a snapshot came from so branch lineage can be traced.
+type Metadata struct {
+	Name         string    `json:"name"`
+	SessionID    string    `json:"session_id"`
+	TurnIndex    int       `json:"turn_index"`
+	CreatedAt    time.Time `json:"created_at"`
+	BranchedFrom string    `json:"branched_from,omitempty"`
+}
+
+// Snapshot is a full copy of the world plus the recent history needed to resume.
+type Snapshot struct {
+	Version  int             `json:"version"`
+	Metadata Metadata        `json:"metadata"`
+	World    *mcp.WorldState `json:"world"`
+	History  []string        `json:"history,omitempty"`
+}
+
+// ValidateName rejects names that would escape the save directories.
+func ValidateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	return nil
+}
+
+// BookmarkPath returns the file used for the named bookmark.
+func BookmarkPath(name string) string {
+	return filepath.Join(BookmarkDir, name+".json")
+}
+
+// SavePath returns the file used for the named save.
+func SavePath(name string) string {
+	return filepath.Join(Dir, name+".json")
+}
+
+// Write stores the snapshot at path, writing to a temp file first so a crash
+// never leaves a partial save behind.
+func Write(path string, snapshot Snapshot) error {
+	if snapshot.World == nil {
+		return fmt.Errorf("snapshot has no world state")
+	}
+	Migrate(&snapshot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read loads a snapshot from path.
+func Read(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	if snapshot.World == nil {
+		return Snapshot{}, fmt.Errorf("snapshot %s has no world state", path)
+	}
+	if snapshot.Version > CurrentVersion {
+		return Snapshot{}, fmt.Errorf("snapshot %s has version %d, newer than supported version %d", path, snapshot.Version, CurrentVersion)
+	}
+	return snapshot, nil
+}
+
+// Branch copies the named bookmark into a new save, recording the bookmark as the
+// save's origin. It refuses to overwrite an existing save.
+func Branch(bookmark, newSave string) (string, error) {
+	if err := ValidateName(bookmark); err != nil {
+		return "", fmt.Errorf("bookmark: %w", err)
+	}
+	if err := ValidateName(newSave); err != nil {
+		return "", fmt.Errorf("save: %w", err)
+	}
+	snapshot, err := Read(BookmarkPath(bookmark))
+	if err != nil {
+		return "", err
+	}
+	target := SavePath(newSave)
+	if _, err := os.Stat(target); err == nil {
+		return "", fmt.Errorf("save %q already exists", newSave)
+	}
+	Migrate(&snapshot)
+	snapshot.Metadata.BranchedFrom = Lineage(bookmark, snapshot.Metadata)
+	snapshot.Metadata.Name = newSave
+	snapshot.Metadata.CreatedAt = time.Now()
+	if err := Write(target, snapshot); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// Lineage describes a bookmark as a branch origin, e.g. "before-cellar@1a2b3c4d#12".
+func Lineage(bookmark string, metadata Metadata) string {
+	sessionID := metadata.SessionID
+	if len(sessionID) > 8 {
+		sessionID = sessionID[:8]
+	}
+	return fmt.Sprintf("%s@%s#%d", bookmark, sessionID, metadata.TurnIndex)
+}
+
+// Resolve maps a save name to its file, falling back to a bookmark of the same name.
+func Resolve(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	for _, path := range []string{SavePath(name), BookmarkPath(name)} {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no save or bookmark named %q", name)
+}
+
+// Origin returns the branched_from value for a session started from the snapshot at path.
+// Loading a bookmark directly branches from that bookmark; loading a save inherits the
+// save's own lineage.
+func Origin(path string, snapshot Snapshot) string {
+	if filepath.Clean(filepath.Dir(path)) == filepath.Clean(BookmarkDir) {
+		return Lineage(strings.TrimSuffix(filepath.Base(path), ".json"), snapshot.Metadata)
+	}
+	return snapshot.Metadata.BranchedFrom
+}
+
+// Latest returns the name of the most recently written save, ignoring bookmarks.
+func Latest() (string, error) {
+	paths, err := filepath.Glob(filepath.Join(Dir, "*.json"))
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	var latestTime time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestTime) {
+			latest, latestTime = path, info.ModTime()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no saves in %s", Dir)
+	}
+	return strings.TrimSuffix(filepath.Base(latest), ".json"), nil
+}
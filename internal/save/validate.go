@@ -0,0 +1,32 @@
+package save
+
+import (
+	"fmt"
+	"strings"
+
+	"textadventure/internal/game"
+	"textadventure/internal/mcp"
+)
+
+// Validate checks that a world keeps the game's invariants (game.CheckWorld) and that
+// the player only has conditions the game knows. It is run when a save is loaded at
+// startup and before an edited save is written.
+func Validate(world *mcp.WorldState) error {
+	if world == nil {
+		return fmt.Errorf("world state is missing")
+	}
+	var problems []string
+	for _, violation := range game.CheckWorld(mcp.MCPToGameWorldState(world)) {
+		problems = append(problems, violation.Detail)
+	}
+	for _, condition := range world.Player.Conditions {
+		if !game.IsKnownCondition(condition.Name) {
+			problems = append(problems, fmt.Sprintf("unknown player condition %q", condition.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid world: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
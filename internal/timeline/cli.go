@@ -0,0 +1,93 @@
+package timeline
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"textadventure/internal/artifacts"
+	"textadventure/internal/game/events"
+	"textadventure/internal/logging"
+)
+
+// RunCLI implements `textadventure timeline <session-id> [--json] [--actor id] [--location id] [--turn 3-7]`.
+// The session ID may be a prefix, as shown in the feed and debug output.
+// `textadventure timeline world <completion-id>` prints the full world state a logged
+// completion was generated against.
+func RunCLI(args []string, out io.Writer) error {
+	if len(args) > 0 && args[0] == "world" {
+		return runWorldCLI(args[1:], out)
+	}
+	fs := flag.NewFlagSet("timeline", flag.ContinueOnError)
+	fs.SetOutput(out)
+	asJSON := fs.Bool("json", false, "render the timeline as JSON")
+	actor := fs.String("actor", "", "only show events by this actor (player or an NPC id)")
+	location := fs.String("location", "", "only show events at this location")
+	turnRange := fs.String("turn", "", "only show this turn or range of turns, e.g. 3-7")
+
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		return fmt.Errorf("usage: textadventure timeline <session-id> [--json] [--actor id] [--location id] [--turn 3-7]")
+	}
+	sessionID := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	opts := Options{Actor: *actor, Location: *location}
+	if *turnRange != "" {
+		from, to, err := ParseTurnRange(*turnRange)
+		if err != nil {
+			return err
+		}
+		opts.FromTurn, opts.ToTurn = from, to
+	}
+
+	store, err := events.OpenStore(artifacts.LoadConfigFromEnv().CompletionsDB, sessionID)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	stored, err := store.SessionEvents(sessionID)
+	if err != nil {
+		return err
+	}
+	if len(stored) == 0 {
+		return fmt.Errorf("no events recorded for session %q", sessionID)
+	}
+
+	if *asJSON {
+		rendered, err := FormatJSON(stored, opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(out, rendered)
+		return err
+	}
+	_, err = io.WriteString(out, Format(stored, opts))
+	return err
+}
+
+func runWorldCLI(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: textadventure timeline world <completion-id>")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid completion id %q", args[0])
+	}
+
+	logger, err := logging.NewCompletionLogger(artifacts.LoadConfigFromEnv().CompletionsDB)
+	if err != nil {
+		return err
+	}
+	defer logger.Close()
+
+	worldState, err := logger.WorldStateAt(id)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, worldState+"\n")
+	return err
+}
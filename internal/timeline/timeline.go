@@ -0,0 +1,117 @@
+// Package timeline renders the persisted world event log as a turn-by-turn replay
+// of what happened mechanically in a session.
+package timeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"textadventure/internal/game/events"
+)
+
+// Options filters which events are rendered. Zero values match everything.
+type Options struct {
+	Actor    string
+	Location string
+	FromTurn int
+	ToTurn   int // 0 means no upper bound
+}
+
+// Turn groups the events of a single turn.
+type Turn struct {
+	SessionID string  `json:"session_id"`
+	Index     int     `json:"turn"`
+	Events    []Event `json:"events"`
+}
+
+// Event is one world event as the timeline shows it.
+type Event struct {
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor,omitempty"`
+	Location  string    `json:"location,omitempty"`
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ParseTurnRange parses "7" or "3-7" into an inclusive range.
+func ParseTurnRange(value string) (int, int, error) {
+	fromText, toText, isRange := strings.Cut(value, "-")
+	from, err := strconv.Atoi(strings.TrimSpace(fromText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid turn range %q", value)
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(toText))
+	if err != nil || to < from {
+		return 0, 0, fmt.Errorf("invalid turn range %q", value)
+	}
+	return from, to, nil
+}
+
+// Group filters stored events and groups them by turn, preserving their order. A turn
+// is kept when any of its events match the filters, and only matching events are
+// included.
+func Group(stored []events.Stored, opts Options) []Turn {
+	var turns []Turn
+	for _, event := range stored {
+		if event.TurnIndex < opts.FromTurn || (opts.ToTurn > 0 && event.TurnIndex > opts.ToTurn) {
+			continue
+		}
+		if opts.Location != "" && !strings.EqualFold(event.Location, opts.Location) {
+			continue
+		}
+		if opts.Actor != "" && !strings.EqualFold(event.Actor, opts.Actor) {
+			continue
+		}
+		if len(turns) == 0 || turns[len(turns)-1].SessionID != event.SessionID || turns[len(turns)-1].Index != event.TurnIndex {
+			turns = append(turns, Turn{SessionID: event.SessionID, Index: event.TurnIndex})
+		}
+		turn := &turns[len(turns)-1]
+		turn.Events = append(turn.Events, Event{
+			Type:      string(event.Type),
+			Actor:     event.Actor,
+			Location:  event.Location,
+			Content:   event.Content,
+			Timestamp: event.Timestamp,
+		})
+	}
+	return turns
+}
+
+// Format renders stored events as an aligned text timeline.
+func Format(stored []events.Stored, opts Options) string {
+	turns := Group(stored, opts)
+	if len(turns) == 0 {
+		return "No matching turns\n"
+	}
+
+	var b strings.Builder
+	for i, turn := range turns {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Turn %d  %s\n", turn.Index, turn.Events[0].Timestamp.Format("15:04:05"))
+		for _, event := range turn.Events {
+			fmt.Fprintf(&b, "  %-13s %-10s %-10s %s\n", event.Type, event.Actor, event.Location, event.Content)
+		}
+	}
+	return b.String()
+}
+
+// FormatJSON renders stored events grouped by turn as indented JSON.
+func FormatJSON(stored []events.Stored, opts Options) (string, error) {
+	turns := Group(stored, opts)
+	if turns == nil {
+		turns = []Turn{}
+	}
+	data, err := json.MarshalIndent(turns, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal timeline: %w", err)
+	}
+	return string(data) + "\n", nil
+}
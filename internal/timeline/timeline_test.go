@@ -0,0 +1,107 @@
+package timeline
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"textadventure/internal/game/events"
+)
+
+// fixtureRows are world_events rows as the store reads them back: two turns of one
+// session and a turn of another that shares its prefix.
+func fixtureRows() []events.Stored {
+	at := func(seconds int) time.Time { return time.Date(2026, 10, 15, 21, 4, seconds, 0, time.UTC) }
+	row := func(session string, turn int, eventType events.WorldEventType, actor, location, content string, seconds int) events.Stored {
+		return events.Stored{SessionID: session, TurnIndex: turn, WorldEvent: events.WorldEvent{Type: eventType, Actor: actor, Location: location, Content: content, Timestamp: at(seconds)}}
+	}
+	return []events.Stored{
+		row("abc-1", 1, events.EventMovement, "player", "foyer", "The player walks into the foyer", 0),
+		row("abc-1", 1, events.EventSpeech, "elena", "library", `"Is someone there?"`, 1),
+		row("abc-1", 2, events.EventItemTransfer, "player", "foyer", "The player picks up the brass lamp", 10),
+		row("abc-1", 2, events.EventScheduled, "event", "foyer", "The grandfather clock strikes midnight", 11),
+		row("abc-2", 1, events.EventAction, "elena", "library", "Elena tries the locked door", 30),
+	}
+}
+
+func TestFormat(t *testing.T) {
+	want := `Turn 1  21:04:00
+  movement      player     foyer      The player walks into the foyer
+  speak         elena      library    "Is someone there?"
+
+Turn 2  21:04:10
+  item_transfer player     foyer      The player picks up the brass lamp
+  scheduled     event      foyer      The grandfather clock strikes midnight
+
+Turn 1  21:04:30
+  action        elena      library    Elena tries the locked door
+`
+	if got := Format(fixtureRows(), Options{}); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"actor", Options{Actor: "Elena"}, `Turn 1  21:04:01
+  speak         elena      library    "Is someone there?"
+
+Turn 1  21:04:30
+  action        elena      library    Elena tries the locked door
+`},
+		{"location", Options{Location: "foyer", FromTurn: 2}, `Turn 2  21:04:10
+  item_transfer player     foyer      The player picks up the brass lamp
+  scheduled     event      foyer      The grandfather clock strikes midnight
+`},
+		{"turn range", Options{FromTurn: 2, ToTurn: 2, Actor: "event"}, `Turn 2  21:04:11
+  scheduled     event      foyer      The grandfather clock strikes midnight
+`},
+		{"nothing matches", Options{Actor: "marcus"}, "No matching turns\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(fixtureRows(), tt.opts); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	rendered, err := FormatJSON(fixtureRows(), Options{ToTurn: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var turns []Turn
+	if err := json.Unmarshal([]byte(rendered), &turns); err != nil {
+		t.Fatal(err)
+	}
+	if len(turns) != 2 || turns[0].SessionID != "abc-1" || turns[1].SessionID != "abc-2" {
+		t.Fatalf("turns = %+v", turns)
+	}
+	if first := turns[0].Events[1]; first.Type != "speak" || first.Actor != "elena" || first.Location != "library" || first.Content != `"Is someone there?"` {
+		t.Errorf("event = %+v", first)
+	}
+
+	if empty, err := FormatJSON(nil, Options{}); err != nil || empty != "[]\n" {
+		t.Errorf("empty timeline = %q, %v", empty, err)
+	}
+}
+
+func TestParseTurnRange(t *testing.T) {
+	if from, to, err := ParseTurnRange("3-7"); err != nil || from != 3 || to != 7 {
+		t.Errorf("3-7 = %d, %d, %v", from, to, err)
+	}
+	if from, to, err := ParseTurnRange("4"); err != nil || from != 4 || to != 4 {
+		t.Errorf("4 = %d, %d, %v", from, to, err)
+	}
+	for _, bad := range []string{"", "x", "7-3", "3-"} {
+		if _, _, err := ParseTurnRange(bad); err == nil {
+			t.Errorf("%q parsed", bad)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package worldgraph
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"textadventure/internal/mcp"
+	"textadventure/internal/save"
+)
+
+// RunCLI implements `textadventure graph <save> [--json]`, printing the graph of a save
+// or bookmark as DOT, or JSON with --json.
+func RunCLI(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.SetOutput(out)
+	asJSON := fs.Bool("json", false, "render the graph as JSON instead of DOT")
+
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		return fmt.Errorf("usage: textadventure graph <save> [--json]")
+	}
+	name := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	path, err := save.Resolve(name)
+	if err != nil {
+		return err
+	}
+	snapshot, err := save.Read(path)
+	if err != nil {
+		return err
+	}
+	save.Migrate(&snapshot)
+	graph := Build(mcp.MCPToGameWorldState(snapshot.World))
+
+	if *asJSON {
+		data, err := graph.JSON()
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	_, err = io.WriteString(out, graph.DOT())
+	return err
+}
@@ -0,0 +1,323 @@
+// Package worldgraph exports the world as a graph of who is where, who holds what and
+// who knows about whom, for authoring and debugging emergent NPC behavior. Graphs render
+// as JSON with a stable schema, or as Graphviz DOT.
+package worldgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"textadventure/internal/game"
+)
+
+// SchemaVersion is the version of the JSON graph format. It changes only when a node or
+// edge kind is removed or changes meaning; new kinds may appear without a bump.
+const SchemaVersion = 1
+
+// NodeKind is what a node stands for.
+type NodeKind string
+
+const (
+	NodePlayer   NodeKind = "player"
+	NodeNPC      NodeKind = "npc"
+	NodeItem     NodeKind = "item"
+	NodeLocation NodeKind = "location"
+)
+
+// EdgeKind is how two nodes are related. Edges point from the actor to what it relates to.
+type EdgeKind string
+
+const (
+	// LocatedIn links the player, an NPC or an item to the location it is in.
+	LocatedIn EdgeKind = "located_in"
+	// Holds links the player or an NPC to an item they carry, and a container to what
+	// it holds.
+	Holds EdgeKind = "holds"
+	// Met links the player to an NPC they have met.
+	Met EdgeKind = "met"
+	// KnowsAbout links the player to the locations they have visited, and an NPC to
+	// anything its memories, thoughts or actions mention. The label is what mentioned it.
+	KnowsAbout EdgeKind = "knows_about"
+	// DispositionToward links an NPC to someone it feels a certain way about, labelled
+	// with its attitude. Only NPCs' relationships with the player are recorded so far.
+	DispositionToward EdgeKind = "disposition_toward"
+)
+
+// Graph is the exported world.
+type Graph struct {
+	Version int    `json:"version"`
+	Nodes   []Node `json:"nodes"`
+	Edges   []Edge `json:"edges"`
+}
+
+// Node is the player, an NPC, an item or a location. IDs are prefixed with their kind
+// ("npc:elena", "item:brass_key"); the player is just "player".
+type Node struct {
+	ID    string   `json:"id"`
+	Kind  NodeKind `json:"kind"`
+	Label string   `json:"label"`
+}
+
+// Edge is a relation between two nodes.
+type Edge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Kind  EdgeKind `json:"kind"`
+	Label string   `json:"label,omitempty"`
+}
+
+// PlayerID is the player's node ID.
+const PlayerID = "player"
+
+// NodeID returns the node ID of an entity.
+func NodeID(kind NodeKind, id string) string {
+	if kind == NodePlayer {
+		return PlayerID
+	}
+	return string(kind) + ":" + id
+}
+
+// mentionLabelLimit caps how much of a mentioning memory an edge label repeats.
+const mentionLabelLimit = 80
+
+type builder struct {
+	nodes map[string]Node
+	edges map[edgeKey]Edge
+}
+
+// edgeKey identifies an edge regardless of its label; the first label wins.
+type edgeKey struct {
+	from, to string
+	kind     EdgeKind
+}
+
+func (b *builder) node(kind NodeKind, id, label string) string {
+	nodeID := NodeID(kind, id)
+	if _, exists := b.nodes[nodeID]; !exists || label != "" {
+		if label == "" {
+			label = id
+		}
+		b.nodes[nodeID] = Node{ID: nodeID, Kind: kind, Label: label}
+	}
+	return nodeID
+}
+
+func (b *builder) edge(from, to string, kind EdgeKind, label string) {
+	key := edgeKey{from: from, to: to, kind: kind}
+	if _, exists := b.edges[key]; !exists {
+		b.edges[key] = Edge{From: from, To: to, Kind: kind, Label: label}
+	}
+}
+
+// placeOf returns the node an item's location refers to: the player, an NPC, a
+// container item or a location.
+func (b *builder) placeOf(world game.WorldState, location string) (string, EdgeKind) {
+	if location == "player" {
+		return PlayerID, Holds
+	}
+	if _, isNPC := world.NPCs[location]; isNPC {
+		return b.node(NodeNPC, location, ""), Holds
+	}
+	if _, isItem := world.Items[location]; isItem {
+		return b.node(NodeItem, location, ""), Holds
+	}
+	return b.node(NodeLocation, location, ""), LocatedIn
+}
+
+// Build collects the graph of world. The same world always produces the same graph.
+func Build(world game.WorldState) Graph {
+	b := &builder{nodes: make(map[string]Node), edges: make(map[edgeKey]Edge)}
+
+	for id, location := range world.Locations {
+		b.node(NodeLocation, id, location.Name)
+	}
+	for id, item := range world.Items {
+		b.node(NodeItem, id, item.Name)
+	}
+	for id := range world.NPCs {
+		b.node(NodeNPC, id, "")
+	}
+	b.node(NodePlayer, "", PlayerID)
+
+	if world.Location != "" {
+		b.edge(PlayerID, b.node(NodeLocation, world.Location, ""), LocatedIn, "")
+	}
+	for _, itemID := range world.Inventory {
+		b.edge(PlayerID, b.node(NodeItem, itemID, ""), Holds, "")
+	}
+	for _, npcID := range world.MetNPCs {
+		b.edge(PlayerID, b.node(NodeNPC, npcID, ""), Met, "")
+	}
+	for _, locationID := range world.VisitedLocations {
+		b.edge(PlayerID, b.node(NodeLocation, locationID, ""), KnowsAbout, "visited")
+	}
+
+	for id, npc := range world.NPCs {
+		npcNode := NodeID(NodeNPC, id)
+		if npc.Location != "" {
+			b.edge(npcNode, b.node(NodeLocation, npc.Location, ""), LocatedIn, "")
+		}
+		for _, itemID := range npc.Inventory {
+			b.edge(npcNode, b.node(NodeItem, itemID, ""), Holds, "")
+		}
+		if !npc.Relationship.IsZero() {
+			b.edge(npcNode, PlayerID, DispositionToward, game.Attitude(npc.Relationship.Affinity))
+		}
+	}
+	for id, item := range world.Items {
+		if item.Location == "" {
+			continue
+		}
+		place, kind := b.placeOf(world, item.Location)
+		if kind == Holds {
+			b.edge(place, NodeID(NodeItem, id), Holds, "")
+		} else {
+			b.edge(NodeID(NodeItem, id), place, LocatedIn, "")
+		}
+	}
+
+	mentions := mentionPatterns(world)
+	for id, npc := range world.NPCs {
+		npcNode := NodeID(NodeNPC, id)
+		for _, source := range npcKnowledge(npc) {
+			for _, mention := range mentions {
+				if mention.node != npcNode && mention.pattern.MatchString(source) {
+					b.edge(npcNode, mention.node, KnowsAbout, truncate(source, mentionLabelLimit))
+				}
+			}
+		}
+	}
+
+	return b.graph()
+}
+
+func (b *builder) graph() Graph {
+	graph := Graph{Version: SchemaVersion, Nodes: make([]Node, 0, len(b.nodes)), Edges: make([]Edge, 0, len(b.edges))}
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, edge := range b.edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Kind != graph.Nodes[j].Kind {
+			return kindOrder[graph.Nodes[i].Kind] < kindOrder[graph.Nodes[j].Kind]
+		}
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.To < b.To
+	})
+	return graph
+}
+
+var kindOrder = map[NodeKind]int{NodePlayer: 0, NodeLocation: 1, NodeNPC: 2, NodeItem: 3}
+
+// npcKnowledge is what an NPC remembers, thought and did, oldest first.
+func npcKnowledge(npc game.NPCInfo) []string {
+	sources := append([]string(nil), npc.Memories...)
+	for _, entry := range npc.RecentThoughts {
+		sources = append(sources, entry.Text)
+	}
+	for _, entry := range npc.RecentActions {
+		sources = append(sources, entry.Text)
+	}
+	return sources
+}
+
+type mention struct {
+	node    string
+	pattern *regexp.Regexp
+}
+
+// mentionPatterns match an entity by its ID (underscores read as spaces) or its name,
+// as whole words in any case.
+func mentionPatterns(world game.WorldState) []mention {
+	var mentions []mention
+	add := func(node string, terms ...string) {
+		var alternatives []string
+		for _, term := range terms {
+			term = strings.TrimSpace(strings.ReplaceAll(term, "_", " "))
+			if term != "" {
+				alternatives = append(alternatives, regexp.QuoteMeta(term))
+			}
+		}
+		if len(alternatives) == 0 {
+			return
+		}
+		mentions = append(mentions, mention{
+			node:    node,
+			pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(alternatives, "|") + `)\b`),
+		})
+	}
+	for id := range world.NPCs {
+		add(NodeID(NodeNPC, id), id)
+	}
+	for id, item := range world.Items {
+		add(NodeID(NodeItem, id), id, item.Name)
+	}
+	for id, location := range world.Locations {
+		add(NodeID(NodeLocation, id), id, location.Name)
+	}
+	sort.Slice(mentions, func(i, j int) bool { return mentions[i].node < mentions[j].node })
+	return mentions
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// JSON renders the graph as indented JSON.
+func (g Graph) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode graph: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+var nodeShapes = map[NodeKind]string{
+	NodePlayer:   "doublecircle",
+	NodeNPC:      "ellipse",
+	NodeItem:     "box",
+	NodeLocation: "house",
+}
+
+// DOT renders the graph in Graphviz DOT, e.g. for `dot -Tsvg`.
+func (g Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph world {\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=%s];\n", dotQuote(node.ID), dotQuote(node.Label), nodeShapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		label := string(edge.Kind)
+		if edge.Label != "" {
+			label += ": " + edge.Label
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(label))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func dotQuote(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	text = strings.ReplaceAll(text, "\n", `\n`)
+	return `"` + text + `"`
+}
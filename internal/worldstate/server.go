@@ -0,0 +1,302 @@
+package worldstate
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type noArgs struct{}
+
+type restoreArgs struct {
+	State string `json:"state" jsonschema:"JSON string of a full world state, as returned by get_world_state"`
+}
+
+type movePlayerArgs struct {
+	Location string `json:"location" jsonschema:"The location ID to move the player to"`
+}
+
+type moveNPCArgs struct {
+	NPCID    string `json:"npc_id" jsonschema:"The NPC ID to move"`
+	Location string `json:"location" jsonschema:"The location ID to move the NPC to"`
+}
+
+type transferItemArgs struct {
+	Item         string `json:"item" jsonschema:"The item ID to transfer"`
+	FromLocation string `json:"from_location" jsonschema:"Source location ID, NPC ID or player"`
+	ToLocation   string `json:"to_location" jsonschema:"Destination location ID, NPC ID or player"`
+}
+
+type putInContainerArgs struct {
+	Item      string `json:"item" jsonschema:"The item ID to put away"`
+	Container string `json:"container" jsonschema:"The container item ID"`
+}
+
+type takeFromContainerArgs struct {
+	Item       string `json:"item" jsonschema:"The item ID to take out"`
+	Container  string `json:"container" jsonschema:"The container item ID"`
+	ToLocation string `json:"to_location" jsonschema:"Destination location ID, NPC ID or player"`
+}
+
+type containerArgs struct {
+	Container string `json:"container" jsonschema:"The container item ID"`
+}
+
+type itemArgs struct {
+	Item string `json:"item" jsonschema:"The item ID"`
+}
+
+type unlockDoorArgs struct {
+	Location  string `json:"location" jsonschema:"The location where the door is"`
+	Direction string `json:"direction" jsonschema:"The direction of the door"`
+	KeyItem   string `json:"key_item" jsonschema:"The key item to use"`
+}
+
+type updateNPCMemoryArgs struct {
+	NPCID   string `json:"npc_id" jsonschema:"The NPC whose memory to update"`
+	Thought string `json:"thought,omitempty" jsonschema:"The NPC's latest thought"`
+	Action  string `json:"action,omitempty" jsonschema:"The NPC's latest action"`
+	Turn    int    `json:"turn,omitempty" jsonschema:"The turn index these happened on (0 if unknown)"`
+}
+
+type configureNPCArgs struct {
+	NPCID        string `json:"npc_id" jsonschema:"The NPC ID to configure"`
+	Personality  string `json:"personality,omitempty" jsonschema:"Brief personality description"`
+	Backstory    string `json:"backstory,omitempty" jsonschema:"Background story explaining who they are"`
+	CoreMemories string `json:"core_memories,omitempty" jsonschema:"Comma-separated list of important memories"`
+}
+
+type npcArgs struct {
+	NPCID string `json:"npc_id" jsonschema:"The NPC ID"`
+}
+
+type recordDialogueArgs struct {
+	NPCID        string            `json:"npc_id" jsonschema:"The NPC who said the line"`
+	NodeID       string            `json:"node_id" jsonschema:"The ID of the dialogue node said"`
+	QuestUpdates map[string]string `json:"quest_updates,omitempty" jsonschema:"New quest statuses keyed by quest ID"`
+}
+
+type npcGoalArgs struct {
+	NPCID  string `json:"npc_id" jsonschema:"The NPC whose goals change"`
+	Action string `json:"action" jsonschema:"add or complete"`
+	Goal   string `json:"goal" jsonschema:"The goal, in a short phrase"`
+}
+
+type npcEmotionArgs struct {
+	NPCID   string  `json:"npc_id" jsonschema:"The NPC whose feelings change"`
+	Emotion string  `json:"emotion" jsonschema:"One of fear, trust, curiosity"`
+	Delta   float64 `json:"delta" jsonschema:"How much the emotion changes, from -1 to 1"`
+	Cause   string  `json:"cause,omitempty" jsonschema:"What the NPC perceived that changed it"`
+}
+
+type syncEmotionsArgs struct {
+	NPCID    string             `json:"npc_id" jsonschema:"The NPC whose emotions are replaced"`
+	Emotions map[string]float64 `json:"emotions" jsonschema:"Intensities from 0 to 1 keyed by emotion"`
+}
+
+type relationshipArgs struct {
+	NPCID       string `json:"npc_id" jsonschema:"The NPC whose regard for the player changes"`
+	Delta       int    `json:"delta" jsonschema:"How much affinity changes, from -3 to 3"`
+	Interaction string `json:"interaction" jsonschema:"What the player did, in a short phrase"`
+}
+
+type playerConditionArgs struct {
+	Action    string `json:"action" jsonschema:"add or remove"`
+	Condition string `json:"condition" jsonschema:"One of injured, exhausted, soaked, cold"`
+}
+
+type conditionArg struct {
+	Name  string `json:"name"`
+	Turns int    `json:"turns,omitempty"`
+}
+
+type syncConditionsArgs struct {
+	Conditions []conditionArg `json:"conditions"`
+}
+
+type scheduleEventArgs struct {
+	DelayTurns  int              `json:"delay_turns" jsonschema:"Turns until the event fires (1 to 20)"`
+	Description string           `json:"description" jsonschema:"What happens when it fires"`
+	Mutations   []map[string]any `json:"mutations" jsonschema:"List of tool calls ({tool, args}) to run when it fires"`
+	Location    string           `json:"location,omitempty" jsonschema:"Where the event happens"`
+}
+
+type syncEventsArgs struct {
+	Events []map[string]any `json:"events" jsonschema:"Scheduled events as returned in the world state"`
+}
+
+type createItemArgs struct {
+	ItemID       string   `json:"item_id" jsonschema:"Unique identifier for the item"`
+	Name         string   `json:"name" jsonschema:"Human-readable name"`
+	Location     string   `json:"location" jsonschema:"Where the item is: a location ID, player or an NPC ID"`
+	InitialFacts []string `json:"initial_facts,omitempty" jsonschema:"Initial facts about the item"`
+}
+
+type createNPCArgs struct {
+	NPCID        string   `json:"npc_id" jsonschema:"Unique identifier for the NPC"`
+	Name         string   `json:"name" jsonschema:"Human-readable name"`
+	Location     string   `json:"location" jsonschema:"Location where the NPC starts"`
+	InitialFacts []string `json:"initial_facts,omitempty" jsonschema:"Initial facts about the NPC"`
+}
+
+type createLocationArgs struct {
+	LocationID string            `json:"location_id" jsonschema:"Unique identifier for the location"`
+	Name       string            `json:"name" jsonschema:"Human-readable name"`
+	Exits      map[string]string `json:"exits,omitempty" jsonschema:"Exits as direction to location ID"`
+	Outdoors   bool              `json:"outdoors,omitempty" jsonschema:"Whether the location is open to the weather; being soaked or cold only wears off indoors"`
+}
+
+type locationAmbienceArgs struct {
+	LocationID string `json:"location_id" jsonschema:"The location ID"`
+	Ambience   string `json:"ambience" jsonschema:"The room's ongoing background, or empty to clear it"`
+}
+
+type ambienceHeardArgs struct {
+	NPCID      string `json:"npc_id" jsonschema:"The NPC who perceived the ambience"`
+	LocationID string `json:"location_id" jsonschema:"Where the NPC perceived it"`
+	Ambience   string `json:"ambience" jsonschema:"The ambience the NPC perceived"`
+}
+
+type locationFactsArgs struct {
+	LocationID string   `json:"location_id" jsonschema:"The location to add facts to"`
+	NewFacts   []string `json:"new_facts" jsonschema:"Facts to add"`
+}
+
+type itemFactsArgs struct {
+	ItemID   string   `json:"item_id" jsonschema:"The item to add facts to"`
+	NewFacts []string `json:"new_facts" jsonschema:"Facts to add"`
+}
+
+type npcFactsArgs struct {
+	NPCID    string   `json:"npc_id" jsonschema:"The NPC to add facts to"`
+	NewFacts []string `json:"new_facts" jsonschema:"Facts to add"`
+}
+
+// NewServer creates an MCP server with the world-state tools, backed by store.
+func NewServer(store *Store) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "Text Adventure World State", Version: "v1.0.0"}, nil)
+
+	addTool(server, store, "get_world_state", "Get the current world state for context.",
+		func(state world, args noArgs) (string, bool) { return getWorldState(state) })
+	addTool(server, store, "restore_world_state", "Replace the entire world state with a saved snapshot.",
+		func(state world, args restoreArgs) (string, bool) { return restoreWorldState(state, args.State) })
+	addTool(server, store, "move_player", "Move the player to a different location.",
+		func(state world, args movePlayerArgs) (string, bool) { return movePlayer(state, args.Location) })
+	addTool(server, store, "move_npc", "Move an NPC to a different location.",
+		func(state world, args moveNPCArgs) (string, bool) { return moveNPC(state, args.NPCID, args.Location) })
+	addTool(server, store, "transfer_item", "Transfer an item from one location to another.",
+		func(state world, args transferItemArgs) (string, bool) {
+			return transferItem(state, args.Item, args.FromLocation, args.ToLocation)
+		})
+	addTool(server, store, "put_item_in_container", "Put an item, wherever it is, into a container item.",
+		func(state world, args putInContainerArgs) (string, bool) {
+			return putItemInContainer(state, args.Item, args.Container)
+		})
+	addTool(server, store, "take_item_from_container", "Take an item out of a container item.",
+		func(state world, args takeFromContainerArgs) (string, bool) {
+			return takeItemFromContainer(state, args.Item, args.Container, args.ToLocation)
+		})
+	addTool(server, store, "open_container", "Open or look inside a container item, revealing its contents to the player.",
+		func(state world, args containerArgs) (string, bool) { return openContainer(state, args.Container) })
+	addTool(server, store, "add_to_inventory", "Add an item to the player's inventory from their current location.",
+		func(state world, args itemArgs) (string, bool) { return addToInventory(state, args.Item) })
+	addTool(server, store, "remove_from_inventory", "Remove an item from the player's inventory to their current location.",
+		func(state world, args itemArgs) (string, bool) { return removeFromInventory(state, args.Item) })
+	addTool(server, store, "unlock_door", "Unlock a door using a key from the player's inventory.",
+		func(state world, args unlockDoorArgs) (string, bool) {
+			return unlockDoor(state, args.Location, args.Direction, args.KeyItem)
+		})
+	addTool(server, store, "update_npc_memory", "Record an NPC's latest thought and/or action.",
+		func(state world, args updateNPCMemoryArgs) (string, bool) {
+			return updateNPCMemory(state, args.NPCID, args.Thought, args.Action, args.Turn)
+		})
+	addTool(server, store, "configure_npc", "Configure an NPC's personality, backstory, and core memories.",
+		func(state world, args configureNPCArgs) (string, bool) {
+			return configureNPC(state, args.NPCID, args.Personality, args.Backstory, args.CoreMemories)
+		})
+	addTool(server, store, "mark_npc_as_met", "Mark an NPC as met by the player (for narrative purposes).",
+		func(state world, args npcArgs) (string, bool) { return markNPCAsMet(state, args.NPCID) })
+	addTool(server, store, "record_dialogue", "Record that an NPC said one of its authored dialogue lines, and apply its quest updates.",
+		func(state world, args recordDialogueArgs) (string, bool) {
+			return recordDialogue(state, args.NPCID, args.NodeID, args.QuestUpdates)
+		})
+	addTool(server, store, "set_npc_goal", "Add a goal an NPC pursues, or mark one complete.",
+		func(state world, args npcGoalArgs) (string, bool) {
+			return setNPCGoal(state, args.NPCID, args.Action, args.Goal)
+		})
+	addTool(server, store, "adjust_npc_emotion", "Raise or lower one of an NPC's emotions (fear, trust, curiosity).",
+		func(state world, args npcEmotionArgs) (string, bool) {
+			return adjustNPCEmotion(state, args.NPCID, args.Emotion, args.Delta)
+		})
+	addTool(server, store, "sync_npc_emotions", "Replace an NPC's emotions, used by the game after per-turn decay.",
+		func(state world, args syncEmotionsArgs) (string, bool) {
+			return syncNPCEmotions(state, args.NPCID, args.Emotions)
+		})
+	addTool(server, store, "update_relationship", "Change how an NPC regards the player after a notable interaction.",
+		func(state world, args relationshipArgs) (string, bool) {
+			return updateRelationship(state, args.NPCID, args.Delta, args.Interaction)
+		})
+	addTool(server, store, "set_player_condition", "Add or remove a physical condition on the player.",
+		func(state world, args playerConditionArgs) (string, bool) {
+			return setPlayerCondition(state, args.Action, args.Condition)
+		})
+	addTool(server, store, "sync_player_conditions", "Replace the player's conditions, used by the game after per-turn decay.",
+		func(state world, args syncConditionsArgs) (string, bool) {
+			return syncPlayerConditions(state, args.Conditions)
+		})
+	addTool(server, store, "schedule_event", "Schedule world changes to happen a number of turns from now.",
+		func(state world, args scheduleEventArgs) (string, bool) {
+			return scheduleEvent(state, args.DelayTurns, args.Description, args.Mutations, args.Location)
+		})
+	addTool(server, store, "sync_scheduled_events", "Replace the pending scheduled events, used by the game after each turn's countdown.",
+		func(state world, args syncEventsArgs) (string, bool) { return syncScheduledEvents(state, args.Events) })
+	addTool(server, store, "create_item", "Create a new item in the world.",
+		func(state world, args createItemArgs) (string, bool) {
+			return createItem(state, args.ItemID, args.Name, args.Location, args.InitialFacts)
+		})
+	addTool(server, store, "create_npc", "Create a new NPC in the world.",
+		func(state world, args createNPCArgs) (string, bool) {
+			return createNPC(state, args.NPCID, args.Name, args.Location, args.InitialFacts)
+		})
+	addTool(server, store, "create_location", "Create a new location in the world.",
+		func(state world, args createLocationArgs) (string, bool) {
+			return createLocation(state, args.LocationID, args.Name, args.Exits, args.Outdoors)
+		})
+	addTool(server, store, "set_location_ambience", "Set or clear a location's ongoing background ambience.",
+		func(state world, args locationAmbienceArgs) (string, bool) {
+			return setLocationAmbience(state, args.LocationID, args.Ambience)
+		})
+	addTool(server, store, "mark_ambience_heard", "Record that an NPC has perceived a location's ambience, used by the game so it is perceived once.",
+		func(state world, args ambienceHeardArgs) (string, bool) {
+			return markAmbienceHeard(state, args.NPCID, args.LocationID, args.Ambience)
+		})
+	addTool(server, store, "add_location_facts", "Add facts to a location.",
+		func(state world, args locationFactsArgs) (string, bool) {
+			return addLocationFacts(state, args.LocationID, args.NewFacts)
+		})
+	addTool(server, store, "add_item_facts", "Add facts to an item.",
+		func(state world, args itemFactsArgs) (string, bool) {
+			return addItemFacts(state, args.ItemID, args.NewFacts)
+		})
+	addTool(server, store, "add_npc_facts", "Add facts to an NPC.",
+		func(state world, args npcFactsArgs) (string, bool) {
+			return addNPCFacts(state, args.NPCID, args.NewFacts)
+		})
+
+	return server
+}
+
+// addTool registers a tool whose result is the text message run returns. The world is
+// saved when run reports a change.
+func addTool[In any](server *mcp.Server, store *Store, name, description string, run func(state world, args In) (string, bool)) {
+	mcp.AddTool(server, &mcp.Tool{Name: name, Description: description},
+		func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[In]) (*mcp.CallToolResultFor[any], error) {
+			message, err := store.update(func(state world) (string, bool) {
+				return run(state, params.Arguments)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResultFor[any]{Content: []mcp.Content{&mcp.TextContent{Text: message}}}, nil
+		})
+}
@@ -0,0 +1,303 @@
+package worldstate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// testWorldJSON has the player in the foyer with a key to the locked study door. The
+// foyer has a coin, the study a chest holding a ring, and the hall a locked safe and
+// elena, who carries a letter.
+const testWorldJSON = `{
+  "player": {"location": "foyer", "inventory": ["key"], "met_npcs": [], "visited_locations": ["foyer"],
+    "conditions": [{"name": "cold", "turns": 2}]},
+  "locations": {
+    "foyer": {"name": "Foyer", "facts": [], "items": ["coin"], "exits": {"north": "study", "east": "hall"},
+      "door_states": {"north": {"locked": true, "description": "oak door"}}},
+    "study": {"name": "Study", "facts": [], "items": ["chest"], "exits": {"south": "foyer"}, "door_states": {}},
+    "hall": {"name": "Hall", "facts": [], "items": ["safe"], "exits": {"west": "foyer"}, "door_states": {}}
+  },
+  "items": {
+    "key": {"name": "key", "facts": [], "location": "player", "can_unlock": ["foyer_north"]},
+    "coin": {"name": "coin", "facts": [], "location": "foyer", "can_unlock": []},
+    "chest": {"name": "chest", "facts": [], "location": "study", "can_unlock": [], "is_container": true, "contains": ["ring"]},
+    "ring": {"name": "ring", "facts": [], "location": "chest", "can_unlock": []},
+    "safe": {"name": "safe", "facts": [], "location": "hall", "can_unlock": [], "is_container": true, "locked": true},
+    "letter": {"name": "letter", "facts": [], "location": "elena", "can_unlock": []}
+  },
+  "npcs": {
+    "elena": {"name": "Elena", "location": "hall", "inventory": ["letter"], "facts": [], "recent_thoughts": [], "recent_actions": []}
+  },
+  "quests": [{"id": "escape", "title": "Escape", "status": "active"}]
+}`
+
+// connect serves store's tools in process and returns a session to call them through.
+func connect(t *testing.T, store *Store) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := NewServer(store).Connect(ctx, serverTransport); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func callText(t *testing.T, session *mcp.ClientSession, tool string, args map[string]any) string {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: tool, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: %v", tool, err)
+	}
+	if result.IsError || len(result.Content) != 1 {
+		t.Fatalf("%s: tool failed: %+v", tool, result.Content)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func testStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "world_state.json")
+	if err := os.WriteFile(path, []byte(testWorldJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(path), path
+}
+
+func TestTools(t *testing.T) {
+	tests := []struct {
+		tool    string
+		args    map[string]any
+		want    string
+		changes bool
+	}{
+		{"move_player", map[string]any{"location": "hall"}, "Player moved from foyer to hall", true},
+		{"move_player", map[string]any{"location": "study"}, "Error: The oak door is locked", false},
+		{"move_player", map[string]any{"location": "attic"}, "Error: Location 'attic' does not exist", false},
+		{"move_npc", map[string]any{"npc_id": "elena", "location": "foyer"}, "NPC elena moved from hall to foyer", true},
+		{"move_npc", map[string]any{"npc_id": "elena", "location": "study"}, "Error: Cannot move directly from hall to study", false},
+		{"move_npc", map[string]any{"npc_id": "ghost", "location": "foyer"}, "Error: NPC 'ghost' does not exist", false},
+		{"transfer_item", map[string]any{"item": "letter", "from_location": "elena", "to_location": "player"}, "Item 'letter' transferred from elena to player", true},
+		{"transfer_item", map[string]any{"item": "coin", "from_location": "hall", "to_location": "player"}, "Error: Item 'coin' not in location 'hall'", false},
+		{"put_item_in_container", map[string]any{"item": "key", "container": "chest"}, "Put key in chest", true},
+		{"put_item_in_container", map[string]any{"item": "coin", "container": "safe"}, "Error: safe is locked", false},
+		{"put_item_in_container", map[string]any{"item": "chest", "container": "chest"}, "Error: chest cannot go inside chest", false},
+		{"take_item_from_container", map[string]any{"item": "ring", "container": "chest", "to_location": "player"}, "Took ring from chest to player", true},
+		{"take_item_from_container", map[string]any{"item": "coin", "container": "chest", "to_location": "player"}, "Error: Item 'coin' not in chest", false},
+		{"open_container", map[string]any{"container": "chest"}, "Opened chest: it holds ring", true},
+		{"open_container", map[string]any{"container": "ring"}, "Error: ring is not a container", false},
+		{"add_to_inventory", map[string]any{"item": "coin"}, "Player picked up coin", true},
+		{"add_to_inventory", map[string]any{"item": "chest"}, "Error: Item 'chest' is not available in foyer", false},
+		{"remove_from_inventory", map[string]any{"item": "key"}, "Player dropped key in foyer", true},
+		{"remove_from_inventory", map[string]any{"item": "coin"}, "Error: Item 'coin' is not in inventory", false},
+		{"unlock_door", map[string]any{"location": "foyer", "direction": "north", "key_item": "key"}, "Door to the north in foyer has been unlocked with key", true},
+		{"unlock_door", map[string]any{"location": "foyer", "direction": "north", "key_item": "coin"}, "Error: Player does not have coin", false},
+		{"unlock_door", map[string]any{"location": "foyer", "direction": "east", "key_item": "key"}, "Error: No door to the east in foyer", false},
+		{"update_npc_memory", map[string]any{"npc_id": "elena", "thought": "who is that?", "action": "steps back", "turn": 3}, "Updated elena memory - thought: 'who is that?', action: 'steps back'", true},
+		{"update_npc_memory", map[string]any{"npc_id": "ghost", "thought": "boo"}, "Error: NPC 'ghost' does not exist", false},
+		{"configure_npc", map[string]any{"npc_id": "elena", "personality": "wry", "core_memories": "a fire, a name"}, "Updated elena: personality, core memories", true},
+		{"configure_npc", map[string]any{"npc_id": "elena"}, "No configuration changes provided for elena", false},
+		{"configure_npc", map[string]any{"npc_id": "ghost", "personality": "wry"}, "Error: NPC 'ghost' does not exist", false},
+		{"mark_npc_as_met", map[string]any{"npc_id": "elena"}, "Player has now met elena", true},
+		{"mark_npc_as_met", map[string]any{"npc_id": "ghost"}, "Error: NPC 'ghost' does not exist", false},
+		{"record_dialogue", map[string]any{"npc_id": "elena", "node_id": "greeting", "quest_updates": map[string]any{"escape": "done"}}, "elena said greeting", true},
+		{"record_dialogue", map[string]any{"npc_id": "elena", "node_id": "greeting", "quest_updates": map[string]any{"heist": "done"}}, "Error: Quest 'heist' does not exist", false},
+		{"set_npc_goal", map[string]any{"npc_id": "elena", "action": "add", "goal": "find a way out"}, "elena now has the goal: find a way out", true},
+		{"set_npc_goal", map[string]any{"npc_id": "elena", "action": "complete", "goal": "find a way out"}, "Error: elena has no goal 'find a way out'", false},
+		{"set_npc_goal", map[string]any{"npc_id": "elena", "action": "abandon", "goal": "hide"}, "Error: action must be 'add' or 'complete', got 'abandon'", false},
+		{"adjust_npc_emotion", map[string]any{"npc_id": "elena", "emotion": "fear", "delta": 0.4}, "elena fear: 0.00 -> 0.40", true},
+		{"adjust_npc_emotion", map[string]any{"npc_id": "elena", "emotion": "joy", "delta": 0.4}, "Error: Unknown emotion 'joy'", false},
+		{"sync_npc_emotions", map[string]any{"npc_id": "elena", "emotions": map[string]any{"trust": 1.5}}, "elena emotions: 1", true},
+		{"sync_npc_emotions", map[string]any{"npc_id": "elena", "emotions": map[string]any{"joy": 0.1}}, "Error: Unknown emotion 'joy'", false},
+		{"update_relationship", map[string]any{"npc_id": "elena", "delta": 2, "interaction": "returned the letter"}, "elena affinity for the player: 0 -> 2 (returned the letter)", true},
+		{"update_relationship", map[string]any{"npc_id": "elena", "delta": 5, "interaction": "saved her life"}, "Error: delta must be between -3 and 3", false},
+		{"set_player_condition", map[string]any{"action": "add", "condition": "soaked"}, "Player is now soaked", true},
+		{"set_player_condition", map[string]any{"action": "remove", "condition": "injured"}, "Player is not injured", false},
+		{"set_player_condition", map[string]any{"action": "add", "condition": "cursed"}, "Error: Unknown condition 'cursed'", false},
+		{"sync_player_conditions", map[string]any{"conditions": []any{map[string]any{"name": "cold", "turns": 3}}}, "Player conditions: cold", true},
+		{"sync_player_conditions", map[string]any{"conditions": []any{map[string]any{"name": "cursed"}}}, "Error: Unknown condition 'cursed'", false},
+		{"schedule_event", map[string]any{"delay_turns": 2, "description": "the bell tolls", "mutations": []any{}}, "Scheduled event 1 in 2 turns: the bell tolls", true},
+		{"schedule_event", map[string]any{"delay_turns": 0, "description": "the bell tolls", "mutations": []any{}}, "Error: delay_turns must be between 1 and 20", false},
+		{"sync_scheduled_events", map[string]any{"events": []any{}}, "0 scheduled events pending", true},
+		{"create_item", map[string]any{"item_id": "lamp", "name": "brass lamp", "location": "hall"}, "Created item 'brass lamp' (lamp) at hall", true},
+		{"create_item", map[string]any{"item_id": "coin", "name": "coin", "location": "hall"}, "Error: Item 'coin' already exists", false},
+		{"create_npc", map[string]any{"npc_id": "marcus", "name": "Marcus", "location": "study"}, "Created NPC 'Marcus' (marcus) at study", true},
+		{"create_npc", map[string]any{"npc_id": "marcus", "name": "Marcus", "location": "attic"}, "Error: Location 'attic' does not exist", false},
+		{"create_location", map[string]any{"location_id": "attic", "name": "Attic", "exits": map[string]any{"down": "study"}}, "Created location 'Attic' (attic)", true},
+		{"create_location", map[string]any{"location_id": "foyer", "name": "Foyer"}, "Error: Location 'foyer' already exists", false},
+		{"set_location_ambience", map[string]any{"location_id": "foyer", "ambience": "rain on the windows"}, "Ambience of foyer: rain on the windows", true},
+		{"set_location_ambience", map[string]any{"location_id": "attic", "ambience": "wind"}, "Error: Location 'attic' does not exist", false},
+		{"mark_ambience_heard", map[string]any{"npc_id": "elena", "location_id": "foyer", "ambience": "rain"}, "elena has heard the ambience of foyer", true},
+		{"mark_ambience_heard", map[string]any{"npc_id": "elena", "location_id": "attic", "ambience": "wind"}, "Error: Location 'attic' does not exist", false},
+		{"add_location_facts", map[string]any{"location_id": "foyer", "new_facts": []any{"the floor is marble", "it's draughty"}}, `Added 2 facts to foyer: ['the floor is marble', "it's draughty"]`, true},
+		{"add_location_facts", map[string]any{"location_id": "attic", "new_facts": []any{"dusty"}}, "Error: Location 'attic' does not exist", false},
+		{"add_item_facts", map[string]any{"item_id": "coin", "new_facts": []any{"worn smooth"}}, "Added 1 facts to coin: ['worn smooth']", true},
+		{"add_item_facts", map[string]any{"item_id": "lamp", "new_facts": []any{"dented"}}, "Error: Item 'lamp' does not exist", false},
+		{"add_npc_facts", map[string]any{"npc_id": "elena", "new_facts": []any{"has a scar"}}, "Added 1 facts to elena: ['has a scar']", true},
+		{"add_npc_facts", map[string]any{"npc_id": "ghost", "new_facts": []any{"transparent"}}, "Error: NPC 'ghost' does not exist", false},
+	}
+
+	covered := map[string]bool{"get_world_state": true, "restore_world_state": true}
+	for _, tt := range tests {
+		covered[tt.tool] = true
+		store, path := testStore(t)
+		session := connect(t, store)
+		if got := callText(t, session, tt.tool, tt.args); got != tt.want {
+			t.Errorf("%s %v = %q, want %q", tt.tool, tt.args, got, tt.want)
+		}
+		saved, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed := !bytes.Equal(saved, []byte(testWorldJSON)); changed != tt.changes {
+			t.Errorf("%s %v: world changed = %v, want %v", tt.tool, tt.args, changed, tt.changes)
+		}
+	}
+
+	// Every tool the server offers has a row above
+	tools, err := connect(t, NewMemoryStore(nil)).ListTools(context.Background(), &mcp.ListToolsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools.Tools {
+		if !covered[tool.Name] {
+			t.Errorf("%s has no test", tool.Name)
+		}
+	}
+}
+
+// A successful call leaves the world as the next call sees it, as the Python server's.
+func TestToolEffects(t *testing.T) {
+	store, _ := testStore(t)
+	session := connect(t, store)
+	for _, step := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"unlock_door", map[string]any{"location": "foyer", "direction": "north", "key_item": "key"}},
+		{"move_player", map[string]any{"location": "study"}},
+		{"take_item_from_container", map[string]any{"item": "ring", "container": "chest", "to_location": "player"}},
+		{"remove_from_inventory", map[string]any{"item": "key"}},
+		{"create_location", map[string]any{"location_id": "garden", "name": "Garden", "exits": map[string]any{"in": "foyer"}, "outdoors": true}},
+	} {
+		if got := callText(t, session, step.tool, step.args); strings.HasPrefix(got, "Error:") {
+			t.Fatalf("%s: %s", step.tool, got)
+		}
+	}
+
+	state, err := store.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := state.player()
+	if stringField(player, "location") != "study" || !slices.Equal(stringList(player, "inventory"), []string{"ring"}) ||
+		!slices.Equal(stringList(player, "visited_locations"), []string{"foyer", "study"}) {
+		t.Errorf("player = %v", player)
+	}
+	study, _ := state.lookup("locations", "study")
+	chest, _ := state.lookup("items", "chest")
+	key, _ := state.lookup("items", "key")
+	if !slices.Equal(stringList(study, "items"), []string{"chest", "key"}) || len(stringList(chest, "contains")) != 0 || stringField(key, "location") != "study" {
+		t.Errorf("study holds %q, chest %q, key at %q", stringList(study, "items"), stringList(chest, "contains"), stringField(key, "location"))
+	}
+	foyer, _ := state.lookup("locations", "foyer")
+	if door := object(foyer, "door_states")["north"].(map[string]any); door["locked"] != false {
+		t.Errorf("north door = %v", door)
+	}
+	if garden, _ := state.lookup("locations", "garden"); garden["outdoors"] != true || study["outdoors"] == true {
+		t.Errorf("garden outdoors = %v, study outdoors = %v", garden["outdoors"], study["outdoors"])
+	}
+}
+
+func TestGetAndRestoreWorldState(t *testing.T) {
+	store, _ := testStore(t)
+	session := connect(t, store)
+	snapshot := callText(t, session, "get_world_state", nil)
+	if !strings.Contains(snapshot, `"location": "foyer"`) {
+		t.Fatalf("get_world_state = %s", snapshot)
+	}
+
+	callText(t, session, "move_player", map[string]any{"location": "hall"})
+	if got := callText(t, session, "restore_world_state", map[string]any{"state": snapshot}); got != "World state restored (player at foyer)" {
+		t.Errorf("restore = %q", got)
+	}
+	if got := callText(t, session, "get_world_state", nil); got != snapshot {
+		t.Errorf("restored world differs:\n%s", got)
+	}
+
+	for state, want := range map[string]string{
+		"{not json":              "Error: Invalid world state JSON",
+		`{"player": {}}`:         "Error: World state must include player and locations",
+		`{"locations": {}}`:      "Error: World state must include player and locations",
+		`{"player": [], "x": 1}`: "Error: World state must include player and locations",
+	} {
+		if got := callText(t, session, "restore_world_state", map[string]any{"state": state}); !strings.HasPrefix(got, want) {
+			t.Errorf("restore %s = %q, want %q", state, got, want)
+		}
+	}
+	if got := callText(t, session, "get_world_state", nil); got != snapshot {
+		t.Error("a refused restore changed the world")
+	}
+}
+
+func TestStoreFiles(t *testing.T) {
+	// A missing file starts the default world and is written
+	path := filepath.Join(t.TempDir(), "data", "world_state.json")
+	if got := callText(t, connect(t, NewStore(path)), "move_player", map[string]any{"location": "library"}); got != "Player moved from foyer to library" {
+		t.Errorf("move in the default world = %q", got)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("no world file written: %v", err)
+	}
+
+	// A broken file is reported rather than replaced by the default world
+	broken := filepath.Join(t.TempDir(), "world_state.json")
+	if err := os.WriteFile(broken, []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := connect(t, NewStore(broken)).CallTool(context.Background(), &mcp.CallToolParams{Name: "get_world_state"})
+	if err == nil && !result.IsError {
+		t.Error("a broken world file was read")
+	}
+	if data, _ := os.ReadFile(broken); string(data) != "{broken" {
+		t.Error("a broken world file was overwritten")
+	}
+}
+
+// The Go server offers exactly the tools the Python server does.
+func TestToolParityWithPythonServer(t *testing.T) {
+	source, err := os.ReadFile("../../services/worldstate/world_state.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var python []string
+	for _, match := range regexp.MustCompile(`@mcp\.tool\(\)\s+async def (\w+)`).FindAllSubmatch(source, -1) {
+		python = append(python, string(match[1]))
+	}
+	tools, err := connect(t, NewMemoryStore(nil)).ListTools(context.Background(), &mcp.ListToolsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var goTools []string
+	for _, tool := range tools.Tools {
+		goTools = append(goTools, tool.Name)
+	}
+	slices.Sort(python)
+	slices.Sort(goTools)
+	if len(python) == 0 || !slices.Equal(python, goTools) {
+		t.Errorf("tools differ:\npython %v\ngo     %v", python, goTools)
+	}
+}
@@ -0,0 +1,269 @@
+// Package worldstate is the world-state MCP server written in Go. It serves the same
+// tools as services/worldstate/world_state.py, against the same JSON file, so the game
+// can run it in process instead of starting the Python server.
+package worldstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// DefaultPath is where the Python server keeps the world, relative to the repository root.
+const DefaultPath = "services/world_state.json"
+
+// Store loads and saves the world state file. Every tool reads the file, changes it and
+// writes it back, as the Python server does, so the two can share a file.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	// memory holds the encoded world of a store without a file; see NewMemoryStore.
+	memory []byte
+}
+
+// NewStore creates a store for the world state file at path. The file is created with
+// the default world the first time it is read.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// NewMemoryStore creates a store that keeps the world in memory, starting from state,
+// the JSON of a world, instead of in a file; with no state it starts from the default
+// world. Tests use it to run the real tools without touching the disk.
+func NewMemoryStore(state []byte) *Store {
+	return &Store{memory: slices.Clone(state)}
+}
+
+// Snapshot returns the JSON of the current world, as get_world_state answers it.
+func (s *Store) Snapshot() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(state, "", "  ")
+}
+
+// world is the decoded world state. It stays untyped so fields the game doesn't know
+// about (a scenario's own tools, older saves) survive a round trip.
+type world map[string]any
+
+// update runs change against the current world and saves it if change reports that it
+// changed anything. It returns the message change produced.
+func (s *Store) update(change func(state world) (message string, changed bool)) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	message, changed := change(state)
+	if changed {
+		if err := s.save(state); err != nil {
+			return "", err
+		}
+	}
+	return message, nil
+}
+
+func (s *Store) load() (world, error) {
+	if s.path == "" {
+		if s.memory == nil {
+			return defaultWorld(), nil
+		}
+		var state world
+		if err := json.Unmarshal(s.memory, &state); err != nil {
+			return nil, fmt.Errorf("parse world state: %w", err)
+		}
+		return state, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		state := defaultWorld()
+		if err := s.save(state); err != nil {
+			return nil, err
+		}
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read world state: %w", err)
+	}
+	var state world
+	if err := json.Unmarshal(data, &state); err != nil {
+		// Unlike the Python server, don't fall back to the default world: the next save
+		// would overwrite the broken file
+		return nil, fmt.Errorf("parse world state %s: %w", s.path, err)
+	}
+	return state, nil
+}
+
+func (s *Store) save(state world) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode world state: %w", err)
+	}
+	if s.path == "" {
+		s.memory = data
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("save world state: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("save world state: %w", err)
+	}
+	return nil
+}
+
+// defaultWorldJSON is the Python server's DEFAULT_WORLD_STATE.
+const defaultWorldJSON = `{
+  "briefing": {
+    "title": "The Manor",
+    "premise": "You wake on the cold floor of an old manor with no memory of arriving. The doors are heavy, the rooms are quiet, and you are not the only one here.",
+    "content_warnings": ["memory loss", "mild peril"],
+    "suggested_verbs": ["look", "examine", "go north", "take", "talk to"]
+  },
+  "player": {
+    "location": "foyer",
+    "inventory": [],
+    "met_npcs": [],
+    "visited_locations": ["foyer"],
+    "conditions": []
+  },
+  "locations": {
+    "foyer": {
+      "name": "Old Foyer",
+      "outdoors": false,
+      "facts": [],
+      "exits": {"north": "study", "east": "library", "west": "kitchen"},
+      "door_states": {"north": {"locked": true, "description": "locked oak door"}}
+    },
+    "study": {
+      "name": "Quiet Study",
+      "outdoors": false,
+      "facts": [],
+      "exits": {"south": "foyer", "up": "attic"},
+      "door_states": {}
+    },
+    "library": {
+      "name": "Dusty Library",
+      "outdoors": false,
+      "facts": [],
+      "exits": {"west": "foyer"},
+      "door_states": {}
+    },
+    "kitchen": {
+      "name": "Abandoned Kitchen",
+      "outdoors": false,
+      "facts": [],
+      "exits": {"east": "foyer", "down": "cellar"},
+      "door_states": {"down": {"locked": true, "description": "heavy wooden trapdoor"}}
+    },
+    "attic": {
+      "name": "Cramped Attic",
+      "outdoors": false,
+      "facts": [],
+      "narrator_notes": ["emphasize the cold and the wind worrying at the roof slates"],
+      "exits": {"down": "study"},
+      "door_states": {}
+    },
+    "cellar": {
+      "name": "Stone Cellar",
+      "outdoors": false,
+      "facts": [],
+      "exits": {"up": "kitchen"},
+      "door_states": {}
+    }
+  },
+  "items": {},
+  "npcs": {
+    "elena": {
+      "location": "library",
+      "debug_color": "35",
+      "description": "a woman in her thirties with dark hair loose and slightly disheveled, wearing a simple gray dress",
+      "inventory": [],
+      "recent_thoughts": [],
+      "recent_actions": [],
+      "personality": "curious and observant, pragmatic under pressure, empathetic but guarded",
+      "backstory": "She has just woken up inside the manor and cannot remember who she is or how she got there.",
+      "core_memories": []
+    }
+  }
+}`
+
+func defaultWorld() world {
+	var state world
+	if err := json.Unmarshal([]byte(defaultWorldJSON), &state); err != nil {
+		panic(fmt.Sprintf("worldstate: invalid default world: %v", err))
+	}
+	return state
+}
+
+// object returns the object under key, creating it when it is missing.
+func object(parent map[string]any, key string) map[string]any {
+	if child, ok := parent[key].(map[string]any); ok {
+		return child
+	}
+	child := make(map[string]any)
+	parent[key] = child
+	return child
+}
+
+// lookup returns the object under key of the object under section, e.g. an NPC by ID.
+func (w world) lookup(section, key string) (map[string]any, bool) {
+	entries, ok := w[section].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	entry, ok := entries[key].(map[string]any)
+	return entry, ok
+}
+
+func (w world) player() map[string]any {
+	return object(w, "player")
+}
+
+// stringList returns the strings in the list under key; a missing list is empty.
+func stringList(parent map[string]any, key string) []string {
+	switch list := parent[key].(type) {
+	case []string:
+		return list
+	case []any:
+		values := make([]string, 0, len(list))
+		for _, value := range list {
+			if text, ok := value.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+	return []string{}
+}
+
+func stringField(parent map[string]any, key string) string {
+	text, _ := parent[key].(string)
+	return text
+}
+
+// anyList returns the list under key; a missing list is empty.
+func anyList(parent map[string]any, key string) []any {
+	if list, ok := parent[key].([]any); ok {
+		return list
+	}
+	return []any{}
+}
+
+// removeString removes the first occurrence of value, like Python's list.remove.
+func removeString(list []string, value string) []string {
+	for i, entry := range list {
+		if entry == value {
+			return append(list[:i:i], list[i+1:]...)
+		}
+	}
+	return list
+}
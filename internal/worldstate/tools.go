@@ -0,0 +1,841 @@
+package worldstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// The tools below mirror world_state.py: same checks, same messages. Failures are
+// "Error: ..." messages rather than tool errors, which is what clients already expect.
+
+// maxNPCMemoryEntries is how many recent thoughts and actions are kept per NPC.
+const maxNPCMemoryEntries = 20
+
+// Limits on scheduled events; the game validates the same caps before calling.
+const (
+	maxScheduleDelay      = 20
+	maxScheduledMutations = 5
+)
+
+var knownConditions = map[string]bool{"injured": true, "exhausted": true, "soaked": true, "cold": true}
+
+var knownEmotions = map[string]bool{"fear": true, "trust": true, "curiosity": true}
+
+// containerOpenedFact marks a container the player has looked inside, matching
+// game.ContainerOpenedFact.
+const containerOpenedFact = "The player has looked inside"
+
+// Limits on relationships; the game validates the same caps before calling.
+const (
+	maxAffinity       = 10
+	maxAffinityChange = 3
+	maxInteractions   = 5
+)
+
+func getWorldState(state world) (string, bool) {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Error: Could not encode world state: %v", err), false
+	}
+	return string(data), false
+}
+
+func restoreWorldState(state world, snapshot string) (string, bool) {
+	var restored world
+	if err := json.Unmarshal([]byte(snapshot), &restored); err != nil {
+		return fmt.Sprintf("Error: Invalid world state JSON: %v", err), false
+	}
+	player, hasPlayer := restored["player"].(map[string]any)
+	if _, hasLocations := restored["locations"]; !hasPlayer || !hasLocations {
+		return "Error: World state must include player and locations", false
+	}
+
+	// Older saves don't track visited rooms; the room they were saved in is the one known
+	if _, ok := player["visited_locations"]; !ok {
+		player["visited_locations"] = []any{player["location"]}
+	}
+
+	// Older saves have no briefing or scenario tools; keep the scenario's rather than dropping them
+	for _, key := range []string{"briefing", "passthrough_tools"} {
+		if _, ok := restored[key]; !ok {
+			if current, ok := state[key]; ok {
+				restored[key] = current
+			}
+		}
+	}
+
+	for key := range state {
+		delete(state, key)
+	}
+	for key, value := range restored {
+		state[key] = value
+	}
+	location := stringField(player, "location")
+	if location == "" {
+		location = "unknown"
+	}
+	return fmt.Sprintf("World state restored (player at %s)", location), true
+}
+
+// lockedDoorTo checks the way from one location to another: it returns an error message
+// when there is no exit to it or the door on that exit is locked.
+func lockedDoorTo(state world, from, to string) string {
+	current, _ := state.lookup("locations", from)
+	if current == nil {
+		current = map[string]any{}
+	}
+	exits := object(current, "exits")
+	var directions []string
+	for direction, target := range exits {
+		if target == to {
+			directions = append(directions, direction)
+		}
+	}
+	if len(directions) == 0 {
+		return fmt.Sprintf("Error: Cannot move directly from %s to %s", from, to)
+	}
+	sort.Strings(directions)
+	doors := object(current, "door_states")
+	for _, direction := range directions {
+		door, _ := doors[direction].(map[string]any)
+		if locked, _ := door["locked"].(bool); locked {
+			description := stringField(door, "description")
+			if description == "" {
+				description = "door"
+			}
+			return fmt.Sprintf("Error: The %s is locked", description)
+		}
+	}
+	return ""
+}
+
+func movePlayer(state world, location string) (string, bool) {
+	player := state.player()
+	current := stringField(player, "location")
+
+	if _, ok := state.lookup("locations", location); !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", location), false
+	}
+	if problem := lockedDoorTo(state, current, location); problem != "" {
+		return problem, false
+	}
+
+	// Move player, revealing the room on the known map
+	player["location"] = location
+	visited := stringList(player, "visited_locations")
+	if !slices.Contains(visited, location) {
+		player["visited_locations"] = append(visited, location)
+	}
+	return fmt.Sprintf("Player moved from %s to %s", current, location), true
+}
+
+func moveNPC(state world, npcID, location string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	current := stringField(npc, "location")
+
+	if _, ok := state.lookup("locations", location); !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", location), false
+	}
+	if problem := lockedDoorTo(state, current, location); problem != "" {
+		return problem, false
+	}
+
+	npc["location"] = location
+	return fmt.Sprintf("NPC %s moved from %s to %s", npcID, current, location), true
+}
+
+func transferItem(state world, item, from, to string) (string, bool) {
+	itemData, ok := state.lookup("items", item)
+	if !ok {
+		return fmt.Sprintf("Error: Item '%s' does not exist", item), false
+	}
+	if problem := detachItem(state, item, from); problem != "" {
+		return problem, false
+	}
+	if problem := attachItem(state, item, to); problem != "" {
+		return problem, false
+	}
+
+	// The item's own location is what clients read; keep it in step with the lists
+	itemData["location"] = to
+	return fmt.Sprintf("Item '%s' transferred from %s to %s", item, from, to), true
+}
+
+// detachItem takes item out of the list holding it at from: the player's inventory, an
+// NPC's, a container's contents or a location's items. It returns an error message when
+// the item isn't there.
+func detachItem(state world, item, from string) string {
+	if from == "player" {
+		player := state.player()
+		inventory := stringList(player, "inventory")
+		if !slices.Contains(inventory, item) {
+			return fmt.Sprintf("Error: Item '%s' not in player inventory", item)
+		}
+		player["inventory"] = removeString(inventory, item)
+	} else if npc, ok := state.lookup("npcs", from); ok {
+		inventory := stringList(npc, "inventory")
+		if !slices.Contains(inventory, item) {
+			return fmt.Sprintf("Error: Item '%s' not in %s's inventory", item, from)
+		}
+		npc["inventory"] = removeString(inventory, item)
+	} else if container, ok := state.lookup("items", from); ok {
+		contents := stringList(container, "contains")
+		if !slices.Contains(contents, item) {
+			return fmt.Sprintf("Error: Item '%s' not in %s", item, from)
+		}
+		container["contains"] = removeString(contents, item)
+	} else {
+		location, ok := state.lookup("locations", from)
+		if !ok {
+			return fmt.Sprintf("Error: Location '%s' does not exist", from)
+		}
+		items := stringList(location, "items")
+		if !slices.Contains(items, item) {
+			return fmt.Sprintf("Error: Item '%s' not in location '%s'", item, from)
+		}
+		location["items"] = removeString(items, item)
+	}
+	return ""
+}
+
+// attachItem adds item to the list holding things at to, the counterpart of detachItem.
+func attachItem(state world, item, to string) string {
+	if to == "player" {
+		player := state.player()
+		player["inventory"] = append(stringList(player, "inventory"), item)
+	} else if npc, ok := state.lookup("npcs", to); ok {
+		npc["inventory"] = append(stringList(npc, "inventory"), item)
+	} else if container, ok := state.lookup("items", to); ok {
+		container["contains"] = append(stringList(container, "contains"), item)
+	} else {
+		location, ok := state.lookup("locations", to)
+		if !ok {
+			return fmt.Sprintf("Error: Location '%s' does not exist", to)
+		}
+		location["items"] = append(stringList(location, "items"), item)
+	}
+	return ""
+}
+
+func addToInventory(state world, item string) (string, bool) {
+	current := stringField(state.player(), "location")
+	location, _ := state.lookup("locations", current)
+	if location == nil || !slices.Contains(stringList(location, "items"), item) {
+		return fmt.Sprintf("Error: Item '%s' is not available in %s", item, current), false
+	}
+
+	if result, changed := transferItem(state, item, current, "player"); !changed {
+		return result, false
+	}
+	return fmt.Sprintf("Player picked up %s", item), true
+}
+
+func removeFromInventory(state world, item string) (string, bool) {
+	player := state.player()
+	current := stringField(player, "location")
+	if !slices.Contains(stringList(player, "inventory"), item) {
+		return fmt.Sprintf("Error: Item '%s' is not in inventory", item), false
+	}
+
+	if result, changed := transferItem(state, item, "player", current); !changed {
+		return result, false
+	}
+	return fmt.Sprintf("Player dropped %s in %s", item, current), true
+}
+
+func unlockDoor(state world, location, direction, keyItem string) (string, bool) {
+	locationData, ok := state.lookup("locations", location)
+	if !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", location), false
+	}
+	door, ok := object(locationData, "door_states")[direction].(map[string]any)
+	if !ok {
+		return fmt.Sprintf("Error: No door to the %s in %s", direction, location), false
+	}
+	if !slices.Contains(stringList(state.player(), "inventory"), keyItem) {
+		return fmt.Sprintf("Error: Player does not have %s", keyItem), false
+	}
+	key, ok := state.lookup("items", keyItem)
+	if !ok {
+		return fmt.Sprintf("Error: Item '%s' does not exist", keyItem), false
+	}
+
+	doorID := location + "_" + direction
+	if !slices.Contains(stringList(key, "can_unlock"), doorID) {
+		return fmt.Sprintf("Error: %s cannot unlock this door", keyItem), false
+	}
+
+	door["locked"] = false
+	return fmt.Sprintf("Door to the %s in %s has been unlocked with %s", direction, location, keyItem), true
+}
+
+// openableContainer looks up a container that can be opened, or explains why it can't.
+func openableContainer(state world, containerID string) (map[string]any, string) {
+	container, ok := state.lookup("items", containerID)
+	if !ok {
+		return nil, fmt.Sprintf("Error: Item '%s' does not exist", containerID)
+	}
+	if isContainer, _ := container["is_container"].(bool); !isContainer {
+		return nil, fmt.Sprintf("Error: %s is not a container", containerID)
+	}
+	if locked, _ := container["locked"].(bool); locked {
+		return nil, fmt.Sprintf("Error: %s is locked", containerID)
+	}
+	return container, ""
+}
+
+func putItemInContainer(state world, item, containerID string) (string, bool) {
+	itemData, ok := state.lookup("items", item)
+	if !ok {
+		return fmt.Sprintf("Error: Item '%s' does not exist", item), false
+	}
+	container, problem := openableContainer(state, containerID)
+	if problem != "" {
+		return problem, false
+	}
+	// Nothing goes inside itself, or inside something it already holds
+	for holder := containerID; holder != ""; {
+		if holder == item {
+			return fmt.Sprintf("Error: %s cannot go inside %s", item, containerID), false
+		}
+		parent, ok := state.lookup("items", holder)
+		if !ok {
+			break
+		}
+		holder = stringField(parent, "location")
+	}
+	contents := stringList(container, "contains")
+	if slices.Contains(contents, item) {
+		return fmt.Sprintf("Error: Item '%s' is already in %s", item, containerID), false
+	}
+	if capacity, _ := container["capacity"].(float64); capacity > 0 && len(contents) >= int(capacity) {
+		return fmt.Sprintf("Error: %s is full (capacity %d)", containerID, int(capacity)), false
+	}
+
+	from := stringField(itemData, "location")
+	if problem := detachItem(state, item, from); problem != "" {
+		return problem, false
+	}
+	container["contains"] = append(stringList(container, "contains"), item)
+	itemData["location"] = containerID
+	return fmt.Sprintf("Put %s in %s", item, containerID), true
+}
+
+func takeItemFromContainer(state world, item, containerID, to string) (string, bool) {
+	itemData, ok := state.lookup("items", item)
+	if !ok {
+		return fmt.Sprintf("Error: Item '%s' does not exist", item), false
+	}
+	if _, problem := openableContainer(state, containerID); problem != "" {
+		return problem, false
+	}
+	if problem := detachItem(state, item, containerID); problem != "" {
+		return problem, false
+	}
+	if problem := attachItem(state, item, to); problem != "" {
+		return problem, false
+	}
+	itemData["location"] = to
+	return fmt.Sprintf("Took %s from %s to %s", item, containerID, to), true
+}
+
+func openContainer(state world, containerID string) (string, bool) {
+	container, problem := openableContainer(state, containerID)
+	if problem != "" {
+		return problem, false
+	}
+	facts := stringList(container, "facts")
+	if !slices.Contains(facts, containerOpenedFact) {
+		container["facts"] = append(facts, containerOpenedFact)
+	}
+	contents := stringList(container, "contains")
+	if len(contents) == 0 {
+		return fmt.Sprintf("Opened %s: it is empty", containerID), true
+	}
+	return fmt.Sprintf("Opened %s: it holds %s", containerID, strings.Join(contents, ", ")), true
+}
+
+func memoryEntry(text string, turn int) map[string]any {
+	entry := map[string]any{"text": text}
+	if turn > 0 {
+		entry["turn"] = turn
+	}
+	return entry
+}
+
+func appendMemory(npc map[string]any, key, text string, turn int) {
+	entries := append(anyList(npc, key), memoryEntry(text, turn))
+	if len(entries) > maxNPCMemoryEntries {
+		entries = entries[len(entries)-maxNPCMemoryEntries:]
+	}
+	npc[key] = entries
+}
+
+func updateNPCMemory(state world, npcID, thought, action string, turn int) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+
+	npc["recent_thoughts"] = anyList(npc, "recent_thoughts")
+	npc["recent_actions"] = anyList(npc, "recent_actions")
+
+	var updates []string
+	if thought != "" {
+		appendMemory(npc, "recent_thoughts", thought, turn)
+		updates = append(updates, fmt.Sprintf("thought: '%s'", thought))
+	}
+	if action != "" {
+		appendMemory(npc, "recent_actions", action, turn)
+		updates = append(updates, fmt.Sprintf("action: '%s'", action))
+	}
+
+	if len(updates) == 0 {
+		return fmt.Sprintf("No updates provided for %s", npcID), true
+	}
+	return fmt.Sprintf("Updated %s memory - %s", npcID, strings.Join(updates, ", ")), true
+}
+
+func configureNPC(state world, npcID, personality, backstory, coreMemories string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+
+	var updates []string
+	if personality != "" {
+		npc["personality"] = personality
+		updates = append(updates, "personality")
+	}
+	if backstory != "" {
+		npc["backstory"] = backstory
+		updates = append(updates, "backstory")
+	}
+	if coreMemories != "" {
+		memories := []string{}
+		for _, memory := range strings.Split(coreMemories, ",") {
+			if memory = strings.TrimSpace(memory); memory != "" {
+				memories = append(memories, memory)
+			}
+		}
+		npc["core_memories"] = memories
+		updates = append(updates, "core memories")
+	}
+
+	if len(updates) == 0 {
+		return fmt.Sprintf("No configuration changes provided for %s", npcID), false
+	}
+	return fmt.Sprintf("Updated %s: %s", npcID, strings.Join(updates, ", ")), true
+}
+
+func markNPCAsMet(state world, npcID string) (string, bool) {
+	if _, ok := state.lookup("npcs", npcID); !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+
+	player := state.player()
+	met := stringList(player, "met_npcs")
+	if slices.Contains(met, npcID) {
+		return fmt.Sprintf("Player has already met %s", npcID), false
+	}
+	player["met_npcs"] = append(met, npcID)
+	return fmt.Sprintf("Player has now met %s", npcID), true
+}
+
+func recordDialogue(state world, npcID, nodeID string, questUpdates map[string]string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	quests := anyList(state, "quests")
+	for questID := range questUpdates {
+		if !slices.ContainsFunc(quests, func(entry any) bool {
+			quest, _ := entry.(map[string]any)
+			return stringField(quest, "id") == questID
+		}) {
+			return fmt.Sprintf("Error: Quest '%s' does not exist", questID), false
+		}
+	}
+
+	spoken := stringList(npc, "dialogue_spoken")
+	if !slices.Contains(spoken, nodeID) {
+		npc["dialogue_spoken"] = append(spoken, nodeID)
+	}
+	for _, entry := range quests {
+		quest, _ := entry.(map[string]any)
+		if status, ok := questUpdates[stringField(quest, "id")]; ok {
+			quest["status"] = status
+		}
+	}
+	return fmt.Sprintf("%s said %s", npcID, nodeID), true
+}
+
+func setNPCGoal(state world, npcID, action, goal string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	goal = strings.TrimSpace(goal)
+	if goal == "" {
+		return "Error: goal must not be empty", false
+	}
+
+	goals := stringList(npc, "goals")
+	switch action {
+	case "add":
+		if slices.Contains(goals, goal) {
+			return fmt.Sprintf("%s already has the goal: %s", npcID, goal), false
+		}
+		npc["goals"] = append(goals, goal)
+		return fmt.Sprintf("%s now has the goal: %s", npcID, goal), true
+	case "complete":
+		i := slices.Index(goals, goal)
+		if i < 0 {
+			return fmt.Sprintf("Error: %s has no goal '%s'", npcID, goal), false
+		}
+		npc["goals"] = slices.Delete(goals, i, i+1)
+		return fmt.Sprintf("%s completed the goal: %s", npcID, goal), true
+	}
+	return fmt.Sprintf("Error: action must be 'add' or 'complete', got '%s'", action), false
+}
+
+// npcEmotions returns the NPC's emotions map, creating it if needed.
+func npcEmotions(npc map[string]any) map[string]any {
+	emotions, ok := npc["emotions"].(map[string]any)
+	if !ok {
+		emotions = map[string]any{}
+		npc["emotions"] = emotions
+	}
+	return emotions
+}
+
+func adjustNPCEmotion(state world, npcID, emotion string, delta float64) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	if !knownEmotions[emotion] {
+		return fmt.Sprintf("Error: Unknown emotion '%s'", emotion), false
+	}
+
+	emotions := npcEmotions(npc)
+	before, _ := emotions[emotion].(float64)
+	after := math.Round(math.Max(0, math.Min(1, before+delta))*100) / 100
+	emotions[emotion] = after
+	return fmt.Sprintf("%s %s: %.2f -> %.2f", npcID, emotion, before, after), true
+}
+
+func syncNPCEmotions(state world, npcID string, emotions map[string]float64) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	cleaned := map[string]any{}
+	for emotion, intensity := range emotions {
+		if !knownEmotions[emotion] {
+			return fmt.Sprintf("Error: Unknown emotion '%s'", emotion), false
+		}
+		cleaned[emotion] = math.Max(0, math.Min(1, intensity))
+	}
+	npc["emotions"] = cleaned
+	return fmt.Sprintf("%s emotions: %d", npcID, len(cleaned)), true
+}
+
+func updateRelationship(state world, npcID string, delta int, interaction string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	if delta < -maxAffinityChange || delta > maxAffinityChange {
+		return fmt.Sprintf("Error: delta must be between -%d and %d", maxAffinityChange, maxAffinityChange), false
+	}
+	interaction = strings.TrimSpace(interaction)
+	if interaction == "" {
+		return "Error: interaction must not be empty", false
+	}
+
+	relationship := object(npc, "relationship")
+	before := 0
+	switch affinity := relationship["affinity"].(type) {
+	case float64:
+		before = int(affinity)
+	case int:
+		before = affinity
+	}
+	after := max(-maxAffinity, min(maxAffinity, before+delta))
+	interactions := append(stringList(relationship, "interactions"), interaction)
+	if len(interactions) > maxInteractions {
+		interactions = interactions[len(interactions)-maxInteractions:]
+	}
+	relationship["affinity"] = after
+	relationship["interactions"] = interactions
+	return fmt.Sprintf("%s affinity for the player: %d -> %d (%s)", npcID, before, after, interaction), true
+}
+
+func setPlayerCondition(state world, action, condition string) (string, bool) {
+	if action != "add" && action != "remove" {
+		return fmt.Sprintf("Error: Unknown action '%s' (expected add or remove)", action), false
+	}
+	if !knownConditions[condition] {
+		return fmt.Sprintf("Error: Unknown condition '%s'", condition), false
+	}
+
+	player := state.player()
+	conditions := anyList(player, "conditions")
+	var existing map[string]any
+	for _, entry := range conditions {
+		if entry, ok := entry.(map[string]any); ok && entry["name"] == condition {
+			existing = entry
+			break
+		}
+	}
+
+	var message string
+	if action == "add" {
+		if existing != nil {
+			// Re-applying a condition restarts its decay clock
+			existing["turns"] = 0
+		} else {
+			conditions = append(conditions, map[string]any{"name": condition, "turns": 0})
+		}
+		message = fmt.Sprintf("Player is now %s", condition)
+	} else {
+		if existing == nil {
+			return fmt.Sprintf("Player is not %s", condition), false
+		}
+		kept := []any{}
+		for _, entry := range conditions {
+			if entry, ok := entry.(map[string]any); ok && entry["name"] == condition {
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		conditions = kept
+		message = fmt.Sprintf("Player is no longer %s", condition)
+	}
+
+	player["conditions"] = conditions
+	return message, true
+}
+
+func syncPlayerConditions(state world, conditions []conditionArg) (string, bool) {
+	cleaned := make([]any, 0, len(conditions))
+	names := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		if !knownConditions[condition.Name] {
+			return fmt.Sprintf("Error: Unknown condition '%s'", condition.Name), false
+		}
+		cleaned = append(cleaned, map[string]any{"name": condition.Name, "turns": condition.Turns})
+		names = append(names, condition.Name)
+	}
+
+	state.player()["conditions"] = cleaned
+	summary := strings.Join(names, ", ")
+	if summary == "" {
+		summary = "none"
+	}
+	return fmt.Sprintf("Player conditions: %s", summary), true
+}
+
+func scheduleEvent(state world, delayTurns int, description string, mutations []map[string]any, location string) (string, bool) {
+	if delayTurns < 1 || delayTurns > maxScheduleDelay {
+		return fmt.Sprintf("Error: delay_turns must be between 1 and %d", maxScheduleDelay), false
+	}
+	if strings.TrimSpace(description) == "" {
+		return "Error: description is required", false
+	}
+	if len(mutations) > maxScheduledMutations {
+		return fmt.Sprintf("Error: at most %d mutations per event", maxScheduledMutations), false
+	}
+	if mutations == nil {
+		mutations = []map[string]any{}
+	}
+
+	events := anyList(state, "scheduled_events")
+	nextID := 1
+	for _, event := range events {
+		if event, ok := event.(map[string]any); ok {
+			if id, ok := event["id"].(float64); ok && int(id) >= nextID {
+				nextID = int(id) + 1
+			}
+		}
+	}
+	state["scheduled_events"] = append(events, map[string]any{
+		"id":          nextID,
+		"turns_left":  delayTurns,
+		"description": description,
+		"location":    location,
+		"mutations":   mutations,
+	})
+	return fmt.Sprintf("Scheduled event %d in %d turns: %s", nextID, delayTurns, description), true
+}
+
+func syncScheduledEvents(state world, events []map[string]any) (string, bool) {
+	if events == nil {
+		events = []map[string]any{}
+	}
+	state["scheduled_events"] = events
+	return fmt.Sprintf("%d scheduled events pending", len(events)), true
+}
+
+func createItem(state world, itemID, name, location string, facts []string) (string, bool) {
+	if _, exists := state.lookup("items", itemID); exists {
+		return fmt.Sprintf("Error: Item '%s' already exists", itemID), false
+	}
+	_, isLocation := state.lookup("locations", location)
+	_, isNPC := state.lookup("npcs", location)
+	if location != "player" && !isLocation && !isNPC {
+		return fmt.Sprintf("Error: Location '%s' does not exist", location), false
+	}
+	if facts == nil {
+		facts = []string{}
+	}
+
+	object(state, "items")[itemID] = map[string]any{
+		"name":       name,
+		"facts":      facts,
+		"location":   location,
+		"can_unlock": []string{},
+	}
+	return fmt.Sprintf("Created item '%s' (%s) at %s", name, itemID, location), true
+}
+
+func createNPC(state world, npcID, name, location string, facts []string) (string, bool) {
+	if _, exists := state.lookup("npcs", npcID); exists {
+		return fmt.Sprintf("Error: NPC '%s' already exists", npcID), false
+	}
+	if _, ok := state.lookup("locations", location); !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", location), false
+	}
+	if facts == nil {
+		facts = []string{}
+	}
+
+	object(state, "npcs")[npcID] = map[string]any{
+		"name":            name,
+		"location":        location,
+		"debug_color":     "37",
+		"facts":           facts,
+		"inventory":       []string{},
+		"recent_thoughts": []any{},
+		"recent_actions":  []any{},
+		"personality":     "",
+		"backstory":       "",
+		"memories":        []string{},
+	}
+	return fmt.Sprintf("Created NPC '%s' (%s) at %s", name, npcID, location), true
+}
+
+func createLocation(state world, locationID, name string, exits map[string]string, outdoors bool) (string, bool) {
+	if _, exists := state.lookup("locations", locationID); exists {
+		return fmt.Sprintf("Error: Location '%s' already exists", locationID), false
+	}
+	if exits == nil {
+		exits = map[string]string{}
+	}
+
+	object(state, "locations")[locationID] = map[string]any{
+		"name":        name,
+		"outdoors":    outdoors,
+		"facts":       []string{},
+		"exits":       exits,
+		"door_states": map[string]any{},
+	}
+	return fmt.Sprintf("Created location '%s' (%s)", name, locationID), true
+}
+
+func setLocationAmbience(state world, locationID, ambience string) (string, bool) {
+	location, ok := state.lookup("locations", locationID)
+	if !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", locationID), false
+	}
+	ambience = strings.TrimSpace(ambience)
+	if ambience == "" {
+		delete(location, "ambience")
+		return fmt.Sprintf("Cleared the ambience of %s", locationID), true
+	}
+	location["ambience"] = ambience
+	return fmt.Sprintf("Ambience of %s: %s", locationID, ambience), true
+}
+
+func markAmbienceHeard(state world, npcID, locationID, ambience string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+	if _, ok := state.lookup("locations", locationID); !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", locationID), false
+	}
+	heard, ok := npc["ambience_heard"].(map[string]any)
+	if !ok {
+		heard = map[string]any{}
+		npc["ambience_heard"] = heard
+	}
+	heard[locationID] = ambience
+	return fmt.Sprintf("%s has heard the ambience of %s", npcID, locationID), true
+}
+
+func addLocationFacts(state world, locationID string, facts []string) (string, bool) {
+	location, ok := state.lookup("locations", locationID)
+	if !ok {
+		return fmt.Sprintf("Error: Location '%s' does not exist", locationID), false
+	}
+
+	// Add all facts - deduplication handled by LLM at attribution level
+	location["facts"] = append(stringList(location, "facts"), facts...)
+	return fmt.Sprintf("Added %d facts to %s: %s", len(facts), locationID, pyList(facts)), true
+}
+
+func addItemFacts(state world, itemID string, facts []string) (string, bool) {
+	item, ok := state.lookup("items", itemID)
+	if !ok {
+		return fmt.Sprintf("Error: Item '%s' does not exist", itemID), false
+	}
+
+	// Add all facts - deduplication handled by LLM at attribution level
+	item["facts"] = append(stringList(item, "facts"), facts...)
+	return fmt.Sprintf("Added %d facts to %s: %s", len(facts), itemID, pyList(facts)), true
+}
+
+func addNPCFacts(state world, npcID string, facts []string) (string, bool) {
+	npc, ok := state.lookup("npcs", npcID)
+	if !ok {
+		return fmt.Sprintf("Error: NPC '%s' does not exist", npcID), false
+	}
+
+	// Simple deduplication - exact string matches only
+	existing := stringList(npc, "facts")
+	var added []string
+	for _, fact := range facts {
+		if !slices.Contains(existing, fact) {
+			existing = append(existing, fact)
+			added = append(added, fact)
+		}
+	}
+	npc["facts"] = existing
+
+	if len(added) == 0 {
+		return fmt.Sprintf("No new facts added to %s (all were duplicates)", npcID), true
+	}
+	return fmt.Sprintf("Added %d facts to %s: %s", len(added), npcID, pyList(added)), true
+}
+
+// pyList formats strings the way the Python server prints a list, e.g. ['a', "b's"].
+func pyList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		if strings.Contains(value, "'") && !strings.Contains(value, `"`) {
+			quoted[i] = `"` + value + `"`
+		} else {
+			quoted[i] = "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+		}
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -3,11 +3,9 @@ PROJECT_ROOT="$(dirname "$0")/.."
 cd "$PROJECT_ROOT"
 
 echo "Starting development environment in DEBUG MODE..."
-echo "Debug logs will be written to debug.log"
+echo "Debug logs will be written to the session directory (see ~/.local/share/textadventure/sessions/latest/debug.log)"
 echo "This will start the MCP server in the background and then the game"
 
-> debug.log
-
 # Load tracing env if available (Langfuse + OTEL)
 if [ -f .env.tracing ]; then
   # shellcheck disable=SC1091
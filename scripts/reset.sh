@@ -13,12 +13,6 @@ else
     echo "✓ No existing world state file found"
 fi
 
-# Also clear debug logs if they exist
-if [ -f "debug.log" ]; then
-    > debug.log
-    echo "✓ Cleared debug log"
-fi
-
 echo ""
 echo "🎮 World state has been reset!"
 echo "   Next game start will use the default world configuration"
@@ -32,44 +32,60 @@ WORLD_STATE_FILE = Path(__file__).parent.parent / "world_state.json"
 
 # Default world state
 DEFAULT_WORLD_STATE = {
+    # Player-facing scene setting shown before the intro. The game never sends it to a model.
+    "briefing": {
+        "title": "The Manor",
+        "premise": "You wake on the cold floor of an old manor with no memory of arriving. The doors are heavy, the rooms are quiet, and you are not the only one here.",
+        "content_warnings": ["memory loss", "mild peril"],
+        "suggested_verbs": ["look", "examine", "go north", "take", "talk to"]
+    },
     "player": {
         "location": "foyer",
         "inventory": [],
-        "met_npcs": []
+        "met_npcs": [],
+        "visited_locations": ["foyer"],
+        "conditions": []
     },
     "locations": {
         "foyer": {
             "name": "Old Foyer",
+            "outdoors": False,
             "facts": [],
             "exits": {"north": "study", "east": "library", "west": "kitchen"},
             "door_states": {"north": {"locked": True, "description": "locked oak door"}}
         },
         "study": {
             "name": "Quiet Study", 
+            "outdoors": False,
             "facts": [],
             "exits": {"south": "foyer", "up": "attic"},
             "door_states": {}
         },
         "library": {
             "name": "Dusty Library",
+            "outdoors": False,
             "facts": [],
             "exits": {"west": "foyer"},
             "door_states": {}
         },
         "kitchen": {
             "name": "Abandoned Kitchen",
+            "outdoors": False,
             "facts": [],
             "exits": {"east": "foyer", "down": "cellar"},
             "door_states": {"down": {"locked": True, "description": "heavy wooden trapdoor"}}
         },
         "attic": {
             "name": "Cramped Attic",
+            "outdoors": False,
             "facts": [],
+            "narrator_notes": ["emphasize the cold and the wind worrying at the roof slates"],
             "exits": {"down": "study"},
             "door_states": {}
         },
         "cellar": {
             "name": "Stone Cellar",
+            "outdoors": False,
             "facts": [],
             "exits": {"up": "kitchen"},
             "door_states": {}
@@ -130,6 +146,37 @@ async def get_world_state() -> str:
     return json.dumps(state, indent=2)
 
 
+@mcp.tool()
+async def restore_world_state(state: str) -> str:
+    """Replace the entire world state with a saved snapshot.
+    
+    Args:
+        state: JSON string of a full world state, as returned by get_world_state
+        
+    Returns:
+        Success message or error description
+    """
+    try:
+        restored = json.loads(state)
+    except json.JSONDecodeError as e:
+        return f"Error: Invalid world state JSON: {e}"
+    
+    if not isinstance(restored, dict) or "player" not in restored or "locations" not in restored:
+        return "Error: World state must include player and locations"
+    
+    # Older saves don't track visited rooms; the room they were saved in is the one known
+    restored["player"].setdefault("visited_locations", [restored["player"].get("location")])
+    
+    # Older saves have no briefing or scenario tools; keep the scenario's rather than dropping them
+    current = load_world_state()
+    for key in ("briefing", "passthrough_tools"):
+        if key not in restored and key in current:
+            restored[key] = current[key]
+    
+    save_world_state(restored)
+    return f"World state restored (player at {restored['player'].get('location', 'unknown')})"
+
+
 @mcp.tool()
 async def move_player(location: str) -> str:
     """Move the player to a different location.
@@ -159,8 +206,11 @@ async def move_player(location: str) -> str:
             if door_state.get("locked", False):
                 return f"Error: The {door_state.get('description', 'door')} is locked"
     
-    # Move player
+    # Move player, revealing the room on the known map
     state["player"]["location"] = location
+    visited = state["player"].setdefault("visited_locations", [])
+    if location not in visited:
+        visited.append(location)
     save_world_state(state)
     
     return f"Player moved from {current_location} to {location}"
@@ -204,55 +254,71 @@ async def move_npc(npc_id: str, location: str) -> str:
     return f"NPC {npc_id} moved from {current_location} to {location}"
 
 
-@mcp.tool()
-async def transfer_item(item: str, from_location: str, to_location: str) -> str:
-    """Transfer an item from one location to another.
+def detach_item(state: Dict[str, Any], item: str, from_location: str) -> Optional[str]:
+    """Take an item out of whatever holds it: the player, an NPC, a container or a location.
     
-    Args:
-        item: The item ID to transfer
-        from_location: Source location ID (or "player" for inventory)
-        to_location: Destination location ID (or "player" for inventory)
-        
-    Returns:
-        Success message or error description
+    Returns an error message when the item isn't there, None otherwise.
     """
-    state = load_world_state()
-    
-    # Validate item exists
-    if item not in state["items"]:
-        return f"Error: Item '{item}' does not exist"
-    
-    # Handle player inventory
     if from_location == "player":
         if item not in state["player"]["inventory"]:
             return f"Error: Item '{item}' not in player inventory"
         state["player"]["inventory"].remove(item)
-    # Handle NPC inventory
     elif from_location in state.get("npcs", {}):
         if item not in state["npcs"][from_location].get("inventory", []):
             return f"Error: Item '{item}' not in {from_location}'s inventory"
         state["npcs"][from_location]["inventory"].remove(item)
+    elif from_location in state["items"]:
+        if item not in state["items"][from_location].get("contains", []):
+            return f"Error: Item '{item}' not in {from_location}"
+        state["items"][from_location]["contains"].remove(item)
     else:
-        # Validate location exists
         if from_location not in state["locations"]:
             return f"Error: Location '{from_location}' does not exist"
         if item not in state["locations"][from_location]["items"]:
             return f"Error: Item '{item}' not in location '{from_location}'"
         state["locations"][from_location]["items"].remove(item)
-    
-    # Add to destination
+    return None
+
+
+def attach_item(state: Dict[str, Any], item: str, to_location: str) -> Optional[str]:
+    """Add an item to what holds things at to_location, the counterpart of detach_item."""
     if to_location == "player":
         state["player"]["inventory"].append(item)
-    # Handle NPC inventory
     elif to_location in state.get("npcs", {}):
-        if "inventory" not in state["npcs"][to_location]:
-            state["npcs"][to_location]["inventory"] = []
-        state["npcs"][to_location]["inventory"].append(item)
+        state["npcs"][to_location].setdefault("inventory", []).append(item)
+    elif to_location in state["items"]:
+        state["items"][to_location].setdefault("contains", []).append(item)
     else:
         if to_location not in state["locations"]:
             return f"Error: Location '{to_location}' does not exist"
         state["locations"][to_location]["items"].append(item)
+    return None
+
+
+@mcp.tool()
+async def transfer_item(item: str, from_location: str, to_location: str) -> str:
+    """Transfer an item from one location to another.
+    
+    Args:
+        item: The item ID to transfer
+        from_location: Source location ID (or "player" for inventory)
+        to_location: Destination location ID (or "player" for inventory)
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    # Validate item exists
+    if item not in state["items"]:
+        return f"Error: Item '{item}' does not exist"
+    
+    problem = detach_item(state, item, from_location) or attach_item(state, item, to_location)
+    if problem:
+        return problem
     
+    # The item's own location is what clients read; keep it in step with the lists
+    state["items"][item]["location"] = to_location
     save_world_state(state)
     return f"Item '{item}' transferred from {from_location} to {to_location}"
 
@@ -349,8 +415,146 @@ async def unlock_door(location: str, direction: str, key_item: str) -> str:
     return f"Door to the {direction} in {location} has been unlocked with {key_item}"
 
 
+# Recorded on a container once the player has looked inside; matches game.ContainerOpenedFact.
+CONTAINER_OPENED_FACT = "The player has looked inside"
+
+
+def openable_container(state: Dict[str, Any], container: str) -> Optional[str]:
+    """Explain why a container can't be opened, or return None if it can."""
+    if container not in state["items"]:
+        return f"Error: Item '{container}' does not exist"
+    if not state["items"][container].get("is_container"):
+        return f"Error: {container} is not a container"
+    if state["items"][container].get("locked"):
+        return f"Error: {container} is locked"
+    return None
+
+
 @mcp.tool()
-async def update_npc_memory(npc_id: str, thought: str = "", action: str = "") -> str:
+async def put_item_in_container(item: str, container: str) -> str:
+    """Put an item, wherever it is, into a container item.
+    
+    Args:
+        item: The item ID to put away
+        container: The container item ID
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    if item not in state["items"]:
+        return f"Error: Item '{item}' does not exist"
+    problem = openable_container(state, container)
+    if problem:
+        return problem
+    
+    # Nothing goes inside itself, or inside something it already holds
+    holder = container
+    while holder:
+        if holder == item:
+            return f"Error: {item} cannot go inside {container}"
+        if holder not in state["items"]:
+            break
+        holder = state["items"][holder].get("location", "")
+    
+    contents = state["items"][container].get("contains", [])
+    if item in contents:
+        return f"Error: Item '{item}' is already in {container}"
+    capacity = int(state["items"][container].get("capacity", 0))
+    if capacity > 0 and len(contents) >= capacity:
+        return f"Error: {container} is full (capacity {capacity})"
+    
+    problem = detach_item(state, item, state["items"][item].get("location", ""))
+    if problem:
+        return problem
+    state["items"][container].setdefault("contains", []).append(item)
+    state["items"][item]["location"] = container
+    save_world_state(state)
+    
+    return f"Put {item} in {container}"
+
+
+@mcp.tool()
+async def take_item_from_container(item: str, container: str, to_location: str) -> str:
+    """Take an item out of a container item.
+    
+    Args:
+        item: The item ID to take out
+        container: The container item ID
+        to_location: Destination location ID, NPC ID or "player"
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    if item not in state["items"]:
+        return f"Error: Item '{item}' does not exist"
+    problem = openable_container(state, container)
+    if problem:
+        return problem
+    
+    problem = detach_item(state, item, container) or attach_item(state, item, to_location)
+    if problem:
+        return problem
+    state["items"][item]["location"] = to_location
+    save_world_state(state)
+    
+    return f"Took {item} from {container} to {to_location}"
+
+
+@mcp.tool()
+async def open_container(container: str) -> str:
+    """Open or look inside a container item, revealing its contents to the player.
+    
+    Args:
+        container: The container item ID
+        
+    Returns:
+        What the container holds, or error description
+    """
+    state = load_world_state()
+    
+    problem = openable_container(state, container)
+    if problem:
+        return problem
+    
+    facts = state["items"][container].setdefault("facts", [])
+    if CONTAINER_OPENED_FACT not in facts:
+        facts.append(CONTAINER_OPENED_FACT)
+    save_world_state(state)
+    
+    contents = state["items"][container].get("contains", [])
+    if not contents:
+        return f"Opened {container}: it is empty"
+    return f"Opened {container}: it holds {', '.join(contents)}"
+
+
+# Recent thoughts/actions kept per NPC. Clients decide how many reach a prompt.
+MAX_NPC_MEMORY_ENTRIES = 20
+
+
+def memory_entry(text: str, turn: int) -> Dict[str, Any]:
+    entry: Dict[str, Any] = {"text": text}
+    if turn > 0:
+        entry["turn"] = turn
+    return entry
+
+
+@mcp.tool()
+async def update_npc_memory(npc_id: str, thought: str = "", action: str = "", turn: int = 0) -> str:
+    """Record an NPC's latest thought and/or action.
+    
+    Entries are stored as {"text", "turn"} so clients can tell how old they are;
+    entries written before turns were recorded are plain strings.
+    
+    Args:
+        npc_id: The NPC whose memory to update
+        thought: The NPC's latest thought
+        action: The NPC's latest action
+        turn: The turn index these happened on (0 if unknown)
+    """
     state = load_world_state()
     
     if npc_id not in state["npcs"]:
@@ -364,14 +568,12 @@ async def update_npc_memory(npc_id: str, thought: str = "", action: str = "") ->
         npc["recent_actions"] = []
     
     if thought:
-        npc["recent_thoughts"].append(thought)
-        if len(npc["recent_thoughts"]) > 4:
-            npc["recent_thoughts"] = npc["recent_thoughts"][-4:]
+        npc["recent_thoughts"].append(memory_entry(thought, turn))
+        npc["recent_thoughts"] = npc["recent_thoughts"][-MAX_NPC_MEMORY_ENTRIES:]
     
     if action:
-        npc["recent_actions"].append(action)
-        if len(npc["recent_actions"]) > 4:
-            npc["recent_actions"] = npc["recent_actions"][-4:]
+        npc["recent_actions"].append(memory_entry(action, turn))
+        npc["recent_actions"] = npc["recent_actions"][-MAX_NPC_MEMORY_ENTRIES:]
     
     save_world_state(state)
     
@@ -457,6 +659,360 @@ async def mark_npc_as_met(npc_id: str) -> str:
     return f"Player has now met {npc_id}"
 
 
+
+@mcp.tool()
+async def record_dialogue(npc_id: str, node_id: str, quest_updates: Optional[Dict[str, str]] = None) -> str:
+    """Record that an NPC said one of its authored dialogue lines, and apply its quest updates.
+    
+    Args:
+        npc_id: The NPC who said the line
+        node_id: The ID of the dialogue node said
+        quest_updates: New quest statuses keyed by quest ID
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    npc = state.get("npcs", {}).get(npc_id)
+    if npc is None:
+        return f"Error: NPC '{npc_id}' does not exist"
+    
+    quest_updates = quest_updates or {}
+    quests = state.get("quests", [])
+    known_quests = {quest.get("id") for quest in quests}
+    for quest_id in quest_updates:
+        if quest_id not in known_quests:
+            return f"Error: Quest '{quest_id}' does not exist"
+    
+    spoken = npc.get("dialogue_spoken", [])
+    if node_id not in spoken:
+        spoken.append(node_id)
+    npc["dialogue_spoken"] = spoken
+    for quest in quests:
+        if quest.get("id") in quest_updates:
+            quest["status"] = quest_updates[quest["id"]]
+    save_world_state(state)
+    
+    return f"{npc_id} said {node_id}"
+
+
+@mcp.tool()
+async def set_npc_goal(npc_id: str, action: str, goal: str) -> str:
+    """Add a goal an NPC pursues, or mark one complete.
+    
+    Args:
+        npc_id: The NPC whose goals change
+        action: "add" or "complete"
+        goal: The goal, in a short phrase
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    npc = state.get("npcs", {}).get(npc_id)
+    if npc is None:
+        return f"Error: NPC '{npc_id}' does not exist"
+    goal = goal.strip()
+    if not goal:
+        return "Error: goal must not be empty"
+    
+    goals = npc.get("goals", [])
+    if action == "add":
+        if goal in goals:
+            return f"{npc_id} already has the goal: {goal}"
+        goals.append(goal)
+        npc["goals"] = goals
+        save_world_state(state)
+        return f"{npc_id} now has the goal: {goal}"
+    if action == "complete":
+        if goal not in goals:
+            return f"Error: {npc_id} has no goal '{goal}'"
+        goals.remove(goal)
+        npc["goals"] = goals
+        save_world_state(state)
+        return f"{npc_id} completed the goal: {goal}"
+    return f"Error: action must be 'add' or 'complete', got '{action}'"
+
+
+@mcp.tool()
+async def set_location_ambience(location_id: str, ambience: str) -> str:
+    """Set or clear a location's ongoing background ambience.
+    
+    Args:
+        location_id: The location ID
+        ambience: The room's ongoing background, or empty to clear it
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    location = state.get("locations", {}).get(location_id)
+    if location is None:
+        return f"Error: Location '{location_id}' does not exist"
+    
+    ambience = ambience.strip()
+    if not ambience:
+        location.pop("ambience", None)
+        save_world_state(state)
+        return f"Cleared the ambience of {location_id}"
+    location["ambience"] = ambience
+    save_world_state(state)
+    
+    return f"Ambience of {location_id}: {ambience}"
+
+
+@mcp.tool()
+async def mark_ambience_heard(npc_id: str, location_id: str, ambience: str) -> str:
+    """Record that an NPC has perceived a location's ambience, used by the game so it is perceived once.
+    
+    Args:
+        npc_id: The NPC who perceived the ambience
+        location_id: Where the NPC perceived it
+        ambience: The ambience the NPC perceived
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    npc = state.get("npcs", {}).get(npc_id)
+    if npc is None:
+        return f"Error: NPC '{npc_id}' does not exist"
+    if location_id not in state.get("locations", {}):
+        return f"Error: Location '{location_id}' does not exist"
+    
+    npc.setdefault("ambience_heard", {})[location_id] = ambience
+    save_world_state(state)
+    
+    return f"{npc_id} has heard the ambience of {location_id}"
+
+
+KNOWN_EMOTIONS = {"fear", "trust", "curiosity"}
+
+
+@mcp.tool()
+async def adjust_npc_emotion(npc_id: str, emotion: str, delta: float, cause: str = "") -> str:
+    """Raise or lower one of an NPC's emotions (fear, trust, curiosity).
+    
+    Args:
+        npc_id: The NPC whose feelings change
+        emotion: One of fear, trust, curiosity
+        delta: How much the emotion changes, from -1 to 1
+        cause: What the NPC perceived that changed it
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    npc = state.get("npcs", {}).get(npc_id)
+    if npc is None:
+        return f"Error: NPC '{npc_id}' does not exist"
+    if emotion not in KNOWN_EMOTIONS:
+        return f"Error: Unknown emotion '{emotion}'"
+    
+    emotions = npc.setdefault("emotions", {})
+    before = float(emotions.get(emotion, 0))
+    after = round(max(0.0, min(1.0, before + delta)), 2)
+    emotions[emotion] = after
+    save_world_state(state)
+    
+    return f"{npc_id} {emotion}: {before:.2f} -> {after:.2f}"
+
+
+@mcp.tool()
+async def sync_npc_emotions(npc_id: str, emotions: Dict[str, float]) -> str:
+    """Replace an NPC's emotions, used by the game after per-turn decay.
+    
+    Args:
+        npc_id: The NPC whose emotions are replaced
+        emotions: Intensities from 0 to 1 keyed by emotion
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    npc = state.get("npcs", {}).get(npc_id)
+    if npc is None:
+        return f"Error: NPC '{npc_id}' does not exist"
+    for emotion in emotions:
+        if emotion not in KNOWN_EMOTIONS:
+            return f"Error: Unknown emotion '{emotion}'"
+    
+    npc["emotions"] = {emotion: max(0.0, min(1.0, float(v))) for emotion, v in emotions.items()}
+    save_world_state(state)
+    
+    return f"{npc_id} emotions: {len(npc['emotions'])}"
+
+
+MAX_AFFINITY = 10
+MAX_AFFINITY_CHANGE = 3
+MAX_INTERACTIONS = 5
+
+
+@mcp.tool()
+async def update_relationship(npc_id: str, delta: int, interaction: str) -> str:
+    """Change how an NPC regards the player after a notable interaction.
+    
+    Args:
+        npc_id: The NPC whose regard for the player changes
+        delta: How much affinity changes, from -3 to 3
+        interaction: What the player did, in a short phrase
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    npc = state.get("npcs", {}).get(npc_id)
+    if npc is None:
+        return f"Error: NPC '{npc_id}' does not exist"
+    if delta < -MAX_AFFINITY_CHANGE or delta > MAX_AFFINITY_CHANGE:
+        return f"Error: delta must be between -{MAX_AFFINITY_CHANGE} and {MAX_AFFINITY_CHANGE}"
+    interaction = interaction.strip()
+    if not interaction:
+        return "Error: interaction must not be empty"
+    
+    relationship = npc.setdefault("relationship", {})
+    before = int(relationship.get("affinity", 0))
+    after = max(-MAX_AFFINITY, min(MAX_AFFINITY, before + delta))
+    relationship["affinity"] = after
+    relationship["interactions"] = (relationship.get("interactions", []) + [interaction])[-MAX_INTERACTIONS:]
+    save_world_state(state)
+    
+    return f"{npc_id} affinity for the player: {before} -> {after} ({interaction})"
+
+
+KNOWN_CONDITIONS = {"injured", "exhausted", "soaked", "cold"}
+
+
+@mcp.tool()
+async def set_player_condition(action: str, condition: str) -> str:
+    """Add or remove a physical condition on the player.
+    
+    Args:
+        action: "add" or "remove"
+        condition: One of injured, exhausted, soaked, cold
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    if action not in ("add", "remove"):
+        return f"Error: Unknown action '{action}' (expected add or remove)"
+    if condition not in KNOWN_CONDITIONS:
+        return f"Error: Unknown condition '{condition}'"
+    
+    conditions = state["player"].get("conditions", [])
+    existing = [c for c in conditions if c.get("name") == condition]
+    
+    if action == "add":
+        if existing:
+            # Re-applying a condition restarts its decay clock
+            existing[0]["turns"] = 0
+        else:
+            conditions.append({"name": condition, "turns": 0})
+        message = f"Player is now {condition}"
+    else:
+        if not existing:
+            return f"Player is not {condition}"
+        conditions = [c for c in conditions if c.get("name") != condition]
+        message = f"Player is no longer {condition}"
+    
+    state["player"]["conditions"] = conditions
+    save_world_state(state)
+    
+    return message
+
+
+@mcp.tool()
+async def sync_player_conditions(conditions: List[Dict[str, Any]]) -> str:
+    """Replace the player's conditions, used by the game after per-turn decay.
+    
+    Args:
+        conditions: List of {"name": str, "turns": int} entries
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    
+    cleaned = []
+    for entry in conditions:
+        name = entry.get("name")
+        if name not in KNOWN_CONDITIONS:
+            return f"Error: Unknown condition '{name}'"
+        cleaned.append({"name": name, "turns": int(entry.get("turns", 0))})
+    
+    state["player"]["conditions"] = cleaned
+    save_world_state(state)
+    
+    return f"Player conditions: {', '.join(c['name'] for c in cleaned) or 'none'}"
+
+
+# Limits on scheduled events; the game validates the same caps before calling.
+MAX_SCHEDULE_DELAY = 20
+MAX_SCHEDULED_MUTATIONS = 5
+
+
+@mcp.tool()
+async def schedule_event(delay_turns: int, description: str, mutations: List[Dict[str, Any]], location: str = "") -> str:
+    """Schedule world changes to happen a number of turns from now.
+    
+    Args:
+        delay_turns: Turns until the event fires (1 to MAX_SCHEDULE_DELAY)
+        description: What happens when it fires, e.g. "The powder keg explodes"
+        mutations: List of {"tool": str, "args": dict} tool calls to run when it fires
+        location: Where the event happens
+        
+    Returns:
+        Success message or error description
+    """
+    if delay_turns < 1 or delay_turns > MAX_SCHEDULE_DELAY:
+        return f"Error: delay_turns must be between 1 and {MAX_SCHEDULE_DELAY}"
+    if not description.strip():
+        return "Error: description is required"
+    if len(mutations) > MAX_SCHEDULED_MUTATIONS:
+        return f"Error: at most {MAX_SCHEDULED_MUTATIONS} mutations per event"
+    
+    state = load_world_state()
+    events = state.get("scheduled_events", [])
+    next_id = max((e.get("id", 0) for e in events), default=0) + 1
+    events.append({
+        "id": next_id,
+        "turns_left": delay_turns,
+        "description": description,
+        "location": location,
+        "mutations": mutations,
+    })
+    state["scheduled_events"] = events
+    save_world_state(state)
+    
+    return f"Scheduled event {next_id} in {delay_turns} turns: {description}"
+
+
+@mcp.tool()
+async def sync_scheduled_events(events: List[Dict[str, Any]]) -> str:
+    """Replace the pending scheduled events, used by the game after each turn's countdown.
+    
+    Args:
+        events: List of scheduled events as returned in the world state
+        
+    Returns:
+        Success message or error description
+    """
+    state = load_world_state()
+    state["scheduled_events"] = events
+    save_world_state(state)
+    
+    return f"{len(events)} scheduled events pending"
+
+
 @mcp.tool()
 async def create_item(item_id: str, name: str, location: str, initial_facts: Optional[List[str]] = None) -> str:
     """Create a new item in the world.
@@ -529,13 +1085,14 @@ async def create_npc(npc_id: str, name: str, location: str, initial_facts: Optio
 
 
 @mcp.tool() 
-async def create_location(location_id: str, name: str, exits: Optional[Dict[str, str]] = None) -> str:
+async def create_location(location_id: str, name: str, exits: Optional[Dict[str, str]] = None, outdoors: bool = False) -> str:
     """Create a new location in the world.
     
     Args:
         location_id: Unique identifier for the location (e.g., "secret_room")
         name: Human-readable name (e.g., "Secret Room")
         exits: Optional dictionary of exits {"direction": "location_id"}
+        outdoors: Whether the location is open to the weather; being soaked or cold only wears off indoors
         
     Returns:
         Success message or error description
@@ -547,6 +1104,7 @@ async def create_location(location_id: str, name: str, exits: Optional[Dict[str,
     
     state["locations"][location_id] = {
         "name": name,
+        "outdoors": outdoors,
         "facts": [],
         "exits": exits or {},
         "door_states": {}